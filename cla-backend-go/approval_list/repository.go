@@ -19,8 +19,8 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 const (
@@ -46,15 +46,15 @@ type IRepository interface {
 
 type repository struct {
 	stage          string
-	dynamoDBClient *dynamodb.DynamoDB
+	dynamoDBClient storage.Driver
 	tableName      string
 }
 
 // NewRepository creates a new instance of the whitelist service
-func NewRepository(awsSession *session.Session, stage string) IRepository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) IRepository {
 	return repository{
 		stage:          stage,
-		dynamoDBClient: dynamodb.New(awsSession),
+		dynamoDBClient: dynamoDBClient,
 		tableName:      fmt.Sprintf("cla-%s-ccla-whitelist-requests", stage),
 	}
 }
@@ -0,0 +1,361 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package branch_protection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	githubpkg "github.com/google/go-github/v32/github"
+	"github.com/sirupsen/logrus"
+)
+
+// Protector is the part of the github.BranchProtectionRepository used to check and re-apply the branch protection
+type Protector interface {
+	GetDefaultBranchForRepo(ctx context.Context, owner, repoName string) (string, error)
+	GetProtectedBranch(ctx context.Context, owner, repoName, protectedBranchName string) (*githubpkg.Protection, error)
+	EnableBranchProtection(ctx context.Context, owner, repoName, branchName string, enforceAdmin bool, enableStatusChecks, disableStatusChecks []string) error
+}
+
+// Drift is a repository whose default branch protection no longer matches the EasyCLA settings
+type Drift struct {
+	OrganizationName string
+	RepositoryName   string
+	BranchName       string
+	CLAGroupID       string
+	Drift            []string
+	// Reported is set when the same drift was already reported by a previous run, the CLA managers are not notified
+	// again
+	Reported     bool
+	Reapplied    bool
+	ReapplyError string
+}
+
+// Report is the outcome of a drift detection run
+type Report struct {
+	OrganizationsChecked int
+	RepositoriesChecked  int
+	// RepositoriesFailed are the repositories which couldn't be checked, e.g. when rate limited
+	RepositoriesFailed int
+	Drifts             []*Drift
+}
+
+// Service detects the branch protection drift of the repositories of the branch protection enabled organizations
+type Service interface {
+	DetectDrift(ctx context.Context) (*Report, error)
+}
+
+type service struct {
+	githubOrgRepo    github_organizations.Repository
+	repositoriesRepo repositories.Repository
+	projectService   project.Service
+	eventsService    events.Service
+	reapply          bool
+	newProtector     func(installationID int64) (Protector, error)
+}
+
+// NewService creates a new branch protection drift detection service, the drift is re-applied when reapply is set.
+// The options select the rate limiter of the GitHub calls.
+func NewService(githubOrgRepo github_organizations.Repository, repositoriesRepo repositories.Repository, projectService project.Service, eventsService events.Service, reapply bool, opts ...github.BranchProtectionRepositoryOption) Service {
+	return &service{
+		githubOrgRepo:    githubOrgRepo,
+		repositoriesRepo: repositoriesRepo,
+		projectService:   projectService,
+		eventsService:    eventsService,
+		reapply:          reapply,
+		newProtector: func(installationID int64) (Protector, error) {
+			gitHubClient, err := github.NewGithubAppClient(installationID)
+			if err != nil {
+				return nil, err
+			}
+			return github.NewBranchProtectionRepository(gitHubClient.Repositories, opts...), nil
+		},
+	}
+}
+
+// DetectDrift compares the default branch protection of the enabled repositories with the expected protection, logs an
+// event for each drift and notifies the CLA managers of the CLA Groups with drifted repositories
+func (s *service) DetectDrift(ctx context.Context) (*Report, error) {
+	f := logrus.Fields{
+		"functionName":   "branch_protection.DetectDrift",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"reapply":        s.reapply,
+	}
+
+	orgs, err := s.githubOrgRepo.GetBranchProtectionEnabledGithubOrganizations(ctx)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the branch protection enabled github organizations")
+		return nil, err
+	}
+
+	report := &Report{}
+	for _, org := range orgs {
+		if repositories.OrganizationType(org) != utils.GitHubType {
+			continue
+		}
+		if org.OrganizationInstallationID == 0 {
+			log.WithFields(f).Debugf("the EasyCLA application isn't installed on github organization: %s, skipping", org.OrganizationName)
+			continue
+		}
+		if orgErr := s.checkOrganization(ctx, org, report); orgErr != nil {
+			log.WithFields(f).WithError(orgErr).Warnf("unable to check the branch protection of github organization: %s", org.OrganizationName)
+		}
+	}
+
+	s.notifyCLAManagers(ctx, report.Drifts)
+
+	log.WithFields(f).Infof("checked %d repositories of %d github organizations, found %d drifted repositories, %d repositories failed",
+		report.RepositoriesChecked, report.OrganizationsChecked, len(report.Drifts), report.RepositoriesFailed)
+	return report, nil
+}
+
+// checkOrganization checks the enabled GitHub repositories of the organization
+func (s *service) checkOrganization(ctx context.Context, org *models.GithubOrganization, report *Report) error {
+	f := logrus.Fields{
+		"functionName":     "branch_protection.checkOrganization",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"organizationName": org.OrganizationName,
+	}
+
+	repos, err := s.repositoriesRepo.GetRepositoriesByOrganizationName(ctx, org.OrganizationName)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	protector, err := s.newProtector(org.OrganizationInstallationID)
+	if err != nil {
+		return err
+	}
+	report.OrganizationsChecked++
+
+	for _, repo := range repos {
+		if !repo.Enabled || (repo.RepositoryType != "" && repo.RepositoryType != utils.GitHubType) {
+			continue
+		}
+
+		drift, checkErr := s.checkRepository(ctx, protector, org, repo)
+		if checkErr != nil {
+			log.WithFields(f).WithError(checkErr).Warnf("unable to check the branch protection of repository: %s", repo.RepositoryName)
+			report.RepositoriesFailed++
+			continue
+		}
+		report.RepositoriesChecked++
+		if drift != nil {
+			report.Drifts = append(report.Drifts, drift)
+		}
+	}
+
+	return nil
+}
+
+// checkRepository compares the default branch protection of the repository with the expected protection, returns nil
+// if the protection is as expected
+func (s *service) checkRepository(ctx context.Context, protector Protector, org *models.GithubOrganization, repo *models.GithubRepository) (*Drift, error) {
+	f := logrus.Fields{
+		"functionName":     "branch_protection.checkRepository",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"organizationName": org.OrganizationName,
+		"repositoryName":   repo.RepositoryName,
+	}
+
+	branchName, err := protector.GetDefaultBranchForRepo(ctx, org.OrganizationName, repo.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+
+	protection, err := protector.GetProtectedBranch(ctx, org.OrganizationName, repo.RepositoryName, branchName)
+	if err != nil && !errors.Is(err, github.ErrBranchNotProtected) {
+		return nil, err
+	}
+
+	requiredChecks := repositories.StatusCheckNames(repo.EnforcementMode)
+	problems := github.BranchProtectionDrift(protection, requiredChecks, true)
+
+	reportedProblems, err := s.repositoriesRepo.GetBranchProtectionDrift(ctx, repo.RepositoryID)
+	if err != nil {
+		// without the last reported drift the drift is reported again rather than missed
+		log.WithFields(f).WithError(err).Warn("unable to load the last reported branch protection drift")
+		reportedProblems = nil
+	}
+	reported := sameDrift(problems, reportedProblems)
+	if !reported {
+		if updateErr := s.repositoriesRepo.UpdateBranchProtectionDrift(ctx, repo.RepositoryID, problems); updateErr != nil {
+			log.WithFields(f).WithError(updateErr).Warn("unable to record the reported branch protection drift")
+		}
+	}
+	if len(problems) == 0 {
+		return nil, nil
+	}
+
+	drift := &Drift{
+		OrganizationName: org.OrganizationName,
+		RepositoryName:   repo.RepositoryName,
+		BranchName:       branchName,
+		CLAGroupID:       repo.RepositoryProjectID,
+		Drift:            problems,
+		Reported:         reported,
+	}
+	log.WithFields(f).Debugf("branch protection drift detected on branch: %s - %v, already reported: %t", branchName, problems, reported)
+
+	if s.reapply {
+		reapplyErr := protector.EnableBranchProtection(ctx, org.OrganizationName, repo.RepositoryName, branchName, true, requiredChecks, disabledStatusChecks(requiredChecks))
+		if reapplyErr != nil {
+			log.WithFields(f).WithError(reapplyErr).Warn("unable to re-apply the branch protection")
+			drift.ReapplyError = reapplyErr.Error()
+		} else {
+			drift.Reapplied = true
+		}
+	}
+
+	// the drift already reported by a previous run is only logged again when it was re-applied
+	if reported && !drift.Reapplied {
+		return drift, nil
+	}
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType: events.BranchProtectionDriftDetected,
+		ProjectID: repo.RepositoryProjectID,
+		UserID:    "easycla system",
+		EventData: &events.BranchProtectionDriftDetectedEventData{
+			RepositoryName: repo.RepositoryName,
+			BranchName:     branchName,
+			Drift:          problems,
+			Reapplied:      drift.Reapplied,
+		},
+	})
+
+	return drift, nil
+}
+
+// sameDrift returns true if the drift is the drift last reported, in any order
+func sameDrift(drift, reported []string) bool {
+	if len(drift) != len(reported) {
+		return false
+	}
+	sortedDrift := append([]string(nil), drift...)
+	sortedReported := append([]string(nil), reported...)
+	sort.Strings(sortedDrift)
+	sort.Strings(sortedReported)
+	for i := range sortedDrift {
+		if sortedDrift[i] != sortedReported[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// disabledStatusChecks returns the EasyCLA status checks the branch protection must no longer require, the checks of
+// the other enforcement modes
+func disabledStatusChecks(requiredChecks []string) []string {
+	var disabled []string
+	for _, check := range repositories.AllStatusCheckNames() {
+		if !utils.StringInSlice(check, requiredChecks) {
+			disabled = append(disabled, check)
+		}
+	}
+	return disabled
+}
+
+// notifyCLAManagers sends an email listing the drifted repositories to the CLA managers of each CLA Group, the drift
+// already reported by a previous run is left out
+func (s *service) notifyCLAManagers(ctx context.Context, drifts []*Drift) {
+	f := logrus.Fields{
+		"functionName":   "branch_protection.notifyCLAManagers",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	byCLAGroup := make(map[string][]*Drift)
+	for _, drift := range drifts {
+		if drift.CLAGroupID == "" || drift.Reported {
+			continue
+		}
+		byCLAGroup[drift.CLAGroupID] = append(byCLAGroup[drift.CLAGroupID], drift)
+	}
+
+	claGroupIDs := make([]string, 0, len(byCLAGroup))
+	for claGroupID := range byCLAGroup {
+		claGroupIDs = append(claGroupIDs, claGroupID)
+	}
+	sort.Strings(claGroupIDs)
+
+	for _, claGroupID := range claGroupIDs {
+		claManagers, err := s.projectService.GetCLAManagers(ctx, claGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("fetching the cla managers of cla group: %s failed", claGroupID)
+			continue
+		}
+		claGroupModel, err := s.projectService.GetCLAGroupByID(ctx, claGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("loading the cla group: %s failed", claGroupID)
+			continue
+		}
+
+		subject, body, recipients := driftEmailContent(claGroupModel, claManagers, byCLAGroup[claGroupID])
+		if len(recipients) == 0 {
+			log.WithFields(f).Warnf("no cla manager emails for cla group: %s registered, can't notify the cla managers", claGroupModel.ProjectName)
+			continue
+		}
+
+		log.WithFields(f).Debugf("sending email with subject: %s for cla group: %s for recipients: %+v", subject, claGroupModel.ProjectName, recipients)
+		if err := utils.SendEmail(subject, body, recipients); err != nil {
+			log.WithFields(f).WithError(err).Warnf("sending email for subject: %s and cla group: %s failed", subject, claGroupModel.ProjectName)
+		}
+	}
+}
+
+// driftEmailContent prepares the branch protection drift email
+func driftEmailContent(claGroupModel *models.ClaGroup, managers []*models.ClaManagerUser, drifts []*Drift) (string, string, []string) {
+	claGroupName := claGroupModel.ProjectName
+	subject := fmt.Sprintf("EasyCLA: Branch Protection Changed for CLA Group: %s", claGroupName)
+
+	repoContent := "<ul>"
+	for _, drift := range drifts {
+		repoContent += fmt.Sprintf("<li>%s (branch %s)<ul>", drift.RepositoryName, drift.BranchName)
+		for _, problem := range drift.Drift {
+			repoContent += "<li>" + problem + "</li>"
+		}
+		if drift.Reapplied {
+			repoContent += "<li>EasyCLA re-applied the branch protection</li>"
+		}
+		repoContent += "</ul></li>"
+	}
+	repoContent += "</ul>"
+
+	body := `
+	<p>Hello Project Manager,</p>
+	<p>This is a notification email from EasyCLA regarding the CLA Group %s.</p>
+	<p>Branch protection is enabled within EasyCLA for the GitHub Organizations of the CLA Group, but the branch
+	protection rules of the following repositories no longer require the EasyCLA check or no longer apply to
+	administrators:</p>
+	%s
+	<p>Please verify the repository settings to ensure EasyCLA is a required check for merging Pull Requests.
+	See: GitHub Repository -> Settings -> Branches -> Branch Protection Rules -> Add/Edit the default branch.</p>
+	%s
+	%s
+	`
+
+	body = fmt.Sprintf(body, claGroupName, repoContent,
+		utils.GetEmailHelpContent(claGroupModel.Version == utils.V2), utils.GetEmailSignOffContent())
+	var recipients []string
+	for _, m := range managers {
+		if m.UserEmail == "" {
+			continue
+		}
+		recipients = append(recipients, m.UserEmail)
+	}
+
+	return subject, body, recipients
+}
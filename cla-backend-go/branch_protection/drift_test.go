@@ -0,0 +1,164 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package branch_protection
+
+import (
+	"context"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	githubpkg "github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeProtector struct {
+	protection *githubpkg.Protection
+	// disabled holds the status checks disabled by the last re-apply
+	disabled []string
+}
+
+func (p *fakeProtector) GetDefaultBranchForRepo(ctx context.Context, owner, repoName string) (string, error) {
+	return "main", nil
+}
+
+func (p *fakeProtector) GetProtectedBranch(ctx context.Context, owner, repoName, protectedBranchName string) (*githubpkg.Protection, error) {
+	return p.protection, nil
+}
+
+func (p *fakeProtector) EnableBranchProtection(ctx context.Context, owner, repoName, branchName string, enforceAdmin bool, enableStatusChecks, disableStatusChecks []string) error {
+	p.disabled = disableStatusChecks
+	return nil
+}
+
+type fakeOrganizations struct {
+	github_organizations.Repository
+}
+
+func (r *fakeOrganizations) GetBranchProtectionEnabledGithubOrganizations(ctx context.Context) ([]*models.GithubOrganization, error) {
+	return []*models.GithubOrganization{{OrganizationName: "acme", OrganizationInstallationID: 1}}, nil
+}
+
+// fakeRepositories keeps the last reported drift of a single repository
+type fakeRepositories struct {
+	repositories.Repository
+	repo     *models.GithubRepository
+	reported []string
+}
+
+func (r *fakeRepositories) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error) {
+	return []*models.GithubRepository{r.repo}, nil
+}
+
+func (r *fakeRepositories) GetBranchProtectionDrift(ctx context.Context, repositoryID string) ([]string, error) {
+	return r.reported, nil
+}
+
+func (r *fakeRepositories) UpdateBranchProtectionDrift(ctx context.Context, repositoryID string, drift []string) error {
+	r.reported = drift
+	return nil
+}
+
+type fakeProjects struct {
+	project.Service
+}
+
+func (s *fakeProjects) GetCLAManagers(ctx context.Context, claGroupID string) ([]*models.ClaManagerUser, error) {
+	return []*models.ClaManagerUser{{UserEmail: "manager@acme.com"}}, nil
+}
+
+func (s *fakeProjects) GetCLAGroupByID(ctx context.Context, claGroupID string) (*models.ClaGroup, error) {
+	return &models.ClaGroup{ProjectID: claGroupID, ProjectName: "Acme"}, nil
+}
+
+type fakeEvents struct {
+	events.Service
+	logged int
+}
+
+func (s *fakeEvents) LogEvent(args *events.LogEventArgs) {
+	s.logged++
+}
+
+type countingEmailSender struct {
+	sent int
+}
+
+func (s *countingEmailSender) SendEmail(subject string, body string, recipients []string) error {
+	s.sent++
+	return nil
+}
+
+func protection(checks []string, enforceAdmins bool) *githubpkg.Protection {
+	return &githubpkg.Protection{
+		RequiredStatusChecks: &githubpkg.RequiredStatusChecks{Contexts: checks},
+		EnforceAdmins:        &githubpkg.AdminEnforcement{Enabled: enforceAdmins},
+	}
+}
+
+func TestDetectDriftNotifiesOnce(t *testing.T) {
+	sender := &countingEmailSender{}
+	previousSender := utils.GetEmailSender()
+	utils.SetEmailSender(sender)
+	defer utils.SetEmailSender(previousSender)
+
+	protector := &fakeProtector{}
+	repos := &fakeRepositories{repo: &models.GithubRepository{
+		RepositoryID:        "r1",
+		RepositoryName:      "acme/widgets",
+		RepositoryProjectID: "cla-group-1",
+		Enabled:             true,
+	}}
+	eventsService := &fakeEvents{}
+	s := &service{
+		githubOrgRepo:    &fakeOrganizations{},
+		repositoriesRepo: repos,
+		projectService:   &fakeProjects{},
+		eventsService:    eventsService,
+		newProtector:     func(installationID int64) (Protector, error) { return protector, nil },
+	}
+
+	testCases := []struct {
+		name       string
+		protection *githubpkg.Protection
+		drifts     int
+		notified   int
+		reported   int
+	}{
+		{"new drift", protection(nil, true), 1, 1, 1},
+		{"same drift", protection(nil, true), 1, 1, 1},
+		{"changed drift", protection(nil, false), 1, 2, 2},
+		{"drift fixed", protection([]string{utils.GitHubBotName}, true), 0, 2, 0},
+		{"drift after the fix", protection([]string{utils.GitHubBotName}, false), 1, 3, 1},
+	}
+
+	for _, tc := range testCases {
+		protector.protection = tc.protection
+		report, err := s.DetectDrift(context.Background())
+		if !assert.NoError(t, err, tc.name) {
+			return
+		}
+		assert.Len(t, report.Drifts, tc.drifts, tc.name)
+		assert.Equal(t, tc.notified, sender.sent, tc.name)
+		assert.Equal(t, tc.notified, eventsService.logged, tc.name)
+		assert.Len(t, repos.reported, tc.reported, tc.name)
+	}
+}
+
+func TestReapplyDisablesTheOtherModeChecks(t *testing.T) {
+	protector := &fakeProtector{protection: protection([]string{utils.GitHubBotName}, true)}
+	repos := &fakeRepositories{}
+	s := &service{repositoriesRepo: repos, eventsService: &fakeEvents{}, reapply: true}
+
+	drift, err := s.checkRepository(context.Background(), protector, &models.GithubOrganization{OrganizationName: "acme"},
+		&models.GithubRepository{RepositoryID: "r1", RepositoryName: "acme/widgets", EnforcementMode: repositories.EnforcementModeDCO})
+	if assert.NoError(t, err) && assert.NotNil(t, drift) {
+		assert.True(t, drift.Reapplied)
+		assert.Equal(t, []string{utils.GitHubBotName}, protector.disabled)
+	}
+}
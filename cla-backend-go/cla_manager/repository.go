@@ -13,11 +13,11 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/gofrs/uuid"
 )
@@ -40,15 +40,15 @@ type IRepository interface { //nolint
 
 type repository struct {
 	stage          string
-	dynamoDBClient *dynamodb.DynamoDB
+	dynamoDBClient storage.Driver
 	tableName      string
 }
 
 // NewRepository creates a new company repository instance
-func NewRepository(awsSession *session.Session, stage string) IRepository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) IRepository {
 	return repository{
 		stage:          stage,
-		dynamoDBClient: dynamodb.New(awsSession),
+		dynamoDBClient: dynamoDBClient,
 		tableName:      fmt.Sprintf("cla-%s-cla-manager-requests", stage),
 	}
 }
@@ -0,0 +1,151 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+var signaturesService signatures.SignatureService
+var companyRepo company.IRepository
+var projectRepo project.ProjectRepository
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	userRepo := user.NewDynamoRepository(dynamoDBClient, stage)
+	companyRepo = company.NewRepository(dynamoDBClient, stage)
+	signaturesRepo := signatures.NewRepository(dynamoDBClient, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	projectRepo = project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(dynamoDBClient, stage)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+	usersService := users.NewService(usersRepo, eventsService)
+	companyService := company.NewService(companyRepo, configFile.CorporateConsoleURL, userRepo, usersService)
+	signaturesService = signatures.NewService(signaturesRepo, companyService, usersService, eventsService, true)
+
+	utils.SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) {
+	f := logrus.Fields{
+		"functionName":   "handler",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        event.ID,
+	}
+
+	sigs, err := signaturesService.GetSignaturesWithApprovalListEntryDetails(ctx)
+	if err != nil {
+		log.WithFields(f).WithError(err).Fatal("unable to load the signatures with approval list entry details")
+	}
+
+	now := time.Now()
+	var expiredCount int
+	for _, sig := range sigs {
+		if len(signatures.ExpiredApprovalListEntries(sig, now)) == 0 {
+			continue
+		}
+
+		companyModel, companyErr := companyRepo.GetCompany(ctx, sig.SignatureReferenceID.String())
+		if companyErr != nil {
+			log.WithFields(f).WithError(companyErr).Warnf("unable to load company: %s for signature: %s - skipping",
+				sig.SignatureReferenceID, sig.SignatureID)
+			continue
+		}
+		claGroupModel, claGroupErr := projectRepo.GetCLAGroupByID(ctx, sig.ProjectID, project.DontLoadRepoDetails)
+		if claGroupErr != nil {
+			log.WithFields(f).WithError(claGroupErr).Warnf("unable to load CLA Group: %s for signature: %s - skipping",
+				sig.ProjectID, sig.SignatureID)
+			continue
+		}
+
+		expired, expireErr := signaturesService.ExpireApprovalListEntries(ctx, claGroupModel, companyModel, sig)
+		if expireErr != nil {
+			log.WithFields(f).WithError(expireErr).Warnf("unable to remove the expired approval list entries for signature: %s",
+				sig.SignatureID)
+			continue
+		}
+		expiredCount += len(expired)
+	}
+
+	log.WithFields(f).Infof("removed %d expired approval list entries from %d signatures with approval list entry details",
+		expiredCount, len(sigs))
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		handler(utils.NewContext(), events.CloudWatchEvent{})
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
@@ -0,0 +1,133 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/branch_protection"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+var driftService branch_protection.Service
+
+func init() {
+	var awsSession = session.Must(session.NewSession(&aws.Config{}))
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+	github.Init(configFile.Github.AppID, configFile.Github.AppPrivateKey, configFile.Github.AccessToken)
+
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	githubOrganizationsRepo := github_organizations.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(dynamoDBClient, stage)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo, usersRepo)
+
+	// REAPPLY_BRANCH_PROTECTION re-applies the protection of the drifted branches, the default only reports the drift
+	reapply := os.Getenv("REAPPLY_BRANCH_PROTECTION") == "true"
+	// the run is a background task so the GitHub calls wait for the rate limiter unless asked to fail fast
+	limiter := github.EnableBlockingLimiter()
+	if os.Getenv("NON_BLOCKING_RATE_LIMIT") == "true" {
+		limiter = github.EnableNonBlockingLimiter()
+	}
+	driftService = branch_protection.NewService(githubOrganizationsRepo, repositoriesRepo, projectService, eventsService, reapply, limiter)
+
+	utils.SetSnsEmailSender(awsSession, configFile.SNSEventTopicARN, configFile.SenderEmailAddress)
+}
+
+func handler(ctx context.Context, event events.CloudWatchEvent) {
+	f := logrus.Fields{
+		"functionName":   "handler",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        event.ID,
+	}
+
+	report, err := driftService.DetectDrift(ctx)
+	if err != nil {
+		log.WithFields(f).WithError(err).Fatal("unable to detect the branch protection drift")
+	}
+
+	var reapplied int
+	for _, drift := range report.Drifts {
+		if drift.Reapplied {
+			reapplied++
+		}
+	}
+	log.WithFields(f).Infof("branch protection drift detected on %d of %d repositories, re-applied on %d repositories",
+		len(report.Drifts), report.RepositoriesChecked, reapplied)
+}
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+func main() {
+	log.Info("Lambda server starting...")
+	printBuildInfo()
+	if os.Getenv("LOCAL_MODE") == "true" {
+		handler(utils.NewContext(), events.CloudWatchEvent{})
+	} else {
+		lambda.Start(handler)
+	}
+	log.Infof("Lambda shutting down...")
+}
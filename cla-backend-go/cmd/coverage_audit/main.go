@@ -0,0 +1,137 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+// readCommits returns the commits of the local git repository or of the exported commit author list
+func readCommits(repositoryPath, authorsFile string) ([]*coverage.AuthorCommit, error) {
+	if repositoryPath != "" {
+		return coverage.ReadGitLog(utils.NewContext(), repositoryPath)
+	}
+	file, err := os.Open(authorsFile) // nolint - the file is provided by the operator
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			log.Warnf("problem closing the author list, error: %+v", closeErr)
+		}
+	}()
+	return coverage.ParseAuthorList(file)
+}
+
+// writeReport writes the report in the format, csv or json
+func writeReport(w io.Writer, report *coverage.AuditReport, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return report.WriteCSV(w)
+}
+
+func main() {
+	var claGroupID, repositoryPath, authorsFile, format, output string
+	flag.StringVar(&claGroupID, "cla-group-id", "", "the CLA Group the commit authors are audited against")
+	flag.StringVar(&repositoryPath, "repository", "", "path of a local git repository, the commits of its current branch are audited")
+	flag.StringVar(&authorsFile, "authors", "", "path of an exported commit author list - a CSV file with the name, email and date of each commit")
+	flag.StringVar(&format, "format", "csv", "report format: csv or json")
+	flag.StringVar(&output, "output", "", "report file, the report is written to stdout by default")
+	flag.Parse()
+
+	printBuildInfo()
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	if claGroupID == "" {
+		log.Fatal("missing -cla-group-id parameter")
+	}
+	if (repositoryPath == "") == (authorsFile == "") {
+		log.Fatal("exactly one of the -repository or -authors parameters is required")
+	}
+	if format != "csv" && format != "json" {
+		log.Fatalf("unsupported -format: %s, expecting csv or json", format)
+	}
+
+	commits, err := readCommits(repositoryPath, authorsFile)
+	if err != nil {
+		log.Fatalf("unable to read the commits, error: %+v", err)
+	}
+	log.Infof("read %d commits", len(commits))
+
+	awsSession := session.Must(session.NewSession(&aws.Config{}))
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	signaturesRepo := signatures.NewRepository(dynamoDBClient, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	evaluator := coverage.NewEvaluator(usersRepo, signaturesRepo, projectRepo)
+
+	report, err := coverage.Audit(utils.NewContext(), evaluator, claGroupID, commits)
+	if err != nil {
+		log.Fatalf("unable to audit the commits against CLA Group: %s, error: %+v", claGroupID, err)
+	}
+	log.Infof("CLA Group: %s - authors: %d, covered: %d, uncovered: %d, uncovered commits: %d of %d", claGroupID,
+		report.Authors, report.CoveredAuthors, len(report.Uncovered), report.UncoveredCommits, report.Commits)
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, createErr := os.Create(output)
+		if createErr != nil {
+			log.Fatalf("unable to create the report file: %s, error: %+v", output, createErr)
+		}
+		defer file.Close() // nolint
+		w = file
+	}
+	if err := writeReport(w, report, format); err != nil {
+		log.Fatalf("unable to write the report, error: %+v", err)
+	}
+}
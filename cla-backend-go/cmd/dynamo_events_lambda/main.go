@@ -26,12 +26,17 @@ import (
 
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/check_runs"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/dynamo_events"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/event_subscriptions"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
 
 	"github.com/communitybridge/easycla/cla-backend-go/token"
 
 	"github.com/communitybridge/easycla/cla-backend-go/company"
 	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
 	v2Company "github.com/communitybridge/easycla/cla-backend-go/v2/company"
 
 	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
@@ -47,6 +52,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 )
 
@@ -65,6 +71,7 @@ var (
 )
 
 var dynamoEventsService dynamo_events.Service
+var eventSubscriptionsService event_subscriptions.Service
 
 func init() {
 	var awsSession = session.Must(session.NewSession(&aws.Config{}))
@@ -77,21 +84,23 @@ func init() {
 	if err != nil {
 		log.Panicf("Unable to load config - Error: %v", err)
 	}
-	usersRepo := users.NewRepository(awsSession, stage)
-	userRepo := user.NewDynamoRepository(awsSession, stage)
-	companyRepo := company.NewRepository(awsSession, stage)
-	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
-	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	repositoriesRepo := repositories.NewRepository(awsSession, stage)
-	gerritRepo := gerrits.NewRepository(awsSession, stage)
-	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
-	eventsRepo := claevents.NewRepository(awsSession, stage)
-	claManagerRequestsRepo := cla_manager.NewRepository(awsSession, stage)
-	approvalListRequestsRepo := approval_list.NewRepository(awsSession, stage)
-	githubOrganizationsRepo := github_organizations.NewRepository(awsSession, stage)
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	userRepo := user.NewDynamoRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	signaturesRepo := signatures.NewRepository(dynamoDBClient, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(dynamoDBClient, stage)
+	claManagerRequestsRepo := cla_manager.NewRepository(dynamoDBClient, stage)
+	approvalListRequestsRepo := approval_list.NewRepository(dynamoDBClient, stage)
+	githubOrganizationsRepo := github_organizations.NewRepository(dynamoDBClient, stage)
 
 	token.Init(configFile.Auth0Platform.ClientID, configFile.Auth0Platform.ClientSecret, configFile.Auth0Platform.URL, configFile.Auth0Platform.Audience)
 	github.Init(configFile.Github.AppID, configFile.Github.AppPrivateKey, configFile.Github.AccessToken)
+	gitlab.Init(configFile.GitLab.BaseURL, configFile.GitLab.AccessToken)
 
 	user_service.InitClient(configFile.APIGatewayURL, configFile.AcsAPIKey)
 	project_service.InitClient(configFile.APIGatewayURL)
@@ -121,6 +130,8 @@ func init() {
 	v2CompanyService := v2Company.NewService(companyService, signaturesRepo, projectRepo, usersRepo, companyRepo, projectClaGroupRepo, eventsService)
 	organization_service.InitClient(configFile.APIGatewayURL, eventsService)
 	acs_service.InitClient(configFile.APIGatewayURL, configFile.AcsAPIKey)
+	eventSubscriptionsService = event_subscriptions.NewService(event_subscriptions.NewRepository(dynamoDBClient, stage))
+	checkRunsService := check_runs.NewService(check_runs.NewRepository(dynamoDBClient, stage), repositoriesRepo, coverage.NewEvaluator(usersRepo, signaturesRepo, projectRepo), configFile.ClaV1ApiURL, github.NewGithubAppClient)
 	dynamoEventsService = dynamo_events.NewService(
 		stage,
 		signaturesRepo,
@@ -134,11 +145,45 @@ func init() {
 		repositoriesService,
 		gerritService,
 		claManagerRequestsRepo,
-		approvalListRequestsRepo)
+		approvalListRequestsRepo,
+		metrics.NewRepository(dynamoDBClient, stage, configFile.APIGatewayURL, projectClaGroupRepo),
+		dynamo_events.NewDynamoRetryStore(dynamoDBClient, stage),
+		eventSubscriptionsService,
+		checkRunsService)
 }
 
-func handler(ctx context.Context, event events.DynamoDBEvent) {
-	dynamoEventsService.ProcessEvents(event)
+// handler processes the DynamoDB stream events - the same function is also invoked on a schedule to retry the
+// failed events and the failed event subscription deliveries, in which case the payload is a CloudWatch scheduled
+// event without any records
+func handler(ctx context.Context, payload json.RawMessage) error {
+	var dynamodbEvent events.DynamoDBEvent
+	if err := json.Unmarshal(payload, &dynamodbEvent); err != nil {
+		return err
+	}
+
+	if len(dynamodbEvent.Records) > 0 {
+		dynamoEventsService.ProcessEvents(dynamodbEvent)
+		return nil
+	}
+
+	var scheduledEvent events.CloudWatchEvent
+	if err := json.Unmarshal(payload, &scheduledEvent); err == nil && scheduledEvent.DetailType == "Scheduled Event" {
+		succeeded, err := dynamoEventsService.RetryFailedEvents(utils.NewContext())
+		if err != nil {
+			log.WithError(err).Warn("unable to retry the failed events")
+			return err
+		}
+		log.Infof("retried failed events, %d succeeded", succeeded)
+
+		delivered, err := eventSubscriptionsService.RetryDeliveries(utils.NewContext())
+		if err != nil {
+			log.WithError(err).Warn("unable to retry the event subscription deliveries")
+			return err
+		}
+		log.Infof("retried event subscription deliveries, %d delivered", delivered)
+	}
+
+	return nil
 }
 
 func printBuildInfo() {
@@ -152,14 +197,14 @@ func main() {
 	log.Info("Lambda server starting...")
 	printBuildInfo()
 	if os.Getenv("LOCAL_MODE") == "true" {
-		var dynamodbEvent events.DynamoDBEvent
+		var payload json.RawMessage = []byte("{}")
 		args := os.Args[1:]
 		if len(args) > 0 {
-			if err := json.Unmarshal([]byte(args[0]), &dynamodbEvent); err != nil {
-				log.Fatal(err)
-			}
+			payload = []byte(args[0])
+		}
+		if err := handler(utils.NewContext(), payload); err != nil {
+			log.Fatal(err)
 		}
-		handler(utils.NewContext(), dynamodbEvent)
 	} else {
 		lambda.Start(handler)
 	}
@@ -0,0 +1,189 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/sirupsen/logrus"
+
+	"github.com/communitybridge/easycla/cla-backend-go/approval_list"
+	"github.com/communitybridge/easycla/cla-backend-go/cla_manager"
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/token"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	acs_service "github.com/communitybridge/easycla/cla-backend-go/v2/acs-service"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/check_runs"
+	v2Company "github.com/communitybridge/easycla/cla-backend-go/v2/company"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/dynamo_events"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/event_subscriptions"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
+	organization_service "github.com/communitybridge/easycla/cla-backend-go/v2/organization-service"
+	project_service "github.com/communitybridge/easycla/cla-backend-go/v2/project-service"
+	user_service "github.com/communitybridge/easycla/cla-backend-go/v2/user-service"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+// newDynamoEventsService wires the dynamo events service the same way as the dynamo events lambda, the failed events
+// are replayed by the handlers of the lambda
+func newDynamoEventsService(awsSession *session.Session, stage string, configFile config.Config) dynamo_events.Service {
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	userRepo := user.NewDynamoRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	signaturesRepo := signatures.NewRepository(dynamoDBClient, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(dynamoDBClient, stage)
+	claManagerRequestsRepo := cla_manager.NewRepository(dynamoDBClient, stage)
+	approvalListRequestsRepo := approval_list.NewRepository(dynamoDBClient, stage)
+	githubOrganizationsRepo := github_organizations.NewRepository(dynamoDBClient, stage)
+
+	token.Init(configFile.Auth0Platform.ClientID, configFile.Auth0Platform.ClientSecret, configFile.Auth0Platform.URL, configFile.Auth0Platform.Audience)
+	github.Init(configFile.Github.AppID, configFile.Github.AppPrivateKey, configFile.Github.AccessToken)
+	gitlab.Init(configFile.GitLab.BaseURL, configFile.GitLab.AccessToken)
+
+	user_service.InitClient(configFile.APIGatewayURL, configFile.AcsAPIKey)
+	project_service.InitClient(configFile.APIGatewayURL)
+	githubOrganizationsService := github_organizations.NewService(githubOrganizationsRepo, repositoriesRepo, projectClaGroupRepo)
+	repositoriesService := repositories.NewService(repositoriesRepo, githubOrganizationsRepo, projectClaGroupRepo)
+	gerritService := gerrits.NewService(gerritRepo, &gerrits.LFGroup{
+		LfBaseURL:    configFile.LFGroup.ClientURL,
+		ClientID:     configFile.LFGroup.ClientID,
+		ClientSecret: configFile.LFGroup.ClientSecret,
+		RefreshToken: configFile.LFGroup.RefreshToken,
+	})
+	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo, usersRepo)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+	usersService := users.NewService(usersRepo, eventsService)
+	companyService := company.NewService(companyRepo, configFile.CorporateConsoleURL, userRepo, usersService)
+	v2CompanyService := v2Company.NewService(companyService, signaturesRepo, projectRepo, usersRepo, companyRepo, projectClaGroupRepo, eventsService)
+	organization_service.InitClient(configFile.APIGatewayURL, eventsService)
+	acs_service.InitClient(configFile.APIGatewayURL, configFile.AcsAPIKey)
+	eventSubscriptionsService := event_subscriptions.NewService(event_subscriptions.NewRepository(dynamoDBClient, stage))
+	checkRunsService := check_runs.NewService(check_runs.NewRepository(dynamoDBClient, stage), repositoriesRepo, coverage.NewEvaluator(usersRepo, signaturesRepo, projectRepo), configFile.ClaV1ApiURL, github.NewGithubAppClient)
+	return dynamo_events.NewService(
+		stage,
+		signaturesRepo,
+		companyRepo,
+		v2CompanyService,
+		projectClaGroupRepo,
+		eventsRepo,
+		projectRepo,
+		projectService,
+		githubOrganizationsService,
+		repositoriesService,
+		gerritService,
+		claManagerRequestsRepo,
+		approvalListRequestsRepo,
+		metrics.NewRepository(dynamoDBClient, stage, configFile.APIGatewayURL, projectClaGroupRepo),
+		dynamo_events.NewDynamoRetryStore(dynamoDBClient, stage),
+		eventSubscriptionsService,
+		checkRunsService)
+}
+
+func main() {
+	var list, deadLetterOnly, force bool
+	var replayID string
+	flag.BoolVar(&list, "list", false, "list the failed events")
+	flag.BoolVar(&deadLetterOnly, "dead-letter", false, "only list the failed events which are no longer retried")
+	flag.StringVar(&replayID, "replay", "", "the failed event ID to replay")
+	flag.BoolVar(&force, "force", false, "replay the failed event even if its handler is not idempotent")
+	flag.Parse()
+
+	printBuildInfo()
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	if list == (replayID != "") {
+		log.Fatal("one of the -list or -replay parameters is required")
+	}
+
+	awsSession := session.Must(session.NewSession(&aws.Config{}))
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+	dynamoEventsService := newDynamoEventsService(awsSession, stage, configFile)
+
+	ctx := utils.NewContext()
+	if replayID != "" {
+		if err := dynamoEventsService.ReplayFailedEvent(ctx, replayID, force); err != nil {
+			log.WithError(err).Fatalf("unable to replay the failed event: %s", replayID)
+		}
+		log.Infof("replayed the failed event: %s", replayID)
+		return
+	}
+
+	failedEvents, err := dynamoEventsService.ListFailedEvents(ctx, deadLetterOnly)
+	if err != nil {
+		log.WithError(err).Fatal("unable to list the failed events")
+	}
+	for _, failedEvent := range failedEvents {
+		log.WithFields(logrus.Fields{
+			"failedEventID":    failedEvent.FailedEventID,
+			"eventID":          failedEvent.EventID,
+			"eventKey":         failedEvent.EventKey,
+			"handlerName":      failedEvent.HandlerName,
+			"idempotent":       failedEvent.Idempotent,
+			"attempts":         failedEvent.Attempts,
+			"nextAttemptEpoch": failedEvent.NextAttemptEpoch,
+			"deadLetter":       failedEvent.DeadLetter,
+			"dateCreated":      failedEvent.DateCreated,
+		}).Infof("failed event: %s", failedEvent.LastError)
+	}
+	log.Infof("found %d failed events", len(failedEvents))
+}
@@ -4,6 +4,7 @@
 package org_service
 
 import (
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/communitybridge/easycla/cla-backend-go/cmd/functional_tests/test_models"
 	"github.com/communitybridge/easycla/cla-backend-go/company"
 	"github.com/communitybridge/easycla/cla-backend-go/config"
@@ -56,13 +57,14 @@ func (t *TestBehaviour) RunIsUserHaveRoleScope() {
 		company.IRepository
 		project.ProjectRepository
 	}
-	eventsRepo := events.NewRepository(awsSession, stage)
-	usersRepo := users.NewRepository(awsSession, stage)
-	companyRepo := company.NewRepository(awsSession, stage)
-	repositoriesRepo := repositories.NewRepository(awsSession, stage)
-	gerritRepo := gerrits.NewRepository(awsSession, stage)
-	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	dynamoDBClient := dynamodb.New(awsSession)
+	eventsRepo := events.NewRepository(dynamoDBClient, stage)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
 
 	eventsService := events.NewService(eventsRepo, combinedRepo{
 		usersRepo,
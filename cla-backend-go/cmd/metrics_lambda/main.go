@@ -18,6 +18,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
@@ -52,8 +53,9 @@ func init() {
 	if err != nil {
 		log.Panicf("Unable to load config - Error: %v", err)
 	}
-	pcgRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	metricsRepo = metrics.NewRepository(awsSession, stage, configFile.APIGatewayURL, pcgRepo)
+	dynamoDBClient := dynamodb.New(awsSession)
+	pcgRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	metricsRepo = metrics.NewRepository(dynamoDBClient, stage, configFile.APIGatewayURL, pcgRepo)
 	token.Init(configFile.Auth0Platform.ClientID, configFile.Auth0Platform.ClientSecret, configFile.Auth0Platform.URL, configFile.Auth0Platform.Audience)
 	project_service.InitClient(configFile.APIGatewayURL)
 }
@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	awsqs "github.com/aws/aws-sdk-go/service/sqs"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
@@ -48,6 +50,7 @@ var (
 
 var awsSession = session.Must(session.NewSession(&aws.Config{}))
 var metricsRepo metrics.Repository
+var metricsService metrics.Service
 var stage string
 
 func init() {
@@ -60,8 +63,10 @@ func init() {
 	if err != nil {
 		log.Panicf("Unable to load config - Error: %v", err)
 	}
-	pcgRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	metricsRepo = metrics.NewRepository(awsSession, stage, configFile.APIGatewayURL, pcgRepo)
+	dynamoDBClient := dynamodb.New(awsSession)
+	pcgRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	metricsRepo = metrics.NewRepository(dynamoDBClient, stage, configFile.APIGatewayURL, pcgRepo)
+	metricsService = metrics.NewService(metricsRepo, pcgRepo)
 	token.Init(configFile.Auth0Platform.ClientID, configFile.Auth0Platform.ClientSecret, configFile.Auth0Platform.URL, configFile.Auth0Platform.Audience)
 	v2ProjectService.InitClient(configFile.APIGatewayURL)
 }
@@ -78,6 +83,8 @@ func handler(ctx context.Context, event events.CloudWatchEvent) {
 		log.WithFields(f).WithError(err).Fatal("unable to get totalCount metrics from dynamodb.")
 	}
 
+	logMonthlyGrowth(f)
+
 	req := stats.Request{
 		Products: &stats.Products{
 			EasyCLA: stats.Stats{
@@ -151,6 +158,30 @@ func handler(ctx context.Context, event events.CloudWatchEvent) {
 	}
 }
 
+// logMonthlyGrowth logs the growth of the total counts over the last month, from the metrics history
+func logMonthlyGrowth(f logrus.Fields) {
+	now := time.Now().UTC()
+	history, err := metricsService.GetMetricsHistory(metrics.HistoryScopeTotal, metrics.IDHistoryTotal,
+		now.AddDate(0, -1, 0).Format(metrics.HistoryDateFormat), now.Format(metrics.HistoryDateFormat), metrics.HistoryGranularityDay)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to get the metrics history from dynamodb")
+		return
+	}
+	if len(history.List) < 2 {
+		log.WithFields(f).Info("not enough metrics history to report the growth over the last month")
+		return
+	}
+
+	first, last := history.List[0], history.List[len(history.List)-1]
+	log.WithFields(f).Infof("growth from %s to %s - ICLAs: %+d, CCLAs: %+d, employees: %+d, repositories: %+d, CLA managers: %+d",
+		first.Date, last.Date,
+		last.IclaCount-first.IclaCount,
+		last.CclaCount-first.CclaCount,
+		last.EmployeeCount-first.EmployeeCount,
+		last.RepositoriesCount-first.RepositoriesCount,
+		last.ClaManagersCount-first.ClaManagersCount)
+}
+
 func printBuildInfo() {
 	log.Infof("Version                 : %s", version)
 	log.Infof("Git commit hash         : %s", commit)
@@ -4,6 +4,7 @@
 package cmd
 
 import (
+	"sync"
 	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
@@ -18,11 +19,22 @@ type responseMetrics struct {
 	expire  time.Time
 }
 
-var reqMap = make(map[string]*responseMetrics, 5)
+var (
+	reqMapLock sync.Mutex
+	reqMap     = make(map[string]*responseMetrics, 5)
+)
 
 // requestStart holds the request ID, method and timing information in a small structure
 func requestStart(reqID, method string) {
 	now, _ := utils.CurrentTime()
+	reqMapLock.Lock()
+	defer reqMapLock.Unlock()
+	// Drop the requests which were never cleared, such as the ones interrupted by a panic
+	for id, x := range reqMap {
+		if now.After(x.expire) {
+			delete(reqMap, id)
+		}
+	}
 	reqMap[reqID] = &responseMetrics{
 		reqID:   reqID,
 		method:  method,
@@ -32,12 +44,15 @@ func requestStart(reqID, method string) {
 	}
 }
 
-// getRequestMetrics returns the response metrics based on the request id value
+// getRequestMetrics returns a copy of the response metrics based on the request id value
 func getRequestMetrics(reqID string) *responseMetrics {
+	reqMapLock.Lock()
+	defer reqMapLock.Unlock()
 	if x, found := reqMap[reqID]; found {
 		now, _ := utils.CurrentTime()
-		x.elapsed = now.Sub(x.start)
-		return x
+		metrics := *x
+		metrics.elapsed = now.Sub(x.start)
+		return &metrics
 	}
 
 	return nil
@@ -45,5 +60,7 @@ func getRequestMetrics(reqID string) *responseMetrics {
 
 // clearRequestMetrics removes the request from the map
 func clearRequestMetrics(reqID string) {
+	reqMapLock.Lock()
+	defer reqMapLock.Unlock()
 	delete(reqMap, reqID)
 }
@@ -10,6 +10,7 @@ import (
 
 	ini "github.com/communitybridge/easycla/cla-backend-go/init"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -35,6 +36,11 @@ func init() {
 		"GH_ORG_VALIDATION": "true",
 		// should we validate company API queries against the current authenticated user?
 		"COMPANY_USER_VALIDATION": "true",
+		// storage driver for the repositories - dynamodb or memory (local mode only)
+		"STORAGE_DRIVER": storage.DynamoDBDriverName,
+		// trace exporter - none, stdout or otlp, the OTLP/HTTP collector is set with OTEL_EXPORTER_OTLP_ENDPOINT
+		"OTEL_TRACES_EXPORTER": "none",
+		"OTEL_SERVICE_NAME":    "easycla-api",
 	}
 
 	for key, value := range defaults {
@@ -6,6 +6,7 @@ package cmd
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/communitybridge/easycla/cla-backend-go/v2/dynamo_events"
 	v2GithubActivity "github.com/communitybridge/easycla/cla-backend-go/v2/github_activity"
+	v2GitlabActivity "github.com/communitybridge/easycla/cla-backend-go/v2/gitlab_activity"
 
 	"github.com/gofrs/uuid"
 
@@ -41,11 +43,13 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
 	v2Gerrits "github.com/communitybridge/easycla/cla-backend-go/v2/gerrits"
 
-	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/aws/session"
 
 	lfxAuth "github.com/LF-Engineering/lfx-kit/auth"
 	"github.com/communitybridge/easycla/cla-backend-go/docs"
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	v2Docs "github.com/communitybridge/easycla/cla-backend-go/v2/docs"
 	v2Events "github.com/communitybridge/easycla/cla-backend-go/v2/events"
@@ -69,6 +73,8 @@ import (
 
 	"github.com/communitybridge/easycla/cla-backend-go/auth"
 	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
 	"github.com/communitybridge/easycla/cla-backend-go/docraptor"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi"
@@ -76,15 +82,22 @@ import (
 	v2RestAPI "github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi"
 	v2Ops "github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
 	"github.com/communitybridge/easycla/cla-backend-go/health"
+	"github.com/communitybridge/easycla/cla-backend-go/htmlpdf"
 	"github.com/communitybridge/easycla/cla-backend-go/template"
 	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/check_runs"
 	v2ClaManager "github.com/communitybridge/easycla/cla-backend-go/v2/cla_manager"
 	v2Company "github.com/communitybridge/easycla/cla-backend-go/v2/company"
+	v2Coverage "github.com/communitybridge/easycla/cla-backend-go/v2/coverage"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/event_subscriptions"
 	v2Health "github.com/communitybridge/easycla/cla-backend-go/v2/health"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/resign_campaign"
 	v2Template "github.com/communitybridge/easycla/cla-backend-go/v2/template"
 
 	"github.com/go-openapi/loads"
+	"github.com/go-openapi/runtime/middleware"
 	"github.com/rs/cors"
 	"github.com/savaki/dynastore"
 	"github.com/sirupsen/logrus"
@@ -156,6 +169,8 @@ func server(localMode bool) http.Handler {
 
 	stage := viper.GetString("STAGE")
 	dynamodbRegion := ini.GetProperty("DYNAMODB_AWS_REGION")
+	storageDriver := viper.GetString("STORAGE_DRIVER")
+	tracesExporter := viper.GetString("OTEL_TRACES_EXPORTER")
 
 	log.WithFields(f).Infof("Service %s starting...", ini.ServiceName)
 
@@ -172,6 +187,8 @@ func server(localMode bool) http.Handler {
 		log.Infof("GH_ORG_VALIDATION       : %t", githubOrgValidation)
 		log.Infof("COMPANY_USER_VALIDATION : %t", companyUserValidation)
 		log.Infof("STAGE                   : %s", stage)
+		log.Infof("STORAGE_DRIVER          : %s", storageDriver)
+		log.Infof("OTEL_TRACES_EXPORTER    : %s", tracesExporter)
 		log.Infof("Service Host            : %s", host)
 		log.Infof("Service Port            : %d", *portFlag)
 	} else {
@@ -186,6 +203,8 @@ func server(localMode bool) http.Handler {
 		f["githubOrgValidation"] = githubOrgValidation
 		f["companyUserValidation"] = companyUserValidation
 		f["stage"] = stage
+		f["storageDriver"] = storageDriver
+		f["tracesExporter"] = tracesExporter
 		f["serviceHost"] = host
 		log.WithFields(f).Info("config")
 	}
@@ -194,6 +213,17 @@ func server(localMode bool) http.Handler {
 	if err != nil {
 		log.WithFields(f).WithError(err).Panic("Unable to load AWS session")
 	}
+	telemetry.InstrumentAWSSession(awsSession)
+
+	// The spans are exported in the background, the Lambda handler also starts an export after each request
+	tracingConfig := telemetry.TracingConfig{
+		ServiceName:  viper.GetString("OTEL_SERVICE_NAME"),
+		Exporter:     tracesExporter,
+		OTLPEndpoint: viper.GetString("OTEL_EXPORTER_OTLP_ENDPOINT"),
+	}
+	if err = telemetry.InitTracing(tracingConfig); err != nil {
+		log.WithFields(f).WithError(err).Fatal("unable to initialize the tracing")
+	}
 
 	configFile := ini.GetConfig()
 
@@ -210,9 +240,19 @@ func server(localMode bool) http.Handler {
 	api := operations.NewClaAPI(swaggerSpec)
 	v2API := v2Ops.NewEasyclaAPI(v2SwaggerSpec)
 
-	docraptorClient, err := docraptor.NewDocraptorClient(configFile.Docraptor.APIKey, configFile.Docraptor.TestMode)
-	if err != nil {
-		log.WithFields(f).WithError(err).Panic("unable to setup docraptor client")
+	var pdfRenderer template.PDFRenderer
+	switch configFile.PDFRenderer {
+	case config.PDFRendererLocal:
+		log.WithFields(f).Warn("rendering the templates with the experimental local pdf renderer - use docraptor in production")
+		pdfRenderer = htmlpdf.NewRenderer()
+	case "", config.PDFRendererDocraptor:
+		docraptorClient, docraptorErr := docraptor.NewDocraptorClient(configFile.Docraptor.APIKey, configFile.Docraptor.TestMode)
+		if docraptorErr != nil {
+			log.WithFields(f).WithError(docraptorErr).Panic("unable to setup docraptor client")
+		}
+		pdfRenderer = docraptorClient
+	default:
+		log.WithFields(f).Panicf("unsupported pdf renderer: %s", configFile.PDFRenderer)
 	}
 
 	authValidator, err := auth.NewAuthValidator(
@@ -224,22 +264,33 @@ func server(localMode bool) http.Handler {
 		logrus.Panic(err)
 	}
 	github.Init(configFile.Github.AppID, configFile.Github.AppPrivateKey, configFile.Github.AccessToken)
+	gitlab.Init(configFile.GitLab.BaseURL, configFile.GitLab.AccessToken)
+
+	if storageDriver == storage.MemoryDriverName && !localMode {
+		log.WithFields(f).Fatal("the memory storage driver is only supported in local mode")
+	}
+	dynamoDBClient, err := storage.NewDriver(storageDriver, awsSession, stage)
+	if err != nil {
+		log.WithFields(f).WithError(err).Fatalf("unsupported STORAGE_DRIVER value: %s", storageDriver)
+	}
 
 	// Our backend repository handlers
-	userRepo := user.NewDynamoRepository(awsSession, stage)
-	usersRepo := users.NewRepository(awsSession, stage)
-	repositoriesRepo := repositories.NewRepository(awsSession, stage)
-	gerritRepo := gerrits.NewRepository(awsSession, stage)
-	templateRepo := template.NewRepository(awsSession, stage)
-	approvalListRepo := approval_list.NewRepository(awsSession, stage)
-	companyRepo := company.NewRepository(awsSession, stage)
-	signaturesRepo := signatures.NewRepository(awsSession, stage, companyRepo, usersRepo)
-	projectClaGroupRepo := projects_cla_groups.NewRepository(awsSession, stage)
-	projectRepo := project.NewRepository(awsSession, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
-	eventsRepo := events.NewRepository(awsSession, stage)
-	metricsRepo := metrics.NewRepository(awsSession, stage, configFile.APIGatewayURL, projectClaGroupRepo)
-	githubOrganizationsRepo := github_organizations.NewRepository(awsSession, stage)
-	claManagerReqRepo := cla_manager.NewRepository(awsSession, stage)
+	userRepo := user.NewDynamoRepository(dynamoDBClient, stage)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	templateRepo := template.NewRepository(dynamoDBClient, stage)
+	approvalListRepo := approval_list.NewRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	signaturesRepo := signatures.NewRepository(dynamoDBClient, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := events.NewRepository(dynamoDBClient, stage)
+	metricsRepo := metrics.NewRepository(dynamoDBClient, stage, configFile.APIGatewayURL, projectClaGroupRepo)
+	githubOrganizationsRepo := github_organizations.NewRepository(dynamoDBClient, stage)
+	claManagerReqRepo := cla_manager.NewRepository(dynamoDBClient, stage)
+	resignCampaignRepo := resign_campaign.NewRepository(dynamoDBClient, stage)
+	eventSubscriptionsRepo := event_subscriptions.NewRepository(dynamoDBClient, stage)
 
 	// Our service layer handlers
 	eventsService := events.NewService(eventsRepo, combinedRepo{
@@ -257,8 +308,8 @@ func server(localMode bool) http.Handler {
 	acs_service.InitClient(configFile.APIGatewayURL, configFile.AcsAPIKey)
 
 	usersService := users.NewService(usersRepo, eventsService)
-	healthService := health.New(Version, Commit, Branch, BuildDate)
-	templateService := template.NewService(stage, templateRepo, docraptorClient, awsSession)
+	healthService := health.New(Version, Commit, Branch, BuildDate, readinessChecks(awsSession, dynamoDBClient, configFile, stage)...)
+	templateService := template.NewService(stage, templateRepo, pdfRenderer, awsSession)
 	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo, usersRepo)
 	v2ProjectService := v2Project.NewService(projectService, projectRepo, projectClaGroupRepo)
 	companyService := company.NewService(companyRepo, configFile.CorporateConsoleURL, userRepo, usersService)
@@ -276,7 +327,16 @@ func server(localMode bool) http.Handler {
 	githubOrganizationsService := github_organizations.NewService(githubOrganizationsRepo, repositoriesRepo, projectClaGroupRepo)
 	v2GithubOrganizationsService := v2GithubOrganizations.NewService(githubOrganizationsRepo, repositoriesRepo, projectClaGroupRepo)
 	autoEnableService := dynamo_events.NewAutoEnableService(repositoriesService, repositoriesRepo, githubOrganizationsRepo, projectClaGroupRepo, projectService)
-	v2GithubActivityService := v2GithubActivity.NewService(repositoriesRepo, eventsService, autoEnableService)
+	coverageEvaluator := coverage.NewEvaluator(usersRepo, signaturesRepo, projectRepo)
+	checkRunsService := check_runs.NewService(check_runs.NewRepository(dynamoDBClient, stage), repositoriesRepo, coverageEvaluator, configFile.ClaV1ApiURL, github.NewGithubAppClient)
+	v2GithubActivityService := v2GithubActivity.NewService(repositoriesRepo, githubOrganizationsRepo, eventsService, autoEnableService, checkRunsService)
+	v2GitlabActivityService := v2GitlabActivity.NewService(repositoriesRepo, githubOrganizationsRepo, projectClaGroupRepo, coverageEvaluator)
+	var githubDeliveryStore v2GithubActivity.DeliveryStore
+	if localMode {
+		githubDeliveryStore = v2GithubActivity.NewInMemoryDeliveryStore()
+	} else {
+		githubDeliveryStore = v2GithubActivity.NewDynamoDeliveryStore(dynamoDBClient, stage)
+	}
 	gerritService := gerrits.NewService(gerritRepo, &gerrits.LFGroup{
 		LfBaseURL:    configFile.LFGroup.ClientURL,
 		ClientID:     configFile.LFGroup.ClientID,
@@ -284,8 +344,15 @@ func server(localMode bool) http.Handler {
 		RefreshToken: configFile.LFGroup.RefreshToken,
 	})
 	v2ClaGroupService := cla_groups.NewService(projectService, templateService, projectClaGroupRepo, v1ClaManagerService, signaturesService, metricsRepo, gerritService, repositoriesService, eventsService)
+	resignCampaignService := resign_campaign.NewService(resignCampaignRepo, templateService, signaturesService, usersService, eventsService)
+	eventSubscriptionsService := event_subscriptions.NewService(eventSubscriptionsRepo)
 
-	sessionStore, err := dynastore.New(dynastore.Path("/"), dynastore.HTTPOnly(), dynastore.TableName(configFile.SessionStoreTableName), dynastore.DynamoDB(dynamodb.New(awsSession)))
+	// the sessions are kept by the storage driver as well, in the session store table of the in-memory store
+	sessionStoreTableName := configFile.SessionStoreTableName
+	if storageDriver == storage.MemoryDriverName {
+		sessionStoreTableName = fmt.Sprintf("cla-%s-session-store", stage)
+	}
+	sessionStore, err := dynastore.New(dynastore.Path("/"), dynastore.HTTPOnly(), dynastore.TableName(sessionStoreTableName), dynastore.DynamoDB(storage.NewDynamoDBClient(dynamoDBClient, awsSession)))
 	if err != nil {
 		log.WithFields(f).WithError(err).Panic("unable to create new Dynastore session")
 	}
@@ -327,7 +394,18 @@ func server(localMode bool) http.Handler {
 	v2ClaManager.Configure(v2API, v2ClaManagerService, configFile.LFXPortalURL, projectClaGroupRepo, userRepo)
 	sign.Configure(v2API, v2SignService)
 	cla_groups.Configure(v2API, v2ClaGroupService, projectService, projectClaGroupRepo, eventsService)
-	v2GithubActivity.Configure(v2API, v2GithubActivityService)
+	resign_campaign.Configure(v2API, resignCampaignService, projectService)
+	event_subscriptions.Configure(v2API, eventSubscriptionsService, projectService)
+	v2Coverage.Configure(v2API, coverageEvaluator, repositoriesRepo, projectService)
+	if len(configFile.Github.WebhookSecrets) == 0 {
+		// the deliveries can't be verified, each one would be rejected
+		if !localMode {
+			log.WithFields(f).Fatal("the GitHub App webhook secret is not configured, set the cla-gh-app-webhook-secret-<stage> SSM parameter")
+		}
+		log.WithFields(f).Warn("the GitHub App webhook secret is not configured, the GitHub webhook deliveries will be rejected")
+	}
+	v2GithubActivity.Configure(v2API, v2GithubActivityService, eventsService, configFile.Github.WebhookSecrets, githubDeliveryStore)
+	v2GitlabActivity.Configure(v2API, v2GitlabActivityService, configFile.GitLab.WebhookSecrets)
 
 	userCreaterMiddleware := func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -339,7 +417,7 @@ func server(localMode bool) http.Handler {
 	// The middleware configuration is for the handler executors. These do not apply to the swagger.json document.
 	// The middleware executes after routing but before authentication, binding and validation
 	middlewareSetupfunc := func(handler http.Handler) http.Handler {
-		return setRequestIDHandler(responseLoggingMiddleware(userCreaterMiddleware(handler)))
+		return setRequestIDHandler(tracingMiddleware(responseLoggingMiddleware(userCreaterMiddleware(handler))))
 	}
 
 	v2API.CsvProducer = openapi_runtime.ProducerFunc(func(w io.Writer, data interface{}) error {
@@ -408,6 +486,37 @@ func server(localMode bool) http.Handler {
 	return apiHandler
 }
 
+// readinessChecks returns the dependencies probed by the readiness check - HEALTH_NON_CRITICAL_CHECKS overrides the
+// checks which only degrade the service, e.g. "sns,docraptor,platform"
+func readinessChecks(awsSession *session.Session, dynamoDBClient storage.Driver, configFile config.Config, stage string) []health.Check {
+	checks := health.DynamoDBTableChecks(dynamoDBClient, health.CoreTableNames(stage))
+	checks = append(checks,
+		health.S3BucketCheck(awsSession, configFile.SignatureFilesBucket),
+		health.SNSTopicCheck(awsSession, configFile.SNSEventTopicARN),
+		health.TokenCheck(),
+	)
+	if configFile.PDFRenderer != config.PDFRendererLocal {
+		checks = append(checks, health.HTTPCheck("docraptor", "https://docraptor.com/", false))
+	}
+
+	// The platform services are reached through the API gateway, the API keeps serving the v1 and GitHub flows
+	// without them
+	apiGwURL := strings.TrimPrefix(configFile.APIGatewayURL, "https://")
+	for _, service := range []struct{ name, basePath string }{
+		{"user-service", "user-service/v1"},
+		{"project-service", "project-service/v1"},
+		{"organization-service", "organization-service"},
+		{"acs-service", "acs/v1/api"},
+	} {
+		checks = append(checks, health.HTTPCheck("platform:"+service.name, fmt.Sprintf("https://%s/%s/", apiGwURL, service.basePath), false))
+	}
+
+	if nonCritical := viper.GetString("HEALTH_NON_CRITICAL_CHECKS"); nonCritical != "" {
+		checks = health.WithNonCritical(checks, strings.Split(nonCritical, ","))
+	}
+	return checks
+}
+
 // setupCORSHandler sets up the CORS logic and creates the middleware HTTP handler
 func setupCORSHandler(handler http.Handler, allowedOrigins []string) http.Handler {
 	f := logrus.Fields{
@@ -540,31 +649,47 @@ func setRequestIDHandler(next http.Handler) http.Handler {
 	})
 }
 
-// responseLoggingMiddleware logs the responses from API endpoints
+// tracingMiddleware starts the span of the request - the spans started from the context of the handlers are its
+// children, including the ones started from a context holding only the x-request-id value
+func tracingMiddleware(next http.Handler) http.Handler {
+	return telemetry.ServerHandler(next, func(r *http.Request) string {
+		route := telemetry.UnmatchedRoute
+		if matched := middleware.MatchedRouteFrom(r); matched != nil {
+			route = matched.BasePath + matched.PathPattern
+		}
+		return fmt.Sprintf("%s %s", r.Method, route)
+	})
+}
+
+// responseLoggingMiddleware logs the responses from API endpoints and records the request metrics
 func responseLoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		requestStart(r.Header.Get(utils.XREQUESTID), r.Method)
+		reqID := r.Header.Get(utils.XREQUESTID)
+		requestStart(reqID, r.Method)
 		log.Debugf("BEGIN - %s %s", r.Method, r.URL.String())
-		next.ServeHTTP(NewLoggingResponseWriter(w), r)
-		if r.Response != nil {
-			reqMetrics := getRequestMetrics(r.Header.Get(utils.XREQUESTID))
-			if reqMetrics != nil {
-				log.Debugf("END - %s %s - response code: %d response status: %s, elapsed: %v",
-					r.Method, r.URL.String(), r.Response.StatusCode, r.Response.Status, reqMetrics.elapsed)
-			} else {
-				log.Debugf("END - %s %s - response code: %d response status: %s",
-					r.Method, r.URL.String(), r.Response.StatusCode, r.Response.Status)
-			}
-			clearRequestMetrics(r.Header.Get(utils.XREQUESTID))
-		} else {
-			reqMetrics := getRequestMetrics(r.Header.Get(utils.XREQUESTID))
-			if reqMetrics != nil {
-				log.Debugf("END - %s %s, elapsed: %v", r.Method, r.URL.String(), reqMetrics.elapsed)
-			} else {
-				log.Debugf("END - %s %s", r.Method, r.URL.String())
-			}
-			clearRequestMetrics(r.Header.Get(utils.XREQUESTID))
+		lrw := NewLoggingResponseWriter(w)
+		next.ServeHTTP(lrw, r)
+		statusCode := lrw.StatusCode
+		if statusCode == 0 {
+			// The handler wrote the body without calling WriteHeader
+			statusCode = http.StatusOK
+		}
+
+		reqMetrics := getRequestMetrics(reqID)
+		clearRequestMetrics(reqID)
+		if reqMetrics == nil {
+			log.Debugf("END - %s %s - response code: %d", r.Method, r.URL.String(), statusCode)
+			return
+		}
+		log.Debugf("END - %s %s - response code: %d, elapsed: %v", r.Method, r.URL.String(), statusCode, reqMetrics.elapsed)
+
+		// The middleware executes after routing, the route is the path pattern of the matched operation prefixed
+		// with the base path of its API version
+		var route string
+		if matched := middleware.MatchedRouteFrom(r); matched != nil {
+			route = matched.BasePath + matched.PathPattern
 		}
+		telemetry.ObserveHTTPRequest(route, r.Method, statusCode, reqMetrics.elapsed)
 	})
 }
 
@@ -6,15 +6,22 @@
 package cmd
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/LF-Engineering/aws-lambda-go-api-proxy/httpadapter"
 	"github.com/aws/aws-lambda-go/lambda"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 func runServer(cmd *cobra.Command, args []string) {
 	log.Info("Lambda server starting...")
-	handler := server(false)
+	// Nothing can scrape the Lambda functions, the metrics are written to the function logs after each request
+	// as CloudWatch embedded metric logs - the export of the spans is started after each request as well
+	handler := telemetry.EMFHandler(telemetry.FlushTracesHandler(server(false)), os.Stdout, fmt.Sprintf("EasyCLA/%s", viper.GetString("STAGE")))
 
 	lambdaHandler := httpadapter.New(handler)
 
@@ -13,6 +13,7 @@ import (
 	"syscall"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -21,12 +22,15 @@ import (
 func runServer(cmd *cobra.Command, args []string) {
 	log.Info("Staring the HTTP server in local mode...")
 
-	handler := server(true)
+	// The Prometheus scrape endpoint is served next to the API, outside of the CORS and routing handlers
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", telemetry.Handler())
+	mux.Handle("/", server(true))
 
 	errs := make(chan error, 2)
 	go func() {
 		log.Infof("Running http server on port: %d - set PORT environment variable to change port", viper.GetInt("PORT"))
-		errs <- http.ListenAndServe(fmt.Sprintf(":%d", viper.GetInt("PORT")), handler)
+		errs <- http.ListenAndServe(fmt.Sprintf(":%d", viper.GetInt("PORT")), mux)
 	}()
 	go func() {
 		c := make(chan os.Signal)
@@ -0,0 +1,141 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/communitybridge/easycla/cla-backend-go/company"
+	"github.com/communitybridge/easycla/cla-backend-go/config"
+	claevents "github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/user"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	v2Signatures "github.com/communitybridge/easycla/cla-backend-go/v2/signatures"
+)
+
+var (
+	// version the application version
+	version string
+
+	// build/Commit the application build number
+	commit string
+
+	// branch the build branch
+	branch string
+
+	// build date
+	buildDate string
+)
+
+func printBuildInfo() {
+	log.Infof("Version                 : %s", version)
+	log.Infof("Git commit hash         : %s", commit)
+	log.Infof("Branch                  : %s", branch)
+	log.Infof("Build date              : %s", buildDate)
+}
+
+// newSignatureService wires the v2 signature service with the repositories of the stage
+func newSignatureService(awsSession *session.Session, stage string, configFile config.Config) v2Signatures.Service {
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
+	userRepo := user.NewDynamoRepository(dynamoDBClient, stage)
+	companyRepo := company.NewRepository(dynamoDBClient, stage)
+	signaturesRepo := signatures.NewRepository(dynamoDBClient, stage, companyRepo, usersRepo)
+	projectClaGroupRepo := projects_cla_groups.NewRepository(dynamoDBClient, stage)
+	repositoriesRepo := repositories.NewRepository(dynamoDBClient, stage)
+	gerritRepo := gerrits.NewRepository(dynamoDBClient, stage)
+	projectRepo := project.NewRepository(dynamoDBClient, stage, repositoriesRepo, gerritRepo, projectClaGroupRepo)
+	eventsRepo := claevents.NewRepository(dynamoDBClient, stage)
+
+	type combinedRepo struct {
+		users.UserRepository
+		company.IRepository
+		project.ProjectRepository
+	}
+	eventsService := claevents.NewService(eventsRepo, combinedRepo{
+		usersRepo,
+		companyRepo,
+		projectRepo,
+	})
+	usersService := users.NewService(usersRepo, eventsService)
+	companyService := company.NewService(companyRepo, configFile.CorporateConsoleURL, userRepo, usersService)
+	projectService := project.NewService(projectRepo, repositoriesRepo, gerritRepo, projectClaGroupRepo, usersRepo)
+	signaturesService := signatures.NewService(signaturesRepo, companyService, usersService, eventsService, true)
+
+	utils.SetS3Storage(awsSession, configFile.SignatureFilesBucket)
+	return v2Signatures.NewService(awsSession, configFile.SignatureFilesBucket, projectService, companyService, signaturesService, projectClaGroupRepo)
+}
+
+func main() {
+	var claGroupIDs string
+	var verbose bool
+	flag.StringVar(&claGroupIDs, "cla-group-ids", "", "comma separated list of CLA Group IDs to verify")
+	flag.BoolVar(&verbose, "verbose", false, "report every signature, including the verified ones")
+	flag.Parse()
+
+	printBuildInfo()
+	stage := os.Getenv("STAGE")
+	if stage == "" {
+		log.Fatal("stage not set")
+	}
+	log.Infof("STAGE set to %s\n", stage)
+	if claGroupIDs == "" {
+		log.Fatal("missing -cla-group-ids parameter")
+	}
+
+	awsSession := session.Must(session.NewSession(&aws.Config{}))
+	configFile, err := config.LoadConfig("", awsSession, stage)
+	if err != nil {
+		log.Panicf("Unable to load config - Error: %v", err)
+	}
+	signatureService := newSignatureService(awsSession, stage, configFile)
+
+	ctx := utils.NewContext()
+	var failures int
+	for _, claGroupID := range strings.Split(claGroupIDs, ",") {
+		claGroupID = strings.TrimSpace(claGroupID)
+		if claGroupID == "" {
+			continue
+		}
+
+		results, verifyErr := signatureService.VerifyCLAGroupSignedDocuments(ctx, claGroupID)
+		if verifyErr != nil {
+			log.Fatalf("unable to verify the signed documents of CLA Group: %s, error: %+v", claGroupID, verifyErr)
+		}
+
+		counts := map[string]int{}
+		for _, result := range results {
+			counts[result.Status]++
+			if result.Status == v2Signatures.SignedDocumentMismatch || result.Status == v2Signatures.SignedDocumentMissing {
+				failures++
+			} else if !verbose {
+				continue
+			}
+			fmt.Printf("%s\t%s\t%s\t%s\t%s\n", claGroupID, result.SignatureID, result.ClaType, result.Status, result.DocumentPath)
+		}
+
+		log.Infof("CLA Group: %s - verified: %d, mismatch: %d, missing document: %d, no digest: %d", claGroupID,
+			counts[v2Signatures.SignedDocumentVerified], counts[v2Signatures.SignedDocumentMismatch],
+			counts[v2Signatures.SignedDocumentMissing], counts[v2Signatures.SignedDocumentDigestNotStored])
+	}
+
+	if failures > 0 {
+		log.Warnf("found %d mismatched or missing signed documents", failures)
+		os.Exit(1)
+	}
+}
@@ -22,9 +22,9 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 	"github.com/gofrs/uuid"
 )
 
@@ -60,16 +60,16 @@ type IRepository interface { //nolint
 
 type repository struct {
 	stage                   string
-	dynamoDBClient          *dynamodb.DynamoDB
+	dynamoDBClient          storage.Driver
 	companyTableName        string
 	companyInvitesTableName string
 }
 
 // NewRepository creates a new company repository instance
-func NewRepository(awsSession *session.Session, stage string) IRepository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) IRepository {
 	return repository{
 		stage:                   stage,
-		dynamoDBClient:          dynamodb.New(awsSession),
+		dynamoDBClient:          dynamoDBClient,
 		companyTableName:        fmt.Sprintf("cla-%s-companies", stage),
 		companyInvitesTableName: fmt.Sprintf("cla-%s-company-invites", stage),
 	}
@@ -103,7 +103,7 @@ func (repo repository) GetCompanies(ctx context.Context) (*models.Companies, err
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, dbErr := repo.dynamoDBClient.Scan(scanInput)
+		results, dbErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if dbErr != nil {
 			log.WithFields(f).Warnf("error retrieving get all companies, error: %v", dbErr)
 			return nil, dbErr
@@ -179,7 +179,7 @@ func (repo repository) GetCompanyByExternalID(ctx context.Context, companySFID s
 		IndexName:                 aws.String("external-company-index"),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("error retrieving company using company_external_id. error = %s", err.Error())
 		return nil, err
@@ -225,7 +225,7 @@ func (repo repository) GetCompanyByName(ctx context.Context, companyName string)
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving company by companyName: %s, error: %+v", companyName, queryErr)
 		return nil, queryErr
@@ -257,7 +257,7 @@ func (repo repository) GetCompany(ctx context.Context, companyID string) (*model
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"companyID":      companyID,
 	}
-	companyTableData, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	companyTableData, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(repo.companyTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"company_id": {
@@ -345,7 +345,7 @@ func (repo repository) SearchCompanyByName(ctx context.Context, companyName stri
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, dbErr := repo.dynamoDBClient.Scan(scanInput)
+		results, dbErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if dbErr != nil {
 			log.Warnf("error retrieving companies for search term: %s, error: %v", companyName, dbErr)
 			return nil, dbErr
@@ -403,7 +403,7 @@ func (repo repository) DeleteCompanyByID(ctx context.Context, companyID string)
 		"companyID":      companyID,
 	}
 	log.WithFields(f).Debug("deleting company by ID")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"company_id": {S: aws.String(companyID)},
 		},
@@ -426,7 +426,7 @@ func (repo repository) DeleteCompanyBySFID(ctx context.Context, companySFID stri
 	}
 
 	log.WithFields(f).Debug("deleting company by SFID...")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"company_external_id": {S: aws.String(companySFID)},
 		},
@@ -498,7 +498,7 @@ func (repo repository) GetCompaniesByUserManager(ctx context.Context, userID str
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 		// Make the DynamoDB Query API call
-		results, dbErr := repo.dynamoDBClient.Scan(scanInput)
+		results, dbErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if dbErr != nil {
 			log.WithFields(f).Warnf("error retrieving companies for userID %s in ACL, error: %v", userID, dbErr)
 			return nil, dbErr
@@ -707,7 +707,7 @@ func (repo repository) GetCompanyInviteRequest(ctx context.Context, companyInvit
 		TableName:                 aws.String(repo.companyInvitesTableName),
 	}
 
-	queryResults, err := repo.dynamoDBClient.Query(queryInput)
+	queryResults, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to query the company invite based on invite ID: %s, error: %v", companyInviteID, err)
 		return nil, err
@@ -766,7 +766,7 @@ func (repo repository) GetCompanyInviteRequests(ctx context.Context, companyID s
 		IndexName:                 aws.String("requested-company-index"), // Name of a secondary index
 	}
 
-	companyInviteAV, err := repo.dynamoDBClient.Query(queryInput)
+	companyInviteAV, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to retrieve data from Company-Invites table, error: %v", err)
 		return nil, err
@@ -817,7 +817,7 @@ func (repo repository) GetCompanyUserInviteRequests(ctx context.Context, company
 		IndexName:                 aws.String("requested-company-index"), // Name of a secondary index
 	}
 
-	queryResults, err := repo.dynamoDBClient.Query(queryInput)
+	queryResults, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to retrieve data from Company-Invites table using company id: %s and user id: %s, error: %v", companyID, userID, err)
 		return nil, err
@@ -878,7 +878,7 @@ func (repo repository) GetUserInviteRequests(ctx context.Context, userID string)
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
 
-		queryResults, err := repo.dynamoDBClient.Scan(scanInput)
+		queryResults, err := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if err != nil {
 			log.WithFields(f).Warnf("Unable to retrieve data from Company-Invites table using user id: %s, error: %v", userID, err)
 			return nil, err
@@ -998,7 +998,7 @@ func (repo repository) AddPendingCompanyInviteRequest(ctx context.Context, compa
 		TableName: aws.String(fmt.Sprintf("cla-%s-company-invites", repo.stage)),
 	}
 
-	_, err = repo.dynamoDBClient.PutItem(input)
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to create a new pending invite, error: %v", err)
 		return nil, err
@@ -1071,7 +1071,7 @@ func (repo repository) updateInviteRequestStatus(ctx context.Context, companyInv
 		TableName:        aws.String(fmt.Sprintf("cla-%s-company-invites", repo.stage)),
 	}
 
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("ApproveCompanyAccessRequest - unable to update request with approved status, error: %v",
 			updateErr)
@@ -1113,7 +1113,7 @@ func (repo repository) UpdateCompanyAccessList(ctx context.Context, companyID st
 		UpdateExpression: aws.String("SET #S = :s, #M = :m"),
 	}
 
-	_, err := repo.dynamoDBClient.UpdateItem(input)
+	_, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Error updating Company Access List, error: %v", err)
 		return err
@@ -1156,7 +1156,7 @@ func (repo repository) CreateCompany(ctx context.Context, in *models.Company) (*
 	if err != nil {
 		return nil, err
 	}
-	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:      av,
 		TableName: aws.String(repo.companyTableName),
 	})
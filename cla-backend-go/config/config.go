@@ -13,6 +13,15 @@ import (
 
 var easyCLAConfig Config
 
+// PDF renderers
+const (
+	// PDFRendererDocraptor renders the templates through the DocRaptor service
+	PDFRendererDocraptor = "docraptor"
+	// PDFRendererLocal renders the templates in-process with the experimental htmlpdf renderer, e.g. for the offline
+	// dev environments
+	PDFRendererLocal = "local"
+)
+
 // Config data model
 type Config struct {
 	// Auth0
@@ -33,6 +42,10 @@ type Config struct {
 	// Docraptor
 	Docraptor Docraptor `json:"docraptor"`
 
+	// PDFRenderer selects the renderer of the CLA templates, one of PDFRendererDocraptor (the default) or the
+	// experimental PDFRendererLocal
+	PDFRenderer string `json:"pdf_renderer"`
+
 	// LF Identity
 
 	// AWS
@@ -41,6 +54,9 @@ type Config struct {
 	// Github Application
 	Github Github `json:"github"`
 
+	// GitLab - optional, GitLab support is disabled without an access token
+	GitLab GitLab `json:"gitlab"`
+
 	// Dynamo Session Store
 	SessionStoreTableName string `json:"sessionStoreTableName"`
 
@@ -117,6 +133,20 @@ type Github struct {
 	AccessToken   string `json:"accessToken"`
 	AppID         int    `json:"app_id"`
 	AppPrivateKey string `json:"app_private_key"`
+	// WebhookSecret is a comma separated list of secrets used to verify the webhook payload signatures - more
+	// than one value is supported so that the secret can be rotated without dropping deliveries
+	WebhookSecret  string   `json:"webhook_secret"`
+	WebhookSecrets []string `json:"-"`
+}
+
+// GitLab model
+type GitLab struct {
+	// BaseURL is the GitLab API URL, e.g. https://gitlab.com/api/v4
+	BaseURL     string `json:"base_url"`
+	AccessToken string `json:"access_token"`
+	// WebhookSecret is a comma separated list of the secret tokens GitLab sends with the webhook deliveries
+	WebhookSecret  string   `json:"webhook_secret"`
+	WebhookSecrets []string `json:"-"`
 }
 
 // MetricsReport keeps the config needed to send the metrics data report
@@ -156,5 +186,21 @@ func LoadConfig(configFilePath string, awsSession *session.Session, awsStage str
 	// Convert the allowed origins into an array of values
 	easyCLAConfig.AllowedOrigins = strings.Split(easyCLAConfig.AllowedOriginsCommaSeparated, ",")
 
+	// Convert the GitHub webhook secrets into an array of values, ignoring any empty entries
+	easyCLAConfig.Github.WebhookSecrets = []string{}
+	for _, secret := range strings.Split(easyCLAConfig.Github.WebhookSecret, ",") {
+		if strings.TrimSpace(secret) != "" {
+			easyCLAConfig.Github.WebhookSecrets = append(easyCLAConfig.Github.WebhookSecrets, strings.TrimSpace(secret))
+		}
+	}
+
+	// Same for the GitLab webhook secrets
+	easyCLAConfig.GitLab.WebhookSecrets = []string{}
+	for _, secret := range strings.Split(easyCLAConfig.GitLab.WebhookSecret, ",") {
+		if strings.TrimSpace(secret) != "" {
+			easyCLAConfig.GitLab.WebhookSecrets = append(easyCLAConfig.GitLab.WebhookSecrets, strings.TrimSpace(secret))
+		}
+	}
+
 	return easyCLAConfig, nil
 }
@@ -38,6 +38,20 @@ func getSSMString(ssmClient *ssm.SSM, key string) (string, error) {
 	return strings.TrimSpace(*value.Parameter.Value), nil
 }
 
+// getOptionalSSMString fetches the specified key value, returns an empty value if the key can't be read
+func getOptionalSSMString(ssmClient *ssm.SSM, key string) string {
+	value, err := ssmClient.GetParameter(&ssm.GetParameterInput{
+		Name:           aws.String(key),
+		WithDecryption: aws.Bool(false),
+	})
+	if err != nil {
+		log.Debugf("optional SSM parameter %s not set", key)
+		return ""
+	}
+
+	return strings.TrimSpace(*value.Parameter.Value)
+}
+
 // loadSSMConfig fetches all the configuration values and populates the response Config model
 func loadSSMConfig(awsSession *session.Session, stage string) Config { //nolint
 	f := logrus.Fields{
@@ -65,6 +79,7 @@ func loadSSMConfig(awsSession *session.Session, stage string) Config { //nolint
 		fmt.Sprintf("cla-gh-access-token-%s", stage),
 		fmt.Sprintf("cla-gh-app-id-%s", stage),
 		fmt.Sprintf("cla-gh-app-private-key-%s", stage),
+		fmt.Sprintf("cla-gh-app-webhook-secret-%s", stage),
 		fmt.Sprintf("cla-corporate-base-%s", stage),
 		fmt.Sprintf("cla-corporate-v2-base-%s", stage),
 		fmt.Sprintf("cla-doc-raptor-api-key-%s", stage),
@@ -132,6 +147,8 @@ func loadSSMConfig(awsSession *session.Session, stage string) Config { //nolint
 			config.Github.AppID = githubAppID
 		case fmt.Sprintf("cla-gh-app-private-key-%s", stage):
 			config.Github.AppPrivateKey = resp.value
+		case fmt.Sprintf("cla-gh-app-webhook-secret-%s", stage):
+			config.Github.WebhookSecret = resp.value
 
 		case fmt.Sprintf("cla-corporate-base-%s", stage):
 			corporateConsoleURLValue := resp.value
@@ -200,5 +217,11 @@ func loadSSMConfig(awsSession *session.Session, stage string) Config { //nolint
 		}
 	}
 
+	// Optional keys - the features using them are disabled when they aren't set
+	config.GitLab.BaseURL = getOptionalSSMString(ssmClient, fmt.Sprintf("cla-gitlab-base-url-%s", stage))
+	config.GitLab.AccessToken = getOptionalSSMString(ssmClient, fmt.Sprintf("cla-gitlab-access-token-%s", stage))
+	config.GitLab.WebhookSecret = getOptionalSSMString(ssmClient, fmt.Sprintf("cla-gitlab-webhook-secret-%s", stage))
+	config.PDFRenderer = getOptionalSSMString(ssmClient, fmt.Sprintf("cla-pdf-renderer-%s", stage))
+
 	return config
 }
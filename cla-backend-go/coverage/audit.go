@@ -0,0 +1,299 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// gitLogFormat is the git log format of the audited commits - author name, author email and the strict ISO 8601
+// author date, separated by tabs
+const gitLogFormat = "--format=%an%x09%ae%x09%aI"
+
+// auditCSVHeader is the header of the CSV audit report
+var auditCSVHeader = []string{"name", "email", "github_username", "commits", "first_commit", "last_commit", "user_id", "company_id", "reason"}
+
+// noReplyEmailPattern matches the GitHub no-reply commit emails, e.g. 1234+octocat@users.noreply.github.com
+var noReplyEmailPattern = regexp.MustCompile(`(?i)^(?:\d+\+)?([a-z\d](?:[a-z\d-]*[a-z\d])?)@users\.noreply\.github\.com$`)
+
+// ErrNoCommits is returned when the audited history has no commits
+var ErrNoCommits = errors.New("no commits to audit")
+
+// AuthorCommit is a commit of the audited history, reduced to its author and date
+type AuthorCommit struct {
+	Name           string
+	Email          string
+	GitHubUsername string
+	Date           time.Time
+}
+
+// AuditedAuthor is a commit author of the audited history with the coverage verdict
+type AuditedAuthor struct {
+	Name           string    `json:"name"`
+	Email          string    `json:"email"`
+	GitHubUsername string    `json:"github_username,omitempty"`
+	Commits        int       `json:"commits"`
+	FirstCommit    time.Time `json:"first_commit"`
+	LastCommit     time.Time `json:"last_commit"`
+	Covered        bool      `json:"covered"`
+	Rule           string    `json:"rule,omitempty"`
+	UserID         string    `json:"user_id,omitempty"`
+	CompanyID      string    `json:"company_id,omitempty"`
+	Reason         string    `json:"reason,omitempty"`
+}
+
+// AuditReport is the retroactive coverage of the commit authors of a history, only the uncovered authors are listed.
+// The exempted authors, e.g. the bots, are counted as covered and on their own.
+type AuditReport struct {
+	ClaGroupID       string           `json:"cla_group_id"`
+	Authors          int              `json:"authors"`
+	CoveredAuthors   int              `json:"covered_authors"`
+	ExemptedAuthors  int              `json:"exempted_authors"`
+	Commits          int              `json:"commits"`
+	UncoveredCommits int              `json:"uncovered_commits"`
+	Uncovered        []*AuditedAuthor `json:"uncovered"`
+}
+
+// Audit evaluates the coverage of each commit author of the history against the signatures of the CLA Group, using
+// the same matching as the pull request checks. The authors are grouped by email, the uncovered authors are reported
+// with the most commits first.
+func Audit(ctx context.Context, evaluator Evaluator, claGroupID string, commits []*AuthorCommit) (*AuditReport, error) {
+	f := logrus.Fields{
+		"functionName":   "coverage.Audit",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"commits":        len(commits),
+	}
+	if len(commits) == 0 {
+		return nil, ErrNoCommits
+	}
+
+	authors := GroupAuthors(commits)
+	log.WithFields(f).Debugf("auditing %d commit authors", len(authors))
+	ctx = WithExemptionCache(ctx)
+
+	report := &AuditReport{ClaGroupID: claGroupID, Authors: len(authors), Commits: len(commits)}
+	for _, author := range authors {
+		verdict, err := evaluator.Evaluate(ctx, claGroupID, &Identity{Email: author.Email, GitHubUsername: author.GitHubUsername})
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("problem evaluating the coverage of author: %s", author.Email)
+			return nil, err
+		}
+		author.Covered = verdict.Covered
+		author.Rule = verdict.Rule
+		author.UserID = verdict.UserID
+		author.CompanyID = verdict.CompanyID
+		author.Reason = verdict.Reason
+		if author.Covered {
+			report.CoveredAuthors++
+			if verdict.Rule == RuleExemption {
+				report.ExemptedAuthors++
+			}
+			continue
+		}
+		report.UncoveredCommits += author.Commits
+		report.Uncovered = append(report.Uncovered, author)
+	}
+
+	return report, nil
+}
+
+// GroupAuthors groups the commits by author email - by GitHub username or name for the commits without an email -
+// counting the commits and the date range of each author. The authors with the most commits come first.
+func GroupAuthors(commits []*AuthorCommit) []*AuditedAuthor {
+	byKey := make(map[string]*AuditedAuthor)
+	var authors []*AuditedAuthor
+	for _, commit := range commits {
+		email := strings.TrimSpace(commit.Email)
+		key := strings.ToLower(email)
+		if key == "" && commit.GitHubUsername != "" {
+			key = "github#" + strings.ToLower(strings.TrimSpace(commit.GitHubUsername))
+		} else if key == "" {
+			key = "name#" + strings.ToLower(strings.TrimSpace(commit.Name))
+		}
+		author, ok := byKey[key]
+		if !ok {
+			author = &AuditedAuthor{Name: strings.TrimSpace(commit.Name), Email: email, GitHubUsername: commit.GitHubUsername}
+			if author.GitHubUsername == "" {
+				author.GitHubUsername = noReplyUsername(email)
+			}
+			byKey[key] = author
+			authors = append(authors, author)
+		}
+		author.Commits++
+		if commit.Date.IsZero() {
+			continue
+		}
+		if author.FirstCommit.IsZero() || commit.Date.Before(author.FirstCommit) {
+			author.FirstCommit = commit.Date
+		}
+		if commit.Date.After(author.LastCommit) {
+			author.LastCommit = commit.Date
+		}
+	}
+
+	sort.SliceStable(authors, func(i, j int) bool {
+		if authors[i].Commits != authors[j].Commits {
+			return authors[i].Commits > authors[j].Commits
+		}
+		return strings.ToLower(authors[i].Email) < strings.ToLower(authors[j].Email)
+	})
+	return authors
+}
+
+// noReplyUsername returns the GitHub username of a GitHub no-reply email, empty for the other emails
+func noReplyUsername(email string) string {
+	match := noReplyEmailPattern.FindStringSubmatch(email)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// ReadGitLog returns the commits of the current branch of the local git repository, merge commits included
+func ReadGitLog(ctx context.Context, repositoryPath string) ([]*AuthorCommit, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", "-C", repositoryPath, "log", gitLogFormat) // nolint - the path is a git argument, not a shell command
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("unable to read the git log of %s: %v: %s", repositoryPath, err, strings.TrimSpace(stderr.String()))
+	}
+	return parseGitLog(&stdout)
+}
+
+// parseGitLog parses the output of git log with the gitLogFormat
+func parseGitLog(r io.Reader) ([]*AuthorCommit, error) {
+	var commits []*AuthorCommit
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git log output on line %d: %q", line, scanner.Text())
+		}
+		date, err := time.Parse(time.RFC3339, fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid commit date on line %d: %v", line, err)
+		}
+		commits = append(commits, &AuthorCommit{Name: fields[0], Email: fields[1], Date: date})
+	}
+	return commits, scanner.Err()
+}
+
+// ParseAuthorList parses an exported commit author list - a CSV file with a row per commit. The columns are name,
+// email and date unless the first row is a header naming the columns: name, email, github_username and date, the
+// email or the github_username column is required. The dates are ISO 8601 dates, with or without the time.
+func ParseAuthorList(r io.Reader) ([]*AuthorCommit, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, ErrNoCommits
+	}
+
+	columns := map[string]int{"name": 0, "email": 1, "date": 2, "github_username": -1}
+	if isAuthorListHeader(rows[0]) {
+		for column := range columns {
+			columns[column] = -1
+		}
+		for i, value := range rows[0] {
+			if _, ok := columns[strings.ToLower(strings.TrimSpace(value))]; ok {
+				columns[strings.ToLower(strings.TrimSpace(value))] = i
+			}
+		}
+		if columns["email"] < 0 && columns["github_username"] < 0 {
+			return nil, errors.New("the author list needs an email or a github_username column")
+		}
+		rows = rows[1:]
+	}
+
+	value := func(row []string, column string) string {
+		i := columns[column]
+		if i < 0 || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+	var commits []*AuthorCommit
+	for i, row := range rows {
+		commit := &AuthorCommit{Name: value(row, "name"), Email: value(row, "email"), GitHubUsername: value(row, "github_username")}
+		if commit.Email == "" && commit.GitHubUsername == "" {
+			return nil, fmt.Errorf("row %d of the author list has no email or github username", i+1)
+		}
+		if date := value(row, "date"); date != "" {
+			commit.Date, err = ParseCommitDate(date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date on row %d of the author list: %v", i+1, err)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+func isAuthorListHeader(row []string) bool {
+	for _, value := range row {
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "email", "github_username":
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCommitDate parses an ISO 8601 commit date, a date and time with a time zone or only the date
+func ParseCommitDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// WriteCSV writes the uncovered authors of the report as CSV, with a header row
+func (r *AuditReport) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVHeader); err != nil {
+		return err
+	}
+	for _, author := range r.Uncovered {
+		row := []string{author.Name, author.Email, author.GitHubUsername, strconv.Itoa(author.Commits),
+			formatCommitDate(author.FirstCommit), formatCommitDate(author.LastCommit), author.UserID, author.CompanyID, author.Reason}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatCommitDate formats the date of a commit, empty for an unknown date
+func formatCommitDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+	return date.UTC().Format(time.RFC3339)
+}
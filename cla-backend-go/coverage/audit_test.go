@@ -0,0 +1,79 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEvaluator struct {
+	Evaluator
+	covered map[string]bool
+}
+
+func (e *fakeEvaluator) Evaluate(ctx context.Context, claGroupID string, identity *Identity) (*Verdict, error) {
+	if e.covered[identity.Email] || e.covered[identity.GitHubUsername] {
+		return &Verdict{Covered: true, Rule: RuleICLA}, nil
+	}
+	return &Verdict{Reason: ReasonUnknownUser}, nil
+}
+
+func TestParseAuthorList(t *testing.T) {
+	commits, err := ParseAuthorList(strings.NewReader("date,email,name\n2021-01-05,jane@example.org,Jane\n2021-02-01T10:00:00+01:00,jane@example.org,Jane\n"))
+	if assert.NoError(t, err) && assert.Len(t, commits, 2) {
+		assert.Equal(t, "Jane", commits[0].Name)
+		assert.Equal(t, "jane@example.org", commits[0].Email)
+		assert.Equal(t, time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC), commits[0].Date)
+	}
+
+	// without a header the columns are name, email and date
+	commits, err = ParseAuthorList(strings.NewReader("\"Doe, Joe\",joe@example.org,2021-03-01\n"))
+	if assert.NoError(t, err) && assert.Len(t, commits, 1) {
+		assert.Equal(t, "Doe, Joe", commits[0].Name)
+	}
+
+	_, err = ParseAuthorList(strings.NewReader("name,email\nJane,\n"))
+	assert.Error(t, err)
+}
+
+func TestAudit(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2021, 1, d, 0, 0, 0, 0, time.UTC) }
+	commits, err := parseGitLog(strings.NewReader(strings.Join([]string{
+		"Jane Doe\tjane@example.org\t2021-01-03T00:00:00Z",
+		"Jane Doe\tJane@Example.org\t2021-01-01T00:00:00Z",
+		"Joe\tjoe@example.org\t2021-01-02T00:00:00Z",
+		"Octocat\t1234+octocat@users.noreply.github.com\t2021-01-04T00:00:00Z",
+		"Octocat\t1234+octocat@users.noreply.github.com\t2021-01-05T00:00:00Z",
+		"Octocat\t1234+octocat@users.noreply.github.com\t2021-01-06T00:00:00Z",
+	}, "\n")))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	report, err := Audit(context.Background(), &fakeEvaluator{covered: map[string]bool{"joe@example.org": true}}, "cla-group-1", commits)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, report.Authors)
+	assert.Equal(t, 1, report.CoveredAuthors)
+	assert.Equal(t, 5, report.UncoveredCommits)
+	if assert.Len(t, report.Uncovered, 2) {
+		assert.Equal(t, "octocat", report.Uncovered[0].GitHubUsername)
+		assert.Equal(t, 3, report.Uncovered[0].Commits)
+		assert.Equal(t, 2, report.Uncovered[1].Commits)
+		assert.Equal(t, day(1), report.Uncovered[1].FirstCommit)
+		assert.Equal(t, day(3), report.Uncovered[1].LastCommit)
+	}
+
+	var csv bytes.Buffer
+	assert.NoError(t, report.WriteCSV(&csv))
+	assert.Equal(t, 3, strings.Count(csv.String(), "\n"))
+	assert.Contains(t, csv.String(), "Jane Doe,jane@example.org,,2,2021-01-01T00:00:00Z,2021-01-03T00:00:00Z,,,"+ReasonUnknownUser)
+}
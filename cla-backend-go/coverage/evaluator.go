@@ -0,0 +1,376 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	openapierrors "github.com/go-openapi/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// coverage rules, the rule which covered the contributor
+const (
+	RuleICLA      = "icla"
+	RuleCCLA      = "ccla"
+	RuleExemption = "exemption"
+)
+
+// reasons the contributor isn't covered
+const (
+	ReasonUnknownUser           = "no EasyCLA user matches the contributor"
+	ReasonNoSignature           = "the contributor has not signed an ICLA and is not associated with a company"
+	ReasonNoEmployeeSignature   = "the contributor has not acknowledged the corporate CLA of the company"
+	ReasonNoCorporateSignature  = "the company of the contributor has not signed a corporate CLA"
+	ReasonNotInApprovalList     = "the contributor is not in the approval list of the corporate CLA"
+	ReasonApprovalListEntryGone = "the approval list entry matching the contributor has expired"
+)
+
+// Identity is the contributor as seen by the code hosting service, e.g. the author of a commit
+type Identity struct {
+	Email string
+	// GitHubUserID and GitHubUsername are the GitHub user the commit is linked to, verified by GitHub unlike the
+	// commit email
+	GitHubUserID   int64
+	GitHubUsername string
+	GitLabUsername string
+	// LFUsername is only used to find the user record, e.g. when a contributor is looked up by their LF ID
+	LFUsername string
+	// GitHubOrganizations are the GitHub organizations the contributor is a member of, matched against the GitHub
+	// organization approval list when provided
+	GitHubOrganizations []string
+}
+
+// Verdict is the outcome of the coverage evaluation of a contributor
+type Verdict struct {
+	Covered           bool
+	Rule              string
+	UserID            string
+	CompanyID         string
+	SignatureID       string
+	Reason            string
+	ApprovalListType  string
+	ApprovalListEntry string
+	// the exemption of the CLA Group covering the contributor, set for the exemption rule
+	ExemptionID      string
+	ExemptionType    string
+	ExemptionPattern string
+}
+
+// CLAGroupRepository loads the CLA Group of an evaluation, for its exemptions
+type CLAGroupRepository interface {
+	GetCLAGroupByID(ctx context.Context, claGroupID string, loadRepoDetails bool) (*models.ClaGroup, error)
+}
+
+// Evaluator decides if a contributor is covered by a signature of the CLA Group
+type Evaluator interface {
+	Evaluate(ctx context.Context, claGroupID string, identity *Identity) (*Verdict, error)
+	Explain(ctx context.Context, claGroupID string, identity *Identity) (*Explanation, error)
+}
+
+type evaluator struct {
+	usersRepo     users.UserRepository
+	signatureRepo signatures.SignatureRepository
+	claGroupRepo  CLAGroupRepository
+	now           func() time.Time
+}
+
+// NewEvaluator creates a new coverage evaluator
+func NewEvaluator(usersRepo users.UserRepository, signatureRepo signatures.SignatureRepository, claGroupRepo CLAGroupRepository) Evaluator {
+	return &evaluator{
+		usersRepo:     usersRepo,
+		signatureRepo: signatureRepo,
+		claGroupRepo:  claGroupRepo,
+		now:           time.Now,
+	}
+}
+
+// Evaluate returns the verdict for the contributor, the contributor is covered by an exemption of the CLA Group, by a
+// signed ICLA, or by an employee acknowledgement of a signed corporate CLA whose approval list matches the contributor
+func (e *evaluator) Evaluate(ctx context.Context, claGroupID string, identity *Identity) (*Verdict, error) {
+	f := logrus.Fields{
+		"functionName":   "coverage.Evaluate",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"email":          identity.Email,
+		"githubUsername": identity.GitHubUsername,
+		"gitlabUsername": identity.GitLabUsername,
+	}
+
+	exemption, err := e.matchExemption(ctx, claGroupID, identity)
+	if err != nil {
+		return nil, err
+	}
+	if exemption != nil {
+		log.WithFields(f).Debugf("the contributor matches the %s exemption: %s", exemption.Type, exemption.Pattern)
+		telemetry.ObserveCoverageExemption(claGroupID, exemption.Type, exemption.Pattern)
+		return &Verdict{
+			Covered:          true,
+			Rule:             RuleExemption,
+			ExemptionID:      exemption.ExemptionID,
+			ExemptionType:    exemption.Type,
+			ExemptionPattern: exemption.Pattern,
+		}, nil
+	}
+
+	user, _, err := e.lookupUser(identity)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem looking up the user record of the contributor")
+		return nil, err
+	}
+	if user == nil {
+		log.WithFields(f).Debug("no user record matches the contributor")
+		return &Verdict{Reason: ReasonUnknownUser}, nil
+	}
+	verdict := &Verdict{UserID: user.UserID, CompanyID: user.CompanyID}
+
+	icla, err := e.signatureRepo.GetIndividualSignature(ctx, claGroupID, user.UserID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("problem loading the ICLA of user: %s", user.UserID)
+		return nil, err
+	}
+	if icla != nil {
+		verdict.Covered = true
+		verdict.Rule = RuleICLA
+		verdict.SignatureID = icla.SignatureID
+		return verdict, nil
+	}
+
+	if user.CompanyID == "" {
+		verdict.Reason = ReasonNoSignature
+		return verdict, nil
+	}
+
+	employeeSignature, err := e.signatureRepo.GetEmployeeSignature(ctx, claGroupID, user.CompanyID, user.UserID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("problem loading the employee signature of user: %s", user.UserID)
+		return nil, err
+	}
+	if employeeSignature == nil {
+		verdict.Reason = ReasonNoEmployeeSignature
+		return verdict, nil
+	}
+
+	ccla, err := e.signatureRepo.GetCorporateSignature(ctx, claGroupID, user.CompanyID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("problem loading the corporate signature of company: %s", user.CompanyID)
+		return nil, err
+	}
+	if ccla == nil {
+		verdict.Reason = ReasonNoCorporateSignature
+		return verdict, nil
+	}
+	verdict.SignatureID = ccla.SignatureID
+
+	listType, entry, expired := MatchApprovalList(ccla, identity, e.now())
+	if listType == "" {
+		verdict.Reason = ReasonNotInApprovalList
+		return verdict, nil
+	}
+	verdict.ApprovalListType = listType
+	verdict.ApprovalListEntry = entry
+	if expired {
+		verdict.Reason = ReasonApprovalListEntryGone
+		return verdict, nil
+	}
+
+	verdict.Covered = true
+	verdict.Rule = RuleCCLA
+	return verdict, nil
+}
+
+// exemptionCacheKey is the context key of the exemptions loaded by a batch of evaluations
+type exemptionCacheKey struct{}
+
+type exemptionCache struct {
+	lock       sync.Mutex
+	exemptions map[string][]*models.ClaGroupExemption
+}
+
+// WithExemptionCache returns a context keeping the exemptions of the CLA Groups loaded by the evaluations using it, so
+// a batch of evaluations - e.g. the authors of an audit or the pull requests of a CLA Group - loads them only once
+func WithExemptionCache(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(exemptionCacheKey{}).(*exemptionCache); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, exemptionCacheKey{}, &exemptionCache{exemptions: make(map[string][]*models.ClaGroupExemption)})
+}
+
+// matchExemption returns the exemption of the CLA Group matching the contributor, nil if none matches or the CLA
+// Group is unknown
+func (e *evaluator) matchExemption(ctx context.Context, claGroupID string, identity *Identity) (*models.ClaGroupExemption, error) {
+	exemptions, err := e.exemptions(ctx, claGroupID)
+	if err != nil {
+		return nil, err
+	}
+	return MatchExemption(exemptions, identity), nil
+}
+
+// exemptions returns the exemptions of the CLA Group, from the exemption cache of the context when it has one
+func (e *evaluator) exemptions(ctx context.Context, claGroupID string) ([]*models.ClaGroupExemption, error) {
+	f := logrus.Fields{
+		"functionName":   "coverage.exemptions",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+	if e.claGroupRepo == nil {
+		return nil, nil
+	}
+
+	cache, _ := ctx.Value(exemptionCacheKey{}).(*exemptionCache)
+	if cache != nil {
+		cache.lock.Lock()
+		exemptions, ok := cache.exemptions[claGroupID]
+		cache.lock.Unlock()
+		if ok {
+			return exemptions, nil
+		}
+	}
+
+	var exemptions []*models.ClaGroupExemption
+	claGroup, err := e.claGroupRepo.GetCLAGroupByID(ctx, claGroupID, false)
+	if err != nil {
+		var notFound *utils.CLAGroupNotFound
+		if !errors.As(err, &notFound) {
+			log.WithFields(f).WithError(err).Warn("problem loading the exemptions of the CLA Group")
+			return nil, err
+		}
+		log.WithFields(f).Debug("unknown CLA Group, no exemptions")
+	} else if claGroup != nil {
+		exemptions = claGroup.Exemptions
+	}
+
+	if cache != nil {
+		cache.lock.Lock()
+		cache.exemptions[claGroupID] = exemptions
+		cache.lock.Unlock()
+	}
+	return exemptions, nil
+}
+
+// lookupUser returns the user record of the contributor and the identity attribute which matched it - by GitHub user
+// ID first, then by GitHub username and then by LF username. Anyone can set the email of a commit, so the email is only
+// used when the contribution is not linked to a GitHub user.
+func (e *evaluator) lookupUser(identity *Identity) (*models.User, string, error) {
+	if identity.GitHubUserID != 0 {
+		user, err := e.usersRepo.GetUserByUserName(fmt.Sprintf("github:%d", identity.GitHubUserID), true)
+		if err != nil && !isNotFound(err) {
+			return nil, "", err
+		}
+		if user != nil {
+			return user, ResolvedByGitHubUserID, nil
+		}
+	}
+	if identity.GitHubUsername != "" {
+		user, err := e.usersRepo.GetUserByGitHubUsername(identity.GitHubUsername)
+		if err != nil && !isNotFound(err) {
+			return nil, "", err
+		}
+		if user != nil {
+			return user, ResolvedByGitHubUsername, nil
+		}
+	}
+	if identity.Email != "" && identity.GitHubUserID == 0 && identity.GitHubUsername == "" {
+		user, err := e.usersRepo.GetUserByEmail(identity.Email)
+		if err != nil && !isNotFound(err) {
+			return nil, "", err
+		}
+		if user != nil {
+			return user, ResolvedByEmail, nil
+		}
+	}
+	if identity.LFUsername != "" {
+		user, err := e.usersRepo.GetUserByLFUserName(identity.LFUsername)
+		if err != nil && !isNotFound(err) {
+			return nil, "", err
+		}
+		if user != nil {
+			return user, ResolvedByLFUsername, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// isNotFound returns true if the error is the not found error of the users repository
+func isNotFound(err error) bool {
+	var apiErr openapierrors.Error
+	return errors.As(err, &apiErr) && apiErr.Code() == http.StatusNotFound
+}
+
+// MatchApprovalList returns the approval list type and entry of the corporate signature matching the contributor, an
+// empty type if no entry matches. A match on an entry which expired at or before the specified time is only reported
+// when no other entry matches.
+func MatchApprovalList(sig *models.Signature, identity *Identity, now time.Time) (string, string, bool) {
+	key := func(listType, value string) string {
+		return listType + "#" + strings.ToLower(strings.TrimSpace(value))
+	}
+	expired := make(map[string]bool)
+	for _, detail := range signatures.ExpiredApprovalListEntries(sig, now) {
+		expired[key(detail.ListType, detail.Value)] = true
+	}
+
+	var expiredType, expiredEntry string
+	for _, candidate := range approvalListCandidates(sig, identity) {
+		listType, entries, match := candidate.listType, candidate.entries, candidate.match
+		for _, entry := range entries {
+			if !match(entry) {
+				continue
+			}
+			if !expired[key(listType, entry)] {
+				return listType, entry, false
+			}
+			if expiredType == "" {
+				expiredType, expiredEntry = listType, entry
+			}
+		}
+	}
+	if expiredType != "" {
+		return expiredType, expiredEntry, true
+	}
+	return "", "", false
+}
+
+type approvalListCandidate struct {
+	listType string
+	entries  []string
+	match    func(entry string) bool
+}
+
+// approvalListCandidates returns the approval lists of the signature in the order they are matched
+func approvalListCandidates(sig *models.Signature, identity *Identity) []approvalListCandidate {
+	equalFold := func(value string) func(string) bool {
+		return func(entry string) bool {
+			return value != "" && strings.EqualFold(strings.TrimSpace(entry), value)
+		}
+	}
+	return []approvalListCandidate{
+		{signatures.ApprovalListTypeEmail, sig.EmailApprovalList, equalFold(identity.Email)},
+		{signatures.ApprovalListTypeDomain, sig.DomainApprovalList, func(entry string) bool {
+			_, ok := utils.DomainApprovalListMatch(identity.Email, []string{entry})
+			return identity.Email != "" && ok
+		}},
+		{signatures.ApprovalListTypeGithubUsername, sig.GithubUsernameApprovalList, equalFold(identity.GitHubUsername)},
+		{signatures.ApprovalListTypeGitlabUsername, sig.GitlabUsernameApprovalList, equalFold(identity.GitLabUsername)},
+		{signatures.ApprovalListTypeGithubOrg, sig.GithubOrgApprovalList, func(entry string) bool {
+			for _, org := range identity.GitHubOrganizations {
+				if strings.EqualFold(strings.TrimSpace(entry), org) {
+					return true
+				}
+			}
+			return false
+		}},
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	openapierrors "github.com/go-openapi/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchApprovalList(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	sig := &models.Signature{
+		EmailApprovalList:          []string{"contractor@example.org"},
+		DomainApprovalList:         []string{"example.com"},
+		GithubUsernameApprovalList: []string{"octocat"},
+		GitlabUsernameApprovalList: []string{"Tanuki"},
+		GithubOrgApprovalList:      []string{"example-org"},
+		ApprovalListEntryDetails: []*models.ApprovalListEntryDetail{
+			{ListType: signatures.ApprovalListTypeEmail, Value: "contractor@example.org", ExpiresOn: "2021-05-31T00:00:00Z"},
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		identity  *Identity
+		listType  string
+		entry     string
+		isExpired bool
+	}{
+		{"domain", &Identity{Email: "jane@example.com"}, signatures.ApprovalListTypeDomain, "example.com", false},
+		{"github username", &Identity{Email: "jane@gmail.com", GitHubUsername: "OctoCat"}, signatures.ApprovalListTypeGithubUsername, "octocat", false},
+		{"gitlab username", &Identity{GitLabUsername: "tanuki"}, signatures.ApprovalListTypeGitlabUsername, "Tanuki", false},
+		{"github organization", &Identity{GitHubOrganizations: []string{"other-org", "Example-Org"}}, signatures.ApprovalListTypeGithubOrg, "example-org", false},
+		{"expired email", &Identity{Email: "contractor@example.org"}, signatures.ApprovalListTypeEmail, "contractor@example.org", true},
+		{"expired email with a valid username", &Identity{Email: "contractor@example.org", GitLabUsername: "tanuki"}, signatures.ApprovalListTypeGitlabUsername, "Tanuki", false},
+		{"no match", &Identity{Email: "joe@example.net", GitHubUsername: "joe"}, "", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listType, entry, expired := MatchApprovalList(sig, tc.identity, now)
+			assert.Equal(t, tc.listType, listType)
+			assert.Equal(t, tc.entry, entry)
+			assert.Equal(t, tc.isExpired, expired)
+		})
+	}
+}
+
+func TestMatchContributor(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	sig := &models.Signature{DomainApprovalList: []string{"example.com"}}
+	user := &models.User{UserID: "u1", LfEmail: "jane@gmail.com", Emails: []string{"jane@example.com"}, GithubUsername: "jane"}
+
+	// the identity of the contribution comes first, then the other identities of the user record
+	identities := contributorIdentities(&Identity{Email: "jane@example.net"}, user)
+	if assert.Len(t, identities, 4) {
+		assert.Equal(t, "", identities[0].source)
+		assert.Equal(t, "jane", identities[3].identity.GitHubUsername)
+	}
+
+	// an email of the user record only matches as another identity of the contributor
+	match := matchContributor(sig, identities, now)
+	if assert.NotNil(t, match) {
+		assert.Equal(t, signatures.ApprovalListTypeDomain, match.listType)
+		assert.Equal(t, "the email jane@example.com of the user record", match.source)
+	}
+
+	assert.Nil(t, matchContributor(sig, contributorIdentities(&Identity{GitHubUsername: "jane"}, &models.User{UserID: "u1"}), now))
+}
+
+// failingUserRepository fails the email lookups and doesn't find the users by their GitHub username
+type failingUserRepository struct {
+	fakeUserRepository
+}
+
+func (r *failingUserRepository) GetUserByEmail(userEmail string) (*models.User, error) {
+	return nil, errors.New("dynamodb unavailable")
+}
+
+func (r *failingUserRepository) GetUserByGitHubUsername(gitHubUsername string) (*models.User, error) {
+	return nil, openapierrors.NotFound("user not found when searching by user_github_username: %s", gitHubUsername)
+}
+
+func TestLookupUser(t *testing.T) {
+	usersRepo := &fakeUserRepository{users: []*models.User{
+		{UserID: "jane", LfEmail: "jane@example.com", GithubID: "1001", GithubUsername: "jane"},
+		{UserID: "mallory", LfEmail: "mallory@example.org", GithubID: "2002", GithubUsername: "mallory"},
+	}}
+	signatureRepo := &fakeSignatureRepository{iclas: map[string]*models.Signature{"jane": {SignatureID: "icla-jane"}}}
+	e := &evaluator{usersRepo: usersRepo, signatureRepo: signatureRepo, claGroupRepo: &countingCLAGroupRepository{}, now: time.Now}
+
+	testCases := []struct {
+		name       string
+		identity   *Identity
+		userID     string
+		resolvedBy string
+	}{
+		{"the GitHub user ID comes before the email", &Identity{Email: "jane@example.com", GitHubUserID: 2002, GitHubUsername: "mallory"}, "mallory", ResolvedByGitHubUserID},
+		{"the GitHub username comes before the email", &Identity{Email: "jane@example.com", GitHubUsername: "mallory"}, "mallory", ResolvedByGitHubUsername},
+		{"the email is ignored for a commit linked to an unknown GitHub user", &Identity{Email: "jane@example.com", GitHubUserID: 3003, GitHubUsername: "eve"}, "", ""},
+		{"the email is used for a commit not linked to a GitHub user", &Identity{Email: "jane@example.com"}, "jane", ResolvedByEmail},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, resolvedBy, err := e.lookupUser(tc.identity)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.resolvedBy, resolvedBy)
+			if tc.userID == "" {
+				assert.Nil(t, user)
+			} else if assert.NotNil(t, user) {
+				assert.Equal(t, tc.userID, user.UserID)
+			}
+		})
+	}
+
+	// the ICLA of the owner of the commit email doesn't cover the GitHub user who authored the commit
+	verdict, err := e.Evaluate(context.Background(), "cla-group-1", &Identity{Email: "jane@example.com", GitHubUserID: 2002, GitHubUsername: "mallory"})
+	if assert.NoError(t, err) {
+		assert.False(t, verdict.Covered)
+		assert.Equal(t, "mallory", verdict.UserID)
+	}
+
+	// a user not found is not an error, a failed lookup is returned so the webhook is delivered again
+	failing := &evaluator{usersRepo: &failingUserRepository{}, signatureRepo: signatureRepo, claGroupRepo: &countingCLAGroupRepository{}, now: time.Now}
+	user, _, err := failing.lookupUser(&Identity{GitHubUsername: "jane"})
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+	_, err = failing.Evaluate(context.Background(), "cla-group-1", &Identity{Email: "jane@example.com"})
+	assert.Error(t, err)
+	_, err = failing.Explain(context.Background(), "cla-group-1", &Identity{Email: "jane@example.com"})
+	assert.Error(t, err)
+}
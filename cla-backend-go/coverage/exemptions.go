@@ -0,0 +1,138 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+)
+
+// exemption types, the contributor identity the pattern of an exemption is matched against
+const (
+	ExemptionTypeGitHubUsername = "github-username"
+	ExemptionTypeGitHubApp      = "github-app"
+	ExemptionTypeGitLabUsername = "gitlab-username"
+	ExemptionTypeEmail          = "email"
+)
+
+// maxExemptionPatternLength is the maximum length of the pattern of an exemption
+const maxExemptionPatternLength = 256
+
+// gitHubAppLoginSuffix is the suffix of the login of the bot account of a GitHub App, e.g. dependabot[bot]
+const gitHubAppLoginSuffix = "[bot]"
+
+// gitHubAppSlugPattern matches the slug of a GitHub App, e.g. dependabot or renovate
+var gitHubAppSlugPattern = regexp.MustCompile(`(?i)^[a-z\d](?:[a-z\d-]*[a-z\d])?$`)
+
+// minExemptionLiteralLength is the minimum number of characters other than wildcards of the username patterns
+const minExemptionLiteralLength = 3
+
+// ErrInvalidExemption is returned when the type or the pattern of an exemption is invalid
+var ErrInvalidExemption = errors.New("invalid exemption")
+
+// NormalizeExemption validates the type and the pattern of an exemption and returns the pattern as it is stored - the
+// GitHub App exemptions are stored by the slug of the app, without the [bot] suffix of its login
+func NormalizeExemption(exemptionType, pattern string) (string, error) {
+	pattern = strings.TrimSpace(pattern)
+	if pattern == "" || len(pattern) > maxExemptionPatternLength {
+		return "", fmt.Errorf("%w: the pattern needs 1 to %d characters", ErrInvalidExemption, maxExemptionPatternLength)
+	}
+
+	switch exemptionType {
+	case ExemptionTypeGitHubApp:
+		slug := pattern
+		if strings.HasSuffix(strings.ToLower(slug), gitHubAppLoginSuffix) {
+			slug = slug[:len(slug)-len(gitHubAppLoginSuffix)]
+		}
+		if !gitHubAppSlugPattern.MatchString(slug) {
+			return "", fmt.Errorf("%w: %s is not the slug of a GitHub App", ErrInvalidExemption, pattern)
+		}
+		return strings.ToLower(slug), nil
+	case ExemptionTypeGitHubUsername, ExemptionTypeGitLabUsername:
+		// a broad pattern such as * or *a* would exempt nearly every contributor
+		if strings.Count(pattern, "*") > 1 || len(strings.Replace(pattern, "*", "", -1)) < minExemptionLiteralLength {
+			return "", fmt.Errorf("%w: the pattern %s needs at most one wildcard and %d other characters", ErrInvalidExemption, pattern, minExemptionLiteralLength)
+		}
+		return pattern, nil
+	case ExemptionTypeEmail:
+		// only the local part takes wildcards, the domain is the one of the accounts of the bot or the service
+		at := strings.LastIndex(pattern, "@")
+		if at < 1 || strings.Count(pattern, "@") > 1 {
+			return "", fmt.Errorf("%w: the pattern %s is not an email address", ErrInvalidExemption, pattern)
+		}
+		domain := pattern[at+1:]
+		if strings.Contains(domain, "*") || !strings.Contains(strings.Trim(domain, "."), ".") {
+			return "", fmt.Errorf("%w: the pattern %s needs a domain without wildcards", ErrInvalidExemption, pattern)
+		}
+		return pattern, nil
+	default:
+		return "", fmt.Errorf("%w: unsupported type %q, expecting %s, %s, %s or %s", ErrInvalidExemption, exemptionType,
+			ExemptionTypeGitHubUsername, ExemptionTypeGitHubApp, ExemptionTypeGitLabUsername, ExemptionTypeEmail)
+	}
+}
+
+// MatchExemption returns the first exemption matching the contributor, nil if no exemption matches
+func MatchExemption(exemptions []*models.ClaGroupExemption, identity *Identity) *models.ClaGroupExemption {
+	for _, exemption := range exemptions {
+		if exemption == nil {
+			continue
+		}
+		var match bool
+		switch exemption.Type {
+		case ExemptionTypeGitHubUsername:
+			match = identity.GitHubUsername != "" && matchPattern(exemption.Pattern, identity.GitHubUsername)
+		case ExemptionTypeGitHubApp:
+			slug := gitHubAppSlug(identity)
+			match = slug != "" && strings.EqualFold(slug, exemption.Pattern)
+		case ExemptionTypeGitLabUsername:
+			match = identity.GitLabUsername != "" && matchPattern(exemption.Pattern, identity.GitLabUsername)
+		case ExemptionTypeEmail:
+			match = identity.Email != "" && matchPattern(exemption.Pattern, identity.Email)
+		}
+		if match {
+			return exemption
+		}
+	}
+	return nil
+}
+
+// gitHubAppSlug returns the slug of the GitHub App of a bot account contributor, empty for the other contributors -
+// only the login GitHub resolved for the commit is considered, anyone can author a commit with the no-reply email of
+// the bot account
+func gitHubAppSlug(identity *Identity) string {
+	login := strings.TrimSpace(identity.GitHubUsername)
+	if len(login) > len(gitHubAppLoginSuffix) && strings.HasSuffix(strings.ToLower(login), gitHubAppLoginSuffix) {
+		return login[:len(login)-len(gitHubAppLoginSuffix)]
+	}
+	return ""
+}
+
+// matchPattern returns true if the value matches the case-insensitive pattern, * matches any sequence of characters
+// and every other character matches itself - the brackets of *[bot] are not a character class
+func matchPattern(pattern, value string) bool {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return value == pattern
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
+}
@@ -0,0 +1,116 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchExemption(t *testing.T) {
+	exemptions := []*models.ClaGroupExemption{
+		{ExemptionID: "e1", Type: ExemptionTypeGitHubApp, Pattern: "dependabot"},
+		{ExemptionID: "e2", Type: ExemptionTypeGitHubUsername, Pattern: "*[bot]"},
+		{ExemptionID: "e3", Type: ExemptionTypeEmail, Pattern: "ci-*@automation.example.org"},
+		{ExemptionID: "e4", Type: ExemptionTypeGitLabUsername, Pattern: "release-bot"},
+	}
+
+	testCases := []struct {
+		name        string
+		identity    *Identity
+		exemptionID string
+	}{
+		{"github app login", &Identity{GitHubUsername: "Dependabot[bot]"}, "e1"},
+		{"github app no-reply email without the login", &Identity{Email: "49699333+dependabot[bot]@users.noreply.github.com"}, ""},
+		{"github username pattern", &Identity{GitHubUsername: "renovate[bot]"}, "e2"},
+		{"brackets are not a character class", &Identity{GitHubUsername: "robot"}, ""},
+		{"email pattern", &Identity{Email: "CI-nightly@automation.example.org"}, "e3"},
+		{"email pattern suffix", &Identity{Email: "ci-nightly@automation.example.org.evil.com"}, ""},
+		{"gitlab username", &Identity{GitLabUsername: "release-bot"}, "e4"},
+		{"no match", &Identity{Email: "jane@example.org", GitHubUsername: "jane"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			exemption := MatchExemption(exemptions, tc.identity)
+			if tc.exemptionID == "" {
+				assert.Nil(t, exemption)
+			} else if assert.NotNil(t, exemption) {
+				assert.Equal(t, tc.exemptionID, exemption.ExemptionID)
+			}
+		})
+	}
+}
+
+func TestNormalizeExemption(t *testing.T) {
+	pattern, err := NormalizeExemption(ExemptionTypeGitHubApp, " Renovate[bot] ")
+	assert.NoError(t, err)
+	assert.Equal(t, "renovate", pattern)
+
+	testCases := []struct {
+		exemptionType string
+		pattern       string
+		valid         bool
+	}{
+		{ExemptionTypeGitHubUsername, "*[bot]", true},
+		{ExemptionTypeGitLabUsername, "release-bot", true},
+		{ExemptionTypeEmail, "ci-*@automation.example.org", true},
+		{ExemptionTypeEmail, "*@bots.example.org", true},
+		{ExemptionTypeGitHubApp, "*[bot]", false},
+		{ExemptionTypeEmail, "**", false},
+		{ExemptionTypeEmail, "*@*", false},
+		{ExemptionTypeEmail, "*@*.org", false},
+		{ExemptionTypeEmail, "*.*", false},
+		{ExemptionTypeEmail, "ci@localhost", false},
+		{ExemptionTypeGitHubUsername, "*a*", false},
+		{ExemptionTypeGitHubUsername, "*.*", false},
+		{ExemptionTypeGitLabUsername, "*bot", true},
+		{ExemptionTypeGitLabUsername, "*b", false},
+		{"lf-username", "jdoe", false},
+	}
+	for _, tc := range testCases {
+		_, err := NormalizeExemption(tc.exemptionType, tc.pattern)
+		if tc.valid {
+			assert.NoError(t, err, "%s %s", tc.exemptionType, tc.pattern)
+		} else {
+			assert.True(t, errors.Is(err, ErrInvalidExemption), "%s %s", tc.exemptionType, tc.pattern)
+		}
+	}
+}
+
+type countingCLAGroupRepository struct {
+	loads int
+}
+
+func (r *countingCLAGroupRepository) GetCLAGroupByID(ctx context.Context, claGroupID string, loadRepoDetails bool) (*models.ClaGroup, error) {
+	r.loads++
+	return &models.ClaGroup{
+		ProjectID:  claGroupID,
+		Exemptions: []*models.ClaGroupExemption{{ExemptionID: "e1", Type: ExemptionTypeGitHubApp, Pattern: "dependabot"}},
+	}, nil
+}
+
+func TestEvaluateExemptionCache(t *testing.T) {
+	repo := &countingCLAGroupRepository{}
+	evaluator := NewEvaluator(nil, nil, repo)
+	identity := &Identity{GitHubUsername: "dependabot[bot]"}
+
+	ctx := WithExemptionCache(context.Background())
+	for i := 0; i < 3; i++ {
+		verdict, err := evaluator.Evaluate(ctx, "cla-group-1", identity)
+		if assert.NoError(t, err) {
+			assert.Equal(t, RuleExemption, verdict.Rule)
+		}
+	}
+	assert.Equal(t, 1, repo.loads)
+
+	// without the cache every evaluation loads the exemptions
+	_, err := evaluator.Evaluate(context.Background(), "cla-group-1", identity)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, repo.loads)
+}
@@ -0,0 +1,372 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// identity attributes the user record of the contributor was found by
+const (
+	ResolvedByEmail          = "email"
+	ResolvedByGitHubUserID   = "githubUserID"
+	ResolvedByGitHubUsername = "githubUsername"
+	ResolvedByLFUsername     = "lfUsername"
+)
+
+// coverage checks, in the order they are evaluated
+const (
+	CheckExemption               = "exemption"
+	CheckUserRecord              = "user-record"
+	CheckICLA                    = "icla-signature"
+	CheckCompanyAssociation      = "company-association"
+	CheckEmployeeAcknowledgement = "employee-acknowledgement"
+	CheckCorporateSignature      = "corporate-signature"
+	CheckApprovalList            = "approval-list"
+)
+
+// MaxNearMisses is the maximum number of near-misses of an explanation
+const MaxNearMisses = 10
+
+// Explanation is the detailed coverage evaluation of a contributor - the outcome of each check and, when the
+// contributor is not covered, the near-misses: the signatures which would cover the contributor but for one condition
+type Explanation struct {
+	Covered        bool
+	Rule           string
+	UserID         string
+	UserResolvedBy string
+	CompanyID      string
+	SignatureID    string
+	Reason         string
+	Checks         []*Check
+	NearMisses     []*NearMiss
+	// the exemption of the CLA Group covering the contributor, set for the exemption rule
+	ExemptionID      string
+	ExemptionType    string
+	ExemptionPattern string
+}
+
+// Check is the outcome of a single coverage check
+type Check struct {
+	Name        string
+	Passed      bool
+	Detail      string
+	CompanyID   string
+	SignatureID string
+}
+
+// NearMiss is a signature which almost covers the contributor
+type NearMiss struct {
+	Rule              string
+	CompanyID         string
+	CompanyName       string
+	SignatureID       string
+	ApprovalListType  string
+	ApprovalListEntry string
+	Reason            string
+}
+
+func (x *Explanation) addCheck(check *Check) {
+	x.Checks = append(x.Checks, check)
+	if !check.Passed && x.Reason == "" {
+		x.Reason = check.Detail
+	}
+}
+
+func (x *Explanation) addNearMiss(nearMiss *NearMiss) {
+	if len(x.NearMisses) < MaxNearMisses {
+		x.NearMisses = append(x.NearMisses, nearMiss)
+	}
+}
+
+// contributorIdentity is an identity matched against the approval lists for the near-misses, source describes the
+// identities taken from the user record, it is empty for the identity of the contribution
+type contributorIdentity struct {
+	identity *Identity
+	source   string
+}
+
+// approvalListMatch is the best approval list match of the contributor identities
+type approvalListMatch struct {
+	listType string
+	entry    string
+	source   string
+	expired  bool
+}
+
+// Explain evaluates every coverage path of the contributor. Evaluate stops at the first failed condition, Explain
+// reports each check and looks for the near-misses, including the corporate signatures of the other companies of the
+// CLA Group whose approval lists match the contributor. The checks follow the Evaluate path, so both agree on the
+// coverage - the other identities of the user record only show up as near-misses.
+func (e *evaluator) Explain(ctx context.Context, claGroupID string, identity *Identity) (*Explanation, error) {
+	f := logrus.Fields{
+		"functionName":   "coverage.Explain",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"email":          identity.Email,
+		"githubUsername": identity.GitHubUsername,
+		"lfUsername":     identity.LFUsername,
+	}
+	now := e.now()
+	explanation := &Explanation{}
+
+	exemption, err := e.matchExemption(ctx, claGroupID, identity)
+	if err != nil {
+		return nil, err
+	}
+	if exemption != nil {
+		explanation.addCheck(&Check{Name: CheckExemption, Passed: true, Detail: fmt.Sprintf("the contributor matches the %s exemption %s of the CLA Group", exemption.Type, exemption.Pattern)})
+		explanation.Covered = true
+		explanation.Rule = RuleExemption
+		explanation.ExemptionID = exemption.ExemptionID
+		explanation.ExemptionType = exemption.Type
+		explanation.ExemptionPattern = exemption.Pattern
+		return explanation, nil
+	}
+	// not being exempted isn't the reason, most contributors need a signature
+	explanation.Checks = append(explanation.Checks, &Check{Name: CheckExemption, Detail: "no exemption of the CLA Group matches the contributor"})
+
+	user, resolvedBy, err := e.lookupUser(identity)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem looking up the user record of the contributor")
+		return nil, err
+	}
+	if user == nil {
+		log.WithFields(f).Debug("no user record matches the contributor")
+		explanation.addCheck(&Check{Name: CheckUserRecord, Detail: ReasonUnknownUser})
+		// the approval lists still tell which company would cover the contributor once they have a user record
+		if err := e.explainOtherCompanies(ctx, claGroupID, nil, contributorIdentities(identity, nil), explanation, now); err != nil {
+			return nil, err
+		}
+		return explanation, nil
+	}
+	explanation.UserID = user.UserID
+	explanation.UserResolvedBy = resolvedBy
+	explanation.CompanyID = user.CompanyID
+	explanation.addCheck(&Check{Name: CheckUserRecord, Passed: true, Detail: fmt.Sprintf("the contributor is the user %s, found by %s", user.UserID, resolvedBy)})
+	identities := contributorIdentities(identity, user)
+
+	icla, err := e.signatureRepo.GetIndividualSignature(ctx, claGroupID, user.UserID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("problem loading the ICLA of user: %s", user.UserID)
+		return nil, err
+	}
+	if icla != nil {
+		explanation.addCheck(&Check{Name: CheckICLA, Passed: true, Detail: "the contributor has signed an ICLA", SignatureID: icla.SignatureID})
+		explanation.Covered = true
+		explanation.Rule = RuleICLA
+		explanation.SignatureID = icla.SignatureID
+		return explanation, nil
+	}
+	// a missing ICLA isn't the reason, the reason is the failed condition of the corporate CLA path
+	explanation.Checks = append(explanation.Checks, &Check{Name: CheckICLA, Detail: "the contributor has not signed an ICLA"})
+
+	if user.CompanyID == "" {
+		explanation.addCheck(&Check{Name: CheckCompanyAssociation, Detail: ReasonNoSignature})
+	} else {
+		explanation.addCheck(&Check{Name: CheckCompanyAssociation, Passed: true, Detail: "the contributor is associated with a company", CompanyID: user.CompanyID})
+		covered, companyErr := e.explainCompany(ctx, claGroupID, user, identity, identities, explanation, now)
+		if companyErr != nil {
+			return nil, companyErr
+		}
+		if covered {
+			return explanation, nil
+		}
+	}
+
+	if err := e.explainOtherCompanies(ctx, claGroupID, user, identities, explanation, now); err != nil {
+		return nil, err
+	}
+	return explanation, nil
+}
+
+// explainCompany checks the corporate signature of the company of the contributor, returns true if it covers them
+func (e *evaluator) explainCompany(ctx context.Context, claGroupID string, user *models.User, identity *Identity, identities []contributorIdentity, explanation *Explanation, now time.Time) (bool, error) {
+	f := logrus.Fields{
+		"functionName":   "coverage.explainCompany",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"userID":         user.UserID,
+		"companyID":      user.CompanyID,
+	}
+
+	employeeSignature, err := e.signatureRepo.GetEmployeeSignature(ctx, claGroupID, user.CompanyID, user.UserID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("problem loading the employee signature of user: %s", user.UserID)
+		return false, err
+	}
+	if employeeSignature == nil {
+		explanation.addCheck(&Check{Name: CheckEmployeeAcknowledgement, Detail: ReasonNoEmployeeSignature, CompanyID: user.CompanyID})
+	} else {
+		explanation.addCheck(&Check{Name: CheckEmployeeAcknowledgement, Passed: true, Detail: "the contributor has acknowledged the corporate CLA of the company", CompanyID: user.CompanyID, SignatureID: employeeSignature.SignatureID})
+	}
+
+	ccla, err := e.signatureRepo.GetCorporateSignature(ctx, claGroupID, user.CompanyID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("problem loading the corporate signature of company: %s", user.CompanyID)
+		return false, err
+	}
+	if ccla == nil {
+		explanation.addCheck(&Check{Name: CheckCorporateSignature, Detail: ReasonNoCorporateSignature, CompanyID: user.CompanyID})
+		return false, nil
+	}
+	explanation.SignatureID = ccla.SignatureID
+	explanation.addCheck(&Check{Name: CheckCorporateSignature, Passed: true, Detail: "the company has signed a corporate CLA", CompanyID: user.CompanyID, SignatureID: ccla.SignatureID})
+
+	// the approval list is matched exactly like Evaluate does, with the identity of the contribution
+	listType, entry, expired := MatchApprovalList(ccla, identity, now)
+	switch {
+	case listType == "":
+		explanation.addCheck(&Check{Name: CheckApprovalList, Detail: ReasonNotInApprovalList, CompanyID: user.CompanyID, SignatureID: ccla.SignatureID})
+		if match := matchContributor(ccla, identities[1:], now); match != nil {
+			reason := fmt.Sprintf("the %s approval list entry %s matches %s, not the identity of the contribution", match.listType, match.entry, match.source)
+			if match.expired {
+				reason += ", and the approval list entry has expired"
+			}
+			explanation.addNearMiss(&NearMiss{Rule: RuleCCLA, CompanyID: user.CompanyID, SignatureID: ccla.SignatureID, ApprovalListType: match.listType, ApprovalListEntry: match.entry, Reason: reason})
+		}
+		if len(ccla.GithubOrgApprovalList) > 0 && len(identity.GitHubOrganizations) == 0 {
+			explanation.addNearMiss(&NearMiss{
+				Rule:             RuleCCLA,
+				CompanyID:        user.CompanyID,
+				SignatureID:      ccla.SignatureID,
+				ApprovalListType: signatures.ApprovalListTypeGithubOrg,
+				Reason:           fmt.Sprintf("the corporate CLA approves the GitHub organizations %s, the organization membership of the contributor is not checked", strings.Join(ccla.GithubOrgApprovalList, ", ")),
+			})
+		}
+		return false, nil
+	case expired:
+		explanation.addCheck(&Check{Name: CheckApprovalList, Detail: ReasonApprovalListEntryGone, CompanyID: user.CompanyID, SignatureID: ccla.SignatureID})
+		explanation.addNearMiss(&NearMiss{Rule: RuleCCLA, CompanyID: user.CompanyID, SignatureID: ccla.SignatureID, ApprovalListType: listType, ApprovalListEntry: entry, Reason: ReasonApprovalListEntryGone})
+		return false, nil
+	}
+	explanation.addCheck(&Check{Name: CheckApprovalList, Passed: true, Detail: fmt.Sprintf("the contributor matches the %s approval list entry %s", listType, entry), CompanyID: user.CompanyID, SignatureID: ccla.SignatureID})
+
+	if employeeSignature == nil {
+		explanation.addNearMiss(&NearMiss{
+			Rule:              RuleCCLA,
+			CompanyID:         user.CompanyID,
+			SignatureID:       ccla.SignatureID,
+			ApprovalListType:  listType,
+			ApprovalListEntry: entry,
+			Reason:            "the contributor is in the approval list of the company but has not acknowledged its corporate CLA",
+		})
+		return false, nil
+	}
+
+	explanation.Covered = true
+	explanation.Rule = RuleCCLA
+	return true, nil
+}
+
+// explainOtherCompanies adds the near-misses of the corporate signatures of the CLA Group whose approval lists match
+// the contributor while the contributor isn't associated with the company
+func (e *evaluator) explainOtherCompanies(ctx context.Context, claGroupID string, user *models.User, identities []contributorIdentity, explanation *Explanation, now time.Time) error {
+	f := logrus.Fields{
+		"functionName":   "coverage.explainOtherCompanies",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	companies, err := e.signatureRepo.GetCompanyIDsWithSignedCorporateSignatures(ctx, claGroupID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the companies with a signed corporate CLA")
+		return err
+	}
+
+	for _, company := range companies {
+		if len(explanation.NearMisses) >= MaxNearMisses {
+			break
+		}
+		if user != nil && company.CompanyID == user.CompanyID {
+			continue
+		}
+		ccla, sigErr := e.signatureRepo.GetSignature(ctx, company.SignatureID)
+		if sigErr != nil || ccla == nil {
+			log.WithFields(f).WithError(sigErr).Warnf("unable to load the corporate signature: %s", company.SignatureID)
+			continue
+		}
+		match := matchContributor(ccla, identities, now)
+		if match == nil {
+			continue
+		}
+
+		var reason string
+		switch {
+		case user == nil:
+			reason = fmt.Sprintf("the contributor is in the approval list of %s but no EasyCLA user matches the contributor", company.CompanyName)
+		case user.CompanyID == "":
+			reason = fmt.Sprintf("the contributor is in the approval list of %s but is not associated with the company", company.CompanyName)
+		default:
+			reason = fmt.Sprintf("the contributor is in the approval list of %s but is associated with another company", company.CompanyName)
+		}
+		if match.source != "" {
+			reason += fmt.Sprintf(", through %s", match.source)
+		}
+		if match.expired {
+			reason += ", and the approval list entry has expired"
+		}
+
+		explanation.addNearMiss(&NearMiss{
+			Rule:              RuleCCLA,
+			CompanyID:         company.CompanyID,
+			CompanyName:       company.CompanyName,
+			SignatureID:       company.SignatureID,
+			ApprovalListType:  match.listType,
+			ApprovalListEntry: match.entry,
+			Reason:            reason,
+		})
+	}
+	return nil
+}
+
+// contributorIdentities returns the identities of the contributor matched against the approval lists for the
+// near-misses - the identity of the contribution first, then the emails and the GitHub username of the user record
+func contributorIdentities(identity *Identity, user *models.User) []contributorIdentity {
+	identities := []contributorIdentity{{identity: identity}}
+	if user == nil {
+		return identities
+	}
+
+	for _, email := range append([]string{user.LfEmail}, user.Emails...) {
+		if email != "" && !strings.EqualFold(strings.TrimSpace(email), strings.TrimSpace(identity.Email)) {
+			identities = append(identities, contributorIdentity{
+				identity: &Identity{Email: email},
+				source:   fmt.Sprintf("the email %s of the user record", email),
+			})
+		}
+	}
+	if identity.GitHubUsername == "" && user.GithubUsername != "" {
+		identities = append(identities, contributorIdentity{
+			identity: &Identity{GitHubUsername: user.GithubUsername},
+			source:   fmt.Sprintf("the GitHub username %s of the user record", user.GithubUsername),
+		})
+	}
+	return identities
+}
+
+// matchContributor returns the best approval list match of the contributor identities, preferring the current
+// matches and then the first identity, nil if no identity matches
+func matchContributor(sig *models.Signature, identities []contributorIdentity, now time.Time) *approvalListMatch {
+	var best *approvalListMatch
+	for _, candidate := range identities {
+		listType, entry, expired := MatchApprovalList(sig, candidate.identity, now)
+		if listType == "" {
+			continue
+		}
+		if best == nil || (best.expired && !expired) {
+			best = &approvalListMatch{listType: listType, entry: entry, source: candidate.source, expired: expired}
+		}
+	}
+	return best
+}
@@ -0,0 +1,167 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUserRepository finds the users by their emails, GitHub user ID, GitHub username and LF username
+type fakeUserRepository struct {
+	users.UserRepository
+	users []*models.User
+}
+
+func (r *fakeUserRepository) GetUserByEmail(userEmail string) (*models.User, error) {
+	for _, user := range r.users {
+		for _, email := range append([]string{user.LfEmail}, user.Emails...) {
+			if strings.EqualFold(email, userEmail) {
+				return user, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetUserByGitHubUsername(gitHubUsername string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.GithubUsername != "" && strings.EqualFold(user.GithubUsername, gitHubUsername) {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetUserByUserName(userName string, fullMatch bool) (*models.User, error) {
+	for _, user := range r.users {
+		if user.GithubID != "" && "github:"+user.GithubID == userName {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepository) GetUserByLFUserName(lfUserName string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.LfUsername != "" && user.LfUsername == lfUserName {
+			return user, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeSignatureRepository keeps the signatures of a single CLA Group
+type fakeSignatureRepository struct {
+	signatures.SignatureRepository
+	iclas     map[string]*models.Signature
+	employees map[string]*models.Signature
+	cclas     map[string]*models.Signature
+	companies []signatures.SignatureCompanyID
+}
+
+func (r *fakeSignatureRepository) GetIndividualSignature(ctx context.Context, claGroupID, userID string) (*models.Signature, error) {
+	return r.iclas[userID], nil
+}
+
+func (r *fakeSignatureRepository) GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error) {
+	return r.employees[userID], nil
+}
+
+func (r *fakeSignatureRepository) GetCorporateSignature(ctx context.Context, claGroupID, companyID string) (*models.Signature, error) {
+	return r.cclas[companyID], nil
+}
+
+func (r *fakeSignatureRepository) GetSignature(ctx context.Context, signatureID string) (*models.Signature, error) {
+	for _, ccla := range r.cclas {
+		if ccla.SignatureID == signatureID {
+			return ccla, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeSignatureRepository) GetCompanyIDsWithSignedCorporateSignatures(ctx context.Context, claGroupID string) ([]signatures.SignatureCompanyID, error) {
+	return r.companies, nil
+}
+
+func TestExplainAgreesWithEvaluate(t *testing.T) {
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+	usersRepo := &fakeUserRepository{users: []*models.User{
+		{UserID: "jane", LfEmail: "jane@icla.org"},
+		{UserID: "nobody", LfEmail: "nobody@example.net"},
+		{UserID: "joe", CompanyID: "acme", LfEmail: "joe@acme.com", Emails: []string{"joe@personal.org"}, GithubUsername: "joe-gh"},
+		{UserID: "ann", CompanyID: "acme", LfEmail: "ann@acme.com"},
+		{UserID: "bob", CompanyID: "no-ccla", LfEmail: "bob@no-ccla.com"},
+		{UserID: "carl", CompanyID: "acme", LfEmail: "carl@contractor.org"},
+		{UserID: "dan", CompanyID: "acme", LfEmail: "dan@other.org"},
+	}}
+	signatureRepo := &fakeSignatureRepository{
+		iclas: map[string]*models.Signature{"jane": {SignatureID: "icla-jane"}},
+		employees: map[string]*models.Signature{
+			"joe":  {SignatureID: "ecla-joe"},
+			"bob":  {SignatureID: "ecla-bob"},
+			"carl": {SignatureID: "ecla-carl"},
+			"dan":  {SignatureID: "ecla-dan"},
+		},
+		cclas: map[string]*models.Signature{"acme": {
+			SignatureID:                "ccla-acme",
+			DomainApprovalList:         []string{"acme.com"},
+			EmailApprovalList:          []string{"carl@contractor.org"},
+			GithubUsernameApprovalList: []string{"joe-gh"},
+			ApprovalListEntryDetails: []*models.ApprovalListEntryDetail{
+				{ListType: signatures.ApprovalListTypeEmail, Value: "carl@contractor.org", ExpiresOn: "2021-05-31T00:00:00Z"},
+			},
+		}},
+		companies: []signatures.SignatureCompanyID{{SignatureID: "ccla-acme", CompanyID: "acme", CompanyName: "Acme"}},
+	}
+	e := &evaluator{usersRepo: usersRepo, signatureRepo: signatureRepo, claGroupRepo: &countingCLAGroupRepository{}, now: func() time.Time { return now }}
+
+	testCases := []struct {
+		name       string
+		identity   *Identity
+		covered    bool
+		rule       string
+		nearMisses int
+	}{
+		{"exemption", &Identity{GitHubUsername: "dependabot[bot]"}, true, RuleExemption, 0},
+		{"unknown user in an approval list", &Identity{Email: "stranger@acme.com"}, false, "", 1},
+		{"icla", &Identity{Email: "jane@icla.org"}, true, RuleICLA, 0},
+		{"no company", &Identity{Email: "nobody@example.net"}, false, "", 0},
+		{"domain approval list", &Identity{Email: "joe@acme.com"}, true, RuleCCLA, 0},
+		{"email of the contribution not in the user record", &Identity{Email: "joe@acme.dev", GitHubUsername: "joe-gh"}, true, RuleCCLA, 0},
+		{"only another email of the user record is approved", &Identity{Email: "JOE@personal.org"}, false, "", 1},
+		{"employee acknowledgement missing", &Identity{Email: "ann@acme.com"}, false, "", 1},
+		{"corporate signature missing", &Identity{Email: "bob@no-ccla.com"}, false, "", 0},
+		{"approval list entry expired", &Identity{Email: "carl@contractor.org"}, false, "", 1},
+		{"not in the approval list", &Identity{Email: "dan@other.org"}, false, "", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			verdict, err := e.Evaluate(context.Background(), "cla-group-1", tc.identity)
+			if !assert.NoError(t, err) {
+				return
+			}
+			explanation, err := e.Explain(context.Background(), "cla-group-1", tc.identity)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tc.covered, verdict.Covered)
+			assert.Equal(t, verdict.Covered, explanation.Covered)
+			assert.Equal(t, tc.rule, verdict.Rule)
+			assert.Equal(t, verdict.Rule, explanation.Rule)
+			assert.Equal(t, verdict.Reason, explanation.Reason)
+			assert.Len(t, explanation.NearMisses, tc.nearMisses)
+		})
+	}
+}
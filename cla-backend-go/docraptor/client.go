@@ -5,6 +5,7 @@ package docraptor
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,8 @@ import (
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
 )
 
 var (
@@ -26,9 +29,10 @@ const (
 
 // Client structure model
 type Client struct {
-	apiKey   string
-	url      string
-	testMode bool
+	apiKey     string
+	url        string
+	testMode   bool
+	httpClient *http.Client
 }
 
 // NewDocraptorClient creates a new docraptor client instance
@@ -40,17 +44,19 @@ func NewDocraptorClient(key string, testMode bool) (Client, error) {
 	url := fmt.Sprintf(docraptorURL, key)
 
 	return Client{
-		apiKey:   key,
-		url:      url,
-		testMode: testMode,
+		apiKey:     key,
+		url:        url,
+		testMode:   testMode,
+		httpClient: telemetry.NewHTTPClient("docraptor", "createPDF"),
 	}, nil
 }
 
 // CreatePDF accepts an HTML document and returns a PDF
-func (dc Client) CreatePDF(html string, claType string) (io.ReadCloser, error) {
+func (dc Client) CreatePDF(ctx context.Context, html string, claType string) (io.ReadCloser, error) {
 	f := logrus.Fields{
-		"functionName": "CreatePDF",
-		"claType":      claType,
+		"functionName":   "CreatePDF",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claType":        claType,
 	}
 
 	document := map[string]interface{}{
@@ -67,7 +73,13 @@ func (dc Client) CreatePDF(html string, claType string) (io.ReadCloser, error) {
 	}
 
 	log.WithFields(f).Debug("Generating PDF using docraptor...")
-	resp, err := http.Post(dc.url, "application/json", bytes.NewBuffer(documentBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dc.url, bytes.NewBuffer(documentBytes))
+	if err != nil {
+		log.WithFields(f).Warnf("unable to create the docraptor request, error: %+v", err)
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := dc.httpClient.Do(req)
 	if err != nil {
 		log.WithFields(f).Warnf("problem with API call to docraptor, error: %+v", err)
 		return nil, err
@@ -5,6 +5,7 @@ package events
 
 import (
 	"fmt"
+	"strings"
 )
 
 // EventData returns event data string which is used for event logging and containsPII field
@@ -23,6 +24,52 @@ type RepositoryDisabledEventData struct {
 	RepositoryName string
 }
 
+// RepositoryRenamedEventData . . .
+type RepositoryRenamedEventData struct {
+	OldRepositoryName string
+	NewRepositoryName string
+}
+
+// RepositoryTransferredEventData . . .
+type RepositoryTransferredEventData struct {
+	OldRepositoryName string
+	NewRepositoryName string
+	Disabled          bool
+}
+
+// RepositoryArchivedEventData . . .
+type RepositoryArchivedEventData struct {
+	RepositoryName string
+}
+
+// RepositoryUnarchivedEventData . . .
+type RepositoryUnarchivedEventData struct {
+	RepositoryName string
+}
+
+// RepositoryEnforcementModeUpdatedEventData . . .
+type RepositoryEnforcementModeUpdatedEventData struct {
+	RepositoryName     string
+	OldEnforcementMode string
+	NewEnforcementMode string
+}
+
+// GithubWebhookRejectedEventData . . .
+type GithubWebhookRejectedEventData struct {
+	DeliveryID string
+	EventName  string
+	RemoteAddr string
+	Reason     string
+}
+
+// BranchProtectionDriftDetectedEventData . . .
+type BranchProtectionDriftDetectedEventData struct {
+	RepositoryName string
+	BranchName     string
+	Drift          []string
+	Reapplied      bool
+}
+
 // GerritProjectDeletedEventData . . .
 type GerritProjectDeletedEventData struct {
 	DeletedCount int
@@ -88,6 +135,34 @@ type CompanyACLUserAddedEventData struct {
 // CLATemplateCreatedEventData . . .
 type CLATemplateCreatedEventData struct{}
 
+// CLATemplateVersionCreatedEventData . . .
+type CLATemplateVersionCreatedEventData struct {
+	TemplateID      string
+	TemplateName    string
+	TemplateVersion int64
+}
+
+// CLATemplateDeletedEventData . . .
+type CLATemplateDeletedEventData struct {
+	TemplateID   string
+	TemplateName string
+}
+
+// ResignCampaignStartedEventData . . .
+type ResignCampaignStartedEventData struct {
+	CampaignID         string
+	ClaType            string
+	TargetMajorVersion int64
+	RecipientCount     int64
+}
+
+// ResignCampaignCompletedEventData . . .
+type ResignCampaignCompletedEventData struct {
+	CampaignID         string
+	ClaType            string
+	TargetMajorVersion int64
+}
+
 // GitHubOrganizationAddedEventData . . .
 type GitHubOrganizationAddedEventData struct {
 	GitHubOrganizationName  string
@@ -108,6 +183,32 @@ type GitHubOrganizationUpdatedEventData struct {
 	AutoEnabledClaGroupID  string
 }
 
+// GitHubOrganizationRenamedEventData . . .
+type GitHubOrganizationRenamedEventData struct {
+	OldGitHubOrganizationName string
+	NewGitHubOrganizationName string
+	RepositoryCount           int
+}
+
+// GitHubOrganizationInstallationDeletedEventData . . .
+type GitHubOrganizationInstallationDeletedEventData struct {
+	GitHubOrganizationName string
+	InstallationID         int64
+	DisabledCount          int
+}
+
+// GitHubOrganizationInstallationSuspendedEventData . . .
+type GitHubOrganizationInstallationSuspendedEventData struct {
+	GitHubOrganizationName string
+	InstallationID         int64
+}
+
+// GitHubOrganizationInstallationUnsuspendedEventData . . .
+type GitHubOrganizationInstallationUnsuspendedEventData struct {
+	GitHubOrganizationName string
+	InstallationID         int64
+}
+
 // CCLAApprovalListRequestCreatedEventData . . .
 type CCLAApprovalListRequestCreatedEventData struct {
 	RequestID string
@@ -232,6 +333,22 @@ type CLAApprovalListRemoveGitHubUsernameData struct {
 	ApprovalListGitHubUsername string
 }
 
+// CLAApprovalListAddGitLabUsernameData . . .
+type CLAApprovalListAddGitLabUsernameData struct {
+	UserName                   string
+	UserEmail                  string
+	UserLFID                   string
+	ApprovalListGitLabUsername string
+}
+
+// CLAApprovalListRemoveGitLabUsernameData . . .
+type CLAApprovalListRemoveGitLabUsernameData struct {
+	UserName                   string
+	UserEmail                  string
+	UserLFID                   string
+	ApprovalListGitLabUsername string
+}
+
 // CLAApprovalListAddGitHubOrgData . . .
 type CLAApprovalListAddGitHubOrgData struct {
 	UserName              string
@@ -248,6 +365,14 @@ type CLAApprovalListRemoveGitHubOrgData struct {
 	ApprovalListGitHubOrg string
 }
 
+// CLAApprovalListEntryExpiredData . . .
+type CLAApprovalListEntryExpiredData struct {
+	ApprovalListType  string
+	ApprovalListValue string
+	ExpiresOn         string
+	AddedBy           string
+}
+
 // ApprovalListGitHubOrganizationAddedEventData . . .
 type ApprovalListGitHubOrganizationAddedEventData struct {
 	GitHubOrganizationName string
@@ -281,6 +406,20 @@ type CLAGroupUpdatedEventData struct {
 // CLAGroupDeletedEventData . . .
 type CLAGroupDeletedEventData struct{}
 
+// CLAGroupExemptionAddedEventData . . .
+type CLAGroupExemptionAddedEventData struct {
+	ExemptionID   string
+	ExemptionType string
+	Pattern       string
+}
+
+// CLAGroupExemptionDeletedEventData . . .
+type CLAGroupExemptionDeletedEventData struct {
+	ExemptionID   string
+	ExemptionType string
+	Pattern       string
+}
+
 // ContributorNotifyCompanyAdminData . . .
 type ContributorNotifyCompanyAdminData struct {
 	AdminName  string
@@ -336,6 +475,84 @@ func (ed *RepositoryDisabledEventData) GetEventDetailsString(args *LogEventArgs)
 	return data, true
 }
 
+// GetEventDetailsString . . .
+func (ed *RepositoryRenamedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The GitHub repository: %s was renamed to: %s for the project %s by the user %s.", ed.OldRepositoryName, ed.NewRepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *RepositoryTransferredEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The GitHub repository: %s was transferred to: %s for the project %s by the user %s.", ed.OldRepositoryName, ed.NewRepositoryName, args.projectName, args.userName)
+	if ed.Disabled {
+		data = data + " The repository was disabled until it is enabled for a CLA Group of the new organization."
+	}
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *RepositoryArchivedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The GitHub repository: %s was archived and disabled for the project %s by the user %s.", ed.RepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *RepositoryUnarchivedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The GitHub repository: %s was unarchived and enabled for the project %s by the user %s.", ed.RepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *GitHubOrganizationRenamedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub Organization: %s was renamed to: %s, %d repositories were updated by: %s.",
+		ed.OldGitHubOrganizationName, ed.NewGitHubOrganizationName, ed.RepositoryCount, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *GitHubOrganizationInstallationDeletedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The EasyCLA GitHub application installation: %d was removed from GitHub Organization: %s, %d repositories were disabled by: %s.",
+		ed.InstallationID, ed.GitHubOrganizationName, ed.DisabledCount, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *GitHubOrganizationInstallationSuspendedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The EasyCLA GitHub application installation: %d was suspended for GitHub Organization: %s by: %s.",
+		ed.InstallationID, ed.GitHubOrganizationName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *GitHubOrganizationInstallationUnsuspendedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The EasyCLA GitHub application installation: %d was unsuspended for GitHub Organization: %s by: %s.",
+		ed.InstallationID, ed.GitHubOrganizationName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *GithubWebhookRejectedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The GitHub webhook delivery: %s for event: %s from: %s was rejected, reason: %s.",
+		ed.DeliveryID, ed.EventName, ed.RemoteAddr, ed.Reason)
+	return data, false
+}
+
+// GetEventDetailsString . . .
+func (ed *RepositoryEnforcementModeUpdatedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The enforcement mode of the GitHub repository: %s was changed from: %s to: %s for the project %s by the user %s.", ed.RepositoryName, ed.OldEnforcementMode, ed.NewEnforcementMode, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *BranchProtectionDriftDetectedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The branch protection of branch: %s of GitHub repository: %s for project: %s no longer matches the EasyCLA settings: %s.",
+		ed.BranchName, ed.RepositoryName, args.projectName, strings.Join(ed.Drift, ", "))
+	if ed.Reapplied {
+		data = data + " The branch protection was re-applied."
+	}
+	return data, false
+}
+
 // GetEventDetailsString . . .
 func (ed *UserCreatedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("User: %s added. User Details: %+v.", args.userName, args.UserModel)
@@ -387,6 +604,33 @@ func (ed *CLATemplateCreatedEventData) GetEventDetailsString(args *LogEventArgs)
 	return data, true
 }
 
+// GetEventDetailsString . . .
+func (ed *CLATemplateVersionCreatedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("Version %d of the CLA template: %s (%s) was created by: %s.",
+		ed.TemplateVersion, ed.TemplateName, ed.TemplateID, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *CLATemplateDeletedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The CLA template: %s (%s) was deleted by: %s.", ed.TemplateName, ed.TemplateID, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *ResignCampaignStartedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s re-sign campaign: %s asking %d recipients to sign version %d of the CLA Group: %s was started by: %s.",
+		ed.ClaType, ed.CampaignID, ed.RecipientCount, ed.TargetMajorVersion, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *ResignCampaignCompletedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s re-sign campaign: %s was completed, all the recipients signed version %d of the CLA Group: %s.",
+		ed.ClaType, ed.CampaignID, ed.TargetMajorVersion, args.projectName)
+	return data, true
+}
+
 // GetEventDetailsString . . .
 func (ed *GitHubOrganizationAddedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("GitHub Organization: %s was added with auto-enabled: %t, with branch protection enabled: %t",
@@ -514,6 +758,20 @@ func (ed *CLAApprovalListRemoveGitHubUsernameData) GetEventDetailsString(args *L
 	return data, true
 }
 
+// GetEventDetailsString . . .
+func (ed *CLAApprovalListAddGitLabUsernameData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager: %s, Email: %s, LFID: %s added GitLab Username: %s to the approval list for Company: %s, Project: %s.",
+		ed.UserName, ed.UserEmail, ed.UserLFID, ed.ApprovalListGitLabUsername, args.companyName, args.projectName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *CLAApprovalListRemoveGitLabUsernameData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager: %s, Email: %s, LFID: %s removed GitLab Username: %s from the approval list for Company: %s, Project: %s.",
+		ed.UserName, ed.UserEmail, ed.UserLFID, ed.ApprovalListGitLabUsername, args.companyName, args.projectName)
+	return data, true
+}
+
 // GetEventDetailsString . . .
 func (ed *CLAApprovalListAddGitHubOrgData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("CLA Manager: %s, Email: %s, LFID: %s added GitHub Organization: %s to the approval list for Company: %s, Project: %s.",
@@ -528,6 +786,13 @@ func (ed *CLAApprovalListRemoveGitHubOrgData) GetEventDetailsString(args *LogEve
 	return data, true
 }
 
+// GetEventDetailsString . . .
+func (ed *CLAApprovalListEntryExpiredData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("Approval list %s entry: %s added by CLA Manager: %s expired on %s and was removed from the approval list for Company: %s, Project: %s.",
+		ed.ApprovalListType, ed.ApprovalListValue, ed.AddedBy, ed.ExpiresOn, args.companyName, args.projectName)
+	return data, true
+}
+
 // GetEventDetailsString . . .
 func (ed *CCLAApprovalListRequestCreatedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("User: %s created a CCLA Approval Request for Project: %s, Company: %s with Request ID: %s.",
@@ -584,6 +849,20 @@ func (ed *CLAGroupDeletedEventData) GetEventDetailsString(args *LogEventArgs) (s
 	return data, true
 }
 
+// GetEventDetailsString . . .
+func (ed *CLAGroupExemptionAddedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s exemption: %s (ID: %s) was added to CLA Group ID: %s by: %s.",
+		ed.ExemptionType, ed.Pattern, ed.ExemptionID, args.ProjectID, args.userName)
+	return data, true
+}
+
+// GetEventDetailsString . . .
+func (ed *CLAGroupExemptionDeletedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s exemption: %s (ID: %s) was removed from CLA Group ID: %s by: %s.",
+		ed.ExemptionType, ed.Pattern, ed.ExemptionID, args.ProjectID, args.userName)
+	return data, true
+}
+
 // GetEventDetailsString . . .
 func (ed *GerritProjectDeletedEventData) GetEventDetailsString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("%d Gerrit Repositories were deleted due to CLA Group/Project: %s deletion.",
@@ -683,6 +962,76 @@ func (ed *RepositoryDisabledEventData) GetEventSummaryString(args *LogEventArgs)
 	return data, true
 }
 
+// GetEventSummaryString . . .
+func (ed *RepositoryRenamedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub Repository: %s was renamed to: %s for Project: %s by: %s.", ed.OldRepositoryName, ed.NewRepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *RepositoryTransferredEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub Repository: %s was transferred to: %s for Project: %s by: %s.", ed.OldRepositoryName, ed.NewRepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *RepositoryArchivedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub Repository: %s was archived for Project: %s by: %s.", ed.RepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *RepositoryUnarchivedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub Repository: %s was unarchived for Project: %s by: %s.", ed.RepositoryName, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *GitHubOrganizationRenamedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub Organization: %s was renamed to: %s by: %s.", ed.OldGitHubOrganizationName, ed.NewGitHubOrganizationName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *GitHubOrganizationInstallationDeletedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The EasyCLA GitHub application was removed from GitHub Organization: %s by: %s.", ed.GitHubOrganizationName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *GitHubOrganizationInstallationSuspendedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The EasyCLA GitHub application was suspended for GitHub Organization: %s by: %s.", ed.GitHubOrganizationName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *GitHubOrganizationInstallationUnsuspendedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The EasyCLA GitHub application was unsuspended for GitHub Organization: %s by: %s.", ed.GitHubOrganizationName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *GithubWebhookRejectedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("GitHub webhook delivery: %s for event: %s was rejected.", ed.DeliveryID, ed.EventName)
+	return data, false
+}
+
+// GetEventSummaryString . . .
+func (ed *RepositoryEnforcementModeUpdatedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The enforcement mode of GitHub Repository: %s was changed to: %s for Project: %s by: %s.", ed.RepositoryName, ed.NewEnforcementMode, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *BranchProtectionDriftDetectedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The branch protection of GitHub repository: %s for project: %s no longer matches the EasyCLA settings.",
+		ed.RepositoryName, args.projectName)
+	if ed.Reapplied {
+		data = data + " The branch protection was re-applied."
+	}
+	return data, false
+}
+
 // GetEventSummaryString . . .
 func (ed *UserCreatedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("User: %s was added, User Details: %+v.", args.userName, args.UserModel)
@@ -734,6 +1083,32 @@ func (ed *CLATemplateCreatedEventData) GetEventSummaryString(args *LogEventArgs)
 	return data, true
 }
 
+// GetEventSummaryString . . .
+func (ed *CLATemplateVersionCreatedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("Version %d of the CLA template %s was created by: %s.", ed.TemplateVersion, ed.TemplateName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *CLATemplateDeletedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The CLA template %s was deleted by: %s.", ed.TemplateName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *ResignCampaignStartedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s re-sign campaign for version %d of the CLA Group %s was started by: %s.",
+		ed.ClaType, ed.TargetMajorVersion, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *ResignCampaignCompletedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s re-sign campaign for version %d of the CLA Group %s was completed.",
+		ed.ClaType, ed.TargetMajorVersion, args.projectName)
+	return data, true
+}
+
 // GetEventSummaryString . . .
 func (ed *GitHubOrganizationAddedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("GitHub Organization: %s was added with auto-enabled: %t, branch protection enabled: %t",
@@ -861,6 +1236,20 @@ func (ed *CLAApprovalListRemoveGitHubUsernameData) GetEventSummaryString(args *L
 	return data, true
 }
 
+// GetEventSummaryString . . .
+func (ed *CLAApprovalListAddGitLabUsernameData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager: %s added GitLab Username: %s to the approval list for Company: %s, Project: %s.",
+		ed.UserName, ed.ApprovalListGitLabUsername, args.companyName, args.projectName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *CLAApprovalListRemoveGitLabUsernameData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("CLA Manager: %s removed GitLab Username: %s from the approval list for Company: %s, Project: %s.",
+		ed.UserName, ed.ApprovalListGitLabUsername, args.companyName, args.projectName)
+	return data, true
+}
+
 // GetEventSummaryString . . .
 func (ed *CLAApprovalListAddGitHubOrgData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("CLA Manager: %s added GitHub Organization: %s to the approval list for Company: %s, Project: %s.",
@@ -875,6 +1264,13 @@ func (ed *CLAApprovalListRemoveGitHubOrgData) GetEventSummaryString(args *LogEve
 	return data, true
 }
 
+// GetEventSummaryString . . .
+func (ed *CLAApprovalListEntryExpiredData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("Approval list %s entry: %s expired and was removed from the approval list for Company: %s, Project: %s.",
+		ed.ApprovalListType, ed.ApprovalListValue, args.companyName, args.projectName)
+	return data, true
+}
+
 // GetEventSummaryString . . .
 func (ed *CCLAApprovalListRequestCreatedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("User: %s created a CCLA Approval Request for Project: %s, Company: %s.",
@@ -930,6 +1326,20 @@ func (ed *CLAGroupDeletedEventData) GetEventSummaryString(args *LogEventArgs) (s
 	return data, true
 }
 
+// GetEventSummaryString . . .
+func (ed *CLAGroupExemptionAddedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s exemption %s was added to the CLA Group %s by the user %s.",
+		ed.ExemptionType, ed.Pattern, args.projectName, args.userName)
+	return data, true
+}
+
+// GetEventSummaryString . . .
+func (ed *CLAGroupExemptionDeletedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
+	data := fmt.Sprintf("The %s exemption %s was removed from the CLA Group %s by the user %s.",
+		ed.ExemptionType, ed.Pattern, args.projectName, args.userName)
+	return data, true
+}
+
 // GetEventSummaryString . . .
 func (ed *GerritProjectDeletedEventData) GetEventSummaryString(args *LogEventArgs) (string, bool) {
 	data := fmt.Sprintf("%d Gerrit repositories were deleted due to CLA Group/Project %s deletion.",
@@ -36,8 +36,24 @@ const (
 	UserUpdated        = "user.updated"
 	UserDeleted        = "user.deleted"
 
-	RepositoryAdded    = "repository.added"
-	RepositoryDisabled = "repository.disabled"
+	RepositoryAdded       = "repository.added"
+	RepositoryDisabled    = "repository.disabled"
+	RepositoryRenamed     = "repository.renamed"
+	RepositoryTransferred = "repository.transferred"
+	RepositoryArchived    = "repository.archived"
+	RepositoryUnarchived  = "repository.unarchived"
+
+	RepositoryEnforcementModeUpdated = "repository.enforcement_mode_updated"
+
+	GithubWebhookRejected = "github_webhook.rejected"
+
+	BranchProtectionDriftDetected = "branch_protection.drift_detected"
+
+	CLATemplateVersionCreated = "cla_template.version_created"
+	CLATemplateDeleted        = "cla_template.deleted"
+
+	ResignCampaignStarted   = "resign_campaign.started"
+	ResignCampaignCompleted = "resign_campaign.completed"
 
 	GerritRepositoryAdded   = "gerrit_repository.added"
 	GerritRepositoryDeleted = "gerrit_repository.deleted"
@@ -45,6 +61,11 @@ const (
 	GithubOrganizationAdded   = "github_organization.added"
 	GithubOrganizationDeleted = "github_organization.deleted"
 	GithubOrganizationUpdated = "github_organization.updated"
+	GithubOrganizationRenamed = "github_organization.renamed"
+
+	GithubOrganizationInstallationDeleted     = "github_organization.installation_deleted"
+	GithubOrganizationInstallationSuspended   = "github_organization.installation_suspended"
+	GithubOrganizationInstallationUnsuspended = "github_organization.installation_unsuspended"
 
 	CompanyACLUserAdded       = "company_acl.user_added"
 	CompanyACLRequestAdded    = "company_acl.request_added"
@@ -74,6 +95,9 @@ const (
 	CLAGroupUpdated = "cla_group.updated"
 	CLAGroupDeleted = "cla_group.deleted"
 
+	CLAGroupExemptionAdded   = "cla_group.exemption_added"
+	CLAGroupExemptionDeleted = "cla_group.exemption_deleted"
+
 	InvalidatedSignature = "signature.invalidated"
 
 	ContributorNotifyCompanyAdminType = "contributor.notify_company_admin"
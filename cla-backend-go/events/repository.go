@@ -24,8 +24,8 @@ import (
 
 	"github.com/gofrs/uuid"
 
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	eventOps "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/events"
@@ -69,14 +69,14 @@ type Repository interface {
 // repository data model
 type repository struct {
 	stage          string
-	dynamoDBClient *dynamodb.DynamoDB
+	dynamoDBClient storage.Driver
 }
 
 // NewRepository creates a new instance of the event repository
-func NewRepository(awsSession *session.Session, stage string) Repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
 	return &repository{
 		stage:          stage,
-		dynamoDBClient: dynamodb.New(awsSession),
+		dynamoDBClient: dynamoDBClient,
 	}
 }
 
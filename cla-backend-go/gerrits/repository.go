@@ -20,11 +20,10 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // errors
@@ -44,17 +43,17 @@ type Repository interface {
 }
 
 // NewRepository create new Repository
-func NewRepository(awsSession *session.Session, stage string) Repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
 	return &repo{
 		stage:          stage,
-		dynamoDBClient: dynamodb.New(awsSession),
+		dynamoDBClient: dynamoDBClient,
 		tableName:      fmt.Sprintf("cla-%s-gerrit-instances", stage),
 	}
 }
 
 type repo struct {
 	stage          string
-	dynamoDBClient *dynamodb.DynamoDB
+	dynamoDBClient storage.Driver
 	tableName      string
 }
 
@@ -0,0 +1,102 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// Fixture is a recorded GitHub API interaction, the response is replayed for the requests with the same method and path
+type Fixture struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body"`
+}
+
+// RecordedRequest is a request received by the fixture server
+type RecordedRequest struct {
+	Method string
+	Path   string
+	Body   []byte
+}
+
+// FixtureServer is a local stand-in for the GitHub API replaying recorded fixtures - used by the tests of the
+// GitHub integrations. The requests without a fixture get a 404 response.
+type FixtureServer struct {
+	*httptest.Server
+	lock     sync.Mutex
+	fixtures []*Fixture
+	requests []*RecordedRequest
+}
+
+// NewFixtureServer starts a fixture server replaying the fixtures of the JSON file, a list of fixtures
+func NewFixtureServer(fixtureFile string) (*FixtureServer, error) {
+	data, err := ioutil.ReadFile(fixtureFile) // nolint - the fixture files are part of the tests
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, err
+	}
+
+	s := &FixtureServer{fixtures: fixtures}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s, nil
+}
+
+func (s *FixtureServer) serve(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body) // nolint
+	s.lock.Lock()
+	s.requests = append(s.requests, &RecordedRequest{Method: r.Method, Path: r.URL.Path, Body: body})
+	var fixture *Fixture
+	for _, candidate := range s.fixtures {
+		if candidate.Method == r.Method && candidate.Path == r.URL.Path {
+			fixture = candidate
+			break
+		}
+	}
+	s.lock.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if fixture == nil {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`)) // nolint
+		return
+	}
+	status := fixture.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	w.Write(fixture.Body) // nolint
+}
+
+// GitHubClient returns a GitHub client sending its requests to the fixture server
+func (s *FixtureServer) GitHubClient() *github.Client {
+	client := github.NewClient(s.Server.Client())
+	client.BaseURL, _ = url.Parse(s.URL + "/") // nolint - the URL of the test server is valid
+	return client
+}
+
+// Requests returns the requests received with the method and path, e.g. to check the payload of the created resources
+func (s *FixtureServer) Requests(method, path string) []*RecordedRequest {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var requests []*RecordedRequest
+	for _, request := range s.requests {
+		if request.Method == method && request.Path == path {
+			requests = append(requests, request)
+		}
+	}
+	return requests
+}
@@ -450,6 +450,38 @@ func mergeStatusChecks(currentCheck *githubpkg.RequiredStatusChecks, enableConte
 	return currentCheck
 }
 
+// BranchProtectionDrift returns the differences between the branch protection and the protection EasyCLA expects,
+// none when the branch requires the status checks and, if expected, enforces the protection for administrators
+func BranchProtectionDrift(protection *githubpkg.Protection, requiredChecks []string, enforceAdmin bool) []string {
+	if protection == nil {
+		return []string{"the branch is not protected"}
+	}
+
+	var drift []string
+	var currentChecks []string
+	if protection.RequiredStatusChecks != nil {
+		currentChecks = protection.RequiredStatusChecks.Contexts
+	}
+	for _, check := range requiredChecks {
+		found := false
+		for _, c := range currentChecks {
+			if c == check {
+				found = true
+				break
+			}
+		}
+		if !found {
+			drift = append(drift, fmt.Sprintf("the %s status check is not required", check))
+		}
+	}
+
+	if enforceAdmin && !IsEnforceAdminEnabled(protection) {
+		drift = append(drift, "the protection is not enforced for administrators")
+	}
+
+	return drift
+}
+
 //IsEnforceAdminEnabled checks if enforce admin option is enabled for the branch protection
 func IsEnforceAdminEnabled(protection *githubpkg.Protection) bool {
 	if protection.EnforceAdmins == nil {
@@ -207,6 +207,55 @@ func TestEnableBranchProtection(t *testing.T) {
 
 }
 
+// TestBranchProtectionDrift tests the detection of the differences with the expected branch protection
+func TestBranchProtectionDrift(t *testing.T) {
+	testCases := []struct {
+		Name          string
+		protection    *githubsdk.Protection
+		enforceAdmin  bool
+		expectedDrift []string
+	}{
+		{
+			Name:          "not protected",
+			enforceAdmin:  true,
+			expectedDrift: []string{"the branch is not protected"},
+		},
+		{
+			Name: "no drift",
+			protection: &githubsdk.Protection{
+				RequiredStatusChecks: &githubsdk.RequiredStatusChecks{Contexts: []string{"travis-ci", "EasyCLA"}},
+				EnforceAdmins:        &githubsdk.AdminEnforcement{Enabled: true},
+			},
+			enforceAdmin: true,
+		},
+		{
+			Name: "status check removed and admin enforcement disabled",
+			protection: &githubsdk.Protection{
+				RequiredStatusChecks: &githubsdk.RequiredStatusChecks{Contexts: []string{"travis-ci"}},
+				EnforceAdmins:        &githubsdk.AdminEnforcement{Enabled: false},
+			},
+			enforceAdmin: true,
+			expectedDrift: []string{
+				"the EasyCLA status check is not required",
+				"the protection is not enforced for administrators",
+			},
+		},
+		{
+			Name:          "admin enforcement not expected",
+			protection:    &githubsdk.Protection{RequiredStatusChecks: &githubsdk.RequiredStatusChecks{Contexts: []string{"EasyCLA"}}},
+			enforceAdmin:  false,
+			expectedDrift: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(tt *testing.T) {
+			drift := BranchProtectionDrift(tc.protection, []string{"EasyCLA"}, tc.enforceAdmin)
+			assert.Equal(tt, tc.expectedDrift, drift)
+		})
+	}
+}
+
 func TestNonBlockingRateLimitRepositories_GetBranchProtection(t *testing.T) {
 	owner := "johnblocking"
 	repo := "johnsrepoblocking"
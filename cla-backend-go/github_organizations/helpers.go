@@ -5,11 +5,13 @@ package github_organizations
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
-	"github.com/communitybridge/easycla/cla-backend-go/github"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/strfmt"
 	"github.com/sirupsen/logrus"
@@ -32,42 +34,50 @@ func buildGithubOrganizationListModels(ctx context.Context, githubOrganizations
 			go func(ghorg *models.GithubOrganization) {
 				defer wg.Done()
 				ghorg.GithubInfo = &models.GithubOrganizationGithubInfo{}
-				log.WithFields(f).Debugf("Loading GitHub organization details: %s...", ghorg.OrganizationName)
-				user, err := github.GetUserDetails(ghorg.OrganizationName)
+				ghorg.Repositories = &models.GithubOrganizationRepositories{
+					List: make([]*models.GithubRepositoryInfo, 0),
+				}
+
+				orgType := repositories.OrganizationType(ghorg)
+				provider, err := repositories.GetProvider(orgType)
+				if err != nil {
+					log.WithFields(f).WithError(err).Warnf("unable to load the provider of organization: %s", ghorg.OrganizationName)
+					ghorg.GithubInfo.Error = err.Error()
+					ghorg.Repositories.Error = err.Error()
+					return
+				}
+
+				log.WithFields(f).Debugf("Loading %s organization details: %s...", orgType, ghorg.OrganizationName)
+				org, err := provider.GetOrganization(ctx, ghorg.OrganizationName)
 				if err != nil {
 					ghorg.GithubInfo.Error = err.Error()
 				} else {
-					url := strfmt.URI(*user.HTMLURL)
+					url := strfmt.URI(org.HTMLURL)
 					ghorg.GithubInfo.Details = &models.GithubOrganizationGithubInfoDetails{
-						Bio:     user.Bio,
 						HTMLURL: &url,
-						ID:      user.ID,
+						ID:      &org.ID,
+					}
+					if org.Description != "" {
+						ghorg.GithubInfo.Details.Bio = &org.Description
 					}
 				}
 
-				ghorg.Repositories = &models.GithubOrganizationRepositories{
-					List: make([]*models.GithubRepositoryInfo, 0),
+				log.WithFields(f).Debugf("Loading %s repository list of organization: %s...", orgType, ghorg.OrganizationName)
+				list, err := provider.ListRepositories(ctx, ghorg)
+				if err != nil {
+					log.WithFields(f).WithError(err).Warnf("unable to get repositories of organization: %s", ghorg.OrganizationName)
+					ghorg.Repositories.Error = err.Error()
+					return
 				}
 
-				if ghorg.OrganizationInstallationID != 0 {
-					log.WithFields(f).Debugf("Loading GitHub repository list based on installation id: %d...", ghorg.OrganizationInstallationID)
-					list, err := github.GetInstallationRepositories(ctx, ghorg.OrganizationInstallationID)
-					if err != nil {
-						log.WithFields(f).Warnf("unable to get repositories for installation id : %d", ghorg.OrganizationInstallationID)
-						ghorg.Repositories.Error = err.Error()
-						return
-					}
-
-					log.WithFields(f).Debugf("Found %d GitHub repositories using installation id: %d...",
-						len(list), ghorg.OrganizationInstallationID)
-					for _, repoInfo := range list {
-						ghorg.Repositories.List = append(ghorg.Repositories.List, &models.GithubRepositoryInfo{
-							RepositoryGithubID: utils.Int64Value(repoInfo.ID),
-							RepositoryName:     utils.StringValue(repoInfo.FullName),
-							RepositoryURL:      utils.StringValue(repoInfo.URL),
-							RepositoryType:     "github",
-						})
-					}
+				log.WithFields(f).Debugf("Found %d %s repositories of organization: %s...", len(list), orgType, ghorg.OrganizationName)
+				for _, repoInfo := range list {
+					ghorg.Repositories.List = append(ghorg.Repositories.List, &models.GithubRepositoryInfo{
+						RepositoryGithubID: repoInfo.ExternalID,
+						RepositoryName:     repoInfo.FullName,
+						RepositoryURL:      repoInfo.HTMLURL,
+						RepositoryType:     orgType,
+					})
 				}
 			}(ghorganization)
 		}
@@ -75,3 +85,43 @@ func buildGithubOrganizationListModels(ctx context.Context, githubOrganizations
 	}
 	return ghOrgList
 }
+
+// ValidateOrganization checks the organization can be added with the repository type - GitHub organizations are
+// verified when the EasyCLA GitHub application is installed, the organizations of the other providers must exist
+// and be visible to EasyCLA now
+func ValidateOrganization(ctx context.Context, organizationName string, orgType string) error {
+	f := logrus.Fields{
+		"functionName":     "ValidateOrganization",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"organizationName": organizationName,
+		"organizationType": orgType,
+	}
+
+	provider, err := repositories.GetProvider(orgType)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the organization provider")
+		return err
+	}
+	if provider.Type() == utils.GitHubType {
+		// sub-groups are a GitLab feature
+		if strings.Contains(organizationName, "/") {
+			return fmt.Errorf("invalid github organization name: %s", organizationName)
+		}
+		return nil
+	}
+
+	if _, err := provider.GetOrganization(ctx, organizationName); err != nil {
+		log.WithFields(f).WithError(err).Warnf("unable to load the %s organization", provider.Type())
+		return err
+	}
+	return nil
+}
+
+// organizationType returns the repository type of the organization to create, organizations are GitHub organizations
+// unless specified otherwise
+func organizationType(input *models.CreateGithubOrganization) string {
+	if input.OrganizationType == "" {
+		return utils.GitHubType
+	}
+	return strings.ToLower(input.OrganizationType)
+}
@@ -127,6 +127,64 @@ func (mr *MockRepositoryMockRecorder) UpdateGithubOrganization(ctx, projectSFID,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGithubOrganization", reflect.TypeOf((*MockRepository)(nil).UpdateGithubOrganization), ctx, projectSFID, organizationName, autoEnabled, branchProtectionEnabled)
 }
 
+// GetGithubOrganizationByInstallationID mocks base method
+func (m *MockRepository) GetGithubOrganizationByInstallationID(ctx context.Context, installationID int64) (*models.GithubOrganization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGithubOrganizationByInstallationID", ctx, installationID)
+	ret0, _ := ret[0].(*models.GithubOrganization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGithubOrganizationByInstallationID indicates an expected call of GetGithubOrganizationByInstallationID
+func (mr *MockRepositoryMockRecorder) GetGithubOrganizationByInstallationID(ctx, installationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGithubOrganizationByInstallationID", reflect.TypeOf((*MockRepository)(nil).GetGithubOrganizationByInstallationID), ctx, installationID)
+}
+
+// GetBranchProtectionEnabledGithubOrganizations mocks base method
+func (m *MockRepository) GetBranchProtectionEnabledGithubOrganizations(ctx context.Context) ([]*models.GithubOrganization, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtectionEnabledGithubOrganizations", ctx)
+	ret0, _ := ret[0].([]*models.GithubOrganization)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchProtectionEnabledGithubOrganizations indicates an expected call of GetBranchProtectionEnabledGithubOrganizations
+func (mr *MockRepositoryMockRecorder) GetBranchProtectionEnabledGithubOrganizations(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionEnabledGithubOrganizations", reflect.TypeOf((*MockRepository)(nil).GetBranchProtectionEnabledGithubOrganizations), ctx)
+}
+
+// UpdateGithubOrganizationInstallationID mocks base method
+func (m *MockRepository) UpdateGithubOrganizationInstallationID(ctx context.Context, organizationName string, installationID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateGithubOrganizationInstallationID", ctx, organizationName, installationID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateGithubOrganizationInstallationID indicates an expected call of UpdateGithubOrganizationInstallationID
+func (mr *MockRepositoryMockRecorder) UpdateGithubOrganizationInstallationID(ctx, organizationName, installationID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateGithubOrganizationInstallationID", reflect.TypeOf((*MockRepository)(nil).UpdateGithubOrganizationInstallationID), ctx, organizationName, installationID)
+}
+
+// RenameGithubOrganization mocks base method
+func (m *MockRepository) RenameGithubOrganization(ctx context.Context, organizationName, newOrganizationName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameGithubOrganization", ctx, organizationName, newOrganizationName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameGithubOrganization indicates an expected call of RenameGithubOrganization
+func (mr *MockRepositoryMockRecorder) RenameGithubOrganization(ctx, organizationName, newOrganizationName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameGithubOrganization", reflect.TypeOf((*MockRepository)(nil).RenameGithubOrganization), ctx, organizationName, newOrganizationName)
+}
+
 // DeleteGithubOrganization mocks base method
 func (m *MockRepository) DeleteGithubOrganization(ctx context.Context, projectSFID, githubOrgName string) error {
 	m.ctrl.T.Helper()
@@ -13,6 +13,7 @@ type GithubOrganization struct {
 	OrganizationName           string `json:"organization_name,omitempty"`
 	OrganizationNameLower      string `json:"organization_name_lower,omitempty"`
 	OrganizationSFID           string `json:"organization_sfid,omitempty"`
+	OrganizationType           string `json:"organization_type,omitempty"`
 	ProjectSFID                string `json:"project_sfid"`
 	AutoEnabled                bool   `json:"auto_enabled"`
 	BranchProtectionEnabled    bool   `json:"branch_protection_enabled"`
@@ -28,6 +29,7 @@ func ToModel(in *GithubOrganization) *models.GithubOrganization {
 		OrganizationInstallationID: in.OrganizationInstallationID,
 		OrganizationName:           in.OrganizationName,
 		OrganizationSfid:           in.OrganizationSFID,
+		OrganizationType:           in.OrganizationType,
 		Version:                    in.Version,
 		AutoEnabled:                in.AutoEnabled,
 		AutoEnabledClaGroupID:      in.AutoEnabledClaGroupID,
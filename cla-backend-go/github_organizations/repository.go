@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -15,12 +16,13 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // indexes
@@ -42,22 +44,26 @@ type Repository interface {
 	GetGithubOrganizationsByParent(ctx context.Context, parentProjectSFID string) (*models.GithubOrganizations, error)
 	GetGithubOrganization(ctx context.Context, githubOrganizationName string) (*models.GithubOrganization, error)
 	GetGithubOrganizationByName(ctx context.Context, githubOrganizationName string) (*models.GithubOrganizations, error)
+	GetGithubOrganizationByInstallationID(ctx context.Context, installationID int64) (*models.GithubOrganization, error)
+	GetBranchProtectionEnabledGithubOrganizations(ctx context.Context) ([]*models.GithubOrganization, error)
 	UpdateGithubOrganization(ctx context.Context, projectSFID string, organizationName string, autoEnabled bool, autoEnabledClaGroupID string, branchProtectionEnabled bool) error
+	UpdateGithubOrganizationInstallationID(ctx context.Context, organizationName string, installationID int64) error
+	RenameGithubOrganization(ctx context.Context, organizationName string, newOrganizationName string) error
 	DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error
 	DeleteGithubOrganizationByParent(ctx context.Context, parentProjectSFID string, githubOrgName string) error
 }
 
 type repository struct {
 	stage              string
-	dynamoDBClient     *dynamodb.DynamoDB
+	dynamoDBClient     storage.Driver
 	githubOrgTableName string
 }
 
 // NewRepository creates a new instance of the githubOrganizations repository
-func NewRepository(awsSession *session.Session, stage string) repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) repository {
 	return repository{
 		stage:              stage,
-		dynamoDBClient:     dynamodb.New(awsSession),
+		dynamoDBClient:     dynamoDBClient,
 		githubOrgTableName: fmt.Sprintf("cla-%s-github-orgs", stage),
 	}
 }
@@ -97,6 +103,12 @@ func (repo repository) AddGithubOrganization(ctx context.Context, parentProjectS
 		len(existingRecord.List) == 1 &&
 		parentProjectSFID == existingRecord.List[0].OrganizationSfid {
 
+		if repositories.OrganizationType(existingRecord.List[0]) != organizationType(input) {
+			log.WithFields(f).Warnf("existing organization is a %s organization", repositories.OrganizationType(existingRecord.List[0]))
+			return nil, fmt.Errorf("organization %s is already registered as a %s organization",
+				utils.StringValue(input.OrganizationName), repositories.OrganizationType(existingRecord.List[0]))
+		}
+
 		// These are our rules for updating
 		autoEnabled := existingRecord.List[0].AutoEnabled || utils.BoolValue(input.AutoEnabled)
 		branchProtectionEnabled := existingRecord.List[0].BranchProtectionEnabled || utils.BoolValue(input.BranchProtectionEnabled)
@@ -148,6 +160,7 @@ func (repo repository) AddGithubOrganization(ctx context.Context, parentProjectS
 		OrganizationName:           *input.OrganizationName,
 		OrganizationNameLower:      strings.ToLower(*input.OrganizationName),
 		OrganizationSFID:           parentProjectSFID,
+		OrganizationType:           organizationType(input),
 		ProjectSFID:                projectSFID,
 		AutoEnabled:                aws.BoolValue(input.AutoEnabled),
 		AutoEnabledClaGroupID:      input.AutoEnabledClaGroupID,
@@ -163,7 +176,7 @@ func (repo repository) AddGithubOrganization(ctx context.Context, parentProjectS
 	}
 
 	log.WithFields(f).Debug("Adding github organization record to the database...")
-	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:                av,
 		TableName:           aws.String(repo.githubOrgTableName),
 		ConditionExpression: aws.String("attribute_not_exists(organization_name)"),
@@ -212,7 +225,7 @@ func (repo repository) GetGithubOrganizations(ctx context.Context, projectSFID s
 		IndexName:                 aws.String(ProjectSFIDOrganizationNameIndex),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("error retrieving github_organizations using project_sfid = %s. error = %s", projectSFID, err.Error())
 		return nil, err
@@ -263,7 +276,7 @@ func (repo repository) GetGithubOrganizationsByParent(ctx context.Context, paren
 		IndexName:                 aws.String(GithubOrgSFIDIndex),
 	}
 
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("error retrieving github_organizations using organization_sfid = %s, error = %+v", parentProjectSFID, err)
 		return nil, err
@@ -313,7 +326,7 @@ func (repo repository) GetGithubOrganizationByName(ctx context.Context, githubOr
 	}
 
 	log.WithFields(f).Debugf("querying for github organization by name using organization_name_lower=%s...", strings.ToLower(githubOrganizationName))
-	results, err := repo.dynamoDBClient.Query(queryInput)
+	results, err := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("error retrieving github_organizations using githubOrganizationName = %s", githubOrganizationName)
 		return nil, err
@@ -343,7 +356,7 @@ func (repo repository) GetGithubOrganization(ctx context.Context, githubOrganiza
 	}
 
 	log.WithFields(f).Debug("Querying for github organization by name...")
-	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"organization_name": {
 				S: aws.String(githubOrganizationName),
@@ -424,7 +437,7 @@ func (repo repository) UpdateGithubOrganization(ctx context.Context, projectSFID
 	}
 
 	log.WithFields(f).Debug("updating github organization record...")
-	_, updateErr := repo.dynamoDBClient.UpdateItem(input)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("unable to update github organization record, error: %+v", updateErr)
 		return updateErr
@@ -433,6 +446,240 @@ func (repo repository) UpdateGithubOrganization(ctx context.Context, projectSFID
 	return nil
 }
 
+// GetGithubOrganizationByInstallationID returns the github organization which has the specified GitHub app installation id
+func (repo repository) GetGithubOrganizationByInstallationID(ctx context.Context, installationID int64) (*models.GithubOrganization, error) {
+	f := logrus.Fields{
+		"functionName":   "GetGithubOrganizationByInstallationID",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"installationID": installationID,
+	}
+
+	filter := expression.Name("organization_installation_id").Equal(expression.Value(installationID))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the scan expression")
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.githubOrgTableName),
+	}
+
+	var githubOrgs []*GithubOrganization
+	for {
+		results, scanErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
+		if scanErr != nil {
+			log.WithFields(f).WithError(scanErr).Warn("unable to scan github organizations by installation id")
+			return nil, scanErr
+		}
+
+		var items []*GithubOrganization
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem decoding database results")
+			return nil, err
+		}
+		githubOrgs = append(githubOrgs, items...)
+
+		if results.LastEvaluatedKey == nil || len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	if len(githubOrgs) == 0 {
+		return nil, ErrOrganizationDoesNotExist
+	}
+	if len(githubOrgs) > 1 {
+		log.WithFields(f).Warnf("more than one github organization with the same installation id in the database, count: %d", len(githubOrgs))
+	}
+
+	return ToModel(githubOrgs[0]), nil
+}
+
+// GetBranchProtectionEnabledGithubOrganizations returns the github organizations which have branch protection enabled
+func (repo repository) GetBranchProtectionEnabledGithubOrganizations(ctx context.Context) ([]*models.GithubOrganization, error) {
+	f := logrus.Fields{
+		"functionName":   "GetBranchProtectionEnabledGithubOrganizations",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	filter := expression.Name("branch_protection_enabled").Equal(expression.Value(true))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the scan expression")
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.githubOrgTableName),
+	}
+
+	var githubOrgs []*models.GithubOrganization
+	for {
+		results, scanErr := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
+		if scanErr != nil {
+			log.WithFields(f).WithError(scanErr).Warn("unable to scan the branch protection enabled github organizations")
+			return nil, scanErr
+		}
+
+		var items []*GithubOrganization
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem decoding database results")
+			return nil, err
+		}
+		for _, item := range items {
+			githubOrgs = append(githubOrgs, ToModel(item))
+		}
+
+		if results.LastEvaluatedKey == nil || len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	log.WithFields(f).Debugf("found %d github organizations with branch protection enabled", len(githubOrgs))
+	return githubOrgs, nil
+}
+
+// UpdateGithubOrganizationInstallationID updates the GitHub app installation id of the github organization, zero
+// indicates that the application is no longer installed (or suspended) on the organization
+func (repo repository) UpdateGithubOrganizationInstallationID(ctx context.Context, organizationName string, installationID int64) error {
+	f := logrus.Fields{
+		"functionName":     "UpdateGithubOrganizationInstallationID",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"organizationName": organizationName,
+		"installationID":   installationID,
+	}
+
+	_, currentTime := utils.CurrentTime()
+	log.WithFields(f).Debug("updating github organization installation id...")
+	_, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"organization_name": {
+				S: aws.String(organizationName),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#I": aws.String("organization_installation_id"),
+			"#M": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":i": {
+				N: aws.String(strconv.FormatInt(installationID, 10)),
+			},
+			":m": {
+				S: aws.String(currentTime),
+			},
+		},
+		ConditionExpression: aws.String("attribute_exists(organization_name)"),
+		UpdateExpression:    aws.String("SET #I = :i, #M = :m"),
+		TableName:           aws.String(repo.githubOrgTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrOrganizationDoesNotExist
+		}
+		log.WithFields(f).WithError(err).Warn("unable to update github organization installation id")
+		return err
+	}
+
+	return nil
+}
+
+// RenameGithubOrganization moves the github organization record to the new organization name - the organization
+// name is the table key, so we create the record under the new name and remove the old one in a single transaction
+func (repo repository) RenameGithubOrganization(ctx context.Context, organizationName string, newOrganizationName string) error {
+	f := logrus.Fields{
+		"functionName":        "RenameGithubOrganization",
+		utils.XREQUESTID:      ctx.Value(utils.XREQUESTID),
+		"organizationName":    organizationName,
+		"newOrganizationName": newOrganizationName,
+	}
+
+	result, err := repo.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"organization_name": {
+				S: aws.String(organizationName),
+			},
+		},
+		TableName: aws.String(repo.githubOrgTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load github organization")
+		return err
+	}
+	if len(result.Item) == 0 {
+		return ErrOrganizationDoesNotExist
+	}
+
+	var githubOrg GithubOrganization
+	err = dynamodbattribute.UnmarshalMap(result.Item, &githubOrg)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("error unmarshalling organization table data")
+		return err
+	}
+
+	_, currentTime := utils.CurrentTime()
+	githubOrg.OrganizationName = newOrganizationName
+	githubOrg.OrganizationNameLower = strings.ToLower(newOrganizationName)
+	githubOrg.DateModified = currentTime
+
+	av, err := dynamodbattribute.MarshalMap(githubOrg)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshall the renamed github organization")
+		return err
+	}
+
+	// the new record and the removal of the old one are applied together, so we never end up with both
+	log.WithFields(f).Debug("moving github organization record to the new name...")
+	_, err = repo.dynamoDBClient.TransactWriteItemsWithContext(ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					Item:                av,
+					TableName:           aws.String(repo.githubOrgTableName),
+					ConditionExpression: aws.String("attribute_not_exists(organization_name)"),
+				},
+			},
+			{
+				Delete: &dynamodb.Delete{
+					Key: map[string]*dynamodb.AttributeValue{
+						"organization_name": {
+							S: aws.String(organizationName),
+						},
+					},
+					TableName:           aws.String(repo.githubOrgTableName),
+					ConditionExpression: aws.String("attribute_exists(organization_name)"),
+				},
+			},
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(*dynamodb.TransactionCanceledException); ok && len(aerr.CancellationReasons) == 2 {
+			if aws.StringValue(aerr.CancellationReasons[0].Code) == "ConditionalCheckFailed" {
+				log.WithFields(f).WithError(err).Warn("github organization with the new name already exists")
+				return errors.New("github organization already exists")
+			}
+			if aws.StringValue(aerr.CancellationReasons[1].Code) == "ConditionalCheckFailed" {
+				log.WithFields(f).WithError(err).Warn("github organization was removed while renaming it")
+				return ErrOrganizationDoesNotExist
+			}
+		}
+		log.WithFields(f).WithError(err).Warn("cannot move the github organization record to the new name")
+		return err
+	}
+
+	return nil
+}
+
 func (repo repository) DeleteGithubOrganization(ctx context.Context, projectSFID string, githubOrgName string) error {
 	f := logrus.Fields{
 		"functionName":   "DeleteGithubOrganization",
@@ -456,7 +703,7 @@ func (repo repository) DeleteGithubOrganization(ctx context.Context, projectSFID
 	}
 
 	log.WithFields(f).Debug("Deleting GitHub organization...")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"organization_name": {
 				S: aws.String(githubOrganizationName),
@@ -496,7 +743,7 @@ func (repo repository) DeleteGithubOrganizationByParent(ctx context.Context, par
 	}
 
 	log.WithFields(f).Debug("Deleting GitHub organization...")
-	_, err := repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, err := repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"organization_name": {
 				S: aws.String(githubOrganizationName),
@@ -50,6 +50,7 @@ func (s service) AddGithubOrganization(ctx context.Context, projectSFID string,
 		utils.XREQUESTID:          ctx.Value(utils.XREQUESTID),
 		"projectSFID":             projectSFID,
 		"organizationName":        input.OrganizationName,
+		"organizationType":        input.OrganizationType,
 		"autoEnabled":             input.AutoEnabled,
 		"branchProtectionEnabled": input.BranchProtectionEnabled,
 	}
@@ -67,6 +68,10 @@ func (s service) AddGithubOrganization(ctx context.Context, projectSFID string,
 		}
 	}
 
+	if err := ValidateOrganization(ctx, utils.StringValue(input.OrganizationName), organizationType(input)); err != nil {
+		return nil, err
+	}
+
 	return s.repo.AddGithubOrganization(ctx, parentProjectSFID, projectSFID, input)
 }
 
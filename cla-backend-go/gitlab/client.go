@@ -0,0 +1,272 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errors
+var (
+	// ErrNotConfigured is returned when GitLab support isn't configured
+	ErrNotConfigured = errors.New("gitlab support is not configured")
+	// ErrAccessDenied is returned whenever gitlab returns 401 or 403
+	ErrAccessDenied = errors.New("gitlab access denied")
+	// ErrGroupNotFound is returned when the gitlab group doesn't exist or isn't visible to the access token
+	ErrGroupNotFound = errors.New("gitlab group not found")
+	// ErrProjectNotFound is returned when the gitlab project doesn't exist or isn't visible to the access token
+	ErrProjectNotFound = errors.New("gitlab project not found")
+	// ErrMergeRequestNotFound is returned when the gitlab merge request doesn't exist
+	ErrMergeRequestNotFound = errors.New("gitlab merge request not found")
+)
+
+// commit status states
+const (
+	CommitStatePending = "pending"
+	CommitStateSuccess = "success"
+	CommitStateFailed  = "failed"
+)
+
+// PrivateTokenHeader is the header carrying the GitLab access token
+const PrivateTokenHeader = "PRIVATE-TOKEN"
+
+// nextPageHeader is the header carrying the next page number of a paginated GitLab response, empty on the last page
+const nextPageHeader = "X-Next-Page"
+
+// pageSize is the page size requested from the paginated GitLab APIs
+const pageSize = 100
+
+// Client is a minimal GitLab REST API v4 client
+type Client struct {
+	baseURL     string
+	accessToken string
+	httpClient  *http.Client
+}
+
+// Group is a GitLab group
+type Group struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	FullPath    string `json:"full_path"`
+	Description string `json:"description"`
+	WebURL      string `json:"web_url"`
+}
+
+// Namespace is the group or the user a GitLab project belongs to
+type Namespace struct {
+	ID       int64  `json:"id"`
+	Kind     string `json:"kind"`
+	FullPath string `json:"full_path"`
+}
+
+// Project is a GitLab project
+type Project struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	Path              string    `json:"path"`
+	PathWithNamespace string    `json:"path_with_namespace"`
+	WebURL            string    `json:"web_url"`
+	Archived          bool      `json:"archived"`
+	Namespace         Namespace `json:"namespace"`
+}
+
+// Commit is a GitLab commit
+type Commit struct {
+	ID             string `json:"id"`
+	ShortID        string `json:"short_id"`
+	Title          string `json:"title"`
+	AuthorName     string `json:"author_name"`
+	AuthorEmail    string `json:"author_email"`
+	CommitterName  string `json:"committer_name"`
+	CommitterEmail string `json:"committer_email"`
+}
+
+// CommitStatus is the status of a commit reported by an external service
+type CommitStatus struct {
+	State       string `json:"state"`
+	Name        string `json:"name,omitempty"`
+	TargetURL   string `json:"target_url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// NewClient creates a GitLab client for the API base URL, e.g. https://gitlab.com/api/v4, authenticated with the
+// access token - the default http client is used when none is specified
+func NewClient(baseURL string, accessToken string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		accessToken: accessToken,
+		httpClient:  httpClient,
+	}
+}
+
+// GetGroup returns the group with the specified full path, e.g. my-group/my-sub-group
+func (c *Client) GetGroup(ctx context.Context, groupPath string) (*Group, error) {
+	var group Group
+	if _, err := c.do(ctx, http.MethodGet, "/groups/"+url.PathEscape(groupPath), nil, nil, &group); err != nil {
+		return nil, notFound(err, ErrGroupNotFound)
+	}
+	return &group, nil
+}
+
+// ListGroupProjects returns the projects of the group and its sub-groups, archived projects are skipped
+func (c *Client) ListGroupProjects(ctx context.Context, groupPath string) ([]*Project, error) {
+	var projects []*Project
+	query := url.Values{
+		"include_subgroups": {"true"},
+		"archived":          {"false"},
+		"order_by":          {"path"},
+		"sort":              {"asc"},
+	}
+	err := c.paginate(ctx, "/groups/"+url.PathEscape(groupPath)+"/projects", query, func(body []byte) error {
+		var page []*Project
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		projects = append(projects, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, notFound(err, ErrGroupNotFound)
+	}
+	return projects, nil
+}
+
+// GetProject returns the project with the specified ID
+func (c *Client) GetProject(ctx context.Context, projectID int64) (*Project, error) {
+	var project Project
+	if _, err := c.do(ctx, http.MethodGet, fmt.Sprintf("/projects/%d", projectID), nil, nil, &project); err != nil {
+		return nil, notFound(err, ErrProjectNotFound)
+	}
+	return &project, nil
+}
+
+// ListMergeRequestCommits returns the commits of the merge request, mergeRequestIID is the project level ID of the
+// merge request
+func (c *Client) ListMergeRequestCommits(ctx context.Context, projectID int64, mergeRequestIID int64) ([]*Commit, error) {
+	var commits []*Commit
+	err := c.paginate(ctx, fmt.Sprintf("/projects/%d/merge_requests/%d/commits", projectID, mergeRequestIID), url.Values{}, func(body []byte) error {
+		var page []*Commit
+		if err := json.Unmarshal(body, &page); err != nil {
+			return err
+		}
+		commits = append(commits, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, notFound(err, ErrMergeRequestNotFound)
+	}
+	return commits, nil
+}
+
+// SetCommitStatus creates or updates the status of the commit, statuses with the same name replace each other
+func (c *Client) SetCommitStatus(ctx context.Context, projectID int64, sha string, status *CommitStatus) error {
+	_, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/statuses/%s", projectID, url.PathEscape(sha)), nil, status, nil)
+	return notFound(err, ErrProjectNotFound)
+}
+
+// statusError is returned for unsuccessful GitLab responses
+type statusError struct {
+	statusCode int
+	message    string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("gitlab request failed with status code %d: %s", e.statusCode, e.message)
+}
+
+// notFound replaces a 404 response error with the specified error
+func notFound(err error, notFoundErr error) error {
+	var sErr *statusError
+	if errors.As(err, &sErr) && sErr.statusCode == http.StatusNotFound {
+		return notFoundErr
+	}
+	return err
+}
+
+// paginate requests all the pages of the path, handing the body of each page to the callback
+func (c *Client) paginate(ctx context.Context, path string, query url.Values, callback func(body []byte) error) error {
+	query.Set("per_page", strconv.Itoa(pageSize))
+	page := "1"
+	for page != "" {
+		query.Set("page", page)
+		var body json.RawMessage
+		resp, err := c.do(ctx, http.MethodGet, path, query, nil, &body)
+		if err != nil {
+			return err
+		}
+		if err = callback(body); err != nil {
+			return err
+		}
+		page = resp.Header.Get(nextPageHeader)
+	}
+	return nil
+}
+
+// do sends the request with the optional JSON body and decodes the JSON response into out when not nil
+func (c *Client) do(ctx context.Context, method string, path string, query url.Values, in interface{}, out interface{}) (*http.Response, error) {
+	requestURL := c.baseURL + path
+	if len(query) > 0 {
+		requestURL += "?" + query.Encode()
+	}
+
+	var reqBody *bytes.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(payload)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(PrivateTokenHeader, c.accessToken)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return resp, fmt.Errorf("%s %s : %w", method, path, ErrAccessDenied)
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		return resp, &statusError{statusCode: resp.StatusCode, message: strings.TrimSpace(string(body))}
+	}
+
+	if out != nil && len(body) > 0 {
+		if err = json.Unmarshal(body, out); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
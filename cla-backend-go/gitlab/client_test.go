@@ -0,0 +1,119 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newStubServer starts a local server standing in for the GitLab API
+func newStubServer(t *testing.T, statuses *[]CommitStatus) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/groups/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/groups/example%2Fplatform":
+			fmt.Fprint(w, `{"id":7,"name":"Platform","path":"platform","full_path":"example/platform","web_url":"https://gitlab.example.com/groups/example/platform"}`)
+		case "/api/v4/groups/example%2Fplatform/projects":
+			assert.Equal(t, "true", r.URL.Query().Get("include_subgroups"))
+			if r.URL.Query().Get("page") == "1" {
+				w.Header().Set(nextPageHeader, "2")
+				fmt.Fprint(w, `[{"id":11,"path_with_namespace":"example/platform/api","web_url":"https://gitlab.example.com/example/platform/api"}]`)
+				return
+			}
+			w.Header().Set(nextPageHeader, "")
+			fmt.Fprint(w, `[{"id":12,"path_with_namespace":"example/platform/ui","web_url":"https://gitlab.example.com/example/platform/ui"}]`)
+		default:
+			http.Error(w, `{"message":"404 Group Not Found"}`, http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc("/api/v4/projects/11", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(PrivateTokenHeader) != "token" {
+			http.Error(w, `{"message":"401 Unauthorized"}`, http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id":11,"path_with_namespace":"example/platform/api","namespace":{"id":7,"kind":"group","full_path":"example/platform"}}`)
+	})
+	mux.HandleFunc("/api/v4/projects/11/merge_requests/3/commits", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id":"a1","author_name":"Jane","author_email":"jane@example.com"},{"id":"b2","author_name":"Joe","author_email":"joe@example.org"}]`)
+	})
+	mux.HandleFunc("/api/v4/projects/11/statuses/b2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var status CommitStatus
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&status))
+		*statuses = append(*statuses, status)
+		fmt.Fprint(w, `{"id":1}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestClient(t *testing.T) {
+	var statuses []CommitStatus
+	server := newStubServer(t, &statuses)
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewClient(server.URL+"/api/v4/", "token", server.Client())
+
+	group, err := client.GetGroup(ctx, "example/platform")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), group.ID)
+	assert.Equal(t, "example/platform", group.FullPath)
+
+	_, err = client.GetGroup(ctx, "missing")
+	assert.Equal(t, ErrGroupNotFound, err)
+
+	projects, err := client.ListGroupProjects(ctx, "example/platform")
+	assert.Nil(t, err)
+	if assert.Len(t, projects, 2) {
+		assert.Equal(t, "example/platform/api", projects[0].PathWithNamespace)
+		assert.Equal(t, int64(12), projects[1].ID)
+	}
+
+	project, err := client.GetProject(ctx, 11)
+	assert.Nil(t, err)
+	assert.Equal(t, "example/platform", project.Namespace.FullPath)
+
+	_, err = client.GetProject(ctx, 99)
+	assert.Equal(t, ErrProjectNotFound, err)
+
+	_, err = NewClient(server.URL+"/api/v4", "expired", server.Client()).GetProject(ctx, 11)
+	assert.True(t, errors.Is(err, ErrAccessDenied))
+
+	commits, err := client.ListMergeRequestCommits(ctx, 11, 3)
+	assert.Nil(t, err)
+	if assert.Len(t, commits, 2) {
+		assert.Equal(t, "joe@example.org", commits[1].AuthorEmail)
+	}
+
+	err = client.SetCommitStatus(ctx, 11, "b2", &CommitStatus{State: CommitStateFailed, Name: "EasyCLA", Description: "missing CLA"})
+	assert.Nil(t, err)
+	assert.Equal(t, []CommitStatus{{State: CommitStateFailed, Name: "EasyCLA", Description: "missing CLA"}}, statuses)
+}
+
+func TestParseWebhook(t *testing.T) {
+	event, err := ParseWebhook(SystemHookEventType, []byte(`{"event_name":"project_create","project_id":11,"path_with_namespace":"example/platform/api"}`))
+	assert.Nil(t, err)
+	if assert.IsType(t, &SystemHookEvent{}, event) {
+		assert.Equal(t, ProjectCreateEventName, event.(*SystemHookEvent).EventName)
+		assert.Equal(t, int64(11), event.(*SystemHookEvent).ProjectID)
+	}
+
+	event, err = ParseWebhook(MergeRequestEventType, []byte(`{"object_kind":"merge_request","user":{"username":"jane"},"project":{"id":11},"object_attributes":{"iid":3,"action":"open","last_commit":{"id":"b2"}}}`))
+	assert.Nil(t, err)
+	if assert.IsType(t, &MergeRequestEvent{}, event) {
+		assert.Equal(t, int64(3), event.(*MergeRequestEvent).ObjectAttributes.IID)
+		assert.Equal(t, "b2", event.(*MergeRequestEvent).ObjectAttributes.LastCommit.ID)
+	}
+
+	_, err = ParseWebhook("Push Hook", []byte(`{}`))
+	assert.True(t, errors.Is(err, ErrUnsupportedEvent))
+}
@@ -0,0 +1,106 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// webhook headers
+const (
+	EventHeader = "X-Gitlab-Event"
+	TokenHeader = "X-Gitlab-Token"
+)
+
+// webhook event types, sent in the event header
+const (
+	SystemHookEventType   = "System Hook"
+	MergeRequestEventType = "Merge Request Hook"
+)
+
+// system hook event names
+const (
+	ProjectCreateEventName  = "project_create"
+	ProjectDestroyEventName = "project_destroy"
+)
+
+// merge request actions which change the commits of the merge request
+const (
+	MergeRequestActionOpen   = "open"
+	MergeRequestActionReopen = "reopen"
+	MergeRequestActionUpdate = "update"
+)
+
+// ErrUnsupportedEvent is returned when parsing an event type which isn't handled
+var ErrUnsupportedEvent = errors.New("unsupported gitlab event")
+
+// SystemHookEvent is a GitLab system hook delivery, only the project fields are kept
+type SystemHookEvent struct {
+	EventName         string `json:"event_name"`
+	Name              string `json:"name"`
+	Path              string `json:"path"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	ProjectID         int64  `json:"project_id"`
+	OwnerName         string `json:"owner_name"`
+	OwnerEmail        string `json:"owner_email"`
+}
+
+// EventUser is the user who triggered a webhook event
+type EventUser struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+// EventProject is the project of a webhook event
+type EventProject struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	PathWithNamespace string `json:"path_with_namespace"`
+	WebURL            string `json:"web_url"`
+}
+
+// MergeRequestAttributes are the merge request details of a merge request event
+type MergeRequestAttributes struct {
+	ID              int64  `json:"id"`
+	IID             int64  `json:"iid"`
+	Title           string `json:"title"`
+	State           string `json:"state"`
+	Action          string `json:"action"`
+	URL             string `json:"url"`
+	SourceProjectID int64  `json:"source_project_id"`
+	TargetProjectID int64  `json:"target_project_id"`
+	LastCommit      struct {
+		ID string `json:"id"`
+	} `json:"last_commit"`
+}
+
+// MergeRequestEvent is a GitLab merge request webhook delivery
+type MergeRequestEvent struct {
+	ObjectKind       string                 `json:"object_kind"`
+	User             EventUser              `json:"user"`
+	Project          EventProject           `json:"project"`
+	ObjectAttributes MergeRequestAttributes `json:"object_attributes"`
+}
+
+// ParseWebhook parses the webhook payload of the event type, returns a *SystemHookEvent or a *MergeRequestEvent
+func ParseWebhook(eventType string, payload []byte) (interface{}, error) {
+	var event interface{}
+	switch eventType {
+	case SystemHookEventType:
+		event = &SystemHookEvent{}
+	case MergeRequestEventType:
+		event = &MergeRequestEvent{}
+	default:
+		return nil, fmt.Errorf("%s : %w", eventType, ErrUnsupportedEvent)
+	}
+
+	if err := json.Unmarshal(payload, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
@@ -0,0 +1,31 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab
+
+// DefaultBaseURL is the GitLab API URL used when no base URL is configured
+const DefaultBaseURL = "https://gitlab.com/api/v4"
+
+var gitlabClient *Client
+
+// Init initializes the GitLab client, GitLab support stays disabled when no access token is configured
+func Init(baseURL string, accessToken string) {
+	if accessToken == "" {
+		gitlabClient = nil
+		return
+	}
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	gitlabClient = NewClient(baseURL, accessToken, nil)
+}
+
+// GetClient returns the GitLab client, returns nil if GitLab support isn't configured
+func GetClient() *Client {
+	return gitlabClient
+}
+
+// IsEnabled returns true if GitLab support is configured
+func IsEnabled() bool {
+	return gitlabClient != nil
+}
@@ -0,0 +1,105 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/token"
+)
+
+// DynamoDBTableChecks returns a critical check of each table
+func DynamoDBTableChecks(dynamoDBClient storage.Driver, tableNames []string) []Check {
+	var checks []Check
+	for _, tableName := range tableNames {
+		tableName := tableName
+		checks = append(checks, Check{
+			Name:     "dynamodb:" + tableName,
+			Critical: true,
+			Probe: func(ctx context.Context) error {
+				_, err := dynamoDBClient.DescribeTable(&dynamodb.DescribeTableInput{
+					TableName: aws.String(tableName),
+				})
+				return err
+			},
+		})
+	}
+	return checks
+}
+
+// S3BucketCheck returns a critical check of the access to the bucket, e.g. the bucket of the signed documents
+func S3BucketCheck(awsSession *session.Session, bucket string) Check {
+	s3Client := s3.New(awsSession)
+	return Check{
+		Name:     "s3:" + bucket,
+		Critical: true,
+		Probe: func(ctx context.Context) error {
+			_, err := s3Client.HeadBucketWithContext(ctx, &s3.HeadBucketInput{
+				Bucket: aws.String(bucket),
+			})
+			return err
+		},
+	}
+}
+
+// SNSTopicCheck returns a non-critical check of the topic the emails are sent to - the API keeps working without
+// the emails
+func SNSTopicCheck(awsSession *session.Session, topicARN string) Check {
+	snsClient := sns.New(awsSession)
+	return Check{
+		Name:     "sns:email-topic",
+		Critical: false,
+		Probe: func(ctx context.Context) error {
+			_, err := snsClient.GetTopicAttributesWithContext(ctx, &sns.GetTopicAttributesInput{
+				TopicArn: aws.String(topicARN),
+			})
+			return err
+		},
+	}
+}
+
+// TokenCheck returns a critical check of the Auth0 token of the platform service calls - the token is cached, so
+// the token endpoint is only called when the token expired
+func TokenCheck() Check {
+	return Check{
+		Name:     "auth0:token",
+		Critical: true,
+		Probe: func(ctx context.Context) error {
+			_, err := token.GetToken()
+			return err
+		},
+	}
+}
+
+// HTTPCheck returns a check of the reachability of a service - any response below 500 is healthy, the endpoints are
+// called without credentials so an authorization error still shows the service is up
+func HTTPCheck(name, url string, critical bool) Check {
+	return Check{
+		Name:     name,
+		Critical: critical,
+		Probe: func(ctx context.Context) error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+			if err != nil {
+				return err
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close() // nolint
+			if resp.StatusCode >= http.StatusInternalServerError {
+				return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+			}
+			return nil
+		},
+	}
+}
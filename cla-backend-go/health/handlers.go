@@ -7,6 +7,7 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/health"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/runtime/middleware"
 )
 
@@ -21,6 +22,29 @@ func Configure(api *operations.ClaAPI, service Service) {
 
 		return health.NewHealthCheckOK().WithPayload(result)
 	})
+
+	api.HealthLivenessCheckHandler = health.LivenessCheckHandlerFunc(func(params health.LivenessCheckParams) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		result, err := service.LivenessCheck(params.HTTPRequest.Context())
+		if err != nil {
+			return health.NewLivenessCheckBadRequest().WithPayload(errorResponse(err))
+		}
+
+		return health.NewLivenessCheckOK().WithXRequestID(reqID).WithPayload(result)
+	})
+
+	api.HealthReadinessCheckHandler = health.ReadinessCheckHandlerFunc(func(params health.ReadinessCheckParams) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		result, err := service.ReadinessCheck(params.HTTPRequest.Context())
+		if err != nil {
+			return health.NewReadinessCheckBadRequest().WithPayload(errorResponse(err))
+		}
+		if result.Status == StatusNotHealthy {
+			return health.NewReadinessCheckServiceUnavailable().WithXRequestID(reqID).WithPayload(result)
+		}
+
+		return health.NewReadinessCheckOK().WithXRequestID(reqID).WithPayload(result)
+	})
 }
 
 type codedResponse interface {
@@ -0,0 +1,173 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package health
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"golang.org/x/sync/singleflight"
+)
+
+// Health status values
+const (
+	StatusHealthy    = "healthy"
+	StatusDegraded   = "degraded"
+	StatusNotHealthy = "not healthy"
+)
+
+const (
+	// defaultCheckTimeout bounds the probe of a dependency which does not set a timeout of its own
+	defaultCheckTimeout = 5 * time.Second
+	// readinessCacheTTL is how long the result of a probe is reused - the readiness endpoint is polled by the load
+	// balancers and must not add load to the dependencies
+	readinessCacheTTL = 30 * time.Second
+)
+
+// errCheckTimeout is the error of the probes which did not complete within their timeout
+var errCheckTimeout = errors.New("the check did not complete within its timeout")
+
+// Check is the probe of a dependency of the readiness check. A failed critical check makes the service not ready,
+// a failed non-critical check only makes it degraded.
+type Check struct {
+	// Name identifies the check, it is prefixed with its kind, e.g. s3:cla-signature-files-dev
+	Name     string
+	Critical bool
+	// Timeout of the probe, defaultCheckTimeout when zero
+	Timeout time.Duration
+	Probe   func(ctx context.Context) error
+}
+
+// WithNonCritical returns the checks with the named checks non-critical and the other checks critical, overriding
+// the defaults of the checks - a name matches the check name or its kind, so "platform" matches all the platform
+// service checks
+func WithNonCritical(checks []Check, names []string) []Check {
+	out := make([]Check, len(checks))
+	for i, check := range checks {
+		out[i] = check
+		out[i].Critical = true
+		kind := strings.SplitN(check.Name, ":", 2)[0]
+		for _, name := range names {
+			name = strings.TrimSpace(name)
+			if name != "" && (name == check.Name || name == kind) {
+				out[i].Critical = false
+			}
+		}
+	}
+	return out
+}
+
+// readiness runs the checks and caches their results
+type readiness struct {
+	checks []Check
+	ttl    time.Duration
+	group  singleflight.Group
+
+	mu      sync.Mutex
+	results map[string]*cachedResult
+}
+
+type cachedResult struct {
+	status    models.HealthStatus
+	checkedAt time.Time
+}
+
+func newReadiness(checks []Check) *readiness {
+	return &readiness{
+		checks:  checks,
+		ttl:     readinessCacheTTL,
+		results: make(map[string]*cachedResult),
+	}
+}
+
+// run returns the status of each check, the checks are probed concurrently unless their cached result is current
+func (r *readiness) run() []*models.HealthStatus {
+	statuses := make([]*models.HealthStatus, len(r.checks))
+	var wg sync.WaitGroup
+	for i, check := range r.checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			status := r.status(check)
+			statuses[i] = &status
+		}(i, check)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func (r *readiness) status(check Check) models.HealthStatus {
+	r.mu.Lock()
+	cached, ok := r.results[check.Name]
+	r.mu.Unlock()
+	if ok && time.Since(cached.checkedAt) < r.ttl {
+		return cached.status
+	}
+
+	// The concurrent readiness requests share a single probe of the dependency
+	v, _, _ := r.group.Do(check.Name, func() (interface{}, error) {
+		status := probe(check)
+		r.mu.Lock()
+		r.results[check.Name] = &cachedResult{status: status, checkedAt: time.Now()}
+		r.mu.Unlock()
+		return status, nil
+	})
+	return v.(models.HealthStatus)
+}
+
+// probe runs the probe of the check within its timeout, the probes which do not honour the context are abandoned
+// when the timeout expires - the result is shared with the other requests, so it does not depend on the context of
+// the request
+func probe(check Check) models.HealthStatus {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = defaultCheckTimeout
+	}
+	probeCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- check.Probe(probeCtx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-probeCtx.Done():
+		err = errCheckTimeout
+	}
+
+	status := models.HealthStatus{
+		Name:      check.Name,
+		Critical:  check.Critical,
+		Healthy:   err == nil,
+		Duration:  time.Since(start).String(),
+		TimeStamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// overallStatus returns not healthy when a critical check failed, degraded when only non-critical checks failed
+func overallStatus(statuses []*models.HealthStatus) string {
+	status := StatusHealthy
+	for _, item := range statuses {
+		if item.Healthy {
+			continue
+		}
+		if item.Critical {
+			return StatusNotHealthy
+		}
+		status = StatusDegraded
+	}
+	return status
+}
@@ -0,0 +1,74 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverallStatus(t *testing.T) {
+	assert.Equal(t, StatusHealthy, overallStatus([]*models.HealthStatus{
+		{Name: "s3:bucket", Critical: true, Healthy: true},
+	}))
+	assert.Equal(t, StatusDegraded, overallStatus([]*models.HealthStatus{
+		{Name: "s3:bucket", Critical: true, Healthy: true},
+		{Name: "sns:email-topic", Critical: false, Healthy: false},
+	}))
+	assert.Equal(t, StatusNotHealthy, overallStatus([]*models.HealthStatus{
+		{Name: "sns:email-topic", Critical: false, Healthy: false},
+		{Name: "s3:bucket", Critical: true, Healthy: false},
+	}))
+}
+
+func TestWithNonCritical(t *testing.T) {
+	checks := WithNonCritical([]Check{
+		{Name: "s3:bucket", Critical: true},
+		{Name: "sns:email-topic", Critical: false},
+		{Name: "platform:user-service", Critical: false},
+		{Name: "platform:acs-service", Critical: false},
+	}, []string{" platform", "s3:bucket"})
+
+	assert.False(t, checks[0].Critical)
+	assert.True(t, checks[1].Critical)
+	assert.False(t, checks[2].Critical)
+	assert.False(t, checks[3].Critical)
+}
+
+func TestReadinessCachesAndTimesOut(t *testing.T) {
+	var calls int32
+	r := newReadiness([]Check{
+		{
+			Name:     "s3:bucket",
+			Critical: true,
+			Probe: func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return errors.New("access denied")
+			},
+		},
+		{
+			Name:    "platform:user-service",
+			Timeout: 10 * time.Millisecond,
+			Probe: func(ctx context.Context) error {
+				<-ctx.Done()
+				return nil
+			},
+		},
+	})
+
+	statuses := r.run()
+	assert.Equal(t, "access denied", statuses[0].Error)
+	assert.Equal(t, errCheckTimeout.Error(), statuses[1].Error)
+	assert.Equal(t, StatusNotHealthy, overallStatus(statuses))
+
+	// The second run reuses the cached results
+	r.run()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -21,20 +21,25 @@ type Service struct {
 	commit    string
 	branch    string
 	buildDate string
+	readiness *readiness
 }
 
 // HealthService interface
 type HealthService interface { // nolint
 	HealthCheck(ctx context.Context) (*models.Health, error)
+	LivenessCheck(ctx context.Context) (*models.Health, error)
+	ReadinessCheck(ctx context.Context) (*models.Health, error)
 }
 
-// New is a simple helper function to create a health service instance
-func New(version, commit, branch, buildDate string) Service {
+// New is a simple helper function to create a health service instance, the checks are the dependencies probed by
+// the readiness check
+func New(version, commit, branch, buildDate string, checks ...Check) Service {
 	return Service{
 		version:   version,
 		commit:    commit,
 		branch:    branch,
 		buildDate: buildDate,
+		readiness: newReadiness(checks),
 	}
 }
 
@@ -53,16 +58,39 @@ func (s Service) HealthCheck(ctx context.Context) (*models.Health, error) {
 	allStatus = append(allStatus, &hs)
 	allStatus = append(allStatus, getDynamoTableStatus()...)
 
-	var status = "healthy"
+	var status = StatusHealthy
 	for _, item := range allStatus {
 		// If any of our dynamodb tables are not healthy, then overall we are not healthy
 		if !item.Healthy {
-			status = "not healthy"
+			status = StatusNotHealthy
 			break
 		}
 	}
 
-	response := models.Health{
+	return s.health(status, allStatus), nil
+}
+
+// LivenessCheck API call reports that the process is up and serving requests, it does not probe the dependencies
+// so a dependency outage does not get the service restarted
+func (s Service) LivenessCheck(ctx context.Context) (*models.Health, error) {
+	hs := models.HealthStatus{
+		TimeStamp: time.Now().UTC().Format(time.RFC3339),
+		Healthy:   true,
+		Name:      "CLA",
+		Duration:  time.Since(time.Now()).String(),
+	}
+	return s.health(StatusHealthy, []*models.HealthStatus{&hs}), nil
+}
+
+// ReadinessCheck API call probes the dependencies of the service - the status is not healthy when a critical
+// dependency is unavailable and degraded when only non-critical dependencies are unavailable
+func (s Service) ReadinessCheck(ctx context.Context) (*models.Health, error) {
+	allStatus := s.readiness.run()
+	return s.health(overallStatus(allStatus), allStatus), nil
+}
+
+func (s Service) health(status string, allStatus []*models.HealthStatus) *models.Health {
+	return &models.Health{
 		Status:         status,
 		TimeStamp:      time.Now().UTC().Format(time.RFC3339),
 		Version:        s.version,
@@ -71,32 +99,14 @@ func (s Service) HealthCheck(ctx context.Context) (*models.Health, error) {
 		BuildTimeStamp: s.buildDate,
 		Healths:        allStatus,
 	}
-
-	return &response, nil
 }
 
 // getDynamoTableStatus queries the dynamodb tables and reports if it is healthy
 func getDynamoTableStatus() []*models.HealthStatus {
 	var allStatus []*models.HealthStatus
+	var allStatusLock sync.Mutex
 
-	tableNames := []string{
-		"cla-" + ini.GetStage() + "-ccla-whitelist-requests",
-		"cla-" + ini.GetStage() + "-cla-manager-requests",
-		"cla-" + ini.GetStage() + "-companies",
-		"cla-" + ini.GetStage() + "-company-invites",
-		"cla-" + ini.GetStage() + "-events",
-		"cla-" + ini.GetStage() + "-gerrit-instances",
-		"cla-" + ini.GetStage() + "-github-orgs",
-		"cla-" + ini.GetStage() + "-metrics",
-		"cla-" + ini.GetStage() + "-projects",
-		"cla-" + ini.GetStage() + "-projects-cla-groups",
-		"cla-" + ini.GetStage() + "-repositories",
-		"cla-" + ini.GetStage() + "-session-store",
-		"cla-" + ini.GetStage() + "-signatures",
-		"cla-" + ini.GetStage() + "-store",
-		"cla-" + ini.GetStage() + "-user-permissions",
-		"cla-" + ini.GetStage() + "-users",
-	}
+	tableNames := CoreTableNames(ini.GetStage())
 
 	var wg sync.WaitGroup
 	wg.Add(len(tableNames))
@@ -114,7 +124,9 @@ func getDynamoTableStatus() []*models.HealthStatus {
 				Name:     "EasyCLA - Dynamodb - " + tableName,
 				Duration: dynamoDuration.String()}
 
+			allStatusLock.Lock()
 			allStatus = append(allStatus, &dy)
+			allStatusLock.Unlock()
 		}(tableName)
 	}
 
@@ -123,6 +135,28 @@ func getDynamoTableStatus() []*models.HealthStatus {
 	return allStatus
 }
 
+// CoreTableNames returns the names of the tables the API can not serve requests without
+func CoreTableNames(stage string) []string {
+	return []string{
+		"cla-" + stage + "-ccla-whitelist-requests",
+		"cla-" + stage + "-cla-manager-requests",
+		"cla-" + stage + "-companies",
+		"cla-" + stage + "-company-invites",
+		"cla-" + stage + "-events",
+		"cla-" + stage + "-gerrit-instances",
+		"cla-" + stage + "-github-orgs",
+		"cla-" + stage + "-metrics",
+		"cla-" + stage + "-projects",
+		"cla-" + stage + "-projects-cla-groups",
+		"cla-" + stage + "-repositories",
+		"cla-" + stage + "-session-store",
+		"cla-" + stage + "-signatures",
+		"cla-" + stage + "-store",
+		"cla-" + stage + "-user-permissions",
+		"cla-" + stage + "-users",
+	}
+}
+
 // isDynamoAlive runs a check to see if we have connectivity to the database for the given table - returns true if successful, false otherwise
 func isDynamoAlive(tableName string) bool {
 	// Grab the AWS session
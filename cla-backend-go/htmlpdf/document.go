@@ -0,0 +1,68 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package htmlpdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+const (
+	catalogObject   = 1
+	pagesObject     = 2
+	infoObject      = 3
+	firstFontObject = 4
+	firstPageObject = firstFontObject + 4
+)
+
+// writeDocument writes the PDF document of the page content streams. The output only depends on the content, there
+// are no dates or ids, so the same HTML always renders to the same document.
+func writeDocument(pages []*bytes.Buffer) []byte {
+	if len(pages) == 0 {
+		pages = []*bytes.Buffer{{}}
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+	writeObject := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n")
+
+	writeObject(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObject))
+
+	kids := make([]string, 0, len(pages))
+	for i := range pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPageObject+2*i))
+	}
+	writeObject(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+
+	writeObject("<< /Producer (EasyCLA) >>")
+
+	fontResources := make([]string, 0, len(baseFonts))
+	for i, baseFont := range baseFonts {
+		writeObject(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", baseFont))
+		fontResources = append(fontResources, fmt.Sprintf("/%s %d 0 R", font(i).resourceName(), firstFontObject+i))
+	}
+
+	for i, page := range pages {
+		writeObject(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+			pagesObject, formatNumber(pageWidth), formatNumber(pageHeight), strings.Join(fontResources, " "), firstPageObject+2*i+1))
+		writeObject(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(offsets)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, catalogObject, infoObject, xref)
+
+	return buf.Bytes()
+}
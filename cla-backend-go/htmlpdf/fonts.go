@@ -0,0 +1,125 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package htmlpdf
+
+// font is one of the standard PDF fonts used by the renderer, the PDF viewers provide them so nothing is embedded
+type font int
+
+const (
+	fontRegular font = iota
+	fontBold
+	fontItalic
+	fontBoldItalic
+)
+
+// baseFonts are the PDF names of the fonts, in the order of the font constants
+var baseFonts = []string{"Helvetica", "Helvetica-Bold", "Helvetica-Oblique", "Helvetica-BoldOblique"}
+
+// resourceName returns the name of the font in the page resources
+func (f font) resourceName() string {
+	return []string{"F1", "F2", "F3", "F4"}[f]
+}
+
+func (f font) isBold() bool {
+	return f == fontBold || f == fontBoldItalic
+}
+
+// helveticaWidths are the widths of the printable ASCII characters (32 to 126) in thousandths of the font size, the
+// oblique font has the same widths
+var helveticaWidths = []int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+	278, 278, 584, 584, 584, 556, 1015,
+	667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+	278, 278, 278, 469, 556, 333,
+	556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500,
+	334, 260, 334, 584,
+}
+
+// helveticaBoldWidths are the widths of the printable ASCII characters of the bold fonts
+var helveticaBoldWidths = []int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+	333, 333, 584, 584, 584, 611, 975,
+	722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+	333, 278, 333, 584, 556, 333,
+	556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+	389, 280, 389, 584,
+}
+
+// winAnsiGlyph is a character of the WinAnsiEncoding outside of ASCII and Latin-1
+type winAnsiGlyph struct {
+	code    byte
+	regular int
+	bold    int
+}
+
+// winAnsiGlyphs are the typographic characters the templates use, e.g. the curly quotes
+var winAnsiGlyphs = map[rune]winAnsiGlyph{
+	'€': {0x80, 556, 556},
+	'‚': {0x82, 222, 278},
+	'ƒ': {0x83, 556, 556},
+	'„': {0x84, 333, 500},
+	'…': {0x85, 1000, 1000},
+	'†': {0x86, 556, 556},
+	'‡': {0x87, 556, 556},
+	'ˆ': {0x88, 333, 333},
+	'‰': {0x89, 1000, 1000},
+	'Š': {0x8A, 667, 667},
+	'‹': {0x8B, 333, 333},
+	'Œ': {0x8C, 1000, 1000},
+	'Ž': {0x8E, 611, 611},
+	'‘': {0x91, 222, 278},
+	'’': {0x92, 222, 278},
+	'“': {0x93, 333, 500},
+	'”': {0x94, 333, 500},
+	'•': {0x95, 350, 350},
+	'–': {0x96, 556, 556},
+	'—': {0x97, 1000, 1000},
+	'˜': {0x98, 333, 333},
+	'™': {0x99, 1000, 1000},
+	'š': {0x9A, 500, 556},
+	'›': {0x9B, 333, 333},
+	'œ': {0x9C, 944, 944},
+	'ž': {0x9E, 500, 500},
+	'Ÿ': {0x9F, 667, 667},
+}
+
+// encode converts the character to its WinAnsiEncoding code and width, the characters the encoding doesn't have are
+// replaced by a question mark
+func (f font) encode(r rune) (byte, int) {
+	widths := helveticaWidths
+	if f.isBold() {
+		widths = helveticaBoldWidths
+	}
+
+	switch {
+	case r >= 32 && r <= 126:
+		return byte(r), widths[r-32]
+	case r == '\u00a0':
+		// the non-breaking space
+		return 0xA0, widths[0]
+	case r > '\u00a0' && r <= '\u00ff':
+		// the Latin-1 letters are close enough to the width of the average lower case letter
+		return byte(r), widths['o'-32]
+	}
+
+	if glyph, ok := winAnsiGlyphs[r]; ok {
+		if f.isBold() {
+			return glyph.code, glyph.bold
+		}
+		return glyph.code, glyph.regular
+	}
+	return '?', widths['?'-32]
+}
+
+// textWidth returns the width of the text in points
+func (f font) textWidth(text string, size float64) float64 {
+	var width int
+	for _, r := range text {
+		_, w := f.encode(r)
+		width += w
+	}
+	return float64(width) * size / 1000
+}
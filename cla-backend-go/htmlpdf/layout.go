@@ -0,0 +1,506 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package htmlpdf
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// US Letter, in points
+	pageWidth  = 612.0
+	pageHeight = 792.0
+	pageMargin = 72.0
+
+	defaultFontSize = 11.0
+	// lineHeight is relative to the font size
+	lineHeight = 1.3
+	// blockMargin is the space around the paragraphs, headings and lists, relative to the font size
+	blockMargin = 0.8
+	listIndent  = 18.0
+	quoteIndent = 36.0
+)
+
+// headingSizes are the font sizes of the headings
+var headingSizes = map[string]float64{"h1": 20, "h2": 16, "h3": 13, "h4": 11, "h5": 10, "h6": 9}
+
+// blockElements start a new paragraph, the other elements are laid out inline
+var blockElements = map[string]bool{
+	"html": true, "body": true, "div": true, "p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true,
+	"h6": true, "ul": true, "ol": true, "li": true, "blockquote": true, "pre": true, "hr": true, "center": true,
+	"section": true, "article": true, "header": true, "footer": true, "address": true, "dl": true, "dt": true,
+	"dd": true, "table": true, "thead": true, "tbody": true, "tfoot": true, "tr": true, "td": true, "th": true,
+}
+
+// marginElements are the block elements with space above and below
+var marginElements = map[string]bool{
+	"p": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "ul": true, "ol": true,
+	"blockquote": true, "pre": true, "dl": true, "table": true,
+}
+
+// hiddenElements have no visible content
+var hiddenElements = map[string]bool{"head": true, "title": true, "style": true, "script": true}
+
+type alignment int
+
+const (
+	alignLeft alignment = iota
+	alignCenter
+	alignRight
+	alignJustify
+)
+
+// block is an open block element with the formatting it applies to its content
+type block struct {
+	name       string
+	align      alignment
+	size       float64
+	bold       bool
+	indent     float64
+	breakAfter bool
+	// ordered and items are set for the lists
+	isList  bool
+	ordered bool
+	items   int
+}
+
+// segment is a text in a single font
+type segment struct {
+	font font
+	text string
+}
+
+// word is the unit of the line breaking, a word has several segments when the font changes within the word
+type word struct {
+	segments    []segment
+	spaceBefore bool
+	// lineBreak is a forced line break, it has no segments
+	lineBreak bool
+}
+
+func (w *word) width(size float64) float64 {
+	var width float64
+	for _, seg := range w.segments {
+		width += seg.font.textWidth(seg.text, size)
+	}
+	return width
+}
+
+// layout places the content of the HTML document on the pages, the text is written to the content streams of the
+// pages as it's laid out
+type layout struct {
+	pages  []*bytes.Buffer
+	blocks []*block
+	words  []*word
+
+	// y is the top of the next line on the current page
+	y float64
+	// gap is the vertical space before the next line, it is dropped at the top of a page
+	gap          float64
+	breakPending bool
+
+	bold         int
+	italic       int
+	hidden       int
+	pendingSpace bool
+}
+
+func newLayout() *layout {
+	return &layout{
+		blocks: []*block{{align: alignLeft, size: defaultFontSize}},
+	}
+}
+
+func (l *layout) current() *block {
+	return l.blocks[len(l.blocks)-1]
+}
+
+func (l *layout) handle(t token) {
+	switch t.kind {
+	case textToken:
+		if l.hidden == 0 {
+			l.addText(t.text)
+		}
+	case startTagToken:
+		switch {
+		case hiddenElements[t.name]:
+			l.hidden++
+		case t.name == "br":
+			l.addLineBreak()
+		case t.name == "b" || t.name == "strong":
+			l.bold++
+		case t.name == "i" || t.name == "em":
+			l.italic++
+		case blockElements[t.name]:
+			l.openBlock(t)
+		}
+	case endTagToken:
+		switch {
+		case hiddenElements[t.name]:
+			if l.hidden > 0 {
+				l.hidden--
+			}
+		case t.name == "br":
+			// the browsers treat </br> as a line break too, the templates rely on it
+			l.addLineBreak()
+		case t.name == "b" || t.name == "strong":
+			if l.bold > 0 {
+				l.bold--
+			}
+		case t.name == "i" || t.name == "em":
+			if l.italic > 0 {
+				l.italic--
+			}
+		case blockElements[t.name]:
+			l.closeBlock(t.name)
+		}
+	}
+}
+
+func (l *layout) openBlock(t token) {
+	l.flush()
+
+	parent := l.current()
+	b := &block{
+		name:   t.name,
+		align:  parent.align,
+		size:   parent.size,
+		bold:   parent.bold,
+		indent: parent.indent,
+	}
+	if size, ok := headingSizes[t.name]; ok {
+		b.size = size
+		b.bold = true
+	}
+	switch t.name {
+	case "ul", "ol":
+		b.isList = true
+		b.ordered = t.name == "ol"
+		b.indent += listIndent
+	case "blockquote":
+		b.indent += quoteIndent
+	case "center":
+		b.align = alignCenter
+	case "th":
+		b.bold = true
+	}
+
+	style := parseStyle(t.attrs["style"])
+	switch style["text-align"] {
+	case "left":
+		b.align = alignLeft
+	case "center":
+		b.align = alignCenter
+	case "right":
+		b.align = alignRight
+	case "justify":
+		b.align = alignJustify
+	}
+	if style["page-break-before"] == "always" {
+		l.breakPending = len(l.pages) > 0
+	}
+	b.breakAfter = style["page-break-after"] == "always"
+
+	if marginElements[t.name] {
+		l.gap = maxFloat(l.gap, b.size*blockMargin)
+	}
+	l.blocks = append(l.blocks, b)
+
+	if t.name == "li" {
+		l.addText(l.listMarker() + " ")
+	}
+}
+
+// listMarker returns the bullet or the number of the new item of the innermost list
+func (l *layout) listMarker() string {
+	for i := len(l.blocks) - 1; i >= 0; i-- {
+		list := l.blocks[i]
+		if !list.isList {
+			continue
+		}
+		list.items++
+		if list.ordered {
+			return strconv.Itoa(list.items) + "."
+		}
+		break
+	}
+	return "•"
+}
+
+func (l *layout) closeBlock(name string) {
+	// the root block can't be closed, an end tag without an open element is ignored
+	index := -1
+	for i := len(l.blocks) - 1; i > 0; i-- {
+		if l.blocks[i].name == name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return
+	}
+
+	l.flush()
+	for i := len(l.blocks) - 1; i >= index; i-- {
+		b := l.blocks[i]
+		if marginElements[b.name] {
+			l.gap = maxFloat(l.gap, b.size*blockMargin)
+		}
+		if b.breakAfter && len(l.pages) > 0 {
+			l.breakPending = true
+		}
+	}
+	l.blocks = l.blocks[:index]
+}
+
+func (l *layout) font() font {
+	bold := l.bold > 0 || l.current().bold
+	switch {
+	case bold && l.italic > 0:
+		return fontBoldItalic
+	case bold:
+		return fontBold
+	case l.italic > 0:
+		return fontItalic
+	}
+	return fontRegular
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f'
+}
+
+// addText adds the words of the text to the paragraph, the white space is collapsed like the browsers do
+func (l *layout) addText(text string) {
+	if text == "" {
+		return
+	}
+	f := l.font()
+	if isSpace(rune(text[0])) {
+		l.pendingSpace = true
+	}
+
+	for i, field := range strings.FieldsFunc(text, isSpace) {
+		spaceBefore := l.pendingSpace || i > 0
+		l.pendingSpace = false
+
+		if !spaceBefore && len(l.words) > 0 && !l.words[len(l.words)-1].lineBreak {
+			// the text continues the previous word, e.g. after a change of the font
+			last := l.words[len(l.words)-1]
+			if seg := &last.segments[len(last.segments)-1]; seg.font == f {
+				seg.text += field
+			} else {
+				last.segments = append(last.segments, segment{font: f, text: field})
+			}
+			continue
+		}
+		l.words = append(l.words, &word{segments: []segment{{font: f, text: field}}, spaceBefore: spaceBefore})
+	}
+
+	if isSpace(rune(text[len(text)-1])) {
+		l.pendingSpace = true
+	}
+}
+
+func (l *layout) addLineBreak() {
+	l.words = append(l.words, &word{lineBreak: true})
+	l.pendingSpace = false
+}
+
+// flush lays out the words of the current paragraph
+func (l *layout) flush() {
+	words := l.words
+	l.words = nil
+	l.pendingSpace = false
+	if len(words) == 0 {
+		return
+	}
+
+	b := l.current()
+	maxWidth := pageWidth - 2*pageMargin - b.indent
+	lines := breakLines(words, b.size, maxWidth)
+	for i, line := range lines {
+		l.writeLine(line, b, maxWidth, i == len(lines)-1)
+	}
+}
+
+// breakLines fills the lines with as many words as fit, the words wider than the line are split
+func breakLines(words []*word, size, maxWidth float64) [][]*word {
+	spaceWidth := fontRegular.textWidth(" ", size)
+
+	var lines [][]*word
+	var current []*word
+	var width float64
+	for _, w := range words {
+		if w.lineBreak {
+			lines = append(lines, current)
+			current, width = nil, 0
+			continue
+		}
+
+		for _, part := range splitWord(w, size, maxWidth) {
+			partWidth := part.width(size)
+			if len(current) > 0 && part.spaceBefore {
+				partWidth += spaceWidth
+			}
+			if len(current) > 0 && width+partWidth > maxWidth {
+				lines = append(lines, current)
+				current, width = nil, 0
+				partWidth = part.width(size)
+			}
+			current = append(current, part)
+			width += partWidth
+		}
+	}
+	if len(current) > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// splitWord splits a word which doesn't fit on a line into parts which fit
+func splitWord(w *word, size, maxWidth float64) []*word {
+	if w.width(size) <= maxWidth {
+		return []*word{w}
+	}
+
+	parts := []*word{{spaceBefore: w.spaceBefore}}
+	var width float64
+	for _, seg := range w.segments {
+		var text strings.Builder
+		for _, r := range seg.text {
+			runeWidth := seg.font.textWidth(string(r), size)
+			if width+runeWidth > maxWidth && width > 0 {
+				part := parts[len(parts)-1]
+				if text.Len() > 0 {
+					part.segments = append(part.segments, segment{font: seg.font, text: text.String()})
+					text.Reset()
+				}
+				parts = append(parts, &word{})
+				width = 0
+			}
+			text.WriteRune(r)
+			width += runeWidth
+		}
+		if text.Len() > 0 {
+			part := parts[len(parts)-1]
+			part.segments = append(part.segments, segment{font: seg.font, text: text.String()})
+		}
+	}
+	return parts
+}
+
+// writeLine writes the text of the line to the content stream of the page, starting a new page if needed
+func (l *layout) writeLine(line []*word, b *block, maxWidth float64, isLast bool) {
+	height := b.size * lineHeight
+	if len(l.pages) == 0 || l.breakPending || l.y+l.gap+height > pageHeight-pageMargin {
+		l.pages = append(l.pages, &bytes.Buffer{})
+		l.y = pageMargin
+		l.breakPending = false
+	} else {
+		l.y += l.gap
+	}
+	l.gap = 0
+	// the baseline leaves the half of the leading above the ascent of the font
+	baseline := pageHeight - (l.y + b.size)
+	l.y += height
+
+	if len(line) == 0 {
+		return
+	}
+
+	spaceWidth := fontRegular.textWidth(" ", b.size)
+	var width float64
+	var spaces int
+	for i, w := range line {
+		if i > 0 && w.spaceBefore {
+			width += spaceWidth
+			spaces++
+		}
+		width += w.width(b.size)
+	}
+
+	x := pageMargin + b.indent
+	var wordSpacing float64
+	switch b.align {
+	case alignCenter:
+		x += (maxWidth - width) / 2
+	case alignRight:
+		x += maxWidth - width
+	case alignJustify:
+		// the last line of a paragraph isn't stretched
+		if !isLast && spaces > 0 {
+			wordSpacing = (maxWidth - width) / float64(spaces)
+		}
+	}
+
+	// the words are merged into runs of the same font, the spaces are part of the runs - all the fonts have the
+	// same space width and the word spacing applies to the spaces within the strings
+	type run struct {
+		font font
+		x    float64
+		text strings.Builder
+	}
+	var runs []*run
+	for i, w := range line {
+		if i > 0 && w.spaceBefore {
+			runs[len(runs)-1].text.WriteByte(' ')
+			x += spaceWidth + wordSpacing
+		}
+		for _, seg := range w.segments {
+			if len(runs) == 0 || runs[len(runs)-1].font != seg.font {
+				runs = append(runs, &run{font: seg.font, x: x})
+			}
+			runs[len(runs)-1].text.WriteString(seg.text)
+			x += seg.font.textWidth(seg.text, b.size)
+		}
+	}
+
+	page := l.pages[len(l.pages)-1]
+	page.WriteString("BT\n")
+	if wordSpacing != 0 {
+		fmt.Fprintf(page, "%s Tw\n", formatNumber(wordSpacing))
+	}
+	for _, r := range runs {
+		fmt.Fprintf(page, "/%s %s Tf 1 0 0 1 %s %s Tm (%s) Tj\n", r.font.resourceName(), formatNumber(b.size),
+			formatNumber(r.x), formatNumber(baseline), encodeString(r.font, r.text.String()))
+	}
+	if wordSpacing != 0 {
+		page.WriteString("0 Tw\n")
+	}
+	page.WriteString("ET\n")
+}
+
+// encodeString encodes the text in the WinAnsiEncoding of the font and escapes it for a PDF string, the non ASCII
+// bytes are written as octal escapes so the content streams remain plain text
+func encodeString(f font, text string) string {
+	var sb strings.Builder
+	for _, r := range text {
+		code, _ := f.encode(r)
+		switch {
+		case code == '(' || code == ')' || code == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(code)
+		case code < 32 || code > 126:
+			fmt.Fprintf(&sb, "\\%03o", code)
+		default:
+			sb.WriteByte(code)
+		}
+	}
+	return sb.String()
+}
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,147 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package htmlpdf
+
+import (
+	"html"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	startTagToken
+	endTagToken
+)
+
+// token is a piece of the HTML document, the text of the text tokens is unescaped
+type token struct {
+	kind  tokenKind
+	name  string
+	attrs map[string]string
+	text  string
+}
+
+// tokenize splits the HTML document into text and tags, the comments, doctype and processing instructions are
+// dropped. It is lenient like the browsers, e.g. a tag which isn't closed is treated as text.
+func tokenize(doc string) []token {
+	var tokens []token
+	for len(doc) > 0 {
+		start := strings.IndexByte(doc, '<')
+		if start < 0 {
+			tokens = append(tokens, token{kind: textToken, text: html.UnescapeString(doc)})
+			break
+		}
+		if start > 0 {
+			tokens = append(tokens, token{kind: textToken, text: html.UnescapeString(doc[:start])})
+			doc = doc[start:]
+		}
+
+		if strings.HasPrefix(doc, "<!--") {
+			end := strings.Index(doc, "-->")
+			if end < 0 {
+				break
+			}
+			doc = doc[end+len("-->"):]
+			continue
+		}
+
+		end := strings.IndexByte(doc, '>')
+		if end < 0 {
+			tokens = append(tokens, token{kind: textToken, text: html.UnescapeString(doc)})
+			break
+		}
+		tag := doc[1:end]
+		doc = doc[end+1:]
+
+		switch {
+		case strings.HasPrefix(tag, "!"), strings.HasPrefix(tag, "?"):
+			continue
+		case strings.HasPrefix(tag, "/"):
+			tokens = append(tokens, token{kind: endTagToken, name: tagName(tag[1:])})
+		default:
+			name := tagName(tag)
+			if name == "" {
+				tokens = append(tokens, token{kind: textToken, text: "<" + html.UnescapeString(tag) + ">"})
+				continue
+			}
+			tokens = append(tokens, token{kind: startTagToken, name: name, attrs: parseAttributes(tag[len(name):])})
+			// a self closing tag like <div/> is an empty element
+			if strings.HasSuffix(tag, "/") && !voidElements[name] {
+				tokens = append(tokens, token{kind: endTagToken, name: name})
+			}
+		}
+	}
+	return tokens
+}
+
+// voidElements are the elements without content and end tag
+var voidElements = map[string]bool{
+	"br": true, "hr": true, "img": true, "input": true, "meta": true, "link": true, "col": true, "wbr": true,
+}
+
+// tagName returns the lower case name at the start of the tag
+func tagName(tag string) string {
+	end := strings.IndexFunc(tag, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-')
+	})
+	if end < 0 {
+		end = len(tag)
+	}
+	return strings.ToLower(tag[:end])
+}
+
+// parseAttributes parses the attributes of a tag, the names are lower cased
+func parseAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+	for {
+		s = strings.TrimLeft(s, " \t\r\n\f/")
+		if s == "" {
+			return attrs
+		}
+
+		end := strings.IndexAny(s, " \t\r\n\f/=")
+		if end < 0 {
+			end = len(s)
+		}
+		name := strings.ToLower(s[:end])
+		s = strings.TrimLeft(s[end:], " \t\r\n\f")
+		if !strings.HasPrefix(s, "=") {
+			attrs[name] = ""
+			continue
+		}
+
+		s = strings.TrimLeft(s[1:], " \t\r\n\f")
+		var value string
+		if s != "" && (s[0] == '"' || s[0] == '\'') {
+			end = strings.IndexByte(s[1:], s[0])
+			if end < 0 {
+				value, s = s[1:], ""
+			} else {
+				value, s = s[1:end+1], s[end+2:]
+			}
+		} else {
+			end = strings.IndexAny(s, " \t\r\n\f")
+			if end < 0 {
+				end = len(s)
+			}
+			value, s = s[:end], s[end:]
+		}
+		attrs[name] = html.UnescapeString(value)
+	}
+}
+
+// parseStyle returns the declarations of a style attribute, the property names and values are lower cased
+func parseStyle(style string) map[string]string {
+	declarations := make(map[string]string)
+	for _, declaration := range strings.Split(style, ";") {
+		parts := strings.SplitN(declaration, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		declarations[strings.ToLower(strings.TrimSpace(parts[0]))] = strings.ToLower(strings.TrimSpace(parts[1]))
+	}
+	return declarations
+}
@@ -0,0 +1,59 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+// Package htmlpdf renders HTML documents to PDF in-process. It supports the subset of HTML the CLA templates use -
+// paragraphs, headings, lists, line breaks, bold and italic text, text alignment and page breaks - and lays the text
+// out with the standard Helvetica fonts on US Letter pages.
+//
+// The renderer is experimental and meant for the offline dev environments - DocRaptor stays the renderer of the
+// deployed stages.
+package htmlpdf
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+
+	"github.com/sirupsen/logrus"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Renderer is the experimental self-hosted alternative to the DocRaptor client
+type Renderer struct {
+}
+
+// NewRenderer creates a new in-process PDF renderer
+func NewRenderer() Renderer {
+	return Renderer{}
+}
+
+// CreatePDF accepts an HTML document and returns a PDF
+func (r Renderer) CreatePDF(ctx context.Context, html string, claType string) (io.ReadCloser, error) {
+	f := logrus.Fields{
+		"functionName":   "htmlpdf.CreatePDF",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claType":        claType,
+	}
+
+	_, span := telemetry.StartSpan(ctx, "htmlpdf.CreatePDF")
+	defer span.End()
+	span.SetAttributes(attribute.String("cla_type", claType))
+
+	log.WithFields(f).Debug("Generating PDF using the local renderer...")
+	return ioutil.NopCloser(bytes.NewReader(Render(html))), nil
+}
+
+// Render lays out the HTML document and returns the PDF document
+func Render(html string) []byte {
+	l := newLayout()
+	for _, t := range tokenize(html) {
+		l.handle(t)
+	}
+	l.flush()
+	return writeDocument(l.pages)
+}
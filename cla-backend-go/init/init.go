@@ -25,6 +25,11 @@ var (
 // CommonInit initializes the common properties
 func CommonInit() {
 	stage = GetProperty("STAGE")
+
+	// Optional local configuration file - when set the configuration is read from it instead of AWS SSM
+	if err := viper.BindEnv("CONFIG_FILE"); err == nil {
+		configFile = viper.GetString("CONFIG_FILE")
+	}
 }
 
 // GetProperty is a common routine to bind and return the specified environment variable
@@ -61,6 +61,7 @@ func buildCLAGroupDocumentModels(dbDocumentModels []DBProjectDocumentModel) []mo
 			DocumentMajorVersion:    dbDocumentModel.DocumentMajorVersion,
 			DocumentMinorVersion:    dbDocumentModel.DocumentMinorVersion,
 			DocumentCreationDate:    dbDocumentModel.DocumentCreationDate,
+			DocumentTemplateVersion: dbDocumentModel.DocumentTemplateVersion,
 		})
 	}
 
@@ -164,3 +165,19 @@ func GetCurrentDocument(ctx context.Context, docs []models.ClaGroupDocument) (mo
 
 	return currentDoc, nil
 }
+
+// buildCLAGroupExemptionModels builds response models based on the array of db models
+func buildCLAGroupExemptionModels(dbExemptionModels []DBExemptionModel) []*models.ClaGroupExemption {
+	response := []*models.ClaGroupExemption{}
+	for _, dbExemptionModel := range dbExemptionModels {
+		response = append(response, &models.ClaGroupExemption{
+			ExemptionID: dbExemptionModel.ExemptionID,
+			Type:        dbExemptionModel.Type,
+			Pattern:     dbExemptionModel.Pattern,
+			Description: dbExemptionModel.Description,
+			CreatedBy:   dbExemptionModel.CreatedBy,
+			DateCreated: dbExemptionModel.DateCreated,
+		})
+	}
+	return response
+}
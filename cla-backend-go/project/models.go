@@ -23,6 +23,7 @@ type DBProjectModel struct {
 	ProjectIndividualDocuments       []DBProjectDocumentModel `dynamodbav:"project_individual_documents"`
 	ProjectMemberDocuments           []DBProjectDocumentModel `dynamodbav:"project_member_documents"`
 	ProjectACL                       []string                 `dynamodbav:"project_acl"`
+	Exemptions                       []DBExemptionModel       `dynamodbav:"exemptions"`
 }
 
 // DBProjectDocumentModel is a data model for the CLA Group Project documents
@@ -37,4 +38,15 @@ type DBProjectDocumentModel struct {
 	DocumentMajorVersion    string `dynamodbav:"document_major_version"`
 	DocumentMinorVersion    string `dynamodbav:"document_minor_version"`
 	DocumentCreationDate    string `dynamodbav:"document_creation_date"`
+	DocumentTemplateVersion int64  `dynamodbav:"document_template_version"`
+}
+
+// DBExemptionModel is a data model for the contributor identities the CLA Group exempts from signing
+type DBExemptionModel struct {
+	ExemptionID string `dynamodbav:"exemption_id"`
+	Type        string `dynamodbav:"type"`
+	Pattern     string `dynamodbav:"pattern"`
+	Description string `dynamodbav:"description"`
+	CreatedBy   string `dynamodbav:"created_by"`
+	DateCreated string `dynamodbav:"date_created"`
 }
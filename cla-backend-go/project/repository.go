@@ -23,18 +23,20 @@ import (
 	"github.com/gofrs/uuid"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // errors
 var (
 	ErrProjectDoesNotExist = errors.New("project does not exist")
 	ErrProjectIDMissing    = errors.New("project id is missing")
+	ErrExemptionNotFound   = errors.New("exemption not found")
 )
 
 // constants
@@ -57,12 +59,14 @@ type ProjectRepository interface { //nolint
 	GetClaGroupsByFoundationSFID(ctx context.Context, foundationSFID string, loadRepoDetails bool) (*models.ClaGroups, error)
 	GetClaGroupByProjectSFID(ctx context.Context, projectSFID string, loadRepoDetails bool) (*models.ClaGroup, error)
 	UpdateRootCLAGroupRepositoriesCount(ctx context.Context, claGroupID string, diff int64, reset bool) error
+	AddCLAGroupExemption(ctx context.Context, claGroupID string, exemption *models.ClaGroupExemption) (*models.ClaGroupExemption, error)
+	DeleteCLAGroupExemption(ctx context.Context, claGroupID string, exemptionID string) error
 }
 
 // NewRepository creates instance of project repository
-func NewRepository(awsSession *session.Session, stage string, ghRepo repositories.Repository, gerritRepo gerrits.Repository, projectClaGroupRepo projects_cla_groups.Repository) ProjectRepository {
+func NewRepository(dynamoDBClient storage.Driver, stage string, ghRepo repositories.Repository, gerritRepo gerrits.Repository, projectClaGroupRepo projects_cla_groups.Repository) ProjectRepository {
 	return &repo{
-		dynamoDBClient:      dynamodb.New(awsSession),
+		dynamoDBClient:      dynamoDBClient,
 		stage:               stage,
 		ghRepo:              ghRepo,
 		gerritRepo:          gerritRepo,
@@ -73,7 +77,7 @@ func NewRepository(awsSession *session.Session, stage string, ghRepo repositorie
 
 type repo struct {
 	stage               string
-	dynamoDBClient      *dynamodb.DynamoDB
+	dynamoDBClient      storage.Driver
 	ghRepo              repositories.Repository
 	gerritRepo          gerrits.Repository
 	projectClaGroupRepo projects_cla_groups.Repository
@@ -131,7 +135,7 @@ func (repo *repo) CreateCLAGroup(ctx context.Context, claGroupModel *models.ClaG
 	}
 	addStringAttribute(input.Item, "version", claGroupModel.Version)
 
-	_, err = repo.dynamoDBClient.PutItem(input)
+	_, err = repo.dynamoDBClient.PutItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).Warnf("Unable to create a new CLA Group record, error: %v", err)
 		return nil, err
@@ -174,7 +178,7 @@ func (repo *repo) getCLAGroupByID(ctx context.Context, claGroupID string, loadCL
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving cla group by claGroupID: %s, error: %v", claGroupID, queryErr)
 		return nil, queryErr
@@ -258,7 +262,7 @@ func (repo *repo) GetCLAGroupsByExternalID(ctx context.Context, params *project.
 
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving projects, error: %v", errQuery)
 			return nil, errQuery
@@ -330,7 +334,7 @@ func (repo *repo) GetClaGroupsByFoundationSFID(ctx context.Context, foundationSF
 
 	var projects []models.ClaGroup
 	for {
-		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		results, errQuery := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving projects, error: %v", errQuery)
 			return nil, errQuery
@@ -410,7 +414,7 @@ func (repo *repo) GetCLAGroupByName(ctx context.Context, projectName string) (*m
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving project by projectName: %s, error: %v", projectName, queryErr)
 		return nil, queryErr
@@ -469,7 +473,7 @@ func (repo *repo) GetExternalCLAGroup(ctx context.Context, projectExternalID str
 	}
 
 	// Make the DynamoDB Query API call
-	results, queryErr := repo.dynamoDBClient.Query(queryInput)
+	results, queryErr := repo.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if queryErr != nil {
 		log.WithFields(f).Warnf("error retrieving project by projectExternalID: %s, error: %v", projectExternalID, queryErr)
 		return nil, queryErr
@@ -553,7 +557,7 @@ func (repo *repo) GetCLAGroups(ctx context.Context, params *project.GetProjectsP
 
 	// Loop until we have all the records
 	for ok := true; ok; ok = lastEvaluatedKey != "" {
-		results, errQuery := repo.dynamoDBClient.Scan(scanInput)
+		results, errQuery := repo.dynamoDBClient.ScanWithContext(ctx, scanInput)
 		if errQuery != nil {
 			log.WithFields(f).Warnf("error retrieving projects, error: %v", errQuery)
 			return nil, errQuery
@@ -615,7 +619,7 @@ func (repo *repo) DeleteCLAGroup(ctx context.Context, claGroupID string) error {
 
 	var deleteErr error
 	// Perform the delete
-	_, deleteErr = repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+	_, deleteErr = repo.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(repo.claGroupTable),
 		Key: map[string]*dynamodb.AttributeValue{
 			"project_id": {
@@ -740,7 +744,7 @@ func (repo *repo) UpdateCLAGroup(ctx context.Context, claGroupModel *models.ClaG
 	//log.Debugf("Update input: %+V", updateInput.GoString())
 
 	// Make the DynamoDB Update API call
-	_, updateErr := repo.dynamoDBClient.UpdateItem(updateInput)
+	_, updateErr := repo.dynamoDBClient.UpdateItemWithContext(ctx, updateInput)
 	if updateErr != nil {
 		log.WithFields(f).Warnf("error updating CLAGroup by claGroupID: %s, error: %v", claGroupModel.ProjectID, updateErr)
 		return nil, updateErr
@@ -793,7 +797,7 @@ func (repo *repo) UpdateRootCLAGroupRepositoriesCount(ctx context.Context, claGr
 		TableName: aws.String(repo.claGroupTable),
 	}
 
-	_, err := repo.dynamoDBClient.UpdateItem(input)
+	_, err := repo.dynamoDBClient.UpdateItemWithContext(ctx, input)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("unable to update repositories count")
 	}
@@ -801,6 +805,123 @@ func (repo *repo) UpdateRootCLAGroupRepositoriesCount(ctx context.Context, claGr
 	return err
 }
 
+// AddCLAGroupExemption appends the exemption to the exemptions of the CLA Group, the exemption ID and creation date
+// are assigned
+func (repo *repo) AddCLAGroupExemption(ctx context.Context, claGroupID string, exemption *models.ClaGroupExemption) (*models.ClaGroupExemption, error) {
+	f := logrus.Fields{
+		"functionName":   "AddCLAGroupExemption",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"exemptionType":  exemption.Type,
+		"pattern":        exemption.Pattern,
+		"tableName":      repo.claGroupTable,
+	}
+
+	exemptionID, err := uuid.NewV4()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to generate a UUID for the exemption")
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	dbModel := DBExemptionModel{
+		ExemptionID: exemptionID.String(),
+		Type:        exemption.Type,
+		Pattern:     exemption.Pattern,
+		Description: exemption.Description,
+		CreatedBy:   exemption.CreatedBy,
+		DateCreated: now,
+	}
+	value, err := dynamodbattribute.Marshal([]DBExemptionModel{dbModel})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshal the exemption")
+		return nil, err
+	}
+
+	log.WithFields(f).Debug("adding CLA Group exemption")
+	_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"project_id": {S: aws.String(claGroupID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#exemptions":   aws.String("exemptions"),
+			"#dateModified": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":exemptionValue":    value,
+			":emptyList":         {L: []*dynamodb.AttributeValue{}},
+			":dateModifiedValue": {S: aws.String(now)},
+		},
+		ConditionExpression: aws.String("attribute_exists(project_id)"),
+		UpdateExpression:    aws.String("SET #exemptions = list_append(if_not_exists(#exemptions, :emptyList), :exemptionValue), #dateModified = :dateModifiedValue"),
+		TableName:           aws.String(repo.claGroupTable),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return nil, &utils.CLAGroupNotFound{CLAGroupID: claGroupID}
+		}
+		log.WithFields(f).WithError(err).Warn("error adding CLA Group exemption")
+		return nil, err
+	}
+
+	return buildCLAGroupExemptionModels([]DBExemptionModel{dbModel})[0], nil
+}
+
+// DeleteCLAGroupExemption removes the exemption from the exemptions of the CLA Group
+func (repo *repo) DeleteCLAGroupExemption(ctx context.Context, claGroupID string, exemptionID string) error {
+	f := logrus.Fields{
+		"functionName":   "DeleteCLAGroupExemption",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"exemptionID":    exemptionID,
+		"tableName":      repo.claGroupTable,
+	}
+
+	claGroup, err := repo.GetCLAGroupByID(ctx, claGroupID, DontLoadRepoDetails)
+	if err != nil {
+		return err
+	}
+	index := -1
+	for i, exemption := range claGroup.Exemptions {
+		if exemption != nil && exemption.ExemptionID == exemptionID {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return ErrExemptionNotFound
+	}
+
+	_, now := utils.CurrentTime()
+	log.WithFields(f).Debugf("removing CLA Group exemption at index %d", index)
+	// the condition makes sure the list didn't change since it was loaded
+	_, err = repo.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"project_id": {S: aws.String(claGroupID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#exemptions":   aws.String("exemptions"),
+			"#exemptionID":  aws.String("exemption_id"),
+			"#dateModified": aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":exemptionIDValue":  {S: aws.String(exemptionID)},
+			":dateModifiedValue": {S: aws.String(now)},
+		},
+		ConditionExpression: aws.String(fmt.Sprintf("#exemptions[%d].#exemptionID = :exemptionIDValue", index)),
+		UpdateExpression:    aws.String(fmt.Sprintf("REMOVE #exemptions[%d] SET #dateModified = :dateModifiedValue", index)),
+		TableName:           aws.String(repo.claGroupTable),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrExemptionNotFound
+		}
+		log.WithFields(f).WithError(err).Warn("error removing CLA Group exemption")
+		return err
+	}
+
+	return nil
+}
+
 // buildCLAGroupModels converts the database response model into an API response data model
 func (repo *repo) buildCLAGroupModels(ctx context.Context, results []map[string]*dynamodb.AttributeValue, loadRepoDetails bool) ([]models.ClaGroup, error) {
 	var projects []models.ClaGroup
@@ -891,6 +1012,7 @@ func (repo *repo) buildCLAGroupModel(ctx context.Context, dbModel DBProjectModel
 		ProjectCorporateDocuments:    buildCLAGroupDocumentModels(dbModel.ProjectCorporateDocuments),
 		ProjectIndividualDocuments:   buildCLAGroupDocumentModels(dbModel.ProjectIndividualDocuments),
 		ProjectMemberDocuments:       buildCLAGroupDocumentModels(dbModel.ProjectMemberDocuments),
+		Exemptions:                   buildCLAGroupExemptionModels(dbModel.Exemptions),
 		GithubRepositories:           ghOrgs,
 		Gerrits:                      gerrits,
 		DateCreated:                  dbModel.DateCreated,
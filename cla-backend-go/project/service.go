@@ -38,6 +38,8 @@ type Service interface {
 	GetClaGroupByProjectSFID(ctx context.Context, projectSFID string, loadRepoDetails bool) (*models.ClaGroup, error)
 	SignedAtFoundationLevel(ctx context.Context, foundationSFID string) (bool, error)
 	GetCLAManagers(ctx context.Context, claGroupID string) ([]*models.ClaManagerUser, error)
+	AddCLAGroupExemption(ctx context.Context, claGroupID string, exemption *models.ClaGroupExemption) (*models.ClaGroupExemption, error)
+	DeleteCLAGroupExemption(ctx context.Context, claGroupID string, exemptionID string) error
 }
 
 // service
@@ -318,6 +320,16 @@ func (s service) UpdateCLAGroup(ctx context.Context, claGroupModel *models.ClaGr
 	return s.repo.UpdateCLAGroup(ctx, claGroupModel)
 }
 
+// AddCLAGroupExemption service method
+func (s service) AddCLAGroupExemption(ctx context.Context, claGroupID string, exemption *models.ClaGroupExemption) (*models.ClaGroupExemption, error) {
+	return s.repo.AddCLAGroupExemption(ctx, claGroupID, exemption)
+}
+
+// DeleteCLAGroupExemption service method
+func (s service) DeleteCLAGroupExemption(ctx context.Context, claGroupID string, exemptionID string) error {
+	return s.repo.DeleteCLAGroupExemption(ctx, claGroupID, exemptionID)
+}
+
 // GetClaGroupsByFoundationSFID service method
 func (s service) GetClaGroupsByFoundationSFID(ctx context.Context, foundationSFID string, loadRepoDetails bool) (*models.ClaGroups, error) {
 	return s.repo.GetClaGroupsByFoundationSFID(ctx, foundationSFID, loadRepoDetails)
@@ -16,13 +16,13 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // constants
@@ -58,15 +58,15 @@ type Repository interface {
 
 type repo struct {
 	tableName      string
-	dynamoDBClient *dynamodb.DynamoDB
+	dynamoDBClient storage.Driver
 	stage          string
 }
 
 // NewRepository provides implementation of projects_cla_group repository
-func NewRepository(awsSession *session.Session, stage string) Repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
 	return &repo{
 		tableName:      fmt.Sprintf("cla-%s-projects-cla-groups", stage),
-		dynamoDBClient: dynamodb.New(awsSession),
+		dynamoDBClient: dynamoDBClient,
 		stage:          stage,
 	}
 }
@@ -0,0 +1,62 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package repositories
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// enforcement modes - what the commit authors of the pull requests of a repository need before the pull request can
+// be merged
+const (
+	// EnforcementModeCLA requires the commit authors to be covered by a signed CLA, the default
+	EnforcementModeCLA = "cla"
+	// EnforcementModeDCO requires the commits to be signed off by their author (Developer Certificate of Origin)
+	EnforcementModeDCO = "dco"
+	// EnforcementModeCLAOrDCO requires each commit to be either covered by a signed CLA or signed off by its author
+	EnforcementModeCLAOrDCO = "cla-or-dco"
+)
+
+// DCOStatusCheckName is the name of the status check of the repositories in the DCO enforcement mode, the other
+// modes use the EasyCLA status check
+const DCOStatusCheckName = "EasyCLA DCO"
+
+// ErrInvalidEnforcementMode is returned for an enforcement mode other than cla, dco or cla-or-dco
+var ErrInvalidEnforcementMode = errors.New("invalid enforcement mode, expecting one of: cla, dco, cla-or-dco")
+
+// EnforcementMode returns the enforcement mode, the repositories without a mode are in the CLA mode
+func EnforcementMode(mode string) string {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "" {
+		return EnforcementModeCLA
+	}
+	return mode
+}
+
+// IsValidEnforcementMode returns true if the mode is one of the enforcement modes, empty is the CLA mode
+func IsValidEnforcementMode(mode string) bool {
+	switch EnforcementMode(mode) {
+	case EnforcementModeCLA, EnforcementModeDCO, EnforcementModeCLAOrDCO:
+		return true
+	}
+	return false
+}
+
+// StatusCheckNames returns the names of the status checks the branch protection of a repository in the enforcement
+// mode requires
+func StatusCheckNames(mode string) []string {
+	if EnforcementMode(mode) == EnforcementModeDCO {
+		return []string{DCOStatusCheckName}
+	}
+	return []string{utils.GitHubBotName}
+}
+
+// AllStatusCheckNames returns the names of the status checks of all the enforcement modes, e.g. to disable the check
+// of the previous mode once the mode of a repository changed
+func AllStatusCheckNames() []string {
+	return []string{utils.GitHubBotName, DCOStatusCheckName}
+}
@@ -96,6 +96,63 @@ func (mr *MockRepositoryMockRecorder) DisableRepository(ctx, repositoryID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableRepository", reflect.TypeOf((*MockRepository)(nil).DisableRepository), ctx, repositoryID)
 }
 
+// UpdateRepositoryName mocks base method
+func (m *MockRepository) UpdateRepositoryName(ctx context.Context, repositoryID, organizationName, repositoryName, repositoryURL string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRepositoryName", ctx, repositoryID, organizationName, repositoryName, repositoryURL)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRepositoryName indicates an expected call of UpdateRepositoryName
+func (mr *MockRepositoryMockRecorder) UpdateRepositoryName(ctx, repositoryID, organizationName, repositoryName, repositoryURL interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRepositoryName", reflect.TypeOf((*MockRepository)(nil).UpdateRepositoryName), ctx, repositoryID, organizationName, repositoryName, repositoryURL)
+}
+
+// UpdateEnforcementMode mocks base method
+func (m *MockRepository) UpdateEnforcementMode(ctx context.Context, repositoryID, enforcementMode string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEnforcementMode", ctx, repositoryID, enforcementMode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEnforcementMode indicates an expected call of UpdateEnforcementMode
+func (mr *MockRepositoryMockRecorder) UpdateEnforcementMode(ctx, repositoryID, enforcementMode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEnforcementMode", reflect.TypeOf((*MockRepository)(nil).UpdateEnforcementMode), ctx, repositoryID, enforcementMode)
+}
+
+// GetBranchProtectionDrift mocks base method
+func (m *MockRepository) GetBranchProtectionDrift(ctx context.Context, repositoryID string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBranchProtectionDrift", ctx, repositoryID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBranchProtectionDrift indicates an expected call of GetBranchProtectionDrift
+func (mr *MockRepositoryMockRecorder) GetBranchProtectionDrift(ctx, repositoryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBranchProtectionDrift", reflect.TypeOf((*MockRepository)(nil).GetBranchProtectionDrift), ctx, repositoryID)
+}
+
+// UpdateBranchProtectionDrift mocks base method
+func (m *MockRepository) UpdateBranchProtectionDrift(ctx context.Context, repositoryID string, drift []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBranchProtectionDrift", ctx, repositoryID, drift)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBranchProtectionDrift indicates an expected call of UpdateBranchProtectionDrift
+func (mr *MockRepositoryMockRecorder) UpdateBranchProtectionDrift(ctx, repositoryID, drift interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBranchProtectionDrift", reflect.TypeOf((*MockRepository)(nil).UpdateBranchProtectionDrift), ctx, repositoryID, drift)
+}
+
 // DisableRepositoriesByProjectID mocks base method
 func (m *MockRepository) DisableRepositoriesByProjectID(ctx context.Context, projectID string) error {
 	m.ctrl.T.Helper()
@@ -154,6 +211,21 @@ func (mr *MockRepositoryMockRecorder) GetRepositoryByGithubID(ctx, externalID, e
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepositoryByGithubID", reflect.TypeOf((*MockRepository)(nil).GetRepositoryByGithubID), ctx, externalID, enabled)
 }
 
+// GetRepositoryByExternalID mocks base method
+func (m *MockRepository) GetRepositoryByExternalID(ctx context.Context, repositoryType, externalID string, enabled bool) (*models.GithubRepository, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRepositoryByExternalID", ctx, repositoryType, externalID, enabled)
+	ret0, _ := ret[0].(*models.GithubRepository)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRepositoryByExternalID indicates an expected call of GetRepositoryByExternalID
+func (mr *MockRepositoryMockRecorder) GetRepositoryByExternalID(ctx, repositoryType, externalID, enabled interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRepositoryByExternalID", reflect.TypeOf((*MockRepository)(nil).GetRepositoryByExternalID), ctx, repositoryType, externalID, enabled)
+}
+
 // GetRepositoriesByCLAGroup mocks base method
 func (m *MockRepository) GetRepositoriesByCLAGroup(ctx context.Context, claGroup string, enabled bool) ([]*models.GithubRepository, error) {
 	m.ctrl.T.Helper()
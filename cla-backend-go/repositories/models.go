@@ -19,8 +19,11 @@ type RepositoryDBModel struct {
 	RepositoryURL              string `dynamodbav:"repository_url" json:"repository_url,omitempty"`
 	ProjectSFID                string `dynamodbav:"project_sfid" json:"project_sfid,omitempty"`
 	Enabled                    bool   `dynamodbav:"enabled" json:"enabled"`
+	EnforcementMode            string `dynamodbav:"enforcement_mode" json:"enforcement_mode,omitempty"`
 	Note                       string `dynamodbav:"note" json:"note,omitempty"`
 	Version                    string `dynamodbav:"version" json:"version,omitempty"`
+	// BranchProtectionDrift is the branch protection drift last reported for the repository
+	BranchProtectionDrift []string `dynamodbav:"branch_protection_drift,omitempty" json:"branch_protection_drift,omitempty"`
 }
 
 func convertModels(dbModels []*RepositoryDBModel) []*models.GithubRepository {
@@ -46,6 +49,7 @@ func (gr *RepositoryDBModel) toModel() *models.GithubRepository {
 		RepositoryURL:              gr.RepositoryURL,
 		ProjectSFID:                gr.ProjectSFID,
 		Enabled:                    gr.Enabled,
+		EnforcementMode:            EnforcementMode(gr.EnforcementMode),
 		Note:                       gr.Note,
 		Version:                    gr.Version,
 	}
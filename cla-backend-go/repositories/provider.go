@@ -0,0 +1,86 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// ErrUnsupportedRepositoryType is returned when no provider is registered for the repository type
+var ErrUnsupportedRepositoryType = errors.New("unsupported repository type")
+
+// Provider is the code hosting service the organizations and the repositories are hosted on - GitHub organizations
+// map to GitLab groups and GitHub repositories to GitLab projects
+type Provider interface {
+	// Type returns the repository type of the provider, e.g. github
+	Type() string
+	// GetOrganization returns the details of the organization
+	GetOrganization(ctx context.Context, organizationName string) (*ProviderOrganization, error)
+	// GetRepository returns the repository of the organization with the specified external ID
+	GetRepository(ctx context.Context, organization *models.GithubOrganization, externalID string) (*ProviderRepository, error)
+	// ListRepositories returns the repositories of the organization EasyCLA has access to
+	ListRepositories(ctx context.Context, organization *models.GithubOrganization) ([]*ProviderRepository, error)
+}
+
+// ProviderOrganization is an organization as reported by the provider
+type ProviderOrganization struct {
+	ID          int64
+	Name        string
+	Description string
+	HTMLURL     string
+}
+
+// ProviderRepository is a repository as reported by the provider
+type ProviderRepository struct {
+	ExternalID int64
+	// FullName is the name including the organization, e.g. kubernetes/kubernetes
+	FullName string
+	HTMLURL  string
+}
+
+var (
+	providersLock sync.RWMutex
+	providers     = map[string]Provider{
+		utils.GitHubType: githubProvider{},
+		utils.GitLabType: gitlabProvider{},
+	}
+)
+
+// RegisterProvider registers the provider for its repository type, replacing the provider registered before
+func RegisterProvider(provider Provider) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	providers[provider.Type()] = provider
+}
+
+// GetProvider returns the provider of the repository type, an empty type is a GitHub repository
+func GetProvider(repositoryType string) (Provider, error) {
+	if repositoryType == "" {
+		repositoryType = utils.GitHubType
+	}
+
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	provider, ok := providers[strings.ToLower(repositoryType)]
+	if !ok {
+		return nil, fmt.Errorf("%s : %w", repositoryType, ErrUnsupportedRepositoryType)
+	}
+	return provider, nil
+}
+
+// OrganizationType returns the repository type of the organization, organizations added before GitLab support was
+// available have no type and are GitHub organizations
+func OrganizationType(organization *models.GithubOrganization) string {
+	if organization == nil || organization.OrganizationType == "" {
+		return utils.GitHubType
+	}
+	return organization.OrganizationType
+}
@@ -0,0 +1,79 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package repositories
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// githubProvider provides the GitHub organizations and repositories through the EasyCLA GitHub application
+type githubProvider struct{}
+
+// Type returns the github repository type
+func (p githubProvider) Type() string {
+	return utils.GitHubType
+}
+
+// GetOrganization returns the GitHub organization details
+func (p githubProvider) GetOrganization(ctx context.Context, organizationName string) (*ProviderOrganization, error) {
+	user, err := github.GetUserDetails(organizationName)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderOrganization{
+		ID:          utils.Int64Value(user.ID),
+		Name:        organizationName,
+		Description: utils.StringValue(user.Bio),
+		HTMLURL:     utils.StringValue(user.HTMLURL),
+	}, nil
+}
+
+// GetRepository returns the GitHub repository, the EasyCLA GitHub application must be installed on the organization
+func (p githubProvider) GetRepository(ctx context.Context, organization *models.GithubOrganization, externalID string) (*ProviderRepository, error) {
+	repoGithubID, err := strconv.ParseInt(externalID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if organization.OrganizationInstallationID == 0 {
+		return nil, errors.New("github app not installed on github organization")
+	}
+
+	ghRepo, err := github.GetRepositoryByExternalID(ctx, organization.OrganizationInstallationID, repoGithubID)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderRepository{
+		ExternalID: utils.Int64Value(ghRepo.ID),
+		FullName:   utils.StringValue(ghRepo.FullName),
+		HTMLURL:    utils.StringValue(ghRepo.HTMLURL),
+	}, nil
+}
+
+// ListRepositories returns the repositories the EasyCLA GitHub application installation has access to, none when
+// the application isn't installed
+func (p githubProvider) ListRepositories(ctx context.Context, organization *models.GithubOrganization) ([]*ProviderRepository, error) {
+	if organization.OrganizationInstallationID == 0 {
+		return nil, nil
+	}
+
+	list, err := github.GetInstallationRepositories(ctx, organization.OrganizationInstallationID)
+	if err != nil {
+		return nil, err
+	}
+	repos := make([]*ProviderRepository, 0, len(list))
+	for _, repoInfo := range list {
+		repos = append(repos, &ProviderRepository{
+			ExternalID: utils.Int64Value(repoInfo.ID),
+			FullName:   utils.StringValue(repoInfo.FullName),
+			HTMLURL:    utils.StringValue(repoInfo.HTMLURL),
+		})
+	}
+	return repos, nil
+}
@@ -0,0 +1,95 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// gitlabProvider provides the GitLab groups and projects through the configured GitLab access token, the
+// organization name is the full path of the group
+type gitlabProvider struct{}
+
+// Type returns the gitlab repository type
+func (p gitlabProvider) Type() string {
+	return utils.GitLabType
+}
+
+// GetOrganization returns the GitLab group details
+func (p gitlabProvider) GetOrganization(ctx context.Context, organizationName string) (*ProviderOrganization, error) {
+	client := gitlab.GetClient()
+	if client == nil {
+		return nil, gitlab.ErrNotConfigured
+	}
+
+	group, err := client.GetGroup(ctx, organizationName)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderOrganization{
+		ID:          group.ID,
+		Name:        group.FullPath,
+		Description: group.Description,
+		HTMLURL:     group.WebURL,
+	}, nil
+}
+
+// GetRepository returns the GitLab project, the project must belong to the group or one of its sub-groups
+func (p gitlabProvider) GetRepository(ctx context.Context, organization *models.GithubOrganization, externalID string) (*ProviderRepository, error) {
+	client := gitlab.GetClient()
+	if client == nil {
+		return nil, gitlab.ErrNotConfigured
+	}
+
+	projectID, err := strconv.ParseInt(externalID, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	project, err := client.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !InGitLabGroup(project.PathWithNamespace, organization.OrganizationName) {
+		return nil, gitlab.ErrProjectNotFound
+	}
+	return gitlabProviderRepository(project), nil
+}
+
+// ListRepositories returns the projects of the GitLab group and its sub-groups
+func (p gitlabProvider) ListRepositories(ctx context.Context, organization *models.GithubOrganization) ([]*ProviderRepository, error) {
+	client := gitlab.GetClient()
+	if client == nil {
+		return nil, gitlab.ErrNotConfigured
+	}
+
+	projects, err := client.ListGroupProjects(ctx, organization.OrganizationName)
+	if err != nil {
+		return nil, err
+	}
+	repos := make([]*ProviderRepository, 0, len(projects))
+	for _, project := range projects {
+		repos = append(repos, gitlabProviderRepository(project))
+	}
+	return repos, nil
+}
+
+// InGitLabGroup returns true if the project path, e.g. my-group/my-sub-group/my-project, belongs to the group or one
+// of its sub-groups
+func InGitLabGroup(projectPath, groupPath string) bool {
+	return strings.HasPrefix(strings.ToLower(projectPath), strings.ToLower(strings.TrimSuffix(groupPath, "/"))+"/")
+}
+
+func gitlabProviderRepository(project *gitlab.Project) *ProviderRepository {
+	return &ProviderRepository{
+		ExternalID: project.ID,
+		FullName:   project.PathWithNamespace,
+		HTMLURL:    project.WebURL,
+	}
+}
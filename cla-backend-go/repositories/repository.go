@@ -19,11 +19,10 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 
-	"github.com/aws/aws-sdk-go/aws/session"
-
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // index
@@ -56,11 +55,16 @@ type Repository interface {
 	EnableRepository(ctx context.Context, repositoryID string) error
 	EnableRepositoryWithCLAGroupID(ctx context.Context, repositoryID, claGroupID string) error
 	DisableRepository(ctx context.Context, repositoryID string) error
+	UpdateRepositoryName(ctx context.Context, repositoryID, organizationName, repositoryName, repositoryURL string) error
+	UpdateEnforcementMode(ctx context.Context, repositoryID, enforcementMode string) error
+	GetBranchProtectionDrift(ctx context.Context, repositoryID string) ([]string, error)
+	UpdateBranchProtectionDrift(ctx context.Context, repositoryID string, drift []string) error
 	DisableRepositoriesByProjectID(ctx context.Context, projectID string) error
 	DisableRepositoriesOfGithubOrganization(ctx context.Context, externalProjectID, githubOrgName string) error
 	GetRepository(ctx context.Context, repositoryID string) (*models.GithubRepository, error)
 	GetRepositoryByName(ctx context.Context, repositoryName string) (*models.GithubRepository, error)
 	GetRepositoryByGithubID(ctx context.Context, externalID string, enabled bool) (*models.GithubRepository, error)
+	GetRepositoryByExternalID(ctx context.Context, repositoryType, externalID string, enabled bool) (*models.GithubRepository, error)
 	GetRepositoriesByCLAGroup(ctx context.Context, claGroup string, enabled bool) ([]*models.GithubRepository, error)
 	GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error)
 	GetCLAGroupRepositoriesGroupByOrgs(ctx context.Context, projectID string, enabled bool) ([]*models.GithubRepositoriesGroupByOrgs, error)
@@ -68,17 +72,17 @@ type Repository interface {
 }
 
 // NewRepository create new Repository
-func NewRepository(awsSession *session.Session, stage string) Repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
 	return &repo{
 		stage:               stage,
-		dynamoDBClient:      dynamodb.New(awsSession),
+		dynamoDBClient:      dynamoDBClient,
 		repositoryTableName: fmt.Sprintf("cla-%s-repositories", stage),
 	}
 }
 
 type repo struct {
 	stage               string
-	dynamoDBClient      *dynamodb.DynamoDB
+	dynamoDBClient      storage.Driver
 	repositoryTableName string
 }
 
@@ -124,6 +128,7 @@ func (r repo) AddGithubRepository(ctx context.Context, externalProjectID string,
 		RepositoryType:             utils.StringValue(input.RepositoryType),
 		RepositoryURL:              utils.StringValue(input.RepositoryURL),
 		Enabled:                    true, // default is enabled
+		EnforcementMode:            EnforcementMode(input.EnforcementMode),
 		Note:                       fmt.Sprintf("created on %s", currentTime),
 		ProjectSFID:                projectSFID,
 		Version:                    "v1",
@@ -135,7 +140,7 @@ func (r repo) AddGithubRepository(ctx context.Context, externalProjectID string,
 	}
 
 	log.WithFields(f).Debug("creating repository entry")
-	_, err = r.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		Item:      av,
 		TableName: aws.String(r.repositoryTableName),
 	})
@@ -167,6 +172,164 @@ func (r *repo) DisableRepository(ctx context.Context, repositoryID string) error
 	return r.disableGithubRepository(ctx, repositoryID)
 }
 
+// UpdateRepositoryName updates the organization name, repository name and URL of the repository entry - used when
+// a repository is renamed or transferred to another organization
+func (r *repo) UpdateRepositoryName(ctx context.Context, repositoryID, organizationName, repositoryName, repositoryURL string) error {
+	f := logrus.Fields{
+		"functionName":     "UpdateRepositoryName",
+		utils.XREQUESTID:   ctx.Value(utils.XREQUESTID),
+		"repositoryID":     repositoryID,
+		"organizationName": organizationName,
+		"repositoryName":   repositoryName,
+		"repositoryURL":    repositoryURL,
+	}
+
+	_, now := utils.CurrentTime()
+	log.WithFields(f).Debug("updating repository name")
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository_id": {S: aws.String(repositoryID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#organizationName": aws.String("repository_organization_name"),
+			"#repositoryName":   aws.String("repository_name"),
+			"#repositoryURL":    aws.String("repository_url"),
+			"#dateModified":     aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":organizationNameValue": {S: aws.String(organizationName)},
+			":repositoryNameValue":   {S: aws.String(repositoryName)},
+			":repositoryURLValue":    {S: aws.String(repositoryURL)},
+			":dateModifiedValue":     {S: aws.String(now)},
+		},
+		ConditionExpression: aws.String("attribute_exists(repository_id)"),
+		UpdateExpression:    aws.String("SET #organizationName = :organizationNameValue, #repositoryName = :repositoryNameValue, #repositoryURL = :repositoryURLValue, #dateModified = :dateModifiedValue"),
+		TableName:           aws.String(r.repositoryTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrGithubRepositoryNotFound
+		}
+		log.WithFields(f).WithError(err).Warn("error updating github repository name")
+		return err
+	}
+
+	return nil
+}
+
+// UpdateEnforcementMode updates the enforcement mode of the repository entry, one of the EnforcementMode values
+func (r *repo) UpdateEnforcementMode(ctx context.Context, repositoryID, enforcementMode string) error {
+	f := logrus.Fields{
+		"functionName":    "UpdateEnforcementMode",
+		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
+		"repositoryID":    repositoryID,
+		"enforcementMode": enforcementMode,
+	}
+
+	_, now := utils.CurrentTime()
+	log.WithFields(f).Debug("updating repository enforcement mode")
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository_id": {S: aws.String(repositoryID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#enforcementMode": aws.String("enforcement_mode"),
+			"#dateModified":    aws.String("date_modified"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":enforcementModeValue": {S: aws.String(enforcementMode)},
+			":dateModifiedValue":    {S: aws.String(now)},
+		},
+		ConditionExpression: aws.String("attribute_exists(repository_id)"),
+		UpdateExpression:    aws.String("SET #enforcementMode = :enforcementModeValue, #dateModified = :dateModifiedValue"),
+		TableName:           aws.String(r.repositoryTableName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrGithubRepositoryNotFound
+		}
+		log.WithFields(f).WithError(err).Warn("error updating github repository enforcement mode")
+		return err
+	}
+
+	return nil
+}
+
+// GetBranchProtectionDrift returns the branch protection drift last reported for the repository, none if the
+// protection of the repository matched the EasyCLA settings when it was last checked
+func (r *repo) GetBranchProtectionDrift(ctx context.Context, repositoryID string) ([]string, error) {
+	f := logrus.Fields{
+		"functionName":   "GetBranchProtectionDrift",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryID":   repositoryID,
+	}
+
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.repositoryTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository_id": {S: aws.String(repositoryID)},
+		},
+		ProjectionExpression:     aws.String("#drift"),
+		ExpressionAttributeNames: map[string]*string{"#drift": aws.String("branch_protection_drift")},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the branch protection drift of the repository")
+		return nil, err
+	}
+
+	var out RepositoryDBModel
+	err = dynamodbattribute.UnmarshalMap(result.Item, &out)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling response")
+		return nil, err
+	}
+	return out.BranchProtectionDrift, nil
+}
+
+// UpdateBranchProtectionDrift records the branch protection drift reported for the repository, an empty drift
+// removes the recorded drift
+func (r *repo) UpdateBranchProtectionDrift(ctx context.Context, repositoryID string, drift []string) error {
+	f := logrus.Fields{
+		"functionName":   "UpdateBranchProtectionDrift",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryID":   repositoryID,
+		"drift":          drift,
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		Key: map[string]*dynamodb.AttributeValue{
+			"repository_id": {S: aws.String(repositoryID)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#drift": aws.String("branch_protection_drift"),
+		},
+		ConditionExpression: aws.String("attribute_exists(repository_id)"),
+		UpdateExpression:    aws.String("REMOVE #drift"),
+		TableName:           aws.String(r.repositoryTableName),
+	}
+	if len(drift) > 0 {
+		driftValue, err := dynamodbattribute.Marshal(drift)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem marshalling the branch protection drift")
+			return err
+		}
+		input.ExpressionAttributeValues = map[string]*dynamodb.AttributeValue{":driftValue": driftValue}
+		input.UpdateExpression = aws.String("SET #drift = :driftValue")
+	}
+
+	log.WithFields(f).Debug("updating repository branch protection drift")
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, input)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrGithubRepositoryNotFound
+		}
+		log.WithFields(f).WithError(err).Warn("error updating github repository branch protection drift")
+		return err
+	}
+
+	return nil
+}
+
 func (r *repo) DisableRepositoriesByProjectID(ctx context.Context, projectID string) error {
 	repoModels, err := r.getProjectRepositories(ctx, projectID, true)
 	if err != nil {
@@ -208,7 +371,7 @@ func (r *repo) GetRepository(ctx context.Context, repositoryID string) (*models.
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"repositoryID":   repositoryID,
 	}
-	result, err := r.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
 		TableName: aws.String(r.repositoryTableName),
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {
@@ -262,7 +425,7 @@ func (r *repo) GetRepositoryByName(ctx context.Context, repositoryName string) (
 		IndexName:                 aws.String(RepositoryNameIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("unable to get repositories by name")
 		return nil, err
@@ -315,7 +478,7 @@ func (r *repo) GetRepositoriesByCLAGroup(ctx context.Context, claGroupID string,
 		IndexName:                 aws.String(ProjectRepositoryIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error: %+v", err)
 		return nil, err
@@ -363,7 +526,7 @@ func (r *repo) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgN
 	}
 
 	log.WithFields(f).Debug("querying repositories table by github organization name")
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get github repositories by organization name. error: %+v", err)
 		return nil, err
@@ -450,7 +613,7 @@ func (r repo) ListProjectRepositories(ctx context.Context, externalProjectID str
 		IndexName:                 aws.String(indexName),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error = %s", err.Error())
 		return nil, err
@@ -498,7 +661,7 @@ func (r repo) getProjectRepositories(ctx context.Context, projectID string, enab
 		IndexName:                 aws.String(ProjectRepositoryIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error = %s", err.Error())
 		return nil, err
@@ -543,7 +706,7 @@ func (r repo) getRepositoriesByGithubOrg(ctx context.Context, githubOrgName stri
 		TableName:                 aws.String(r.repositoryTableName),
 	}
 
-	results, err := r.dynamoDBClient.Scan(scanInput)
+	results, err := r.dynamoDBClient.ScanWithContext(ctx, scanInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get github organizations repositories. error = %s", err.Error())
 		return nil, err
@@ -593,7 +756,7 @@ func (r repo) GetRepositoryByGithubID(ctx context.Context, externalID string, en
 		IndexName:                 aws.String(ExternalRepositoryIndex),
 	}
 
-	results, err := r.dynamoDBClient.Query(queryInput)
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
 	if err != nil {
 		log.WithFields(f).Warnf("unable to get project github repositories. error = %s", err.Error())
 		return nil, err
@@ -610,6 +773,51 @@ func (r repo) GetRepositoryByGithubID(ctx context.Context, externalID string, en
 	return result.toModel(), nil
 }
 
+// GetRepositoryByExternalID returns the repository of the repository type, e.g. gitlab, with the specified external ID -
+// external IDs are only unique per repository type
+func (r repo) GetRepositoryByExternalID(ctx context.Context, repositoryType, externalID string, enabled bool) (*models.GithubRepository, error) {
+	f := logrus.Fields{
+		"functionName":   "GetRepositoryByExternalID",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repositoryType": repositoryType,
+		"externalID":     externalID,
+		"enabled":        enabled,
+	}
+
+	condition := expression.Key("repository_external_id").Equal(expression.Value(externalID))
+	filter := expression.Name(repositoryEnabledColumn).Equal(expression.Value(enabled)).
+		And(expression.Name("repository_type").Equal(expression.Value(repositoryType)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).WithFilter(filter).Build()
+	if err != nil {
+		return nil, err
+	}
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(r.repositoryTableName),
+		IndexName:                 aws.String(ExternalRepositoryIndex),
+	}
+
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to query the repositories by external ID")
+		return nil, err
+	}
+	if len(results.Items) == 0 {
+		return nil, ErrGithubRepositoryNotFound
+	}
+	var result *RepositoryDBModel
+	err = dynamodbattribute.UnmarshalMap(results.Items[0], &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.toModel(), nil
+}
+
 func (r repo) enableGithubRepository(ctx context.Context, repositoryID string) error {
 	return r.setEnabledGithubRepository(ctx, repositoryID, true)
 }
@@ -655,7 +863,7 @@ func (r repo) setEnabledGithubRepository(ctx context.Context, repositoryID strin
 
 	_, now := utils.CurrentTime()
 	log.WithFields(f).Debug("updating repository record")
-	_, err := r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {S: aws.String(repositoryID)},
 		},
@@ -726,7 +934,7 @@ func (r repo) setEnabledGithubRepositoryWithCLAGroupID(ctx context.Context, repo
 
 	_, now := utils.CurrentTime()
 	log.WithFields(f).Debug("updating repository record")
-	_, err := r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {S: aws.String(repositoryID)},
 		},
@@ -797,7 +1005,7 @@ func (r repo) setClaGroupIDGithubRepository(ctx context.Context, repositoryID, c
 
 	_, now := utils.CurrentTime()
 	log.WithFields(f).Debug("updating repository record with cla group id")
-	_, err := r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+	_, err := r.dynamoDBClient.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"repository_id": {S: aws.String(repositoryID)},
 		},
@@ -7,13 +7,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/sirupsen/logrus"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
-	"github.com/communitybridge/easycla/cla-backend-go/github"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
@@ -97,21 +95,28 @@ func (s *service) AddGithubRepository(ctx context.Context, externalProjectID str
 		log.WithFields(f).Warnf("github app not installed on github organization: %s", utils.StringValue(input.RepositoryOrganizationName))
 		return nil, errors.New("github app not installed on github organization")
 	}
-	repoGithubID, err := strconv.ParseInt(utils.StringValue(input.RepositoryExternalID), 10, 64)
+	// The organization decides where the repository is hosted
+	organizationType := OrganizationType(org.List[0])
+	if input.RepositoryType != nil && *input.RepositoryType != "" && !strings.EqualFold(*input.RepositoryType, organizationType) {
+		log.WithFields(f).Warnf("repository type: %s doesn't match the %s organization: %s", utils.StringValue(input.RepositoryType), organizationType, utils.StringValue(input.RepositoryOrganizationName))
+		return nil, fmt.Errorf("repository type %s doesn't match the %s organization", utils.StringValue(input.RepositoryType), organizationType)
+	}
+	provider, err := GetProvider(organizationType)
 	if err != nil {
-		log.WithFields(f).WithError(err).Warnf("problem converting repository external ID - should be an integer value: %s", utils.StringValue(input.RepositoryExternalID))
+		log.WithFields(f).WithError(err).Warn("problem loading the repository provider")
 		return nil, err
 	}
-	ghRepo, err := github.GetRepositoryByExternalID(ctx, org.List[0].OrganizationInstallationID, repoGithubID)
+	providerRepo, err := provider.GetRepository(ctx, org.List[0], utils.StringValue(input.RepositoryExternalID))
 	if err != nil {
-		log.WithFields(f).WithError(err).Warnf("problem loading repository by organization installation ID: %d and repo github id: %d", org.List[0].OrganizationInstallationID, repoGithubID)
+		log.WithFields(f).WithError(err).Warnf("problem loading %s repository by external ID: %s", organizationType, utils.StringValue(input.RepositoryExternalID))
 		return nil, err
 	}
 
-	log.Debugf("ghRepo.HTMLURL %s, input.RepositoryURL  %s", *ghRepo.HTMLURL, *input.RepositoryURL)
-	if !strings.EqualFold(*ghRepo.HTMLURL, *input.RepositoryURL) {
-		return nil, errors.New("github repository not found")
+	log.Debugf("providerRepo.HTMLURL %s, input.RepositoryURL  %s", providerRepo.HTMLURL, utils.StringValue(input.RepositoryURL))
+	if !strings.EqualFold(providerRepo.HTMLURL, utils.StringValue(input.RepositoryURL)) {
+		return nil, fmt.Errorf("%s repository not found", organizationType)
 	}
+	input.RepositoryType = &organizationType
 
 	// Check to see if the repository already exists...
 	existingModel, err := s.GetRepositoryByName(ctx, utils.StringValue(input.RepositoryName))
@@ -0,0 +1,174 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// approval list types recorded on the approval list entry details
+const (
+	ApprovalListTypeEmail          = "email"
+	ApprovalListTypeDomain         = "domain"
+	ApprovalListTypeGithubUsername = "githubUsername"
+	ApprovalListTypeGithubOrg      = "githubOrg"
+	ApprovalListTypeGitlabUsername = "gitlabUsername"
+)
+
+// approvalListEntryDetailsColumn holds the expiry and the adding user of the approval list entries - the
+// approval list columns remain flat string lists
+const approvalListEntryDetailsColumn = "approval_list_entry_details"
+
+// approvalListTypes is the order the approval lists are processed in
+var approvalListTypes = []string{ApprovalListTypeEmail, ApprovalListTypeDomain, ApprovalListTypeGithubUsername, ApprovalListTypeGithubOrg, ApprovalListTypeGitlabUsername}
+
+// approvalListValues returns the current entries of each approval list of the signature
+func approvalListValues(sig *models.Signature) map[string][]string {
+	return map[string][]string{
+		ApprovalListTypeEmail:          sig.EmailApprovalList,
+		ApprovalListTypeDomain:         sig.DomainApprovalList,
+		ApprovalListTypeGithubUsername: sig.GithubUsernameApprovalList,
+		ApprovalListTypeGithubOrg:      sig.GithubOrgApprovalList,
+		ApprovalListTypeGitlabUsername: sig.GitlabUsernameApprovalList,
+	}
+}
+
+// approvalListAdditions returns the entries added by the approval list update, by approval list
+func approvalListAdditions(params *models.ApprovalList) map[string][]string {
+	return map[string][]string{
+		ApprovalListTypeEmail:          params.AddEmailApprovalList,
+		ApprovalListTypeDomain:         params.AddDomainApprovalList,
+		ApprovalListTypeGithubUsername: params.AddGithubUsernameApprovalList,
+		ApprovalListTypeGithubOrg:      params.AddGithubOrgApprovalList,
+		ApprovalListTypeGitlabUsername: params.AddGitlabUsernameApprovalList,
+	}
+}
+
+// approvalListEntryExpired returns true if the entry has an expiry at or before the specified time
+func approvalListEntryExpired(detail *models.ApprovalListEntryDetail, now time.Time) bool {
+	if detail.ExpiresOn == "" {
+		return false
+	}
+	expiresOn, err := time.Parse(time.RFC3339, detail.ExpiresOn)
+	if err != nil {
+		return false
+	}
+	return !expiresOn.After(now)
+}
+
+// ExpiredApprovalListEntries returns the approval list entries of the signature that expired at or before the specified time
+func ExpiredApprovalListEntries(sig *models.Signature, now time.Time) []*models.ApprovalListEntryDetail {
+	var expired []*models.ApprovalListEntryDetail
+	for _, detail := range sig.ApprovalListEntryDetails {
+		if approvalListEntryExpired(detail, now) {
+			expired = append(expired, detail)
+		}
+	}
+	return expired
+}
+
+// expiredApprovalListRemovals converts the expired entries into an approval list update removing them
+func expiredApprovalListRemovals(expired []*models.ApprovalListEntryDetail) *models.ApprovalList {
+	removals := &models.ApprovalList{}
+	for _, detail := range expired {
+		switch detail.ListType {
+		case ApprovalListTypeEmail:
+			removals.RemoveEmailApprovalList = append(removals.RemoveEmailApprovalList, detail.Value)
+		case ApprovalListTypeDomain:
+			removals.RemoveDomainApprovalList = append(removals.RemoveDomainApprovalList, detail.Value)
+		case ApprovalListTypeGithubUsername:
+			removals.RemoveGithubUsernameApprovalList = append(removals.RemoveGithubUsernameApprovalList, detail.Value)
+		case ApprovalListTypeGithubOrg:
+			removals.RemoveGithubOrgApprovalList = append(removals.RemoveGithubOrgApprovalList, detail.Value)
+		case ApprovalListTypeGitlabUsername:
+			removals.RemoveGitlabUsernameApprovalList = append(removals.RemoveGitlabUsernameApprovalList, detail.Value)
+		}
+	}
+	return removals
+}
+
+// buildApprovalListEntryDetails keeps the entry details in step with the updated approval lists - details of removed
+// entries are dropped and the entries added by the update are recorded with the expiry and the adding user
+func buildApprovalListEntryDetails(existing []*models.ApprovalListEntryDetail, approvalLists, additions map[string][]string, expiresOn, addedBy string, now time.Time) []*models.ApprovalListEntryDetail {
+	key := func(listType, value string) string {
+		return listType + "#" + strings.TrimSpace(value)
+	}
+
+	added := map[string]bool{}
+	for listType, values := range additions {
+		for _, value := range values {
+			added[key(listType, value)] = true
+		}
+	}
+
+	var details []*models.ApprovalListEntryDetail
+	seen := map[string]bool{}
+	for _, detail := range existing {
+		k := key(detail.ListType, detail.Value)
+		// Entries added again replace their previous details
+		if seen[k] || added[k] || !utils.StringInSlice(detail.Value, approvalLists[detail.ListType]) {
+			continue
+		}
+		seen[k] = true
+		details = append(details, detail)
+	}
+
+	if expiresOn == "" && addedBy == "" {
+		return details
+	}
+
+	for _, listType := range approvalListTypes {
+		for _, value := range additions[listType] {
+			value = strings.TrimSpace(value)
+			k := key(listType, value)
+			if seen[k] || !utils.StringInSlice(value, approvalLists[listType]) {
+				continue
+			}
+			seen[k] = true
+			details = append(details, &models.ApprovalListEntryDetail{
+				ListType:  listType,
+				Value:     value,
+				ExpiresOn: expiresOn,
+				AddedBy:   addedBy,
+				AddedOn:   utils.TimeToString(now),
+			})
+		}
+	}
+
+	return details
+}
+
+// toApprovalListEntryDetailModels converts the database entry details into response models
+func toApprovalListEntryDetailModels(items []ItemApprovalListEntryDetail) []*models.ApprovalListEntryDetail {
+	var details []*models.ApprovalListEntryDetail
+	for _, item := range items {
+		details = append(details, &models.ApprovalListEntryDetail{
+			ListType:  item.ListType,
+			Value:     item.Value,
+			ExpiresOn: item.ExpiresOn,
+			AddedBy:   item.AddedBy,
+			AddedOn:   item.AddedOn,
+		})
+	}
+	return details
+}
+
+// toApprovalListEntryDetailItems converts the entry details into database models
+func toApprovalListEntryDetailItems(details []*models.ApprovalListEntryDetail) []ItemApprovalListEntryDetail {
+	var items []ItemApprovalListEntryDetail
+	for _, detail := range details {
+		items = append(items, ItemApprovalListEntryDetail{
+			ListType:  detail.ListType,
+			Value:     detail.Value,
+			ExpiresOn: detail.ExpiresOn,
+			AddedBy:   detail.AddedBy,
+			AddedOn:   detail.AddedOn,
+		})
+	}
+	return items
+}
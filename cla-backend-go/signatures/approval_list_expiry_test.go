@@ -0,0 +1,109 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"testing"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiredApprovalListEntries(t *testing.T) {
+	now := time.Date(2021, 1, 15, 12, 0, 0, 0, time.UTC)
+	sig := &models.Signature{
+		ApprovalListEntryDetails: []*models.ApprovalListEntryDetail{
+			{ListType: ApprovalListTypeEmail, Value: "intern@example.com", ExpiresOn: "2021-01-15T12:00:00Z"},
+			{ListType: ApprovalListTypeEmail, Value: "contractor@example.com", ExpiresOn: "2021-02-01T00:00:00Z"},
+			{ListType: ApprovalListTypeDomain, Value: "example.org", ExpiresOn: "2021-01-01T00:00:00Z"},
+			{ListType: ApprovalListTypeGithubUsername, Value: "employee", AddedBy: "manager"},
+			{ListType: ApprovalListTypeGithubOrg, Value: "example-org", ExpiresOn: "not-a-date"},
+		},
+	}
+
+	expired := ExpiredApprovalListEntries(sig, now)
+	assert.Len(t, expired, 2)
+	assert.Equal(t, "intern@example.com", expired[0].Value)
+	assert.Equal(t, "example.org", expired[1].Value)
+
+	removals := expiredApprovalListRemovals(expired)
+	assert.Equal(t, []string{"intern@example.com"}, removals.RemoveEmailApprovalList)
+	assert.Equal(t, []string{"example.org"}, removals.RemoveDomainApprovalList)
+	assert.Nil(t, removals.RemoveGithubUsernameApprovalList)
+	assert.Nil(t, removals.AddEmailApprovalList)
+}
+
+func TestBuildApprovalListEntryDetails(t *testing.T) {
+	now := time.Date(2021, 1, 15, 12, 0, 0, 0, time.UTC)
+	existing := []*models.ApprovalListEntryDetail{
+		{ListType: ApprovalListTypeEmail, Value: "intern@example.com", ExpiresOn: "2021-02-01T00:00:00Z", AddedBy: "manager"},
+		{ListType: ApprovalListTypeEmail, Value: "removed@example.com", ExpiresOn: "2021-02-01T00:00:00Z", AddedBy: "manager"},
+		{ListType: ApprovalListTypeDomain, Value: "example.org", ExpiresOn: "2021-03-01T00:00:00Z", AddedBy: "manager"},
+	}
+
+	testCases := []struct {
+		name      string
+		lists     map[string][]string
+		additions map[string][]string
+		expiresOn string
+		addedBy   string
+		expected  []*models.ApprovalListEntryDetail
+	}{
+		{
+			name: "details of removed entries are dropped",
+			lists: map[string][]string{
+				ApprovalListTypeEmail:  {"intern@example.com"},
+				ApprovalListTypeDomain: {"example.org"},
+			},
+			expected: []*models.ApprovalListEntryDetail{existing[0], existing[2]},
+		},
+		{
+			name: "added entries are recorded with the expiry and the adding user",
+			lists: map[string][]string{
+				ApprovalListTypeEmail:          {"intern@example.com", "removed@example.com"},
+				ApprovalListTypeDomain:         {"example.org"},
+				ApprovalListTypeGithubUsername: {"contractor"},
+			},
+			additions: map[string][]string{ApprovalListTypeGithubUsername: {" contractor "}},
+			expiresOn: "2021-06-30T00:00:00Z",
+			addedBy:   "other-manager",
+			expected: []*models.ApprovalListEntryDetail{existing[0], existing[1], existing[2], {
+				ListType:  ApprovalListTypeGithubUsername,
+				Value:     "contractor",
+				ExpiresOn: "2021-06-30T00:00:00Z",
+				AddedBy:   "other-manager",
+				AddedOn:   "2021-01-15T12:00:00Z",
+			}},
+		},
+		{
+			name: "entries added again replace their previous details",
+			lists: map[string][]string{
+				ApprovalListTypeEmail:  {"intern@example.com", "removed@example.com"},
+				ApprovalListTypeDomain: {"example.org"},
+			},
+			additions: map[string][]string{ApprovalListTypeDomain: {"example.org"}},
+			addedBy:   "manager",
+			expected: []*models.ApprovalListEntryDetail{existing[0], existing[1], {
+				ListType: ApprovalListTypeDomain,
+				Value:    "example.org",
+				AddedBy:  "manager",
+				AddedOn:  "2021-01-15T12:00:00Z",
+			}},
+		},
+		{
+			name:      "nothing is recorded without an expiry or an adding user",
+			lists:     map[string][]string{ApprovalListTypeGithubOrg: {"example-org"}},
+			additions: map[string][]string{ApprovalListTypeGithubOrg: {"example-org"}},
+			expected:  nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			details := buildApprovalListEntryDetails(existing, tc.lists, tc.additions, tc.expiresOn, tc.addedBy, now)
+			assert.Equal(t, tc.expected, details)
+		})
+	}
+}
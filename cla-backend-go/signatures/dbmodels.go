@@ -5,34 +5,47 @@ package signatures
 
 // ItemSignature database model
 type ItemSignature struct {
-	SignatureID                   string   `json:"signature_id"`
-	DateCreated                   string   `json:"date_created"`
-	DateModified                  string   `json:"date_modified"`
-	SignatureApproved             bool     `json:"signature_approved"`
-	SignatureSigned               bool     `json:"signature_signed"`
-	SignatureDocumentMajorVersion string   `json:"signature_document_major_version"`
-	SignatureDocumentMinorVersion string   `json:"signature_document_minor_version"`
-	SignatureReferenceID          string   `json:"signature_reference_id"`
-	SignatureReferenceName        string   `json:"signature_reference_name"`
-	SignatureReferenceNameLower   string   `json:"signature_reference_name_lower"`
-	SignatureProjectID            string   `json:"signature_project_id"`
-	SignatureReferenceType        string   `json:"signature_reference_type"`
-	SignatureType                 string   `json:"signature_type"`
-	SignatureUserCompanyID        string   `json:"signature_user_ccla_company_id"`
-	EmailWhitelist                []string `json:"email_whitelist"`
-	DomainWhitelist               []string `json:"domain_whitelist"`
-	GitHubWhitelist               []string `json:"github_whitelist"`
-	GitHubOrgWhitelist            []string `json:"github_org_whitelist"`
-	SignatureACL                  []string `json:"signature_acl"`
-	UserGithubUsername            string   `json:"user_github_username"`
-	UserLFUsername                string   `json:"user_lf_username"`
-	UserName                      string   `json:"user_name"`
-	UserEmail                     string   `json:"user_email"`
-	SigtypeSignedApprovedID       string   `json:"sigtype_signed_approved_id"`
-	SignedOn                      string   `json:"signed_on"`
-	SignatoryName                 string   `json:"signatory_name"`
-	UserDocusignName              string   `json:"user_docusign_name"`
-	UserDocusignDateSigned        string   `json:"user_docusign_date_signed"`
+	SignatureID                   string                        `json:"signature_id"`
+	DateCreated                   string                        `json:"date_created"`
+	DateModified                  string                        `json:"date_modified"`
+	SignatureApproved             bool                          `json:"signature_approved"`
+	SignatureSigned               bool                          `json:"signature_signed"`
+	SignatureDocumentMajorVersion string                        `json:"signature_document_major_version"`
+	SignatureDocumentMinorVersion string                        `json:"signature_document_minor_version"`
+	SignatureReferenceID          string                        `json:"signature_reference_id"`
+	SignatureReferenceName        string                        `json:"signature_reference_name"`
+	SignatureReferenceNameLower   string                        `json:"signature_reference_name_lower"`
+	SignatureProjectID            string                        `json:"signature_project_id"`
+	SignatureReferenceType        string                        `json:"signature_reference_type"`
+	SignatureType                 string                        `json:"signature_type"`
+	SignatureUserCompanyID        string                        `json:"signature_user_ccla_company_id"`
+	EmailWhitelist                []string                      `json:"email_whitelist"`
+	DomainWhitelist               []string                      `json:"domain_whitelist"`
+	GitHubWhitelist               []string                      `json:"github_whitelist"`
+	GitHubOrgWhitelist            []string                      `json:"github_org_whitelist"`
+	GitLabUsernameApprovalList    []string                      `json:"gitlab_username_approval_list"`
+	SignatureACL                  []string                      `json:"signature_acl"`
+	UserGithubUsername            string                        `json:"user_github_username"`
+	UserLFUsername                string                        `json:"user_lf_username"`
+	UserName                      string                        `json:"user_name"`
+	UserEmail                     string                        `json:"user_email"`
+	SigtypeSignedApprovedID       string                        `json:"sigtype_signed_approved_id"`
+	SignedOn                      string                        `json:"signed_on"`
+	SignatoryName                 string                        `json:"signatory_name"`
+	UserDocusignName              string                        `json:"user_docusign_name"`
+	UserDocusignDateSigned        string                        `json:"user_docusign_date_signed"`
+	SignedDocumentSHA256          string                        `json:"signed_document_sha256"`
+	SignatureLocale               string                        `json:"signature_locale"`
+	ApprovalListEntryDetails      []ItemApprovalListEntryDetail `json:"approval_list_entry_details"`
+}
+
+// ItemApprovalListEntryDetail is the database model for the expiry and the adding user of an approval list entry
+type ItemApprovalListEntryDetail struct {
+	ListType  string `json:"list_type"`
+	Value     string `json:"value"`
+	ExpiresOn string `json:"expires_on,omitempty"`
+	AddedBy   string `json:"added_by,omitempty"`
+	AddedOn   string `json:"added_on,omitempty"`
 }
 
 // DBManagersModel is a database model for only the ACL/Manager column
@@ -28,6 +28,7 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("domain_whitelist"),
 		expression.Name("github_whitelist"),
 		expression.Name("github_org_whitelist"),
+		expression.Name("gitlab_username_approval_list"),
 		expression.Name("user_github_username"),
 		expression.Name("user_lf_username"),
 		expression.Name("user_name"),
@@ -36,6 +37,9 @@ func buildProjection() expression.ProjectionBuilder {
 		expression.Name("signatory_name"),
 		expression.Name("user_docusign_date_signed"),
 		expression.Name("user_docusign_name"),
+		expression.Name("signed_document_sha256"), // digest recorded when the signed document is stored
+		expression.Name("signature_locale"),       // locale of the courtesy translation shown to the signer
+		expression.Name("approval_list_entry_details"),
 	)
 }
 
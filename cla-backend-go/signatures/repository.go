@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-openapi/strfmt"
 
@@ -29,9 +30,9 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // constants
@@ -58,6 +59,7 @@ type SignatureRepository interface {
 
 	GetSignature(ctx context.Context, signatureID string) (*models.Signature, error)
 	GetIndividualSignature(ctx context.Context, claGroupID, userID string) (*models.Signature, error)
+	GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error)
 	GetCorporateSignature(ctx context.Context, claGroupID, companyID string) (*models.Signature, error)
 	GetSignatureACL(ctx context.Context, signatureID string) ([]string, error)
 	GetProjectSignatures(ctx context.Context, params signatures.GetProjectSignaturesParams, pageSize int64) (*models.Signatures, error)
@@ -68,7 +70,8 @@ type SignatureRepository interface {
 	GetCompanyIDsWithSignedCorporateSignatures(ctx context.Context, claGroupID string) ([]SignatureCompanyID, error)
 	GetUserSignatures(ctx context.Context, params signatures.GetUserSignaturesParams, pageSize int64) (*models.Signatures, error)
 	ProjectSignatures(ctx context.Context, projectID string) (*models.Signatures, error)
-	UpdateApprovalList(ctx context.Context, projectID, companyID string, params *models.ApprovalList) (*models.Signature, error)
+	UpdateApprovalList(ctx context.Context, claManager *models.User, projectID, companyID string, params *models.ApprovalList) (*models.Signature, error)
+	GetSignaturesWithApprovalListEntryDetails(ctx context.Context) ([]*models.Signature, error)
 
 	AddCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error)
 	RemoveCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error)
@@ -86,17 +89,17 @@ type SignatureRepository interface {
 // repository data model
 type repository struct {
 	stage              string
-	dynamoDBClient     *dynamodb.DynamoDB
+	dynamoDBClient     storage.Driver
 	companyRepo        company.IRepository
 	usersRepo          users.UserRepository
 	signatureTableName string
 }
 
 // NewRepository creates a new instance of the whitelist service
-func NewRepository(awsSession *session.Session, stage string, companyRepo company.IRepository, usersRepo users.UserRepository) SignatureRepository {
+func NewRepository(dynamoDBClient storage.Driver, stage string, companyRepo company.IRepository, usersRepo users.UserRepository) SignatureRepository {
 	return repository{
 		stage:              stage,
-		dynamoDBClient:     dynamodb.New(awsSession),
+		dynamoDBClient:     dynamoDBClient,
 		companyRepo:        companyRepo,
 		usersRepo:          usersRepo,
 		signatureTableName: fmt.Sprintf("cla-%s-signatures", stage),
@@ -509,6 +512,101 @@ func (repo repository) GetIndividualSignature(ctx context.Context, claGroupID, u
 	return sigs[0], nil
 }
 
+// GetEmployeeSignature returns the employee acknowledgement signature record for the specified CLA Group, Company and User
+func (repo repository) GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error) {
+	f := logrus.Fields{
+		"functionName":           "GetEmployeeSignature",
+		utils.XREQUESTID:         ctx.Value(utils.XREQUESTID),
+		"tableName":              repo.signatureTableName,
+		"claGroupID":             claGroupID,
+		"companyID":              companyID,
+		"userID":                 userID,
+		"signatureType":          utils.SignatureTypeCLA,
+		"signatureReferenceType": utils.SignatureReferenceTypeUser,
+		"signatureApproved":      "true",
+		"signatureSigned":        "true",
+	}
+
+	// These are the keys we want to match for an employee Signature with a given CLA Group, Company and User ID
+	condition := expression.Key("signature_project_id").Equal(expression.Value(claGroupID)).
+		And(expression.Key("signature_reference_id").Equal(expression.Value(userID)))
+	filter := expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCLA)).
+		And(expression.Name("signature_reference_type").Equal(expression.Value("user"))).
+		And(expression.Name("signature_approved").Equal(expression.Value(aws.Bool(true)))).
+		And(expression.Name("signature_signed").Equal(expression.Value(aws.Bool(true)))).
+		And(expression.Name("signature_user_ccla_company_id").Equal(expression.Value(companyID)))
+
+	builder := expression.NewBuilder().
+		WithKeyCondition(condition).
+		WithFilter(filter).
+		WithProjection(buildProjection())
+
+	// Use the nice builder to create the expression
+	expr, err := builder.Build()
+	if err != nil {
+		log.WithFields(f).Warnf("error building expression for project employee signature query, error: %v", err)
+		return nil, err
+	}
+
+	// Assemble the query input parameters
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		FilterExpression:          expr.Filter(),
+		TableName:                 aws.String(repo.signatureTableName),
+		Limit:                     aws.Int64(100),                             // The maximum number of items to evaluate (not necessarily the number of matching items)
+		IndexName:                 aws.String(SignatureProjectReferenceIndex), // Name of a secondary index to scan
+	}
+
+	sigs := make([]*models.Signature, 0)
+	var lastEvaluatedKey string
+
+	// Loop until we have all the records
+	for ok := true; ok; ok = lastEvaluatedKey != "" {
+		// Make the DynamoDB Query API call
+		//log.WithFields(f).Debugf("Running signature project query using queryInput: %+v", queryInput)
+		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		//log.WithFields(f).Debugf("Ran signature project query, results: %+v, error: %+v", results, errQuery)
+		if errQuery != nil {
+			log.WithFields(f).Warnf("error retrieving project employee signature ID, error: %v", errQuery)
+			return nil, errQuery
+		}
+
+		// Convert the list of DB models to a list of response models
+		//log.WithFields(f).Debug("Building response models...")
+		signatureList, modelErr := repo.buildProjectSignatureModels(ctx, results, claGroupID, LoadACLDetails)
+		if modelErr != nil {
+			log.WithFields(f).Warnf("error converting DB model to response model for signatures, error: %v",
+				modelErr)
+			return nil, modelErr
+		}
+
+		// Add to the signatures response model to the list
+		sigs = append(sigs, signatureList...)
+
+		//log.WithFields(f).Debugf("LastEvaluatedKey: %+v", results.LastEvaluatedKey)
+		if results.LastEvaluatedKey["signature_id"] != nil {
+			lastEvaluatedKey = *results.LastEvaluatedKey["signature_id"].S
+			queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+		} else {
+			lastEvaluatedKey = ""
+		}
+	}
+
+	// Didn't find a matching record
+	if len(sigs) == 0 {
+		return nil, nil
+	}
+
+	if len(sigs) > 1 {
+		log.WithFields(f).Warnf("found multiple matching employee signatures - found %d total", len(sigs))
+	}
+
+	return sigs[0], nil
+}
+
 // GetCorporateSignature returns the signature record for the specified CLA Group and Company ID
 func (repo repository) GetCorporateSignature(ctx context.Context, claGroupID, companyID string) (*models.Signature, error) {
 	f := logrus.Fields{
@@ -1716,8 +1814,9 @@ func (repo repository) RemoveCLAManager(ctx context.Context, signatureID, claMan
 	return sigModel, nil
 }
 
-// UpdateApprovalList updates the specified project/company signature with the updated approval list information
-func (repo repository) UpdateApprovalList(ctx context.Context, projectID, companyID string, params *models.ApprovalList) (*models.Signature, error) { // nolint
+// UpdateApprovalList updates the specified project/company signature with the updated approval list information - the
+// entries added are recorded with the CLA Manager and the optional expiry of the update
+func (repo repository) UpdateApprovalList(ctx context.Context, claManager *models.User, projectID, companyID string, params *models.ApprovalList) (*models.Signature, error) { // nolint
 	f := logrus.Fields{
 		"functionName": "UpdateApprovalList",
 		"projectID":    projectID,
@@ -1749,6 +1848,8 @@ func (repo repository) UpdateApprovalList(ctx context.Context, projectID, compan
 
 	// Just grab and use the first one - need to figure out conflict resolution if more than one
 	sig := sigs.Signatures[0]
+	approvalLists := approvalListValues(sig)
+	existingEntryDetails := sig.ApprovalListEntryDetails
 	expressionAttributeNames := map[string]*string{}
 	expressionAttributeValues := map[string]*dynamodb.AttributeValue{}
 	haveAdditions := false
@@ -1758,6 +1859,7 @@ func (repo repository) UpdateApprovalList(ctx context.Context, projectID, compan
 	if params.AddEmailApprovalList != nil || params.RemoveEmailApprovalList != nil {
 		columnName := "email_whitelist"
 		attrList := buildApprovalAttributeList(ctx, sig.EmailApprovalList, params.AddEmailApprovalList, params.RemoveEmailApprovalList)
+		approvalLists[ApprovalListTypeEmail] = attributeListValues(attrList)
 		// If no entries after consolidating all the updates, we need to remove the column
 		if attrList == nil || attrList.L == nil {
 			var rmColErr error
@@ -1779,6 +1881,7 @@ func (repo repository) UpdateApprovalList(ctx context.Context, projectID, compan
 	if params.AddDomainApprovalList != nil || params.RemoveDomainApprovalList != nil {
 		columnName := "domain_whitelist"
 		attrList := buildApprovalAttributeList(ctx, sig.DomainApprovalList, params.AddDomainApprovalList, params.RemoveDomainApprovalList)
+		approvalLists[ApprovalListTypeDomain] = attributeListValues(attrList)
 		// If no entries after consolidating all the updates, we need to remove the column
 		if attrList == nil || attrList.L == nil {
 			var rmColErr error
@@ -1800,6 +1903,7 @@ func (repo repository) UpdateApprovalList(ctx context.Context, projectID, compan
 	if params.AddGithubUsernameApprovalList != nil || params.RemoveGithubUsernameApprovalList != nil {
 		columnName := "github_whitelist"
 		attrList := buildApprovalAttributeList(ctx, sig.GithubUsernameApprovalList, params.AddGithubUsernameApprovalList, params.RemoveGithubUsernameApprovalList)
+		approvalLists[ApprovalListTypeGithubUsername] = attributeListValues(attrList)
 		// If no entries after consolidating all the updates, we need to remove the column
 		if attrList == nil || attrList.L == nil {
 			var rmColErr error
@@ -1821,6 +1925,7 @@ func (repo repository) UpdateApprovalList(ctx context.Context, projectID, compan
 	if params.AddGithubOrgApprovalList != nil || params.RemoveGithubOrgApprovalList != nil {
 		columnName := "github_org_whitelist"
 		attrList := buildApprovalAttributeList(ctx, sig.GithubOrgApprovalList, params.AddGithubOrgApprovalList, params.RemoveGithubOrgApprovalList)
+		approvalLists[ApprovalListTypeGithubOrg] = attributeListValues(attrList)
 		// If no entries after consolidating all the updates, we need to remove the column
 		if attrList == nil || attrList.L == nil {
 			var rmColErr error
@@ -1839,6 +1944,61 @@ func (repo repository) UpdateApprovalList(ctx context.Context, projectID, compan
 		}
 	}
 
+	if params.AddGitlabUsernameApprovalList != nil || params.RemoveGitlabUsernameApprovalList != nil {
+		columnName := "gitlab_username_approval_list"
+		attrList := buildApprovalAttributeList(ctx, sig.GitlabUsernameApprovalList, params.AddGitlabUsernameApprovalList, params.RemoveGitlabUsernameApprovalList)
+		approvalLists[ApprovalListTypeGitlabUsername] = attributeListValues(attrList)
+		// If no entries after consolidating all the updates, we need to remove the column
+		if attrList == nil || attrList.L == nil {
+			var rmColErr error
+			sig, rmColErr = repo.removeColumn(ctx, sig.SignatureID.String(), columnName)
+			if rmColErr != nil {
+				msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+					columnName, companyID, projectID, signed, approved)
+				log.WithFields(f).Warn(msg)
+				return nil, errors.New(msg)
+			}
+		} else {
+			haveAdditions = true
+			expressionAttributeNames["#GL"] = aws.String(columnName)
+			expressionAttributeValues[":gl"] = attrList
+			updateExpression = updateExpression + " #GL = :gl, "
+		}
+	}
+
+	// Keep the expiry and the adding user of the entries in step with the updated lists
+	var addedBy string
+	if claManager != nil {
+		addedBy = claManager.LfUsername
+	}
+	// Expiries are stored in UTC so the python backend can compare them without parsing offsets
+	expiresOn := params.ExpiresOn
+	if parsed, parseErr := time.Parse(time.RFC3339, expiresOn); parseErr == nil {
+		expiresOn = utils.TimeToString(parsed)
+	}
+	entryDetails := buildApprovalListEntryDetails(existingEntryDetails, approvalLists, approvalListAdditions(params), expiresOn, addedBy, time.Now())
+	if len(entryDetails) > 0 {
+		entryDetailsAttr, marshalErr := dynamodbattribute.Marshal(toApprovalListEntryDetailItems(entryDetails))
+		if marshalErr != nil {
+			log.WithFields(f).Warnf("unable to marshal approval list entry details for company ID: %s project ID: %s, error: %v",
+				companyID, projectID, marshalErr)
+			return nil, marshalErr
+		}
+		haveAdditions = true
+		expressionAttributeNames["#AD"] = aws.String(approvalListEntryDetailsColumn)
+		expressionAttributeValues[":ad"] = entryDetailsAttr
+		updateExpression = updateExpression + " #AD = :ad, "
+	} else if len(existingEntryDetails) > 0 {
+		var rmColErr error
+		sig, rmColErr = repo.removeColumn(ctx, sig.SignatureID.String(), approvalListEntryDetailsColumn)
+		if rmColErr != nil {
+			msg := fmt.Sprintf("unable to remove column %s for signature for company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t",
+				approvalListEntryDetailsColumn, companyID, projectID, signed, approved)
+			log.WithFields(f).Warn(msg)
+			return nil, errors.New(msg)
+		}
+	}
+
 	// Ensure at least one value is set for us to update
 	if !haveAdditions {
 		log.WithFields(f).Debugf("no updates required to any of the approved list values company ID: %s project ID: %s, type: ccla, signed: %t, approved: %t - expecting at least something to update",
@@ -1939,6 +2099,56 @@ func (repo repository) removeColumn(ctx context.Context, signatureID, columnName
 	return updatedSig, nil
 }
 
+// GetSignaturesWithApprovalListEntryDetails returns the signed and approved CCLA signatures with approval list entry
+// details - used to find the approval list entries that expired
+func (repo repository) GetSignaturesWithApprovalListEntryDetails(ctx context.Context) ([]*models.Signature, error) {
+	f := logrus.Fields{
+		"functionName":   "GetSignaturesWithApprovalListEntryDetails",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	filter := expression.AttributeExists(expression.Name(approvalListEntryDetailsColumn)).
+		And(expression.Name("signature_type").Equal(expression.Value(utils.SignatureTypeCCLA))).
+		And(expression.Name("signature_signed").Equal(expression.Value(true))).
+		And(expression.Name("signature_approved").Equal(expression.Value(true)))
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(buildProjection()).Build()
+	if err != nil {
+		log.WithFields(f).Warnf("error building expression for approval list entry details scan, error: %v", err)
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		TableName:                 aws.String(repo.signatureTableName),
+	}
+
+	var sigs []*models.Signature
+	for {
+		results, scanErr := repo.dynamoDBClient.Scan(scanInput)
+		if scanErr != nil {
+			log.WithFields(f).Warnf("error scanning signatures with approval list entry details, error: %v", scanErr)
+			return nil, scanErr
+		}
+
+		pageSigs, modelErr := repo.buildProjectSignatureModels(ctx, &dynamodb.QueryOutput{Items: results.Items}, "", LoadACLDetails)
+		if modelErr != nil {
+			log.WithFields(f).Warnf("error converting DB model to response model for signatures, error: %v", modelErr)
+			return nil, modelErr
+		}
+		sigs = append(sigs, pageSigs...)
+
+		if results.LastEvaluatedKey == nil || len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return sigs, nil
+}
+
 func (repo repository) AddSigTypeSignedApprovedID(ctx context.Context, signatureID string, val string) error {
 	f := logrus.Fields{
 		"functionName":            "AddSigTypeSignedApprovedID",
@@ -2134,6 +2344,7 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 			DomainApprovalList:          dbSignature.DomainWhitelist,
 			GithubUsernameApprovalList:  dbSignature.GitHubWhitelist,
 			GithubOrgApprovalList:       dbSignature.GitHubOrgWhitelist,
+			GitlabUsernameApprovalList:  dbSignature.GitLabUsernameApprovalList,
 			UserName:                    dbSignature.UserName,
 			UserLFID:                    dbSignature.UserLFUsername,
 			UserGHID:                    dbSignature.UserGithubUsername,
@@ -2141,6 +2352,9 @@ func (repo repository) buildProjectSignatureModels(ctx context.Context, results
 			SignatoryName:               dbSignature.SignatoryName,
 			UserDocusignName:            dbSignature.UserDocusignName,
 			UserDocusignDateSigned:      dbSignature.UserDocusignDateSigned,
+			SignedDocumentDigest:        dbSignature.SignedDocumentSHA256,
+			SignatureLocale:             dbSignature.SignatureLocale,
+			ApprovalListEntryDetails:    toApprovalListEntryDetailModels(dbSignature.ApprovalListEntryDetails),
 		}
 		sigs = append(sigs, sig)
 		go func(sigModel *models.Signature, signatureUserCompanyID string, sigACL []string) {
@@ -2283,6 +2497,18 @@ func buildApprovalAttributeList(ctx context.Context, existingList, addEntries, r
 	return &dynamodb.AttributeValue{L: responseList}
 }
 
+// attributeListValues is a helper function to convert an approval list attribute back into the list of entries
+func attributeListValues(attrList *dynamodb.AttributeValue) []string {
+	var values []string
+	if attrList == nil {
+		return values
+	}
+	for _, value := range attrList.L {
+		values = append(values, aws.StringValue(value.S))
+	}
+	return values
+}
+
 // buildCompanyIDList is a helper function to convert the DB response models into a simple list of company IDs
 func (repo repository) buildCompanyIDList(ctx context.Context, results *dynamodb.QueryOutput) ([]SignatureCompanyID, error) {
 	f := logrus.Fields{
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 
@@ -34,6 +35,7 @@ import (
 type SignatureService interface {
 	GetSignature(ctx context.Context, signatureID string) (*models.Signature, error)
 	GetIndividualSignature(ctx context.Context, claGroupID, userID string) (*models.Signature, error)
+	GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error)
 	GetCorporateSignature(ctx context.Context, claGroupID, companyID string) (*models.Signature, error)
 	GetProjectSignatures(ctx context.Context, params signatures.GetProjectSignaturesParams) (*models.Signatures, error)
 	GetProjectCompanySignature(ctx context.Context, companyID, projectID string, signed, approved *bool, nextKey *string, pageSize *int64) (*models.Signature, error)
@@ -48,6 +50,8 @@ type SignatureService interface {
 	AddGithubOrganizationToWhitelist(ctx context.Context, signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error)
 	DeleteGithubOrganizationFromWhitelist(ctx context.Context, signatureID string, whiteListParams models.GhOrgWhitelist, githubAccessToken string) ([]models.GithubOrg, error)
 	UpdateApprovalList(ctx context.Context, authUser *auth.User, claGroupModel *models.ClaGroup, companyModel *models.Company, claGroupID string, params *models.ApprovalList) (*models.Signature, error)
+	GetSignaturesWithApprovalListEntryDetails(ctx context.Context) ([]*models.Signature, error)
+	ExpireApprovalListEntries(ctx context.Context, claGroupModel *models.ClaGroup, companyModel *models.Company, sig *models.Signature) ([]*models.ApprovalListEntryDetail, error)
 
 	AddCLAManager(ctx context.Context, signatureID, claManagerID string) (*models.Signature, error)
 	RemoveCLAManager(ctx context.Context, ignatureID, claManagerID string) (*models.Signature, error)
@@ -57,6 +61,13 @@ type SignatureService interface {
 	GetClaGroupCorporateContributors(ctx context.Context, claGroupID string, companyID *string, searchTerm *string) (*models.CorporateContributorList, error)
 }
 
+// approvalListExpiryUser is recorded as the user of the events logged when approval list entries expire
+var approvalListExpiryUser = &models.User{
+	UserID:     "easycla system",
+	LfUsername: "easycla system",
+	Username:   "easycla system",
+}
+
 type service struct {
 	repo                SignatureRepository
 	companyService      company.IService
@@ -86,6 +97,11 @@ func (s service) GetIndividualSignature(ctx context.Context, claGroupID, userID
 	return s.repo.GetIndividualSignature(ctx, claGroupID, userID)
 }
 
+// GetEmployeeSignature returns the employee acknowledgement signature associated with the specified CLA Group, Company and User ID
+func (s service) GetEmployeeSignature(ctx context.Context, claGroupID, companyID, userID string) (*models.Signature, error) {
+	return s.repo.GetEmployeeSignature(ctx, claGroupID, companyID, userID)
+}
+
 // GetCorporateSignature returns the signature associated with the specified CLA Group and Company ID
 func (s service) GetCorporateSignature(ctx context.Context, claGroupID, companyID string) (*models.Signature, error) {
 	return s.repo.GetCorporateSignature(ctx, claGroupID, companyID)
@@ -417,7 +433,7 @@ func (s service) UpdateApprovalList(ctx context.Context, authUser *auth.User, cl
 		return nil, userErr
 	}
 
-	updatedSig, err := s.repo.UpdateApprovalList(ctx, claGroupModel.ProjectID, companyModel.CompanyID, params)
+	updatedSig, err := s.repo.UpdateApprovalList(ctx, userModel, claGroupModel.ProjectID, companyModel.CompanyID, params)
 	if err != nil {
 		return updatedSig, err
 	}
@@ -437,6 +453,64 @@ func (s service) UpdateApprovalList(ctx context.Context, authUser *auth.User, cl
 	return updatedSig, nil
 }
 
+// GetSignaturesWithApprovalListEntryDetails returns the CCLA signatures with approval list entry details
+func (s service) GetSignaturesWithApprovalListEntryDetails(ctx context.Context) ([]*models.Signature, error) {
+	return s.repo.GetSignaturesWithApprovalListEntryDetails(ctx)
+}
+
+// ExpireApprovalListEntries removes the expired approval list entries of the CCLA signature, logs the approval list
+// update events and notifies the CLA Managers - returns the entries that expired
+func (s service) ExpireApprovalListEntries(ctx context.Context, claGroupModel *models.ClaGroup, companyModel *models.Company, sig *models.Signature) ([]*models.ApprovalListEntryDetail, error) {
+	f := logrus.Fields{
+		"functionName":   "ExpireApprovalListEntries",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupModel.ProjectID,
+		"companyID":      companyModel.CompanyID,
+		"signatureID":    sig.SignatureID,
+	}
+
+	expired := ExpiredApprovalListEntries(sig, time.Now())
+	if len(expired) == 0 {
+		return nil, nil
+	}
+
+	log.WithFields(f).Debugf("removing %d expired approval list entries", len(expired))
+	removals := expiredApprovalListRemovals(expired)
+	_, err := s.repo.UpdateApprovalList(ctx, nil, claGroupModel.ProjectID, companyModel.CompanyID, removals)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem removing expired approval list entries")
+		return nil, err
+	}
+
+	for _, detail := range expired {
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ClaApprovalListUpdated,
+			ProjectID:         claGroupModel.ProjectID,
+			ClaGroupModel:     claGroupModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			LfUsername:        approvalListExpiryUser.LfUsername,
+			UserID:            approvalListExpiryUser.UserID,
+			UserModel:         approvalListExpiryUser,
+			ExternalProjectID: claGroupModel.ProjectExternalID,
+			EventData: &events.CLAApprovalListEntryExpiredData{
+				ApprovalListType:  detail.ListType,
+				ApprovalListValue: detail.Value,
+				ExpiresOn:         detail.ExpiresOn,
+				AddedBy:           detail.AddedBy,
+			},
+		})
+	}
+
+	// Send an email to the CLA Managers
+	for _, claManager := range sig.SignatureACL {
+		claManagerEmail := getBestEmail(claManager)
+		s.sendApprovalListUpdateEmailToCLAManagers(companyModel, claGroupModel, claManager.Username, claManagerEmail, removals)
+	}
+
+	return expired, nil
+}
+
 // Disassociate project signatures
 func (s service) InvalidateProjectRecords(ctx context.Context, projectID string, projectName string) (int, error) {
 	f := logrus.Fields{
@@ -508,6 +582,8 @@ func buildApprovalListSummary(approvalListChanges *models.ApprovalList) string {
 	approvalListSummary += appendList(approvalListChanges.RemoveGithubUsernameApprovalList, "Removed GitHub User:")
 	approvalListSummary += appendList(approvalListChanges.AddGithubOrgApprovalList, "Added GithHub Organization:")
 	approvalListSummary += appendList(approvalListChanges.RemoveGithubOrgApprovalList, "Removed GitHub Organization:")
+	approvalListSummary += appendList(approvalListChanges.AddGitlabUsernameApprovalList, "Added GitLab User:")
+	approvalListSummary += appendList(approvalListChanges.RemoveGitlabUsernameApprovalList, "Removed GitLab User:")
 	approvalListSummary += "</ul>"
 	return approvalListSummary
 }
@@ -750,6 +826,46 @@ func (s service) createEventLogEntries(companyModel *models.Company, claGroupMod
 			},
 		})
 	}
+	for _, value := range approvalList.AddGitlabUsernameApprovalList {
+		// Send an event
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ClaApprovalListUpdated,
+			ProjectID:         claGroupModel.ProjectID,
+			ClaGroupModel:     claGroupModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			LfUsername:        userModel.LfUsername,
+			UserID:            userModel.UserID,
+			UserModel:         userModel,
+			ExternalProjectID: claGroupModel.ProjectExternalID,
+			EventData: &events.CLAApprovalListAddGitLabUsernameData{
+				UserName:                   userModel.LfUsername,
+				UserEmail:                  userModel.LfEmail,
+				UserLFID:                   userModel.UserID,
+				ApprovalListGitLabUsername: value,
+			},
+		})
+	}
+	for _, value := range approvalList.RemoveGitlabUsernameApprovalList {
+		// Send an event
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:         events.ClaApprovalListUpdated,
+			ProjectID:         claGroupModel.ProjectID,
+			ClaGroupModel:     claGroupModel,
+			CompanyID:         companyModel.CompanyID,
+			CompanyModel:      companyModel,
+			LfUsername:        userModel.LfUsername,
+			UserID:            userModel.UserID,
+			UserModel:         userModel,
+			ExternalProjectID: claGroupModel.ProjectExternalID,
+			EventData: &events.CLAApprovalListRemoveGitLabUsernameData{
+				UserName:                   userModel.LfUsername,
+				UserEmail:                  userModel.LfEmail,
+				UserLFID:                   userModel.UserID,
+				ApprovalListGitLabUsername: value,
+			},
+		})
+	}
 	for _, value := range approvalList.AddGithubOrgApprovalList {
 		// Send an event
 		s.eventsService.LogEvent(&events.LogEventArgs{
@@ -0,0 +1,82 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// NewDynamoDBClient returns a DynamoDB client backed by the storage driver, for the libraries which take the DynamoDB
+// client itself, e.g. the session store. The DynamoDB driver is returned as is, for the other drivers the requests of
+// the client are sent to the driver instead of AWS - without signing them, so no AWS credentials are needed.
+func NewDynamoDBClient(driver Driver, awsSession *session.Session) *dynamodb.DynamoDB {
+	if client, ok := driver.(*dynamodb.DynamoDB); ok {
+		return client
+	}
+
+	client := dynamodb.New(awsSession)
+	client.Handlers.Validate.Clear()
+	client.Handlers.Validate.PushBackNamed(corehandlers.ValidateParametersHandler)
+	client.Handlers.Build.Clear()
+	client.Handlers.Sign.Clear()
+	client.Handlers.Send.Clear()
+	client.Handlers.Send.PushBack(func(r *request.Request) {
+		r.Retryable = aws.Bool(false)
+		r.Error = sendToDriver(driver, r)
+	})
+	client.Handlers.ValidateResponse.Clear()
+	client.Handlers.Unmarshal.Clear()
+	client.Handlers.UnmarshalMeta.Clear()
+	client.Handlers.UnmarshalError.Clear()
+	return client
+}
+
+// sendToDriver runs the operation of the request with the storage driver and sets the output of the request
+func sendToDriver(driver Driver, r *request.Request) error {
+	ctx := r.Context()
+	switch input := r.Params.(type) {
+	case *dynamodb.GetItemInput:
+		output, err := driver.GetItemWithContext(ctx, input)
+		if err == nil {
+			*r.Data.(*dynamodb.GetItemOutput) = *output
+		}
+		return err
+	case *dynamodb.PutItemInput:
+		output, err := driver.PutItemWithContext(ctx, input)
+		if err == nil {
+			*r.Data.(*dynamodb.PutItemOutput) = *output
+		}
+		return err
+	case *dynamodb.UpdateItemInput:
+		output, err := driver.UpdateItemWithContext(ctx, input)
+		if err == nil {
+			*r.Data.(*dynamodb.UpdateItemOutput) = *output
+		}
+		return err
+	case *dynamodb.DeleteItemInput:
+		output, err := driver.DeleteItemWithContext(ctx, input)
+		if err == nil {
+			*r.Data.(*dynamodb.DeleteItemOutput) = *output
+		}
+		return err
+	case *dynamodb.QueryInput:
+		output, err := driver.QueryWithContext(ctx, input)
+		if err == nil {
+			*r.Data.(*dynamodb.QueryOutput) = *output
+		}
+		return err
+	case *dynamodb.ScanInput:
+		output, err := driver.ScanWithContext(ctx, input)
+		if err == nil {
+			*r.Data.(*dynamodb.ScanOutput) = *output
+		}
+		return err
+	default:
+		return validationError("the %s operation is not supported by the storage driver", r.Operation.Name)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDynamoDBClient(t *testing.T) {
+	// no region and no credentials, the requests never reach AWS
+	client := NewDynamoDBClient(NewMemoryDriver(TableSchemas("test")...), session.Must(session.NewSession(&aws.Config{})))
+	ctx := context.Background()
+	tableName := aws.String("cla-test-session-store")
+	key := map[string]*dynamodb.AttributeValue{"id": {S: aws.String("session-1")}}
+
+	_, err := client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: tableName,
+		Item:      map[string]*dynamodb.AttributeValue{"id": {S: aws.String("session-1")}, "values": {S: aws.String("state")}},
+	})
+	assert.NoError(t, err)
+
+	output, err := client.GetItemWithContext(ctx, &dynamodb.GetItemInput{TableName: tableName, Key: key})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "state", aws.StringValue(output.Item["values"].S))
+	}
+
+	// the errors of the driver are returned as is
+	_, err = client.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName:           tableName,
+		Item:                key,
+		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, dynamodb.ErrCodeConditionalCheckFailedException, err.(awserr.Error).Code())
+	}
+
+	_, err = client.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{TableName: tableName, Key: key})
+	assert.NoError(t, err)
+	output, err = client.GetItemWithContext(ctx, &dynamodb.GetItemInput{TableName: tableName, Key: key})
+	if assert.NoError(t, err) {
+		assert.Empty(t, output.Item)
+	}
+
+	// the parameters are still validated and the operations the driver doesn't have are rejected
+	_, err = client.GetItemWithContext(ctx, &dynamodb.GetItemInput{Key: key})
+	assert.Error(t, err)
+	_, err = client.ListTablesWithContext(ctx, &dynamodb.ListTablesInput{})
+	assert.Error(t, err)
+
+	// the DynamoDB driver is used as is
+	dynamoDBClient := dynamodb.New(session.Must(session.NewSession(&aws.Config{Region: aws.String("us-east-1")})))
+	assert.Same(t, dynamoDBClient, NewDynamoDBClient(dynamoDBClient, nil))
+}
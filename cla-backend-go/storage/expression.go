@@ -0,0 +1,1260 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// item is a single table row
+type item map[string]*dynamodb.AttributeValue
+
+// tokenKind is the type of an expression token
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenName
+	tokenValue
+	tokenNumber
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits a DynamoDB condition, update or projection expression into tokens
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	isNameRune := func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '#' || r == ':':
+			start := i
+			i++
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("invalid placeholder at position %d", start)
+			}
+			kind := tokenName
+			if r == ':' {
+				kind = tokenValue
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[start:i])})
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i])})
+		case r == '<' || r == '>':
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '<' && runes[i+1] == '>')) {
+				tokens = append(tokens, token{kind: tokenSymbol, text: string(runes[i : i+2])})
+				i += 2
+			} else {
+				tokens = append(tokens, token{kind: tokenSymbol, text: string(r)})
+				i++
+			}
+		case strings.ContainsRune("=+-,()[].", r):
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// pathElement is one step of a document path - either a map key or a list index
+type pathElement struct {
+	name    string
+	index   int
+	isIndex bool
+}
+
+type documentPath []pathElement
+
+// top returns the top level attribute name of the path
+func (p documentPath) top() string {
+	return p[0].name
+}
+
+// operand is a value in an expression - a document path, a placeholder value or a function result
+type operand interface {
+	resolve(it item) (*dynamodb.AttributeValue, bool)
+}
+
+type pathOperand struct {
+	path documentPath
+}
+
+func (o pathOperand) resolve(it item) (*dynamodb.AttributeValue, bool) {
+	return getPath(it, o.path)
+}
+
+type valueOperand struct {
+	value *dynamodb.AttributeValue
+}
+
+func (o valueOperand) resolve(item) (*dynamodb.AttributeValue, bool) {
+	return o.value, true
+}
+
+type sizeOperand struct {
+	path documentPath
+}
+
+func (o sizeOperand) resolve(it item) (*dynamodb.AttributeValue, bool) {
+	av, ok := getPath(it, o.path)
+	if !ok {
+		return nil, false
+	}
+	size, ok := attributeSize(av)
+	if !ok {
+		return nil, false
+	}
+	return &dynamodb.AttributeValue{N: aws.String(strconv.Itoa(size))}, true
+}
+
+// condition is a parsed condition, filter or key condition expression
+type condition interface {
+	eval(it item) bool
+}
+
+type compareCondition struct {
+	op          string
+	left, right operand
+}
+
+func (c compareCondition) eval(it item) bool {
+	left, leftOK := c.left.resolve(it)
+	right, rightOK := c.right.resolve(it)
+	if !leftOK || !rightOK {
+		// a missing attribute is never equal to anything
+		return c.op == "<>"
+	}
+
+	switch c.op {
+	case "=":
+		return equalValues(left, right)
+	case "<>":
+		return !equalValues(left, right)
+	}
+
+	cmp, ok := compareValues(left, right)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+type betweenCondition struct {
+	value, low, high operand
+}
+
+func (c betweenCondition) eval(it item) bool {
+	value, ok1 := c.value.resolve(it)
+	low, ok2 := c.low.resolve(it)
+	high, ok3 := c.high.resolve(it)
+	if !ok1 || !ok2 || !ok3 {
+		return false
+	}
+	cmpLow, ok := compareValues(value, low)
+	if !ok {
+		return false
+	}
+	cmpHigh, ok := compareValues(value, high)
+	if !ok {
+		return false
+	}
+	return cmpLow >= 0 && cmpHigh <= 0
+}
+
+type inCondition struct {
+	value operand
+	list  []operand
+}
+
+func (c inCondition) eval(it item) bool {
+	value, ok := c.value.resolve(it)
+	if !ok {
+		return false
+	}
+	for _, o := range c.list {
+		if candidate, ok := o.resolve(it); ok && equalValues(value, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+type functionCondition struct {
+	name string
+	path documentPath
+	arg  operand
+}
+
+func (c functionCondition) eval(it item) bool {
+	av, exists := getPath(it, c.path)
+	switch c.name {
+	case "attribute_exists":
+		return exists
+	case "attribute_not_exists":
+		return !exists
+	}
+	if !exists {
+		return false
+	}
+
+	arg, ok := c.arg.resolve(it)
+	if !ok {
+		return false
+	}
+	switch c.name {
+	case "attribute_type":
+		return arg.S != nil && attributeType(av) == *arg.S
+	case "begins_with":
+		if av.S != nil && arg.S != nil {
+			return strings.HasPrefix(*av.S, *arg.S)
+		}
+		if av.B != nil && arg.B != nil {
+			return bytes.HasPrefix(av.B, arg.B)
+		}
+		return false
+	case "contains":
+		return containsValue(av, arg)
+	}
+	return false
+}
+
+type andCondition struct {
+	left, right condition
+}
+
+func (c andCondition) eval(it item) bool {
+	return c.left.eval(it) && c.right.eval(it)
+}
+
+type orCondition struct {
+	left, right condition
+}
+
+func (c orCondition) eval(it item) bool {
+	return c.left.eval(it) || c.right.eval(it)
+}
+
+type notCondition struct {
+	cond condition
+}
+
+func (c notCondition) eval(it item) bool {
+	return !c.cond.eval(it)
+}
+
+// keyAttributes returns the top level attribute names referenced by the condition, in order of appearance
+func keyAttributes(c condition) []string {
+	var names []string
+	add := func(o operand) {
+		if p, ok := o.(pathOperand); ok {
+			for _, name := range names {
+				if name == p.path.top() {
+					return
+				}
+			}
+			names = append(names, p.path.top())
+		}
+	}
+
+	var walk func(c condition)
+	walk = func(c condition) {
+		switch v := c.(type) {
+		case compareCondition:
+			add(v.left)
+			add(v.right)
+		case betweenCondition:
+			add(v.value)
+		case functionCondition:
+			add(pathOperand{path: v.path})
+		case andCondition:
+			walk(v.left)
+			walk(v.right)
+		}
+	}
+	walk(c)
+	return names
+}
+
+// parser is a recursive descent parser for the DynamoDB expression syntax
+type parser struct {
+	tokens []token
+	pos    int
+	names  map[string]*string
+	values map[string]*dynamodb.AttributeValue
+}
+
+func newParser(expr string, names map[string]*string, values map[string]*dynamodb.AttributeValue) (*parser, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &parser{tokens: tokens, names: names, values: values}, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isSymbol(symbol string) bool {
+	t := p.peek()
+	return t.kind == tokenSymbol && t.text == symbol
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == tokenIdent && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) expectSymbol(symbol string) error {
+	if !p.isSymbol(symbol) {
+		return fmt.Errorf("expected %q but found %q", symbol, p.peek().text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) expectEOF() error {
+	if p.peek().kind != tokenEOF {
+		return fmt.Errorf("unexpected token %q", p.peek().text)
+	}
+	return nil
+}
+
+// parseCondition parses a complete condition expression
+func parseCondition(expr string, names map[string]*string, values map[string]*dynamodb.AttributeValue) (condition, error) {
+	p, err := newParser(expr, names, values)
+	if err != nil {
+		return nil, err
+	}
+	c, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return c, p.expectEOF()
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orCondition{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andCondition{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (condition, error) {
+	if p.isKeyword("NOT") {
+		p.next()
+		c, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notCondition{cond: c}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (condition, error) {
+	if p.isSymbol("(") {
+		p.next()
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		return c, p.expectSymbol(")")
+	}
+
+	if t := p.peek(); t.kind == tokenIdent && p.tokens[p.pos+1].kind == tokenSymbol && p.tokens[p.pos+1].text == "(" {
+		switch name := strings.ToLower(t.text); name {
+		case "attribute_exists", "attribute_not_exists", "attribute_type", "begins_with", "contains":
+			return p.parseFunction(name)
+		}
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	switch {
+	case t.kind == tokenSymbol && (t.text == "=" || t.text == "<>" || t.text == "<" || t.text == "<=" || t.text == ">" || t.text == ">="):
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareCondition{op: t.text, left: left, right: right}, nil
+	case p.isKeyword("BETWEEN"):
+		p.next()
+		low, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("AND") {
+			return nil, fmt.Errorf("expected AND in BETWEEN but found %q", p.peek().text)
+		}
+		p.next()
+		high, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return betweenCondition{value: left, low: low, high: high}, nil
+	case p.isKeyword("IN"):
+		p.next()
+		if err := p.expectSymbol("("); err != nil {
+			return nil, err
+		}
+		var list []operand
+		for {
+			o, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, o)
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+		return inCondition{value: left, list: list}, p.expectSymbol(")")
+	}
+
+	return nil, fmt.Errorf("expected a comparator but found %q", t.text)
+}
+
+func (p *parser) parseFunction(name string) (condition, error) {
+	p.next() // function name
+	p.next() // (
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	c := functionCondition{name: name, path: path}
+	if name != "attribute_exists" && name != "attribute_not_exists" {
+		if err := p.expectSymbol(","); err != nil {
+			return nil, err
+		}
+		if c.arg, err = p.parseOperand(); err != nil {
+			return nil, err
+		}
+	}
+	return c, p.expectSymbol(")")
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	t := p.peek()
+	if t.kind == tokenValue {
+		p.next()
+		value, ok := p.values[t.text]
+		if !ok {
+			return nil, fmt.Errorf("value placeholder %s is not defined", t.text)
+		}
+		return valueOperand{value: value}, nil
+	}
+
+	if t.kind == tokenIdent && strings.EqualFold(t.text, "size") && p.tokens[p.pos+1].kind == tokenSymbol && p.tokens[p.pos+1].text == "(" {
+		p.next()
+		p.next()
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		return sizeOperand{path: path}, p.expectSymbol(")")
+	}
+
+	path, err := p.parsePath()
+	if err != nil {
+		return nil, err
+	}
+	return pathOperand{path: path}, nil
+}
+
+func (p *parser) parseName() (string, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenIdent:
+		return t.text, nil
+	case tokenName:
+		name, ok := p.names[t.text]
+		if !ok || name == nil {
+			return "", fmt.Errorf("name placeholder %s is not defined", t.text)
+		}
+		return *name, nil
+	}
+	return "", fmt.Errorf("expected an attribute name but found %q", t.text)
+}
+
+func (p *parser) parsePath() (documentPath, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return nil, err
+	}
+	path := documentPath{{name: name}}
+	for {
+		switch {
+		case p.isSymbol("."):
+			p.next()
+			name, err := p.parseName()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, pathElement{name: name})
+		case p.isSymbol("["):
+			p.next()
+			t := p.next()
+			if t.kind != tokenNumber {
+				return nil, fmt.Errorf("expected a list index but found %q", t.text)
+			}
+			index, err := strconv.Atoi(t.text)
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, pathElement{index: index, isIndex: true})
+			if err := p.expectSymbol("]"); err != nil {
+				return nil, err
+			}
+		default:
+			return path, nil
+		}
+	}
+}
+
+// parseProjection parses a comma separated list of document paths
+func parseProjection(expr string, names map[string]*string) ([]documentPath, error) {
+	p, err := newParser(expr, names, nil)
+	if err != nil {
+		return nil, err
+	}
+	var paths []documentPath
+	for {
+		path, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+		if !p.isSymbol(",") {
+			break
+		}
+		p.next()
+	}
+	return paths, p.expectEOF()
+}
+
+// updateAction is a single SET, REMOVE, ADD or DELETE action of an update expression
+type updateAction struct {
+	action string
+	path   documentPath
+	value  setValue
+}
+
+// setValue is the right hand side of a SET action
+type setValue interface {
+	evaluate(it item) (*dynamodb.AttributeValue, error)
+}
+
+type operandValue struct {
+	operand operand
+}
+
+func (v operandValue) evaluate(it item) (*dynamodb.AttributeValue, error) {
+	av, ok := v.operand.resolve(it)
+	if !ok {
+		return nil, fmt.Errorf("the provided expression refers to an attribute that does not exist in the item")
+	}
+	return av, nil
+}
+
+type arithmeticValue struct {
+	op          string
+	left, right setValue
+}
+
+func (v arithmeticValue) evaluate(it item) (*dynamodb.AttributeValue, error) {
+	left, err := v.left.evaluate(it)
+	if err != nil {
+		return nil, err
+	}
+	right, err := v.right.evaluate(it)
+	if err != nil {
+		return nil, err
+	}
+	if left.N == nil || right.N == nil {
+		return nil, fmt.Errorf("an operand in the update expression has an incorrect data type")
+	}
+	if v.op == "-" {
+		return addNumbers(*left.N, "-"+strings.TrimPrefix(*right.N, "+"))
+	}
+	return addNumbers(*left.N, *right.N)
+}
+
+type ifNotExistsValue struct {
+	path     documentPath
+	fallback setValue
+}
+
+func (v ifNotExistsValue) evaluate(it item) (*dynamodb.AttributeValue, error) {
+	if av, ok := getPath(it, v.path); ok {
+		return av, nil
+	}
+	return v.fallback.evaluate(it)
+}
+
+type listAppendValue struct {
+	left, right setValue
+}
+
+func (v listAppendValue) evaluate(it item) (*dynamodb.AttributeValue, error) {
+	left, err := v.left.evaluate(it)
+	if err != nil {
+		return nil, err
+	}
+	right, err := v.right.evaluate(it)
+	if err != nil {
+		return nil, err
+	}
+	if left.L == nil || right.L == nil {
+		return nil, fmt.Errorf("an operand in the update expression has an incorrect data type")
+	}
+	list := make([]*dynamodb.AttributeValue, 0, len(left.L)+len(right.L))
+	list = append(list, left.L...)
+	list = append(list, right.L...)
+	return &dynamodb.AttributeValue{L: list}, nil
+}
+
+// parseUpdate parses an update expression into its actions
+func parseUpdate(expr string, names map[string]*string, values map[string]*dynamodb.AttributeValue) ([]updateAction, error) {
+	p, err := newParser(expr, names, values)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []updateAction
+	for p.peek().kind != tokenEOF {
+		t := p.next()
+		clause := strings.ToUpper(t.text)
+		if t.kind != tokenIdent || (clause != "SET" && clause != "REMOVE" && clause != "ADD" && clause != "DELETE") {
+			return nil, fmt.Errorf("expected SET, REMOVE, ADD or DELETE but found %q", t.text)
+		}
+
+		for {
+			path, err := p.parsePath()
+			if err != nil {
+				return nil, err
+			}
+			action := updateAction{action: clause, path: path}
+			switch clause {
+			case "SET":
+				if err := p.expectSymbol("="); err != nil {
+					return nil, err
+				}
+				if action.value, err = p.parseSetValue(); err != nil {
+					return nil, err
+				}
+			case "ADD", "DELETE":
+				o, err := p.parseOperand()
+				if err != nil {
+					return nil, err
+				}
+				action.value = operandValue{operand: o}
+			}
+			actions = append(actions, action)
+
+			if !p.isSymbol(",") {
+				break
+			}
+			p.next()
+		}
+	}
+
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("update expression is empty")
+	}
+	return actions, nil
+}
+
+func (p *parser) parseSetValue() (setValue, error) {
+	left, err := p.parseSetOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.isSymbol("+") || p.isSymbol("-") {
+		op := p.next().text
+		right, err := p.parseSetOperand()
+		if err != nil {
+			return nil, err
+		}
+		return arithmeticValue{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseSetOperand() (setValue, error) {
+	if t := p.peek(); t.kind == tokenIdent && p.tokens[p.pos+1].kind == tokenSymbol && p.tokens[p.pos+1].text == "(" {
+		switch strings.ToLower(t.text) {
+		case "if_not_exists":
+			p.next()
+			p.next()
+			path, err := p.parsePath()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(","); err != nil {
+				return nil, err
+			}
+			fallback, err := p.parseSetOperand()
+			if err != nil {
+				return nil, err
+			}
+			return ifNotExistsValue{path: path, fallback: fallback}, p.expectSymbol(")")
+		case "list_append":
+			p.next()
+			p.next()
+			left, err := p.parseSetOperand()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(","); err != nil {
+				return nil, err
+			}
+			right, err := p.parseSetOperand()
+			if err != nil {
+				return nil, err
+			}
+			return listAppendValue{left: left, right: right}, p.expectSymbol(")")
+		}
+	}
+
+	o, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return operandValue{operand: o}, nil
+}
+
+// applyUpdate applies the update actions to the item - all values are evaluated against the original item first,
+// the same as DynamoDB does
+func applyUpdate(it item, actions []updateAction) (item, error) {
+	resolved := make([]*dynamodb.AttributeValue, len(actions))
+	for i, action := range actions {
+		if action.value == nil {
+			continue
+		}
+		av, err := action.value.evaluate(it)
+		if err != nil {
+			return nil, err
+		}
+		resolved[i] = av
+	}
+
+	updated := copyItem(it)
+	for i, action := range actions {
+		switch action.action {
+		case "SET":
+			if err := setPath(updated, action.path, copyValue(resolved[i])); err != nil {
+				return nil, err
+			}
+		case "REMOVE":
+			removePath(updated, action.path)
+		case "ADD":
+			current, exists := getPath(updated, action.path)
+			if !exists {
+				if err := setPath(updated, action.path, copyValue(resolved[i])); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			sum, err := addValues(current, resolved[i])
+			if err != nil {
+				return nil, err
+			}
+			if err := setPath(updated, action.path, sum); err != nil {
+				return nil, err
+			}
+		case "DELETE":
+			current, exists := getPath(updated, action.path)
+			if !exists {
+				continue
+			}
+			remaining, err := deleteFromSet(current, resolved[i])
+			if err != nil {
+				return nil, err
+			}
+			if remaining == nil {
+				removePath(updated, action.path)
+			} else if err := setPath(updated, action.path, remaining); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return updated, nil
+}
+
+// getPath returns the value at the document path
+func getPath(it item, path documentPath) (*dynamodb.AttributeValue, bool) {
+	av, ok := it[path[0].name]
+	if !ok || av == nil {
+		return nil, false
+	}
+	for _, element := range path[1:] {
+		if element.isIndex {
+			if av.L == nil || element.index >= len(av.L) {
+				return nil, false
+			}
+			av = av.L[element.index]
+		} else {
+			if av.M == nil {
+				return nil, false
+			}
+			if av, ok = av.M[element.name]; !ok {
+				return nil, false
+			}
+		}
+		if av == nil {
+			return nil, false
+		}
+	}
+	return av, true
+}
+
+// setPath stores the value at the document path, the parent of the last element must exist
+func setPath(it item, path documentPath, value *dynamodb.AttributeValue) error {
+	if len(path) == 1 {
+		it[path[0].name] = value
+		return nil
+	}
+	parent, ok := getPath(it, path[:len(path)-1])
+	if !ok {
+		return fmt.Errorf("the document path provided in the update expression is invalid for update")
+	}
+	last := path[len(path)-1]
+	if last.isIndex {
+		if parent.L == nil {
+			return fmt.Errorf("the document path provided in the update expression is invalid for update")
+		}
+		if last.index >= len(parent.L) {
+			parent.L = append(parent.L, value)
+		} else {
+			parent.L[last.index] = value
+		}
+		return nil
+	}
+	if parent.M == nil {
+		return fmt.Errorf("the document path provided in the update expression is invalid for update")
+	}
+	parent.M[last.name] = value
+	return nil
+}
+
+// removePath deletes the value at the document path if it exists
+func removePath(it item, path documentPath) {
+	if len(path) == 1 {
+		delete(it, path[0].name)
+		return
+	}
+	parent, ok := getPath(it, path[:len(path)-1])
+	if !ok {
+		return
+	}
+	last := path[len(path)-1]
+	if last.isIndex {
+		if parent.L != nil && last.index < len(parent.L) {
+			parent.L = append(parent.L[:last.index], parent.L[last.index+1:]...)
+		}
+		return
+	}
+	if parent.M != nil {
+		delete(parent.M, last.name)
+	}
+}
+
+// projectItem returns a copy of the item with only the projected attributes
+func projectItem(it item, paths []documentPath) item {
+	if len(paths) == 0 {
+		return copyItem(it)
+	}
+	out := item{}
+	for _, path := range paths {
+		av, ok := getPath(it, path)
+		if !ok {
+			continue
+		}
+		if len(path) == 1 || path[1].isIndex {
+			out[path.top()] = copyValue(it[path.top()])
+			continue
+		}
+		// nested map attributes keep their parent maps
+		target := out
+		for _, element := range path[:len(path)-1] {
+			parent, ok := target[element.name]
+			if !ok || parent.M == nil {
+				parent = &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{}}
+				target[element.name] = parent
+			}
+			target = parent.M
+		}
+		target[path[len(path)-1].name] = copyValue(av)
+	}
+	return out
+}
+
+// copyItem returns a deep copy of the item
+func copyItem(it item) item {
+	if it == nil {
+		return nil
+	}
+	out := make(item, len(it))
+	for name, av := range it {
+		out[name] = copyValue(av)
+	}
+	return out
+}
+
+// copyValue returns a deep copy of the attribute value
+func copyValue(av *dynamodb.AttributeValue) *dynamodb.AttributeValue {
+	if av == nil {
+		return nil
+	}
+	out := &dynamodb.AttributeValue{}
+	if av.S != nil {
+		out.S = aws.String(*av.S)
+	}
+	if av.N != nil {
+		out.N = aws.String(*av.N)
+	}
+	if av.B != nil {
+		out.B = append([]byte{}, av.B...)
+	}
+	if av.BOOL != nil {
+		out.BOOL = aws.Bool(*av.BOOL)
+	}
+	if av.NULL != nil {
+		out.NULL = aws.Bool(*av.NULL)
+	}
+	if av.SS != nil {
+		out.SS = aws.StringSlice(aws.StringValueSlice(av.SS))
+	}
+	if av.NS != nil {
+		out.NS = aws.StringSlice(aws.StringValueSlice(av.NS))
+	}
+	if av.BS != nil {
+		out.BS = make([][]byte, len(av.BS))
+		for i, b := range av.BS {
+			out.BS[i] = append([]byte{}, b...)
+		}
+	}
+	if av.L != nil {
+		out.L = make([]*dynamodb.AttributeValue, len(av.L))
+		for i, v := range av.L {
+			out.L[i] = copyValue(v)
+		}
+	}
+	if av.M != nil {
+		out.M = make(map[string]*dynamodb.AttributeValue, len(av.M))
+		for k, v := range av.M {
+			out.M[k] = copyValue(v)
+		}
+	}
+	return out
+}
+
+// attributeType returns the DynamoDB type descriptor of the value, e.g. S, N or SS
+func attributeType(av *dynamodb.AttributeValue) string {
+	switch {
+	case av.S != nil:
+		return dynamodb.ScalarAttributeTypeS
+	case av.N != nil:
+		return dynamodb.ScalarAttributeTypeN
+	case av.B != nil:
+		return dynamodb.ScalarAttributeTypeB
+	case av.BOOL != nil:
+		return "BOOL"
+	case av.NULL != nil:
+		return "NULL"
+	case av.SS != nil:
+		return "SS"
+	case av.NS != nil:
+		return "NS"
+	case av.BS != nil:
+		return "BS"
+	case av.L != nil:
+		return "L"
+	case av.M != nil:
+		return "M"
+	}
+	return ""
+}
+
+func attributeSize(av *dynamodb.AttributeValue) (int, bool) {
+	switch {
+	case av.S != nil:
+		return len(*av.S), true
+	case av.B != nil:
+		return len(av.B), true
+	case av.SS != nil:
+		return len(av.SS), true
+	case av.NS != nil:
+		return len(av.NS), true
+	case av.BS != nil:
+		return len(av.BS), true
+	case av.L != nil:
+		return len(av.L), true
+	case av.M != nil:
+		return len(av.M), true
+	}
+	return 0, false
+}
+
+func parseNumber(n string) (*big.Float, bool) {
+	f, ok := new(big.Float).SetPrec(256).SetString(strings.TrimSpace(n))
+	return f, ok
+}
+
+// compareValues orders two scalar values of the same type
+func compareValues(a, b *dynamodb.AttributeValue) (int, bool) {
+	switch {
+	case a.S != nil && b.S != nil:
+		return strings.Compare(*a.S, *b.S), true
+	case a.N != nil && b.N != nil:
+		x, ok1 := parseNumber(*a.N)
+		y, ok2 := parseNumber(*b.N)
+		if !ok1 || !ok2 {
+			return 0, false
+		}
+		return x.Cmp(y), true
+	case a.B != nil && b.B != nil:
+		return bytes.Compare(a.B, b.B), true
+	}
+	return 0, false
+}
+
+// equalValues compares two values of any type, sets are compared regardless of order
+func equalValues(a, b *dynamodb.AttributeValue) bool {
+	if attributeType(a) != attributeType(b) {
+		return false
+	}
+	switch {
+	case a.S != nil, a.N != nil, a.B != nil:
+		cmp, ok := compareValues(a, b)
+		return ok && cmp == 0
+	case a.BOOL != nil:
+		return *a.BOOL == *b.BOOL
+	case a.NULL != nil:
+		return true
+	case a.SS != nil, a.NS != nil, a.BS != nil:
+		members := setMembers(a)
+		other := setMembers(b)
+		if len(members) != len(other) {
+			return false
+		}
+		for _, m := range members {
+			if !containsMember(other, m) {
+				return false
+			}
+		}
+		return true
+	case a.L != nil:
+		if len(a.L) != len(b.L) {
+			return false
+		}
+		for i := range a.L {
+			if !equalValues(a.L[i], b.L[i]) {
+				return false
+			}
+		}
+		return true
+	case a.M != nil:
+		if len(a.M) != len(b.M) {
+			return false
+		}
+		for k, v := range a.M {
+			w, ok := b.M[k]
+			if !ok || !equalValues(v, w) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// setMembers returns the members of a set as scalar values
+func setMembers(av *dynamodb.AttributeValue) []*dynamodb.AttributeValue {
+	var members []*dynamodb.AttributeValue
+	for _, s := range av.SS {
+		members = append(members, &dynamodb.AttributeValue{S: s})
+	}
+	for _, n := range av.NS {
+		members = append(members, &dynamodb.AttributeValue{N: n})
+	}
+	for _, b := range av.BS {
+		members = append(members, &dynamodb.AttributeValue{B: b})
+	}
+	return members
+}
+
+func containsMember(members []*dynamodb.AttributeValue, av *dynamodb.AttributeValue) bool {
+	for _, m := range members {
+		if equalValues(m, av) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsValue(av, arg *dynamodb.AttributeValue) bool {
+	switch {
+	case av.S != nil:
+		return arg.S != nil && strings.Contains(*av.S, *arg.S)
+	case av.B != nil:
+		return arg.B != nil && bytes.Contains(av.B, arg.B)
+	case av.SS != nil, av.NS != nil, av.BS != nil:
+		return containsMember(setMembers(av), arg)
+	case av.L != nil:
+		return containsMember(av.L, arg)
+	}
+	return false
+}
+
+func addNumbers(a, b string) (*dynamodb.AttributeValue, error) {
+	x, ok1 := parseNumber(a)
+	y, ok2 := parseNumber(b)
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("an operand in the update expression has an incorrect data type")
+	}
+	return &dynamodb.AttributeValue{N: aws.String(new(big.Float).SetPrec(256).Add(x, y).Text('f', -1))}, nil
+}
+
+// addValues implements the ADD action - numbers are summed and sets are merged
+func addValues(current, value *dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+	if current.N != nil && value.N != nil {
+		return addNumbers(*current.N, *value.N)
+	}
+	if attributeType(current) != attributeType(value) || (current.SS == nil && current.NS == nil && current.BS == nil) {
+		return nil, fmt.Errorf("an operand in the update expression has an incorrect data type")
+	}
+	merged := copyValue(current)
+	members := setMembers(current)
+	for _, m := range setMembers(value) {
+		if containsMember(members, m) {
+			continue
+		}
+		members = append(members, m)
+		switch {
+		case m.S != nil:
+			merged.SS = append(merged.SS, aws.String(*m.S))
+		case m.N != nil:
+			merged.NS = append(merged.NS, aws.String(*m.N))
+		case m.B != nil:
+			merged.BS = append(merged.BS, append([]byte{}, m.B...))
+		}
+	}
+	return merged, nil
+}
+
+// deleteFromSet implements the DELETE action, nil is returned when the set becomes empty
+func deleteFromSet(current, value *dynamodb.AttributeValue) (*dynamodb.AttributeValue, error) {
+	if attributeType(current) != attributeType(value) || (current.SS == nil && current.NS == nil && current.BS == nil) {
+		return nil, fmt.Errorf("an operand in the update expression has an incorrect data type")
+	}
+	remove := setMembers(value)
+	remaining := &dynamodb.AttributeValue{}
+	count := 0
+	for _, m := range setMembers(current) {
+		if containsMember(remove, m) {
+			continue
+		}
+		count++
+		switch {
+		case m.S != nil:
+			remaining.SS = append(remaining.SS, m.S)
+		case m.N != nil:
+			remaining.NS = append(remaining.NS, m.N)
+		case m.B != nil:
+			remaining.BS = append(remaining.BS, m.B)
+		}
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	return remaining, nil
+}
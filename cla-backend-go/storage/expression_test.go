@@ -0,0 +1,274 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func stringValue(s string) *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{S: aws.String(s)}
+}
+
+func numberValue(n string) *dynamodb.AttributeValue {
+	return &dynamodb.AttributeValue{N: aws.String(n)}
+}
+
+// newExpressionItem returns an item with an attribute of each type
+func newExpressionItem() item {
+	return item{
+		"signature_id":  stringValue("sig-1"),
+		"project_id":    stringValue("project-a"),
+		"version":       numberValue("2"),
+		"signed":        {BOOL: aws.Bool(true)},
+		"acl":           {SS: aws.StringSlice([]string{"manager-1", "manager-2"})},
+		"counts":        {NS: aws.StringSlice([]string{"1", "2"})},
+		"email_domains": {L: []*dynamodb.AttributeValue{stringValue("acme.com"), stringValue("example.org")}},
+		"user": {M: map[string]*dynamodb.AttributeValue{
+			"name":   stringValue("Jane"),
+			"emails": {L: []*dynamodb.AttributeValue{stringValue("jane@acme.com")}},
+		}},
+	}
+}
+
+var expressionValues = map[string]*dynamodb.AttributeValue{
+	":sig":      stringValue("sig-1"),
+	":other":    stringValue("sig-2"),
+	":prefix":   stringValue("project-"),
+	":one":      numberValue("1"),
+	":two":      numberValue("2"),
+	":three":    numberValue("3"),
+	":ten":      numberValue("10"),
+	":manager":  stringValue("manager-2"),
+	":domain":   stringValue("acme.com"),
+	":jane":     stringValue("Jane"),
+	":string":   stringValue("S"),
+	":list":     stringValue("L"),
+	":true":     {BOOL: aws.Bool(true)},
+	":a":        stringValue("a"),
+	":z":        stringValue("z"),
+	":versions": {L: []*dynamodb.AttributeValue{numberValue("3")}},
+	":managers": {SS: aws.StringSlice([]string{"manager-3"})},
+	":removed":  {SS: aws.StringSlice([]string{"manager-1", "manager-2"})},
+}
+
+var expressionNames = map[string]*string{
+	"#id":   aws.String("signature_id"),
+	"#user": aws.String("user"),
+}
+
+func TestConditionExpression(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expr     string
+		expected bool
+	}{
+		{"equal", "signature_id = :sig", true},
+		{"not equal", "signature_id = :other", false},
+		{"different", "signature_id <> :other", true},
+		{"missing attribute is never equal", "missing = :sig", false},
+		{"missing attribute is different", "missing <> :sig", true},
+		{"less than", "version < :three", true},
+		{"less than or equal", "version <= :two", true},
+		{"greater than", "version > :two", false},
+		{"greater than or equal", "version >= :two", true},
+		{"numbers are not compared as strings", "version < :ten", true},
+		{"strings are compared", "project_id > :a", true},
+		{"string and number are not comparable", "project_id < :ten", false},
+		{"boolean", "signed = :true", true},
+		{"between", "version BETWEEN :one AND :three", true},
+		{"between is inclusive", "version BETWEEN :two AND :ten", true},
+		{"not between", "version BETWEEN :three AND :ten", false},
+		{"between strings", "signature_id between :a AND :z", true},
+		{"in", "signature_id IN (:other, :sig)", true},
+		{"not in", "signature_id IN (:other)", false},
+		{"begins_with", "begins_with(project_id, :prefix)", true},
+		{"not begins_with", "begins_with(signature_id, :prefix)", false},
+		{"begins_with a missing attribute", "begins_with(missing, :prefix)", false},
+		{"contains a set member", "contains(acl, :manager)", true},
+		{"contains a list element", "contains(email_domains, :domain)", true},
+		{"contains a substring", "contains(project_id, :a)", true},
+		{"does not contain", "contains(acl, :domain)", false},
+		{"attribute_exists", "attribute_exists(signature_id)", true},
+		{"attribute_exists of a missing attribute", "attribute_exists(missing)", false},
+		{"attribute_not_exists", "attribute_not_exists(missing)", true},
+		{"attribute_type", "attribute_type(project_id, :string)", true},
+		{"attribute_type mismatch", "attribute_type(project_id, :list)", false},
+		{"size of a string", "size(project_id) > :three", true},
+		{"size of a set", "size(acl) = :two", true},
+		{"size of a list", "size(email_domains) = :two", true},
+		{"size of a missing attribute", "size(missing) = :one", false},
+		{"name placeholder", "#id = :sig", true},
+		{"map element", "#user.name = :jane", true},
+		{"list element", "email_domains[0] = :domain", true},
+		{"list element of a map", "contains(#user.emails[0], :domain)", true},
+		{"list index out of range", "attribute_exists(email_domains[5])", false},
+		{"and", "signature_id = :sig AND version = :two", true},
+		{"and with a false operand", "signature_id = :sig AND version = :three", false},
+		{"or", "signature_id = :other OR version = :two", true},
+		{"not", "NOT signature_id = :other", true},
+		{"not of a function", "NOT attribute_exists(signature_id)", false},
+		{"and binds tighter than or", "signature_id = :sig OR version = :three AND signed = :true", true},
+		{"parentheses", "(signature_id = :sig OR version = :three) AND version = :one", false},
+		{"nested parentheses", "NOT ((signature_id = :other) OR (version = :three))", true},
+		{"lower case keywords", "signature_id = :sig and not version = :three", true},
+	}
+
+	it := newExpressionItem()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := parseCondition(tc.expr, expressionNames, expressionValues)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, c.eval(it))
+			}
+		})
+	}
+}
+
+func TestConditionExpressionErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"undefined value", "signature_id = :undefined"},
+		{"undefined name", "#undefined = :sig"},
+		{"empty placeholder", "signature_id = :"},
+		{"unexpected character", "signature_id == :sig"},
+		{"missing comparator", "signature_id :sig"},
+		{"missing operand", "signature_id ="},
+		{"between without and", "version BETWEEN :one :three"},
+		{"unclosed parenthesis", "(signature_id = :sig"},
+		{"unclosed in", "signature_id IN (:sig"},
+		{"function without argument", "begins_with(project_id)"},
+		{"trailing token", "signature_id = :sig version"},
+		{"invalid list index", "email_domains[a] = :domain"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseCondition(tc.expr, expressionNames, expressionValues)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyAttributes(t *testing.T) {
+	c, err := parseCondition("#id = :sig AND begins_with(project_id, :prefix) AND signature_id <> :other", expressionNames, expressionValues)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"signature_id", "project_id"}, keyAttributes(c))
+	}
+}
+
+func TestUpdateExpression(t *testing.T) {
+	testCases := []struct {
+		name      string
+		expr      string
+		attribute string
+		expected  *dynamodb.AttributeValue
+	}{
+		{"set a new attribute", "SET note = :sig", "note", stringValue("sig-1")},
+		{"set replaces the value", "SET project_id = :other", "project_id", stringValue("sig-2")},
+		{"set with a name placeholder", "SET #id = :other", "signature_id", stringValue("sig-2")},
+		{"set a map element", "SET #user.name = :sig", "user", &dynamodb.AttributeValue{M: map[string]*dynamodb.AttributeValue{
+			"name":   stringValue("sig-1"),
+			"emails": {L: []*dynamodb.AttributeValue{stringValue("jane@acme.com")}},
+		}}},
+		{"set a list element", "SET email_domains[1] = :domain", "email_domains", &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{stringValue("acme.com"), stringValue("acme.com")}}},
+		{"set plus", "SET version = version + :one", "version", numberValue("3")},
+		{"set minus", "SET version = version - :three", "version", numberValue("-1")},
+		{"set if_not_exists of an existing attribute", "SET version = if_not_exists(version, :ten)", "version", numberValue("2")},
+		{"set if_not_exists of a missing attribute", "SET total = if_not_exists(total, :ten)", "total", numberValue("10")},
+		{"set if_not_exists plus", "SET total = if_not_exists(total, :one) + :two", "total", numberValue("3")},
+		{"set list_append", "SET versions = list_append(if_not_exists(versions, :versions), :versions)", "versions", &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{numberValue("3"), numberValue("3")}}},
+		{"remove", "REMOVE project_id", "project_id", nil},
+		{"remove a list element", "REMOVE email_domains[0]", "email_domains", &dynamodb.AttributeValue{L: []*dynamodb.AttributeValue{stringValue("example.org")}}},
+		{"add to a number", "ADD version :ten", "version", numberValue("12")},
+		{"add a missing number", "ADD total :ten", "total", numberValue("10")},
+		{"add to a set", "ADD acl :managers", "acl", &dynamodb.AttributeValue{SS: aws.StringSlice([]string{"manager-1", "manager-2", "manager-3"})}},
+		{"delete from a set", "DELETE acl :managers", "acl", &dynamodb.AttributeValue{SS: aws.StringSlice([]string{"manager-1", "manager-2"})}},
+		{"delete all set members removes the attribute", "DELETE acl :removed", "acl", nil},
+		{"values are evaluated before the update", "SET version = :ten, previous = version", "previous", numberValue("2")},
+		{"several clauses", "SET note = :sig REMOVE project_id ADD version :one", "version", numberValue("3")},
+		{"lower case clause", "set note = :sig", "note", stringValue("sig-1")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			it := newExpressionItem()
+			actions, err := parseUpdate(tc.expr, expressionNames, expressionValues)
+			if !assert.NoError(t, err) {
+				return
+			}
+			updated, err := applyUpdate(it, actions)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tc.expected == nil {
+				assert.NotContains(t, updated, tc.attribute)
+			} else {
+				assert.True(t, equalValues(tc.expected, updated[tc.attribute]), "expected %v, got %v", tc.expected, updated[tc.attribute])
+			}
+			// the original item is left unchanged
+			assert.Equal(t, newExpressionItem(), it)
+		})
+	}
+}
+
+func TestUpdateExpressionErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		expr string
+	}{
+		{"empty", ""},
+		{"unknown clause", "UPSERT note = :sig"},
+		{"set without value", "SET note"},
+		{"undefined value", "SET note = :undefined"},
+		{"set from a missing attribute", "SET note = missing"},
+		{"plus on a string", "SET project_id = project_id + :one"},
+		{"list_append on a string", "SET project_id = list_append(project_id, :versions)"},
+		{"add a string", "ADD project_id :sig"},
+		{"add a number to a set", "ADD acl :one"},
+		{"delete from a number", "DELETE version :managers"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actions, err := parseUpdate(tc.expr, expressionNames, expressionValues)
+			if err == nil {
+				_, err = applyUpdate(newExpressionItem(), actions)
+			}
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProjectionExpression(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expr     string
+		expected item
+	}{
+		{"top level attributes", "signature_id, version", item{"signature_id": stringValue("sig-1"), "version": numberValue("2")}},
+		{"name placeholder", "#id", item{"signature_id": stringValue("sig-1")}},
+		{"map element", "#user.name", item{"user": {M: map[string]*dynamodb.AttributeValue{"name": stringValue("Jane")}}}},
+		{"missing attribute", "signature_id, missing", item{"signature_id": stringValue("sig-1")}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			paths, err := parseProjection(tc.expr, expressionNames)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.expected, projectItem(newExpressionItem(), paths))
+			}
+		})
+	}
+
+	_, err := parseProjection("signature_id,", expressionNames)
+	assert.Error(t, err)
+}
@@ -0,0 +1,622 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// errValidation is the DynamoDB error code for malformed requests
+const errValidation = "ValidationException"
+
+// MemoryDriver is an in-process storage driver - the tables are kept in memory and the global secondary
+// indexes are emulated from the table schemas, so the repositories work unchanged against it
+type MemoryDriver struct {
+	lock   sync.RWMutex
+	tables map[string]*memoryTable
+}
+
+type memoryTable struct {
+	schema TableSchema
+	items  map[string]item
+}
+
+// NewMemoryDriver creates an empty in-memory store with the provided tables
+func NewMemoryDriver(schemas ...TableSchema) *MemoryDriver {
+	d := &MemoryDriver{
+		tables: make(map[string]*memoryTable, len(schemas)),
+	}
+	for _, schema := range schemas {
+		d.tables[schema.TableName] = &memoryTable{
+			schema: schema,
+			items:  make(map[string]item),
+		}
+	}
+	return d
+}
+
+func validationError(format string, args ...interface{}) error {
+	return awserr.New(errValidation, fmt.Sprintf(format, args...), nil)
+}
+
+func (d *MemoryDriver) table(tableName *string) (*memoryTable, error) {
+	t, ok := d.tables[aws.StringValue(tableName)]
+	if !ok {
+		return nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, fmt.Sprintf("Requested resource not found: Table: %s not found", aws.StringValue(tableName)), nil)
+	}
+	return t, nil
+}
+
+// encodeKeyValue returns a string form of a key attribute, used to identify the items
+func encodeKeyValue(av *dynamodb.AttributeValue) (string, bool) {
+	switch {
+	case av == nil:
+		return "", false
+	case av.S != nil:
+		return "S:" + *av.S, true
+	case av.N != nil:
+		n, ok := parseNumber(*av.N)
+		if !ok {
+			return "", false
+		}
+		return "N:" + n.Text('g', -1), true
+	case av.B != nil:
+		return "B:" + base64.StdEncoding.EncodeToString(av.B), true
+	}
+	return "", false
+}
+
+// primaryKey returns the identity of the item in the table
+func (t *memoryTable) primaryKey(it item) (string, error) {
+	hash, ok := encodeKeyValue(it[t.schema.HashKey])
+	if !ok {
+		return "", validationError("One of the required keys was not given a value: %s", t.schema.HashKey)
+	}
+	if t.schema.RangeKey == "" {
+		return hash, nil
+	}
+	rangeKey, ok := encodeKeyValue(it[t.schema.RangeKey])
+	if !ok {
+		return "", validationError("One of the required keys was not given a value: %s", t.schema.RangeKey)
+	}
+	return hash + "|" + rangeKey, nil
+}
+
+// keyOf returns only the primary key attributes of the item
+func (t *memoryTable) keyOf(it item, extra ...string) item {
+	key := item{t.schema.HashKey: copyValue(it[t.schema.HashKey])}
+	if t.schema.RangeKey != "" {
+		key[t.schema.RangeKey] = copyValue(it[t.schema.RangeKey])
+	}
+	for _, name := range extra {
+		if av, ok := it[name]; ok {
+			key[name] = copyValue(av)
+		}
+	}
+	return key
+}
+
+func (t *memoryTable) validateKey(key item) error {
+	expected := 1
+	if t.schema.RangeKey != "" {
+		expected = 2
+	}
+	if len(key) != expected {
+		return validationError("The provided key element does not match the schema")
+	}
+	_, err := t.primaryKey(key)
+	return err
+}
+
+func checkCondition(expr *string, names map[string]*string, values map[string]*dynamodb.AttributeValue, it item) error {
+	if aws.StringValue(expr) == "" {
+		return nil
+	}
+	c, err := parseCondition(*expr, names, values)
+	if err != nil {
+		return validationError("Invalid ConditionExpression: %s", err)
+	}
+	if !c.eval(it) {
+		return awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "The conditional request failed", nil)
+	}
+	return nil
+}
+
+func projection(expr *string, names map[string]*string) ([]documentPath, error) {
+	if aws.StringValue(expr) == "" {
+		return nil, nil
+	}
+	paths, err := parseProjection(*expr, names)
+	if err != nil {
+		return nil, validationError("Invalid ProjectionExpression: %s", err)
+	}
+	return paths, nil
+}
+
+// GetItem returns the item with the provided key
+func (d *MemoryDriver) GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.validateKey(input.Key); err != nil {
+		return nil, err
+	}
+	paths, err := projection(input.ProjectionExpression, input.ExpressionAttributeNames)
+	if err != nil {
+		return nil, err
+	}
+
+	key, _ := t.primaryKey(input.Key)
+	existing, ok := t.items[key]
+	if !ok {
+		return &dynamodb.GetItemOutput{}, nil
+	}
+	return &dynamodb.GetItemOutput{Item: projectItem(existing, paths)}, nil
+}
+
+// PutItem creates or replaces the item
+func (d *MemoryDriver) PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.putItem(input)
+}
+
+func (d *MemoryDriver) putItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error) {
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	key, err := t.primaryKey(input.Item)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := t.items[key]
+	if err = checkCondition(input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, existing); err != nil {
+		return nil, err
+	}
+
+	t.items[key] = copyItem(input.Item)
+
+	out := &dynamodb.PutItemOutput{}
+	if aws.StringValue(input.ReturnValues) == dynamodb.ReturnValueAllOld && existing != nil {
+		out.Attributes = copyItem(existing)
+	}
+	return out, nil
+}
+
+// UpdateItem edits the attributes of the item, creating it if it does not exist
+func (d *MemoryDriver) UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.updateItem(input)
+}
+
+func (d *MemoryDriver) updateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error) {
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.validateKey(input.Key); err != nil {
+		return nil, err
+	}
+
+	key, _ := t.primaryKey(input.Key)
+	existing, exists := t.items[key]
+	if err = checkCondition(input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, existing); err != nil {
+		return nil, err
+	}
+
+	original := existing
+	if !exists {
+		original = copyItem(input.Key)
+	}
+
+	updated := copyItem(original)
+	if aws.StringValue(input.UpdateExpression) != "" {
+		actions, err := parseUpdate(*input.UpdateExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues)
+		if err != nil {
+			return nil, validationError("Invalid UpdateExpression: %s", err)
+		}
+		for _, action := range actions {
+			if action.path.top() == t.schema.HashKey || action.path.top() == t.schema.RangeKey {
+				return nil, validationError("Cannot update attribute %s. This attribute is part of the key", action.path.top())
+			}
+		}
+		if updated, err = applyUpdate(original, actions); err != nil {
+			return nil, validationError("%s", err)
+		}
+	}
+
+	t.items[key] = updated
+
+	out := &dynamodb.UpdateItemOutput{}
+	switch aws.StringValue(input.ReturnValues) {
+	case dynamodb.ReturnValueAllNew, dynamodb.ReturnValueUpdatedNew:
+		out.Attributes = copyItem(updated)
+	case dynamodb.ReturnValueAllOld, dynamodb.ReturnValueUpdatedOld:
+		if exists {
+			out.Attributes = copyItem(existing)
+		}
+	}
+	return out, nil
+}
+
+// DeleteItem removes the item with the provided key
+func (d *MemoryDriver) DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.deleteItem(input)
+}
+
+func (d *MemoryDriver) deleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error) {
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if err = t.validateKey(input.Key); err != nil {
+		return nil, err
+	}
+
+	key, _ := t.primaryKey(input.Key)
+	existing, exists := t.items[key]
+	if err = checkCondition(input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues, existing); err != nil {
+		return nil, err
+	}
+
+	delete(t.items, key)
+
+	out := &dynamodb.DeleteItemOutput{}
+	if aws.StringValue(input.ReturnValues) == dynamodb.ReturnValueAllOld && exists {
+		out.Attributes = copyItem(existing)
+	}
+	return out, nil
+}
+
+// indexSchema returns the key schema used to read the table or the named index. Indexes missing from the table
+// schema are derived from the attributes of the key condition.
+func (t *memoryTable) indexSchema(indexName *string, keyCondition condition) (KeySchema, error) {
+	if aws.StringValue(indexName) == "" {
+		return t.schema.KeySchema, nil
+	}
+	if schema, ok := t.schema.Indexes[*indexName]; ok {
+		return schema, nil
+	}
+	if keyCondition == nil {
+		return KeySchema{}, validationError("The table does not have the specified index: %s", *indexName)
+	}
+	names := keyAttributes(keyCondition)
+	if len(names) == 0 {
+		return KeySchema{}, validationError("Query condition missed key schema element")
+	}
+	schema := KeySchema{HashKey: names[0]}
+	if len(names) > 1 {
+		schema.RangeKey = names[1]
+	}
+	return schema, nil
+}
+
+// sortedItems returns the items which are part of the index, ordered by the index range key
+func (t *memoryTable) sortedItems(schema KeySchema) []item {
+	type entry struct {
+		key string
+		it  item
+	}
+	entries := make([]entry, 0, len(t.items))
+	for key, it := range t.items {
+		// global secondary indexes are sparse - only items with the index keys are part of the index
+		if _, ok := encodeKeyValue(it[schema.HashKey]); !ok {
+			continue
+		}
+		if schema.RangeKey != "" {
+			if _, ok := encodeKeyValue(it[schema.RangeKey]); !ok {
+				continue
+			}
+		}
+		entries = append(entries, entry{key: key, it: it})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if schema.RangeKey != "" {
+			cmp, _ := compareValues(entries[i].it[schema.RangeKey], entries[j].it[schema.RangeKey])
+			if cmp != 0 {
+				return cmp < 0
+			}
+		}
+		return entries[i].key < entries[j].key
+	})
+
+	out := make([]item, len(entries))
+	for i, e := range entries {
+		out[i] = e.it
+	}
+	return out
+}
+
+// readRequest holds the common Query and Scan parameters
+type readRequest struct {
+	keyCondition      condition
+	filter            condition
+	paths             []documentPath
+	schema            KeySchema
+	limit             int64
+	exclusiveStartKey item
+	forward           bool
+	count             bool
+}
+
+// read evaluates a Query or a Scan - the limit applies to the evaluated items, before the filter, like DynamoDB
+func (t *memoryTable) read(r readRequest) ([]item, int64, int64, item, error) {
+	candidates := t.sortedItems(r.schema)
+	if !r.forward {
+		for i, j := 0, len(candidates)-1; i < j; i, j = i+1, j-1 {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		}
+	}
+
+	start := 0
+	if len(r.exclusiveStartKey) > 0 {
+		startKey, err := t.primaryKey(r.exclusiveStartKey)
+		if err != nil {
+			return nil, 0, 0, nil, validationError("The provided starting key is invalid: %s", err)
+		}
+		for i, it := range candidates {
+			if key, _ := t.primaryKey(it); key == startKey {
+				start = i + 1
+				break
+			}
+		}
+	}
+
+	var items []item
+	var count, scanned int64
+	var lastEvaluated, lastEvaluatedKey item
+	for _, it := range candidates[start:] {
+		if r.keyCondition != nil && !r.keyCondition.eval(it) {
+			continue
+		}
+		if r.limit > 0 && scanned == r.limit {
+			// more items remain, the caller continues from the last one we evaluated
+			lastEvaluatedKey = t.keyOf(lastEvaluated, r.schema.HashKey, r.schema.RangeKey)
+			break
+		}
+		lastEvaluated = it
+		scanned++
+		if r.filter != nil && !r.filter.eval(it) {
+			continue
+		}
+		count++
+		if !r.count {
+			items = append(items, projectItem(it, r.paths))
+		}
+	}
+
+	return items, count, scanned, lastEvaluatedKey, nil
+}
+
+// keyConditionsToCondition converts the legacy KeyConditions parameter to a condition
+func keyConditionsToCondition(keyConditions map[string]*dynamodb.Condition) (condition, error) {
+	var result condition
+	names := make([]string, 0, len(keyConditions))
+	for name := range keyConditions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		kc := keyConditions[name]
+		path := pathOperand{path: documentPath{{name: name}}}
+		args := make([]operand, len(kc.AttributeValueList))
+		for i, av := range kc.AttributeValueList {
+			args[i] = valueOperand{value: av}
+		}
+
+		var c condition
+		switch op := aws.StringValue(kc.ComparisonOperator); {
+		case op == dynamodb.ComparisonOperatorBetween && len(args) == 2:
+			c = betweenCondition{value: path, low: args[0], high: args[1]}
+		case op == dynamodb.ComparisonOperatorBeginsWith && len(args) == 1:
+			c = functionCondition{name: "begins_with", path: path.path, arg: args[0]}
+		case len(args) == 1:
+			symbols := map[string]string{
+				dynamodb.ComparisonOperatorEq: "=",
+				dynamodb.ComparisonOperatorLt: "<",
+				dynamodb.ComparisonOperatorLe: "<=",
+				dynamodb.ComparisonOperatorGt: ">",
+				dynamodb.ComparisonOperatorGe: ">=",
+			}
+			symbol, ok := symbols[op]
+			if !ok {
+				return nil, validationError("Unsupported KeyConditions operator: %s", op)
+			}
+			c = compareCondition{op: symbol, left: path, right: args[0]}
+		default:
+			return nil, validationError("Invalid KeyConditions for attribute: %s", name)
+		}
+
+		if result == nil {
+			result = c
+		} else {
+			result = andCondition{left: result, right: c}
+		}
+	}
+
+	if result == nil {
+		return nil, validationError("Either the KeyConditions or KeyConditionExpression parameter must be specified")
+	}
+	return result, nil
+}
+
+// Query returns the items of the table or index matching the key condition
+func (d *MemoryDriver) Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	r := readRequest{
+		limit:             aws.Int64Value(input.Limit),
+		exclusiveStartKey: input.ExclusiveStartKey,
+		forward:           input.ScanIndexForward == nil || *input.ScanIndexForward,
+		count:             aws.StringValue(input.Select) == dynamodb.SelectCount,
+	}
+
+	if aws.StringValue(input.KeyConditionExpression) != "" {
+		if r.keyCondition, err = parseCondition(*input.KeyConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues); err != nil {
+			return nil, validationError("Invalid KeyConditionExpression: %s", err)
+		}
+	} else if r.keyCondition, err = keyConditionsToCondition(input.KeyConditions); err != nil {
+		return nil, err
+	}
+
+	if r.schema, err = t.indexSchema(input.IndexName, r.keyCondition); err != nil {
+		return nil, err
+	}
+	if !containsName(keyAttributes(r.keyCondition), r.schema.HashKey) {
+		return nil, validationError("Query condition missed key schema element: %s", r.schema.HashKey)
+	}
+
+	if aws.StringValue(input.FilterExpression) != "" {
+		if r.filter, err = parseCondition(*input.FilterExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues); err != nil {
+			return nil, validationError("Invalid FilterExpression: %s", err)
+		}
+	}
+	if r.paths, err = projection(input.ProjectionExpression, input.ExpressionAttributeNames); err != nil {
+		return nil, err
+	}
+
+	items, count, scanned, lastEvaluatedKey, err := t.read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dynamodb.QueryOutput{
+		Count:            aws.Int64(count),
+		ScannedCount:     aws.Int64(scanned),
+		LastEvaluatedKey: lastEvaluatedKey,
+	}
+	if !r.count {
+		out.Items = toAttributeMaps(items)
+	}
+	return out, nil
+}
+
+// Scan returns all the items of the table or index matching the filter
+func (d *MemoryDriver) Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	r := readRequest{
+		limit:             aws.Int64Value(input.Limit),
+		exclusiveStartKey: input.ExclusiveStartKey,
+		forward:           true,
+		count:             aws.StringValue(input.Select) == dynamodb.SelectCount,
+	}
+
+	if r.schema, err = t.indexSchema(input.IndexName, nil); err != nil {
+		return nil, err
+	}
+	if aws.StringValue(input.FilterExpression) != "" {
+		if r.filter, err = parseCondition(*input.FilterExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues); err != nil {
+			return nil, validationError("Invalid FilterExpression: %s", err)
+		}
+	}
+	if r.paths, err = projection(input.ProjectionExpression, input.ExpressionAttributeNames); err != nil {
+		return nil, err
+	}
+
+	items, count, scanned, lastEvaluatedKey, err := t.read(r)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dynamodb.ScanOutput{
+		Count:            aws.Int64(count),
+		ScannedCount:     aws.Int64(scanned),
+		LastEvaluatedKey: lastEvaluatedKey,
+	}
+	if !r.count {
+		out.Items = toAttributeMaps(items)
+	}
+	return out, nil
+}
+
+// DescribeTable returns the table key schema and item count
+func (d *MemoryDriver) DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error) {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	t, err := d.table(input.TableName)
+	if err != nil {
+		return nil, err
+	}
+
+	description := &dynamodb.TableDescription{
+		TableName:   aws.String(t.schema.TableName),
+		TableStatus: aws.String(dynamodb.TableStatusActive),
+		ItemCount:   aws.Int64(int64(len(t.items))),
+		KeySchema:   keySchemaElements(t.schema.KeySchema),
+	}
+
+	indexNames := make([]string, 0, len(t.schema.Indexes))
+	for name := range t.schema.Indexes {
+		indexNames = append(indexNames, name)
+	}
+	sort.Strings(indexNames)
+	for _, name := range indexNames {
+		description.GlobalSecondaryIndexes = append(description.GlobalSecondaryIndexes, &dynamodb.GlobalSecondaryIndexDescription{
+			IndexName:   aws.String(name),
+			IndexStatus: aws.String(dynamodb.IndexStatusActive),
+			KeySchema:   keySchemaElements(t.schema.Indexes[name]),
+		})
+	}
+
+	return &dynamodb.DescribeTableOutput{Table: description}, nil
+}
+
+func keySchemaElements(schema KeySchema) []*dynamodb.KeySchemaElement {
+	elements := []*dynamodb.KeySchemaElement{
+		{AttributeName: aws.String(schema.HashKey), KeyType: aws.String(dynamodb.KeyTypeHash)},
+	}
+	if schema.RangeKey != "" {
+		elements = append(elements, &dynamodb.KeySchemaElement{AttributeName: aws.String(schema.RangeKey), KeyType: aws.String(dynamodb.KeyTypeRange)})
+	}
+	return elements
+}
+
+func toAttributeMaps(items []item) []map[string]*dynamodb.AttributeValue {
+	out := make([]map[string]*dynamodb.AttributeValue, len(items))
+	for i, it := range items {
+		out[i] = it
+	}
+	return out
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// The in-memory operations complete immediately, the context variants only check that the context is not done
+
+// GetItemWithContext is GetItem with the context of the caller
+func (d *MemoryDriver) GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInput, _ ...request.Option) (*dynamodb.GetItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.GetItem(input)
+}
+
+// PutItemWithContext is PutItem with the context of the caller
+func (d *MemoryDriver) PutItemWithContext(ctx aws.Context, input *dynamodb.PutItemInput, _ ...request.Option) (*dynamodb.PutItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.PutItem(input)
+}
+
+// UpdateItemWithContext is UpdateItem with the context of the caller
+func (d *MemoryDriver) UpdateItemWithContext(ctx aws.Context, input *dynamodb.UpdateItemInput, _ ...request.Option) (*dynamodb.UpdateItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.UpdateItem(input)
+}
+
+// DeleteItemWithContext is DeleteItem with the context of the caller
+func (d *MemoryDriver) DeleteItemWithContext(ctx aws.Context, input *dynamodb.DeleteItemInput, _ ...request.Option) (*dynamodb.DeleteItemOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.DeleteItem(input)
+}
+
+// QueryWithContext is Query with the context of the caller
+func (d *MemoryDriver) QueryWithContext(ctx aws.Context, input *dynamodb.QueryInput, _ ...request.Option) (*dynamodb.QueryOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.Query(input)
+}
+
+// ScanWithContext is Scan with the context of the caller
+func (d *MemoryDriver) ScanWithContext(ctx aws.Context, input *dynamodb.ScanInput, _ ...request.Option) (*dynamodb.ScanOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.Scan(input)
+}
@@ -0,0 +1,375 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSignature struct {
+	SignatureID       string   `dynamodbav:"signature_id"`
+	ProjectID         string   `dynamodbav:"signature_project_id"`
+	ReferenceID       string   `dynamodbav:"signature_reference_id"`
+	SignatureType     string   `dynamodbav:"signature_type"`
+	Signed            bool     `dynamodbav:"signature_signed"`
+	DateModified      string   `dynamodbav:"date_modified"`
+	EmailApprovalList []string `dynamodbav:"email_whitelist,omitempty"`
+	UserCCLACompanyID string   `dynamodbav:"signature_user_ccla_company_id,omitempty"`
+	SignatureVersion  int      `dynamodbav:"signature_document_major_version"`
+	SignatureACL      []string `dynamodbav:"signature_acl,stringset,omitempty"`
+}
+
+const testTable = "cla-test-signatures"
+
+func newTestDriver(t *testing.T) *MemoryDriver {
+	d := NewMemoryDriver(TableSchemas("test")...)
+	signatures := []testSignature{
+		{SignatureID: "sig-1", ProjectID: "project-a", ReferenceID: "user-1", SignatureType: "cla", Signed: true, DateModified: "2020-09-03T10:00:00Z", SignatureVersion: 1},
+		{SignatureID: "sig-2", ProjectID: "project-a", ReferenceID: "company-1", SignatureType: "ccla", Signed: true, DateModified: "2020-09-01T10:00:00Z", SignatureVersion: 2, SignatureACL: []string{"manager-1"}},
+		{SignatureID: "sig-3", ProjectID: "project-a", ReferenceID: "user-2", SignatureType: "cla", Signed: false, DateModified: "2020-09-02T10:00:00Z", SignatureVersion: 1, UserCCLACompanyID: "company-1"},
+		{SignatureID: "sig-4", ProjectID: "project-b", ReferenceID: "user-1", SignatureType: "cla", Signed: true, DateModified: "2020-09-04T10:00:00Z", SignatureVersion: 1},
+	}
+	for _, s := range signatures {
+		av, err := dynamodbattribute.MarshalMap(s)
+		assert.NoError(t, err)
+		_, err = d.PutItem(&dynamodb.PutItemInput{TableName: aws.String(testTable), Item: av})
+		assert.NoError(t, err)
+	}
+	return d
+}
+
+func signatureIDs(t *testing.T, items []map[string]*dynamodb.AttributeValue) []string {
+	var signatures []testSignature
+	assert.NoError(t, dynamodbattribute.UnmarshalListOfMaps(items, &signatures))
+	ids := []string{}
+	for _, s := range signatures {
+		ids = append(ids, s.SignatureID)
+	}
+	return ids
+}
+
+func TestMemoryDriverGetItem(t *testing.T) {
+	d := newTestDriver(t)
+
+	result, err := d.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(testTable),
+		Key:       map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String("sig-2")}},
+	})
+	assert.NoError(t, err)
+	var s testSignature
+	assert.NoError(t, dynamodbattribute.UnmarshalMap(result.Item, &s))
+	assert.Equal(t, "company-1", s.ReferenceID)
+	assert.Equal(t, []string{"manager-1"}, s.SignatureACL)
+
+	result, err = d.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(testTable),
+		Key:       map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String("missing")}},
+	})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Item)
+
+	_, err = d.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String("cla-test-unknown"),
+		Key:       map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String("sig-1")}},
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, dynamodb.ErrCodeResourceNotFoundException, err.(awserr.Error).Code())
+	}
+}
+
+func TestMemoryDriverQuery(t *testing.T) {
+	d := newTestDriver(t)
+
+	testCases := []struct {
+		name      string
+		indexName string
+		key       expression.KeyConditionBuilder
+		filter    *expression.ConditionBuilder
+		forward   bool
+		expected  []string
+	}{
+		{
+			name:      "hash key only",
+			indexName: "project-signature-index",
+			key:       expression.Key("signature_project_id").Equal(expression.Value("project-a")),
+			forward:   true,
+			expected:  []string{"sig-1", "sig-2", "sig-3"},
+		},
+		{
+			name:      "sorted by the range key descending",
+			indexName: "project-signature-date-index",
+			key:       expression.Key("signature_project_id").Equal(expression.Value("project-a")),
+			expected:  []string{"sig-1", "sig-3", "sig-2"},
+		},
+		{
+			name:      "hash and range key",
+			indexName: "signature-project-reference-index",
+			key: expression.Key("signature_project_id").Equal(expression.Value("project-a")).
+				And(expression.Key("signature_reference_id").BeginsWith("user-")),
+			forward:  true,
+			expected: []string{"sig-1", "sig-3"},
+		},
+		{
+			name:      "with filter",
+			indexName: "project-signature-index",
+			key:       expression.Key("signature_project_id").Equal(expression.Value("project-a")),
+			filter: func() *expression.ConditionBuilder {
+				c := expression.Name("signature_signed").Equal(expression.Value(true)).
+					And(expression.Name("signature_user_ccla_company_id").AttributeNotExists())
+				return &c
+			}(),
+			forward:  true,
+			expected: []string{"sig-1", "sig-2"},
+		},
+		{
+			name:      "sparse index",
+			indexName: "signature-user-ccla-company-index",
+			key:       expression.Key("signature_user_ccla_company_id").Equal(expression.Value("company-1")),
+			forward:   true,
+			expected:  []string{"sig-3"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			builder := expression.NewBuilder().WithKeyCondition(tc.key)
+			if tc.filter != nil {
+				builder = builder.WithFilter(*tc.filter)
+			}
+			expr, err := builder.Build()
+			assert.NoError(t, err)
+
+			result, err := d.Query(&dynamodb.QueryInput{
+				TableName:                 aws.String(testTable),
+				IndexName:                 aws.String(tc.indexName),
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+				KeyConditionExpression:    expr.KeyCondition(),
+				FilterExpression:          expr.Filter(),
+				ScanIndexForward:          aws.Bool(tc.forward),
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, signatureIDs(t, result.Items))
+			assert.Equal(t, int64(len(tc.expected)), *result.Count)
+		})
+	}
+}
+
+func TestMemoryDriverQueryPagination(t *testing.T) {
+	d := newTestDriver(t)
+
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(expression.Key("signature_project_id").Equal(expression.Value("project-a"))).
+		WithProjection(expression.NamesList(expression.Name("signature_id"))).
+		Build()
+	assert.NoError(t, err)
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(testTable),
+		IndexName:                 aws.String("project-signature-date-index"),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		Limit:                     aws.Int64(2),
+	}
+
+	var ids []string
+	pages := 0
+	for {
+		result, err := d.Query(input)
+		assert.NoError(t, err)
+		pages++
+		for _, it := range result.Items {
+			assert.Len(t, it, 1)
+		}
+		ids = append(ids, signatureIDs(t, result.Items)...)
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	assert.Equal(t, 2, pages)
+	assert.Equal(t, []string{"sig-2", "sig-3", "sig-1"}, ids)
+}
+
+func TestMemoryDriverLegacyKeyConditions(t *testing.T) {
+	d := newTestDriver(t)
+
+	result, err := d.Query(&dynamodb.QueryInput{
+		TableName: aws.String(testTable),
+		IndexName: aws.String("reference-signature-index"),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"signature_reference_id": {
+				ComparisonOperator: aws.String(dynamodb.ComparisonOperatorEq),
+				AttributeValueList: []*dynamodb.AttributeValue{{S: aws.String("user-1")}},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sig-1", "sig-4"}, signatureIDs(t, result.Items))
+}
+
+func TestMemoryDriverUpdateItem(t *testing.T) {
+	d := newTestDriver(t)
+	key := map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String("sig-2")}}
+
+	update := expression.Set(expression.Name("signature_signed"), expression.Value(false)).
+		Set(expression.Name("email_whitelist"), expression.Value([]string{"a@example.org"})).
+		Add(expression.Name("signature_document_major_version"), expression.Value(1)).
+		Add(expression.Name("signature_acl"), expression.Value(&dynamodb.AttributeValue{SS: aws.StringSlice([]string{"manager-2", "manager-1"})})).
+		Remove(expression.Name("date_modified"))
+	expr, err := expression.NewBuilder().
+		WithUpdate(update).
+		WithCondition(expression.Name("signature_id").AttributeExists()).
+		Build()
+	assert.NoError(t, err)
+
+	result, err := d.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String(testTable),
+		Key:                       key,
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ReturnValues:              aws.String(dynamodb.ReturnValueAllNew),
+	})
+	assert.NoError(t, err)
+
+	var s testSignature
+	assert.NoError(t, dynamodbattribute.UnmarshalMap(result.Attributes, &s))
+	assert.False(t, s.Signed)
+	assert.Equal(t, 3, s.SignatureVersion)
+	assert.Equal(t, []string{"a@example.org"}, s.EmailApprovalList)
+	assert.ElementsMatch(t, []string{"manager-1", "manager-2"}, s.SignatureACL)
+	assert.Empty(t, s.DateModified)
+
+	// the update condition fails for an item which does not exist
+	_, err = d.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String(testTable),
+		Key:                       map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String("missing")}},
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, dynamodb.ErrCodeConditionalCheckFailedException, err.(awserr.Error).Code())
+	}
+
+	// the key attributes can not be updated
+	_, err = d.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:                 aws.String(testTable),
+		Key:                       key,
+		ExpressionAttributeNames:  map[string]*string{"#id": aws.String("signature_id")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":id": {S: aws.String("sig-9")}},
+		UpdateExpression:          aws.String("SET #id = :id"),
+	})
+	assert.Error(t, err)
+}
+
+func TestMemoryDriverConditionalPutAndDelete(t *testing.T) {
+	d := newTestDriver(t)
+	newItem := map[string]*dynamodb.AttributeValue{
+		"signature_id":         {S: aws.String("sig-1")},
+		"signature_project_id": {S: aws.String("project-c")},
+	}
+
+	_, err := d.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(testTable),
+		Item:                newItem,
+		ConditionExpression: aws.String("attribute_not_exists(signature_id)"),
+	})
+	if assert.Error(t, err) {
+		assert.Equal(t, dynamodb.ErrCodeConditionalCheckFailedException, err.(awserr.Error).Code())
+	}
+
+	_, err = d.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName:                 aws.String(testTable),
+		Key:                       map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String("sig-1")}},
+		ConditionExpression:       aws.String("signature_type IN (:cla, :ccla) AND NOT signature_signed = :false"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":cla": {S: aws.String("cla")}, ":ccla": {S: aws.String("ccla")}, ":false": {BOOL: aws.Bool(false)}},
+	})
+	assert.NoError(t, err)
+
+	_, err = d.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(testTable),
+		Item:                newItem,
+		ConditionExpression: aws.String("attribute_not_exists(signature_id)"),
+	})
+	assert.NoError(t, err)
+
+	scan, err := d.Scan(&dynamodb.ScanInput{
+		TableName:                 aws.String(testTable),
+		FilterExpression:          aws.String("signature_project_id = :project"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":project": {S: aws.String("project-c")}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"sig-1"}, signatureIDs(t, scan.Items))
+	assert.Equal(t, int64(4), *scan.ScannedCount)
+
+	describe, err := d.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(testTable)})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), *describe.Table.ItemCount)
+}
+
+func TestMemoryDriverTransactWriteItems(t *testing.T) {
+	d := newTestDriver(t)
+	key := func(id string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String(id)}}
+	}
+	moveSignature := func(from, to string) *dynamodb.TransactWriteItemsInput {
+		return &dynamodb.TransactWriteItemsInput{
+			TransactItems: []*dynamodb.TransactWriteItem{
+				{Put: &dynamodb.Put{
+					TableName:           aws.String(testTable),
+					Item:                map[string]*dynamodb.AttributeValue{"signature_id": {S: aws.String(to)}, "signature_project_id": {S: aws.String("project-a")}},
+					ConditionExpression: aws.String("attribute_not_exists(signature_id)"),
+				}},
+				{Delete: &dynamodb.Delete{
+					TableName:           aws.String(testTable),
+					Key:                 key(from),
+					ConditionExpression: aws.String("attribute_exists(signature_id)"),
+				}},
+			},
+		}
+	}
+
+	// the put is applied before the failed delete condition, the transaction leaves both untouched
+	_, err := d.TransactWriteItems(moveSignature("sig-9", "sig-5"))
+	if assert.Error(t, err) {
+		canceled, ok := err.(*dynamodb.TransactionCanceledException)
+		if assert.True(t, ok) {
+			assert.Equal(t, dynamodb.ErrCodeTransactionCanceledException, canceled.Code())
+			assert.Equal(t, "None", *canceled.CancellationReasons[0].Code)
+			assert.Equal(t, "ConditionalCheckFailed", *canceled.CancellationReasons[1].Code)
+		}
+	}
+	out, err := d.GetItem(&dynamodb.GetItemInput{TableName: aws.String(testTable), Key: key("sig-5")})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Item)
+
+	_, err = d.TransactWriteItems(moveSignature("sig-4", "sig-5"))
+	assert.NoError(t, err)
+	out, err = d.GetItem(&dynamodb.GetItemInput{TableName: aws.String(testTable), Key: key("sig-5")})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, out.Item)
+	out, err = d.GetItem(&dynamodb.GetItemInput{TableName: aws.String(testTable), Key: key("sig-4")})
+	assert.NoError(t, err)
+	assert.Empty(t, out.Item)
+
+	// a transaction can't apply two actions to the same item
+	_, err = d.TransactWriteItems(moveSignature("sig-5", "sig-5"))
+	if assert.Error(t, err) {
+		assert.Equal(t, errValidation, err.(awserr.Error).Code())
+	}
+}
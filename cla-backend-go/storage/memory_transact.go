@@ -0,0 +1,158 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// maxTransactItems is the maximum number of actions of a DynamoDB transaction
+const maxTransactItems = 25
+
+// cancellation reason codes of the canceled transactions
+const (
+	cancellationReasonNone                   = "None"
+	cancellationReasonConditionalCheckFailed = "ConditionalCheckFailed"
+)
+
+// transactTarget is the item an action of a transaction applies to, with its state before the transaction
+type transactTarget struct {
+	table    *memoryTable
+	key      string
+	original item
+	exists   bool
+}
+
+// TransactWriteItems applies the puts, updates, deletes and condition checks of the request all together or none of
+// them - when a condition fails the transaction is canceled with the reason of each action, like DynamoDB does
+func (d *MemoryDriver) TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if len(input.TransactItems) == 0 || len(input.TransactItems) > maxTransactItems {
+		return nil, validationError("Member must have length less than or equal to %d and greater than 0", maxTransactItems)
+	}
+
+	targets := make([]transactTarget, 0, len(input.TransactItems))
+	rollback := func() {
+		for i := len(targets) - 1; i >= 0; i-- {
+			if targets[i].exists {
+				targets[i].table.items[targets[i].key] = targets[i].original
+			} else {
+				delete(targets[i].table.items, targets[i].key)
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(input.TransactItems))
+	reasons := make([]*dynamodb.CancellationReason, len(input.TransactItems))
+	canceled := false
+	for i, action := range input.TransactItems {
+		target, err := d.transactTarget(action)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		id := target.table.schema.TableName + "/" + target.key
+		if seen[id] {
+			rollback()
+			return nil, validationError("Transaction request cannot include multiple operations on one item")
+		}
+		seen[id] = true
+		targets = append(targets, target)
+
+		switch {
+		case action.ConditionCheck != nil:
+			err = checkCondition(action.ConditionCheck.ConditionExpression, action.ConditionCheck.ExpressionAttributeNames, action.ConditionCheck.ExpressionAttributeValues, target.original)
+		case action.Put != nil:
+			_, err = d.putItem(&dynamodb.PutItemInput{
+				TableName:                 action.Put.TableName,
+				Item:                      action.Put.Item,
+				ConditionExpression:       action.Put.ConditionExpression,
+				ExpressionAttributeNames:  action.Put.ExpressionAttributeNames,
+				ExpressionAttributeValues: action.Put.ExpressionAttributeValues,
+			})
+		case action.Update != nil:
+			_, err = d.updateItem(&dynamodb.UpdateItemInput{
+				TableName:                 action.Update.TableName,
+				Key:                       action.Update.Key,
+				UpdateExpression:          action.Update.UpdateExpression,
+				ConditionExpression:       action.Update.ConditionExpression,
+				ExpressionAttributeNames:  action.Update.ExpressionAttributeNames,
+				ExpressionAttributeValues: action.Update.ExpressionAttributeValues,
+			})
+		case action.Delete != nil:
+			_, err = d.deleteItem(&dynamodb.DeleteItemInput{
+				TableName:                 action.Delete.TableName,
+				Key:                       action.Delete.Key,
+				ConditionExpression:       action.Delete.ConditionExpression,
+				ExpressionAttributeNames:  action.Delete.ExpressionAttributeNames,
+				ExpressionAttributeValues: action.Delete.ExpressionAttributeValues,
+			})
+		}
+
+		reasons[i] = &dynamodb.CancellationReason{Code: aws.String(cancellationReasonNone)}
+		if err != nil {
+			if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+				reasons[i] = &dynamodb.CancellationReason{
+					Code:    aws.String(cancellationReasonConditionalCheckFailed),
+					Message: aws.String(aerr.Message()),
+				}
+				canceled = true
+				continue
+			}
+			rollback()
+			return nil, err
+		}
+	}
+
+	if canceled {
+		rollback()
+		return nil, &dynamodb.TransactionCanceledException{
+			Message_:            aws.String("Transaction cancelled, please refer cancellation reasons for specific reasons"),
+			CancellationReasons: reasons,
+		}
+	}
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+// transactTarget returns the item the action of the transaction applies to
+func (d *MemoryDriver) transactTarget(action *dynamodb.TransactWriteItem) (transactTarget, error) {
+	var tableName *string
+	var key item
+	switch {
+	case action.ConditionCheck != nil:
+		tableName, key = action.ConditionCheck.TableName, action.ConditionCheck.Key
+	case action.Put != nil:
+		tableName, key = action.Put.TableName, action.Put.Item
+	case action.Update != nil:
+		tableName, key = action.Update.TableName, action.Update.Key
+	case action.Delete != nil:
+		tableName, key = action.Delete.TableName, action.Delete.Key
+	default:
+		return transactTarget{}, validationError("TransactItems can only contain one of Check, Put, Update or Delete")
+	}
+
+	t, err := d.table(tableName)
+	if err != nil {
+		return transactTarget{}, err
+	}
+	id, err := t.primaryKey(key)
+	if err != nil {
+		return transactTarget{}, err
+	}
+	original, exists := t.items[id]
+	return transactTarget{table: t, key: id, original: original, exists: exists}, nil
+}
+
+// TransactWriteItemsWithContext is TransactWriteItems with the context of the caller
+func (d *MemoryDriver) TransactWriteItemsWithContext(ctx aws.Context, input *dynamodb.TransactWriteItemsInput, _ ...request.Option) (*dynamodb.TransactWriteItemsOutput, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return d.TransactWriteItems(input)
+}
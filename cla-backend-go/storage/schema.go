@@ -0,0 +1,217 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import "fmt"
+
+// KeySchema is the hash key and optional range key of a table or a global secondary index
+type KeySchema struct {
+	HashKey  string
+	RangeKey string
+}
+
+// TableSchema describes a table and its global secondary indexes
+type TableSchema struct {
+	TableName string
+	KeySchema
+	Indexes map[string]KeySchema
+}
+
+// TableSchemas returns the schemas of the EasyCLA tables for the stage - these mirror the DynamoDB tables
+// and global secondary indexes the repositories query
+func TableSchemas(stage string) []TableSchema {
+	tableName := func(name string) string {
+		return fmt.Sprintf("cla-%s-%s", stage, name)
+	}
+
+	return []TableSchema{
+		{
+			TableName: tableName("ccla-whitelist-requests"),
+			KeySchema: KeySchema{HashKey: "request_id"},
+			Indexes: map[string]KeySchema{
+				"company-id-project-id-index":                 {HashKey: "company_id", RangeKey: "project_id"},
+				"ccla-approval-list-request-project-id-index": {HashKey: "project_id"},
+			},
+		},
+		{
+			TableName: tableName("cla-manager-requests"),
+			KeySchema: KeySchema{HashKey: "request_id"},
+			Indexes: map[string]KeySchema{
+				"cla-manager-requests-company-project-index": {HashKey: "company_id", RangeKey: "project_id"},
+				"cla-manager-requests-project-index":         {HashKey: "project_id"},
+			},
+		},
+		{
+			TableName: tableName("companies"),
+			KeySchema: KeySchema{HashKey: "company_id"},
+			Indexes: map[string]KeySchema{
+				"company-name-index":     {HashKey: "company_name"},
+				"external-company-index": {HashKey: "company_external_id"},
+			},
+		},
+		{
+			TableName: tableName("company-invites"),
+			KeySchema: KeySchema{HashKey: "company_invite_id"},
+			Indexes: map[string]KeySchema{
+				"requested-company-index": {HashKey: "requested_company_id"},
+			},
+		},
+		{
+			TableName: tableName("dynamo-event-failures"),
+			KeySchema: KeySchema{HashKey: "failed_event_id"},
+		},
+		{
+			TableName: tableName("event-deliveries"),
+			KeySchema: KeySchema{HashKey: "delivery_id"},
+			Indexes: map[string]KeySchema{
+				"subscription-id-date-index": {HashKey: "subscription_id", RangeKey: "date_created"},
+				"pending-next-attempt-index": {HashKey: "pending", RangeKey: "next_attempt_epoch"},
+			},
+		},
+		{
+			TableName: tableName("event-subscriptions"),
+			KeySchema: KeySchema{HashKey: "subscription_id"},
+			Indexes: map[string]KeySchema{
+				"foundation-sfid-index": {HashKey: "foundation_sfid"},
+				"cla-group-id-index":    {HashKey: "cla_group_id"},
+			},
+		},
+		{
+			TableName: tableName("events"),
+			KeySchema: KeySchema{HashKey: "event_id"},
+			Indexes: map[string]KeySchema{
+				"event-type-index":                                    {HashKey: "event_type"},
+				"user-id-index":                                       {HashKey: "event_user_id"},
+				"company-id-event-type-index":                         {HashKey: "company_id", RangeKey: "event_type"},
+				"event-project-id-event-time-epoch-index":             {HashKey: "event_project_id", RangeKey: "event_time_epoch"},
+				"event-foundation-sfid-event-time-epoch-index":        {HashKey: "event_foundation_sfid", RangeKey: "event_time_epoch"},
+				"company-sfid-foundation-sfid-event-time-epoch-index": {HashKey: "company_sfid_foundation_sfid", RangeKey: "event_time_epoch"},
+				"company-sfid-project-id-event-time-epoch-index":      {HashKey: "company_sfid_project_id", RangeKey: "event_time_epoch"},
+				"event-date-and-contains-pii-event-time-epoch-index":  {HashKey: "event_date_and_contains_pii", RangeKey: "event_time_epoch"},
+			},
+		},
+		{
+			TableName: tableName("gerrit-instances"),
+			KeySchema: KeySchema{HashKey: "gerrit_id"},
+			Indexes: map[string]KeySchema{
+				"gerrit-project-id-index":   {HashKey: "project_id"},
+				"gerrit-project-sfid-index": {HashKey: "project_sfid"},
+				"gerrit-name-index":         {HashKey: "gerrit_name"},
+			},
+		},
+		{
+			TableName: tableName("github-orgs"),
+			KeySchema: KeySchema{HashKey: "organization_name"},
+			Indexes: map[string]KeySchema{
+				"github-org-sfid-index":                {HashKey: "organization_sfid"},
+				"project-sfid-organization-name-index": {HashKey: "project_sfid", RangeKey: "organization_name"},
+				"organization-name-lower-search-index": {HashKey: "organization_name_lower"},
+			},
+		},
+		{
+			TableName: tableName("metrics"),
+			KeySchema: KeySchema{HashKey: "metric_type", RangeKey: "id"},
+			Indexes: map[string]KeySchema{
+				"metric-type-salesforce-id-index": {HashKey: "metric_type", RangeKey: "salesforce_id"},
+			},
+		},
+		{
+			TableName: tableName("metrics-history"),
+			KeySchema: KeySchema{HashKey: "series_id", RangeKey: "date"},
+		},
+		{
+			TableName: tableName("metrics-members"),
+			KeySchema: KeySchema{HashKey: "counter_id", RangeKey: "member_id"},
+		},
+		{
+			TableName: tableName("projects"),
+			KeySchema: KeySchema{HashKey: "project_id"},
+			Indexes: map[string]KeySchema{
+				"external-project-index":             {HashKey: "project_external_id"},
+				"project-name-search-index":          {HashKey: "project_name"},
+				"project-name-lower-search-index":    {HashKey: "project_name_lower"},
+				"foundation-sfid-project-name-index": {HashKey: "foundation_sfid", RangeKey: "project_name"},
+			},
+		},
+		{
+			TableName: tableName("projects-cla-groups"),
+			KeySchema: KeySchema{HashKey: "project_sfid"},
+			Indexes: map[string]KeySchema{
+				"cla-group-id-index":    {HashKey: "cla_group_id"},
+				"foundation-sfid-index": {HashKey: "foundation_sfid"},
+			},
+		},
+		{
+			TableName: tableName("pull-request-checks"),
+			KeySchema: KeySchema{HashKey: "check_id"},
+			Indexes: map[string]KeySchema{
+				"cla-group-id-index": {HashKey: "cla_group_id"},
+			},
+		},
+		{
+			TableName: tableName("repositories"),
+			KeySchema: KeySchema{HashKey: "repository_id"},
+			Indexes: map[string]KeySchema{
+				"repository-name-index":                           {HashKey: "repository_name"},
+				"project-repository-index":                        {HashKey: "repository_project_id"},
+				"repository-organization-name-index":              {HashKey: "repository_organization_name"},
+				"sfdc-repository-index":                           {HashKey: "repository_sfdc_id"},
+				"project-sfid-repository-index":                   {HashKey: "project_sfid"},
+				"project-sfid-repository-organization-name-index": {HashKey: "project_sfid", RangeKey: "repository_organization_name"},
+				"external-repository-index":                       {HashKey: "repository_external_id"},
+			},
+		},
+		{
+			TableName: tableName("resign-campaigns"),
+			KeySchema: KeySchema{HashKey: "campaign_id"},
+			Indexes: map[string]KeySchema{
+				"cla-group-id-index": {HashKey: "cla_group_id"},
+			},
+		},
+		{
+			TableName: tableName("session-store"),
+			KeySchema: KeySchema{HashKey: "id"},
+		},
+		{
+			TableName: tableName("signatures"),
+			KeySchema: KeySchema{HashKey: "signature_id"},
+			Indexes: map[string]KeySchema{
+				"project-signature-index":                               {HashKey: "signature_project_id"},
+				"project-signature-date-index":                          {HashKey: "signature_project_id", RangeKey: "date_modified"},
+				"project-signature-external-id-index":                   {HashKey: "signature_project_external_id"},
+				"reference-signature-index":                             {HashKey: "signature_reference_id"},
+				"reference-signature-search-index":                      {HashKey: "signature_project_id", RangeKey: "signature_reference_name_lower"},
+				"signature-project-reference-index":                     {HashKey: "signature_project_id", RangeKey: "signature_reference_id"},
+				"signature-project-id-type-index":                       {HashKey: "signature_project_id", RangeKey: "signature_type"},
+				"signature-project-id-sigtype-signed-approved-id-index": {HashKey: "signature_project_id", RangeKey: "sigtype_signed_approved_id"},
+				"signature-user-ccla-company-index":                     {HashKey: "signature_user_ccla_company_id", RangeKey: "signature_project_id"},
+				"signature-company-signatory-index":                     {HashKey: "signature_company_signatory_id"},
+				"signature-company-initial-manager-index":               {HashKey: "signature_company_initial_manager_id"},
+			},
+		},
+		{
+			TableName: tableName("store"),
+			KeySchema: KeySchema{HashKey: "key"},
+		},
+		{
+			TableName: tableName("templates"),
+			KeySchema: KeySchema{HashKey: "template_id", RangeKey: "template_version"},
+		},
+		{
+			TableName: tableName("user-permissions"),
+			KeySchema: KeySchema{HashKey: "username"},
+		},
+		{
+			TableName: tableName("users"),
+			KeySchema: KeySchema{HashKey: "user_id"},
+			Indexes: map[string]KeySchema{
+				"github-user-index":             {HashKey: "user_github_id"},
+				"github-username-index":         {HashKey: "user_github_username"},
+				"github-user-external-id-index": {HashKey: "user_external_id"},
+				"lf-username-index":             {HashKey: "lf_username"},
+				"lf-email-index":                {HashKey: "lf_email"},
+			},
+		},
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// Storage driver names
+const (
+	DynamoDBDriverName = "dynamodb"
+	MemoryDriverName   = "memory"
+)
+
+// Driver is the storage API used by the repositories. The method set matches the DynamoDB client so the
+// repositories keep building their requests with the DynamoDB request and expression models. The WithContext
+// variants carry the context of the caller, e.g. for the tracing of the DynamoDB calls of a request.
+type Driver interface {
+	GetItem(input *dynamodb.GetItemInput) (*dynamodb.GetItemOutput, error)
+	PutItem(input *dynamodb.PutItemInput) (*dynamodb.PutItemOutput, error)
+	UpdateItem(input *dynamodb.UpdateItemInput) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(input *dynamodb.DeleteItemInput) (*dynamodb.DeleteItemOutput, error)
+	Query(input *dynamodb.QueryInput) (*dynamodb.QueryOutput, error)
+	Scan(input *dynamodb.ScanInput) (*dynamodb.ScanOutput, error)
+	DescribeTable(input *dynamodb.DescribeTableInput) (*dynamodb.DescribeTableOutput, error)
+	TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error)
+
+	GetItemWithContext(ctx aws.Context, input *dynamodb.GetItemInput, opts ...request.Option) (*dynamodb.GetItemOutput, error)
+	PutItemWithContext(ctx aws.Context, input *dynamodb.PutItemInput, opts ...request.Option) (*dynamodb.PutItemOutput, error)
+	UpdateItemWithContext(ctx aws.Context, input *dynamodb.UpdateItemInput, opts ...request.Option) (*dynamodb.UpdateItemOutput, error)
+	DeleteItemWithContext(ctx aws.Context, input *dynamodb.DeleteItemInput, opts ...request.Option) (*dynamodb.DeleteItemOutput, error)
+	QueryWithContext(ctx aws.Context, input *dynamodb.QueryInput, opts ...request.Option) (*dynamodb.QueryOutput, error)
+	ScanWithContext(ctx aws.Context, input *dynamodb.ScanInput, opts ...request.Option) (*dynamodb.ScanOutput, error)
+	TransactWriteItemsWithContext(ctx aws.Context, input *dynamodb.TransactWriteItemsInput, opts ...request.Option) (*dynamodb.TransactWriteItemsOutput, error)
+}
+
+// NewDriver returns the storage driver with the given name - the repositories share the returned driver, so the
+// in-memory driver keeps all the tables of the stage in a single in-process store
+func NewDriver(name string, awsSession *session.Session, stage string) (Driver, error) {
+	switch name {
+	case DynamoDBDriverName:
+		return dynamodb.New(awsSession), nil
+	case MemoryDriverName:
+		log.Infof("using the in-memory storage driver for stage: %s", stage)
+		return NewMemoryDriver(TableSchemas(stage)...), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", name)
+	}
+}
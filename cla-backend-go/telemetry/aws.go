@@ -0,0 +1,95 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentAWSSession records the latency and the errors of the DynamoDB calls made by the clients created from
+// the session, and traces the AWS calls made with the context of a traced request - the clients copy the handlers
+// of the session, so it must be called before the clients are created. The otelaws instrumentation only supports
+// the v2 SDK, the spans of the v1 SDK calls are started by the handlers of the session.
+func InstrumentAWSSession(awsSession *session.Session) {
+	awsSession.Handlers.Build.PushFrontNamed(request.NamedHandler{
+		Name: "easycla.telemetry.StartSpan",
+		Fn: func(r *request.Request) {
+			ctx, span := startChildSpan(r.Context(), fmt.Sprintf("%s.%s", r.ClientInfo.ServiceName, operationName(r)), trace.WithSpanKind(trace.SpanKindClient))
+			if !span.IsRecording() {
+				return
+			}
+			span.SetAttributes(
+				attribute.String("aws.service", r.ClientInfo.ServiceName),
+				attribute.String("aws.operation", operationName(r)),
+			)
+			if table := tableName(r.Params); table != "" {
+				span.SetAttributes(attribute.String("db.table", table))
+			}
+			r.SetContext(context.WithValue(ctx, awsSpanKey{}, span))
+		},
+	})
+	awsSession.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "easycla.telemetry.EndCall",
+		Fn: func(r *request.Request) {
+			if span, ok := r.Context().Value(awsSpanKey{}).(trace.Span); ok {
+				span.SetAttributes(
+					attribute.String("aws.request_id", r.RequestID),
+					attribute.Int("aws.retries", r.RetryCount),
+				)
+				endSpan(span, r.Error)
+			}
+			if r.ClientInfo.ServiceName != dynamodb.ServiceName {
+				return
+			}
+			ObserveDynamoDBRequest(operationName(r), tableName(r.Params), errorCode(r.Error), time.Since(r.Time))
+		},
+	})
+}
+
+// awsSpanKey holds the span of an AWS call in the context of the request, apart from the span of the caller
+type awsSpanKey struct{}
+
+func operationName(r *request.Request) string {
+	if r.Operation == nil {
+		return ""
+	}
+	return r.Operation.Name
+}
+
+// tableName returns the table of the input of a single table operation, the batch and transaction operations
+// have no table name
+func tableName(params interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(params))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	field := v.FieldByName("TableName")
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() {
+		return ""
+	}
+	if name, ok := field.Elem().Interface().(string); ok {
+		return name
+	}
+	return ""
+}
+
+func errorCode(err error) string {
+	if err == nil {
+		return ""
+	}
+	if aerr, ok := err.(awserr.Error); ok {
+		return aerr.Code()
+	}
+	return "error"
+}
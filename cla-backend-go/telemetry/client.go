@@ -0,0 +1,144 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	runtimeClient "github.com/go-openapi/runtime/client"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// clientTransport records the latency and the errors of the operations of a generated swagger client
+type clientTransport struct {
+	service string
+	next    runtime.ClientTransport
+}
+
+// NewClientTransport returns the transport of a generated swagger client of the external service, the calls are
+// recorded with the operation ID as method
+func NewClientTransport(service, host, basePath string, schemes []string) runtime.ClientTransport {
+	next := runtimeClient.New(host, basePath, schemes)
+	next.Transport = newTracedTransport(service, next.Transport)
+	return &clientTransport{
+		service: service,
+		next:    next,
+	}
+}
+
+// Submit submits the operation and records its latency, the operation is traced when its context belongs to a trace
+func (t *clientTransport) Submit(operation *runtime.ClientOperation) (interface{}, error) {
+	start := time.Now()
+	if ctx, ok := traceContext(operation.Context); ok {
+		operation.Context = withClientMethod(ctx, operation.ID)
+	}
+
+	result, err := t.next.Submit(operation)
+	ObserveClientRequest(t.service, operation.ID, clientErrorStatus(err), time.Since(start))
+	return result, err
+}
+
+// clientErrorStatus returns the status of a failed operation - the status code of the responses which are not
+// declared by the swagger specification of the service, "error" otherwise
+func clientErrorStatus(err error) string {
+	if err == nil {
+		return ""
+	}
+	if apiErr, ok := err.(*runtime.APIError); ok {
+		return strconv.Itoa(apiErr.Code)
+	}
+	return "error"
+}
+
+// roundTripper records the latency and the errors of the requests of a HTTP client
+type roundTripper struct {
+	service string
+	method  string
+	next    http.RoundTripper
+}
+
+// RoundTrip sends the request and records its latency, the responses with a 4xx or 5xx status code are errors - the
+// request is traced when its context belongs to a trace
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	if ctx, ok := traceContext(req.Context()); ok {
+		// A round tripper must not modify the request of the caller
+		req = req.WithContext(withClientMethod(ctx, t.method))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	status := ""
+	switch {
+	case err != nil:
+		status = "error"
+	case resp.StatusCode >= http.StatusBadRequest:
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	ObserveClientRequest(t.service, t.method, status, time.Since(start))
+	return resp, err
+}
+
+// NewHTTPClient returns a HTTP client which records its requests to the external service as calls of the method
+func NewHTTPClient(service, method string) *http.Client {
+	return NewHTTPClientWithTransport(service, method, http.DefaultTransport)
+}
+
+// NewHTTPClientWithTransport is NewHTTPClient sending the requests with the transport
+func NewHTTPClientWithTransport(service, method string, transport http.RoundTripper) *http.Client {
+	return &http.Client{
+		Transport: &roundTripper{service: service, method: method, next: newTracedTransport(service, transport)},
+	}
+}
+
+// tracedTransport sends the requests made with the context of a trace with the otelhttp transport, which records
+// their spans and sends the trace context to the service - the requests made outside of a trace, e.g. by the
+// background jobs, would each start a trace of their own and are sent untraced
+type tracedTransport struct {
+	plain  http.RoundTripper
+	traced http.RoundTripper
+}
+
+func newTracedTransport(service string, next http.RoundTripper) *tracedTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &tracedTransport{
+		plain: next,
+		traced: otelhttp.NewTransport(next,
+			otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+				return fmt.Sprintf("%s.%s", service, clientMethod(r.Context()))
+			}),
+			otelhttp.WithSpanOptions(trace.WithAttributes(semconv.PeerServiceKey.String(service))),
+		),
+	}
+}
+
+// RoundTrip sends the request with the otelhttp transport when its context belongs to a trace
+func (t *tracedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		return t.plain.RoundTrip(req)
+	}
+	return t.traced.RoundTrip(req)
+}
+
+// clientMethodKey holds the method of the call in the context of a traced request, the name of its span
+type clientMethodKey struct{}
+
+func withClientMethod(ctx context.Context, method string) context.Context {
+	return context.WithValue(ctx, clientMethodKey{}, method)
+}
+
+func clientMethod(ctx context.Context) string {
+	if method, ok := ctx.Value(clientMethodKey{}).(string); ok {
+		return method
+	}
+	return "request"
+}
@@ -0,0 +1,60 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// EnableEMF makes the registry keep the observations of the histograms until they are flushed as CloudWatch
+// embedded metric format (EMF) logs - used in Lambda mode where nothing can scrape the process
+func (r *Registry) EnableEMF() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.emf = true
+}
+
+// FlushEMF writes the observations since the last flush as embedded metric format records, one JSON document
+// per line - CloudWatch extracts the metrics of the namespace from the log lines of the Lambda function
+func (r *Registry) FlushEMF(w io.Writer, namespace string) error {
+	r.mu.Lock()
+	collectors := make([]collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.Unlock()
+
+	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+	encoder := json.NewEncoder(w)
+	var encodeErr error
+	emit := func(record map[string]interface{}) {
+		if encodeErr == nil {
+			encodeErr = encoder.Encode(record)
+		}
+	}
+	for _, c := range collectors {
+		if err := c.flushEMF(namespace, timestamp, emit); err != nil {
+			return err
+		}
+		if encodeErr != nil {
+			return encodeErr
+		}
+	}
+	return nil
+}
+
+// EMFHandler flushes the metrics of the default registry as embedded metric logs after each request, the Lambda
+// execution environment may be frozen between the invocations so the metrics are not kept for a later flush
+func EMFHandler(next http.Handler, w io.Writer, namespace string) http.Handler {
+	DefaultRegistry.EnableEMF()
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(rw, r)
+		if err := DefaultRegistry.FlushEMF(w, namespace); err != nil {
+			log.WithError(err).Warn("unable to flush the embedded metric logs")
+		}
+	})
+}
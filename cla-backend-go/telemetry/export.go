@@ -0,0 +1,72 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"time"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// flushTimeout bounds the export of the queued spans by FlushTraces
+const flushTimeout = 5 * time.Second
+
+// newStdoutExporter returns an exporter writing the spans as JSON to the standard output, e.g. to read them from the
+// CloudWatch logs
+func newStdoutExporter() (sdktrace.SpanExporter, error) {
+	return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+}
+
+// newOTLPExporter returns an exporter sending the spans to the OTLP/HTTP collector of the base URL, e.g. the
+// collector of the OpenTelemetry Lambda extension on http://localhost:4318
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("the otlp trace exporter requires an endpoint")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("invalid otlp trace exporter endpoint: %s", endpoint)
+	}
+
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path.Join("/", u.Path, "v1/traces")),
+	}
+	if u.Scheme == "http" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), options...)
+}
+
+// FlushTraces exports the queued spans, the batches are otherwise exported in the background
+func FlushTraces() {
+	if tracerProvider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := tracerProvider.ForceFlush(ctx); err != nil {
+		log.WithError(err).Warn("unable to export the spans")
+	}
+}
+
+// FlushTracesHandler starts the export of the spans of each request once its response is written, for the Lambda
+// functions whose execution environment may be frozen before the next background export. The export does not
+// delay the response: an export interrupted by the freeze resumes with the next invocation, and the spans are sent
+// to the collector of the OpenTelemetry Lambda extension, which forwards them to the tracing backend.
+func FlushTracesHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		go FlushTraces()
+	})
+}
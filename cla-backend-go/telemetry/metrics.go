@@ -0,0 +1,80 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+)
+
+// DefaultRegistry holds the metrics of the API server
+var DefaultRegistry = NewRegistry()
+
+// The API server metrics
+var (
+	httpRequestDuration = DefaultRegistry.NewHistogramVec("easycla_http_request_duration_seconds",
+		"Latency of the API requests by route, method and status code.", DefaultBuckets, "route", "method", "status")
+	httpRequests = DefaultRegistry.NewCounterVec("easycla_http_requests_total",
+		"Number of API requests by route, method and status code.", "route", "method", "status")
+	dynamoDBRequestDuration = DefaultRegistry.NewHistogramVec("easycla_dynamodb_request_duration_seconds",
+		"Latency of the DynamoDB calls, including the retries, by operation and table.", DefaultBuckets, "operation", "table")
+	dynamoDBRequestErrors = DefaultRegistry.NewCounterVec("easycla_dynamodb_request_errors_total",
+		"Number of failed DynamoDB calls by operation, table and error code.", "operation", "table", "code")
+	clientRequestDuration = DefaultRegistry.NewHistogramVec("easycla_client_request_duration_seconds",
+		"Latency of the calls to the external services by service and method.", DefaultBuckets, "service", "method")
+	clientRequestErrors = DefaultRegistry.NewCounterVec("easycla_client_request_errors_total",
+		"Number of failed calls to the external services by service, method and status code.", "service", "method", "status")
+	coverageExemptions = DefaultRegistry.NewCounterVec("easycla_coverage_exemptions_total",
+		"Number of contributors covered by an exemption instead of a signature by CLA Group and exemption.", "cla_group_id", "exemption_type", "exemption_pattern")
+)
+
+// UnmatchedRoute is the route label of the requests which did not match a route of the API, the raw paths
+// would make the number of series unbounded
+const UnmatchedRoute = "unmatched"
+
+// ObserveHTTPRequest records the latency and the status code of an API request
+func ObserveHTTPRequest(route, method string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	statusCode := strconv.Itoa(status)
+	httpRequestDuration.Observe(elapsed.Seconds(), route, method, statusCode)
+	httpRequests.Inc(route, method, statusCode)
+}
+
+// ObserveDynamoDBRequest records the latency of a DynamoDB call, the error code is empty for successful calls
+func ObserveDynamoDBRequest(operation, table, errorCode string, elapsed time.Duration) {
+	dynamoDBRequestDuration.Observe(elapsed.Seconds(), operation, table)
+	if errorCode != "" {
+		dynamoDBRequestErrors.Inc(operation, table, errorCode)
+	}
+}
+
+// ObserveClientRequest records the latency of a call to an external service, the status is the HTTP status
+// code of the failed calls or "error" when no response was received - it is empty for successful calls
+func ObserveClientRequest(service, method, status string, elapsed time.Duration) {
+	clientRequestDuration.Observe(elapsed.Seconds(), service, method)
+	if status != "" {
+		clientRequestErrors.Inc(service, method, status)
+	}
+}
+
+// ObserveCoverageExemption records a contributor covered by an exemption of the CLA Group, each exemption is its own
+// series so that a broad pattern shows up next to the bot accounts it is meant for
+func ObserveCoverageExemption(claGroupID, exemptionType, pattern string) {
+	coverageExemptions.Inc(claGroupID, exemptionType, pattern)
+}
+
+// Handler returns the handler of the Prometheus scrape endpoint
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := DefaultRegistry.WritePrometheus(w); err != nil {
+			log.WithError(err).Warn("unable to write the metrics")
+		}
+	})
+}
@@ -0,0 +1,380 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histogram buckets
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// labelSeparator joins the label values of a series into the series key, it can not appear in a label value
+const labelSeparator = "\xff"
+
+// collector is a metric family of the registry
+type collector interface {
+	name() string
+	writePrometheus(w io.Writer) error
+	flushEMF(namespace string, timestamp int64, emit func(map[string]interface{})) error
+}
+
+// Registry holds the metric families exposed by the API server
+type Registry struct {
+	mu         sync.Mutex
+	collectors []collector
+	// emf is set when the observations are flushed as embedded metric logs, the histograms then keep the
+	// observations since the last flush
+	emf bool
+}
+
+// NewRegistry creates a new, empty, registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(c collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.collectors {
+		if existing.name() == c.name() {
+			panic(fmt.Sprintf("metric %s registered twice", c.name()))
+		}
+	}
+	r.collectors = append(r.collectors, c)
+}
+
+func (r *Registry) emfEnabled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.emf
+}
+
+// NewCounterVec registers a counter partitioned by the label names
+func (r *Registry) NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	c := &CounterVec{
+		family: family{metricName: name, help: help, labelNames: labelNames},
+		series: make(map[string]*counterSeries),
+	}
+	r.register(c)
+	return c
+}
+
+// NewHistogramVec registers a histogram partitioned by the label names, the buckets are the upper bounds of
+// the buckets in increasing order
+func (r *Registry) NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	h := &HistogramVec{
+		family:   family{metricName: name, help: help, labelNames: labelNames},
+		registry: r,
+		buckets:  buckets,
+		series:   make(map[string]*histogramSeries),
+	}
+	r.register(h)
+	return h
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	collectors := make([]collector, len(r.collectors))
+	copy(collectors, r.collectors)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		if err := c.writePrometheus(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// family holds the name, help and label names of a metric family
+type family struct {
+	metricName string
+	help       string
+	labelNames []string
+}
+
+func (f *family) name() string {
+	return f.metricName
+}
+
+func (f *family) seriesKey(labelValues []string) string {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.metricName, len(f.labelNames), len(labelValues)))
+	}
+	return strings.Join(labelValues, labelSeparator)
+}
+
+func (f *family) writeHeader(w io.Writer, metricType string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.metricName, escapeHelp(f.help), f.metricName, metricType)
+	return err
+}
+
+// labels formats the label pairs of a series, the extra pair is appended when not empty
+func (f *family) labels(labelValues []string, extraName, extraValue string) string {
+	var pairs []string
+	for i, labelName := range f.labelNames {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labelName, escapeLabelValue(labelValues[i])))
+	}
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, escapeLabelValue(extraValue)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// emfRecord returns the embedded metric format record of a series, the metric value is added by the caller
+func (f *family) emfRecord(namespace string, timestamp int64, labelValues []string, unit string) map[string]interface{} {
+	dimensions := make([]string, len(f.labelNames))
+	copy(dimensions, f.labelNames)
+	record := map[string]interface{}{
+		"_aws": map[string]interface{}{
+			"Timestamp": timestamp,
+			"CloudWatchMetrics": []interface{}{
+				map[string]interface{}{
+					"Namespace":  namespace,
+					"Dimensions": [][]string{dimensions},
+					"Metrics": []interface{}{
+						map[string]string{"Name": f.metricName, "Unit": unit},
+					},
+				},
+			},
+		},
+	}
+	for i, labelName := range f.labelNames {
+		record[labelName] = labelValues[i]
+	}
+	return record
+}
+
+// sortedKeys returns the series keys in a stable order so the exposition is deterministic
+func sortedKeys(keys []string) []string {
+	sort.Strings(keys)
+	return keys
+}
+
+// CounterVec is a monotonically increasing count partitioned by labels
+type CounterVec struct {
+	family
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+	flushed     float64
+}
+
+// Inc increases the count of the series of the label values by one
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add increases the count of the series of the label values, negative values are ignored
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		return
+	}
+	key := c.seriesKey(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[key]
+	if !ok {
+		s = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		c.series[key] = s
+	}
+	s.value += delta
+}
+
+// Value returns the count of the series of the label values
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	key := c.seriesKey(labelValues)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if s, ok := c.series[key]; ok {
+		return s.value
+	}
+	return 0
+}
+
+func (c *CounterVec) writePrometheus(w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.writeHeader(w, "counter"); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		s := c.series[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", c.metricName, c.labels(s.labelValues, "", ""), formatFloat(s.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushEMF emits the increase of each series since the last flush
+func (c *CounterVec) flushEMF(namespace string, timestamp int64, emit func(map[string]interface{})) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]string, 0, len(c.series))
+	for key := range c.series {
+		keys = append(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		s := c.series[key]
+		delta := s.value - s.flushed
+		if delta == 0 {
+			continue
+		}
+		record := c.emfRecord(namespace, timestamp, s.labelValues, "Count")
+		record[c.metricName] = delta
+		emit(record)
+		s.flushed = s.value
+	}
+	return nil
+}
+
+// HistogramVec counts observations, such as request latencies, in buckets partitioned by labels
+type HistogramVec struct {
+	family
+	registry *Registry
+	buckets  []float64
+	mu       sync.Mutex
+	series   map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+	// pending are the observations since the last flush, only kept when the embedded metric logs are enabled
+	pending []float64
+}
+
+// maxEMFValues is the maximum number of values of a metric in an embedded metric format record
+const maxEMFValues = 100
+
+// Observe adds an observation to the series of the label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := h.seriesKey(labelValues)
+	emf := h.registry.emfEnabled()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	s, ok := h.series[key]
+	if !ok {
+		s = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(h.buckets)),
+		}
+		h.series[key] = s
+	}
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			s.counts[i]++
+		}
+	}
+	s.count++
+	s.sum += value
+	if emf {
+		s.pending = append(s.pending, value)
+	}
+}
+
+// Count returns the number of observations of the series of the label values
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	key := h.seriesKey(labelValues)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if s, ok := h.series[key]; ok {
+		return s.count
+	}
+	return 0
+}
+
+func (h *HistogramVec) writePrometheus(w io.Writer) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err := h.writeHeader(w, "histogram"); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		s := h.series[key]
+		for i, upperBound := range h.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, h.labels(s.labelValues, "le", formatFloat(upperBound)), s.counts[i]); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			h.metricName, h.labels(s.labelValues, "le", "+Inf"), s.count,
+			h.metricName, h.labels(s.labelValues, "", ""), formatFloat(s.sum),
+			h.metricName, h.labels(s.labelValues, "", ""), s.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flushEMF emits the observations of each series since the last flush, CloudWatch computes the percentiles
+// from the values
+func (h *HistogramVec) flushEMF(namespace string, timestamp int64, emit func(map[string]interface{})) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.series))
+	for key := range h.series {
+		keys = append(keys, key)
+	}
+	for _, key := range sortedKeys(keys) {
+		s := h.series[key]
+		for start := 0; start < len(s.pending); start += maxEMFValues {
+			end := start + maxEMFValues
+			if end > len(s.pending) {
+				end = len(s.pending)
+			}
+			record := h.emfRecord(namespace, timestamp, s.labelValues, "Seconds")
+			record[h.metricName] = s.pending[start:end]
+			emit(record)
+		}
+		s.pending = nil
+	}
+	return nil
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
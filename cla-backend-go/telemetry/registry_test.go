@@ -0,0 +1,84 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.NewCounterVec("requests_total", "Number of requests.", "route", "status")
+	latency := registry.NewHistogramVec("latency_seconds", "Latency.", []float64{0.1, 1}, "route")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			requests.Inc(`/v4/project/{projectID}`, "200")
+		}()
+	}
+	wg.Wait()
+	requests.Inc(`a"b`, "500")
+	latency.Observe(0.05, "/health")
+	latency.Observe(0.5, "/health")
+	latency.Observe(3, "/health")
+
+	var buf bytes.Buffer
+	assert.NoError(t, registry.WritePrometheus(&buf))
+	assert.Equal(t, strings.Join([]string{
+		"# HELP requests_total Number of requests.",
+		"# TYPE requests_total counter",
+		`requests_total{route="/v4/project/{projectID}",status="200"} 50`,
+		`requests_total{route="a\"b",status="500"} 1`,
+		"# HELP latency_seconds Latency.",
+		"# TYPE latency_seconds histogram",
+		`latency_seconds_bucket{route="/health",le="0.1"} 1`,
+		`latency_seconds_bucket{route="/health",le="1"} 2`,
+		`latency_seconds_bucket{route="/health",le="+Inf"} 3`,
+		`latency_seconds_sum{route="/health"} 3.55`,
+		`latency_seconds_count{route="/health"} 3`,
+		"",
+	}, "\n"), buf.String())
+}
+
+func TestFlushEMF(t *testing.T) {
+	registry := NewRegistry()
+	registry.EnableEMF()
+	requests := registry.NewCounterVec("requests_total", "Number of requests.", "route")
+	latency := registry.NewHistogramVec("latency_seconds", "Latency.", DefaultBuckets, "route")
+
+	requests.Add(2, "/health")
+	latency.Observe(0.25, "/health")
+
+	var buf bytes.Buffer
+	assert.NoError(t, registry.FlushEMF(&buf, "EasyCLA/test"))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+		assert.Equal(t, "/health", record["route"])
+		assert.Equal(t, float64(2), record["requests_total"])
+		directive := record["_aws"].(map[string]interface{})["CloudWatchMetrics"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "EasyCLA/test", directive["Namespace"])
+
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &record))
+		assert.Equal(t, []interface{}{0.25}, record["latency_seconds"])
+	}
+
+	// Only the changes since the last flush are written
+	buf.Reset()
+	assert.NoError(t, registry.FlushEMF(&buf, "EasyCLA/test"))
+	assert.Empty(t, buf.String())
+	requests.Inc("/health")
+	assert.NoError(t, registry.FlushEMF(&buf, "EasyCLA/test"))
+	assert.Contains(t, buf.String(), `"requests_total":1`)
+}
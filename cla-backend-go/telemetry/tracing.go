@@ -0,0 +1,145 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name of the tracer of the spans started by the package
+const instrumentationName = "github.com/communitybridge/easycla/cla-backend-go/telemetry"
+
+// tracerProvider is the provider of the enabled tracing, nil when the tracing is disabled - the global provider of
+// OpenTelemetry then only returns non-recording spans
+var tracerProvider *sdktrace.TracerProvider
+
+// requestSpans holds the span contexts of the active requests by request ID
+var requestSpans sync.Map
+
+// TracingConfig configures the exporter of the spans
+type TracingConfig struct {
+	// ServiceName is the service.name resource attribute of the exported spans
+	ServiceName string
+	// Exporter is one of "none" (the default), "stdout" or "otlp"
+	Exporter string
+	// OTLPEndpoint is the base URL of the OTLP/HTTP collector, the spans are sent to its /v1/traces path
+	OTLPEndpoint string
+}
+
+// InitTracing enables the tracing with the configured exporter, the tracing stays disabled for the "none" exporter.
+// The spans are exported in batches in the background, and propagated to the called services with the W3C trace
+// context headers.
+func InitTracing(config TracingConfig) error {
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(config.Exporter) {
+	case "", "none":
+		return nil
+	case "stdout":
+		exporter, err = newStdoutExporter()
+	case "otlp":
+		exporter, err = newOTLPExporter(config.OTLPEndpoint)
+	default:
+		return fmt.Errorf("unsupported trace exporter: %s", config.Exporter)
+	}
+	if err != nil {
+		return err
+	}
+
+	tracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String(config.ServiceName))),
+	)
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return nil
+}
+
+func tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span, the child of the span of the context or of the request of the x-request-id value of the
+// context - a new trace is started when the context has neither, e.g. in the Lambda functions. The span does not
+// record anything when the tracing is disabled.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	if parentCtx, ok := traceContext(ctx); ok {
+		ctx = parentCtx
+	}
+	return tracer().Start(ctx, name, opts...)
+}
+
+// startChildSpan starts a span only when the context belongs to a trace - used by the instrumentation of the
+// clients, whose calls outside of a request or a traced job would each start a trace of their own
+func startChildSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	parentCtx, ok := traceContext(ctx)
+	if !ok {
+		return ctx, trace.SpanFromContext(context.Background())
+	}
+	return tracer().Start(parentCtx, name, opts...)
+}
+
+// traceContext returns the context holding the parent of the spans started from the context - its own span, or
+// the span of the request of its x-request-id value, the handlers building their context from the request ID only.
+// Returns false when the context belongs to no trace.
+func traceContext(ctx context.Context) (context.Context, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, true
+	}
+	requestID, ok := ctx.Value(utils.XREQUESTID).(string)
+	if !ok || requestID == "" {
+		return ctx, false
+	}
+	spanContext, ok := requestSpans.Load(requestID)
+	if !ok {
+		return ctx, false
+	}
+	return trace.ContextWithSpanContext(ctx, spanContext.(trace.SpanContext)), true
+}
+
+// endSpan records the error of the operation of the span, nil errors are ignored, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ServerHandler traces the API requests with otelhttp, the trace of the traceparent header is continued when the
+// caller sent one. The span of a request is registered under its request ID so the spans started from a context
+// holding only the x-request-id value become its children.
+func ServerHandler(next http.Handler, spanName func(r *http.Request) string) http.Handler {
+	register := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span := trace.SpanFromContext(r.Context())
+		requestID := r.Header.Get(utils.XREQUESTID)
+		if requestID != "" && span.SpanContext().IsValid() {
+			span.SetAttributes(attribute.String("request_id", requestID))
+			requestSpans.Store(requestID, span.SpanContext())
+			defer requestSpans.Delete(requestID)
+		}
+		next.ServeHTTP(w, r)
+	})
+	return otelhttp.NewHandler(register, "", otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+		return spanName(r)
+	}))
+}
@@ -0,0 +1,124 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useSpanRecorder enables the tracing with a recorder of the ended spans, the instrumented handlers and clients must
+// be created afterwards
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	return recorder
+}
+
+func TestDisabledTracing(t *testing.T) {
+	_, span := StartSpan(context.Background(), "noop")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	_, child := startChildSpan(context.WithValue(context.Background(), utils.XREQUESTID, "r1"), "dynamodb.GetItem")
+	assert.False(t, child.IsRecording())
+}
+
+func TestServerSpanByRequestID(t *testing.T) {
+	recorder := useSpanRecorder(t)
+	requestID := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NotEmpty(t, r.Header.Get("traceparent"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer service.Close()
+	client := NewHTTPClient("platform", "GetUser")
+
+	handler := ServerHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The handlers build their context from the request ID only
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, requestID)
+		ctx, repoSpan := StartSpan(ctx, "GetProject")
+		_, callSpan := startChildSpan(ctx, "dynamodb.GetItem")
+		callSpan.End()
+
+		req, err := http.NewRequest(http.MethodGet, service.URL, nil)
+		assert.NoError(t, err)
+		resp, err := client.Do(req.WithContext(ctx))
+		if assert.NoError(t, err) {
+			resp.Body.Close() // nolint
+		}
+		repoSpan.End()
+	}), func(r *http.Request) string { return "GET /v4/project/{projectID}" })
+
+	r := httptest.NewRequest(http.MethodGet, "/v4/project/p1", nil)
+	r.Header.Set(utils.XREQUESTID, requestID)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Once the request ended its span is no longer a parent
+	_, orphan := startChildSpan(context.WithValue(context.Background(), utils.XREQUESTID, requestID), "dynamodb.Query")
+	assert.False(t, orphan.IsRecording())
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 4) {
+		call, clientCall, repo, server := spans[0], spans[1], spans[2], spans[3]
+		assert.Equal(t, "GET /v4/project/{projectID}", server.Name())
+		assert.Equal(t, "platform.GetUser", clientCall.Name())
+		assert.False(t, server.Parent().IsValid())
+		assert.Equal(t, server.SpanContext().SpanID(), repo.Parent().SpanID())
+		assert.Equal(t, repo.SpanContext().SpanID(), call.Parent().SpanID())
+		assert.Equal(t, repo.SpanContext().SpanID(), clientCall.Parent().SpanID())
+		for _, span := range spans {
+			assert.Equal(t, server.SpanContext().TraceID(), span.SpanContext().TraceID())
+		}
+		assert.Equal(t, codes.Error, clientCall.Status().Code)
+	}
+}
+
+func TestServerSpanContinuesTraceparent(t *testing.T) {
+	recorder := useSpanRecorder(t)
+
+	handler := ServerHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+		func(r *http.Request) string { return "GET /v4/ops/health" })
+	r := httptest.NewRequest(http.MethodGet, "/v4/ops/health", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	}
+}
+
+func TestClientOutsideOfATrace(t *testing.T) {
+	recorder := useSpanRecorder(t)
+
+	service := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("traceparent"))
+	}))
+	defer service.Close()
+
+	resp, err := NewHTTPClient("docraptor", "CreateDoc").Get(service.URL)
+	if assert.NoError(t, err) {
+		resp.Body.Close() // nolint
+	}
+	assert.Empty(t, recorder.Ended())
+}
@@ -0,0 +1,94 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+)
+
+// DefaultLocale is the locale of the HTML bodies of the templates - the English text is the legally binding version
+// of the agreement, the localizations are courtesy translations
+const DefaultLocale = "en"
+
+var (
+	// ErrLocaleNotSupported error - returned when the template or the CLA Group document has no translation for the locale
+	ErrLocaleNotSupported = errors.New("locale not supported")
+)
+
+var (
+	// localeRegex matches the BCP 47 language tags, such as de, pt-BR or zh-Hant
+	localeRegex = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+	// bodyRegex matches the content of the body element of a HTML document
+	bodyRegex = regexp.MustCompile(`(?is)<body[^>]*>(.*)</body>`)
+)
+
+// translationNotice starts the courtesy translation on a new page, the notice must not contain any of the anchor
+// strings of the DocuSign fields
+const translationNotice = `<div style="page-break-before: always;"><p><b>Courtesy translation (%s)</b></p>` +
+	`<p><i>The English text above is the legally binding version of this agreement. The following translation is ` +
+	`provided for convenience only, in case of any discrepancy the English text prevails.</i></p></div>`
+
+// templateLocales returns the locales the template is available in, starting with the default locale
+func templateLocales(template models.Template) []string {
+	locales := []string{DefaultLocale}
+	for _, localization := range template.Localizations {
+		if localization != nil {
+			locales = append(locales, localization.Locale)
+		}
+	}
+	return locales
+}
+
+// findLocalization returns the translation of the template for the locale, the locales are matched case insensitive
+func findLocalization(template models.Template, locale string) *models.TemplateLocalization {
+	for _, localization := range template.Localizations {
+		if localization != nil && strings.EqualFold(localization.Locale, locale) {
+			return localization
+		}
+	}
+	return nil
+}
+
+// localizedTemplate returns the template with the courtesy translation of the locale appended to the English HTML
+// bodies, the English text stays first so the DocuSign fields are placed on the legally binding version - the
+// template is returned unchanged for the default locale
+func localizedTemplate(template models.Template, locale string) (models.Template, error) {
+	if locale == "" || strings.EqualFold(locale, DefaultLocale) {
+		return template, nil
+	}
+
+	localization := findLocalization(template, locale)
+	if localization == nil {
+		return models.Template{}, fmt.Errorf("%w: the template %s has no %s translation", ErrLocaleNotSupported, template.Name, locale)
+	}
+
+	localized := template
+	localized.IclaHTMLBody = appendTranslation(template.IclaHTMLBody, localization.IclaHTMLBody, localization.Locale)
+	localized.CclaHTMLBody = appendTranslation(template.CclaHTMLBody, localization.CclaHTMLBody, localization.Locale)
+	return localized, nil
+}
+
+// appendTranslation appends the notice and the translation to the English HTML body, inside of its body element when
+// the English HTML body is a complete document
+func appendTranslation(body, translation, locale string) string {
+	if strings.TrimSpace(body) == "" || strings.TrimSpace(translation) == "" {
+		return body
+	}
+
+	if match := bodyRegex.FindStringSubmatch(translation); match != nil {
+		translation = match[1]
+	}
+	translation = fmt.Sprintf(translationNotice, locale) + translation
+
+	end := strings.LastIndex(strings.ToLower(body), "</body>")
+	if end < 0 {
+		return body + translation
+	}
+	return body[:end] + translation + body[end:]
+}
@@ -22,14 +22,63 @@ type DBProjectModel struct {
 
 // DBProjectDocumentModel is a data model for the CLA Group Project documents
 type DBProjectDocumentModel struct {
-	DocumentName            string `dynamodbav:"document_name"`
-	DocumentFileID          string `dynamodbav:"document_file_id"`
-	DocumentPreamble        string `dynamodbav:"document_preamble"`
-	DocumentLegalEntityName string `dynamodbav:"document_legal_entity_name"`
-	DocumentAuthorName      string `dynamodbav:"document_author_name"`
-	DocumentContentType     string `dynamodbav:"document_content_type"`
-	DocumentS3URL           string `dynamodbav:"document_s3_url"`
-	DocumentMajorVersion    string `dynamodbav:"document_major_version"`
-	DocumentMinorVersion    string `dynamodbav:"document_minor_version"`
-	DocumentCreationDate    string `dynamodbav:"document_creation_date"`
+	DocumentName            string            `dynamodbav:"document_name"`
+	DocumentFileID          string            `dynamodbav:"document_file_id"`
+	DocumentPreamble        string            `dynamodbav:"document_preamble"`
+	DocumentLegalEntityName string            `dynamodbav:"document_legal_entity_name"`
+	DocumentAuthorName      string            `dynamodbav:"document_author_name"`
+	DocumentContentType     string            `dynamodbav:"document_content_type"`
+	DocumentS3URL           string            `dynamodbav:"document_s3_url"`
+	DocumentMajorVersion    string            `dynamodbav:"document_major_version"`
+	DocumentMinorVersion    string            `dynamodbav:"document_minor_version"`
+	DocumentCreationDate    string            `dynamodbav:"document_creation_date"`
+	DocumentTemplateVersion int64             `dynamodbav:"document_template_version"`
+	DocumentLocalizedS3URLs map[string]string `dynamodbav:"document_localized_s3_urls"`
+}
+
+// DBTemplateModel is a version of a stored template, the versions are immutable - only the deleted flag is updated
+type DBTemplateModel struct {
+	TemplateID           string                   `dynamodbav:"template_id"`
+	TemplateVersion      int64                    `dynamodbav:"template_version"`
+	TemplateName         string                   `dynamodbav:"template_name"`
+	TemplateDescription  string                   `dynamodbav:"template_description"`
+	TemplateMajorVersion int64                    `dynamodbav:"template_major_version"`
+	TemplateMinorVersion int64                    `dynamodbav:"template_minor_version"`
+	IclaHTMLBody         string                   `dynamodbav:"icla_html_body"`
+	CclaHTMLBody         string                   `dynamodbav:"ccla_html_body"`
+	MetaFields           []DBTemplateMetaField    `dynamodbav:"meta_fields"`
+	IclaFields           []DBTemplateField        `dynamodbav:"icla_fields"`
+	CclaFields           []DBTemplateField        `dynamodbav:"ccla_fields"`
+	Localizations        []DBTemplateLocalization `dynamodbav:"localizations"`
+	CreatedBy            string                   `dynamodbav:"created_by"`
+	DateCreated          string                   `dynamodbav:"date_created"`
+	Deleted              bool                     `dynamodbav:"deleted"`
+}
+
+// DBTemplateMetaField is a placeholder of a stored template
+type DBTemplateMetaField struct {
+	Name             string `dynamodbav:"name"`
+	Description      string `dynamodbav:"description"`
+	TemplateVariable string `dynamodbav:"template_variable"`
+}
+
+// DBTemplateLocalization is a courtesy translation of the HTML bodies of a stored template
+type DBTemplateLocalization struct {
+	Locale       string `dynamodbav:"locale"`
+	IclaHTMLBody string `dynamodbav:"icla_html_body"`
+	CclaHTMLBody string `dynamodbav:"ccla_html_body"`
+}
+
+// DBTemplateField is a DocuSign tab of a stored template
+type DBTemplateField struct {
+	ID           string `dynamodbav:"id"`
+	Name         string `dynamodbav:"name"`
+	AnchorString string `dynamodbav:"anchor_string"`
+	FieldType    string `dynamodbav:"field_type"`
+	IsOptional   bool   `dynamodbav:"is_optional"`
+	IsEditable   bool   `dynamodbav:"is_editable"`
+	Width        int64  `dynamodbav:"width"`
+	Height       int64  `dynamodbav:"height"`
+	OffsetX      int64  `dynamodbav:"offset_x"`
+	OffsetY      int64  `dynamodbav:"offset_y"`
 }
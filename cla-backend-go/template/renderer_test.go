@@ -0,0 +1,62 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/htmlpdf"
+	"github.com/stretchr/testify/assert"
+)
+
+// run with -update to regenerate the golden files after a change of the templates or of the renderer
+var updateGolden = flag.Bool("update", false, "update the golden files")
+
+func TestRenderTemplatesGolden(t *testing.T) {
+	var templateIDs []string
+	for templateID := range templateMap {
+		templateIDs = append(templateIDs, templateID)
+	}
+	sort.Strings(templateIDs)
+
+	s := service{}
+	for _, templateID := range templateIDs {
+		template := templateMap[templateID]
+		var metaFields []*models.MetaField
+		for _, field := range template.MetaFields {
+			metaFields = append(metaFields, &models.MetaField{
+				Name:             field.Name,
+				TemplateVariable: field.TemplateVariable,
+				Value:            "Example " + field.Name,
+			})
+		}
+		iclaHTML, cclaHTML, err := s.InjectProjectInformationIntoTemplate(template, metaFields)
+		if !assert.NoError(t, err, template.Name) {
+			continue
+		}
+
+		for claType, html := range map[string]string{claTypeICLA: iclaHTML, claTypeCCLA: cclaHTML} {
+			name := fmt.Sprintf("%s-%s.pdf", strings.ReplaceAll(strings.ToLower(template.Name), " ", "-"), claType)
+			t.Run(name, func(t *testing.T) {
+				pdf := htmlpdf.Render(html)
+				golden := filepath.Join("testdata", name)
+				if *updateGolden {
+					assert.NoError(t, ioutil.WriteFile(golden, pdf, 0600))
+				}
+
+				expected, err := ioutil.ReadFile(golden)
+				if assert.NoError(t, err) {
+					assert.Equal(t, string(expected), string(pdf))
+				}
+			})
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,15 +18,20 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 var (
 	// ErrTemplateNotFound error
 	ErrTemplateNotFound = errors.New("template not found")
+	// ErrTemplateVersionExists error - returned when the version of a stored template was created concurrently
+	ErrTemplateVersionExists = errors.New("template version already exists")
+	// ErrBuiltInTemplate error - returned when trying to change one of the built-in templates
+	ErrBuiltInTemplate = errors.New("built-in templates can't be changed")
 )
 
 var (
@@ -38,15 +44,21 @@ var (
 // Repository interface functions
 type Repository interface {
 	GetTemplates(ctx context.Context) ([]models.Template, error)
-	GetTemplate(templateID string) (models.Template, error)
+	GetTemplate(ctx context.Context, templateID string) (models.Template, error)
+	GetTemplateVersions(ctx context.Context, templateID string) ([]models.Template, error)
+	GetTemplateVersion(ctx context.Context, templateID string, templateVersion int64) (models.Template, error)
+	CreateTemplateVersion(ctx context.Context, template models.Template) (models.Template, error)
+	DeleteTemplate(ctx context.Context, templateID string) error
 	GetCLAGroup(claGroupID string) (*models.ClaGroup, error)
 	GetCLADocuments(claGroupID string, claType string) ([]models.ClaGroupDocument, error)
+	GetLocalizedDocumentS3URL(claGroupID, claType, documentS3URL, locale string) (string, error)
 	UpdateDynamoContractGroupTemplates(ctx context.Context, ContractGroupID string, template models.Template, pdfUrls models.TemplatePdfs, projectCCLAEnabled, projectICLAEnabled bool) error
 }
 
 type repository struct {
-	stage          string // The AWS stage (dev, staging, prod)
-	dynamoDBClient *dynamodb.DynamoDB
+	stage              string // The AWS stage (dev, staging, prod)
+	dynamoDBClient     storage.Driver
+	templatesTableName string
 }
 
 // CLAGroup structure
@@ -65,17 +77,19 @@ type DynamoProjectIndividualDocuments struct {
 
 // DynamoProjectDocument model
 type DynamoProjectDocument struct {
-	DocumentName            string        `json:"document_name"`
-	DocumentFileID          string        `json:"document_file_id"`
-	DocumentContentType     string        `json:"document_content_type"`
-	DocumentMajorVersion    int           `json:"document_major_version"`
-	DocumentMinorVersion    int           `json:"document_minor_version"`
-	DocumentCreationDate    string        `json:"document_creation_date"`
-	DocumentPreamble        string        `json:"document_preamble"`
-	DocumentLegalEntityName string        `json:"document_legal_entity_name"`
-	DocumentAuthorName      string        `json:"document_author_name"`
-	DocumentS3URL           string        `json:"document_s3_url"`
-	DocumentTabs            []DocumentTab `json:"document_tabs"`
+	DocumentName            string            `json:"document_name"`
+	DocumentFileID          string            `json:"document_file_id"`
+	DocumentContentType     string            `json:"document_content_type"`
+	DocumentMajorVersion    int               `json:"document_major_version"`
+	DocumentMinorVersion    int               `json:"document_minor_version"`
+	DocumentCreationDate    string            `json:"document_creation_date"`
+	DocumentPreamble        string            `json:"document_preamble"`
+	DocumentLegalEntityName string            `json:"document_legal_entity_name"`
+	DocumentAuthorName      string            `json:"document_author_name"`
+	DocumentS3URL           string            `json:"document_s3_url"`
+	DocumentTabs            []DocumentTab     `json:"document_tabs"`
+	DocumentTemplateVersion int64             `json:"document_template_version,omitempty"`
+	DocumentLocalizedS3URLs map[string]string `json:"document_localized_s3_urls,omitempty"`
 }
 
 // DocumentTab structure
@@ -98,10 +112,11 @@ type DocumentTab struct {
 }
 
 // NewRepository creates a new instance of the repository service
-func NewRepository(awsSession *session.Session, stage string) repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) repository {
 	return repository{
-		stage:          stage,
-		dynamoDBClient: dynamodb.New(awsSession),
+		stage:              stage,
+		dynamoDBClient:     dynamoDBClient,
+		templatesTableName: fmt.Sprintf("cla-%s-templates", stage),
 	}
 }
 
@@ -115,9 +130,18 @@ func (r repository) GetTemplates(ctx context.Context) ([]models.Template, error)
 	log.WithFields(f).Debug("Loading templates...")
 	var templates []models.Template
 	for _, template := range templateMap {
+		template.BuiltIn = true
 		templates = append(templates, template)
 	}
 
+	log.WithFields(f).Debug("Loading stored templates...")
+	storedTemplates, err := r.getLatestStoredTemplates()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading stored templates")
+		return nil, err
+	}
+	templates = append(templates, storedTemplates...)
+
 	// Sort the template list based on the name
 	log.WithFields(f).Debug("Sorting templates...")
 	sort.Slice(templates, func(i, j int) bool {
@@ -127,16 +151,256 @@ func (r repository) GetTemplates(ctx context.Context) ([]models.Template, error)
 	return templates, nil
 }
 
-// GetTemplate returns the template based on the template ID
-func (r repository) GetTemplate(templateID string) (models.Template, error) {
+// GetTemplate returns the built-in template or the latest version of the stored template based on the template ID
+func (r repository) GetTemplate(ctx context.Context, templateID string) (models.Template, error) {
+	f := logrus.Fields{
+		"functionName":   "GetTemplate",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"templateID":     templateID,
+	}
+
 	template, ok := templateMap[templateID]
-	if !ok {
+	if ok {
+		template.BuiltIn = true
+		return template, nil
+	}
+
+	result, err := r.dynamoDBClient.Query(&dynamodb.QueryInput{
+		TableName:              aws.String(r.templatesTableName),
+		KeyConditionExpression: aws.String("#templateID = :templateID"),
+		ExpressionAttributeNames: map[string]*string{
+			"#templateID": aws.String("template_id"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":templateID": {S: aws.String(templateID)},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int64(1),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem querying the latest template version")
+		return models.Template{}, err
+	}
+
+	var dbModels []DBTemplateModel
+	err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &dbModels)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the template version")
+		return models.Template{}, err
+	}
+	if len(dbModels) == 0 || dbModels[0].Deleted {
+		return models.Template{}, ErrTemplateNotFound
+	}
+
+	return buildTemplateModel(dbModels[0]), nil
+}
+
+// GetTemplateVersions returns all the versions of the stored template, oldest first
+func (r repository) GetTemplateVersions(ctx context.Context, templateID string) ([]models.Template, error) {
+	f := logrus.Fields{
+		"functionName":   "GetTemplateVersions",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"templateID":     templateID,
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(r.templatesTableName),
+		KeyConditionExpression: aws.String("#templateID = :templateID"),
+		ExpressionAttributeNames: map[string]*string{
+			"#templateID": aws.String("template_id"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":templateID": {S: aws.String(templateID)},
+		},
+	}
+
+	var templates []models.Template
+	for {
+		result, err := r.dynamoDBClient.Query(queryInput)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem querying the template versions")
+			return nil, err
+		}
+
+		var dbModels []DBTemplateModel
+		err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &dbModels)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem unmarshalling the template versions")
+			return nil, err
+		}
+
+		for _, dbModel := range dbModels {
+			if dbModel.Deleted {
+				return nil, ErrTemplateNotFound
+			}
+			templates = append(templates, buildTemplateModel(dbModel))
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	if len(templates) == 0 {
+		return nil, ErrTemplateNotFound
+	}
+
+	return templates, nil
+}
+
+// GetTemplateVersion returns the specified version of the stored template
+func (r repository) GetTemplateVersion(ctx context.Context, templateID string, templateVersion int64) (models.Template, error) {
+	f := logrus.Fields{
+		"functionName":    "GetTemplateVersion",
+		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
+		"templateID":      templateID,
+		"templateVersion": templateVersion,
+	}
+
+	result, err := r.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(r.templatesTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"template_id":      {S: aws.String(templateID)},
+			"template_version": {N: aws.String(strconv.FormatInt(templateVersion, 10))},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the template version")
+		return models.Template{}, err
+	}
+	if len(result.Item) == 0 {
+		return models.Template{}, ErrTemplateNotFound
+	}
+
+	var dbModel DBTemplateModel
+	err = dynamodbattribute.UnmarshalMap(result.Item, &dbModel)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the template version")
+		return models.Template{}, err
+	}
+	if dbModel.Deleted {
 		return models.Template{}, ErrTemplateNotFound
 	}
 
+	return buildTemplateModel(dbModel), nil
+}
+
+// CreateTemplateVersion stores a new version of a template, the version must not exist yet - the stored versions are
+// never changed
+func (r repository) CreateTemplateVersion(ctx context.Context, template models.Template) (models.Template, error) {
+	f := logrus.Fields{
+		"functionName":    "CreateTemplateVersion",
+		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
+		"templateID":      template.ID,
+		"templateVersion": template.TemplateVersion,
+	}
+
+	_, now := utils.CurrentTime()
+	template.DateCreated = now
+	template.BuiltIn = false
+
+	av, err := dynamodbattribute.MarshalMap(buildTemplateDBModel(template))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the template version")
+		return models.Template{}, err
+	}
+
+	_, err = r.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		TableName:           aws.String(r.templatesTableName),
+		Item:                av,
+		ConditionExpression: aws.String("attribute_not_exists(template_id)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return models.Template{}, ErrTemplateVersionExists
+		}
+		log.WithFields(f).WithError(err).Warn("problem storing the template version")
+		return models.Template{}, err
+	}
+
 	return template, nil
 }
 
+// DeleteTemplate flags all the versions of the stored template as deleted, the versions are kept as the documents of
+// the CLA Groups reference them
+func (r repository) DeleteTemplate(ctx context.Context, templateID string) error {
+	f := logrus.Fields{
+		"functionName":   "DeleteTemplate",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"templateID":     templateID,
+	}
+
+	versions, err := r.GetTemplateVersions(ctx, templateID)
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		_, err = r.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+			TableName: aws.String(r.templatesTableName),
+			Key: map[string]*dynamodb.AttributeValue{
+				"template_id":      {S: aws.String(templateID)},
+				"template_version": {N: aws.String(strconv.FormatInt(version.TemplateVersion, 10))},
+			},
+			ExpressionAttributeNames: map[string]*string{
+				"#deleted": aws.String("deleted"),
+			},
+			ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+				":deleted": {BOOL: aws.Bool(true)},
+			},
+			UpdateExpression: aws.String("SET #deleted = :deleted"),
+		})
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("problem deleting template version: %d", version.TemplateVersion)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLatestStoredTemplates returns the latest version of the stored templates which aren't deleted
+func (r repository) getLatestStoredTemplates() ([]models.Template, error) {
+	scanInput := &dynamodb.ScanInput{
+		TableName: aws.String(r.templatesTableName),
+	}
+
+	latest := make(map[string]DBTemplateModel)
+	for {
+		result, err := r.dynamoDBClient.Scan(scanInput)
+		if err != nil {
+			return nil, err
+		}
+
+		var dbModels []DBTemplateModel
+		err = dynamodbattribute.UnmarshalListOfMaps(result.Items, &dbModels)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dbModel := range dbModels {
+			if current, ok := latest[dbModel.TemplateID]; !ok || dbModel.TemplateVersion > current.TemplateVersion {
+				latest[dbModel.TemplateID] = dbModel
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	var templates []models.Template
+	for _, dbModel := range latest {
+		if !dbModel.Deleted {
+			templates = append(templates, buildTemplateModel(dbModel))
+		}
+	}
+
+	return templates, nil
+}
+
 // GetCLAGroup This method belongs in the contract group package. We are leaving it here
 // because it accesses DynamoDB, but the contract group repository is designed
 // to connect to postgres
@@ -172,6 +436,39 @@ func (r repository) GetCLADocuments(claGroupID string, claType string) ([]models
 	return projectDocuments, nil
 }
 
+// GetLocalizedDocumentS3URL returns the S3 URL of the translation of the CLA Group document identified by its S3 URL,
+// the locales are matched case insensitive
+func (r repository) GetLocalizedDocumentS3URL(claGroupID, claType, documentS3URL, locale string) (string, error) {
+	log.Debugf("GetLocalizedDocumentS3URL - claGroupID: %s - claType : %s - locale: %s", claGroupID, claType, locale)
+	dbModel, err := r.fetchCLAGroup(claGroupID)
+	if err != nil {
+		return "", err
+	}
+
+	var dbDocuments []DBProjectDocumentModel
+	switch claType {
+	case "icla":
+		dbDocuments = dbModel.ProjectIndividualDocuments
+	case "ccla":
+		dbDocuments = dbModel.ProjectCorporateDocuments
+	default:
+		return "", fmt.Errorf("not supported cla type supplied")
+	}
+
+	for _, dbDocument := range dbDocuments {
+		if dbDocument.DocumentS3URL != documentS3URL {
+			continue
+		}
+		for documentLocale, s3URL := range dbDocument.DocumentLocalizedS3URLs {
+			if strings.EqualFold(documentLocale, locale) {
+				return s3URL, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: the %s document of the CLA Group %s has no %s translation", ErrLocaleNotSupported, claType, claGroupID, locale)
+}
+
 func (r repository) buildProjectDocuments(dbProjectDocumentModels []DBProjectDocumentModel) []models.ClaGroupDocument {
 	if len(dbProjectDocumentModels) == 0 {
 		return nil
@@ -190,6 +487,7 @@ func (r repository) buildProjectDocuments(dbProjectDocumentModels []DBProjectDoc
 			DocumentName:            dbProjectDocumentModel.DocumentName,
 			DocumentPreamble:        dbProjectDocumentModel.DocumentPreamble,
 			DocumentS3URL:           dbProjectDocumentModel.DocumentS3URL,
+			DocumentTemplateVersion: dbProjectDocumentModel.DocumentTemplateVersion,
 		})
 	}
 
@@ -238,6 +536,113 @@ func (r repository) buildProjectModel(dbModel DBProjectModel) *models.ClaGroup {
 	}
 }
 
+// buildTemplateModel maps the stored template version to the API response model
+func buildTemplateModel(dbModel DBTemplateModel) models.Template {
+	template := models.Template{
+		ID:                   dbModel.TemplateID,
+		Name:                 dbModel.TemplateName,
+		Description:          dbModel.TemplateDescription,
+		TemplateMajorVersion: dbModel.TemplateMajorVersion,
+		TemplateMinorVersion: dbModel.TemplateMinorVersion,
+		TemplateVersion:      dbModel.TemplateVersion,
+		IclaHTMLBody:         dbModel.IclaHTMLBody,
+		CclaHTMLBody:         dbModel.CclaHTMLBody,
+		IclaFields:           buildFieldModels(dbModel.IclaFields),
+		CclaFields:           buildFieldModels(dbModel.CclaFields),
+		CreatedBy:            dbModel.CreatedBy,
+		DateCreated:          dbModel.DateCreated,
+	}
+	for _, metaField := range dbModel.MetaFields {
+		template.MetaFields = append(template.MetaFields, &models.MetaField{
+			Name:             metaField.Name,
+			Description:      metaField.Description,
+			TemplateVariable: metaField.TemplateVariable,
+		})
+	}
+	for _, localization := range dbModel.Localizations {
+		template.Localizations = append(template.Localizations, &models.TemplateLocalization{
+			Locale:       localization.Locale,
+			IclaHTMLBody: localization.IclaHTMLBody,
+			CclaHTMLBody: localization.CclaHTMLBody,
+		})
+	}
+	return template
+}
+
+func buildFieldModels(dbFields []DBTemplateField) []*models.Field {
+	var fields []*models.Field
+	for _, dbField := range dbFields {
+		fields = append(fields, &models.Field{
+			ID:           dbField.ID,
+			Name:         dbField.Name,
+			AnchorString: dbField.AnchorString,
+			FieldType:    dbField.FieldType,
+			IsOptional:   dbField.IsOptional,
+			IsEditable:   dbField.IsEditable,
+			Width:        dbField.Width,
+			Height:       dbField.Height,
+			OffsetX:      dbField.OffsetX,
+			OffsetY:      dbField.OffsetY,
+		})
+	}
+	return fields
+}
+
+// buildTemplateDBModel maps the template to the database model of a stored template version
+func buildTemplateDBModel(template models.Template) DBTemplateModel {
+	dbModel := DBTemplateModel{
+		TemplateID:           template.ID,
+		TemplateVersion:      template.TemplateVersion,
+		TemplateName:         template.Name,
+		TemplateDescription:  template.Description,
+		TemplateMajorVersion: template.TemplateMajorVersion,
+		TemplateMinorVersion: template.TemplateMinorVersion,
+		IclaHTMLBody:         template.IclaHTMLBody,
+		CclaHTMLBody:         template.CclaHTMLBody,
+		IclaFields:           buildFieldDBModels(template.IclaFields),
+		CclaFields:           buildFieldDBModels(template.CclaFields),
+		CreatedBy:            template.CreatedBy,
+		DateCreated:          template.DateCreated,
+	}
+	for _, metaField := range template.MetaFields {
+		dbModel.MetaFields = append(dbModel.MetaFields, DBTemplateMetaField{
+			Name:             metaField.Name,
+			Description:      metaField.Description,
+			TemplateVariable: metaField.TemplateVariable,
+		})
+	}
+	for _, localization := range template.Localizations {
+		if localization == nil {
+			continue
+		}
+		dbModel.Localizations = append(dbModel.Localizations, DBTemplateLocalization{
+			Locale:       localization.Locale,
+			IclaHTMLBody: localization.IclaHTMLBody,
+			CclaHTMLBody: localization.CclaHTMLBody,
+		})
+	}
+	return dbModel
+}
+
+func buildFieldDBModels(fields []*models.Field) []DBTemplateField {
+	var dbFields []DBTemplateField
+	for _, field := range fields {
+		dbFields = append(dbFields, DBTemplateField{
+			ID:           field.ID,
+			Name:         field.Name,
+			AnchorString: field.AnchorString,
+			FieldType:    field.FieldType,
+			IsOptional:   field.IsOptional,
+			IsEditable:   field.IsEditable,
+			Width:        field.Width,
+			Height:       field.Height,
+			OffsetX:      field.OffsetX,
+			OffsetY:      field.OffsetY,
+		})
+	}
+	return dbFields
+}
+
 // UpdateDynamoContractGroupTemplates updates the templates in the data store
 func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, claGroupID string, template models.Template, pdfUrls models.TemplatePdfs, projectCCLAEnabled, projectICLAEnabled bool) error {
 	f := logrus.Fields{
@@ -300,6 +705,8 @@ func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, claG
 			DocumentAuthorName:      template.Name,
 			DocumentS3URL:           pdfUrls.CorporatePDFURL,
 			DocumentTabs:            cclaDocumentTabs,
+			DocumentTemplateVersion: template.TemplateVersion,
+			DocumentLocalizedS3URLs: pdfUrls.CorporatePDFURLByLocale,
 		}
 
 		// project_corporate_documents is a List type, and thus the item needs to be in a slice
@@ -384,6 +791,8 @@ func (r repository) UpdateDynamoContractGroupTemplates(ctx context.Context, claG
 			DocumentAuthorName:      template.Name,
 			DocumentS3URL:           pdfUrls.IndividualPDFURL,
 			DocumentTabs:            iclaDocumentTabs,
+			DocumentTemplateVersion: template.TemplateVersion,
+			DocumentLocalizedS3URLs: pdfUrls.IndividualPDFURLByLocale,
 		}
 
 		var dynamoProjectIndividualDocuments []DynamoProjectDocument
@@ -17,13 +17,13 @@ import (
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
-	"github.com/communitybridge/easycla/cla-backend-go/docraptor"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	"github.com/aymerick/raymond"
+	"github.com/gofrs/uuid"
 )
 
 const (
@@ -34,25 +34,37 @@ const (
 // Service interface
 type Service interface {
 	GetTemplates(ctx context.Context) ([]models.Template, error)
+	GetTemplate(ctx context.Context, templateID string) (models.Template, error)
+	GetTemplateVersions(ctx context.Context, templateID string) ([]models.Template, error)
+	GetTemplateVersion(ctx context.Context, templateID string, templateVersion int64) (models.Template, error)
+	CreateTemplate(ctx context.Context, template *models.Template, createdBy string) (models.Template, error)
+	UpdateTemplate(ctx context.Context, templateID string, template *models.Template, createdBy string) (models.Template, error)
+	DeleteTemplate(ctx context.Context, templateID string) (models.Template, error)
 	CreateCLAGroupTemplate(ctx context.Context, claGroupID string, claGroupFields *models.CreateClaGroupTemplate) (models.TemplatePdfs, error)
-	CreateTemplatePreview(ctx context.Context, claGroupFields *models.CreateClaGroupTemplate, templateFor string) ([]byte, error)
-	GetCLATemplatePreview(ctx context.Context, claGroupID, claType string, watermark bool) ([]byte, error)
+	GetTemplateLocales(ctx context.Context, templateID string) ([]string, error)
+	CreateTemplatePreview(ctx context.Context, claGroupFields *models.CreateClaGroupTemplate, templateFor, locale string) ([]byte, error)
+	GetCLATemplatePreview(ctx context.Context, claGroupID, claType, locale string, watermark bool) ([]byte, error)
+}
+
+// PDFRenderer renders the HTML of the templates to PDF, implemented by the DocRaptor client and the local htmlpdf renderer
+type PDFRenderer interface {
+	CreatePDF(ctx context.Context, html string, claType string) (io.ReadCloser, error)
 }
 
 type service struct {
-	stage           string // The AWS stage (dev, staging, prod)
-	templateRepo    Repository
-	docraptorClient docraptor.Client
-	s3Client        *s3manager.Uploader
+	stage        string // The AWS stage (dev, staging, prod)
+	templateRepo Repository
+	pdfRenderer  PDFRenderer
+	s3Client     *s3manager.Uploader
 }
 
 // NewService API call
-func NewService(stage string, templateRepo Repository, docraptorClient docraptor.Client, awsSession *session.Session) service {
+func NewService(stage string, templateRepo Repository, pdfRenderer PDFRenderer, awsSession *session.Session) service {
 	return service{
-		stage:           stage,
-		templateRepo:    templateRepo,
-		docraptorClient: docraptorClient,
-		s3Client:        s3manager.NewUploader(awsSession),
+		stage:        stage,
+		templateRepo: templateRepo,
+		pdfRenderer:  pdfRenderer,
+		s3Client:     s3manager.NewUploader(awsSession),
 	}
 }
 
@@ -73,18 +85,133 @@ func (s service) GetTemplates(ctx context.Context) ([]models.Template, error) {
 	for i, template := range templates {
 		template.IclaHTMLBody = ""
 		template.CclaHTMLBody = ""
+		var localizations []*models.TemplateLocalization
+		for _, localization := range template.Localizations {
+			localizations = append(localizations, &models.TemplateLocalization{Locale: localization.Locale})
+		}
+		template.Localizations = localizations
 		templates[i] = template
 	}
 
 	return templates, nil
 }
 
-func (s service) CreateTemplatePreview(ctx context.Context, claGroupFields *models.CreateClaGroupTemplate, templateFor string) ([]byte, error) {
+// GetTemplate returns the built-in template or the latest version of the stored template
+func (s service) GetTemplate(ctx context.Context, templateID string) (models.Template, error) {
+	return s.templateRepo.GetTemplate(ctx, templateID)
+}
+
+// GetTemplateVersions returns all the versions of the stored template, oldest first
+func (s service) GetTemplateVersions(ctx context.Context, templateID string) ([]models.Template, error) {
+	return s.templateRepo.GetTemplateVersions(ctx, templateID)
+}
+
+// GetTemplateVersion returns the specified version of the stored template
+func (s service) GetTemplateVersion(ctx context.Context, templateID string, templateVersion int64) (models.Template, error) {
+	return s.templateRepo.GetTemplateVersion(ctx, templateID, templateVersion)
+}
+
+// GetTemplateLocales returns the locales the latest version of the template is available in, the default locale of
+// the legally binding English text first
+func (s service) GetTemplateLocales(ctx context.Context, templateID string) ([]string, error) {
+	template, err := s.templateRepo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+	return templateLocales(template), nil
+}
+
+// CreateTemplate validates and stores the first version of a new template
+func (s service) CreateTemplate(ctx context.Context, template *models.Template, createdBy string) (models.Template, error) {
+	f := logrus.Fields{
+		"functionName":   "CreateTemplate",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"templateName":   template.Name,
+		"createdBy":      createdBy,
+	}
+
+	if err := ValidateTemplate(template); err != nil {
+		log.WithFields(f).WithError(err).Warn("template validation failed")
+		return models.Template{}, err
+	}
+
+	templateID, err := uuid.NewV4()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to generate a UUID for the template")
+		return models.Template{}, err
+	}
+
+	newTemplate := *template
+	newTemplate.ID = templateID.String()
+	newTemplate.TemplateVersion = 1
+	newTemplate.CreatedBy = createdBy
+	return s.templateRepo.CreateTemplateVersion(ctx, newTemplate)
+}
+
+// UpdateTemplate validates the template and stores it as the next version of the stored template, the previous
+// versions remain unchanged
+func (s service) UpdateTemplate(ctx context.Context, templateID string, template *models.Template, createdBy string) (models.Template, error) {
+	f := logrus.Fields{
+		"functionName":   "UpdateTemplate",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"templateID":     templateID,
+		"createdBy":      createdBy,
+	}
+
+	if _, ok := templateMap[templateID]; ok {
+		return models.Template{}, ErrBuiltInTemplate
+	}
+
+	latest, err := s.templateRepo.GetTemplate(ctx, templateID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the latest template version")
+		return models.Template{}, err
+	}
+
+	if err = ValidateTemplate(template); err != nil {
+		log.WithFields(f).WithError(err).Warn("template validation failed")
+		return models.Template{}, err
+	}
+
+	newTemplate := *template
+	newTemplate.ID = templateID
+	newTemplate.TemplateVersion = latest.TemplateVersion + 1
+	newTemplate.CreatedBy = createdBy
+	return s.templateRepo.CreateTemplateVersion(ctx, newTemplate)
+}
+
+// DeleteTemplate deletes the stored template and returns its latest version
+func (s service) DeleteTemplate(ctx context.Context, templateID string) (models.Template, error) {
+	if _, ok := templateMap[templateID]; ok {
+		return models.Template{}, ErrBuiltInTemplate
+	}
+
+	latest, err := s.templateRepo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return models.Template{}, err
+	}
+
+	return latest, s.templateRepo.DeleteTemplate(ctx, templateID)
+}
+
+// getCLAGroupTemplate returns the template the CLA Group documents are generated from - the requested version of a
+// stored template or else the latest version, the built-in templates have no versions
+func (s service) getCLAGroupTemplate(ctx context.Context, templateID string, templateVersion int64) (models.Template, error) {
+	if _, ok := templateMap[templateID]; !ok && templateVersion > 0 {
+		return s.templateRepo.GetTemplateVersion(ctx, templateID, templateVersion)
+	}
+	return s.templateRepo.GetTemplate(ctx, templateID)
+}
+
+// CreateTemplatePreview renders the template for the preview, the courtesy translation of the locale is appended to
+// the English text when a locale is provided
+func (s service) CreateTemplatePreview(ctx context.Context, claGroupFields *models.CreateClaGroupTemplate, templateFor, locale string) ([]byte, error) {
 	f := logrus.Fields{
 		"functionName":   "CreateTemplatePreview",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"templateID":     claGroupFields.TemplateID,
 		"templateFor":    templateFor,
+		"locale":         locale,
 	}
 	var template models.Template
 	var err error
@@ -95,13 +222,19 @@ func (s service) CreateTemplatePreview(ctx context.Context, claGroupFields *mode
 	}
 
 	// Get Template
-	template, err = s.templateRepo.GetTemplate(templateID)
+	template, err = s.getCLAGroupTemplate(ctx, templateID, claGroupFields.TemplateVersion)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("unable to fetch template fields : %s",
 			claGroupFields.TemplateID)
 		return nil, err
 	}
 
+	template, err = localizedTemplate(template, locale)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to localize the template")
+		return nil, err
+	}
+
 	// Apply template fields
 	iclaTemplateHTML, cclaTemplateHTML, err := s.InjectProjectInformationIntoTemplate(template, claGroupFields.MetaFields)
 	if err != nil {
@@ -118,7 +251,7 @@ func (s service) CreateTemplatePreview(ctx context.Context, claGroupFields *mode
 		return nil, errors.New("invalid value of template_for")
 	}
 
-	pdf, err := s.docraptorClient.CreatePDF(templateHTML, templateFor)
+	pdf, err := s.pdfRenderer.CreatePDF(ctx, templateHTML, templateFor)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +283,7 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 	// Verify the caller is authorized for the project that owns this CLA Group
 
 	// Get Template
-	template, err := s.templateRepo.GetTemplate(claGroupFields.TemplateID)
+	template, err := s.getCLAGroupTemplate(ctx, claGroupFields.TemplateID, claGroupFields.TemplateVersion)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warnf("Unable to fetch template fields: %s - returning empty template PDFs",
 			claGroupFields.TemplateID)
@@ -170,6 +303,12 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 	var pdfUrls models.TemplatePdfs
 	var iclaFileURL string
 	var cclaFileURL string
+	var iclaLocalizedFileURLs map[string]string
+	var cclaLocalizedFileURLs map[string]string
+
+	// The go routines replace the HTML bodies of the template with the rendered HTML, the translations are rendered
+	// from a copy of the template
+	sourceTemplate := template
 
 	// Use an error group to keep track of errors thrown in the below go routines
 	// Using go routines sped up the logic from ~8 seconds to ~5 seconds as we wait for the generation to complete
@@ -179,9 +318,9 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 		// Invoke the go routine - any errors will be handled below
 		eg.Go(func() error {
 			log.WithFields(f).Debugf("Creating PDF for %s", claTypeICLA)
-			iclaPdf, iclaErr := s.docraptorClient.CreatePDF(iclaTemplateHTML, claTypeICLA)
+			iclaPdf, iclaErr := s.pdfRenderer.CreatePDF(ctx, iclaTemplateHTML, claTypeICLA)
 			if iclaErr != nil {
-				log.WithFields(f).WithError(iclaErr).Warn("Problem generating ICLA template via docraptor client - returning empty template PDFs")
+				log.WithFields(f).WithError(iclaErr).Warn("Problem generating ICLA template via the pdf renderer - returning empty template PDFs")
 				return err
 			}
 			defer func() {
@@ -190,13 +329,19 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 					log.WithFields(f).WithError(closeErr).Warn("error closing ICLA PDF")
 				}
 			}()
-			iclaFileName := s.generateTemplateS3FilePath(claGroupID, claTypeICLA)
-			iclaFileURL, err = s.SaveTemplateToS3(bucket, iclaFileName, iclaPdf)
+			iclaFileName := s.generateTemplateS3FilePath(claGroupID, claTypeICLA, "")
+			iclaFileURL, err = s.SaveTemplateToS3(ctx, bucket, iclaFileName, iclaPdf)
 			if err != nil {
 				log.WithFields(f).WithError(err).Warnf("Problem uploading ICLA PDF: %s to s3 - returning empty template PDFs", iclaFileName)
 				return err
 			}
 
+			var localizedErr error
+			iclaLocalizedFileURLs, localizedErr = s.createLocalizedPDFs(ctx, bucket, claGroupID, claTypeICLA, sourceTemplate, claGroupFields.MetaFields)
+			if localizedErr != nil {
+				return localizedErr
+			}
+
 			template.IclaHTMLBody = iclaTemplateHTML
 			return nil
 		})
@@ -206,9 +351,9 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 		// Invoke the go routine - any errors will be handled below
 		eg.Go(func() error {
 			log.WithFields(f).Debugf("Creating PDF for %s", claTypeCCLA)
-			cclaPdf, cclaErr := s.docraptorClient.CreatePDF(cclaTemplateHTML, claTypeCCLA)
+			cclaPdf, cclaErr := s.pdfRenderer.CreatePDF(ctx, cclaTemplateHTML, claTypeCCLA)
 			if cclaErr != nil {
-				log.WithFields(f).WithError(cclaErr).Warn("Problem generating CCLA template via docraptor client - returning empty template PDFs")
+				log.WithFields(f).WithError(cclaErr).Warn("Problem generating CCLA template via the pdf renderer - returning empty template PDFs")
 				return err
 			}
 			defer func() {
@@ -217,13 +362,19 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 					log.WithFields(f).WithError(closeErr).Warn("error closing CCLA PDF")
 				}
 			}()
-			cclaFileName := s.generateTemplateS3FilePath(claGroupID, claTypeCCLA)
-			cclaFileURL, err = s.SaveTemplateToS3(bucket, cclaFileName, cclaPdf)
+			cclaFileName := s.generateTemplateS3FilePath(claGroupID, claTypeCCLA, "")
+			cclaFileURL, err = s.SaveTemplateToS3(ctx, bucket, cclaFileName, cclaPdf)
 			if err != nil {
 				log.WithFields(f).Warnf("Problem uploading CCLA PDF: %s to s3, error: %v - returning empty template PDFs", cclaFileName, err)
 				return err
 			}
 
+			var localizedErr error
+			cclaLocalizedFileURLs, localizedErr = s.createLocalizedPDFs(ctx, bucket, claGroupID, claTypeCCLA, sourceTemplate, claGroupFields.MetaFields)
+			if localizedErr != nil {
+				return localizedErr
+			}
+
 			template.CclaHTMLBody = cclaTemplateHTML
 			return nil
 		})
@@ -237,16 +388,20 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 
 	if claGroup.ProjectICLAEnabled && claGroup.ProjectCCLAEnabled {
 		pdfUrls = models.TemplatePdfs{
-			IndividualPDFURL: iclaFileURL,
-			CorporatePDFURL:  cclaFileURL,
+			IndividualPDFURL:         iclaFileURL,
+			CorporatePDFURL:          cclaFileURL,
+			IndividualPDFURLByLocale: iclaLocalizedFileURLs,
+			CorporatePDFURLByLocale:  cclaLocalizedFileURLs,
 		}
 	} else if claGroup.ProjectCCLAEnabled {
 		pdfUrls = models.TemplatePdfs{
-			CorporatePDFURL: cclaFileURL,
+			CorporatePDFURL:         cclaFileURL,
+			CorporatePDFURLByLocale: cclaLocalizedFileURLs,
 		}
 	} else if claGroup.ProjectICLAEnabled {
 		pdfUrls = models.TemplatePdfs{
-			IndividualPDFURL: iclaFileURL,
+			IndividualPDFURL:         iclaFileURL,
+			IndividualPDFURLByLocale: iclaLocalizedFileURLs,
 		}
 	}
 
@@ -263,12 +418,14 @@ func (s service) CreateCLAGroupTemplate(ctx context.Context, claGroupID string,
 	return pdfUrls, nil
 }
 
-func (s service) GetCLATemplatePreview(ctx context.Context, claGroupID, claType string, watermark bool) ([]byte, error) {
+// GetCLATemplatePreview returns the PDF of the CLA Group document, the localized PDF when a locale is provided
+func (s service) GetCLATemplatePreview(ctx context.Context, claGroupID, claType, locale string, watermark bool) ([]byte, error) {
 	f := logrus.Fields{
 		"functionName":   "GetCLATemplatePreview",
 		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 		"claGroupID":     claGroupID,
 		"claType":        claType,
+		"locale":         locale,
 		"watermark":      watermark,
 	}
 
@@ -323,6 +480,14 @@ func (s service) GetCLATemplatePreview(ctx context.Context, claGroupID, claType
 		return nil, err
 	}
 
+	if locale != "" && !strings.EqualFold(locale, DefaultLocale) {
+		pdfS3URL, err = s.templateRepo.GetLocalizedDocumentS3URL(claGroupID, claType, doc.DocumentS3URL, locale)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to find the localized document")
+			return nil, err
+		}
+	}
+
 	// Convert:
 	//   https://cla-signature-files-dev.s3.amazonaws.com/contract-group/66b97366-a298-4625-965e-0c292c39f9a2/template/ccla-2020-09-25T22-37-51Z.pdf
 	// to:
@@ -394,16 +559,19 @@ func (s service) InjectProjectInformationIntoTemplate(template models.Template,
 	return iclaTemplateHTML, cclaTemplateHTML, nil
 }
 
-// generateTemplateS3FilePath helper function to generate a suitable s3 path and filename for the template
-func (s service) generateTemplateS3FilePath(claGroupID, claType string) string {
+// generateTemplateS3FilePath helper function to generate a suitable s3 path and filename for the template, the locale
+// is part of the filename of the localized PDFs
+func (s service) generateTemplateS3FilePath(claGroupID, claType, locale string) string {
 	fileNameTemplate := "contract-group/%s/template/%s"
+	prefix := claType
+	if locale != "" {
+		prefix = fmt.Sprintf("%s-%s", claType, strings.ToLower(locale))
+	}
 	var ext string
 	switch claType {
-	case claTypeICLA:
-		// Format would be, for example: icla-2020-09-25T22-32-59Z.pdf
-		ext = fmt.Sprintf("icla-%s.pdf", strings.ReplaceAll(utils.CurrentSimpleDateTimeString(), ":", "-"))
-	case claTypeCCLA:
-		ext = fmt.Sprintf("ccla-%s.pdf", strings.ReplaceAll(utils.CurrentSimpleDateTimeString(), ":", "-"))
+	case claTypeICLA, claTypeCCLA:
+		// Format would be, for example: icla-2020-09-25T22-32-59Z.pdf or icla-de-2020-09-25T22-32-59Z.pdf
+		ext = fmt.Sprintf("%s-%s.pdf", prefix, strings.ReplaceAll(utils.CurrentSimpleDateTimeString(), ":", "-"))
 	default:
 		return ""
 	}
@@ -411,8 +579,67 @@ func (s service) generateTemplateS3FilePath(claGroupID, claType string) string {
 	return fileName
 }
 
+// createLocalizedPDFs renders and uploads a PDF for each translation of the CLA type and returns their URLs by locale,
+// the localized PDFs contain the legally binding English text followed by the courtesy translation
+func (s service) createLocalizedPDFs(ctx context.Context, bucket, claGroupID, claType string, template models.Template, metaFields []*models.MetaField) (map[string]string, error) {
+	f := logrus.Fields{
+		"functionName":   "createLocalizedPDFs",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"claType":        claType,
+		"templateID":     template.ID,
+	}
+
+	fileURLs := map[string]string{}
+	for _, localization := range template.Localizations {
+		if localization == nil {
+			continue
+		}
+		translation := localization.IclaHTMLBody
+		if claType == claTypeCCLA {
+			translation = localization.CclaHTMLBody
+		}
+		if strings.TrimSpace(translation) == "" {
+			continue
+		}
+
+		localized, err := localizedTemplate(template, localization.Locale)
+		if err != nil {
+			return nil, err
+		}
+		iclaTemplateHTML, cclaTemplateHTML, err := s.InjectProjectInformationIntoTemplate(localized, metaFields)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to inject metadata details into the %s translation", localization.Locale)
+			return nil, err
+		}
+		templateHTML := iclaTemplateHTML
+		if claType == claTypeCCLA {
+			templateHTML = cclaTemplateHTML
+		}
+
+		log.WithFields(f).Debugf("Creating PDF for %s with the %s translation", claType, localization.Locale)
+		pdf, err := s.pdfRenderer.CreatePDF(ctx, templateHTML, claType)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("Problem generating the %s translation via the pdf renderer", localization.Locale)
+			return nil, err
+		}
+		fileName := s.generateTemplateS3FilePath(claGroupID, claType, localization.Locale)
+		fileURL, err := s.SaveTemplateToS3(ctx, bucket, fileName, pdf)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warnf("Problem uploading the localized PDF: %s to s3", fileName)
+			return nil, err
+		}
+		fileURLs[localization.Locale] = fileURL
+	}
+
+	if len(fileURLs) == 0 {
+		return nil, nil
+	}
+	return fileURLs, nil
+}
+
 // SaveTemplateToS3
-func (s service) SaveTemplateToS3(bucket, filepath string, template io.ReadCloser) (string, error) {
+func (s service) SaveTemplateToS3(ctx context.Context, bucket, filepath string, template io.ReadCloser) (string, error) {
 	f := logrus.Fields{
 		"functionName": "SaveTemplateToS3",
 		"bucket":       bucket,
@@ -426,7 +653,7 @@ func (s service) SaveTemplateToS3(bucket, filepath string, template io.ReadClose
 	}()
 
 	// Upload the file to S3.
-	result, err := s.s3Client.Upload(&s3manager.UploadInput{
+	result, err := s.s3Client.UploadWithContext(ctx, &s3manager.UploadInput{
 		Bucket:      aws.String(bucket),
 		Key:         aws.String(filepath),
 		Body:        template,
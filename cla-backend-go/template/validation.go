@@ -0,0 +1,209 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aymerick/raymond"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+)
+
+var (
+	// ErrInvalidTemplate error - the problems of the template are appended to the message
+	ErrInvalidTemplate = errors.New("invalid template")
+)
+
+var (
+	// templateVariableRegex matches the template variables InjectProjectInformationIntoTemplate is able to substitute
+	templateVariableRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// placeholderRegex matches the {{ }} and {{{ }}} expressions of the HTML bodies
+	placeholderRegex = regexp.MustCompile(`\{\{\{?([^{}]*)\}?\}\}`)
+)
+
+// ValidateTemplate checks the template can be rendered by InjectProjectInformationIntoTemplate: the HTML bodies must
+// only use the declared meta field variables as placeholders - helpers, blocks and partials are not supported - every
+// declared variable must be used and the anchor strings of the DocuSign fields must be part of the HTML bodies. The
+// translations are checked by validateLocalizations
+func ValidateTemplate(template *models.Template) error {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if strings.TrimSpace(template.Name) == "" {
+		addProblem("the template name is required")
+	}
+	if strings.TrimSpace(template.IclaHTMLBody) == "" && strings.TrimSpace(template.CclaHTMLBody) == "" {
+		addProblem("at least one of the ICLA or CCLA HTML bodies is required")
+	}
+
+	declared := map[string]bool{}
+	names := map[string]bool{}
+	for _, metaField := range template.MetaFields {
+		if metaField == nil {
+			continue
+		}
+		if metaField.Name == "" {
+			addProblem("the name of the meta field with the variable %s is required", metaField.TemplateVariable)
+		} else if names[metaField.Name] {
+			addProblem("the meta field name %s is declared more than once", metaField.Name)
+		}
+		names[metaField.Name] = true
+
+		if !templateVariableRegex.MatchString(metaField.TemplateVariable) {
+			addProblem("the template variable %q of the meta field %s is not valid, only letters, digits and underscores are supported", metaField.TemplateVariable, metaField.Name)
+			continue
+		}
+		if declared[metaField.TemplateVariable] {
+			addProblem("the template variable %s is declared more than once", metaField.TemplateVariable)
+		}
+		declared[metaField.TemplateVariable] = true
+	}
+
+	used := map[string]bool{}
+	bodies := []struct{ claType, body string }{
+		{claType: claTypeICLA, body: template.IclaHTMLBody},
+		{claType: claTypeCCLA, body: template.CclaHTMLBody},
+	}
+	for _, b := range bodies {
+		claType, body := b.claType, b.body
+		if body == "" {
+			continue
+		}
+		if _, err := raymond.Parse(body); err != nil {
+			addProblem("the %s HTML body can't be parsed: %v", claType, err)
+			continue
+		}
+
+		for _, match := range placeholderRegex.FindAllStringSubmatch(body, -1) {
+			variable := strings.TrimSpace(match[1])
+			switch {
+			case !templateVariableRegex.MatchString(variable):
+				addProblem("the %s HTML body uses the unsupported expression %s, only the meta field variables are supported", claType, match[0])
+			case !declared[variable]:
+				addProblem("the %s HTML body uses the placeholder %s which is not declared as meta field", claType, variable)
+			default:
+				used[variable] = true
+			}
+		}
+	}
+
+	for _, metaField := range template.MetaFields {
+		if metaField != nil && declared[metaField.TemplateVariable] && !used[metaField.TemplateVariable] {
+			addProblem("the template variable %s is not used in the HTML bodies", metaField.TemplateVariable)
+		}
+	}
+
+	problems = append(problems, validateFields(claTypeICLA, template.IclaHTMLBody, template.IclaFields)...)
+	problems = append(problems, validateFields(claTypeCCLA, template.CclaHTMLBody, template.CclaFields)...)
+	problems = append(problems, validateLocalizations(template, declared)...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidTemplate, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateFields checks the DocuSign fields of the CLA type have an ID and can be placed with their anchor string
+func validateFields(claType, body string, fields []*models.Field) []string {
+	var problems []string
+	if len(fields) > 0 && body == "" {
+		return append(problems, fmt.Sprintf("the %s fields require the %s HTML body", claType, claType))
+	}
+
+	// DocuSign matches the anchor strings case insensitive
+	lowerBody := strings.ToLower(body)
+	ids := map[string]bool{}
+	for _, field := range fields {
+		if field == nil {
+			continue
+		}
+		if field.ID == "" {
+			problems = append(problems, fmt.Sprintf("the id of the %s field %s is required", claType, field.Name))
+		} else if ids[field.ID] {
+			problems = append(problems, fmt.Sprintf("the %s field id %s is declared more than once", claType, field.ID))
+		}
+		ids[field.ID] = true
+
+		if field.AnchorString == "" || !strings.Contains(lowerBody, strings.ToLower(field.AnchorString)) {
+			problems = append(problems, fmt.Sprintf("the anchor string %q of the %s field %s is not part of the %s HTML body", field.AnchorString, claType, field.ID, claType))
+		}
+	}
+	return problems
+}
+
+// validateLocalizations checks the translations can be appended to the English HTML bodies: one translation per valid
+// locale other than the default locale, only the declared meta field variables as placeholders and none of the anchor
+// strings - DocuSign places a field on every occurrence of its anchor string and the fields must only be placed on
+// the legally binding English text
+func validateLocalizations(template *models.Template, declared map[string]bool) []string {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	locales := map[string]bool{}
+	for _, localization := range template.Localizations {
+		if localization == nil {
+			continue
+		}
+		locale := localization.Locale
+		switch {
+		case !localeRegex.MatchString(locale):
+			addProblem("the translation locale %q is not a valid language tag", locale)
+		case strings.EqualFold(locale, DefaultLocale):
+			addProblem("the translation locale %s is the locale of the template HTML bodies", locale)
+		case locales[strings.ToLower(locale)]:
+			addProblem("the translation locale %s is declared more than once", locale)
+		}
+		locales[strings.ToLower(locale)] = true
+
+		if strings.TrimSpace(localization.IclaHTMLBody) == "" && strings.TrimSpace(localization.CclaHTMLBody) == "" {
+			addProblem("the %s translation requires at least one of the ICLA or CCLA HTML bodies", locale)
+			continue
+		}
+
+		bodies := []struct {
+			claType, body, englishBody string
+			fields                     []*models.Field
+		}{
+			{claType: claTypeICLA, body: localization.IclaHTMLBody, englishBody: template.IclaHTMLBody, fields: template.IclaFields},
+			{claType: claTypeCCLA, body: localization.CclaHTMLBody, englishBody: template.CclaHTMLBody, fields: template.CclaFields},
+		}
+		for _, b := range bodies {
+			claType, body := b.claType, b.body
+			if body == "" {
+				continue
+			}
+			if b.englishBody == "" {
+				addProblem("the %s %s translation requires the %s HTML body", locale, claType, claType)
+				continue
+			}
+			if _, err := raymond.Parse(body); err != nil {
+				addProblem("the %s %s translation can't be parsed: %v", locale, claType, err)
+				continue
+			}
+
+			for _, match := range placeholderRegex.FindAllStringSubmatch(body, -1) {
+				variable := strings.TrimSpace(match[1])
+				if !templateVariableRegex.MatchString(variable) || !declared[variable] {
+					addProblem("the %s %s translation uses the placeholder %s which is not declared as meta field", locale, claType, match[0])
+				}
+			}
+
+			// DocuSign matches the anchor strings case insensitive
+			lowerBody := strings.ToLower(body)
+			for _, field := range b.fields {
+				if field != nil && field.AnchorString != "" && strings.Contains(lowerBody, strings.ToLower(field.AnchorString)) {
+					addProblem("the %s %s translation contains the anchor string %q of the field %s, the fields are only placed on the English text", locale, claType, field.AnchorString, field.ID)
+				}
+			}
+		}
+	}
+	return problems
+}
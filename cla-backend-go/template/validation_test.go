@@ -0,0 +1,157 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package template
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func validTemplate() *models.Template {
+	return &models.Template{
+		Name:         "Custom Style",
+		IclaHTMLBody: "<p>{{ PROJECT_NAME }} Individual CLA</p><p>Full name:</p>",
+		CclaHTMLBody: "<p>{{ PROJECT_NAME }} Corporate CLA - {{{CONTACT_EMAIL}}}</p><p>Corporation Name:</p>",
+		MetaFields: []*models.MetaField{
+			{Name: "Project Name", TemplateVariable: "PROJECT_NAME"},
+			{Name: "Contact Email Address", TemplateVariable: "CONTACT_EMAIL"},
+		},
+		IclaFields: []*models.Field{
+			{ID: "full_name", Name: "Full Name", AnchorString: "Full name:"},
+		},
+		CclaFields: []*models.Field{
+			{ID: "corporation_name", Name: "Corporation Name", AnchorString: "Corporation Name:"},
+		},
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		modify   func(template *models.Template)
+		problems []string
+	}{
+		{
+			name:   "valid template",
+			modify: func(template *models.Template) {},
+		},
+		{
+			name: "missing name and bodies",
+			modify: func(template *models.Template) {
+				template.Name = " "
+				template.IclaHTMLBody = ""
+				template.CclaHTMLBody = ""
+				template.MetaFields = nil
+				template.IclaFields = nil
+				template.CclaFields = nil
+			},
+			problems: []string{"the template name is required", "at least one of the ICLA or CCLA HTML bodies is required"},
+		},
+		{
+			name: "undeclared placeholder",
+			modify: func(template *models.Template) {
+				template.IclaHTMLBody += "{{ PROJECT_ENTITY_NAME }}"
+			},
+			problems: []string{"the icla HTML body uses the placeholder PROJECT_ENTITY_NAME which is not declared as meta field"},
+		},
+		{
+			name: "helpers and blocks",
+			modify: func(template *models.Template) {
+				template.CclaHTMLBody += "{{#if PROJECT_NAME}}{{/if}}{{> footer}}"
+			},
+			problems: []string{
+				"the ccla HTML body uses the unsupported expression {{#if PROJECT_NAME}}",
+				"the ccla HTML body uses the unsupported expression {{/if}}",
+				"the ccla HTML body uses the unsupported expression {{> footer}}",
+			},
+		},
+		{
+			name: "unused and duplicate variables",
+			modify: func(template *models.Template) {
+				template.MetaFields = append(template.MetaFields,
+					&models.MetaField{Name: "Project Entity Name", TemplateVariable: "PROJECT_ENTITY_NAME"},
+					&models.MetaField{Name: "Project", TemplateVariable: "PROJECT_NAME"},
+					&models.MetaField{Name: "Invalid", TemplateVariable: "PROJECT NAME"})
+			},
+			problems: []string{
+				"the template variable PROJECT_NAME is declared more than once",
+				`the template variable "PROJECT NAME" of the meta field Invalid is not valid`,
+				"the template variable PROJECT_ENTITY_NAME is not used in the HTML bodies",
+			},
+		},
+		{
+			name: "missing anchor string",
+			modify: func(template *models.Template) {
+				template.IclaFields[0].AnchorString = "Signature:"
+				template.CclaFields = append(template.CclaFields, &models.Field{ID: "corporation_name", AnchorString: "Corporation Name:"})
+			},
+			problems: []string{
+				`the anchor string "Signature:" of the icla field full_name is not part of the icla HTML body`,
+				"the ccla field id corporation_name is declared more than once",
+			},
+		},
+		{
+			name: "valid translation",
+			modify: func(template *models.Template) {
+				template.Localizations = []*models.TemplateLocalization{
+					{Locale: "de", IclaHTMLBody: "<p>{{ PROJECT_NAME }} Individuelle CLA</p><p>Vollständiger Name</p>"},
+					{Locale: "pt-BR", CclaHTMLBody: "<p>{{ PROJECT_NAME }} CLA Corporativa</p>"},
+				}
+			},
+		},
+		{
+			name: "invalid translations",
+			modify: func(template *models.Template) {
+				template.IclaHTMLBody = ""
+				template.IclaFields = nil
+				template.Localizations = []*models.TemplateLocalization{
+					{Locale: "en", CclaHTMLBody: "<p>Corporate CLA</p>"},
+					{Locale: "German", CclaHTMLBody: "<p>Unternehmens-CLA</p>"},
+					{Locale: "fr", IclaHTMLBody: "<p>CLA</p>", CclaHTMLBody: "<p>{{ PROJECT_ENTITY_NAME }}</p><p>corporation name:</p>"},
+					{Locale: "pt-BR", CclaHTMLBody: "<p>CLA Corporativa</p>"},
+					{Locale: "pt-br"},
+				}
+			},
+			problems: []string{
+				"the translation locale en is the locale of the template HTML bodies",
+				`the translation locale "German" is not a valid language tag`,
+				"the fr icla translation requires the icla HTML body",
+				"the fr ccla translation uses the placeholder {{ PROJECT_ENTITY_NAME }} which is not declared as meta field",
+				`the fr ccla translation contains the anchor string "Corporation Name:" of the field corporation_name`,
+				"the translation locale pt-br is declared more than once",
+				"the pt-br translation requires at least one of the ICLA or CCLA HTML bodies",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			template := validTemplate()
+			tc.modify(template)
+			err := ValidateTemplate(template)
+			if len(tc.problems) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.True(t, errors.Is(err, ErrInvalidTemplate))
+				for _, problem := range tc.problems {
+					assert.Contains(t, err.Error(), problem)
+				}
+				assert.Equal(t, len(tc.problems), strings.Count(err.Error(), ";")+1)
+			}
+		})
+	}
+}
+
+func TestValidateBuiltInTemplates(t *testing.T) {
+	for _, template := range templateMap {
+		template := template
+		assert.NoError(t, ValidateTemplate(&template), template.Name)
+	}
+}
@@ -260,6 +260,83 @@ func TestValidDomain(t *testing.T) {
 	}
 }
 
+// TestValidDomainApprovalListEntry tests the domain approval list entry validator
+func TestValidDomainApprovalListEntry(t *testing.T) {
+	validEntries := []string{
+		"example.com",
+		"*.example.com",
+		"*example.com",
+		".corp.example.com",
+		`regex:example\.(com|de|fr)`,
+		`regex:[a-z]+\.corp\.example\.com`,
+	}
+	inValidEntries := []string{
+		"",
+		"*.",
+		"**.example.com",
+		"example_com",
+		"regex:",
+		"regex:example(com",
+		"regex:" + strings.Repeat("a", 256),
+		"regex:.*",
+		`regex:.+\..+`,
+		`regex:[a-z]+\.(com|org)`,
+		`regex:[[:alpha:]]+\.example\.com`,
+		`regex:(a+)+\.example\.com`,
+		`regex:(corp|(eu|us))\.example\.com`,
+		`regex:^example\.com$`,
+		`regex:ex\w+\.com`,
+		`regex:example\.c{2}om`,
+		`regex:[a-z]+[0-9]+[a-z]+[0-9]+\.example\.com`,
+	}
+
+	for _, entry := range validEntries {
+		msg, valid := utils.ValidDomainApprovalListEntry(entry)
+		assert.True(t, valid, fmt.Sprintf("valid entry %s %s", entry, msg))
+	}
+
+	for _, entry := range inValidEntries {
+		msg, valid := utils.ValidDomainApprovalListEntry(entry)
+		assert.False(t, valid, fmt.Sprintf("invalid entry %s %s", entry, msg))
+	}
+}
+
+// TestDomainApprovalListMatch tests the domain approval list matching
+func TestDomainApprovalListMatch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		email   string
+		entries []string
+		match   string
+	}{
+		{name: "plain domain", email: "user@example.com", entries: []string{"example.com"}, match: "example.com"},
+		{name: "plain domain is case insensitive", email: " User@Example.COM ", entries: []string{"EXAMPLE.com"}, match: "EXAMPLE.com"},
+		{name: "plain domain does not match sub-domains", email: "user@corp.example.com", entries: []string{"example.com"}},
+		{name: "wildcard matches the domain", email: "user@example.com", entries: []string{"*.example.com"}, match: "*.example.com"},
+		{name: "wildcard matches sub-domains", email: "user@eu.corp.example.com", entries: []string{"*.example.com"}, match: "*.example.com"},
+		{name: "dot prefix matches sub-domains", email: "user@corp.example.com", entries: []string{".example.com"}, match: ".example.com"},
+		{name: "star prefix matches sub-domains", email: "user@corp.example.com", entries: []string{"*example.com"}, match: "*example.com"},
+		{name: "wildcard does not match a lookalike domain", email: "user@evil-example.com", entries: []string{"*.example.com", "*example.com", ".example.com"}},
+		{name: "wildcard does not match a longer domain", email: "user@example.com.evil.org", entries: []string{"*.example.com"}},
+		{name: "plain domain dots are not wildcards", email: "user@exampleXcom", entries: []string{"example.com"}},
+		{name: "regex matches the whole domain", email: "user@example.de", entries: []string{`regex:example\.(com|de)`}, match: `regex:example\.(com|de)`},
+		{name: "regex is anchored", email: "user@evil-example.com", entries: []string{`regex:example\.(com|de)`}},
+		{name: "invalid regex never matches", email: "user@example.com", entries: []string{"regex:example(com"}},
+		{name: "regex matching any domain never matches", email: "user@gmail.com", entries: []string{"regex:.*", `regex:.+\..+`, `regex:[a-z]+\.com`}},
+		{name: "the first matching entry is returned", email: "user@corp.example.com", entries: []string{"example.org", "*.example.com", "regex:.*"}, match: "*.example.com"},
+		{name: "email without a domain", email: "user@", entries: []string{"regex:.*"}},
+		{name: "no entries", email: "user@example.com"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, ok := utils.DomainApprovalListMatch(tc.email, tc.entries)
+			assert.Equal(t, tc.match != "", ok)
+			assert.Equal(t, tc.match, match)
+		})
+	}
+}
+
 // TestGitHubUsername tests the GitHub username validator
 func TestGitHubUsername(t *testing.T) {
 	validGitHubUsername := []string{
@@ -12,15 +12,15 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // RepositoryDynamo data model
 type RepositoryDynamo struct {
 	Stage          string
-	DynamoDBClient *dynamodb.DynamoDB
+	DynamoDBClient storage.Driver
 }
 
 // RepositoryService interface methods
@@ -49,10 +49,10 @@ type User struct {
 }
 
 // NewDynamoRepository creates a new dynamo repository model
-func NewDynamoRepository(awsSession *session.Session, stage string) RepositoryService {
+func NewDynamoRepository(dynamoDBClient storage.Driver, stage string) RepositoryService {
 	return RepositoryDynamo{
 		Stage:          stage,
-		DynamoDBClient: dynamodb.New(awsSession),
+		DynamoDBClient: dynamoDBClient,
 	}
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/communitybridge/easycla/cla-backend-go/config"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
@@ -100,7 +101,8 @@ func Write(user event.Event) {
 	if stage == "" {
 		log.Fatal("stage not set")
 	}
-	usersRepo := users.NewRepository(awsSession, stage)
+	dynamoDBClient := dynamodb.New(awsSession)
+	usersRepo := users.NewRepository(dynamoDBClient, stage)
 
 	userDetails, userErr = usersRepo.GetUserByLFUserName(*uc.Username)
 	if userErr != nil {
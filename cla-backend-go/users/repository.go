@@ -23,8 +23,8 @@ import (
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // UserRepository interface defines the functions for the users repository
@@ -44,15 +44,15 @@ type UserRepository interface {
 // repository data model
 type repository struct {
 	stage          string
-	dynamoDBClient *dynamodb.DynamoDB
+	dynamoDBClient storage.Driver
 	tableName      string
 }
 
 // NewRepository creates a new instance of the whitelist service
-func NewRepository(awsSession *session.Session, stage string) UserRepository {
+func NewRepository(dynamoDBClient storage.Driver, stage string) UserRepository {
 	return repository{
 		stage:          stage,
-		dynamoDBClient: dynamodb.New(awsSession),
+		dynamoDBClient: dynamoDBClient,
 		tableName:      fmt.Sprintf("cla-%s-users", stage),
 	}
 }
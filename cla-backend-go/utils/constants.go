@@ -102,6 +102,9 @@ const ProjectTypeProject = "Project"
 // GitHubType is the repository type identifier for github
 const GitHubType = "github"
 
+// GitLabType is the repository type identifier for gitlab
+const GitLabType = "gitlab"
+
 // SortOrderAscending ascending sort order constant
 const SortOrderAscending = "asc"
 
@@ -0,0 +1,235 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DomainApprovalListRegexPrefix marks a domain approval list entry as a regular expression rule, the expression is
+// matched against the whole domain of the email address, e.g. regex:example\.(com|de|fr)
+const DomainApprovalListRegexPrefix = "regex:"
+
+// domainApprovalListRegexMaxLength is the maximum length of a regular expression rule
+const domainApprovalListRegexMaxLength = 255
+
+// domainApprovalListRegexMaxRepeats is the maximum number of + and * repetitions of a regular expression rule, which
+// bounds the backtracking of the Python matcher
+const domainApprovalListRegexMaxRepeats = 3
+
+// domainApprovalListRegexProbes are unrelated domains a regular expression rule must not match - a rule matching
+// them would approve nearly every email address
+var domainApprovalListRegexProbes = []string{
+	"gmail.com",
+	"outlook.com",
+	"yahoo.co.jp",
+	"qq.com",
+	"mail.ru",
+	"gmx.de",
+	"proton.me",
+	"icloud.com",
+	"a.b.c.d.e.f.xyz",
+	"x-1.io",
+}
+
+// domainApprovalListWildcardPrefixes are the prefixes which make an entry match the domain and all of its sub-domains
+var domainApprovalListWildcardPrefixes = []string{"*.", "*", "."}
+
+// ValidDomainApprovalListEntry tests the specified domain approval list entry - a domain (example.com), a wildcard
+// domain (*.example.com) or a regular expression rule (regex:...) - returns true if the entry is valid, returns false
+// and a message otherwise
+func ValidDomainApprovalListEntry(entry string) (string, bool) {
+	entry = strings.TrimSpace(entry)
+
+	if strings.HasPrefix(entry, DomainApprovalListRegexPrefix) {
+		expr := strings.TrimPrefix(entry, DomainApprovalListRegexPrefix)
+		re, err := compileDomainApprovalListRegex(expr)
+		if err != nil {
+			return err.Error(), false
+		}
+		for _, probe := range domainApprovalListRegexProbes {
+			if re.MatchString(probe) {
+				return fmt.Sprintf("regular expression matches unrelated domains such as %s", probe), false
+			}
+		}
+		return "", true
+	}
+
+	domain, _ := splitDomainApprovalListWildcard(entry)
+	return ValidDomain(domain)
+}
+
+// DomainApprovalListMatch returns the first domain approval list entry matching the domain of the email address,
+// returns false if none of the entries match. Plain domains only match the domain itself, wildcard domains match the
+// domain and its sub-domains but never a domain merely ending with the same characters, e.g. *.example.com matches
+// example.com and corp.example.com but not evil-example.com.
+func DomainApprovalListMatch(email string, entries []string) (string, bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	at := strings.LastIndex(email, "@")
+	if at < 0 || at == len(email)-1 {
+		return "", false
+	}
+	emailDomain := email[at+1:]
+
+	for _, entry := range entries {
+		if domainApprovalListEntryMatches(emailDomain, entry) {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// domainApprovalListEntryMatches returns true if the lower case email domain matches the domain approval list entry
+func domainApprovalListEntryMatches(emailDomain, entry string) bool {
+	entry = strings.TrimSpace(entry)
+
+	if strings.HasPrefix(entry, DomainApprovalListRegexPrefix) {
+		// the rules stored before their syntax was restricted are ignored when they no longer validate
+		if _, valid := ValidDomainApprovalListEntry(entry); !valid {
+			return false
+		}
+		re, err := compileDomainApprovalListRegex(strings.TrimPrefix(entry, DomainApprovalListRegexPrefix))
+		if err != nil {
+			return false
+		}
+		return re.MatchString(emailDomain)
+	}
+
+	domain, wildcard := splitDomainApprovalListWildcard(strings.ToLower(entry))
+	if domain == "" {
+		return false
+	}
+	if emailDomain == domain {
+		return true
+	}
+	return wildcard && strings.HasSuffix(emailDomain, "."+domain)
+}
+
+// splitDomainApprovalListWildcard removes the wildcard prefix of the entry, returns the domain and true if the entry
+// had a wildcard prefix
+func splitDomainApprovalListWildcard(entry string) (string, bool) {
+	for _, prefix := range domainApprovalListWildcardPrefixes {
+		if strings.HasPrefix(entry, prefix) {
+			return strings.TrimPrefix(entry, prefix), true
+		}
+	}
+	return entry, false
+}
+
+// compileDomainApprovalListRegex checks the syntax of the regular expression rule and compiles it, anchored to the
+// whole domain and case insensitive
+func compileDomainApprovalListRegex(expr string) (*regexp.Regexp, error) {
+	if err := checkDomainApprovalListRegex(expr); err != nil {
+		return nil, err
+	}
+	return regexp.Compile(`(?i)^(?:` + expr + `)$`)
+}
+
+// checkDomainApprovalListRegex checks the regular expression rule only uses the syntax Go and the Python matcher
+// agree on: the domain characters (letters, digits and -), escaped dots (\.), character classes of domain characters
+// and ranges ([a-z0-9-]), groups of alternatives which don't nest ((com|de)) and the ?, + and * quantifiers on a
+// character or a character class - a quantifier never applies to a group, which rules out the nested repetitions
+// the Python matcher backtracks on
+func checkDomainApprovalListRegex(expr string) error {
+	switch {
+	case len(expr) == 0:
+		return errors.New("regular expression is empty")
+	case len(expr) > domainApprovalListRegexMaxLength:
+		return fmt.Errorf("regular expression length is %d, can't exceed %d", len(expr), domainApprovalListRegexMaxLength)
+	}
+
+	depth, repeats := 0, 0
+	quantifiable := false
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case isDomainChar(c):
+			quantifiable = true
+		case c == '\\':
+			if i+1 == len(expr) || expr[i+1] != '.' {
+				return errors.New("regular expression can only escape the dot")
+			}
+			i++
+			quantifiable = true
+		case c == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return errors.New("regular expression has an unterminated character class")
+			}
+			if !validDomainCharClass(expr[i+1 : i+end]) {
+				return fmt.Errorf("regular expression character class %s can only hold letters, digits, ranges and a trailing -", expr[i:i+end+1])
+			}
+			i += end
+			quantifiable = true
+		case c == '(':
+			if depth > 0 {
+				return errors.New("regular expression groups can't be nested")
+			}
+			depth++
+			quantifiable = false
+		case c == ')':
+			if depth == 0 {
+				return errors.New("regular expression has an unbalanced )")
+			}
+			depth--
+			quantifiable = false
+		case c == '|':
+			quantifiable = false
+		case c == '?' || c == '+' || c == '*':
+			if !quantifiable {
+				return fmt.Errorf("regular expression quantifier %c can only follow a character or a character class", c)
+			}
+			if c != '?' {
+				repeats++
+			}
+			quantifiable = false
+		default:
+			return fmt.Errorf("regular expression character %q is not supported", c)
+		}
+	}
+	if depth != 0 {
+		return errors.New("regular expression has an unbalanced (")
+	}
+	if repeats > domainApprovalListRegexMaxRepeats {
+		return fmt.Errorf("regular expression has %d + and * quantifiers, can't exceed %d", repeats, domainApprovalListRegexMaxRepeats)
+	}
+	return nil
+}
+
+// isDomainChar returns true for the characters of a domain label
+func isDomainChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '-'
+}
+
+// validDomainCharClass returns true if the character class content only holds domain characters and letter or digit
+// ranges, a - which isn't part of a range has to be the last character
+func validDomainCharClass(class string) bool {
+	if class == "" {
+		return false
+	}
+	for i := 0; i < len(class); i++ {
+		c := class[i]
+		if c == '-' {
+			if i != len(class)-1 {
+				return false
+			}
+			continue
+		}
+		if !isDomainChar(c) {
+			return false
+		}
+		if i+2 < len(class) && class[i+1] == '-' {
+			from, to := class[i], class[i+2]
+			sameKind := (from >= 'a' && to <= 'z') || (from >= 'A' && to <= 'Z') || (from >= '0' && to <= '9')
+			if !sameKind || from > to {
+				return false
+			}
+			i += 2
+		}
+	}
+	return true
+}
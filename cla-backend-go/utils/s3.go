@@ -5,6 +5,8 @@ package utils
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"io/ioutil"
 	"strings"
@@ -44,6 +46,16 @@ func SetS3Storage(awsSession *session.Session, bucketName string) {
 	}
 }
 
+// SetS3StorageClient sets the default S3Storage, e.g. to a fake in the tests
+func SetS3StorageClient(storage S3Storage) {
+	s3Storage = storage
+}
+
+// GetS3Storage returns the current default S3Storage
+func GetS3Storage() S3Storage {
+	return s3Storage
+}
+
 // Upload file to s3 storage at path contract-group/<project-ID>/<claType>/<identifier>/<signatureID>.pdf
 // claType should be cla or ccla
 // identifier can be user-id or company-id
@@ -140,6 +152,13 @@ func SignedCLAFilename(projectID string, claType string, identifier string, sign
 	return strings.Join([]string{"contract-group", projectID, claType, identifier, signatureID}, "/") + ".pdf"
 }
 
+// SignedDocumentDigest returns the hex encoded SHA-256 digest of the signed document content - the same value
+// is recorded on the signature record when the signed document is stored
+func SignedDocumentDigest(fileContent []byte) string {
+	digest := sha256.Sum256(fileContent)
+	return hex.EncodeToString(digest[:])
+}
+
 // SignedClaGroupZipFilename provides s3 bucket url of zip of pdf
 func SignedClaGroupZipFilename(projectID string, claType string) string {
 	return strings.Join([]string{"contract-group", projectID, claType}, "/") + ".zip"
@@ -258,6 +258,23 @@ func ValidGitHubUsername(githubUsername string) (string, bool) {
 	return "", true
 }
 
+// ValidGitLabUsername tests the specified GitLab username string, returns true if valid, returns false otherwise
+func ValidGitLabUsername(gitlabUsername string) (string, bool) {
+	gitlabUsername = strings.TrimSpace(gitlabUsername)
+
+	if len(gitlabUsername) < 2 || len(gitlabUsername) > 255 {
+		return "gitlab username must be between 2 and 255 characters", false
+	}
+
+	// GitLab usernames start and end with an alpha numeric value and can contain dots, dashes and underscores
+	re := regexp.MustCompile(`^[a-zA-Z0-9_]([a-zA-Z0-9._-]*[a-zA-Z0-9_-])?$`)
+	if !re.MatchString(gitlabUsername) || strings.Contains(gitlabUsername, "..") {
+		return fmt.Sprintf("invalid GitLab username: %s", gitlabUsername), false
+	}
+
+	return "", true
+}
+
 // ValidGitHubOrg tests the specified GitHub Organization string, returns true if valid, returns false otherwise
 func ValidGitHubOrg(githubOrg string) (string, bool) {
 
@@ -19,6 +19,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/communitybridge/easycla/cla-backend-go/token"
 
 	"github.com/communitybridge/easycla/cla-backend-go/v2/acs-service/client"
@@ -54,11 +55,7 @@ func InitClient(APIGwURL string, apiKey string) {
 	acsServiceClient = &Client{
 		apiKey:   apiKey,
 		apiGwURL: APIGwURL,
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     url,
-			BasePath: "acs/v1/api",
-			Schemes:  []string{"https"},
-		}),
+		cl:       client.New(telemetry.NewClientTransport("acs-service", url, "acs/v1/api", []string{"https"}), strfmt.Default),
 	}
 }
 
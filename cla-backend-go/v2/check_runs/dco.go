@@ -0,0 +1,71 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package check_runs
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// dcoURL is the text of the Developer Certificate of Origin the contributors certify by signing off their commits
+const dcoURL = "https://developercertificate.org/"
+
+// signOffPattern matches the Signed-off-by trailers of a commit message, e.g. Signed-off-by: Jane Doe <jane@example.org>
+var signOffPattern = regexp.MustCompile(`(?mi)^\s*Signed-off-by:\s*(.*?)\s*<([^<>\s]+)>\s*$`)
+
+// signOff is a Signed-off-by trailer of a commit message
+type signOff struct {
+	name  string
+	email string
+}
+
+// signOffs returns the Signed-off-by trailers of the commit message
+func signOffs(message string) []signOff {
+	var result []signOff
+	for _, match := range signOffPattern.FindAllStringSubmatch(message, -1) {
+		result = append(result, signOff{name: match[1], email: match[2]})
+	}
+	return result
+}
+
+// isSignedOff returns true if the commit is signed off by its author, the email of a Signed-off-by trailer has to match
+// the email of the commit author. Merge commits don't need a sign-off, the merged commits are checked on their own.
+// The reason explains why the commit isn't signed off.
+func isSignedOff(commit *github.RepositoryCommit) (bool, string) {
+	if len(commit.Parents) > 1 {
+		return true, ""
+	}
+
+	authorEmail := commit.GetCommit().GetAuthor().GetEmail()
+	trailers := signOffs(commit.GetCommit().GetMessage())
+	if len(trailers) == 0 {
+		return false, "the commit message has no Signed-off-by trailer"
+	}
+	for _, trailer := range trailers {
+		if authorEmail != "" && strings.EqualFold(trailer.email, authorEmail) {
+			return true, ""
+		}
+	}
+	if authorEmail == "" {
+		return false, "the email of the commit author is unknown"
+	}
+	return false, fmt.Sprintf("no Signed-off-by trailer matches the commit author email %s", authorEmail)
+}
+
+// dcoRemediation returns the instructions to sign off the commits of a pull request with the number of commits
+func dcoRemediation(commits int) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Each commit has to be signed off by its author to certify the [Developer Certificate of Origin](%s): ", dcoURL))
+	b.WriteString("the commit message needs a `Signed-off-by: Name <email>` trailer with the email of the commit author. ")
+	b.WriteString("`git commit --signoff` adds the trailer to new commits, the commits of the pull request are signed off with:\n\n")
+	if commits <= 1 {
+		b.WriteString("```\ngit commit --amend --no-edit --signoff\ngit push --force-with-lease\n```\n")
+	} else {
+		b.WriteString(fmt.Sprintf("```\ngit rebase --signoff HEAD~%d\ngit push --force-with-lease\n```\n", commits))
+	}
+	return b.String()
+}
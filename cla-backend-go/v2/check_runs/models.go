@@ -0,0 +1,52 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package check_runs
+
+import (
+	"encoding/json"
+
+	"github.com/google/go-github/v32/github"
+)
+
+// DBPullRequestCheck is an open pull request whose CLA check run is kept current - the check runs of the tracked pull
+// requests are published again when a signature of the CLA Group changes
+type DBPullRequestCheck struct {
+	CheckID           string `dynamodbav:"check_id"`
+	ClaGroupID        string `dynamodbav:"cla_group_id"`
+	RepositoryID      int64  `dynamodbav:"repository_id"`
+	Owner             string `dynamodbav:"owner"`
+	RepositoryName    string `dynamodbav:"repository_name"`
+	InstallationID    int64  `dynamodbav:"installation_id"`
+	PullRequestNumber int    `dynamodbav:"pull_request_number"`
+	HeadSHA           string `dynamodbav:"head_sha"`
+	CheckRunID        int64  `dynamodbav:"check_run_id"`
+	Conclusion        string `dynamodbav:"conclusion"`
+	DateModified      string `dynamodbav:"date_modified"`
+}
+
+// MergeGroupEvent is the merge_group webhook event, sent when a pull request is added to a merge queue. The event
+// isn't known to the GitHub client library.
+type MergeGroupEvent struct {
+	Action       string               `json:"action"`
+	MergeGroup   MergeGroup           `json:"merge_group"`
+	Repo         *github.Repository   `json:"repository"`
+	Installation *github.Installation `json:"installation"`
+}
+
+// MergeGroup is the temporary branch of a merge queue, the head commit is the merge of the queued pull requests
+type MergeGroup struct {
+	HeadSHA string `json:"head_sha"`
+	HeadRef string `json:"head_ref"`
+	BaseSHA string `json:"base_sha"`
+	BaseRef string `json:"base_ref"`
+}
+
+// ParseMergeGroupEvent parses the payload of a merge_group webhook event
+func ParseMergeGroupEvent(payload []byte) (*MergeGroupEvent, error) {
+	var event MergeGroupEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
@@ -0,0 +1,140 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package check_runs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// Repository stores the pull requests whose CLA check run is kept current
+type Repository interface {
+	SaveCheck(ctx context.Context, check *DBPullRequestCheck) error
+	DeleteCheck(ctx context.Context, checkID string) error
+	GetChecksByCLAGroup(ctx context.Context, claGroupID string) ([]*DBPullRequestCheck, error)
+}
+
+// NewRepository creates a repository backed by the cla-<stage>-pull-request-checks table
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
+	return &repo{
+		stage:          stage,
+		dynamoDBClient: dynamoDBClient,
+		tableName:      fmt.Sprintf("cla-%s-pull-request-checks", stage),
+	}
+}
+
+type repo struct {
+	stage          string
+	dynamoDBClient storage.Driver
+	tableName      string
+}
+
+// checkID returns the ID of the check of the pull request
+func checkID(repositoryID int64, pullRequestNumber int) string {
+	return fmt.Sprintf("%d#%d", repositoryID, pullRequestNumber)
+}
+
+// SaveCheck creates or replaces the check of the pull request
+func (r *repo) SaveCheck(ctx context.Context, check *DBPullRequestCheck) error {
+	f := logrus.Fields{
+		"functionName":   "check_runs.SaveCheck",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"checkID":        check.CheckID,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(check)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the pull request check")
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to save the pull request check")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteCheck deletes the check of the pull request, e.g. when the pull request is closed
+func (r *repo) DeleteCheck(ctx context.Context, checkID string) error {
+	f := logrus.Fields{
+		"functionName":   "check_runs.DeleteCheck",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"checkID":        checkID,
+	}
+
+	_, err := r.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"check_id": {S: aws.String(checkID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to delete the pull request check")
+		return err
+	}
+
+	return nil
+}
+
+// GetChecksByCLAGroup returns the checks of the open pull requests of the repositories of the CLA Group
+func (r *repo) GetChecksByCLAGroup(ctx context.Context, claGroupID string) ([]*DBPullRequestCheck, error) {
+	f := logrus.Fields{
+		"functionName":   "check_runs.GetChecksByCLAGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("cla_group_id").Equal(expression.Value(claGroupID))).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the query expression")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String("cla-group-id-index"),
+	}
+
+	var checks []*DBPullRequestCheck
+	for {
+		results, queryErr := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if queryErr != nil {
+			log.WithFields(f).WithError(queryErr).Warn("unable to query the pull request checks")
+			return nil, queryErr
+		}
+
+		var items []*DBPullRequestCheck
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem unmarshalling the pull request checks")
+			return nil, err
+		}
+		checks = append(checks, items...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return checks, nil
+}
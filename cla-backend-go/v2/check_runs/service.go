@@ -0,0 +1,614 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package check_runs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/google/go-github/v32/github"
+	"github.com/sirupsen/logrus"
+)
+
+// CheckRunName is the name of the check run EasyCLA publishes on the pull requests and merge groups of the repositories
+// in the cla and cla-or-dco enforcement modes, the check run of the dco mode is repositories.DCOStatusCheckName
+const CheckRunName = "EasyCLA"
+
+// MergeGroupEventName is the X-GitHub-Event value of the merge_group events
+const MergeGroupEventName = "merge_group"
+
+// pull request and merge group actions
+const (
+	pullRequestActionOpened          = "opened"
+	pullRequestActionReopened        = "reopened"
+	pullRequestActionSynchronize     = "synchronize"
+	pullRequestActionClosed          = "closed"
+	mergeGroupActionChecksRequested  = "checks_requested"
+	checkRunStatusCompleted          = "completed"
+	checkRunConclusionSuccess        = "success"
+	checkRunConclusionFailure        = "failure"
+	checkRunConclusionActionRequired = "action_required"
+	annotationLevelFailure           = "failure"
+)
+
+// maxAnnotations is the maximum number of annotations GitHub accepts in a check run request
+const maxAnnotations = 50
+
+// commitsPageSize is the page size of the pull request commits, GitHub lists at most 250 commits of a pull request
+const commitsPageSize = 100
+
+// annotationPath is the path of the annotations - an annotation needs a path of the repository while the missing
+// CLA is about the commit, not a file, so the annotations are set on the repository root
+const annotationPath = "."
+
+// ClientFactory returns the GitHub client of an installation of the EasyCLA GitHub App
+type ClientFactory func(installationID int64) (*github.Client, error)
+
+// Service publishes the CLA check runs of the pull requests and merge groups
+type Service interface {
+	CheckPullRequest(ctx context.Context, event *github.PullRequestEvent) error
+	CheckMergeGroup(ctx context.Context, event *MergeGroupEvent) error
+	RecheckCLAGroup(ctx context.Context, claGroupID string) error
+}
+
+type service struct {
+	repo             Repository
+	repositoriesRepo repositories.Repository
+	evaluator        coverage.Evaluator
+	apiURL           string
+	newClient        ClientFactory
+	now              func() time.Time
+}
+
+// NewService creates the check runs service, the sign links of the check runs point to the CLA API
+func NewService(repo Repository, repositoriesRepo repositories.Repository, evaluator coverage.Evaluator, apiURL string, newClient ClientFactory) Service {
+	return &service{
+		repo:             repo,
+		repositoriesRepo: repositoriesRepo,
+		evaluator:        evaluator,
+		apiURL:           strings.TrimSuffix(apiURL, "/"),
+		newClient:        newClient,
+		now:              time.Now,
+	}
+}
+
+// checkTarget is the commit a check run is published on
+type checkTarget struct {
+	claGroupID      string
+	enforcementMode string
+	owner           string
+	repositoryName  string
+	repositoryID    int64
+	installationID  int64
+	// pullRequestNumber is 0 for the merge groups
+	pullRequestNumber int
+	headSHA           string
+}
+
+// commitResult is the coverage of the author of a commit and the sign-off of the commit
+type commitResult struct {
+	sha       string
+	author    string
+	covered   bool
+	reason    string
+	signedOff bool
+	dcoReason string
+	// exemption describes the exemption of the CLA Group covering the author, empty when a signature covers them
+	exemption string
+}
+
+// passed returns true if the commit meets the enforcement mode
+func (r *commitResult) passed(mode string) bool {
+	switch mode {
+	case repositories.EnforcementModeDCO:
+		return r.signedOff
+	case repositories.EnforcementModeCLAOrDCO:
+		return r.covered || r.signedOff
+	}
+	return r.covered
+}
+
+// failure returns why the commit doesn't meet the enforcement mode
+func (r *commitResult) failure(mode string) string {
+	switch mode {
+	case repositories.EnforcementModeDCO:
+		return r.dcoReason
+	case repositories.EnforcementModeCLAOrDCO:
+		return fmt.Sprintf("%s, and %s", r.reason, r.dcoReason)
+	}
+	return r.reason
+}
+
+// CheckPullRequest publishes the check run of the opened or updated pull request, the pull request is tracked until
+// it is closed so its check run is published again when a signature of the CLA Group changes
+func (s *service) CheckPullRequest(ctx context.Context, event *github.PullRequestEvent) error {
+	repo := event.GetRepo()
+	pullRequest := event.GetPullRequest()
+	f := logrus.Fields{
+		"functionName":   "check_runs.CheckPullRequest",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repository":     repo.GetFullName(),
+		"pullRequest":    pullRequest.GetNumber(),
+		"action":         event.GetAction(),
+	}
+
+	switch event.GetAction() {
+	case pullRequestActionOpened, pullRequestActionReopened, pullRequestActionSynchronize:
+	case pullRequestActionClosed:
+		log.WithFields(f).Debug("the pull request is closed, no longer tracking its check")
+		return s.repo.DeleteCheck(ctx, checkID(repo.GetID(), pullRequest.GetNumber()))
+	default:
+		log.WithFields(f).Debug("pull request action doesn't change the commits, skipping")
+		return nil
+	}
+
+	repoModel, err := s.repository(ctx, repo.GetID())
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	target := &checkTarget{
+		claGroupID:        repoModel.RepositoryProjectID,
+		enforcementMode:   repositories.EnforcementMode(repoModel.EnforcementMode),
+		owner:             repo.GetOwner().GetLogin(),
+		repositoryName:    repo.GetName(),
+		repositoryID:      repo.GetID(),
+		installationID:    event.GetInstallation().GetID(),
+		pullRequestNumber: pullRequest.GetNumber(),
+		headSHA:           pullRequest.GetHead().GetSHA(),
+	}
+	return s.checkPullRequest(ctx, target)
+}
+
+// CheckMergeGroup publishes the check run of the head commit of the merge group, covering the commits between the
+// base and the head of the merge group
+func (s *service) CheckMergeGroup(ctx context.Context, event *MergeGroupEvent) error {
+	f := logrus.Fields{
+		"functionName":   "check_runs.CheckMergeGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repository":     event.Repo.GetFullName(),
+		"headRef":        event.MergeGroup.HeadRef,
+		"action":         event.Action,
+	}
+
+	if event.Action != mergeGroupActionChecksRequested {
+		log.WithFields(f).Debug("merge group action doesn't request checks, skipping")
+		return nil
+	}
+
+	repoModel, err := s.repository(ctx, event.Repo.GetID())
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	client, err := s.newClient(event.Installation.GetID())
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the github client of the installation")
+		return err
+	}
+	target := &checkTarget{
+		claGroupID:      repoModel.RepositoryProjectID,
+		enforcementMode: repositories.EnforcementMode(repoModel.EnforcementMode),
+		owner:           event.Repo.GetOwner().GetLogin(),
+		repositoryName:  event.Repo.GetName(),
+		repositoryID:    event.Repo.GetID(),
+		installationID:  event.Installation.GetID(),
+		headSHA:         event.MergeGroup.HeadSHA,
+	}
+
+	comparison, _, err := client.Repositories.CompareCommits(ctx, target.owner, target.repositoryName, event.MergeGroup.BaseSHA, event.MergeGroup.HeadSHA)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to compare the merge group commits")
+		return err
+	}
+
+	_, err = s.publish(ctx, client, target, comparison.Commits)
+	return err
+}
+
+// RecheckCLAGroup publishes the check runs of the tracked pull requests of the CLA Group again, the pull requests
+// which were closed in the meantime are no longer tracked. The signatures don't matter to the repositories in the dco
+// enforcement mode, their pull requests are skipped.
+func (s *service) RecheckCLAGroup(ctx context.Context, claGroupID string) error {
+	f := logrus.Fields{
+		"functionName":   "check_runs.RecheckCLAGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	checks, err := s.repo.GetChecksByCLAGroup(ctx, claGroupID)
+	if err != nil {
+		return err
+	}
+	log.WithFields(f).Debugf("checking %d pull requests again", len(checks))
+	// the exemptions of the CLA Group are loaded once for all the pull requests
+	ctx = coverage.WithExemptionCache(ctx)
+
+	for _, check := range checks {
+		repoModel, repoErr := s.repository(ctx, check.RepositoryID)
+		if repoErr != nil {
+			log.WithFields(f).WithError(repoErr).Warnf("unable to load the repository of check: %s", check.CheckID)
+			continue
+		}
+		if repoModel == nil {
+			log.WithFields(f).Debugf("the repository of check %s is no longer enabled, no longer tracking its check", check.CheckID)
+			if err := s.repo.DeleteCheck(ctx, check.CheckID); err != nil {
+				log.WithFields(f).WithError(err).Warnf("unable to delete the check: %s", check.CheckID)
+			}
+			continue
+		}
+		enforcementMode := repositories.EnforcementMode(repoModel.EnforcementMode)
+		if enforcementMode == repositories.EnforcementModeDCO {
+			continue
+		}
+
+		client, clientErr := s.newClient(check.InstallationID)
+		if clientErr != nil {
+			log.WithFields(f).WithError(clientErr).Warnf("unable to create the github client of installation: %d", check.InstallationID)
+			continue
+		}
+
+		pullRequest, resp, prErr := client.PullRequests.Get(ctx, check.Owner, check.RepositoryName, check.PullRequestNumber)
+		if prErr != nil {
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				log.WithFields(f).Debugf("pull request %s no longer exists, no longer tracking its check", check.CheckID)
+				if err := s.repo.DeleteCheck(ctx, check.CheckID); err != nil {
+					log.WithFields(f).WithError(err).Warnf("unable to delete the check: %s", check.CheckID)
+				}
+				continue
+			}
+			log.WithFields(f).WithError(prErr).Warnf("unable to load the pull request of check: %s", check.CheckID)
+			continue
+		}
+		if pullRequest.GetState() != "open" {
+			log.WithFields(f).Debugf("pull request %s is closed, no longer tracking its check", check.CheckID)
+			if err := s.repo.DeleteCheck(ctx, check.CheckID); err != nil {
+				log.WithFields(f).WithError(err).Warnf("unable to delete the check: %s", check.CheckID)
+			}
+			continue
+		}
+
+		target := &checkTarget{
+			claGroupID:        claGroupID,
+			enforcementMode:   enforcementMode,
+			owner:             check.Owner,
+			repositoryName:    check.RepositoryName,
+			repositoryID:      check.RepositoryID,
+			installationID:    check.InstallationID,
+			pullRequestNumber: check.PullRequestNumber,
+			headSHA:           pullRequest.GetHead().GetSHA(),
+		}
+		if err := s.checkPullRequestWithClient(ctx, client, target); err != nil {
+			log.WithFields(f).WithError(err).Warnf("unable to check the pull request again: %s", check.CheckID)
+		}
+	}
+
+	return nil
+}
+
+// repository returns the enabled repository, nil when the repository isn't enabled in EasyCLA
+func (s *service) repository(ctx context.Context, repositoryID int64) (*models.GithubRepository, error) {
+	repoModel, err := s.repositoriesRepo.GetRepositoryByGithubID(ctx, strconv.FormatInt(repositoryID, 10), true)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+			log.WithField("repositoryID", repositoryID).Debug("the repository isn't enabled in EasyCLA, skipping")
+			return nil, nil
+		}
+		return nil, err
+	}
+	return repoModel, nil
+}
+
+func (s *service) checkPullRequest(ctx context.Context, target *checkTarget) error {
+	client, err := s.newClient(target.installationID)
+	if err != nil {
+		log.WithField("installationID", target.installationID).WithError(err).Warn("unable to create the github client of the installation")
+		return err
+	}
+	return s.checkPullRequestWithClient(ctx, client, target)
+}
+
+// checkPullRequestWithClient publishes the check run of the pull request and tracks the pull request
+func (s *service) checkPullRequestWithClient(ctx context.Context, client *github.Client, target *checkTarget) error {
+	f := logrus.Fields{
+		"functionName":   "check_runs.checkPullRequestWithClient",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repository":     target.owner + "/" + target.repositoryName,
+		"pullRequest":    target.pullRequestNumber,
+		"headSHA":        target.headSHA,
+	}
+
+	var commits []*github.RepositoryCommit
+	opts := &github.ListOptions{PerPage: commitsPageSize}
+	for {
+		page, resp, err := client.PullRequests.ListCommits(ctx, target.owner, target.repositoryName, target.pullRequestNumber, opts)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to list the pull request commits")
+			return err
+		}
+		commits = append(commits, page...)
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	checkRun, err := s.publish(ctx, client, target, commits)
+	if err != nil {
+		return err
+	}
+
+	_, now := utils.CurrentTime()
+	return s.repo.SaveCheck(ctx, &DBPullRequestCheck{
+		CheckID:           checkID(target.repositoryID, target.pullRequestNumber),
+		ClaGroupID:        target.claGroupID,
+		RepositoryID:      target.repositoryID,
+		Owner:             target.owner,
+		RepositoryName:    target.repositoryName,
+		InstallationID:    target.installationID,
+		PullRequestNumber: target.pullRequestNumber,
+		HeadSHA:           target.headSHA,
+		CheckRunID:        checkRun.GetID(),
+		Conclusion:        checkRun.GetConclusion(),
+		DateModified:      now,
+	})
+}
+
+// publish evaluates the commit authors and creates the check run on the head commit of the target
+func (s *service) publish(ctx context.Context, client *github.Client, target *checkTarget, commits []*github.RepositoryCommit) (*github.CheckRun, error) {
+	f := logrus.Fields{
+		"functionName":   "check_runs.publish",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"repository":     target.owner + "/" + target.repositoryName,
+		"headSHA":        target.headSHA,
+	}
+
+	results, err := s.evaluateCommits(ctx, target, commits)
+	if err != nil {
+		return nil, err
+	}
+	opts := s.checkRunOptions(target, results)
+
+	checkRun, _, err := client.Checks.CreateCheckRun(ctx, target.owner, target.repositoryName, opts)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to create the check run")
+		return nil, err
+	}
+	log.WithFields(f).Debugf("created check run %d with conclusion %s", checkRun.GetID(), checkRun.GetConclusion())
+	return checkRun, nil
+}
+
+// evaluateCommits returns the coverage of the author and the sign-off of each commit as far as the enforcement mode
+// needs them, each author is only evaluated once
+func (s *service) evaluateCommits(ctx context.Context, target *checkTarget, commits []*github.RepositoryCommit) ([]*commitResult, error) {
+	ctx = coverage.WithExemptionCache(ctx)
+	verdicts := make(map[string]*coverage.Verdict)
+	var results []*commitResult
+	for _, commit := range commits {
+		login := commit.GetAuthor().GetLogin()
+		email := commit.GetCommit().GetAuthor().GetEmail()
+		result := &commitResult{sha: commit.GetSHA(), author: commitAuthor(commit)}
+		results = append(results, result)
+
+		if target.enforcementMode != repositories.EnforcementModeCLA {
+			result.signedOff, result.dcoReason = isSignedOff(commit)
+			if result.signedOff || target.enforcementMode == repositories.EnforcementModeDCO {
+				continue
+			}
+		}
+
+		if login == "" && email == "" {
+			result.reason = "the commit author is unknown"
+			continue
+		}
+		key := strings.ToLower(login + "#" + email)
+		verdict, ok := verdicts[key]
+		if !ok {
+			var err error
+			verdict, err = s.evaluator.Evaluate(ctx, target.claGroupID, &coverage.Identity{Email: email, GitHubUserID: commit.GetAuthor().GetID(), GitHubUsername: login})
+			if err != nil {
+				return nil, err
+			}
+			verdicts[key] = verdict
+		}
+		result.covered = verdict.Covered
+		result.reason = verdict.Reason
+		if verdict.Rule == coverage.RuleExemption {
+			result.exemption = fmt.Sprintf("%s exemption `%s`", verdict.ExemptionType, verdict.ExemptionPattern)
+		}
+	}
+	return results, nil
+}
+
+// checkRunTexts are the texts of the check run of an enforcement mode
+type checkRunTexts struct {
+	successTitle      string
+	successSummary    string
+	failureTitle      string
+	failureSummary    string
+	annotationTitle   string
+	includeSignURL    bool
+	includeSignOffFix bool
+}
+
+var checkRunTextsByMode = map[string]checkRunTexts{
+	repositories.EnforcementModeCLA: {
+		successTitle:    "All committers are covered by a signed CLA",
+		successSummary:  "The authors of the %d commits are covered by a signed CLA.",
+		failureTitle:    "Missing CLA: %s",
+		failureSummary:  "The following commit authors are not covered by a signed CLA:",
+		annotationTitle: "Commit %s is not covered by a signed CLA",
+		includeSignURL:  true,
+	},
+	repositories.EnforcementModeDCO: {
+		successTitle:      "All commits are signed off",
+		successSummary:    "The %d commits are signed off by their authors.",
+		failureTitle:      "Missing DCO sign-off: %s",
+		failureSummary:    "The commits of the following authors are not signed off:",
+		annotationTitle:   "Commit %s is not signed off",
+		includeSignOffFix: true,
+	},
+	repositories.EnforcementModeCLAOrDCO: {
+		successTitle:      "All commits are covered by a signed CLA or signed off",
+		successSummary:    "The %d commits are covered by a signed CLA or signed off by their authors.",
+		failureTitle:      "Missing CLA or DCO sign-off: %s",
+		failureSummary:    "The commits of the following authors are neither covered by a signed CLA nor signed off:",
+		annotationTitle:   "Commit %s is neither covered by a signed CLA nor signed off",
+		includeSignURL:    true,
+		includeSignOffFix: true,
+	},
+}
+
+// checkRunName returns the name of the check run of the enforcement mode, the name of the status check the branch
+// protection of the repository requires
+func checkRunName(mode string) string {
+	if mode == repositories.EnforcementModeDCO {
+		return repositories.DCOStatusCheckName
+	}
+	return CheckRunName
+}
+
+// checkRunOptions returns the completed check run of the commit results, with an annotation per commit which doesn't
+// meet the enforcement mode of the repository
+func (s *service) checkRunOptions(target *checkTarget, results []*commitResult) github.CreateCheckRunOptions {
+	texts, ok := checkRunTextsByMode[target.enforcementMode]
+	if !ok {
+		texts = checkRunTextsByMode[repositories.EnforcementModeCLA]
+	}
+
+	var missingAuthors, exemptedAuthors []string
+	seen := make(map[string]bool)
+	seenExempted := make(map[string]bool)
+	var annotations []*github.CheckRunAnnotation
+	for _, result := range results {
+		if result.passed(target.enforcementMode) {
+			if result.exemption != "" && !seenExempted[result.author] {
+				seenExempted[result.author] = true
+				exemptedAuthors = append(exemptedAuthors, fmt.Sprintf("%s (%s)", result.author, result.exemption))
+			}
+			continue
+		}
+		if !seen[result.author] {
+			seen[result.author] = true
+			missingAuthors = append(missingAuthors, result.author)
+		}
+		if len(annotations) < maxAnnotations {
+			annotations = append(annotations, &github.CheckRunAnnotation{
+				Path:            github.String(annotationPath),
+				StartLine:       github.Int(1),
+				EndLine:         github.Int(1),
+				AnnotationLevel: github.String(annotationLevelFailure),
+				Title:           github.String(fmt.Sprintf(texts.annotationTitle, shortSHA(result.sha))),
+				Message:         github.String(fmt.Sprintf("%s: %s", result.author, result.failure(target.enforcementMode))),
+				RawDetails:      github.String(result.sha),
+			})
+		}
+	}
+	sort.Strings(missingAuthors)
+	sort.Strings(exemptedAuthors)
+
+	var signURL string
+	if texts.includeSignURL {
+		signURL = s.signURL(target)
+	}
+	opts := github.CreateCheckRunOptions{
+		Name:        checkRunName(target.enforcementMode),
+		HeadSHA:     target.headSHA,
+		Status:      github.String(checkRunStatusCompleted),
+		Conclusion:  github.String(checkRunConclusionSuccess),
+		CompletedAt: &github.Timestamp{Time: s.now()},
+		Output: &github.CheckRunOutput{
+			Title:   github.String(texts.successTitle),
+			Summary: github.String(fmt.Sprintf(texts.successSummary, len(results))),
+		},
+	}
+	if signURL != "" {
+		opts.DetailsURL = github.String(signURL)
+	} else if texts.includeSignOffFix {
+		opts.DetailsURL = github.String(dcoURL)
+	}
+	if len(missingAuthors) == 0 {
+		opts.Output.Summary = github.String(*opts.Output.Summary + exemptedSummary(exemptedAuthors))
+		return opts
+	}
+
+	var summary strings.Builder
+	summary.WriteString(texts.failureSummary + "\n\n")
+	for _, author := range missingAuthors {
+		summary.WriteString(fmt.Sprintf("- %s\n", author))
+	}
+	if signURL != "" {
+		summary.WriteString(fmt.Sprintf("\n[Sign the CLA](%s), the check runs again once the CLA is signed.\n", signURL))
+	}
+	if texts.includeSignOffFix {
+		summary.WriteString("\n" + dcoRemediation(len(results)))
+	}
+	summary.WriteString(exemptedSummary(exemptedAuthors))
+
+	opts.Output.Title = github.String(fmt.Sprintf(texts.failureTitle, strings.Join(missingAuthors, ", ")))
+	opts.Output.Summary = github.String(summary.String())
+	opts.Output.Annotations = annotations
+	// action_required needs the details URL the contributors follow to sign
+	if signURL != "" {
+		opts.Conclusion = github.String(checkRunConclusionActionRequired)
+	} else {
+		opts.Conclusion = github.String(checkRunConclusionFailure)
+	}
+	return opts
+}
+
+// exemptedSummary returns the summary section listing the commit authors covered by an exemption of the CLA Group
+// instead of a signature, empty when no author is exempted
+func exemptedSummary(exemptedAuthors []string) string {
+	if len(exemptedAuthors) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nThe following commit authors are exempted from signing by the CLA Group:\n\n")
+	for _, author := range exemptedAuthors {
+		b.WriteString(fmt.Sprintf("- %s\n", author))
+	}
+	return b.String()
+}
+
+// signURL returns the URL the contributors follow to sign the CLA of a pull request, empty for the merge groups
+func (s *service) signURL(target *checkTarget) string {
+	if s.apiURL == "" || target.pullRequestNumber == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s/v2/repository-provider/github/sign/%d/%d/%d", s.apiURL, target.installationID, target.repositoryID, target.pullRequestNumber)
+}
+
+// commitAuthor returns the display name of the commit author, the GitHub login when the commit is linked to a user
+func commitAuthor(commit *github.RepositoryCommit) string {
+	if login := commit.GetAuthor().GetLogin(); login != "" {
+		return "@" + login
+	}
+	author := commit.GetCommit().GetAuthor()
+	if author.GetName() != "" && author.GetEmail() != "" {
+		return fmt.Sprintf("%s <%s>", author.GetName(), author.GetEmail())
+	}
+	if author.GetEmail() != "" {
+		return author.GetEmail()
+	}
+	return "unknown author"
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
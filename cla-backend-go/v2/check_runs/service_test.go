@@ -0,0 +1,153 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package check_runs
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	claGithub "github.com/communitybridge/easycla/cla-backend-go/github"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEvaluator struct {
+	coverage.Evaluator
+	covered map[string]bool
+}
+
+func (e *fakeEvaluator) Evaluate(ctx context.Context, claGroupID string, identity *coverage.Identity) (*coverage.Verdict, error) {
+	if e.covered[identity.Email] {
+		return &coverage.Verdict{Covered: true, Rule: coverage.RuleICLA}, nil
+	}
+	return &coverage.Verdict{Reason: coverage.ReasonNoSignature}, nil
+}
+
+type fakeRepositories struct {
+	repositories.Repository
+}
+
+func (r *fakeRepositories) GetRepositoryByGithubID(ctx context.Context, externalID string, enabled bool) (*models.GithubRepository, error) {
+	if externalID != "55" {
+		return nil, repositories.ErrGithubRepositoryNotFound
+	}
+	return &models.GithubRepository{RepositoryProjectID: "cla-group-1"}, nil
+}
+
+type fakeRepo struct {
+	checks map[string]*DBPullRequestCheck
+}
+
+func (r *fakeRepo) SaveCheck(ctx context.Context, check *DBPullRequestCheck) error {
+	r.checks[check.CheckID] = check
+	return nil
+}
+
+func (r *fakeRepo) DeleteCheck(ctx context.Context, checkID string) error {
+	delete(r.checks, checkID)
+	return nil
+}
+
+func (r *fakeRepo) GetChecksByCLAGroup(ctx context.Context, claGroupID string) ([]*DBPullRequestCheck, error) {
+	var checks []*DBPullRequestCheck
+	for _, check := range r.checks {
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+func TestCheckPullRequest(t *testing.T) {
+	server, err := claGithub.NewFixtureServer("testdata/pull_request.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer server.Close()
+
+	repo := &fakeRepo{checks: make(map[string]*DBPullRequestCheck)}
+	evaluator := &fakeEvaluator{covered: map[string]bool{"jane@example.com": true}}
+	s := NewService(repo, &fakeRepositories{}, evaluator, "https://api.example.org/", func(installationID int64) (*github.Client, error) {
+		assert.Equal(t, int64(99), installationID)
+		return server.GitHubClient(), nil
+	})
+
+	event := &github.PullRequestEvent{
+		Action:       github.String("opened"),
+		Repo:         &github.Repository{ID: github.Int64(55), Name: github.String("widgets"), Owner: &github.User{Login: github.String("example-org")}},
+		Installation: &github.Installation{ID: github.Int64(99)},
+		PullRequest:  &github.PullRequest{Number: github.Int(7), Head: &github.PullRequestBranch{SHA: github.String("3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d")}},
+	}
+	assert.NoError(t, s.CheckPullRequest(context.Background(), event))
+
+	requests := server.Requests("POST", "/repos/example-org/widgets/check-runs")
+	if assert.Len(t, requests, 1) {
+		var opts github.CreateCheckRunOptions
+		assert.NoError(t, json.Unmarshal(requests[0].Body, &opts))
+		assert.Equal(t, CheckRunName, opts.Name)
+		assert.Equal(t, "3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c2d", opts.HeadSHA)
+		assert.Equal(t, "action_required", opts.GetConclusion())
+		assert.Equal(t, "https://api.example.org/v2/repository-provider/github/sign/99/55/7", opts.GetDetailsURL())
+		assert.Equal(t, "Missing CLA: Joe Bloggs <joe@example.org>", opts.Output.GetTitle())
+		if assert.Len(t, opts.Output.Annotations, 1) {
+			assert.Equal(t, "2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d7e8f9a0b1c", opts.Output.Annotations[0].GetRawDetails())
+		}
+	}
+	if assert.Contains(t, repo.checks, "55#7") {
+		assert.Equal(t, "cla-group-1", repo.checks["55#7"].ClaGroupID)
+		assert.Equal(t, int64(4242), repo.checks["55#7"].CheckRunID)
+	}
+
+	// the recheck publishes the open pull request again and stops tracking the closed one
+	repo.checks["55#8"] = &DBPullRequestCheck{CheckID: "55#8", ClaGroupID: "cla-group-1", Owner: "example-org", RepositoryName: "widgets", RepositoryID: 55, InstallationID: 99, PullRequestNumber: 8}
+	assert.NoError(t, s.RecheckCLAGroup(context.Background(), "cla-group-1"))
+	assert.Len(t, server.Requests("POST", "/repos/example-org/widgets/check-runs"), 2)
+	assert.NotContains(t, repo.checks, "55#8")
+
+	event.Action = github.String("closed")
+	assert.NoError(t, s.CheckPullRequest(context.Background(), event))
+	assert.Empty(t, repo.checks)
+
+	// the repositories which aren't enabled are skipped
+	event.Action = github.String("synchronize")
+	event.Repo.ID = github.Int64(56)
+	assert.NoError(t, s.CheckPullRequest(context.Background(), event))
+	assert.Len(t, server.Requests("POST", "/repos/example-org/widgets/check-runs"), 2)
+}
+
+func TestIsSignedOff(t *testing.T) {
+	commit := func(email, message string, parents int) *github.RepositoryCommit {
+		c := &github.RepositoryCommit{Commit: &github.Commit{
+			Author:  &github.CommitAuthor{Email: github.String(email)},
+			Message: github.String(message),
+		}}
+		for i := 0; i < parents; i++ {
+			c.Parents = append(c.Parents, &github.Commit{})
+		}
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		commit *github.RepositoryCommit
+		signed bool
+	}{
+		{"matching trailer", commit("jane@example.org", "Fix\n\nSigned-off-by: Jane Doe <jane@example.org>", 1), true},
+		{"email is case insensitive", commit("Jane@Example.org", "Fix\n\nsigned-off-by: Jane Doe <jane@example.org>", 1), true},
+		{"one of several trailers", commit("jane@example.org", "Fix\n\nSigned-off-by: Joe <joe@example.org>\nSigned-off-by: Jane <jane@example.org>", 1), true},
+		{"no trailer", commit("jane@example.org", "Fix", 1), false},
+		{"trailer of another author", commit("jane@example.org", "Fix\n\nSigned-off-by: Joe <joe@example.org>", 1), false},
+		{"trailer in the subject line", commit("jane@example.org", "Signed-off-by Jane <jane@example.org>", 1), false},
+		{"merge commit", commit("jane@example.org", "Merge branch 'main'", 2), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, reason := isSignedOff(tt.commit)
+			assert.Equal(t, tt.signed, signed)
+			assert.Equal(t, tt.signed, reason == "")
+		})
+	}
+}
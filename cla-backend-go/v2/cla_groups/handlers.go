@@ -11,6 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/aws"
 
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
 
 	"github.com/sirupsen/logrus"
@@ -495,6 +496,161 @@ func Configure(api *operations.EasyclaAPI, service Service, v1ProjectService v1P
 
 		return foundation.NewListFoundationClaGroupsOK().WithXRequestID(reqID).WithPayload(result)
 	})
+
+	api.ClaGroupListClaGroupExemptionsHandler = cla_group.ListClaGroupExemptionsHandlerFunc(func(params cla_group.ListClaGroupExemptionsParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "ClaGroupListClaGroupExemptionsHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"authUsername":   params.XUSERNAME,
+			"authEmail":      params.XEMAIL,
+		}
+
+		claGroupModel, err := v1ProjectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA group by ID")
+			if isCLAGroupNotFound(err) {
+				return cla_group.NewListClaGroupExemptionsNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return cla_group.NewListClaGroupExemptionsInternalServerError().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseInternalServerErrorWithError(reqID, fmt.Sprintf("unable to lookup CLA Group by ID: %s", params.ClaGroupID), err))
+		}
+
+		// Check permissions
+		if !isUserHaveAccessToCLAProject(ctx, authUser, claGroupModel.FoundationSFID, projectClaGroupsRepo) {
+			msg := fmt.Sprintf("user %s does not have access to list the exemptions of the CLA Group with project scope of: %s", authUser.UserName, claGroupModel.FoundationSFID)
+			log.WithFields(f).Warn(msg)
+			return cla_group.NewListClaGroupExemptionsForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		return cla_group.NewListClaGroupExemptionsOK().WithXRequestID(reqID).WithPayload(toExemptionModels(claGroupModel.Exemptions))
+	})
+
+	api.ClaGroupAddClaGroupExemptionHandler = cla_group.AddClaGroupExemptionHandlerFunc(func(params cla_group.AddClaGroupExemptionParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "ClaGroupAddClaGroupExemptionHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"authUsername":   params.XUSERNAME,
+			"authEmail":      params.XEMAIL,
+		}
+
+		claGroupModel, err := v1ProjectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA group by ID")
+			if isCLAGroupNotFound(err) {
+				return cla_group.NewAddClaGroupExemptionNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return cla_group.NewAddClaGroupExemptionInternalServerError().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseInternalServerErrorWithError(reqID, fmt.Sprintf("unable to lookup CLA Group by ID: %s", params.ClaGroupID), err))
+		}
+
+		// Check permissions
+		if !isUserHaveAccessToCLAProject(ctx, authUser, claGroupModel.FoundationSFID, projectClaGroupsRepo) {
+			msg := fmt.Sprintf("user %s does not have access to add an exemption to the CLA Group with project scope of: %s", authUser.UserName, claGroupModel.FoundationSFID)
+			log.WithFields(f).Warn(msg)
+			return cla_group.NewAddClaGroupExemptionForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		exemption, err := service.AddCLAGroupExemption(ctx, claGroupModel, params.Body, authUser.UserName)
+		if err != nil {
+			if errors.Is(err, coverage.ErrInvalidExemption) {
+				return cla_group.NewAddClaGroupExemptionBadRequest().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, "invalid exemption", err))
+			}
+			if errors.Is(err, ErrExemptionAlreadyExists) {
+				return cla_group.NewAddClaGroupExemptionConflict().WithXRequestID(reqID).WithPayload(&models.ErrorResponse{
+					Code:       utils.String409,
+					Message:    fmt.Sprintf("EasyCLA - 409 Conflict - %s", err.Error()),
+					XRequestID: reqID,
+				})
+			}
+			return cla_group.NewAddClaGroupExemptionInternalServerError().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseInternalServerErrorWithError(reqID, fmt.Sprintf("unable to add the exemption to CLA Group: %s", params.ClaGroupID), err))
+		}
+
+		eventsService.LogEvent(&events.LogEventArgs{
+			EventType:     events.CLAGroupExemptionAdded,
+			ClaGroupModel: claGroupModel,
+			LfUsername:    authUser.UserName,
+			EventData: &events.CLAGroupExemptionAddedEventData{
+				ExemptionID:   exemption.ExemptionID,
+				ExemptionType: exemption.Type,
+				Pattern:       exemption.Pattern,
+			},
+		})
+
+		return cla_group.NewAddClaGroupExemptionOK().WithXRequestID(reqID).WithPayload(exemption)
+	})
+
+	api.ClaGroupDeleteClaGroupExemptionHandler = cla_group.DeleteClaGroupExemptionHandlerFunc(func(params cla_group.DeleteClaGroupExemptionParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "ClaGroupDeleteClaGroupExemptionHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"exemptionID":    params.ExemptionID,
+			"authUsername":   params.XUSERNAME,
+			"authEmail":      params.XEMAIL,
+		}
+
+		claGroupModel, err := v1ProjectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA group by ID")
+			if isCLAGroupNotFound(err) {
+				return cla_group.NewDeleteClaGroupExemptionNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return cla_group.NewDeleteClaGroupExemptionInternalServerError().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseInternalServerErrorWithError(reqID, fmt.Sprintf("unable to lookup CLA Group by ID: %s", params.ClaGroupID), err))
+		}
+
+		// Check permissions
+		if !isUserHaveAccessToCLAProject(ctx, authUser, claGroupModel.FoundationSFID, projectClaGroupsRepo) {
+			msg := fmt.Sprintf("user %s does not have access to remove an exemption of the CLA Group with project scope of: %s", authUser.UserName, claGroupModel.FoundationSFID)
+			log.WithFields(f).Warn(msg)
+			return cla_group.NewDeleteClaGroupExemptionForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		exemption, err := service.DeleteCLAGroupExemption(ctx, claGroupModel, params.ExemptionID)
+		if err != nil {
+			if errors.Is(err, v1Project.ErrExemptionNotFound) {
+				return cla_group.NewDeleteClaGroupExemptionNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, "exemption not found", err))
+			}
+			return cla_group.NewDeleteClaGroupExemptionInternalServerError().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseInternalServerErrorWithError(reqID, fmt.Sprintf("unable to remove the exemption of CLA Group: %s", params.ClaGroupID), err))
+		}
+
+		eventsService.LogEvent(&events.LogEventArgs{
+			EventType:     events.CLAGroupExemptionDeleted,
+			ClaGroupModel: claGroupModel,
+			LfUsername:    authUser.UserName,
+			EventData: &events.CLAGroupExemptionDeletedEventData{
+				ExemptionID:   exemption.ExemptionID,
+				ExemptionType: exemption.Type,
+				Pattern:       exemption.Pattern,
+			},
+		})
+
+		return cla_group.NewDeleteClaGroupExemptionNoContent().WithXRequestID(reqID)
+	})
+}
+
+// isCLAGroupNotFound returns true if the error is the CLA Group not found error of the CLA Group lookup
+func isCLAGroupNotFound(err error) bool {
+	var notFound *utils.CLAGroupNotFound
+	return errors.As(err, &notFound) || errors.Is(err, v1Project.ErrProjectDoesNotExist)
 }
 
 // isUserHaveAccessToCLAProject is a helper function to determine if the user has access to the specified project
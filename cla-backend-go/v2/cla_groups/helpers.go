@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
@@ -594,3 +595,23 @@ func isFoundationIDInList(foundationSFID string, projectsSFIDs []string) bool {
 	}
 	return false
 }
+
+// toExemptionModels converts the exemptions of the CLA Group to the response models
+func toExemptionModels(exemptions []*v1Models.ClaGroupExemption) []*models.ClaGroupExemption {
+	response := []*models.ClaGroupExemption{}
+	for _, exemption := range exemptions {
+		response = append(response, toExemptionModel(exemption))
+	}
+	return response
+}
+
+func toExemptionModel(exemption *v1Models.ClaGroupExemption) *models.ClaGroupExemption {
+	return &models.ClaGroupExemption{
+		ExemptionID: exemption.ExemptionID,
+		Type:        exemption.Type,
+		Pattern:     exemption.Pattern,
+		Description: exemption.Description,
+		CreatedBy:   exemption.CreatedBy,
+		DateCreated: exemption.DateCreated,
+	}
+}
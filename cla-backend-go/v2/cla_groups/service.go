@@ -17,6 +17,7 @@ import (
 
 	"github.com/LF-Engineering/lfx-kit/auth"
 	v1ClaManager "github.com/communitybridge/easycla/cla-backend-go/cla_manager"
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
 	"github.com/communitybridge/easycla/cla-backend-go/events"
 	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
@@ -65,8 +66,13 @@ type Service interface {
 	EnableCLAService(ctx context.Context, projectSFIDList []string) error
 	DisableCLAService(ctx context.Context, projectSFIDList []string) error
 	ValidateCLAGroup(ctx context.Context, input *models.ClaGroupValidationRequest) (bool, []string)
+	AddCLAGroupExemption(ctx context.Context, claGroupModel *v1Models.ClaGroup, input *models.ClaGroupExemptionInput, createdBy string) (*models.ClaGroupExemption, error)
+	DeleteCLAGroupExemption(ctx context.Context, claGroupModel *v1Models.ClaGroup, exemptionID string) (*models.ClaGroupExemption, error)
 }
 
+// ErrExemptionAlreadyExists is returned when the CLA Group already has an exemption with the same type and pattern
+var ErrExemptionAlreadyExists = errors.New("the CLA Group already has an exemption with the same type and pattern")
+
 // NewService returns instance of CLA group service
 func NewService(projectService v1Project.Service, templateService v1Template.Service, projectsClaGroupsRepo projects_cla_groups.Repository, claMangerRequests v1ClaManager.IService, signatureService signatureService.SignatureService, metricsRepo metrics.Repository, gerritService gerrits.Service, repositoriesService repositories.Service, eventsService events.Service) Service {
 	return &service{
@@ -983,6 +989,72 @@ func (s *service) ValidateCLAGroup(ctx context.Context, input *models.ClaGroupVa
 	return valid, validationErrors
 }
 
+// AddCLAGroupExemption exempts the contributors matching the pattern of the input from signing the CLA Group, the
+// pattern is validated against the exemption type
+func (s *service) AddCLAGroupExemption(ctx context.Context, claGroupModel *v1Models.ClaGroup, input *models.ClaGroupExemptionInput, createdBy string) (*models.ClaGroupExemption, error) {
+	f := logrus.Fields{
+		"functionName":   "AddCLAGroupExemption",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupModel.ProjectID,
+		"exemptionType":  aws.StringValue(input.Type),
+		"pattern":        aws.StringValue(input.Pattern),
+		"createdBy":      createdBy,
+	}
+
+	pattern, err := coverage.NormalizeExemption(aws.StringValue(input.Type), aws.StringValue(input.Pattern))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("invalid exemption")
+		return nil, err
+	}
+	for _, exemption := range claGroupModel.Exemptions {
+		if exemption != nil && exemption.Type == aws.StringValue(input.Type) && strings.EqualFold(exemption.Pattern, pattern) {
+			log.WithFields(f).Warnf("the CLA Group already has the exemption: %s", exemption.ExemptionID)
+			return nil, ErrExemptionAlreadyExists
+		}
+	}
+
+	log.WithFields(f).Debug("adding CLA Group exemption")
+	exemption, err := s.v1ProjectService.AddCLAGroupExemption(ctx, claGroupModel.ProjectID, &v1Models.ClaGroupExemption{
+		Type:        aws.StringValue(input.Type),
+		Pattern:     pattern,
+		Description: strings.TrimSpace(input.Description),
+		CreatedBy:   createdBy,
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to add the CLA Group exemption")
+		return nil, err
+	}
+	return toExemptionModel(exemption), nil
+}
+
+// DeleteCLAGroupExemption removes the exemption of the CLA Group and returns it
+func (s *service) DeleteCLAGroupExemption(ctx context.Context, claGroupModel *v1Models.ClaGroup, exemptionID string) (*models.ClaGroupExemption, error) {
+	f := logrus.Fields{
+		"functionName":   "DeleteCLAGroupExemption",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupModel.ProjectID,
+		"exemptionID":    exemptionID,
+	}
+
+	var deleted *models.ClaGroupExemption
+	for _, exemption := range claGroupModel.Exemptions {
+		if exemption != nil && exemption.ExemptionID == exemptionID {
+			deleted = toExemptionModel(exemption)
+			break
+		}
+	}
+	if deleted == nil {
+		return nil, v1Project.ErrExemptionNotFound
+	}
+
+	log.WithFields(f).Debugf("removing the %s exemption: %s", deleted.Type, deleted.Pattern)
+	if err := s.v1ProjectService.DeleteCLAGroupExemption(ctx, claGroupModel.ProjectID, exemptionID); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to remove the CLA Group exemption")
+		return nil, err
+	}
+	return deleted, nil
+}
+
 // Find . . .
 func Find(slice []v1Models.ClaGroup, val string) (int, bool) {
 	for i, item := range slice {
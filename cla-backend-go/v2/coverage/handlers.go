@@ -0,0 +1,277 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	v1Coverage "github.com/communitybridge/easycla/cla-backend-go/coverage"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/coverage"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	v1Project "github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrCLAGroupOrRepositoryRequired = errors.New("exactly one of claGroupID or repositoryName is required")
+	ErrContributorRequired          = errors.New("at least one of githubUsername, email or lfUsername is required")
+	ErrTooManyAuditAuthors          = fmt.Errorf("at most %d distinct commit authors are audited per request, use the coverage-audit command for larger histories", maxAuditAuthors)
+)
+
+// maxAuditAuthors is the maximum number of distinct commit authors of an audit request, each author is evaluated
+// with several sequential queries and the request has to complete within the 29 seconds of the API gateway
+const maxAuditAuthors = 100
+
+// Configure setup the coverage API handlers
+func Configure(api *operations.EasyclaAPI, evaluator v1Coverage.Evaluator, repositoriesRepo repositories.Repository, projectService v1Project.Service) {
+	api.CoverageExplainCoverageHandler = coverage.ExplainCoverageHandlerFunc(func(params coverage.ExplainCoverageParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		claGroupID := strings.TrimSpace(swag.StringValue(params.ClaGroupID))
+		repositoryName := strings.TrimSpace(swag.StringValue(params.RepositoryName))
+		identity := &v1Coverage.Identity{
+			Email:          strings.TrimSpace(swag.StringValue(params.Email)),
+			GitHubUsername: strings.TrimSpace(swag.StringValue(params.GithubUsername)),
+			LFUsername:     strings.TrimSpace(swag.StringValue(params.LfUsername)),
+		}
+		f := logrus.Fields{
+			"functionName":   "CoverageExplainCoverageHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     claGroupID,
+			"repositoryName": repositoryName,
+			"email":          identity.Email,
+			"githubUsername": identity.GitHubUsername,
+			"lfUsername":     identity.LFUsername,
+			"authUserName":   user.UserName,
+		}
+
+		if (claGroupID == "") == (repositoryName == "") {
+			return coverage.NewExplainCoverageBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, ErrCLAGroupOrRepositoryRequired))
+		}
+		if identity.Email == "" && identity.GitHubUsername == "" && identity.LFUsername == "" {
+			return coverage.NewExplainCoverageBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, ErrContributorRequired))
+		}
+
+		if repositoryName != "" {
+			repository, err := repositoriesRepo.GetRepositoryByName(ctx, repositoryName)
+			if err != nil {
+				log.WithFields(f).WithError(err).Warn("problem loading the repository by name")
+				if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+					return coverage.NewExplainCoverageNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "repository not found", err))
+				}
+				return coverage.NewExplainCoverageInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			claGroupID = repository.RepositoryProjectID
+			f["claGroupID"] = claGroupID
+		}
+
+		claGroup, err := projectService.GetCLAGroupByID(ctx, claGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA Group by ID")
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return coverage.NewExplainCoverageNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return coverage.NewExplainCoverageInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorized(user, claGroup) {
+			return coverage.NewExplainCoverageForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Explain Coverage", claGroup))
+		}
+
+		explanation, err := evaluator.Explain(ctx, claGroup.ProjectID, identity)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem explaining the coverage of the contributor")
+			return coverage.NewExplainCoverageInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		response := toExplanationModel(explanation)
+		response.ClaGroupID = claGroup.ProjectID
+		response.RepositoryName = repositoryName
+		return coverage.NewExplainCoverageOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
+	api.CoverageAuditCoverageHandler = coverage.AuditCoverageHandlerFunc(func(params coverage.AuditCoverageParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		claGroupID := strings.TrimSpace(swag.StringValue(params.Body.ClaGroupID))
+		f := logrus.Fields{
+			"functionName":   "CoverageAuditCoverageHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     claGroupID,
+			"commits":        len(params.Body.Commits),
+			"authUserName":   user.UserName,
+		}
+
+		commits, err := toAuthorCommits(params.Body.Commits)
+		if err != nil {
+			return coverage.NewAuditCoverageBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if len(v1Coverage.GroupAuthors(commits)) > maxAuditAuthors {
+			return coverage.NewAuditCoverageBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, ErrTooManyAuditAuthors))
+		}
+
+		claGroup, err := projectService.GetCLAGroupByID(ctx, claGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA Group by ID")
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return coverage.NewAuditCoverageNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return coverage.NewAuditCoverageInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorized(user, claGroup) {
+			return coverage.NewAuditCoverageForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Audit Coverage", claGroup))
+		}
+
+		report, err := v1Coverage.Audit(ctx, evaluator, claGroup.ProjectID, commits)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem auditing the coverage of the commit authors")
+			return coverage.NewAuditCoverageInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		return coverage.NewAuditCoverageOK().WithXRequestID(reqID).WithPayload(toAuditReportModel(report))
+	})
+}
+
+// toAuthorCommits converts the commits of the audit request, the commits need an email or a GitHub username
+func toAuthorCommits(items []*models.CoverageAuditInputCommitsItems0) ([]*v1Coverage.AuthorCommit, error) {
+	var commits []*v1Coverage.AuthorCommit
+	for i, item := range items {
+		if item == nil {
+			continue
+		}
+		commit := &v1Coverage.AuthorCommit{
+			Name:           strings.TrimSpace(item.Name),
+			Email:          strings.TrimSpace(item.Email),
+			GitHubUsername: strings.TrimSpace(item.GithubUsername),
+		}
+		if commit.Email == "" && commit.GitHubUsername == "" {
+			return nil, fmt.Errorf("commit %d has no email or githubUsername", i+1)
+		}
+		if date := strings.TrimSpace(item.Date); date != "" {
+			var err error
+			commit.Date, err = v1Coverage.ParseCommitDate(date)
+			if err != nil {
+				return nil, fmt.Errorf("invalid date of commit %d: %s", i+1, date)
+			}
+		}
+		commits = append(commits, commit)
+	}
+	if len(commits) == 0 {
+		return nil, v1Coverage.ErrNoCommits
+	}
+	return commits, nil
+}
+
+// toAuditReportModel converts the audit report to the response model
+func toAuditReportModel(report *v1Coverage.AuditReport) *models.CoverageAuditReport {
+	response := &models.CoverageAuditReport{
+		ClaGroupID:       report.ClaGroupID,
+		Authors:          int64(report.Authors),
+		CoveredAuthors:   int64(report.CoveredAuthors),
+		ExemptedAuthors:  int64(report.ExemptedAuthors),
+		Commits:          int64(report.Commits),
+		UncoveredCommits: int64(report.UncoveredCommits),
+		Uncovered:        []*models.CoverageAuditReportUncoveredItems0{},
+	}
+	for _, author := range report.Uncovered {
+		item := &models.CoverageAuditReportUncoveredItems0{
+			Name:           author.Name,
+			Email:          author.Email,
+			GithubUsername: author.GitHubUsername,
+			Commits:        int64(author.Commits),
+			UserID:         author.UserID,
+			CompanyID:      author.CompanyID,
+			Reason:         author.Reason,
+		}
+		if !author.FirstCommit.IsZero() {
+			item.FirstCommit = strfmt.DateTime(author.FirstCommit)
+			item.LastCommit = strfmt.DateTime(author.LastCommit)
+		}
+		response.Uncovered = append(response.Uncovered, item)
+	}
+	return response
+}
+
+// toExplanationModel converts the explanation to the response model
+func toExplanationModel(explanation *v1Coverage.Explanation) *models.CoverageExplanation {
+	response := &models.CoverageExplanation{
+		Covered:          explanation.Covered,
+		Rule:             explanation.Rule,
+		Reason:           explanation.Reason,
+		UserID:           explanation.UserID,
+		UserResolvedBy:   explanation.UserResolvedBy,
+		CompanyID:        explanation.CompanyID,
+		SignatureID:      explanation.SignatureID,
+		ExemptionID:      explanation.ExemptionID,
+		ExemptionType:    explanation.ExemptionType,
+		ExemptionPattern: explanation.ExemptionPattern,
+	}
+	for _, check := range explanation.Checks {
+		response.Checks = append(response.Checks, &models.CoverageExplanationChecksItems0{
+			Name:        check.Name,
+			Passed:      check.Passed,
+			Detail:      check.Detail,
+			CompanyID:   check.CompanyID,
+			SignatureID: check.SignatureID,
+		})
+	}
+	for _, nearMiss := range explanation.NearMisses {
+		response.NearMisses = append(response.NearMisses, &models.CoverageExplanationNearMissesItems0{
+			Rule:              nearMiss.Rule,
+			CompanyID:         nearMiss.CompanyID,
+			CompanyName:       nearMiss.CompanyName,
+			SignatureID:       nearMiss.SignatureID,
+			ApprovalListType:  nearMiss.ApprovalListType,
+			ApprovalListEntry: nearMiss.ApprovalListEntry,
+			Reason:            nearMiss.Reason,
+		})
+	}
+	return response
+}
+
+// isUserAuthorized returns true if the user is an admin or has access to the foundation of the CLA Group
+func isUserAuthorized(user *auth.User, claGroup *v1Models.ClaGroup) bool {
+	return utils.IsUserAdmin(user) || utils.IsUserAuthorizedForProjectTree(user, claGroup.FoundationSFID, utils.ALLOW_ADMIN_SCOPE)
+}
+
+func forbiddenResponse(reqID string, user *auth.User, action string, claGroup *v1Models.ClaGroup) *models.ErrorResponse {
+	return &models.ErrorResponse{
+		Code:       "403",
+		Message:    fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to %s for the CLA Group %s.", user.UserName, action, claGroup.ProjectName),
+		XRequestID: reqID,
+	}
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(reqID string, err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:       code,
+		Message:    err.Error(),
+		XRequestID: reqID,
+	}
+
+	return &e
+}
@@ -0,0 +1,66 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package dynamo_events
+
+import (
+	"reflect"
+
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// signatureCoverage holds the signature attributes the coverage of the contributors depends on
+type signatureCoverage struct {
+	SignatureProjectID         string   `json:"signature_project_id"`
+	SignatureSigned            bool     `json:"signature_signed"`
+	SignatureApproved          bool     `json:"signature_approved"`
+	EmailWhitelist             []string `json:"email_whitelist"`
+	DomainWhitelist            []string `json:"domain_whitelist"`
+	GitHubWhitelist            []string `json:"github_whitelist"`
+	GitHubOrgWhitelist         []string `json:"github_org_whitelist"`
+	GitLabUsernameApprovalList []string `json:"gitlab_username_approval_list"`
+	ApprovalListEntryDetails   []struct {
+		ListType  string `json:"list_type"`
+		Value     string `json:"value"`
+		ExpiresOn string `json:"expires_on"`
+	} `json:"approval_list_entry_details"`
+}
+
+func (c *signatureCoverage) active() bool {
+	return c.SignatureSigned && c.SignatureApproved
+}
+
+// RecheckPullRequestsEvent publishes the CLA check runs of the open pull requests of the CLA Group again when a
+// signature which covers contributors, or its approval lists, changed
+func (s *service) RecheckPullRequestsEvent(event events.DynamoDBEventRecord) error {
+	f := logrus.Fields{
+		"functionName": "dynamo_events.RecheckPullRequestsEvent",
+		"eventID":      event.EventID,
+		"eventName":    event.EventName,
+	}
+
+	var oldSig, newSig signatureCoverage
+	hasOld, hasNew, err := streamImages(event, &oldSig, &newSig)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem decoding the signature record")
+		return err
+	}
+	if !(hasOld && oldSig.active()) && !(hasNew && newSig.active()) {
+		return nil
+	}
+	if hasOld && hasNew && reflect.DeepEqual(oldSig, newSig) {
+		log.WithFields(f).Debug("the coverage attributes of the signature didn't change, skipping")
+		return nil
+	}
+
+	claGroupID := newSig.SignatureProjectID
+	if claGroupID == "" {
+		claGroupID = oldSig.SignatureProjectID
+	}
+	f["claGroupID"] = claGroupID
+	log.WithFields(f).Debug("checking the pull requests of the CLA Group again")
+	return s.checkRunsService.RecheckCLAGroup(utils.NewContext(), claGroupID)
+}
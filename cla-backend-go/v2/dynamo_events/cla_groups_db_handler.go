@@ -44,8 +44,7 @@ func (s *service) ProcessCLAGroupUpdateEvents(event events.DynamoDBEventRecord)
 		log.WithFields(f).Warnf("unable to update cla manager request with updated CLA Group information, error: %+v", approvalListRequestErr)
 	}
 
-	// TODO - update other tables:
-	//  cla-%s-metrics,
+	// TODO - update other tables (cla-%s-metrics is updated by MetricsCLAGroupEvent):
 	//  cla-%s-projects-cla-groups,
 	//  cla-%s-gerrit-instances,
 	// possibly add/update cla_group_name/project_name to other tables:
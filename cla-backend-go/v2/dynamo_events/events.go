@@ -5,6 +5,7 @@ package dynamo_events
 
 import (
 	"github.com/aws/aws-lambda-go/events"
+	claevent "github.com/communitybridge/easycla/cla-backend-go/events"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	v2ProjectService "github.com/communitybridge/easycla/cla-backend-go/v2/project-service"
@@ -58,5 +59,19 @@ func (s *service) EventAddedEvent(event events.DynamoDBEventRecord) error {
 	if err != nil {
 		return err
 	}
-	return nil
+
+	if s.eventSubscriptionsService == nil {
+		return nil
+	}
+	// Send the event to the webhook subscriptions with the Foundation and project details added above
+	var subscriptionEvent claevent.Event
+	err = unmarshalStreamImage(event.Change.NewImage, &subscriptionEvent)
+	if err != nil {
+		return err
+	}
+	subscriptionEvent.EventFoundationSFID = foundationSFID
+	subscriptionEvent.EventProjectSFID = projectSFID
+	subscriptionEvent.EventSFProjectName = projectSFName
+	subscriptionEvent.EventCompanySFID = companySFID
+	return s.eventSubscriptionsService.DeliverEvent(ctx, &subscriptionEvent)
 }
@@ -10,6 +10,7 @@ import (
 	githubutils "github.com/communitybridge/easycla/cla-backend-go/github"
 	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sync/errgroup"
@@ -169,7 +170,7 @@ func (s *service) enableBranchProtectionForGithubOrg(f logrus.Fields, newGitHubO
 			log.WithFields(f).Debugf("enabling branch protection on the default branch %s for the GitHub repository: %s...",
 				defaultBranch, repo.RepositoryName)
 			return branchProtectionRepo.EnableBranchProtection(ctx, newGitHubOrg.OrganizationName, repo.RepositoryName,
-				defaultBranch, true, []string{utils.GitHubBotName}, []string{})
+				defaultBranch, true, repositories.StatusCheckNames(repo.EnforcementMode), []string{})
 		})
 	}
 
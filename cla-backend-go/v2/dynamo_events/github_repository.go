@@ -128,7 +128,7 @@ func (s *service) EnableBranchProtectionServiceHandler(event events.DynamoDBEven
 				defaultBranch, newRepoModel.RepositoryName)
 			return branchProtectionRepository.EnableBranchProtection(ctx,
 				parentOrgName, newRepoModel.RepositoryName,
-				defaultBranch, true, []string{utils.GitHubBotName}, []string{})
+				defaultBranch, true, repositories.StatusCheckNames(newRepoModel.EnforcementMode), []string{})
 		}
 
 		log.WithFields(f).Debug("github organization branch protection is not enabled - no action required")
@@ -0,0 +1,97 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package dynamo_events
+
+import (
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
+	"github.com/sirupsen/logrus"
+)
+
+// streamImages decodes the old and new images of the record - the image is nil when the record was inserted or removed
+func streamImages(event events.DynamoDBEventRecord, oldImage, newImage interface{}) (bool, bool, error) {
+	hasOld := len(event.Change.OldImage) > 0
+	if hasOld {
+		if err := unmarshalStreamImage(event.Change.OldImage, oldImage); err != nil {
+			return false, false, err
+		}
+	}
+	hasNew := len(event.Change.NewImage) > 0
+	if hasNew {
+		if err := unmarshalStreamImage(event.Change.NewImage, newImage); err != nil {
+			return false, false, err
+		}
+	}
+	return hasOld, hasNew, nil
+}
+
+func metricsEventFields(functionName string, event events.DynamoDBEventRecord) logrus.Fields {
+	return logrus.Fields{
+		"functionName": functionName,
+		"eventID":      event.EventID,
+		"eventName":    event.EventName,
+		"eventSource":  event.EventSource,
+	}
+}
+
+// MetricsSignatureEvent updates the metrics counters with the inserted, modified or removed signature
+func (s *service) MetricsSignatureEvent(event events.DynamoDBEventRecord) error {
+	f := metricsEventFields("MetricsSignatureEvent", event)
+	var oldSig, newSig metrics.ItemSignature
+	hasOld, hasNew, err := streamImages(event, &oldSig, &newSig)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem decoding the signature record")
+		return err
+	}
+
+	var oldPtr, newPtr *metrics.ItemSignature
+	if hasOld {
+		oldPtr = &oldSig
+	}
+	if hasNew {
+		newPtr = &newSig
+	}
+	return s.metricsRepo.ApplySignatureChange(oldPtr, newPtr)
+}
+
+// MetricsRepositoryEvent updates the metrics counters with the inserted, modified or removed GitHub repository
+func (s *service) MetricsRepositoryEvent(event events.DynamoDBEventRecord) error {
+	f := metricsEventFields("MetricsRepositoryEvent", event)
+	var oldRepo, newRepo metrics.ItemRepository
+	hasOld, hasNew, err := streamImages(event, &oldRepo, &newRepo)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem decoding the repository record")
+		return err
+	}
+
+	var oldPtr, newPtr *metrics.ItemRepository
+	if hasOld {
+		oldPtr = &oldRepo
+	}
+	if hasNew {
+		newPtr = &newRepo
+	}
+	return s.metricsRepo.ApplyRepositoryChange(oldPtr, newPtr)
+}
+
+// MetricsCLAGroupEvent updates the metrics counters with the inserted, modified or removed CLA Group
+func (s *service) MetricsCLAGroupEvent(event events.DynamoDBEventRecord) error {
+	f := metricsEventFields("MetricsCLAGroupEvent", event)
+	var oldCLAGroup, newCLAGroup metrics.ItemProject
+	hasOld, hasNew, err := streamImages(event, &oldCLAGroup, &newCLAGroup)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem decoding the CLA Group record")
+		return err
+	}
+
+	var oldPtr, newPtr *metrics.ItemProject
+	if hasOld {
+		oldPtr = &oldCLAGroup
+	}
+	if hasNew {
+		newPtr = &newCLAGroup
+	}
+	return s.metricsRepo.ApplyCLAGroupChange(oldPtr, newPtr)
+}
@@ -0,0 +1,219 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package dynamo_events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// retry policy
+const (
+	MaxRetryAttempts = 8
+	retryBaseDelay   = time.Minute
+	retryMaxDelay    = 6 * time.Hour
+)
+
+// eventHandler is a registered handler for a table:action key
+type eventHandler struct {
+	name       string
+	fn         EventHandlerFunc
+	idempotent bool
+}
+
+// handlerName returns the short name of the handler function, e.g. SignatureSignedEvent
+func handlerName(fn EventHandlerFunc) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+// retryDelay returns the backoff delay before the next attempt
+func retryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// recordFailure saves the failed handler and record pair - only idempotent handlers are retried automatically,
+// the others go straight to the dead letter list and need an operator to replay them
+func (s *service) recordFailure(ctx context.Context, key string, handler eventHandler, record events.DynamoDBEventRecord, handlerErr error) {
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.recordFailure",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"key":            key,
+		"handlerName":    handler.name,
+		"eventID":        record.EventID,
+	}
+
+	if s.retryStore == nil {
+		return
+	}
+
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to marshal the event record, dropping failed event")
+		return
+	}
+
+	failedEventID, err := uuid.NewV4()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to generate a failed event id, dropping failed event")
+		return
+	}
+
+	now, currentTime := utils.CurrentTime()
+	failedEvent := &FailedEvent{
+		FailedEventID:    failedEventID.String(),
+		EventKey:         key,
+		HandlerName:      handler.name,
+		Idempotent:       handler.idempotent,
+		EventID:          record.EventID,
+		Record:           string(recordBytes),
+		Attempts:         1,
+		LastError:        handlerErr.Error(),
+		NextAttemptEpoch: now.Add(retryDelay(1)).Unix(),
+		DeadLetter:       !handler.idempotent,
+		DateCreated:      currentTime,
+		DateModified:     currentTime,
+	}
+
+	if err := s.retryStore.SaveFailedEvent(ctx, failedEvent); err != nil {
+		log.WithFields(f).WithError(err).Error("unable to save the failed event")
+	}
+}
+
+// findHandler returns the registered handler for the key and name
+func (s *service) findHandler(key, name string) (eventHandler, bool) {
+	for _, handler := range s.functions[key] {
+		if handler.name == name {
+			return handler, true
+		}
+	}
+	return eventHandler{}, false
+}
+
+// ListFailedEvents returns the failed events, when deadLetterOnly is set only the events which are no longer retried
+func (s *service) ListFailedEvents(ctx context.Context, deadLetterOnly bool) ([]*FailedEvent, error) {
+	if s.retryStore == nil {
+		return nil, fmt.Errorf("retry store is not configured")
+	}
+
+	failedEvents, err := s.retryStore.ListFailedEvents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !deadLetterOnly {
+		return failedEvents, nil
+	}
+
+	var out []*FailedEvent
+	for _, failedEvent := range failedEvents {
+		if failedEvent.DeadLetter {
+			out = append(out, failedEvent)
+		}
+	}
+	return out, nil
+}
+
+// RetryFailedEvents retries the failed events which are due, returns the number of events processed successfully
+func (s *service) RetryFailedEvents(ctx context.Context) (int, error) {
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.RetryFailedEvents",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	failedEvents, err := s.ListFailedEvents(ctx, false)
+	if err != nil {
+		return 0, err
+	}
+
+	now, _ := utils.CurrentTime()
+	succeeded := 0
+	for _, failedEvent := range failedEvents {
+		if failedEvent.DeadLetter || failedEvent.NextAttemptEpoch > now.Unix() {
+			continue
+		}
+		if err := s.replay(ctx, failedEvent); err != nil {
+			log.WithFields(f).WithField("failedEventID", failedEvent.FailedEventID).WithError(err).Warn("retry of the failed event failed")
+			continue
+		}
+		succeeded++
+	}
+
+	log.WithFields(f).Debugf("retried failed events, %d succeeded", succeeded)
+	return succeeded, nil
+}
+
+// ReplayFailedEvent replays the failed event on operator request. Events of handlers which are not idempotent are
+// only replayed when force is set, as they may have partially completed the first time.
+func (s *service) ReplayFailedEvent(ctx context.Context, failedEventID string, force bool) error {
+	if s.retryStore == nil {
+		return fmt.Errorf("retry store is not configured")
+	}
+
+	failedEvent, err := s.retryStore.GetFailedEvent(ctx, failedEventID)
+	if err != nil {
+		return err
+	}
+
+	if !failedEvent.Idempotent && !force {
+		return fmt.Errorf("handler %s is not idempotent, replay needs to be forced", failedEvent.HandlerName)
+	}
+
+	return s.replay(ctx, failedEvent)
+}
+
+// replay runs the handler for the failed event again, the entry is removed on success and updated on failure
+func (s *service) replay(ctx context.Context, failedEvent *FailedEvent) error {
+	handler, ok := s.findHandler(failedEvent.EventKey, failedEvent.HandlerName)
+	if !ok {
+		return fmt.Errorf("no handler %s registered for %s", failedEvent.HandlerName, failedEvent.EventKey)
+	}
+
+	var record events.DynamoDBEventRecord
+	if err := json.Unmarshal([]byte(failedEvent.Record), &record); err != nil {
+		return fmt.Errorf("unable to unmarshal the event record: %v", err)
+	}
+
+	handlerErr := handler.fn(record)
+	if handlerErr == nil {
+		return s.retryStore.DeleteFailedEvent(ctx, failedEvent.FailedEventID)
+	}
+
+	now, currentTime := utils.CurrentTime()
+	failedEvent.Attempts++
+	failedEvent.LastError = handlerErr.Error()
+	failedEvent.NextAttemptEpoch = now.Add(retryDelay(failedEvent.Attempts)).Unix()
+	failedEvent.DeadLetter = failedEvent.DeadLetter || !handler.idempotent || failedEvent.Attempts >= MaxRetryAttempts
+	failedEvent.DateModified = currentTime
+	if err := s.retryStore.SaveFailedEvent(ctx, failedEvent); err != nil {
+		return err
+	}
+
+	return handlerErr
+}
@@ -0,0 +1,268 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package dynamo_events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrFailedEventNotFound = errors.New("failed event not found")
+)
+
+// FailedEvent is a handler and stream record pair which could not be processed
+type FailedEvent struct {
+	FailedEventID    string `dynamodbav:"failed_event_id" json:"failed_event_id"`
+	EventKey         string `dynamodbav:"event_key" json:"event_key"`
+	HandlerName      string `dynamodbav:"handler_name" json:"handler_name"`
+	Idempotent       bool   `dynamodbav:"idempotent" json:"idempotent"`
+	EventID          string `dynamodbav:"event_id" json:"event_id"`
+	Record           string `dynamodbav:"record" json:"record"`
+	Attempts         int    `dynamodbav:"attempts" json:"attempts"`
+	LastError        string `dynamodbav:"last_error" json:"last_error"`
+	NextAttemptEpoch int64  `dynamodbav:"next_attempt_epoch" json:"next_attempt_epoch"`
+	DeadLetter       bool   `dynamodbav:"dead_letter" json:"dead_letter"`
+	DateCreated      string `dynamodbav:"date_created" json:"date_created"`
+	DateModified     string `dynamodbav:"date_modified" json:"date_modified"`
+}
+
+// RetryStore keeps the failed events so they can be retried or replayed by an operator
+type RetryStore interface {
+	SaveFailedEvent(ctx context.Context, failedEvent *FailedEvent) error
+	GetFailedEvent(ctx context.Context, failedEventID string) (*FailedEvent, error)
+	ListFailedEvents(ctx context.Context) ([]*FailedEvent, error)
+	DeleteFailedEvent(ctx context.Context, failedEventID string) error
+}
+
+// NewDynamoRetryStore creates a retry store backed by the cla-<stage>-dynamo-event-failures table
+func NewDynamoRetryStore(dynamoDBClient storage.Driver, stage string) RetryStore {
+	return &dynamoRetryStore{
+		dynamoDBClient: dynamoDBClient,
+		tableName:      fmt.Sprintf("cla-%s-dynamo-event-failures", stage),
+	}
+}
+
+type dynamoRetryStore struct {
+	dynamoDBClient storage.Driver
+	tableName      string
+}
+
+// SaveFailedEvent creates or replaces the failed event entry
+func (r *dynamoRetryStore) SaveFailedEvent(ctx context.Context, failedEvent *FailedEvent) error {
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.SaveFailedEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"failedEventID":  failedEvent.FailedEventID,
+		"handlerName":    failedEvent.HandlerName,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(failedEvent)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the failed event")
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to save the failed event")
+		return err
+	}
+
+	return nil
+}
+
+// GetFailedEvent returns the failed event entry
+func (r *dynamoRetryStore) GetFailedEvent(ctx context.Context, failedEventID string) (*FailedEvent, error) {
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.GetFailedEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"failedEventID":  failedEventID,
+	}
+
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"failed_event_id": {S: aws.String(failedEventID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the failed event")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrFailedEventNotFound
+	}
+
+	var failedEvent FailedEvent
+	err = dynamodbattribute.UnmarshalMap(result.Item, &failedEvent)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the failed event")
+		return nil, err
+	}
+
+	return &failedEvent, nil
+}
+
+// ListFailedEvents returns all the failed event entries, oldest first
+func (r *dynamoRetryStore) ListFailedEvents(ctx context.Context) ([]*FailedEvent, error) {
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.ListFailedEvents",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	expr, err := expression.NewBuilder().WithProjection(buildFailedEventProjection()).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		ExpressionAttributeNames: expr.Names(),
+		ProjectionExpression:     expr.Projection(),
+		TableName:                aws.String(r.tableName),
+	}
+
+	var out []*FailedEvent
+	for {
+		results, scanErr := r.dynamoDBClient.ScanWithContext(ctx, scanInput)
+		if scanErr != nil {
+			log.WithFields(f).WithError(scanErr).Warn("unable to scan the failed events")
+			return nil, scanErr
+		}
+
+		var items []*FailedEvent
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem unmarshalling the failed events")
+			return nil, err
+		}
+		out = append(out, items...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	sortFailedEvents(out)
+	return out, nil
+}
+
+// DeleteFailedEvent removes the failed event entry
+func (r *dynamoRetryStore) DeleteFailedEvent(ctx context.Context, failedEventID string) error {
+	f := logrus.Fields{
+		"functionName":   "dynamo_events.DeleteFailedEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"failedEventID":  failedEventID,
+	}
+
+	_, err := r.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"failed_event_id": {S: aws.String(failedEventID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to delete the failed event")
+		return err
+	}
+
+	return nil
+}
+
+func buildFailedEventProjection() expression.ProjectionBuilder {
+	return expression.NamesList(
+		expression.Name("failed_event_id"),
+		expression.Name("event_key"),
+		expression.Name("handler_name"),
+		expression.Name("idempotent"),
+		expression.Name("event_id"),
+		expression.Name("record"),
+		expression.Name("attempts"),
+		expression.Name("last_error"),
+		expression.Name("next_attempt_epoch"),
+		expression.Name("dead_letter"),
+		expression.Name("date_created"),
+		expression.Name("date_modified"),
+	)
+}
+
+// NewInMemoryRetryStore creates a retry store which keeps the failed events in memory - used for local mode and tests
+func NewInMemoryRetryStore() RetryStore {
+	return &inMemoryRetryStore{
+		failedEvents: make(map[string]*FailedEvent),
+	}
+}
+
+type inMemoryRetryStore struct {
+	lock         sync.Mutex
+	failedEvents map[string]*FailedEvent
+}
+
+// SaveFailedEvent creates or replaces the failed event entry
+func (r *inMemoryRetryStore) SaveFailedEvent(ctx context.Context, failedEvent *FailedEvent) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	copied := *failedEvent
+	r.failedEvents[failedEvent.FailedEventID] = &copied
+	return nil
+}
+
+// GetFailedEvent returns the failed event entry
+func (r *inMemoryRetryStore) GetFailedEvent(ctx context.Context, failedEventID string) (*FailedEvent, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	failedEvent, ok := r.failedEvents[failedEventID]
+	if !ok {
+		return nil, ErrFailedEventNotFound
+	}
+	copied := *failedEvent
+	return &copied, nil
+}
+
+// ListFailedEvents returns all the failed event entries, oldest first
+func (r *inMemoryRetryStore) ListFailedEvents(ctx context.Context) ([]*FailedEvent, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	out := make([]*FailedEvent, 0, len(r.failedEvents))
+	for _, failedEvent := range r.failedEvents {
+		copied := *failedEvent
+		out = append(out, &copied)
+	}
+	sortFailedEvents(out)
+	return out, nil
+}
+
+// DeleteFailedEvent removes the failed event entry
+func (r *inMemoryRetryStore) DeleteFailedEvent(ctx context.Context, failedEventID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.failedEvents, failedEventID)
+	return nil
+}
+
+func sortFailedEvents(failedEvents []*FailedEvent) {
+	sort.Slice(failedEvents, func(i, j int) bool {
+		if failedEvents[i].DateCreated == failedEvents[j].DateCreated {
+			return failedEvents[i].FailedEventID < failedEvents[j].FailedEventID
+		}
+		return failedEvents[i].DateCreated < failedEvents[j].DateCreated
+	})
+}
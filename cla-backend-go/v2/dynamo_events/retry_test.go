@@ -0,0 +1,101 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package dynamo_events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	testCases := []struct {
+		attempts int
+		delay    time.Duration
+	}{
+		{attempts: 0, delay: time.Minute},
+		{attempts: 1, delay: time.Minute},
+		{attempts: 2, delay: 2 * time.Minute},
+		{attempts: 4, delay: 8 * time.Minute},
+		{attempts: 20, delay: retryMaxDelay},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.delay, retryDelay(tc.attempts))
+	}
+}
+
+func TestHandlerName(t *testing.T) {
+	s := &service{}
+	assert.Equal(t, "SignatureSignedEvent", handlerName(s.SignatureSignedEvent))
+}
+
+func TestRetryFailedEvents(t *testing.T) {
+	ctx := context.Background()
+	key := "signatures:MODIFY"
+	record := events.DynamoDBEventRecord{EventID: "c4ca4238a0b923820dcc509a6f75849b", EventName: "MODIFY"}
+
+	failures := 1
+	flaky := func(events.DynamoDBEventRecord) error {
+		if failures > 0 {
+			failures--
+			return errors.New("temporary failure")
+		}
+		return nil
+	}
+	broken := func(events.DynamoDBEventRecord) error {
+		return errors.New("permanent failure")
+	}
+
+	s := &service{
+		functions: map[string][]eventHandler{
+			key: {
+				{name: "flaky", fn: flaky, idempotent: true},
+				{name: "broken", fn: broken, idempotent: false},
+			},
+		},
+		retryStore: NewInMemoryRetryStore(),
+	}
+
+	for _, h := range s.functions[key] {
+		if err := h.fn(record); err != nil {
+			s.recordFailure(ctx, key, h, record, err)
+		}
+	}
+
+	failedEvents, err := s.ListFailedEvents(ctx, false)
+	assert.NoError(t, err)
+	assert.Len(t, failedEvents, 2)
+
+	deadLetters, err := s.ListFailedEvents(ctx, true)
+	assert.NoError(t, err)
+	if assert.Len(t, deadLetters, 1) {
+		assert.Equal(t, "broken", deadLetters[0].HandlerName)
+	}
+
+	// make the idempotent failure due now
+	for _, failedEvent := range failedEvents {
+		failedEvent.NextAttemptEpoch = 0
+		assert.NoError(t, s.retryStore.SaveFailedEvent(ctx, failedEvent))
+	}
+
+	succeeded, err := s.RetryFailedEvents(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, succeeded)
+
+	// the dead letter is only replayed when forced and stays in the store while the handler keeps failing
+	assert.Error(t, s.ReplayFailedEvent(ctx, deadLetters[0].FailedEventID, false))
+	assert.EqualError(t, s.ReplayFailedEvent(ctx, deadLetters[0].FailedEventID, true), "permanent failure")
+
+	failedEvents, err = s.ListFailedEvents(ctx, false)
+	assert.NoError(t, err)
+	if assert.Len(t, failedEvents, 1) {
+		assert.Equal(t, 2, failedEvents[0].Attempts)
+		assert.True(t, failedEvents[0].DeadLetter)
+	}
+}
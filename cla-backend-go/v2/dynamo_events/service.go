@@ -4,13 +4,14 @@
 package dynamo_events
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"reflect"
-	"runtime"
 	"strings"
 	"sync"
 
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+
 	"github.com/communitybridge/easycla/cla-backend-go/gerrits"
 
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
@@ -28,6 +29,9 @@ import (
 	v2Company "github.com/communitybridge/easycla/cla-backend-go/v2/company"
 
 	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/check_runs"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/event_subscriptions"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/metrics"
 
 	"github.com/sirupsen/logrus"
 
@@ -50,25 +54,32 @@ type EventHandlerFunc func(event events.DynamoDBEventRecord) error
 
 type service struct {
 	// key : tablename:action
-	functions                map[string][]EventHandlerFunc
-	signatureRepo            signatures.SignatureRepository
-	companyRepo              company.IRepository
-	companyService           v2Company.Service
-	projectsClaGroupRepo     projects_cla_groups.Repository
-	eventsRepo               claevent.Repository
-	projectRepo              project.ProjectRepository
-	projectService           project.Service
-	githubOrgService         github_organizations.Service
-	repositoryService        repositories.Service
-	gerritService            gerrits.Service
-	autoEnableService        *autoEnableServiceProvider
-	claManagerRequestsRepo   cla_manager.IRepository
-	approvalListRequestsRepo approval_list.IRepository
+	functions                 map[string][]eventHandler
+	retryStore                RetryStore
+	signatureRepo             signatures.SignatureRepository
+	companyRepo               company.IRepository
+	companyService            v2Company.Service
+	projectsClaGroupRepo      projects_cla_groups.Repository
+	eventsRepo                claevent.Repository
+	projectRepo               project.ProjectRepository
+	projectService            project.Service
+	githubOrgService          github_organizations.Service
+	repositoryService         repositories.Service
+	gerritService             gerrits.Service
+	autoEnableService         *autoEnableServiceProvider
+	claManagerRequestsRepo    cla_manager.IRepository
+	approvalListRequestsRepo  approval_list.IRepository
+	metricsRepo               metrics.Repository
+	eventSubscriptionsService event_subscriptions.Service
+	checkRunsService          check_runs.Service
 }
 
 // Service implements DynamoDB stream event handler service
 type Service interface {
 	ProcessEvents(event events.DynamoDBEvent)
+	ListFailedEvents(ctx context.Context, deadLetterOnly bool) ([]*FailedEvent, error)
+	RetryFailedEvents(ctx context.Context) (int, error)
+	ReplayFailedEvent(ctx context.Context, failedEventID string, force bool) error
 }
 
 // NewService creates DynamoDB stream event handler service
@@ -84,7 +95,11 @@ func NewService(stage string,
 	repositoryService repositories.Service,
 	gerritService gerrits.Service,
 	claManagerRequestsRepo cla_manager.IRepository,
-	approvalListRequestsRepo approval_list.IRepository) Service {
+	approvalListRequestsRepo approval_list.IRepository,
+	metricsRepo metrics.Repository,
+	retryStore RetryStore,
+	eventSubscriptionsService event_subscriptions.Service,
+	checkRunsService check_runs.Service) Service {
 
 	signaturesTable := fmt.Sprintf("cla-%s-signatures", stage)
 	eventsTable := fmt.Sprintf("cla-%s-events", stage)
@@ -95,63 +110,88 @@ func NewService(stage string,
 	claGroupsTable := fmt.Sprintf("cla-%s-projects", stage)
 
 	s := &service{
-		functions:                make(map[string][]EventHandlerFunc),
-		signatureRepo:            signatureRepo,
-		companyRepo:              companyRepo,
-		companyService:           companyService,
-		projectsClaGroupRepo:     pcgRepo,
-		eventsRepo:               eventsRepo,
-		projectRepo:              projectRepo,
-		projectService:           projService,
-		githubOrgService:         githubOrgService,
-		repositoryService:        repositoryService,
-		gerritService:            gerritService,
-		autoEnableService:        &autoEnableServiceProvider{repositoryService: repositoryService},
-		claManagerRequestsRepo:   claManagerRequestsRepo,
-		approvalListRequestsRepo: approvalListRequestsRepo,
+		functions:                 make(map[string][]eventHandler),
+		retryStore:                retryStore,
+		signatureRepo:             signatureRepo,
+		companyRepo:               companyRepo,
+		companyService:            companyService,
+		projectsClaGroupRepo:      pcgRepo,
+		eventsRepo:                eventsRepo,
+		projectRepo:               projectRepo,
+		projectService:            projService,
+		githubOrgService:          githubOrgService,
+		repositoryService:         repositoryService,
+		gerritService:             gerritService,
+		autoEnableService:         &autoEnableServiceProvider{repositoryService: repositoryService},
+		claManagerRequestsRepo:    claManagerRequestsRepo,
+		approvalListRequestsRepo:  approvalListRequestsRepo,
+		metricsRepo:               metricsRepo,
+		eventSubscriptionsService: eventSubscriptionsService,
+		checkRunsService:          checkRunsService,
 	}
 
-	s.registerCallback(signaturesTable, Modify, s.SignatureSignedEvent)
-	s.registerCallback(signaturesTable, Modify, s.SignatureAssignContributorEvent)
-	s.registerCallback(signaturesTable, Modify, s.SignatureAddSigTypeSignedApprovedID)
-	s.registerCallback(signaturesTable, Insert, s.SignatureAddSigTypeSignedApprovedID)
-	s.registerCallback(signaturesTable, Insert, s.SignatureAddUsersDetails)
+	// The last argument marks the handler as idempotent - failures of idempotent handlers are retried automatically,
+	// the other failures are kept in the dead letter list until an operator replays them
+	s.registerCallback(signaturesTable, Modify, s.SignatureSignedEvent, false)
+	s.registerCallback(signaturesTable, Modify, s.SignatureAssignContributorEvent, false)
+	s.registerCallback(signaturesTable, Modify, s.SignatureAddSigTypeSignedApprovedID, true)
+	s.registerCallback(signaturesTable, Insert, s.SignatureAddSigTypeSignedApprovedID, true)
+	s.registerCallback(signaturesTable, Insert, s.SignatureAddUsersDetails, true)
 	// Add or Remove any CLA Permissions
-	s.registerCallback(signaturesTable, Modify, s.UpdateCLAPermissions)
+	s.registerCallback(signaturesTable, Modify, s.UpdateCLAPermissions, true)
+	// Publish the CLA check runs of the open pull requests again when the signatures or approval lists change
+	s.registerCallback(signaturesTable, Insert, s.RecheckPullRequestsEvent, true)
+	s.registerCallback(signaturesTable, Modify, s.RecheckPullRequestsEvent, true)
+	s.registerCallback(signaturesTable, Remove, s.RecheckPullRequestsEvent, true)
 
-	s.registerCallback(eventsTable, Insert, s.EventAddedEvent)
+	s.registerCallback(eventsTable, Insert, s.EventAddedEvent, true)
 
 	// Enable or Disable the CLA Service Enabled/Disabled flag/attribute in the platform Project Service
-	s.registerCallback(projectsCLAGroupsTable, Insert, s.ProjectServiceEnableCLAServiceHandler)
-	s.registerCallback(projectsCLAGroupsTable, Remove, s.ProjectServiceDisableCLAServiceHandler)
-	s.registerCallback(projectsCLAGroupsTable, Remove, s.ProjectUnenrolledDisableRepositoryHandler)
+	s.registerCallback(projectsCLAGroupsTable, Insert, s.ProjectServiceEnableCLAServiceHandler, false)
+	s.registerCallback(projectsCLAGroupsTable, Remove, s.ProjectServiceDisableCLAServiceHandler, false)
+	s.registerCallback(projectsCLAGroupsTable, Remove, s.ProjectUnenrolledDisableRepositoryHandler, true)
 
 	// Add or Remove any CLA Permissions for the specified project
-	s.registerCallback(projectsCLAGroupsTable, Insert, s.AddCLAPermissions)
-	s.registerCallback(projectsCLAGroupsTable, Remove, s.RemoveCLAPermissions)
+	s.registerCallback(projectsCLAGroupsTable, Insert, s.AddCLAPermissions, true)
+	s.registerCallback(projectsCLAGroupsTable, Remove, s.RemoveCLAPermissions, true)
 
 	// GitHub organization table modified event
-	s.registerCallback(githubOrgTableName, Insert, s.GitHubOrgAddedEvent)
-	s.registerCallback(githubOrgTableName, Modify, s.GitHubOrgUpdatedEvent)
-	s.registerCallback(githubOrgTableName, Remove, s.GitHubOrgDeletedEvent)
+	s.registerCallback(githubOrgTableName, Insert, s.GitHubOrgAddedEvent, false)
+	s.registerCallback(githubOrgTableName, Modify, s.GitHubOrgUpdatedEvent, false)
+	s.registerCallback(githubOrgTableName, Remove, s.GitHubOrgDeletedEvent, false)
 
-	s.registerCallback(repositoryTableName, Insert, s.GithubRepoModifyAddEvent)
-	s.registerCallback(repositoryTableName, Modify, s.GithubRepoModifyAddEvent)
-	s.registerCallback(repositoryTableName, Remove, s.GithubRepoModifyAddEvent)
+	s.registerCallback(repositoryTableName, Insert, s.GithubRepoModifyAddEvent, true)
+	s.registerCallback(repositoryTableName, Modify, s.GithubRepoModifyAddEvent, true)
+	s.registerCallback(repositoryTableName, Remove, s.GithubRepoModifyAddEvent, true)
 
 	// Check and enable/disable the branch protection when a project
-	s.registerCallback(repositoryTableName, Insert, s.EnableBranchProtectionServiceHandler)
-	s.registerCallback(repositoryTableName, Remove, s.DisableBranchProtectionServiceHandler)
-
-	s.registerCallback(claGroupsTable, Modify, s.ProcessCLAGroupUpdateEvents)
+	s.registerCallback(repositoryTableName, Insert, s.EnableBranchProtectionServiceHandler, true)
+	s.registerCallback(repositoryTableName, Remove, s.DisableBranchProtectionServiceHandler, true)
+
+	s.registerCallback(claGroupsTable, Modify, s.ProcessCLAGroupUpdateEvents, true)
+
+	// Maintain the metrics counters - adding or removing a counted record twice has no effect
+	s.registerCallback(signaturesTable, Insert, s.MetricsSignatureEvent, true)
+	s.registerCallback(signaturesTable, Modify, s.MetricsSignatureEvent, true)
+	s.registerCallback(signaturesTable, Remove, s.MetricsSignatureEvent, true)
+	s.registerCallback(repositoryTableName, Insert, s.MetricsRepositoryEvent, true)
+	s.registerCallback(repositoryTableName, Modify, s.MetricsRepositoryEvent, true)
+	s.registerCallback(repositoryTableName, Remove, s.MetricsRepositoryEvent, true)
+	s.registerCallback(claGroupsTable, Insert, s.MetricsCLAGroupEvent, true)
+	s.registerCallback(claGroupsTable, Modify, s.MetricsCLAGroupEvent, true)
+	s.registerCallback(claGroupsTable, Remove, s.MetricsCLAGroupEvent, true)
 
 	return s
 }
 
-func (s *service) registerCallback(tableName, eventName string, callbackFunction EventHandlerFunc) {
+func (s *service) registerCallback(tableName, eventName string, callbackFunction EventHandlerFunc, idempotent bool) {
 	key := fmt.Sprintf("%s:%s", tableName, eventName)
 	funcArr := s.functions[key]
-	funcArr = append(funcArr, callbackFunction)
+	funcArr = append(funcArr, eventHandler{
+		name:       handlerName(callbackFunction),
+		fn:         callbackFunction,
+		idempotent: idempotent,
+	})
 	s.functions[key] = funcArr
 }
 
@@ -181,17 +221,17 @@ func (s *service) ProcessEvents(dynamoDBEvents events.DynamoDBEvent) {
 			wg.Add(len(s.functions[key]))
 
 			// For each function handler...
-			for _, eventHandlerFunction := range s.functions[key] {
-				go func(f EventHandlerFunc, e events.DynamoDBEventRecord) {
+			for _, handler := range s.functions[key] {
+				go func(h eventHandler, e events.DynamoDBEventRecord) {
 					defer wg.Done()
 
-					fnType := runtime.FuncForPC(reflect.ValueOf(f).Pointer()).Name()
+					fnType := h.name
 					log.WithFields(fields).
 						WithField("key", key).
 						WithField("functionType", fnType).
 						Debug("invoking handler")
 
-					err := f(event)
+					err := h.fn(e)
 					if err != nil {
 						log.WithFields(fields).
 							WithField("key", key).
@@ -199,13 +239,14 @@ func (s *service) ProcessEvents(dynamoDBEvents events.DynamoDBEvent) {
 							WithError(err).
 							WithField("event", e).
 							Error("unable to process event")
+						s.recordFailure(utils.NewContext(), key, h, e, err)
 					}
 
 					log.WithFields(fields).
 						WithField("key", key).
 						WithField("functionType", fnType).
 						Debug("done with handler")
-				}(eventHandlerFunction, event)
+				}(handler, event)
 			}
 
 			// Wait until the registered handlers/functions have completed for this event type...
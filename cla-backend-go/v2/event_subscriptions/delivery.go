@@ -0,0 +1,190 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_subscriptions
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// headers of the event deliveries
+const (
+	SignatureHeader = "X-EasyCLA-Signature"
+	TimestampHeader = "X-EasyCLA-Timestamp"
+	EventTypeHeader = "X-EasyCLA-Event"
+	DeliveryHeader  = "X-EasyCLA-Delivery"
+)
+
+// retry policy
+const (
+	MaxDeliveryAttempts = 8
+	retryBaseDelay      = time.Minute
+	retryMaxDelay       = 6 * time.Hour
+	deliveryTimeout     = 10 * time.Second
+)
+
+// nonPublicNetworks are the address ranges the deliveries are never sent to, besides the loopback, link-local,
+// multicast and unspecified addresses - the private networks of RFC 1918, the carrier-grade NAT range and the IPv6
+// unique local addresses
+var nonPublicNetworks = mustParseCIDRs("10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7")
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// publicIP returns true if the deliveries can be sent to the address
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// newDeliveryClient returns the HTTP client of the deliveries - it doesn't follow redirects and only connects to
+// public addresses, as the host of a subscription URL can resolve to another address after the URL was validated
+func newDeliveryClient() *http.Client {
+	dialer := &net.Dialer{Timeout: deliveryTimeout, Control: dialPublicAddress}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the address the dialer checks
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	client := telemetry.NewHTTPClientWithTransport("event-subscriptions", "deliver", transport)
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return client
+}
+
+// dialPublicAddress rejects the connections to the addresses which are not public
+func dialPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("%w: %s is not a public address", ErrInvalidURL, host)
+	}
+	return nil
+}
+
+// Sign returns the signature of the body sent at the timestamp - the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the secret of the subscription. Signing the timestamp lets the receivers reject replayed deliveries.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10))) // nolint
+	mac.Write([]byte("."))                              // nolint
+	mac.Write(body)                                     // nolint
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newSecret returns a random secret for the signatures of a subscription
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// retryDelay returns the backoff delay before the next attempt
+func retryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
+
+// attempt posts the payload of the delivery to the subscription and records the outcome in the delivery - a failed
+// attempt leaves the delivery pending until it failed MaxDeliveryAttempts times
+func attempt(ctx context.Context, client *http.Client, subscription *DBEventSubscription, delivery *DBEventDelivery) {
+	now, currentTime := utils.CurrentTime()
+	delivery.Attempts++
+	delivery.DateModified = currentTime
+
+	statusCode, err := post(ctx, client, subscription, delivery, now)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = DeliveryStatusDelivered
+		delivery.Pending = ""
+		delivery.LastError = ""
+		delivery.NextAttemptEpoch = 0
+		delivery.DateDelivered = currentTime
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= MaxDeliveryAttempts {
+		delivery.Status = DeliveryStatusFailed
+		delivery.Pending = ""
+		delivery.NextAttemptEpoch = 0
+		return
+	}
+	delivery.Status = DeliveryStatusPending
+	delivery.Pending = pendingValue
+	delivery.NextAttemptEpoch = now.Add(retryDelay(delivery.Attempts)).Unix()
+}
+
+// post sends the signed payload, any 2xx response is a successful delivery
+func post(ctx context.Context, client *http.Client, subscription *DBEventSubscription, delivery *DBEventDelivery, now time.Time) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.DeliveryID)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close() // nolint
+	// the response body is not kept, the error is shown to everyone who can read the deliveries of the subscription
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp.StatusCode, fmt.Errorf("%s returned %d", subscription.URL, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
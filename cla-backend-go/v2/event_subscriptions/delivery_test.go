@@ -0,0 +1,241 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_subscriptions
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// echo -n '1600000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=4e107d82910257d43758070322323c95b92af39939824d6610e2c9809a43b8d5", Sign("secret", 1600000000, []byte(`{"a":1}`)))
+	assert.NotEqual(t, Sign("secret", 1600000000, []byte(`{"a":1}`)), Sign("secret", 1600000001, []byte(`{"a":1}`)))
+	assert.NotEqual(t, Sign("secret", 1600000000, []byte(`{"a":1}`)), Sign("other", 1600000000, []byte(`{"a":1}`)))
+}
+
+func TestAttempt(t *testing.T) {
+	statusCode := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, Sign("secret", timestamp, body), r.Header.Get(SignatureHeader))
+		assert.Equal(t, "CLA Manager Created", r.Header.Get(EventTypeHeader))
+		assert.Equal(t, "d1", r.Header.Get(DeliveryHeader))
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	subscription := &DBEventSubscription{SubscriptionID: "s1", URL: server.URL, Secret: "secret", Enabled: true}
+	delivery := &DBEventDelivery{DeliveryID: "d1", SubscriptionID: "s1", EventType: "CLA Manager Created", Payload: `{"event":{}}`}
+
+	attempt(context.Background(), server.Client(), subscription, delivery)
+	assert.Equal(t, DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, pendingValue, delivery.Pending)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, delivery.LastStatusCode)
+	assert.NotZero(t, delivery.NextAttemptEpoch)
+
+	statusCode = http.StatusNoContent
+	attempt(context.Background(), server.Client(), subscription, delivery)
+	assert.Equal(t, DeliveryStatusDelivered, delivery.Status)
+	assert.Empty(t, delivery.Pending)
+	assert.Empty(t, delivery.LastError)
+	assert.NotEmpty(t, delivery.DateDelivered)
+
+	// The delivery fails once it used all its attempts
+	statusCode = http.StatusBadGateway
+	delivery.Attempts = MaxDeliveryAttempts - 1
+	attempt(context.Background(), server.Client(), subscription, delivery)
+	assert.Equal(t, DeliveryStatusFailed, delivery.Status)
+	assert.Empty(t, delivery.Pending)
+}
+
+func TestMatchesEventType(t *testing.T) {
+	assert.True(t, matchesEventType(&DBEventSubscription{}, "CLA Manager Created"))
+	assert.True(t, matchesEventType(&DBEventSubscription{EventTypes: []string{"CLA Manager Created"}}, "CLA Manager Created"))
+	assert.False(t, matchesEventType(&DBEventSubscription{EventTypes: []string{"CLA Manager Created"}}, "Invalidated Signature"))
+
+}
+
+func TestValidateURL(t *testing.T) {
+	defer func(lookup func(context.Context, string) ([]net.IPAddr, error)) { lookupIPAddr = lookup }(lookupIPAddr)
+	hosts := map[string]string{
+		"siem.example.org":     "203.0.113.10",
+		"internal.example.org": "10.1.2.3",
+		"metadata.example.org": "169.254.169.254",
+		"local.example.org":    "::1",
+		"ula.example.org":      "fd12::1",
+	}
+	lookupIPAddr = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		ip, ok := hosts[host]
+		if !ok {
+			return nil, errors.New("no such host")
+		}
+		return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+	}
+
+	testCases := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://siem.example.org/hooks", true},
+		{"https://203.0.113.10:8443/hooks", true},
+		{"http://siem.example.org/hooks", false},
+		{"https:///hooks", false},
+		{"https://unknown.example.org/hooks", false},
+		{"https://internal.example.org/hooks", false},
+		{"https://metadata.example.org/latest", false},
+		{"https://local.example.org/hooks", false},
+		{"https://ula.example.org/hooks", false},
+		{"https://127.0.0.1/hooks", false},
+		{"https://192.168.1.1/hooks", false},
+		{"https://[::ffff:172.16.0.1]/hooks", false},
+		{"https://0.0.0.0/hooks", false},
+	}
+	for _, tc := range testCases {
+		err := validateURL(context.Background(), tc.url)
+		if tc.valid {
+			assert.NoError(t, err, tc.url)
+		} else {
+			assert.True(t, errors.Is(err, ErrInvalidURL), tc.url)
+		}
+	}
+}
+
+func TestDeliveryClient(t *testing.T) {
+	redirected := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hooks", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/internal", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/internal", func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	})
+	mux.HandleFunc("/failing", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("stack trace with internal details")) // nolint
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	subscription := &DBEventSubscription{SubscriptionID: "s1", URL: server.URL + "/hooks", Secret: "secret", Enabled: true}
+
+	// the delivery client doesn't connect to the loopback address of the test server
+	delivery := &DBEventDelivery{DeliveryID: "d1", SubscriptionID: "s1", Payload: `{"event":{}}`}
+	attempt(context.Background(), newDeliveryClient(), subscription, delivery)
+	assert.Equal(t, DeliveryStatusPending, delivery.Status)
+	assert.Contains(t, delivery.LastError, "not a public address")
+
+	// the redirects are not followed, with a transport which connects to the test server
+	client := newDeliveryClient()
+	client.Transport = server.Client().Transport
+	delivery = &DBEventDelivery{DeliveryID: "d2", SubscriptionID: "s1", Payload: `{"event":{}}`}
+	attempt(context.Background(), client, subscription, delivery)
+	assert.Equal(t, DeliveryStatusPending, delivery.Status)
+	assert.Equal(t, http.StatusTemporaryRedirect, delivery.LastStatusCode)
+	assert.False(t, redirected)
+
+	// the response body is not kept
+	subscription.URL = server.URL + "/failing"
+	attempt(context.Background(), client, subscription, delivery)
+	assert.Equal(t, http.StatusInternalServerError, delivery.LastStatusCode)
+	assert.NotContains(t, delivery.LastError, "internal details")
+}
+
+// fakeRepository keeps the subscriptions and the deliveries in memory
+type fakeRepository struct {
+	Repository
+	subscriptions map[string]*DBEventSubscription
+	deliveries    map[string]*DBEventDelivery
+}
+
+func (r *fakeRepository) GetSubscription(ctx context.Context, subscriptionID string) (*DBEventSubscription, error) {
+	subscription, ok := r.subscriptions[subscriptionID]
+	if !ok {
+		return nil, ErrSubscriptionNotFound
+	}
+	return subscription, nil
+}
+
+func (r *fakeRepository) GetSubscriptionsByFoundation(ctx context.Context, foundationSFID string) ([]*DBEventSubscription, error) {
+	return nil, nil
+}
+
+func (r *fakeRepository) GetSubscriptionsByCLAGroup(ctx context.Context, claGroupID string) ([]*DBEventSubscription, error) {
+	var out []*DBEventSubscription
+	for _, subscription := range r.subscriptions {
+		if subscription.ClaGroupID == claGroupID {
+			out = append(out, subscription)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRepository) CreateDelivery(ctx context.Context, delivery *DBEventDelivery) error {
+	if _, ok := r.deliveries[delivery.DeliveryID]; ok {
+		return ErrDeliveryExists
+	}
+	copied := *delivery
+	r.deliveries[delivery.DeliveryID] = &copied
+	return nil
+}
+
+func (r *fakeRepository) SaveDelivery(ctx context.Context, delivery *DBEventDelivery) error {
+	copied := *delivery
+	r.deliveries[delivery.DeliveryID] = &copied
+	return nil
+}
+
+func (r *fakeRepository) GetDueDeliveries(ctx context.Context, epoch int64) ([]*DBEventDelivery, error) {
+	var out []*DBEventDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Pending == pendingValue && delivery.NextAttemptEpoch <= epoch {
+			copied := *delivery
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func TestDeliverEventOnlyRecordsTheDeliveries(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer server.Close()
+
+	repo := &fakeRepository{
+		subscriptions: map[string]*DBEventSubscription{
+			"s1": {SubscriptionID: "s1", ClaGroupID: "cla-group-1", URL: server.URL, Secret: "secret", Enabled: true},
+		},
+		deliveries: make(map[string]*DBEventDelivery),
+	}
+	s := &service{repo: repo, client: server.Client()}
+	event := &events.Event{EventID: "e1", EventType: "CLA Manager Created", EventProjectID: "cla-group-1"}
+
+	assert.NoError(t, s.DeliverEvent(context.Background(), event))
+	assert.NoError(t, s.DeliverEvent(context.Background(), event))
+	assert.Equal(t, 0, posts)
+	if assert.Len(t, repo.deliveries, 1) {
+		for _, delivery := range repo.deliveries {
+			assert.Equal(t, DeliveryStatusPending, delivery.Status)
+			assert.Equal(t, 0, delivery.Attempts)
+		}
+	}
+
+	delivered, err := s.RetryDeliveries(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, 1, posts)
+}
@@ -0,0 +1,318 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_subscriptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/event_subscriptions"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	v1Project "github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDeliveriesPageSize is the number of deliveries returned when the page size is not set
+const defaultDeliveriesPageSize = 50
+
+// errCLAGroupNotFound is returned when the CLA Group of the subscription does not exist
+var errCLAGroupNotFound = errors.New("CLA Group not found")
+
+// Configure setup the event subscription API handlers
+func Configure(api *operations.EasyclaAPI, service Service, projectService v1Project.Service) {
+	api.EventSubscriptionsCreateEventSubscriptionHandler = event_subscriptions.CreateEventSubscriptionHandlerFunc(func(params event_subscriptions.CreateEventSubscriptionParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsCreateEventSubscriptionHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"foundationSFID": params.Body.FoundationSFID,
+			"claGroupID":     params.Body.ClaGroupID,
+			"authUserName":   user.UserName,
+		}
+
+		authorized, err := isUserAuthorized(ctx, projectService, user, params.Body.FoundationSFID, params.Body.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the CLA Group of the subscription")
+			if errors.Is(err, errCLAGroupNotFound) {
+				return event_subscriptions.NewCreateEventSubscriptionNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return event_subscriptions.NewCreateEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !authorized {
+			return event_subscriptions.NewCreateEventSubscriptionForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Create Event Subscription"))
+		}
+
+		subscription, err := service.CreateSubscription(ctx, &params.Body, user.UserName)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem creating the event subscription")
+			if errors.Is(err, ErrInvalidScope) || errors.Is(err, ErrInvalidURL) {
+				return event_subscriptions.NewCreateEventSubscriptionBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewCreateEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return event_subscriptions.NewCreateEventSubscriptionOK().WithXRequestID(reqID).WithPayload(subscription)
+	})
+
+	api.EventSubscriptionsListEventSubscriptionsHandler = event_subscriptions.ListEventSubscriptionsHandlerFunc(func(params event_subscriptions.ListEventSubscriptionsParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		foundationSFID, claGroupID := aws.StringValue(params.FoundationSFID), aws.StringValue(params.ClaGroupID)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsListEventSubscriptionsHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"foundationSFID": foundationSFID,
+			"claGroupID":     claGroupID,
+			"authUserName":   user.UserName,
+		}
+
+		if (foundationSFID == "") == (claGroupID == "") {
+			return event_subscriptions.NewListEventSubscriptionsBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, ErrInvalidScope))
+		}
+		authorized, err := isUserAuthorized(ctx, projectService, user, foundationSFID, claGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the CLA Group of the subscriptions")
+			if errors.Is(err, errCLAGroupNotFound) {
+				return event_subscriptions.NewListEventSubscriptionsNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return event_subscriptions.NewListEventSubscriptionsInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !authorized {
+			return event_subscriptions.NewListEventSubscriptionsForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "List Event Subscriptions"))
+		}
+
+		subscriptions, err := service.GetSubscriptions(ctx, foundationSFID, claGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event subscriptions")
+			return event_subscriptions.NewListEventSubscriptionsInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return event_subscriptions.NewListEventSubscriptionsOK().WithXRequestID(reqID).WithPayload(subscriptions)
+	})
+
+	api.EventSubscriptionsGetEventSubscriptionHandler = event_subscriptions.GetEventSubscriptionHandlerFunc(func(params event_subscriptions.GetEventSubscriptionParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsGetEventSubscriptionHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"subscriptionID": params.SubscriptionID,
+			"authUserName":   user.UserName,
+		}
+
+		subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event subscription")
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return event_subscriptions.NewGetEventSubscriptionNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewGetEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorizedForSubscription(ctx, projectService, user, subscription) {
+			return event_subscriptions.NewGetEventSubscriptionForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Get Event Subscription"))
+		}
+		return event_subscriptions.NewGetEventSubscriptionOK().WithXRequestID(reqID).WithPayload(subscription)
+	})
+
+	api.EventSubscriptionsUpdateEventSubscriptionHandler = event_subscriptions.UpdateEventSubscriptionHandlerFunc(func(params event_subscriptions.UpdateEventSubscriptionParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsUpdateEventSubscriptionHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"subscriptionID": params.SubscriptionID,
+			"authUserName":   user.UserName,
+		}
+
+		subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event subscription")
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return event_subscriptions.NewUpdateEventSubscriptionNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewUpdateEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorizedForSubscription(ctx, projectService, user, subscription) {
+			return event_subscriptions.NewUpdateEventSubscriptionForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Update Event Subscription"))
+		}
+
+		subscription, err = service.UpdateSubscription(ctx, params.SubscriptionID, &params.Body)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem updating the event subscription")
+			if errors.Is(err, ErrInvalidURL) {
+				return event_subscriptions.NewUpdateEventSubscriptionBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewUpdateEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return event_subscriptions.NewUpdateEventSubscriptionOK().WithXRequestID(reqID).WithPayload(subscription)
+	})
+
+	api.EventSubscriptionsDeleteEventSubscriptionHandler = event_subscriptions.DeleteEventSubscriptionHandlerFunc(func(params event_subscriptions.DeleteEventSubscriptionParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsDeleteEventSubscriptionHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"subscriptionID": params.SubscriptionID,
+			"authUserName":   user.UserName,
+		}
+
+		subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event subscription")
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return event_subscriptions.NewDeleteEventSubscriptionNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewDeleteEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorizedForSubscription(ctx, projectService, user, subscription) {
+			return event_subscriptions.NewDeleteEventSubscriptionForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Delete Event Subscription"))
+		}
+
+		err = service.DeleteSubscription(ctx, params.SubscriptionID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem deleting the event subscription")
+			return event_subscriptions.NewDeleteEventSubscriptionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return event_subscriptions.NewDeleteEventSubscriptionNoContent().WithXRequestID(reqID)
+	})
+
+	api.EventSubscriptionsListEventSubscriptionDeliveriesHandler = event_subscriptions.ListEventSubscriptionDeliveriesHandlerFunc(func(params event_subscriptions.ListEventSubscriptionDeliveriesParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsListEventSubscriptionDeliveriesHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"subscriptionID": params.SubscriptionID,
+			"authUserName":   user.UserName,
+		}
+
+		subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event subscription")
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return event_subscriptions.NewListEventSubscriptionDeliveriesNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewListEventSubscriptionDeliveriesInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorizedForSubscription(ctx, projectService, user, subscription) {
+			return event_subscriptions.NewListEventSubscriptionDeliveriesForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "List Event Subscription Deliveries"))
+		}
+
+		pageSize := int64(defaultDeliveriesPageSize)
+		if params.PageSize != nil {
+			pageSize = *params.PageSize
+		}
+		deliveries, err := service.GetDeliveries(ctx, params.SubscriptionID, aws.StringValue(params.Status), pageSize)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event deliveries")
+			return event_subscriptions.NewListEventSubscriptionDeliveriesInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return event_subscriptions.NewListEventSubscriptionDeliveriesOK().WithXRequestID(reqID).WithPayload(deliveries)
+	})
+
+	api.EventSubscriptionsRedeliverEventSubscriptionDeliveryHandler = event_subscriptions.RedeliverEventSubscriptionDeliveryHandlerFunc(func(params event_subscriptions.RedeliverEventSubscriptionDeliveryParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "EventSubscriptionsRedeliverEventSubscriptionDeliveryHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"subscriptionID": params.SubscriptionID,
+			"deliveryID":     params.DeliveryID,
+			"authUserName":   user.UserName,
+		}
+
+		subscription, err := service.GetSubscription(ctx, params.SubscriptionID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the event subscription")
+			if errors.Is(err, ErrSubscriptionNotFound) {
+				return event_subscriptions.NewRedeliverEventSubscriptionDeliveryNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewRedeliverEventSubscriptionDeliveryInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorizedForSubscription(ctx, projectService, user, subscription) {
+			return event_subscriptions.NewRedeliverEventSubscriptionDeliveryForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Redeliver Event"))
+		}
+
+		delivery, err := service.Redeliver(ctx, params.SubscriptionID, params.DeliveryID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem redelivering the event")
+			if errors.Is(err, ErrDeliveryNotFound) {
+				return event_subscriptions.NewRedeliverEventSubscriptionDeliveryNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return event_subscriptions.NewRedeliverEventSubscriptionDeliveryInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return event_subscriptions.NewRedeliverEventSubscriptionDeliveryOK().WithXRequestID(reqID).WithPayload(delivery)
+	})
+}
+
+// isUserAuthorized returns true if the user is an admin or has access to the Foundation of the subscription - the
+// Foundation of a CLA Group subscription is the Foundation of the CLA Group
+func isUserAuthorized(ctx context.Context, projectService v1Project.Service, user *auth.User, foundationSFID, claGroupID string) (bool, error) {
+	if utils.IsUserAdmin(user) {
+		return true, nil
+	}
+	if claGroupID != "" {
+		claGroup, err := projectService.GetCLAGroupByID(ctx, claGroupID)
+		if err != nil {
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return false, errCLAGroupNotFound
+			}
+			return false, err
+		}
+		foundationSFID = claGroup.FoundationSFID
+	}
+	return foundationSFID != "" && utils.IsUserAuthorizedForProjectTree(user, foundationSFID, utils.ALLOW_ADMIN_SCOPE), nil
+}
+
+// isUserAuthorizedForSubscription returns true if the user is authorized for the scope of the subscription, only
+// the admins are authorized for the subscriptions of the deleted CLA Groups
+func isUserAuthorizedForSubscription(ctx context.Context, projectService v1Project.Service, user *auth.User, subscription *models.EventSubscription) bool {
+	authorized, err := isUserAuthorized(ctx, projectService, user, subscription.FoundationSFID, subscription.ClaGroupID)
+	if err != nil {
+		log.WithField("subscriptionID", subscription.SubscriptionID).WithError(err).Warn("problem loading the CLA Group of the subscription")
+		return false
+	}
+	return authorized
+}
+
+func forbiddenResponse(reqID string, user *auth.User, action string) *models.ErrorResponse {
+	return &models.ErrorResponse{
+		Code:       "403",
+		Message:    fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to %s.", user.UserName, action),
+		XRequestID: reqID,
+	}
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(reqID string, err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:       code,
+		Message:    err.Error(),
+		XRequestID: reqID,
+	}
+
+	return &e
+}
@@ -0,0 +1,75 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_subscriptions
+
+// delivery status values
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// DBEventSubscription is the data model of a webhook subscription to the events of a Foundation or of a CLA Group -
+// exactly one of FoundationSFID and ClaGroupID is set, so each subscription is only in the index of its scope
+type DBEventSubscription struct {
+	SubscriptionID string   `dynamodbav:"subscription_id"`
+	FoundationSFID string   `dynamodbav:"foundation_sfid,omitempty"`
+	ClaGroupID     string   `dynamodbav:"cla_group_id,omitempty"`
+	URL            string   `dynamodbav:"url"`
+	EventTypes     []string `dynamodbav:"event_types,stringset,omitempty"`
+	Description    string   `dynamodbav:"description,omitempty"`
+	Enabled        bool     `dynamodbav:"enabled"`
+	Secret         string   `dynamodbav:"secret"`
+	CreatedBy      string   `dynamodbav:"created_by"`
+	DateCreated    string   `dynamodbav:"date_created"`
+	DateModified   string   `dynamodbav:"date_modified"`
+}
+
+// DBEventDelivery is the delivery of an event to a subscription. The payload is kept so the retries and the
+// redeliveries send the same event - Pending is only set while the delivery is pending, so the pending index only
+// holds the deliveries which are retried.
+type DBEventDelivery struct {
+	DeliveryID       string `dynamodbav:"delivery_id"`
+	SubscriptionID   string `dynamodbav:"subscription_id"`
+	EventID          string `dynamodbav:"event_id"`
+	EventType        string `dynamodbav:"event_type"`
+	Payload          string `dynamodbav:"payload"`
+	Status           string `dynamodbav:"delivery_status"`
+	Pending          string `dynamodbav:"pending,omitempty"`
+	Attempts         int    `dynamodbav:"attempts"`
+	LastStatusCode   int    `dynamodbav:"last_status_code"`
+	LastError        string `dynamodbav:"last_error,omitempty"`
+	NextAttemptEpoch int64  `dynamodbav:"next_attempt_epoch"`
+	DateDelivered    string `dynamodbav:"date_delivered,omitempty"`
+	DateCreated      string `dynamodbav:"date_created"`
+	DateModified     string `dynamodbav:"date_modified"`
+}
+
+// EventPayload is the JSON body posted to the subscriptions
+type EventPayload struct {
+	DeliveryID     string        `json:"delivery_id"`
+	SubscriptionID string        `json:"subscription_id"`
+	Event          EventSnapshot `json:"event"`
+}
+
+// EventSnapshot is the audit event as it was recorded
+type EventSnapshot struct {
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	EventTime      string `json:"event_time"`
+	EventTimeEpoch int64  `json:"event_time_epoch"`
+	EventSummary   string `json:"event_summary"`
+	EventData      string `json:"event_data"`
+	UserID         string `json:"user_id,omitempty"`
+	UserName       string `json:"user_name,omitempty"`
+	LfUsername     string `json:"lf_username,omitempty"`
+	ClaGroupID     string `json:"cla_group_id,omitempty"`
+	ClaGroupName   string `json:"cla_group_name,omitempty"`
+	FoundationSFID string `json:"foundation_sfid,omitempty"`
+	ProjectSFID    string `json:"project_sfid,omitempty"`
+	ProjectSFName  string `json:"project_sf_name,omitempty"`
+	CompanyID      string `json:"company_id,omitempty"`
+	CompanyName    string `json:"company_name,omitempty"`
+	CompanySFID    string `json:"company_sfid,omitempty"`
+}
@@ -0,0 +1,380 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_subscriptions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrSubscriptionNotFound = errors.New("event subscription not found")
+	ErrDeliveryNotFound     = errors.New("event delivery not found")
+	ErrDeliveryExists       = errors.New("event delivery already exists")
+)
+
+// pendingValue is the value of the pending attribute of the pending deliveries
+const pendingValue = "true"
+
+// Repository stores the event subscriptions and their deliveries
+type Repository interface {
+	SaveSubscription(ctx context.Context, subscription *DBEventSubscription) error
+	GetSubscription(ctx context.Context, subscriptionID string) (*DBEventSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	GetSubscriptionsByFoundation(ctx context.Context, foundationSFID string) ([]*DBEventSubscription, error)
+	GetSubscriptionsByCLAGroup(ctx context.Context, claGroupID string) ([]*DBEventSubscription, error)
+
+	CreateDelivery(ctx context.Context, delivery *DBEventDelivery) error
+	SaveDelivery(ctx context.Context, delivery *DBEventDelivery) error
+	GetDelivery(ctx context.Context, deliveryID string) (*DBEventDelivery, error)
+	GetDeliveriesBySubscription(ctx context.Context, subscriptionID string, pageSize int64) ([]*DBEventDelivery, error)
+	GetDueDeliveries(ctx context.Context, epoch int64) ([]*DBEventDelivery, error)
+}
+
+// NewRepository creates a repository backed by the cla-<stage>-event-subscriptions and cla-<stage>-event-deliveries
+// tables
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
+	return &repo{
+		stage:                  stage,
+		dynamoDBClient:         dynamoDBClient,
+		subscriptionsTableName: fmt.Sprintf("cla-%s-event-subscriptions", stage),
+		deliveriesTableName:    fmt.Sprintf("cla-%s-event-deliveries", stage),
+	}
+}
+
+type repo struct {
+	stage                  string
+	dynamoDBClient         storage.Driver
+	subscriptionsTableName string
+	deliveriesTableName    string
+}
+
+// SaveSubscription creates or replaces the event subscription
+func (r *repo) SaveSubscription(ctx context.Context, subscription *DBEventSubscription) error {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.SaveSubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscription.SubscriptionID,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(subscription)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the event subscription")
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(r.subscriptionsTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to save the event subscription")
+		return err
+	}
+
+	return nil
+}
+
+// GetSubscription returns the event subscription
+func (r *repo) GetSubscription(ctx context.Context, subscriptionID string) (*DBEventSubscription, error) {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.GetSubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscriptionID,
+	}
+
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.subscriptionsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"subscription_id": {S: aws.String(subscriptionID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the event subscription")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	var subscription DBEventSubscription
+	err = dynamodbattribute.UnmarshalMap(result.Item, &subscription)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the event subscription")
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// DeleteSubscription deletes the event subscription, its deliveries are kept as the delivery log
+func (r *repo) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.DeleteSubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscriptionID,
+	}
+
+	_, err := r.dynamoDBClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.subscriptionsTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"subscription_id": {S: aws.String(subscriptionID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to delete the event subscription")
+		return err
+	}
+
+	return nil
+}
+
+// GetSubscriptionsByFoundation returns the subscriptions to the events of the Foundation
+func (r *repo) GetSubscriptionsByFoundation(ctx context.Context, foundationSFID string) ([]*DBEventSubscription, error) {
+	return r.querySubscriptions(ctx, "foundation-sfid-index", expression.Key("foundation_sfid").Equal(expression.Value(foundationSFID)))
+}
+
+// GetSubscriptionsByCLAGroup returns the subscriptions to the events of the CLA Group
+func (r *repo) GetSubscriptionsByCLAGroup(ctx context.Context, claGroupID string) ([]*DBEventSubscription, error) {
+	return r.querySubscriptions(ctx, "cla-group-id-index", expression.Key("cla_group_id").Equal(expression.Value(claGroupID)))
+}
+
+func (r *repo) querySubscriptions(ctx context.Context, indexName string, condition expression.KeyConditionBuilder) ([]*DBEventSubscription, error) {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.querySubscriptions",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"indexName":      indexName,
+	}
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the query expression")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(r.subscriptionsTableName),
+		IndexName:                 aws.String(indexName),
+	}
+
+	var subscriptions []*DBEventSubscription
+	for {
+		results, queryErr := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if queryErr != nil {
+			log.WithFields(f).WithError(queryErr).Warn("unable to query the event subscriptions")
+			return nil, queryErr
+		}
+
+		var items []*DBEventSubscription
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem unmarshalling the event subscriptions")
+			return nil, err
+		}
+		subscriptions = append(subscriptions, items...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return subscriptions, nil
+}
+
+// CreateDelivery creates the delivery, returns ErrDeliveryExists when the delivery was already created - e.g. when
+// the stream record of the event is processed again
+func (r *repo) CreateDelivery(ctx context.Context, delivery *DBEventDelivery) error {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.CreateDelivery",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"deliveryID":     delivery.DeliveryID,
+		"subscriptionID": delivery.SubscriptionID,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(delivery)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the event delivery")
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:                av,
+		TableName:           aws.String(r.deliveriesTableName),
+		ConditionExpression: aws.String("attribute_not_exists(delivery_id)"),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrDeliveryExists
+		}
+		log.WithFields(f).WithError(err).Warn("unable to create the event delivery")
+		return err
+	}
+
+	return nil
+}
+
+// SaveDelivery replaces the event delivery
+func (r *repo) SaveDelivery(ctx context.Context, delivery *DBEventDelivery) error {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.SaveDelivery",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"deliveryID":     delivery.DeliveryID,
+		"subscriptionID": delivery.SubscriptionID,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(delivery)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the event delivery")
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(r.deliveriesTableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to save the event delivery")
+		return err
+	}
+
+	return nil
+}
+
+// GetDelivery returns the event delivery
+func (r *repo) GetDelivery(ctx context.Context, deliveryID string) (*DBEventDelivery, error) {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.GetDelivery",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"deliveryID":     deliveryID,
+	}
+
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.deliveriesTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"delivery_id": {S: aws.String(deliveryID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the event delivery")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrDeliveryNotFound
+	}
+
+	var delivery DBEventDelivery
+	err = dynamodbattribute.UnmarshalMap(result.Item, &delivery)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the event delivery")
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// GetDeliveriesBySubscription returns the most recent deliveries of the subscription
+func (r *repo) GetDeliveriesBySubscription(ctx context.Context, subscriptionID string, pageSize int64) ([]*DBEventDelivery, error) {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.GetDeliveriesBySubscription",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"subscriptionID": subscriptionID,
+		"pageSize":       pageSize,
+	}
+
+	condition := expression.Key("subscription_id").Equal(expression.Value(subscriptionID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the query expression")
+		return nil, err
+	}
+
+	results, err := r.dynamoDBClient.QueryWithContext(ctx, &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(r.deliveriesTableName),
+		IndexName:                 aws.String("subscription-id-date-index"),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int64(pageSize),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to query the event deliveries")
+		return nil, err
+	}
+
+	var deliveries []*DBEventDelivery
+	err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &deliveries)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the event deliveries")
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetDueDeliveries returns the pending deliveries which are due for their next attempt at the epoch
+func (r *repo) GetDueDeliveries(ctx context.Context, epoch int64) ([]*DBEventDelivery, error) {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.GetDueDeliveries",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"epoch":          strconv.FormatInt(epoch, 10),
+	}
+
+	condition := expression.Key("pending").Equal(expression.Value(pendingValue)).
+		And(expression.Key("next_attempt_epoch").LessThanEqual(expression.Value(epoch)))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the query expression")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(r.deliveriesTableName),
+		IndexName:                 aws.String("pending-next-attempt-index"),
+	}
+
+	var deliveries []*DBEventDelivery
+	for {
+		results, queryErr := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if queryErr != nil {
+			log.WithFields(f).WithError(queryErr).Warn("unable to query the pending event deliveries")
+			return nil, queryErr
+		}
+
+		var items []*DBEventDelivery
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem unmarshalling the pending event deliveries")
+			return nil, err
+		}
+		deliveries = append(deliveries, items...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return deliveries, nil
+}
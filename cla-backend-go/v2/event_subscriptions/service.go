@@ -0,0 +1,448 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package event_subscriptions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrInvalidScope = errors.New("exactly one of foundationSFID or claGroupID is required")
+	ErrInvalidURL   = errors.New("the subscription URL must be an absolute https URL of a public host")
+)
+
+// lookupIPAddr resolves the host of a subscription URL
+var lookupIPAddr = net.DefaultResolver.LookupIPAddr
+
+// Service manages the event subscriptions and delivers the audit events to them
+type Service interface {
+	CreateSubscription(ctx context.Context, input *models.EventSubscriptionInput, lfUsername string) (*models.EventSubscription, error)
+	GetSubscription(ctx context.Context, subscriptionID string) (*models.EventSubscription, error)
+	GetSubscriptions(ctx context.Context, foundationSFID, claGroupID string) ([]*models.EventSubscription, error)
+	UpdateSubscription(ctx context.Context, subscriptionID string, input *models.EventSubscriptionUpdateInput) (*models.EventSubscription, error)
+	DeleteSubscription(ctx context.Context, subscriptionID string) error
+	GetDeliveries(ctx context.Context, subscriptionID, status string, pageSize int64) ([]*models.EventSubscriptionDelivery, error)
+	Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*models.EventSubscriptionDelivery, error)
+
+	DeliverEvent(ctx context.Context, event *events.Event) error
+	RetryDeliveries(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo   Repository
+	client *http.Client
+}
+
+// NewService creates a new event subscription service
+func NewService(repo Repository) Service {
+	return &service{
+		repo:   repo,
+		client: newDeliveryClient(),
+	}
+}
+
+// CreateSubscription creates an enabled subscription unless the input disables it, the generated secret is only
+// returned by this call
+func (s *service) CreateSubscription(ctx context.Context, input *models.EventSubscriptionInput, lfUsername string) (*models.EventSubscription, error) {
+	if (input.FoundationSFID == "") == (input.ClaGroupID == "") {
+		return nil, ErrInvalidScope
+	}
+	subscriptionURL := ""
+	if input.URL != nil {
+		subscriptionURL = *input.URL
+	}
+	if err := validateURL(ctx, subscriptionURL); err != nil {
+		return nil, err
+	}
+
+	subscriptionID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	_, now := utils.CurrentTime()
+	subscription := &DBEventSubscription{
+		SubscriptionID: subscriptionID.String(),
+		FoundationSFID: input.FoundationSFID,
+		ClaGroupID:     input.ClaGroupID,
+		URL:            subscriptionURL,
+		EventTypes:     input.EventTypes,
+		Description:    input.Description,
+		Enabled:        input.Enabled == nil || *input.Enabled,
+		Secret:         secret,
+		CreatedBy:      lfUsername,
+		DateCreated:    now,
+		DateModified:   now,
+	}
+	if err := s.repo.SaveSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return toSubscriptionModel(subscription, true), nil
+}
+
+// GetSubscription returns the subscription without its secret
+func (s *service) GetSubscription(ctx context.Context, subscriptionID string) (*models.EventSubscription, error) {
+	subscription, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return toSubscriptionModel(subscription, false), nil
+}
+
+// GetSubscriptions returns the subscriptions of the Foundation or of the CLA Group, without their secrets
+func (s *service) GetSubscriptions(ctx context.Context, foundationSFID, claGroupID string) ([]*models.EventSubscription, error) {
+	if (foundationSFID == "") == (claGroupID == "") {
+		return nil, ErrInvalidScope
+	}
+
+	var subscriptions []*DBEventSubscription
+	var err error
+	if foundationSFID != "" {
+		subscriptions, err = s.repo.GetSubscriptionsByFoundation(ctx, foundationSFID)
+	} else {
+		subscriptions, err = s.repo.GetSubscriptionsByCLAGroup(ctx, claGroupID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*models.EventSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		out = append(out, toSubscriptionModel(subscription, false))
+	}
+	return out, nil
+}
+
+// UpdateSubscription applies the properties of the input which are set, the secret is only returned when it was
+// rotated
+func (s *service) UpdateSubscription(ctx context.Context, subscriptionID string, input *models.EventSubscriptionUpdateInput) (*models.EventSubscription, error) {
+	subscription, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.URL != "" {
+		if err := validateURL(ctx, input.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = input.URL
+	}
+	if input.EventTypes != nil {
+		subscription.EventTypes = input.EventTypes
+	}
+	if input.Description != "" {
+		subscription.Description = input.Description
+	}
+	if input.Enabled != nil {
+		subscription.Enabled = *input.Enabled
+	}
+	if input.RotateSecret {
+		subscription.Secret, err = newSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+	_, subscription.DateModified = utils.CurrentTime()
+
+	if err := s.repo.SaveSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return toSubscriptionModel(subscription, input.RotateSecret), nil
+}
+
+// DeleteSubscription deletes the subscription, its pending deliveries are no longer retried
+func (s *service) DeleteSubscription(ctx context.Context, subscriptionID string) error {
+	if _, err := s.repo.GetSubscription(ctx, subscriptionID); err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(ctx, subscriptionID)
+}
+
+// GetDeliveries returns the most recent deliveries of the subscription, optionally only those with the status
+func (s *service) GetDeliveries(ctx context.Context, subscriptionID, status string, pageSize int64) ([]*models.EventSubscriptionDelivery, error) {
+	deliveries, err := s.repo.GetDeliveriesBySubscription(ctx, subscriptionID, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*models.EventSubscriptionDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		if status != "" && delivery.Status != status {
+			continue
+		}
+		out = append(out, toDeliveryModel(delivery))
+	}
+	return out, nil
+}
+
+// Redeliver sends the event of the delivery again with a new set of attempts
+func (s *service) Redeliver(ctx context.Context, subscriptionID, deliveryID string) (*models.EventSubscriptionDelivery, error) {
+	subscription, err := s.repo.GetSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.SubscriptionID != subscriptionID {
+		return nil, ErrDeliveryNotFound
+	}
+
+	delivery.Attempts = 0
+	attempt(ctx, s.client, subscription, delivery)
+	if err := s.repo.SaveDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return toDeliveryModel(delivery), nil
+}
+
+// DeliverEvent records the deliveries of the event to the enabled subscriptions of its Foundation and of its CLA
+// Group, they are sent by RetryDeliveries so a slow subscriber doesn't hold up the processing of the stream - an error
+// is only returned when a delivery could not be recorded, the deliveries which were already recorded are not recorded
+// again when the event is processed again.
+func (s *service) DeliverEvent(ctx context.Context, event *events.Event) error {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.DeliverEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"eventID":        event.EventID,
+		"eventType":      event.EventType,
+		"foundationSFID": event.EventFoundationSFID,
+		"claGroupID":     event.EventProjectID,
+	}
+
+	var subscriptions []*DBEventSubscription
+	if event.EventFoundationSFID != "" {
+		foundationSubscriptions, err := s.repo.GetSubscriptionsByFoundation(ctx, event.EventFoundationSFID)
+		if err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, foundationSubscriptions...)
+	}
+	if event.EventProjectID != "" {
+		claGroupSubscriptions, err := s.repo.GetSubscriptionsByCLAGroup(ctx, event.EventProjectID)
+		if err != nil {
+			return err
+		}
+		subscriptions = append(subscriptions, claGroupSubscriptions...)
+	}
+
+	var firstErr error
+	for _, subscription := range subscriptions {
+		if !subscription.Enabled || !matchesEventType(subscription, event.EventType) {
+			continue
+		}
+		if err := s.deliver(ctx, subscription, event); err != nil {
+			log.WithFields(f).WithField("subscriptionID", subscription.SubscriptionID).WithError(err).Warn("unable to record the event delivery")
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// deliver records the delivery of the event to the subscription, due for its first attempt
+func (s *service) deliver(ctx context.Context, subscription *DBEventSubscription, event *events.Event) error {
+	// The delivery ID is derived from the subscription and the event, so a delivery is only recorded once
+	deliveryID := uuid.NewV5(uuid.NamespaceOID, subscription.SubscriptionID+":"+event.EventID).String()
+	payload, err := json.Marshal(EventPayload{
+		DeliveryID:     deliveryID,
+		SubscriptionID: subscription.SubscriptionID,
+		Event:          toEventSnapshot(event),
+	})
+	if err != nil {
+		return err
+	}
+
+	currentTime, now := utils.CurrentTime()
+	delivery := &DBEventDelivery{
+		DeliveryID:       deliveryID,
+		SubscriptionID:   subscription.SubscriptionID,
+		EventID:          event.EventID,
+		EventType:        event.EventType,
+		Payload:          string(payload),
+		Status:           DeliveryStatusPending,
+		Pending:          pendingValue,
+		NextAttemptEpoch: currentTime.Unix(),
+		DateCreated:      now,
+		DateModified:     now,
+	}
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil && !errors.Is(err, ErrDeliveryExists) {
+		return err
+	}
+	return nil
+}
+
+// RetryDeliveries retries the pending deliveries which are due, returns the number of events delivered
+func (s *service) RetryDeliveries(ctx context.Context) (int, error) {
+	f := logrus.Fields{
+		"functionName":   "event_subscriptions.RetryDeliveries",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+	}
+
+	deliveries, err := s.repo.GetDueDeliveries(ctx, time.Now().Unix())
+	if err != nil {
+		return 0, err
+	}
+
+	subscriptions := make(map[string]*DBEventSubscription)
+	delivered := 0
+	for _, delivery := range deliveries {
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = s.repo.GetSubscription(ctx, delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, ErrSubscriptionNotFound) {
+				log.WithFields(f).WithField("subscriptionID", delivery.SubscriptionID).WithError(err).Warn("unable to load the subscription of the delivery")
+				continue
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if subscription == nil || !subscription.Enabled {
+			// The deliveries of the deleted or disabled subscriptions are not sent
+			_, currentTime := utils.CurrentTime()
+			delivery.Status = DeliveryStatusFailed
+			delivery.Pending = ""
+			delivery.NextAttemptEpoch = 0
+			delivery.LastError = "the subscription was deleted or disabled"
+			delivery.DateModified = currentTime
+		} else {
+			attempt(ctx, s.client, subscription, delivery)
+		}
+
+		if err := s.repo.SaveDelivery(ctx, delivery); err != nil {
+			log.WithFields(f).WithField("deliveryID", delivery.DeliveryID).WithError(err).Warn("unable to save the event delivery")
+			continue
+		}
+		if delivery.Status == DeliveryStatusDelivered {
+			delivered++
+		}
+	}
+
+	log.WithFields(f).Debugf("retried %d event deliveries, %d delivered", len(deliveries), delivered)
+	return delivered, nil
+}
+
+// matchesEventType returns true if the subscription has no event type filter or the filter includes the event type
+func matchesEventType(subscription *DBEventSubscription, eventType string) bool {
+	if len(subscription.EventTypes) == 0 {
+		return true
+	}
+	for _, subscribed := range subscription.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// validateURL only accepts absolute https URLs, the events include the names and emails of the signers, whose host
+// only resolves to public addresses - the deliveries must not reach the internal services
+func validateURL(ctx context.Context, subscriptionURL string) error {
+	u, err := url.Parse(subscriptionURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+
+	var ips []net.IP
+	if ip := net.ParseIP(u.Hostname()); ip != nil {
+		ips = append(ips, ip)
+	} else {
+		addrs, lookupErr := lookupIPAddr(ctx, u.Hostname())
+		if lookupErr != nil || len(addrs) == 0 {
+			return fmt.Errorf("%w: unable to resolve %s", ErrInvalidURL, u.Hostname())
+		}
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if !publicIP(ip) {
+			return fmt.Errorf("%w: %s resolves to the address %s which is not public", ErrInvalidURL, u.Hostname(), ip)
+		}
+	}
+	return nil
+}
+
+func toSubscriptionModel(subscription *DBEventSubscription, includeSecret bool) *models.EventSubscription {
+	out := &models.EventSubscription{
+		SubscriptionID: subscription.SubscriptionID,
+		FoundationSFID: subscription.FoundationSFID,
+		ClaGroupID:     subscription.ClaGroupID,
+		URL:            subscription.URL,
+		EventTypes:     subscription.EventTypes,
+		Description:    subscription.Description,
+		Enabled:        subscription.Enabled,
+		CreatedBy:      subscription.CreatedBy,
+		DateCreated:    subscription.DateCreated,
+		DateModified:   subscription.DateModified,
+	}
+	if includeSecret {
+		out.Secret = subscription.Secret
+	}
+	return out
+}
+
+func toDeliveryModel(delivery *DBEventDelivery) *models.EventSubscriptionDelivery {
+	out := &models.EventSubscriptionDelivery{
+		DeliveryID:     delivery.DeliveryID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       int64(delivery.Attempts),
+		LastStatusCode: int64(delivery.LastStatusCode),
+		LastError:      delivery.LastError,
+		DateDelivered:  delivery.DateDelivered,
+		DateCreated:    delivery.DateCreated,
+		DateModified:   delivery.DateModified,
+	}
+	if delivery.Status == DeliveryStatusPending && delivery.NextAttemptEpoch > 0 {
+		out.NextAttempt = utils.TimeToString(time.Unix(delivery.NextAttemptEpoch, 0))
+	}
+	return out
+}
+
+func toEventSnapshot(event *events.Event) EventSnapshot {
+	return EventSnapshot{
+		EventID:        event.EventID,
+		EventType:      event.EventType,
+		EventTime:      event.EventTime,
+		EventTimeEpoch: event.EventTimeEpoch,
+		EventSummary:   event.EventSummary,
+		EventData:      event.EventData,
+		UserID:         event.EventUserID,
+		UserName:       event.EventUserName,
+		LfUsername:     event.EventLfUsername,
+		ClaGroupID:     event.EventProjectID,
+		ClaGroupName:   event.EventProjectName,
+		FoundationSFID: event.EventFoundationSFID,
+		ProjectSFID:    event.EventProjectSFID,
+		ProjectSFName:  event.EventSFProjectName,
+		CompanyID:      event.EventCompanyID,
+		CompanyName:    event.EventCompanyName,
+		CompanySFID:    event.EventCompanySFID,
+	}
+}
@@ -5,11 +5,15 @@ package github_activity
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 
+	"github.com/communitybridge/easycla/cla-backend-go/events"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/sirupsen/logrus"
 
 	"github.com/google/go-github/v32/github"
 
@@ -17,28 +21,102 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/github_activity"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/communitybridge/easycla/cla-backend-go/v2/check_runs"
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gofrs/uuid"
 )
 
 // signatureCheckMiddleware is used to get access to raw http request so can do the
 // signature validation properly
-func signatureCheckMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		payload, err := github.ValidatePayload(r, nil)
-		if err != nil {
-			http.Error(w, "signature check failure", 401)
-			return
-		}
-		defer r.Body.Close()
-		r.Body = ioutil.NopCloser(bytes.NewBuffer(payload))
-		// call the next middleware
-		next.ServeHTTP(w, r)
+func signatureCheckMiddleware(webhookSecrets []string, deliveryStore DeliveryStore, eventService events.Service) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			f := logrus.Fields{
+				"functionName":   "github_activity.signatureCheckMiddleware",
+				utils.XREQUESTID: r.Header.Get(utils.XREQUESTID),
+				"deliveryID":     r.Header.Get(DeliveryIDHeader),
+				"githubEvent":    r.Header.Get(EventHeader),
+			}
+
+			payload, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				log.WithFields(f).WithError(err).Warn("unable to read the webhook payload")
+				http.Error(w, "unable to read payload", http.StatusBadRequest)
+				return
+			}
+			defer r.Body.Close()
+
+			if err := ValidateSignature(r.Header.Get(SignatureSHA256Header), payload, webhookSecrets); err != nil {
+				log.WithFields(f).WithError(err).Warn("webhook signature check failed")
+				logDeliveryRejected(eventService, r, err)
+				http.Error(w, "signature check failure", http.StatusUnauthorized)
+				return
+			}
+
+			// the delivery is recorded before it is processed so a concurrent replay is rejected as well
+			deliveryID := r.Header.Get(DeliveryIDHeader)
+			if err := deliveryStore.MarkDelivered(deliveryID); err != nil {
+				log.WithFields(f).WithError(err).Warn("webhook delivery check failed")
+				if errors.Is(err, ErrReplayedDelivery) || errors.Is(err, ErrMissingDeliveryID) {
+					logDeliveryRejected(eventService, r, err)
+					http.Error(w, "delivery check failure", http.StatusUnauthorized)
+					return
+				}
+				// we don't want to drop valid deliveries because of a storage issue
+			}
+
+			r.Body = ioutil.NopCloser(bytes.NewBuffer(payload))
+			// call the next middleware, a delivery which failed is forgotten so its redelivery is processed
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				if p := recover(); p != nil {
+					forgetDelivery(f, deliveryStore, deliveryID)
+					panic(p)
+				}
+				if recorder.status >= http.StatusInternalServerError {
+					forgetDelivery(f, deliveryStore, deliveryID)
+				}
+			}()
+			next.ServeHTTP(recorder, r)
+		})
+	}
+}
+
+// statusRecorder keeps the status code of the response written by the next handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// forgetDelivery removes the delivery id of a delivery which wasn't processed
+func forgetDelivery(f logrus.Fields, deliveryStore DeliveryStore, deliveryID string) {
+	if err := deliveryStore.ForgetDelivery(deliveryID); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to forget the failed webhook delivery, its redelivery will be rejected")
+	}
+}
+
+// logDeliveryRejected logs an audit event for the rejected webhook delivery
+func logDeliveryRejected(eventService events.Service, r *http.Request, reason error) {
+	eventService.LogEvent(&events.LogEventArgs{
+		EventType: events.GithubWebhookRejected,
+		UserID:    "easycla system",
+		EventData: &events.GithubWebhookRejectedEventData{
+			DeliveryID: r.Header.Get(DeliveryIDHeader),
+			EventName:  r.Header.Get(EventHeader),
+			RemoteAddr: r.RemoteAddr,
+			Reason:     reason.Error(),
+		},
 	})
 }
 
 // Configure setups handlers on api with service
-func Configure(api *operations.EasyclaAPI, service Service) {
+func Configure(api *operations.EasyclaAPI, service Service, eventService events.Service, webhookSecrets []string, deliveryStore DeliveryStore) {
 	api.GithubActivityGithubActivityHandler = github_activity.GithubActivityHandlerFunc(
 		func(params github_activity.GithubActivityParams) middleware.Responder {
 			githubEvent := utils.GetGithubEvent(params.XGITHUBEVENT)
@@ -58,7 +136,16 @@ func Configure(api *operations.EasyclaAPI, service Service) {
 				})
 			}
 
-			event, err := github.ParseWebHook(githubEvent, payload)
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+
+			// the merge_group events aren't known to the github library
+			var event interface{}
+			if githubEvent == check_runs.MergeGroupEventName {
+				event, err = check_runs.ParseMergeGroupEvent(payload)
+			} else {
+				event, err = github.ParseWebHook(githubEvent, payload)
+			}
 			if err != nil {
 				return github_activity.NewGithubActivityBadRequest().WithPayload(&models.ErrorResponse{
 					Code:    "400",
@@ -72,17 +159,27 @@ func Configure(api *operations.EasyclaAPI, service Service) {
 				processError = service.ProcessInstallationRepositoriesEvent(event)
 			case *github.RepositoryEvent:
 				processError = service.ProcessRepositoryEvent(event)
+			case *github.OrganizationEvent:
+				processError = service.ProcessOrganizationEvent(event)
+			case *github.InstallationEvent:
+				processError = service.ProcessInstallationEvent(event)
+			case *github.PullRequestEvent:
+				processError = service.ProcessPullRequestEvent(ctx, event)
+			case *check_runs.MergeGroupEvent:
+				processError = service.ProcessMergeGroupEvent(ctx, event)
 			default:
 				log.Warnf("unsupported event sent : %s", githubEvent)
 			}
 
 			if processError != nil {
 				log.Warnf("processing event : %s failed with : %v", githubEvent, processError)
+				// GitHub can redeliver the failed event, the delivery is forgotten by the signature check middleware
+				return github_activity.NewGithubActivityInternalServerError().WithPayload(errorResponse(reqID, processError))
 			}
 
 			return github_activity.NewGithubActivityOK()
 		})
-	api.AddMiddlewareFor("POST", "/github/activity", signatureCheckMiddleware)
+	api.AddMiddlewareFor("POST", "/github/activity", signatureCheckMiddleware(webhookSecrets, deliveryStore, eventService))
 }
 
 type codedResponse interface {
@@ -0,0 +1,99 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignatureCheckMiddleware(t *testing.T) {
+	payload := []byte(`{"action":"opened","number":1}`)
+
+	type delivery struct {
+		deliveryID string
+		signature  string
+		status     int
+	}
+	testCases := []struct {
+		name       string
+		deliveries []delivery
+		// handlerStatus is the status returned by the next handler for each of its calls
+		handlerStatus []int
+		rejected      int
+	}{
+		{
+			name:          "valid delivery",
+			deliveries:    []delivery{{"d-1", sign("secret", payload), http.StatusOK}},
+			handlerStatus: []int{http.StatusOK},
+		},
+		{
+			name:       "bad signature",
+			deliveries: []delivery{{"d-1", sign("other", payload), http.StatusUnauthorized}},
+			rejected:   1,
+		},
+		{
+			name:       "missing delivery id",
+			deliveries: []delivery{{"", sign("secret", payload), http.StatusUnauthorized}},
+			rejected:   1,
+		},
+		{
+			name: "replayed delivery",
+			deliveries: []delivery{
+				{"d-1", sign("secret", payload), http.StatusOK},
+				{"d-1", sign("secret", payload), http.StatusUnauthorized},
+			},
+			handlerStatus: []int{http.StatusOK},
+			rejected:      1,
+		},
+		{
+			name: "redelivery after a failure",
+			deliveries: []delivery{
+				{"d-1", sign("secret", payload), http.StatusInternalServerError},
+				{"d-1", sign("secret", payload), http.StatusOK},
+				{"d-1", sign("secret", payload), http.StatusUnauthorized},
+			},
+			handlerStatus: []int{http.StatusInternalServerError, http.StatusOK},
+			rejected:      1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			eventService := &fakeEvents{}
+			calls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := ioutil.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, payload, body)
+				w.WriteHeader(tc.handlerStatus[calls])
+				calls++
+			})
+			handler := signatureCheckMiddleware([]string{"secret"}, NewInMemoryDeliveryStore(), eventService)(next)
+
+			for _, d := range tc.deliveries {
+				r := httptest.NewRequest(http.MethodPost, "/v4/github/activity", bytes.NewReader(payload))
+				r.Header.Set(DeliveryIDHeader, d.deliveryID)
+				r.Header.Set(SignatureSHA256Header, d.signature)
+				r.Header.Set(EventHeader, "pull_request")
+				w := httptest.NewRecorder()
+
+				handler.ServeHTTP(w, r)
+				assert.Equal(t, d.status, w.Code, "delivery %s", d.deliveryID)
+			}
+
+			assert.Equal(t, len(tc.handlerStatus), calls)
+			assert.Len(t, eventService.logged, tc.rejected)
+			for _, logged := range eventService.logged {
+				assert.Equal(t, events.GithubWebhookRejected, logged.EventType)
+			}
+		})
+	}
+}
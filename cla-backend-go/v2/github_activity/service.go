@@ -8,13 +8,17 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
 
+	"github.com/communitybridge/easycla/cla-backend-go/v2/check_runs"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/dynamo_events"
 
 	"github.com/communitybridge/easycla/cla-backend-go/events"
 
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+
 	"github.com/communitybridge/easycla/cla-backend-go/repositories"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
@@ -25,25 +29,45 @@ import (
 type Service interface {
 	ProcessInstallationRepositoriesEvent(event *github.InstallationRepositoriesEvent) error
 	ProcessRepositoryEvent(*github.RepositoryEvent) error
+	ProcessOrganizationEvent(*github.OrganizationEvent) error
+	ProcessInstallationEvent(*github.InstallationEvent) error
+	ProcessPullRequestEvent(ctx context.Context, event *github.PullRequestEvent) error
+	ProcessMergeGroupEvent(ctx context.Context, event *check_runs.MergeGroupEvent) error
 }
 
 type eventHandlerService struct {
 	githubRepo        repositories.Repository
+	githubOrgRepo     github_organizations.Repository
 	eventService      events.Service
 	autoEnableService dynamo_events.AutoEnableService
+	checkRunsService  check_runs.Service
 }
 
 // NewService creates a new instance of the Event Handler Service
 func NewService(githubRepo repositories.Repository,
+	githubOrgRepo github_organizations.Repository,
 	eventService events.Service,
-	autoEnableService dynamo_events.AutoEnableService) Service {
+	autoEnableService dynamo_events.AutoEnableService,
+	checkRunsService check_runs.Service) Service {
 	return &eventHandlerService{
 		githubRepo:        githubRepo,
+		githubOrgRepo:     githubOrgRepo,
 		eventService:      eventService,
 		autoEnableService: autoEnableService,
+		checkRunsService:  checkRunsService,
 	}
 }
 
+// ProcessPullRequestEvent publishes the CLA check run of the opened or updated pull request
+func (s *eventHandlerService) ProcessPullRequestEvent(ctx context.Context, event *github.PullRequestEvent) error {
+	return s.checkRunsService.CheckPullRequest(ctx, event)
+}
+
+// ProcessMergeGroupEvent publishes the CLA check run of the merge group
+func (s *eventHandlerService) ProcessMergeGroupEvent(ctx context.Context, event *check_runs.MergeGroupEvent) error {
+	return s.checkRunsService.CheckMergeGroup(ctx, event)
+}
+
 func (s *eventHandlerService) ProcessRepositoryEvent(event *github.RepositoryEvent) error {
 	log.Debugf("ProcessRepositoryEvent called for action : %s", *event.Action)
 	if event.Action == nil {
@@ -54,6 +78,14 @@ func (s *eventHandlerService) ProcessRepositoryEvent(event *github.RepositoryEve
 		return s.handleRepositoryAddedAction(event.Sender, event.Repo)
 	case "deleted":
 		return s.handleRepositoryRemovedAction(event.Sender, event.Repo)
+	case "renamed":
+		return s.handleRepositoryRenamedAction(event.Sender, event.Repo)
+	case "transferred":
+		return s.handleRepositoryTransferredAction(event.Sender, event.Repo)
+	case "archived":
+		return s.handleRepositoryArchivedAction(event.Sender, event.Repo)
+	case "unarchived":
+		return s.handleRepositoryUnarchivedAction(event.Sender, event.Repo)
 	default:
 		log.Warnf("ProcessRepositoryEvent no handler for action : %s", *event.Action)
 	}
@@ -172,3 +204,361 @@ func (s *eventHandlerService) ProcessInstallationRepositoriesEvent(event *github
 
 	return nil
 }
+
+func (s *eventHandlerService) handleRepositoryRenamedAction(sender *github.User, repo *github.Repository) error {
+	repoModel, err := s.getExistingRepository(repo, false)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	organizationName, _ := splitRepositoryFullName(repoModel.RepositoryOrganizationName, *repo.FullName)
+	if err := s.githubRepo.UpdateRepositoryName(context.Background(), repoModel.RepositoryID, organizationName, *repo.FullName, repo.GetHTMLURL()); err != nil {
+		log.Warnf("renaming repo : %s to : %s failed : %v", repoModel.RepositoryName, *repo.FullName, err)
+		return err
+	}
+
+	s.eventService.LogEvent(&events.LogEventArgs{
+		EventType: events.RepositoryRenamed,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    senderLogin(sender),
+		EventData: &events.RepositoryRenamedEventData{
+			OldRepositoryName: repoModel.RepositoryName,
+			NewRepositoryName: *repo.FullName,
+		},
+	})
+
+	return nil
+}
+
+func (s *eventHandlerService) handleRepositoryTransferredAction(sender *github.User, repo *github.Repository) error {
+	repoModel, err := s.getExistingRepository(repo, false)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	organizationName := repo.GetOwner().GetLogin()
+	if organizationName == "" {
+		organizationName, _ = splitRepositoryFullName(repoModel.RepositoryOrganizationName, *repo.FullName)
+	}
+	if err := s.githubRepo.UpdateRepositoryName(context.Background(), repoModel.RepositoryID, organizationName, *repo.FullName, repo.GetHTMLURL()); err != nil {
+		log.Warnf("updating transferred repo : %s to : %s failed : %v", repoModel.RepositoryName, *repo.FullName, err)
+		return err
+	}
+
+	// the CLA Group of the repository belongs to the project of its old organization, so the transferred repository is
+	// disabled until it is enabled again for a CLA Group of its new organization
+	disabled := false
+	if repoModel.Enabled {
+		log.Warnf("repo : %s was transferred to organization : %s, disabling it", *repo.FullName, organizationName)
+		if err := s.githubRepo.DisableRepository(context.Background(), repoModel.RepositoryID); err != nil {
+			log.Warnf("disabling repo : %s failed : %v", *repo.FullName, err)
+			return err
+		}
+		disabled = true
+	}
+
+	s.eventService.LogEvent(&events.LogEventArgs{
+		EventType: events.RepositoryTransferred,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    senderLogin(sender),
+		EventData: &events.RepositoryTransferredEventData{
+			OldRepositoryName: repoModel.RepositoryName,
+			NewRepositoryName: *repo.FullName,
+			Disabled:          disabled,
+		},
+	})
+
+	return nil
+}
+
+func (s *eventHandlerService) handleRepositoryArchivedAction(sender *github.User, repo *github.Repository) error {
+	repoModel, err := s.getExistingRepository(repo, true)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	if err := s.githubRepo.DisableRepository(context.Background(), repoModel.RepositoryID); err != nil {
+		log.Warnf("disabling archived repo : %s failed : %v", *repo.FullName, err)
+		return err
+	}
+
+	s.eventService.LogEvent(&events.LogEventArgs{
+		EventType: events.RepositoryArchived,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    senderLogin(sender),
+		EventData: &events.RepositoryArchivedEventData{
+			RepositoryName: *repo.FullName,
+		},
+	})
+
+	return nil
+}
+
+func (s *eventHandlerService) handleRepositoryUnarchivedAction(sender *github.User, repo *github.Repository) error {
+	repoModel, err := s.getExistingRepository(repo, false)
+	if err != nil || repoModel == nil {
+		return err
+	}
+
+	if repoModel.Enabled {
+		log.Debugf("unarchived repo : %s is enabled, nothing to do", *repo.FullName)
+		return nil
+	}
+
+	// the repository is only enabled again while its organization is configured in EasyCLA with the app installed
+	organizationName := repo.GetOwner().GetLogin()
+	if organizationName == "" {
+		organizationName, _ = splitRepositoryFullName(repoModel.RepositoryOrganizationName, *repo.FullName)
+	}
+	githubOrg, err := s.githubOrgRepo.GetGithubOrganization(context.Background(), organizationName)
+	if err != nil {
+		if errors.Is(err, github_organizations.ErrOrganizationDoesNotExist) {
+			log.Warnf("unarchived repo : %s belongs to not configured organization : %s, nothing to do", *repo.FullName, organizationName)
+			return nil
+		}
+		return fmt.Errorf("fetching the github organization : %s failed : %v", organizationName, err)
+	}
+	if githubOrg.OrganizationInstallationID == 0 {
+		log.Warnf("unarchived repo : %s belongs to organization : %s without the app installed, nothing to do", *repo.FullName, organizationName)
+		return nil
+	}
+
+	if err := s.githubRepo.EnableRepository(context.Background(), repoModel.RepositoryID); err != nil {
+		log.Warnf("enabling unarchived repo : %s failed : %v", *repo.FullName, err)
+		return err
+	}
+
+	s.eventService.LogEvent(&events.LogEventArgs{
+		EventType: events.RepositoryUnarchived,
+		ProjectID: repoModel.RepositoryProjectID,
+		UserID:    senderLogin(sender),
+		EventData: &events.RepositoryUnarchivedEventData{
+			RepositoryName: *repo.FullName,
+		},
+	})
+
+	return nil
+}
+
+// getExistingRepository loads the local repository for the github repository, returns nil if we don't track it - the
+// disabled entries are also considered unless enabledOnly is set
+func (s *eventHandlerService) getExistingRepository(repo *github.Repository, enabledOnly bool) (*models.GithubRepository, error) {
+	if repo == nil || repo.ID == nil || *repo.ID == 0 {
+		return nil, fmt.Errorf("missing repo id")
+	}
+
+	if repo.FullName == nil || *repo.FullName == "" {
+		return nil, fmt.Errorf("repo full name missing")
+	}
+
+	repositoryExternalID := strconv.FormatInt(*repo.ID, 10)
+	repoModel, err := s.githubRepo.GetRepositoryByGithubID(context.Background(), repositoryExternalID, true)
+	if err != nil && errors.Is(err, repositories.ErrGithubRepositoryNotFound) && !enabledOnly {
+		repoModel, err = s.githubRepo.GetRepositoryByGithubID(context.Background(), repositoryExternalID, false)
+	}
+	if err != nil {
+		if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+			log.Warnf("event for non existing local repo : %s, nothing to do", *repo.FullName)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("fetching the repo : %s by external id : %s failed : %v", *repo.FullName, repositoryExternalID, err)
+	}
+
+	return repoModel, nil
+}
+
+// ProcessOrganizationEvent handles the organization events, we're only interested in the org renames
+func (s *eventHandlerService) ProcessOrganizationEvent(event *github.OrganizationEvent) error {
+	if event.Action == nil {
+		return fmt.Errorf("no action found in event payload")
+	}
+	log.Debugf("ProcessOrganizationEvent called for action : %s", *event.Action)
+	switch *event.Action {
+	case "renamed":
+		return s.handleOrganizationRenamedAction(event.Sender, event.Installation, event.Organization)
+	default:
+		log.Warnf("ProcessOrganizationEvent no handler for action : %s", *event.Action)
+	}
+
+	return nil
+}
+
+func (s *eventHandlerService) handleOrganizationRenamedAction(sender *github.User, installation *github.Installation, org *github.Organization) error {
+	if installation == nil || installation.ID == nil || *installation.ID == 0 {
+		return fmt.Errorf("missing installation id")
+	}
+
+	if org == nil || org.Login == nil || *org.Login == "" {
+		return fmt.Errorf("organization login missing")
+	}
+	newOrganizationName := *org.Login
+
+	// the payload only carries the new name, so we find our record through the app installation
+	githubOrg, err := s.githubOrgRepo.GetGithubOrganizationByInstallationID(context.Background(), *installation.ID)
+	if err != nil {
+		if errors.Is(err, github_organizations.ErrOrganizationDoesNotExist) {
+			log.Warnf("rename event for non existing local organization : %s, nothing to do", newOrganizationName)
+			return nil
+		}
+		return fmt.Errorf("fetching the organization by installation id : %d failed : %v", *installation.ID, err)
+	}
+
+	oldOrganizationName := githubOrg.OrganizationName
+	if oldOrganizationName == newOrganizationName {
+		log.Debugf("organization : %s name did not change, nothing to do", newOrganizationName)
+		return nil
+	}
+
+	// the repositories are renamed before the organization - when one of them fails the organization keeps its old
+	// name, so the redelivery of the event renames the repositories still carrying it
+	repoModels, err := s.githubRepo.GetRepositoriesByOrganizationName(context.Background(), oldOrganizationName)
+	if err != nil && !errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+		return fmt.Errorf("fetching the repositories of organization : %s failed : %v", oldOrganizationName, err)
+	}
+
+	var errs []string
+	for _, repoModel := range repoModels {
+		_, repositoryName := splitRepositoryFullName(oldOrganizationName, repoModel.RepositoryName)
+		newRepositoryName := fmt.Sprintf("%s/%s", newOrganizationName, repositoryName)
+		newRepositoryURL := fmt.Sprintf("https://github.com/%s", newRepositoryName)
+		if err := s.githubRepo.UpdateRepositoryName(context.Background(), repoModel.RepositoryID, newOrganizationName, newRepositoryName, newRepositoryURL); err != nil {
+			log.Warnf("renaming the repository : %s to : %s failed : %v", repoModel.RepositoryName, newRepositoryName, err)
+			errs = append(errs, fmt.Sprintf("%s : %v", repoModel.RepositoryName, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("renaming %d of the %d repositories of organization : %s failed : %s", len(errs), len(repoModels), oldOrganizationName, strings.Join(errs, ", "))
+	}
+
+	if err := s.githubOrgRepo.RenameGithubOrganization(context.Background(), oldOrganizationName, newOrganizationName); err != nil {
+		log.Warnf("renaming organization : %s to : %s failed : %v", oldOrganizationName, newOrganizationName, err)
+		return err
+	}
+
+	s.eventService.LogEvent(&events.LogEventArgs{
+		EventType:         events.GithubOrganizationRenamed,
+		ExternalProjectID: githubOrg.ProjectSFID,
+		UserID:            senderLogin(sender),
+		EventData: &events.GitHubOrganizationRenamedEventData{
+			OldGitHubOrganizationName: oldOrganizationName,
+			NewGitHubOrganizationName: newOrganizationName,
+			RepositoryCount:           len(repoModels),
+		},
+	})
+
+	return nil
+}
+
+// ProcessInstallationEvent handles the installation events for the EasyCLA GitHub application
+func (s *eventHandlerService) ProcessInstallationEvent(event *github.InstallationEvent) error {
+	if event.Action == nil {
+		return fmt.Errorf("no action found in event payload")
+	}
+	log.Debugf("ProcessInstallationEvent called for action : %s", *event.Action)
+	if event.Installation == nil || event.Installation.ID == nil || *event.Installation.ID == 0 {
+		return fmt.Errorf("missing installation id")
+	}
+
+	organizationName := event.Installation.GetAccount().GetLogin()
+	if organizationName == "" {
+		return fmt.Errorf("installation account login missing")
+	}
+
+	githubOrg, err := s.githubOrgRepo.GetGithubOrganization(context.Background(), organizationName)
+	if err != nil {
+		if errors.Is(err, github_organizations.ErrOrganizationDoesNotExist) {
+			log.Warnf("installation event for non existing local organization : %s, nothing to do", organizationName)
+			return nil
+		}
+		return fmt.Errorf("fetching the organization : %s failed : %v", organizationName, err)
+	}
+
+	switch *event.Action {
+	case "deleted":
+		return s.handleInstallationDeletedAction(event.Sender, *event.Installation.ID, githubOrg)
+	case "suspend":
+		if err := s.githubOrgRepo.UpdateGithubOrganizationInstallationID(context.Background(), githubOrg.OrganizationName, 0); err != nil {
+			return err
+		}
+		s.eventService.LogEvent(&events.LogEventArgs{
+			EventType:         events.GithubOrganizationInstallationSuspended,
+			ExternalProjectID: githubOrg.ProjectSFID,
+			UserID:            senderLogin(event.Sender),
+			EventData: &events.GitHubOrganizationInstallationSuspendedEventData{
+				GitHubOrganizationName: githubOrg.OrganizationName,
+				InstallationID:         *event.Installation.ID,
+			},
+		})
+	case "unsuspend":
+		if err := s.githubOrgRepo.UpdateGithubOrganizationInstallationID(context.Background(), githubOrg.OrganizationName, *event.Installation.ID); err != nil {
+			return err
+		}
+		s.eventService.LogEvent(&events.LogEventArgs{
+			EventType:         events.GithubOrganizationInstallationUnsuspended,
+			ExternalProjectID: githubOrg.ProjectSFID,
+			UserID:            senderLogin(event.Sender),
+			EventData: &events.GitHubOrganizationInstallationUnsuspendedEventData{
+				GitHubOrganizationName: githubOrg.OrganizationName,
+				InstallationID:         *event.Installation.ID,
+			},
+		})
+	default:
+		log.Warnf("ProcessInstallationEvent no handler for action : %s", *event.Action)
+	}
+
+	return nil
+}
+
+func (s *eventHandlerService) handleInstallationDeletedAction(sender *github.User, installationID int64, githubOrg *models.GithubOrganization) error {
+	if err := s.githubOrgRepo.UpdateGithubOrganizationInstallationID(context.Background(), githubOrg.OrganizationName, 0); err != nil {
+		log.Warnf("clearing the installation id of organization : %s failed : %v", githubOrg.OrganizationName, err)
+		return err
+	}
+
+	repoModels, err := s.githubRepo.GetRepositoriesByOrganizationName(context.Background(), githubOrg.OrganizationName)
+	if err != nil && !errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+		return fmt.Errorf("fetching the repositories of organization : %s failed : %v", githubOrg.OrganizationName, err)
+	}
+
+	disabledCount := 0
+	for _, repoModel := range repoModels {
+		if !repoModel.Enabled {
+			continue
+		}
+		if err := s.githubRepo.DisableRepository(context.Background(), repoModel.RepositoryID); err != nil {
+			log.Warnf("disabling the repository : %s failed : %v", repoModel.RepositoryName, err)
+			continue
+		}
+		disabledCount++
+	}
+
+	s.eventService.LogEvent(&events.LogEventArgs{
+		EventType:         events.GithubOrganizationInstallationDeleted,
+		ExternalProjectID: githubOrg.ProjectSFID,
+		UserID:            senderLogin(sender),
+		EventData: &events.GitHubOrganizationInstallationDeletedEventData{
+			GitHubOrganizationName: githubOrg.OrganizationName,
+			InstallationID:         installationID,
+			DisabledCount:          disabledCount,
+		},
+	})
+
+	return nil
+}
+
+// splitRepositoryFullName splits the org/repo full name, falls back to the provided organization name
+func splitRepositoryFullName(organizationName, fullName string) (string, string) {
+	parts := strings.SplitN(fullName, "/", 2)
+	if len(parts) != 2 {
+		return organizationName, fullName
+	}
+	return parts[0], parts[1]
+}
+
+// senderLogin returns the sender login of the event, we fall back to the system user
+func senderLogin(sender *github.User) string {
+	if sender == nil || sender.Login == nil || *sender.Login == "" {
+		return "easycla system"
+	}
+	return *sender.Login
+}
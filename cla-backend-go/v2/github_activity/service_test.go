@@ -0,0 +1,348 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/google/go-github/v32/github"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRepositories keeps the repositories by their GitHub id, only the methods of the event handlers are implemented
+type fakeRepositories struct {
+	repositories.Repository
+	repos map[int64]*models.GithubRepository
+	// failRename fails the renames of the repositories by their id
+	failRename map[string]bool
+}
+
+func (r *fakeRepositories) GetRepositoryByGithubID(ctx context.Context, externalID string, enabled bool) (*models.GithubRepository, error) {
+	id, _ := strconv.ParseInt(externalID, 10, 64)
+	repo, ok := r.repos[id]
+	if !ok || repo.Enabled != enabled {
+		return nil, repositories.ErrGithubRepositoryNotFound
+	}
+	return repo, nil
+}
+
+func (r *fakeRepositories) GetRepositoriesByOrganizationName(ctx context.Context, gitHubOrgName string) ([]*models.GithubRepository, error) {
+	var result []*models.GithubRepository
+	for _, repo := range r.repos {
+		if repo.RepositoryOrganizationName == gitHubOrgName {
+			result = append(result, repo)
+		}
+	}
+	return result, nil
+}
+
+func (r *fakeRepositories) UpdateRepositoryName(ctx context.Context, repositoryID, organizationName, repositoryName, repositoryURL string) error {
+	if r.failRename[repositoryID] {
+		return errors.New("dynamodb unavailable")
+	}
+	for _, repo := range r.repos {
+		if repo.RepositoryID == repositoryID {
+			repo.RepositoryOrganizationName = organizationName
+			repo.RepositoryName = repositoryName
+			repo.RepositoryURL = repositoryURL
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepositories) EnableRepository(ctx context.Context, repositoryID string) error {
+	for _, repo := range r.repos {
+		if repo.RepositoryID == repositoryID {
+			repo.Enabled = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepositories) DisableRepository(ctx context.Context, repositoryID string) error {
+	for _, repo := range r.repos {
+		if repo.RepositoryID == repositoryID {
+			repo.Enabled = false
+		}
+	}
+	return nil
+}
+
+// fakeGithubOrganizations keeps the github organizations by their name
+type fakeGithubOrganizations struct {
+	github_organizations.Repository
+	orgs map[string]*models.GithubOrganization
+}
+
+func (r *fakeGithubOrganizations) GetGithubOrganization(ctx context.Context, githubOrganizationName string) (*models.GithubOrganization, error) {
+	org, ok := r.orgs[githubOrganizationName]
+	if !ok {
+		return nil, github_organizations.ErrOrganizationDoesNotExist
+	}
+	return org, nil
+}
+
+func (r *fakeGithubOrganizations) GetGithubOrganizationByInstallationID(ctx context.Context, installationID int64) (*models.GithubOrganization, error) {
+	for _, org := range r.orgs {
+		if org.OrganizationInstallationID == installationID {
+			return org, nil
+		}
+	}
+	return nil, github_organizations.ErrOrganizationDoesNotExist
+}
+
+func (r *fakeGithubOrganizations) UpdateGithubOrganizationInstallationID(ctx context.Context, organizationName string, installationID int64) error {
+	r.orgs[organizationName].OrganizationInstallationID = installationID
+	return nil
+}
+
+func (r *fakeGithubOrganizations) RenameGithubOrganization(ctx context.Context, organizationName string, newOrganizationName string) error {
+	org := r.orgs[organizationName]
+	delete(r.orgs, organizationName)
+	org.OrganizationName = newOrganizationName
+	r.orgs[newOrganizationName] = org
+	return nil
+}
+
+// fakeEvents records the logged events
+type fakeEvents struct {
+	events.Service
+	logged []*events.LogEventArgs
+}
+
+func (s *fakeEvents) LogEvent(args *events.LogEventArgs) {
+	s.logged = append(s.logged, args)
+}
+
+func TestProcessGithubEvents(t *testing.T) {
+	sender := &github.User{Login: github.String("octocat")}
+	repoEvent := func(action, fullName, owner string) func(Service) error {
+		return func(s Service) error {
+			return s.ProcessRepositoryEvent(&github.RepositoryEvent{
+				Action: github.String(action),
+				Sender: sender,
+				Repo: &github.Repository{
+					ID:       github.Int64(1001),
+					FullName: github.String(fullName),
+					HTMLURL:  github.String("https://github.com/" + fullName),
+					Owner:    &github.User{Login: github.String(owner)},
+				},
+			})
+		}
+	}
+	installationEvent := func(action string) func(Service) error {
+		return func(s Service) error {
+			return s.ProcessInstallationEvent(&github.InstallationEvent{
+				Action: github.String(action),
+				Sender: sender,
+				Installation: &github.Installation{
+					ID:      github.Int64(42),
+					Account: &github.User{Login: github.String("acme")},
+				},
+			})
+		}
+	}
+
+	testCases := []struct {
+		name              string
+		process           func(Service) error
+		eventType         string
+		organizationName  string
+		installationID    int64
+		repositoryName    string
+		repositoryEnabled bool
+	}{
+		{
+			name: "organization renamed",
+			process: func(s Service) error {
+				return s.ProcessOrganizationEvent(&github.OrganizationEvent{
+					Action:       github.String("renamed"),
+					Sender:       sender,
+					Installation: &github.Installation{ID: github.Int64(42)},
+					Organization: &github.Organization{Login: github.String("acme-corp")},
+				})
+			},
+			eventType:         events.GithubOrganizationRenamed,
+			organizationName:  "acme-corp",
+			installationID:    42,
+			repositoryName:    "acme-corp/widgets",
+			repositoryEnabled: true,
+		},
+		{
+			name:              "repository renamed",
+			process:           repoEvent("renamed", "acme/gadgets", "acme"),
+			eventType:         events.RepositoryRenamed,
+			organizationName:  "acme",
+			installationID:    42,
+			repositoryName:    "acme/gadgets",
+			repositoryEnabled: true,
+		},
+		{
+			name:              "repository transferred to an organization not in EasyCLA",
+			process:           repoEvent("transferred", "umbrella/widgets", "umbrella"),
+			eventType:         events.RepositoryTransferred,
+			organizationName:  "acme",
+			installationID:    42,
+			repositoryName:    "umbrella/widgets",
+			repositoryEnabled: false,
+		},
+		{
+			name:              "repository archived",
+			process:           repoEvent("archived", "acme/widgets", "acme"),
+			eventType:         events.RepositoryArchived,
+			organizationName:  "acme",
+			installationID:    42,
+			repositoryName:    "acme/widgets",
+			repositoryEnabled: false,
+		},
+		{
+			name:              "app uninstalled",
+			process:           installationEvent("deleted"),
+			eventType:         events.GithubOrganizationInstallationDeleted,
+			organizationName:  "acme",
+			installationID:    0,
+			repositoryName:    "acme/widgets",
+			repositoryEnabled: false,
+		},
+		{
+			name:              "app suspended",
+			process:           installationEvent("suspend"),
+			eventType:         events.GithubOrganizationInstallationSuspended,
+			organizationName:  "acme",
+			installationID:    0,
+			repositoryName:    "acme/widgets",
+			repositoryEnabled: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repos := &fakeRepositories{repos: map[int64]*models.GithubRepository{
+				1001: {
+					RepositoryID:               "repository-1",
+					RepositoryName:             "acme/widgets",
+					RepositoryOrganizationName: "acme",
+					RepositoryProjectID:        "cla-group-1",
+					Enabled:                    true,
+				},
+			}}
+			orgs := &fakeGithubOrganizations{orgs: map[string]*models.GithubOrganization{
+				"acme": {OrganizationName: "acme", OrganizationInstallationID: 42, ProjectSFID: "project-1"},
+			}}
+			eventService := &fakeEvents{}
+
+			err := tc.process(NewService(repos, orgs, eventService, nil, nil))
+			assert.NoError(t, err)
+
+			org, ok := orgs.orgs[tc.organizationName]
+			if assert.True(t, ok) {
+				assert.Equal(t, tc.installationID, org.OrganizationInstallationID)
+			}
+			assert.Len(t, orgs.orgs, 1)
+			assert.Equal(t, tc.repositoryName, repos.repos[1001].RepositoryName)
+			assert.Equal(t, tc.repositoryEnabled, repos.repos[1001].Enabled)
+			if assert.Len(t, eventService.logged, 1) {
+				assert.Equal(t, tc.eventType, eventService.logged[0].EventType)
+				assert.Equal(t, "octocat", eventService.logged[0].UserID)
+			}
+		})
+	}
+}
+
+func TestProcessOrganizationRenamedPartialFailure(t *testing.T) {
+	repos := &fakeRepositories{
+		repos: map[int64]*models.GithubRepository{
+			1001: {RepositoryID: "repository-1", RepositoryName: "acme/widgets", RepositoryOrganizationName: "acme", Enabled: true},
+			1002: {RepositoryID: "repository-2", RepositoryName: "acme/gadgets", RepositoryOrganizationName: "acme", Enabled: true},
+		},
+		failRename: map[string]bool{"repository-2": true},
+	}
+	orgs := &fakeGithubOrganizations{orgs: map[string]*models.GithubOrganization{
+		"acme": {OrganizationName: "acme", OrganizationInstallationID: 42, ProjectSFID: "project-1"},
+	}}
+	eventService := &fakeEvents{}
+	s := NewService(repos, orgs, eventService, nil, nil)
+	event := &github.OrganizationEvent{
+		Action:       github.String("renamed"),
+		Installation: &github.Installation{ID: github.Int64(42)},
+		Organization: &github.Organization{Login: github.String("acme-corp")},
+	}
+
+	// the failed repository keeps the organization on its old name, so the event fails and is delivered again
+	assert.Error(t, s.ProcessOrganizationEvent(event))
+	assert.Contains(t, orgs.orgs, "acme")
+	assert.Equal(t, "acme-corp/widgets", repos.repos[1001].RepositoryName)
+	assert.Equal(t, "acme/gadgets", repos.repos[1002].RepositoryName)
+	assert.Empty(t, eventService.logged)
+
+	// the redelivery renames the remaining repository and then the organization
+	repos.failRename = nil
+	assert.NoError(t, s.ProcessOrganizationEvent(event))
+	assert.Contains(t, orgs.orgs, "acme-corp")
+	assert.NotContains(t, orgs.orgs, "acme")
+	assert.Equal(t, "acme-corp/widgets", repos.repos[1001].RepositoryName)
+	assert.Equal(t, "acme-corp/gadgets", repos.repos[1002].RepositoryName)
+	assert.Equal(t, "acme-corp", repos.repos[1002].RepositoryOrganizationName)
+	if assert.Len(t, eventService.logged, 1) {
+		assert.Equal(t, events.GithubOrganizationRenamed, eventService.logged[0].EventType)
+	}
+}
+
+func TestProcessRepositoryTransferredAndUnarchived(t *testing.T) {
+	repos := &fakeRepositories{repos: map[int64]*models.GithubRepository{
+		1001: {RepositoryID: "repository-1", RepositoryName: "acme/widgets", RepositoryOrganizationName: "acme", RepositoryProjectID: "cla-group-1", Enabled: true},
+		1002: {RepositoryID: "repository-2", RepositoryName: "acme/gadgets", RepositoryOrganizationName: "acme", RepositoryProjectID: "cla-group-1", Enabled: true},
+	}}
+	orgs := &fakeGithubOrganizations{orgs: map[string]*models.GithubOrganization{
+		"acme":     {OrganizationName: "acme", OrganizationInstallationID: 42, ProjectSFID: "project-1"},
+		"umbrella": {OrganizationName: "umbrella", OrganizationInstallationID: 43, ProjectSFID: "project-2"},
+		"initech":  {OrganizationName: "initech", ProjectSFID: "project-3"},
+	}}
+	eventService := &fakeEvents{}
+	s := NewService(repos, orgs, eventService, nil, nil)
+	repoEvent := func(action string, id int64, fullName, owner string) *github.RepositoryEvent {
+		return &github.RepositoryEvent{
+			Action: github.String(action),
+			Repo: &github.Repository{
+				ID:       github.Int64(id),
+				FullName: github.String(fullName),
+				HTMLURL:  github.String("https://github.com/" + fullName),
+				Owner:    &github.User{Login: github.String(owner)},
+			},
+		}
+	}
+
+	// the repository transferred to an organization configured in EasyCLA still belongs to the CLA Group of its old
+	// organization, it is disabled until it is enabled for a CLA Group of the new one
+	assert.NoError(t, s.ProcessRepositoryEvent(repoEvent("transferred", 1001, "umbrella/widgets", "umbrella")))
+	assert.False(t, repos.repos[1001].Enabled)
+	assert.Equal(t, "umbrella", repos.repos[1001].RepositoryOrganizationName)
+	assert.Equal(t, "cla-group-1", repos.repos[1001].RepositoryProjectID)
+	if assert.Len(t, eventService.logged, 1) {
+		assert.True(t, eventService.logged[0].EventData.(*events.RepositoryTransferredEventData).Disabled)
+	}
+
+	// the archived repository is enabled again when it is unarchived
+	assert.NoError(t, s.ProcessRepositoryEvent(repoEvent("archived", 1002, "acme/gadgets", "acme")))
+	assert.False(t, repos.repos[1002].Enabled)
+	assert.NoError(t, s.ProcessRepositoryEvent(repoEvent("unarchived", 1002, "acme/gadgets", "acme")))
+	assert.True(t, repos.repos[1002].Enabled)
+	if assert.Len(t, eventService.logged, 3) {
+		assert.Equal(t, events.RepositoryUnarchived, eventService.logged[2].EventType)
+	}
+
+	// unarchiving an enabled repository or one of an organization without the app installed changes nothing
+	assert.NoError(t, s.ProcessRepositoryEvent(repoEvent("unarchived", 1002, "acme/gadgets", "acme")))
+	repos.repos[1001].RepositoryOrganizationName = "initech"
+	assert.NoError(t, s.ProcessRepositoryEvent(repoEvent("unarchived", 1001, "initech/widgets", "initech")))
+	assert.False(t, repos.repos[1001].Enabled)
+	assert.Len(t, eventService.logged, 3)
+}
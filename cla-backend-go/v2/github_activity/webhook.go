@@ -0,0 +1,185 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+)
+
+// GitHub webhook headers
+const (
+	SignatureSHA256Header = "X-Hub-Signature-256"
+	DeliveryIDHeader      = "X-GitHub-Delivery"
+	EventHeader           = "X-GitHub-Event"
+)
+
+// deliveryTTL is how long we remember a delivery id for replay protection
+const deliveryTTL = 24 * time.Hour
+
+// errors
+var (
+	ErrWebhookSecretNotConfigured = errors.New("webhook secret is not configured")
+	ErrMissingSignature           = errors.New("missing webhook signature")
+	ErrInvalidSignature           = errors.New("webhook signature does not match")
+	ErrMissingDeliveryID          = errors.New("missing webhook delivery id")
+	ErrReplayedDelivery           = errors.New("webhook delivery was already processed")
+)
+
+// ValidateSignature verifies the X-Hub-Signature-256 value against the payload using each of the
+// provided secrets. More than one secret is accepted so that the secret can be rotated.
+func ValidateSignature(signature string, payload []byte, secrets []string) error {
+	if len(secrets) == 0 {
+		return ErrWebhookSecretNotConfigured
+	}
+
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	const prefix = "sha256="
+	if !strings.HasPrefix(signature, prefix) {
+		return ErrInvalidSignature
+	}
+
+	signatureBytes, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload) // nolint - hash writes never return an error
+		if hmac.Equal(signatureBytes, mac.Sum(nil)) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+// DeliveryStore keeps track of the webhook delivery ids we have already seen
+type DeliveryStore interface {
+	// MarkDelivered records the delivery id, returns ErrReplayedDelivery if it was recorded before
+	MarkDelivered(deliveryID string) error
+	// ForgetDelivery removes the delivery id of a delivery which failed, so its redelivery is accepted
+	ForgetDelivery(deliveryID string) error
+}
+
+// NewDynamoDeliveryStore creates a delivery store backed by the cla-<stage>-store key/value table
+func NewDynamoDeliveryStore(dynamoDBClient storage.Driver, stage string) DeliveryStore {
+	return &dynamoDeliveryStore{
+		dynamoDBClient: dynamoDBClient,
+		tableName:      fmt.Sprintf("cla-%s-store", stage),
+	}
+}
+
+type dynamoDeliveryStore struct {
+	dynamoDBClient storage.Driver
+	tableName      string
+}
+
+// MarkDelivered stores the delivery id with a conditional put so concurrent replays are also rejected
+func (s *dynamoDeliveryStore) MarkDelivered(deliveryID string) error {
+	if deliveryID == "" {
+		return ErrMissingDeliveryID
+	}
+
+	_, err := s.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"key":    {S: aws.String(fmt.Sprintf("github-delivery-%s", deliveryID))},
+			"value":  {S: aws.String(deliveryID)},
+			"expire": {N: aws.String(fmt.Sprintf("%d", time.Now().Add(deliveryTTL).Unix()))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#key)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#key": aws.String("key"),
+		},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return ErrReplayedDelivery
+		}
+		return err
+	}
+
+	return nil
+}
+
+// ForgetDelivery deletes the delivery id
+func (s *dynamoDeliveryStore) ForgetDelivery(deliveryID string) error {
+	if deliveryID == "" {
+		return nil
+	}
+
+	_, err := s.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"key": {S: aws.String(fmt.Sprintf("github-delivery-%s", deliveryID))},
+		},
+	})
+	return err
+}
+
+// NewInMemoryDeliveryStore creates a delivery store which keeps the delivery ids in memory - used for local mode and tests
+func NewInMemoryDeliveryStore() DeliveryStore {
+	return &inMemoryDeliveryStore{
+		deliveries: make(map[string]time.Time),
+		now:        time.Now,
+	}
+}
+
+type inMemoryDeliveryStore struct {
+	lock       sync.Mutex
+	deliveries map[string]time.Time
+	now        func() time.Time
+}
+
+// MarkDelivered records the delivery id in memory, expired entries are pruned on each call
+func (s *inMemoryDeliveryStore) MarkDelivered(deliveryID string) error {
+	if deliveryID == "" {
+		return ErrMissingDeliveryID
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := s.now()
+	for id, expire := range s.deliveries {
+		if now.After(expire) {
+			delete(s.deliveries, id)
+		}
+	}
+
+	if _, ok := s.deliveries[deliveryID]; ok {
+		return ErrReplayedDelivery
+	}
+	s.deliveries[deliveryID] = now.Add(deliveryTTL)
+
+	return nil
+}
+
+// ForgetDelivery removes the delivery id from memory
+func (s *inMemoryDeliveryStore) ForgetDelivery(deliveryID string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.deliveries, deliveryID)
+	return nil
+}
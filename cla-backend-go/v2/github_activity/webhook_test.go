@@ -0,0 +1,101 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package github_activity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload) // nolint
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestValidateSignature(t *testing.T) {
+	payload := []byte(`{"action":"created","repository":{"id":1234}}`)
+
+	testCases := []struct {
+		name      string
+		signature string
+		secrets   []string
+		err       error
+	}{
+		{
+			name:      "valid signature",
+			signature: sign("current", payload),
+			secrets:   []string{"current"},
+		},
+		{
+			name:      "valid signature with previous secret during rotation",
+			signature: sign("previous", payload),
+			secrets:   []string{"current", "previous"},
+		},
+		{
+			name:      "signature from a retired secret",
+			signature: sign("retired", payload),
+			secrets:   []string{"current", "previous"},
+			err:       ErrInvalidSignature,
+		},
+		{
+			name:      "signature of a different payload",
+			signature: sign("current", []byte(`{"action":"deleted"}`)),
+			secrets:   []string{"current"},
+			err:       ErrInvalidSignature,
+		},
+		{
+			name:      "sha1 signature",
+			signature: "sha1=4f1a4e2e9f4c0c8f4c8a0f3d6b0d3f1f6c2a9b7e",
+			secrets:   []string{"current"},
+			err:       ErrInvalidSignature,
+		},
+		{
+			name:      "not hex encoded",
+			signature: "sha256=not-a-signature",
+			secrets:   []string{"current"},
+			err:       ErrInvalidSignature,
+		},
+		{
+			name:    "missing signature",
+			secrets: []string{"current"},
+			err:     ErrMissingSignature,
+		},
+		{
+			name:      "no secret configured",
+			signature: sign("", payload),
+			err:       ErrWebhookSecretNotConfigured,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateSignature(tc.signature, payload, tc.secrets)
+			assert.Equal(t, tc.err, err)
+		})
+	}
+}
+
+func TestInMemoryDeliveryStore(t *testing.T) {
+	now := time.Now()
+	store := &inMemoryDeliveryStore{
+		deliveries: make(map[string]time.Time),
+		now:        func() time.Time { return now },
+	}
+
+	assert.Equal(t, ErrMissingDeliveryID, store.MarkDelivered(""))
+	assert.NoError(t, store.MarkDelivered("72d3162e-cc78-11e3-81ab-4c9367dc0958"))
+	assert.Equal(t, ErrReplayedDelivery, store.MarkDelivered("72d3162e-cc78-11e3-81ab-4c9367dc0958"))
+	assert.NoError(t, store.MarkDelivered("9a7c1b2e-cc78-11e3-81ab-4c9367dc0958"))
+
+	// once the entry expires the delivery id is forgotten
+	now = now.Add(deliveryTTL + time.Minute)
+	assert.NoError(t, store.MarkDelivered("72d3162e-cc78-11e3-81ab-4c9367dc0958"))
+	assert.Len(t, store.deliveries, 1)
+}
@@ -140,12 +140,14 @@ func (s service) GetGithubOrganizations(ctx context.Context, projectSFID string)
 			BranchProtectionEnabled: org.BranchProtectionEnabled,
 			ConnectionStatus:        "", // updated below
 			GithubOrganizationName:  org.OrganizationName,
+			OrganizationType:        v1Repositories.OrganizationType(org),
 			Repositories:            make([]*models.ProjectGithubRepository, 0),
 		}
 
 		orgmap[org.OrganizationName] = rorg
 		out.List = append(out.List, rorg)
-		if org.OrganizationInstallationID == 0 {
+		// only GitHub organizations are connected through an installation of the EasyCLA GitHub application
+		if org.OrganizationInstallationID == 0 && v1Repositories.OrganizationType(org) == utils.GitHubType {
 			rorg.ConnectionStatus = NoConnection
 		} else {
 			if org.Repositories.Error != "" {
@@ -243,6 +245,7 @@ func (s service) AddGithubOrganization(ctx context.Context, projectSFID string,
 		"autoEnabled":             utils.BoolValue(input.AutoEnabled),
 		"branchProtectionEnabled": utils.BoolValue(input.BranchProtectionEnabled),
 		"organizationName":        utils.StringValue(input.OrganizationName),
+		"organizationType":        input.OrganizationType,
 	}
 
 	var in v1Models.CreateGithubOrganization
@@ -269,6 +272,11 @@ func (s service) AddGithubOrganization(ctx context.Context, projectSFID string,
 	f["parentProjectSFID"] = parentProjectSFID
 	log.WithFields(f).Debug("located parentProjectID...")
 
+	if err = v1GithubOrg.ValidateOrganization(ctx, utils.StringValue(in.OrganizationName), in.OrganizationType); err != nil {
+		log.WithFields(f).WithError(err).Warn("problem validating the organization")
+		return nil, err
+	}
+
 	log.WithFields(f).Debug("adding github organization...")
 	resp, err := s.repo.AddGithubOrganization(ctx, parentProjectSFID, projectSFID, &in)
 	if err != nil {
@@ -0,0 +1,104 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/gitlab_activity"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrMissingToken = errors.New("missing webhook token")
+	ErrInvalidToken = errors.New("invalid webhook token")
+)
+
+// ValidateToken checks the webhook token against the configured secrets, GitLab sends the secret as is
+func ValidateToken(token string, webhookSecrets []string) error {
+	if token == "" {
+		return ErrMissingToken
+	}
+	for _, secret := range webhookSecrets {
+		if secret != "" && subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+			return nil
+		}
+	}
+	return ErrInvalidToken
+}
+
+// tokenCheckMiddleware rejects the webhook deliveries without a valid secret token
+func tokenCheckMiddleware(webhookSecrets []string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := ValidateToken(r.Header.Get(gitlab.TokenHeader), webhookSecrets); err != nil {
+				log.WithFields(logrus.Fields{
+					"functionName":   "gitlab_activity.tokenCheckMiddleware",
+					utils.XREQUESTID: r.Header.Get(utils.XREQUESTID),
+					"gitlabEvent":    r.Header.Get(gitlab.EventHeader),
+					"remoteAddr":     r.RemoteAddr,
+				}).WithError(err).Warn("webhook token check failed")
+				http.Error(w, "token check failure", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Configure setups handlers on api with service
+func Configure(api *operations.EasyclaAPI, service Service, webhookSecrets []string) {
+	api.GitlabActivityGitlabActivityHandler = gitlab_activity.GitlabActivityHandlerFunc(
+		func(params gitlab_activity.GitlabActivityParams) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+			gitlabEvent := utils.StringValue(params.XGITLABEVENT)
+
+			payload, err := params.GitlabActivityInput.MarshalJSON()
+			if err != nil {
+				return gitlab_activity.NewGitlabActivityBadRequest().WithPayload(&models.ErrorResponse{
+					Code:    "400",
+					Message: "json marshall",
+				})
+			}
+
+			event, err := gitlab.ParseWebhook(gitlabEvent, payload)
+			if err != nil {
+				if errors.Is(err, gitlab.ErrUnsupportedEvent) {
+					log.Debugf("unsupported event sent : %s", gitlabEvent)
+					return gitlab_activity.NewGitlabActivityOK()
+				}
+				return gitlab_activity.NewGitlabActivityBadRequest().WithPayload(&models.ErrorResponse{
+					Code:    "400",
+					Message: fmt.Sprintf("parsing event failed : %v", err),
+				})
+			}
+
+			var processError error
+			switch event := event.(type) {
+			case *gitlab.SystemHookEvent:
+				processError = service.ProcessSystemHookEvent(ctx, event)
+			case *gitlab.MergeRequestEvent:
+				processError = service.ProcessMergeRequestEvent(ctx, event)
+			}
+
+			if processError != nil {
+				log.Warnf("processing event : %s failed with : %v", gitlabEvent, processError)
+			}
+
+			return gitlab_activity.NewGitlabActivityOK()
+		})
+	api.AddMiddlewareFor("POST", "/gitlab/activity", tokenCheckMiddleware(webhookSecrets))
+}
@@ -0,0 +1,248 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package gitlab_activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/communitybridge/easycla/cla-backend-go/coverage"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/github_organizations"
+	"github.com/communitybridge/easycla/cla-backend-go/gitlab"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/projects_cla_groups"
+	"github.com/communitybridge/easycla/cla-backend-go/repositories"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/swag"
+	"github.com/sirupsen/logrus"
+)
+
+// StatusName is the name of the commit status EasyCLA sets on the merge requests
+const StatusName = "EasyCLA"
+
+// Service is responsible for handling the gitlab activity events
+type Service interface {
+	ProcessSystemHookEvent(ctx context.Context, event *gitlab.SystemHookEvent) error
+	ProcessMergeRequestEvent(ctx context.Context, event *gitlab.MergeRequestEvent) error
+}
+
+type service struct {
+	repositoryRepo repositories.Repository
+	gitlabOrgRepo  github_organizations.Repository
+	claGroupRepo   projects_cla_groups.Repository
+	evaluator      coverage.Evaluator
+}
+
+// NewService creates a new instance of the gitlab activity service
+func NewService(repositoryRepo repositories.Repository, gitlabOrgRepo github_organizations.Repository, claGroupRepo projects_cla_groups.Repository, evaluator coverage.Evaluator) Service {
+	return &service{
+		repositoryRepo: repositoryRepo,
+		gitlabOrgRepo:  gitlabOrgRepo,
+		claGroupRepo:   claGroupRepo,
+		evaluator:      evaluator,
+	}
+}
+
+// ProcessSystemHookEvent adds the created projects of auto enabled groups and disables the destroyed projects
+func (s *service) ProcessSystemHookEvent(ctx context.Context, event *gitlab.SystemHookEvent) error {
+	switch event.EventName {
+	case gitlab.ProjectCreateEventName:
+		return s.handleProjectCreated(ctx, event)
+	case gitlab.ProjectDestroyEventName:
+		return s.handleProjectDestroyed(ctx, event)
+	default:
+		log.Debugf("ProcessSystemHookEvent no handler for event : %s", event.EventName)
+	}
+	return nil
+}
+
+func (s *service) handleProjectCreated(ctx context.Context, event *gitlab.SystemHookEvent) error {
+	f := logrus.Fields{
+		"functionName":      "gitlab_activity.handleProjectCreated",
+		utils.XREQUESTID:    ctx.Value(utils.XREQUESTID),
+		"projectID":         event.ProjectID,
+		"pathWithNamespace": event.PathWithNamespace,
+	}
+
+	if event.ProjectID == 0 || event.PathWithNamespace == "" {
+		return errors.New("missing project id or path")
+	}
+
+	orgModel, err := s.lookupGroup(ctx, event.PathWithNamespace)
+	if err != nil {
+		return err
+	}
+	if orgModel == nil {
+		log.WithFields(f).Debug("the project doesn't belong to a registered gitlab group, skipping")
+		return nil
+	}
+	if !orgModel.AutoEnabled || orgModel.AutoEnabledClaGroupID == "" {
+		log.WithFields(f).Debugf("autoEnabled is off or has no cla group for gitlab group : %s, skipping", orgModel.OrganizationName)
+		return nil
+	}
+
+	provider, err := repositories.GetProvider(utils.GitLabType)
+	if err != nil {
+		return err
+	}
+	externalID := strconv.FormatInt(event.ProjectID, 10)
+	project, err := provider.GetRepository(ctx, orgModel, externalID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the gitlab project")
+		return err
+	}
+
+	claGroupModel, err := s.claGroupRepo.GetCLAGroup(orgModel.AutoEnabledClaGroupID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("fetching the cla group for cla group id : %s failed", orgModel.AutoEnabledClaGroupID)
+		return err
+	}
+	projectSFID := claGroupModel.ProjectSFID
+	if projectSFID == "" {
+		projectSFID = orgModel.ProjectSFID
+	}
+
+	_, err = s.repositoryRepo.AddGithubRepository(ctx, claGroupModel.ProjectExternalID, projectSFID, &models.GithubRepositoryInput{
+		RepositoryProjectID:        swag.String(orgModel.AutoEnabledClaGroupID),
+		RepositoryName:             swag.String(project.FullName),
+		RepositoryType:             swag.String(utils.GitLabType),
+		RepositoryURL:              swag.String(project.HTMLURL),
+		RepositoryOrganizationName: swag.String(orgModel.OrganizationName),
+		RepositoryExternalID:       swag.String(externalID),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to add the auto enabled gitlab project")
+		return err
+	}
+	log.WithFields(f).Debugf("added gitlab project to cla group : %s", orgModel.AutoEnabledClaGroupID)
+	return nil
+}
+
+func (s *service) handleProjectDestroyed(ctx context.Context, event *gitlab.SystemHookEvent) error {
+	f := logrus.Fields{
+		"functionName":   "gitlab_activity.handleProjectDestroyed",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectID":      event.ProjectID,
+	}
+
+	repoModel, err := s.repositoryRepo.GetRepositoryByExternalID(ctx, utils.GitLabType, strconv.FormatInt(event.ProjectID, 10), true)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+			log.WithFields(f).Debug("the destroyed project isn't enabled in EasyCLA, skipping")
+			return nil
+		}
+		return err
+	}
+
+	log.WithFields(f).Debugf("disabling gitlab project : %s", repoModel.RepositoryName)
+	return s.repositoryRepo.DisableRepository(ctx, repoModel.RepositoryID)
+}
+
+// lookupGroup returns the registered group of the project, the closest parent group of the project path wins
+func (s *service) lookupGroup(ctx context.Context, pathWithNamespace string) (*models.GithubOrganization, error) {
+	parts := strings.Split(pathWithNamespace, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		orgModel, err := s.gitlabOrgRepo.GetGithubOrganization(ctx, strings.Join(parts[:i], "/"))
+		if err != nil {
+			if errors.Is(err, github_organizations.ErrOrganizationDoesNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		if repositories.OrganizationType(orgModel) == utils.GitLabType {
+			return orgModel, nil
+		}
+	}
+	return nil, nil
+}
+
+// ProcessMergeRequestEvent checks the commit authors of the merge request are covered by a signature of the CLA Group
+// of the project and sets the commit status of the last commit accordingly
+func (s *service) ProcessMergeRequestEvent(ctx context.Context, event *gitlab.MergeRequestEvent) error {
+	attrs := event.ObjectAttributes
+	f := logrus.Fields{
+		"functionName":   "gitlab_activity.ProcessMergeRequestEvent",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"projectID":      event.Project.ID,
+		"mergeRequest":   attrs.IID,
+		"action":         attrs.Action,
+	}
+
+	switch attrs.Action {
+	case gitlab.MergeRequestActionOpen, gitlab.MergeRequestActionReopen, gitlab.MergeRequestActionUpdate:
+	default:
+		log.WithFields(f).Debug("merge request action doesn't change the commits, skipping")
+		return nil
+	}
+
+	repoModel, err := s.repositoryRepo.GetRepositoryByExternalID(ctx, utils.GitLabType, strconv.FormatInt(event.Project.ID, 10), true)
+	if err != nil {
+		if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+			log.WithFields(f).Debug("the project isn't enabled in EasyCLA, skipping")
+			return nil
+		}
+		return err
+	}
+
+	client := gitlab.GetClient()
+	if client == nil {
+		return gitlab.ErrNotConfigured
+	}
+	commits, err := client.ListMergeRequestCommits(ctx, event.Project.ID, attrs.IID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to list the merge request commits")
+		return err
+	}
+
+	missing, err := s.missingAuthors(ctx, repoModel.RepositoryProjectID, event.User, commits)
+	if err != nil {
+		return err
+	}
+
+	status := &gitlab.CommitStatus{
+		State:       gitlab.CommitStateSuccess,
+		Name:        StatusName,
+		Description: "All committers are covered by a signed CLA",
+	}
+	if len(missing) > 0 {
+		status.State = gitlab.CommitStateFailed
+		status.Description = fmt.Sprintf("Missing CLA: %s", strings.Join(missing, ", "))
+	}
+	log.WithFields(f).Debugf("setting commit status %s on %s", status.State, attrs.LastCommit.ID)
+	return client.SetCommitStatus(ctx, event.Project.ID, attrs.LastCommit.ID, status)
+}
+
+// missingAuthors returns the names of the commit authors who aren't covered by a signature of the CLA Group
+func (s *service) missingAuthors(ctx context.Context, claGroupID string, user gitlab.EventUser, commits []*gitlab.Commit) ([]string, error) {
+	ctx = coverage.WithExemptionCache(ctx)
+	seen := make(map[string]bool)
+	var missing []string
+	for _, commit := range commits {
+		email := strings.ToLower(commit.AuthorEmail)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		identity := &coverage.Identity{Email: commit.AuthorEmail}
+		// the username is only known for the commits of the user who pushed the merge request
+		if user.Email != "" && strings.EqualFold(user.Email, commit.AuthorEmail) {
+			identity.GitLabUsername = user.Username
+		}
+		verdict, err := s.evaluator.Evaluate(ctx, claGroupID, identity)
+		if err != nil {
+			return nil, err
+		}
+		if !verdict.Covered {
+			missing = append(missing, commit.AuthorName)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
@@ -29,6 +29,37 @@ func Configure(api *operations.EasyclaAPI, service v1Health.Service) {
 		}
 		return health.NewHealthCheckOK().WithXRequestID(reqID).WithPayload(&response)
 	})
+
+	api.HealthLivenessCheckHandler = health.LivenessCheckHandlerFunc(func(params health.LivenessCheckParams) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		result, err := service.LivenessCheck(params.HTTPRequest.Context())
+		if err != nil {
+			return health.NewLivenessCheckBadRequest().WithPayload(errorResponse(err))
+		}
+		var response models.Health
+		err = copier.Copy(&response, result)
+		if err != nil {
+			return health.NewLivenessCheckBadRequest().WithPayload(errorResponse(err))
+		}
+		return health.NewLivenessCheckOK().WithXRequestID(reqID).WithPayload(&response)
+	})
+
+	api.HealthReadinessCheckHandler = health.ReadinessCheckHandlerFunc(func(params health.ReadinessCheckParams) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		result, err := service.ReadinessCheck(params.HTTPRequest.Context())
+		if err != nil {
+			return health.NewReadinessCheckBadRequest().WithPayload(errorResponse(err))
+		}
+		var response models.Health
+		err = copier.Copy(&response, result)
+		if err != nil {
+			return health.NewReadinessCheckBadRequest().WithPayload(errorResponse(err))
+		}
+		if response.Status == v1Health.StatusNotHealthy {
+			return health.NewReadinessCheckServiceUnavailable().WithXRequestID(reqID).WithPayload(&response)
+		}
+		return health.NewReadinessCheckOK().WithXRequestID(reqID).WithPayload(&response)
+	})
 }
 
 type codedResponse interface {
@@ -0,0 +1,508 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/sirupsen/logrus"
+)
+
+// countedAttributes are the attributes of the metric items which are maintained incrementally from the DynamoDB
+// stream events of the signatures, repositories and CLA Groups tables - the other attributes are written by the
+// periodic full calculation
+var countedAttributes = map[string][]string{
+	MetricTypeTotalCount: {
+		"individual_contributors_count",
+		"corporate_contributors_count",
+		"contributors_count",
+		"cla_managers_count",
+		"companies_project_contribution_count",
+		"clas_signed_count",
+		"github_repositories_count",
+		"github_repositories_enabled_count",
+		"projects_count",
+		"projects_live_count",
+	},
+	MetricTypeProject: {
+		"individual_contributors_count",
+		"corporate_contributors_count",
+		"total_contributors_count",
+		"companies_count",
+		"cla_managers_count",
+		"repositories_count",
+	},
+	MetricTypeCompany: {
+		"project_count",
+		"corporate_contributors_count",
+		"cla_managers_count",
+	},
+}
+
+// counter is the attribute of a metric item holding the number of distinct members of the counter
+type counter struct {
+	metricType string
+	metricID   string
+	attribute  string
+}
+
+func (c counter) String() string {
+	return fmt.Sprintf("%s#%s#%s", c.metricType, c.metricID, c.attribute)
+}
+
+// counterMember is a member of a counter - the source is the ID of the signature, repository, gerrit instance or CLA
+// Group the member was counted from, a member stays counted as long as one of its sources remains
+type counterMember struct {
+	counter
+	memberID string
+	sourceID string
+}
+
+func (m counterMember) key() string {
+	return fmt.Sprintf("%s#%s#%s", m.counter, m.memberID, m.sourceID)
+}
+
+// countedSignature returns true if the signature is included in the metrics, only the signed and approved
+// signatures are counted
+func countedSignature(sig *ItemSignature) bool {
+	return sig != nil && sig.SignatureSigned && sig.SignatureApproved
+}
+
+// signatureMembers returns the counter members of the signature, mirroring the counts of the full calculation - the
+// CLA managers are the ones of the signature ACL having a user record
+func signatureMembers(sig *ItemSignature, claManagers []string) []counterMember {
+	if !countedSignature(sig) {
+		return nil
+	}
+
+	var members []counterMember
+	add := func(metricType, metricID, attribute, memberID string) {
+		members = append(members, counterMember{
+			counter:  counter{metricType: metricType, metricID: metricID, attribute: attribute},
+			memberID: memberID,
+			sourceID: sig.SignatureID,
+		})
+	}
+
+	projectID := sig.SignatureProjectID
+	switch signatureType(sig) {
+	case IclaSignature:
+		userID := sig.SignatureReferenceID
+		add(MetricTypeTotalCount, IDTotalCount, "individual_contributors_count", userID)
+		add(MetricTypeTotalCount, IDTotalCount, "contributors_count", userID)
+		add(MetricTypeTotalCount, IDTotalCount, "clas_signed_count", sig.SignatureID)
+		add(MetricTypeProject, projectID, "individual_contributors_count", userID)
+		add(MetricTypeProject, projectID, "total_contributors_count", "icla#"+userID)
+	case EmployeeSignature:
+		userID := sig.SignatureReferenceID
+		companyID := sig.SignatureUserCompanyID
+		add(MetricTypeTotalCount, IDTotalCount, "corporate_contributors_count", userID)
+		add(MetricTypeTotalCount, IDTotalCount, "contributors_count", userID)
+		add(MetricTypeProject, projectID, "corporate_contributors_count", userID)
+		add(MetricTypeProject, projectID, "total_contributors_count", "employee#"+userID)
+		add(MetricTypeCompany, companyID, "corporate_contributors_count", userID)
+	case CclaSignature:
+		companyID := sig.SignatureReferenceID
+		add(MetricTypeTotalCount, IDTotalCount, "companies_project_contribution_count", fmt.Sprintf("%s#%s", companyID, projectID))
+		add(MetricTypeTotalCount, IDTotalCount, "clas_signed_count", sig.SignatureID)
+		add(MetricTypeProject, projectID, "companies_count", companyID)
+		add(MetricTypeCompany, companyID, "project_count", sig.SignatureID)
+		for _, claManager := range claManagers {
+			add(MetricTypeTotalCount, IDTotalCount, "cla_managers_count", claManager)
+			add(MetricTypeProject, projectID, "cla_managers_count", claManager)
+			add(MetricTypeCompany, companyID, "cla_managers_count", claManager)
+		}
+	}
+	return members
+}
+
+// repositoryMembers returns the counter members of the GitHub repository
+func repositoryMembers(r *ItemRepository) []counterMember {
+	if r == nil {
+		return nil
+	}
+	members := []counterMember{
+		{counter: counter{MetricTypeTotalCount, IDTotalCount, "github_repositories_count"}, memberID: r.RepositoryID, sourceID: r.RepositoryID},
+		{counter: counter{MetricTypeProject, r.RepositoryProjectID, "repositories_count"}, memberID: r.RepositoryID, sourceID: r.RepositoryID},
+	}
+	if r.Enabled {
+		members = append(members, counterMember{counter: counter{MetricTypeTotalCount, IDTotalCount, "github_repositories_enabled_count"}, memberID: r.RepositoryID, sourceID: r.RepositoryID})
+	}
+	return members
+}
+
+// gerritInstanceMembers returns the counter members of the gerrit instance - the gerrit instances table has no stream
+// handler, the instances are added by the periodic full calculation
+func gerritInstanceMembers(gi *ItemGerritInstance) []counterMember {
+	if gi == nil {
+		return nil
+	}
+	return []counterMember{
+		{counter: counter{MetricTypeProject, gi.ProjectID, "repositories_count"}, memberID: gi.GerritID, sourceID: gi.GerritID},
+	}
+}
+
+// claGroupMembers returns the counter members of the CLA Group
+func claGroupMembers(p *ItemProject) []counterMember {
+	if p == nil {
+		return nil
+	}
+	members := []counterMember{
+		{counter: counter{MetricTypeTotalCount, IDTotalCount, "projects_count"}, memberID: p.ProjectID, sourceID: p.ProjectID},
+	}
+	if p.ProjectLive {
+		members = append(members, counterMember{counter: counter{MetricTypeTotalCount, IDTotalCount, "projects_live_count"}, memberID: p.ProjectID, sourceID: p.ProjectID})
+	}
+	return members
+}
+
+// diffMembers returns the members of the new record which are not in the old record and the members of the old record
+// which are not in the new record, the modifications which don't change the counted values return no members
+func diffMembers(oldMembers, newMembers []counterMember) ([]counterMember, []counterMember) {
+	oldKeys := make(map[string]bool, len(oldMembers))
+	for _, m := range oldMembers {
+		oldKeys[m.key()] = true
+	}
+	newKeys := make(map[string]bool, len(newMembers))
+	for _, m := range newMembers {
+		newKeys[m.key()] = true
+	}
+
+	var added, removed []counterMember
+	for _, m := range newMembers {
+		if !oldKeys[m.key()] {
+			added = append(added, m)
+		}
+	}
+	for _, m := range oldMembers {
+		if !newKeys[m.key()] {
+			removed = append(removed, m)
+		}
+	}
+	return added, removed
+}
+
+// ApplySignatureChange updates the counters with the change of a signature record, the old or the new signature is
+// nil when the signature was inserted or removed
+func (repo *repo) ApplySignatureChange(oldSig, newSig *ItemSignature) error {
+	var claManagers []string
+	if countedSignature(newSig) && signatureType(newSig) == CclaSignature {
+		var err error
+		claManagers, err = repo.existingUsers(newSig.SignatureACL)
+		if err != nil {
+			return err
+		}
+	}
+	// the removed CLA managers are not looked up, removing a member which was never counted has no effect
+	var oldClaManagers []string
+	if oldSig != nil {
+		oldClaManagers = oldSig.SignatureACL
+	}
+	return repo.applyMemberChanges(signatureMembers(oldSig, oldClaManagers), signatureMembers(newSig, claManagers))
+}
+
+// ApplyRepositoryChange updates the counters with the change of a GitHub repository record
+func (repo *repo) ApplyRepositoryChange(oldRepo, newRepo *ItemRepository) error {
+	return repo.applyMemberChanges(repositoryMembers(oldRepo), repositoryMembers(newRepo))
+}
+
+// ApplyCLAGroupChange updates the counters with the change of a CLA Group record, and the name of the CLA Group in
+// its project metric
+func (repo *repo) ApplyCLAGroupChange(oldCLAGroup, newCLAGroup *ItemProject) error {
+	err := repo.applyMemberChanges(claGroupMembers(oldCLAGroup), claGroupMembers(newCLAGroup))
+	if err != nil {
+		return err
+	}
+	if newCLAGroup == nil {
+		return nil
+	}
+
+	attributes := map[string]*dynamodb.AttributeValue{
+		"project_name": {S: aws.String(newCLAGroup.ProjectName)},
+	}
+	if newCLAGroup.ProjectExternalID != "" {
+		// the salesforce_id is the range key of an index, so it is only set when present
+		attributes["external_project_id"] = &dynamodb.AttributeValue{S: aws.String(newCLAGroup.ProjectExternalID)}
+		attributes["salesforce_id"] = &dynamodb.AttributeValue{S: aws.String(newCLAGroup.ProjectExternalID)}
+	}
+	return repo.setMetricAttributes(MetricTypeProject, newCLAGroup.ProjectID, attributes)
+}
+
+func (repo *repo) applyMemberChanges(oldMembers, newMembers []counterMember) error {
+	added, removed := diffMembers(oldMembers, newMembers)
+	for _, m := range removed {
+		if err := repo.removeMember(m); err != nil {
+			return err
+		}
+	}
+	for _, m := range added {
+		if err := repo.addMember(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxCounterAttempts is the number of attempts of a counter member update conflicting with concurrent updates
+const maxCounterAttempts = 5
+
+// errCounterConflict is returned when the counter member kept changing during the update
+var errCounterConflict = errors.New("metrics counter member updated concurrently")
+
+// addMember adds the source to the member and increments the counter when the member had no source yet, both in one
+// transaction - adding a source twice has no effect, so the stream events can be processed again
+func (repo *repo) addMember(m counterMember) error {
+	f := logrus.Fields{"counter": m.counter.String(), "memberID": m.memberID}
+	for attempt := 0; attempt < maxCounterAttempts; attempt++ {
+		sources, err := repo.memberSources(m)
+		if err != nil {
+			return err
+		}
+		if containsSource(sources, m.sourceID) {
+			return nil
+		}
+
+		if len(sources) == 0 {
+			err = repo.updateMemberAndCounter(m, &dynamodb.TransactWriteItem{
+				Put: &dynamodb.Put{
+					TableName: aws.String(repo.membersTableName),
+					Item: map[string]*dynamodb.AttributeValue{
+						"counter_id": {S: aws.String(m.counter.String())},
+						"member_id":  {S: aws.String(m.memberID)},
+						"sources":    {SS: []*string{aws.String(m.sourceID)}},
+					},
+					ConditionExpression:      aws.String("attribute_not_exists(#sources)"),
+					ExpressionAttributeNames: map[string]*string{"#sources": aws.String("sources")},
+				},
+			}, 1)
+		} else {
+			// the member stays counted, only its sources change
+			_, err = repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName:                 aws.String(repo.membersTableName),
+				Key:                       memberKey(m),
+				UpdateExpression:          aws.String("ADD #sources :sources"),
+				ConditionExpression:       aws.String("attribute_exists(#sources)"),
+				ExpressionAttributeNames:  map[string]*string{"#sources": aws.String("sources")},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":sources": {SS: []*string{aws.String(m.sourceID)}}},
+			})
+		}
+		if isConditionFailure(err) {
+			log.WithFields(f).Debug("the metrics counter member changed, retrying")
+			continue
+		}
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to add the metrics counter member")
+		}
+		return err
+	}
+	log.WithFields(f).Warn("unable to add the metrics counter member, it kept changing")
+	return errCounterConflict
+}
+
+// removeMember removes the source from the member and, when it was the last source of the member, deletes the member
+// and decrements the counter in one transaction - removing a source which was already removed has no effect
+func (repo *repo) removeMember(m counterMember) error {
+	f := logrus.Fields{"counter": m.counter.String(), "memberID": m.memberID}
+	for attempt := 0; attempt < maxCounterAttempts; attempt++ {
+		sources, err := repo.memberSources(m)
+		if err != nil {
+			return err
+		}
+		if !containsSource(sources, m.sourceID) {
+			// the source was already removed or was never counted
+			return nil
+		}
+
+		if len(sources) == 1 {
+			err = repo.updateMemberAndCounter(m, &dynamodb.TransactWriteItem{
+				Delete: &dynamodb.Delete{
+					TableName:                aws.String(repo.membersTableName),
+					Key:                      memberKey(m),
+					ConditionExpression:      aws.String("contains(#sources, :source) AND size(#sources) = :one"),
+					ExpressionAttributeNames: map[string]*string{"#sources": aws.String("sources")},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":source": {S: aws.String(m.sourceID)},
+						":one":    {N: aws.String("1")},
+					},
+				},
+			}, -1)
+		} else {
+			// the member stays counted by its other sources
+			_, err = repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName:                aws.String(repo.membersTableName),
+				Key:                      memberKey(m),
+				UpdateExpression:         aws.String("DELETE #sources :sources"),
+				ConditionExpression:      aws.String("contains(#sources, :source) AND size(#sources) > :one"),
+				ExpressionAttributeNames: map[string]*string{"#sources": aws.String("sources")},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":sources": {SS: []*string{aws.String(m.sourceID)}},
+					":source":  {S: aws.String(m.sourceID)},
+					":one":     {N: aws.String("1")},
+				},
+			})
+		}
+		if isConditionFailure(err) {
+			log.WithFields(f).Debug("the metrics counter member changed, retrying")
+			continue
+		}
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("unable to remove the metrics counter member")
+		}
+		return err
+	}
+	log.WithFields(f).Warn("unable to remove the metrics counter member, it kept changing")
+	return errCounterConflict
+}
+
+// memberSources returns the sources of the counter member, empty when the member does not exist
+func (repo *repo) memberSources(m counterMember) ([]string, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		TableName:      aws.String(repo.membersTableName),
+		Key:            memberKey(m),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		log.WithFields(logrus.Fields{"counter": m.counter.String(), "memberID": m.memberID}).WithError(err).Warn("unable to load the metrics counter member")
+		return nil, err
+	}
+	if av, ok := result.Item["sources"]; ok {
+		return aws.StringValueSlice(av.SS), nil
+	}
+	return nil, nil
+}
+
+// updateMemberAndCounter writes the counter member and adds the delta to its counter in one transaction, the metric
+// item is created if it does not exist
+func (repo *repo) updateMemberAndCounter(m counterMember, memberUpdate *dynamodb.TransactWriteItem, delta int64) error {
+	_, err := repo.dynamoDBClient.TransactWriteItems(&dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			memberUpdate,
+			{
+				Update: &dynamodb.Update{
+					TableName: aws.String(repo.metricTableName),
+					Key: map[string]*dynamodb.AttributeValue{
+						"metric_type": {S: aws.String(m.counter.metricType)},
+						"id":          {S: aws.String(m.counter.metricID)},
+					},
+					UpdateExpression:          aws.String("ADD #count :delta"),
+					ExpressionAttributeNames:  map[string]*string{"#count": aws.String(m.counter.attribute)},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{":delta": {N: aws.String(fmt.Sprintf("%d", delta))}},
+				},
+			},
+		},
+	})
+	return err
+}
+
+func memberKey(m counterMember) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"counter_id": {S: aws.String(m.counter.String())},
+		"member_id":  {S: aws.String(m.memberID)},
+	}
+}
+
+func containsSource(sources []string, sourceID string) bool {
+	for _, source := range sources {
+		if source == sourceID {
+			return true
+		}
+	}
+	return false
+}
+
+// isConditionFailure returns true if the conditional write, or the condition of a transaction, failed
+func isConditionFailure(err error) bool {
+	aerr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	if aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return true
+	}
+	if canceled, ok := err.(*dynamodb.TransactionCanceledException); ok {
+		for _, reason := range canceled.CancellationReasons {
+			if aws.StringValue(reason.Code) == "ConditionalCheckFailed" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setMetricAttributes sets the attributes of the metric item and keeps its other attributes, such as the counters
+func (repo *repo) setMetricAttributes(metricType, id string, attributes map[string]*dynamodb.AttributeValue) error {
+	if len(attributes) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	expressionNames := make(map[string]*string, len(names))
+	expressionValues := make(map[string]*dynamodb.AttributeValue, len(names))
+	updateExpression := "SET"
+	for i, name := range names {
+		if i > 0 {
+			updateExpression += ","
+		}
+		updateExpression += fmt.Sprintf(" #a%d = :a%d", i, i)
+		expressionNames[fmt.Sprintf("#a%d", i)] = aws.String(name)
+		expressionValues[fmt.Sprintf(":a%d", i)] = attributes[name]
+	}
+
+	_, err := repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName: aws.String(repo.metricTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"metric_type": {S: aws.String(metricType)},
+			"id":          {S: aws.String(id)},
+		},
+		UpdateExpression:          aws.String(updateExpression),
+		ExpressionAttributeNames:  expressionNames,
+		ExpressionAttributeValues: expressionValues,
+	})
+	if err != nil {
+		log.WithFields(logrus.Fields{"metricType": metricType, "id": id}).WithError(err).Warn("unable to update the metric")
+		return err
+	}
+	return nil
+}
+
+// existingUsers returns the LF usernames which have a user record
+func (repo *repo) existingUsers(lfUsernames []string) ([]string, error) {
+	var out []string
+	for _, lfUsername := range lfUsernames {
+		if lfUsername == "" {
+			continue
+		}
+		expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("lf_username").Equal(expression.Value(lfUsername))).Build()
+		if err != nil {
+			return nil, err
+		}
+		result, err := repo.dynamoDBClient.Query(&dynamodb.QueryInput{
+			TableName:                 aws.String(fmt.Sprintf("cla-%s-users", repo.stage)),
+			IndexName:                 aws.String("lf-username-index"),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			Select:                    aws.String(dynamodb.SelectCount),
+		})
+		if err != nil {
+			log.WithField("lfUsername", lfUsername).WithError(err).Warn("unable to query the user by LF username")
+			return nil, err
+		}
+		if aws.Int64Value(result.Count) > 0 {
+			out = append(out, lfUsername)
+		}
+	}
+	return out, nil
+}
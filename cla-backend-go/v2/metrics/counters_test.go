@@ -0,0 +1,166 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+// failingDriver fails the next transactions, like a throttled or interrupted write
+type failingDriver struct {
+	*storage.MemoryDriver
+	failures int
+}
+
+func (d *failingDriver) TransactWriteItems(input *dynamodb.TransactWriteItemsInput) (*dynamodb.TransactWriteItemsOutput, error) {
+	if d.failures > 0 {
+		d.failures--
+		return nil, errors.New("transaction interrupted")
+	}
+	return d.MemoryDriver.TransactWriteItems(input)
+}
+
+func newCountersRepo(driver storage.Driver) *repo {
+	return &repo{
+		dynamoDBClient:   driver,
+		metricTableName:  "cla-test-metrics",
+		membersTableName: "cla-test-metrics-members",
+		stage:            "test",
+	}
+}
+
+func counterValue(t *testing.T, driver storage.Driver, c counter) int {
+	result, err := driver.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String("cla-test-metrics"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"metric_type": {S: aws.String(c.metricType)},
+			"id":          {S: aws.String(c.metricID)},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return -1
+	}
+	av, ok := result.Item[c.attribute]
+	if !ok {
+		return 0
+	}
+	value, err := strconv.Atoi(aws.StringValue(av.N))
+	assert.NoError(t, err)
+	return value
+}
+
+func TestCounterMembers(t *testing.T) {
+	c := counter{metricType: MetricTypeProject, metricID: "cla-group-1", attribute: "individual_contributors_count"}
+	member := func(memberID, sourceID string) counterMember {
+		return counterMember{counter: c, memberID: memberID, sourceID: sourceID}
+	}
+	type step struct {
+		add     bool
+		member  counterMember
+		count   int
+		sources []string
+	}
+
+	testCases := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "add and remove a member",
+			steps: []step{
+				{add: true, member: member("u1", "s1"), count: 1, sources: []string{"s1"}},
+				{add: true, member: member("u2", "s2"), count: 2, sources: []string{"s2"}},
+				{add: false, member: member("u1", "s1"), count: 1, sources: nil},
+			},
+		},
+		{
+			name: "duplicate add",
+			steps: []step{
+				{add: true, member: member("u1", "s1"), count: 1, sources: []string{"s1"}},
+				{add: true, member: member("u1", "s1"), count: 1, sources: []string{"s1"}},
+			},
+		},
+		{
+			name: "member counted once for all its sources",
+			steps: []step{
+				{add: true, member: member("u1", "s1"), count: 1, sources: []string{"s1"}},
+				{add: true, member: member("u1", "s2"), count: 1, sources: []string{"s1", "s2"}},
+				{add: false, member: member("u1", "s1"), count: 1, sources: []string{"s2"}},
+				{add: false, member: member("u1", "s2"), count: 0, sources: nil},
+			},
+		},
+		{
+			name: "duplicate remove",
+			steps: []step{
+				{add: true, member: member("u1", "s1"), count: 1, sources: []string{"s1"}},
+				{add: false, member: member("u1", "s1"), count: 0, sources: nil},
+				{add: false, member: member("u1", "s1"), count: 0, sources: nil},
+			},
+		},
+		{
+			name: "remove a source never counted",
+			steps: []step{
+				{add: true, member: member("u1", "s1"), count: 1, sources: []string{"s1"}},
+				{add: false, member: member("u1", "s2"), count: 1, sources: []string{"s1"}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driver := storage.NewMemoryDriver(storage.TableSchemas("test")...)
+			r := newCountersRepo(driver)
+			for i, s := range tc.steps {
+				var err error
+				if s.add {
+					err = r.addMember(s.member)
+				} else {
+					err = r.removeMember(s.member)
+				}
+				if !assert.NoError(t, err, "step %d", i) {
+					return
+				}
+				assert.Equal(t, s.count, counterValue(t, driver, c), "step %d", i)
+				sources, err := r.memberSources(s.member)
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, s.sources, sources, "step %d", i)
+			}
+		})
+	}
+}
+
+func TestCounterMembersRetry(t *testing.T) {
+	c := counter{metricType: MetricTypeTotalCount, metricID: IDTotalCount, attribute: "projects_count"}
+	m := counterMember{counter: c, memberID: "cla-group-1", sourceID: "cla-group-1"}
+	driver := &failingDriver{MemoryDriver: storage.NewMemoryDriver(storage.TableSchemas("test")...)}
+	r := newCountersRepo(driver)
+
+	// a failed transaction neither adds the member nor increments the counter, so the stream event can be retried
+	driver.failures = 1
+	assert.Error(t, r.addMember(m))
+	assert.Equal(t, 0, counterValue(t, driver, c))
+	sources, err := r.memberSources(m)
+	assert.NoError(t, err)
+	assert.Empty(t, sources)
+
+	assert.NoError(t, r.addMember(m))
+	assert.Equal(t, 1, counterValue(t, driver, c))
+
+	driver.failures = 1
+	assert.Error(t, r.removeMember(m))
+	assert.Equal(t, 1, counterValue(t, driver, c))
+	sources, err = r.memberSources(m)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cla-group-1"}, sources)
+
+	assert.NoError(t, r.removeMember(m))
+	assert.Equal(t, 0, counterValue(t, driver, c))
+}
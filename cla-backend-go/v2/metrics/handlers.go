@@ -116,6 +116,16 @@ func Configure(api *operations.EasyclaAPI, service Service, v1CompanyRepo v1Comp
 			}
 			return metrics.NewListCompanyProjectMetricsOK().WithXRequestID(reqID).WithPayload(result)
 		})
+
+	api.MetricsGetMetricsHistoryHandler = metrics.GetMetricsHistoryHandlerFunc(
+		func(params metrics.GetMetricsHistoryParams, user *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			result, err := service.GetMetricsHistory(params.Scope, params.ID, params.From, params.To, utils.StringValue(params.Granularity))
+			if err != nil {
+				return metrics.NewGetMetricsHistoryBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return metrics.NewGetMetricsHistoryOK().WithXRequestID(reqID).WithPayload(result)
+		})
 }
 
 type codedResponse interface {
@@ -0,0 +1,205 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+)
+
+// history errors
+var (
+	ErrInvalidHistoryScope       = errors.New("invalid metrics history scope")
+	ErrInvalidHistoryGranularity = errors.New("invalid metrics history granularity")
+	ErrInvalidHistoryDateRange   = errors.New("invalid metrics history date range")
+)
+
+// HistoryScope constants - the entities a daily rollup is kept for
+const (
+	HistoryScopeCLAGroup   = "cla-group"
+	HistoryScopeCompany    = "company"
+	HistoryScopeFoundation = "foundation"
+	HistoryScopeTotal      = "total"
+
+	IDHistoryTotal = "total_count"
+)
+
+// HistoryGranularity constants
+const (
+	HistoryGranularityDay   = "day"
+	HistoryGranularityWeek  = "week"
+	HistoryGranularityMonth = "month"
+)
+
+// HistoryDateFormat is the format of the date of the daily rollups, the dates sort lexicographically
+const HistoryDateFormat = "2006-01-02"
+
+// MetricsHistory is the daily rollup of the counts of a CLA Group, company, foundation or of all of EasyCLA - the
+// rollup of a day is overwritten by each metrics calculation of that day so the last calculation of the day is kept
+type MetricsHistory struct {
+	SeriesID          string `json:"series_id"`
+	Date              string `json:"date"`
+	Scope             string `json:"scope"`
+	ScopeID           string `json:"scope_id"`
+	Name              string `json:"name,omitempty"`
+	IclaCount         int64  `json:"icla_count"`
+	CclaCount         int64  `json:"ccla_count"`
+	EmployeeCount     int64  `json:"employee_count"`
+	RepositoriesCount int64  `json:"repositories_count"`
+	ClaManagersCount  int64  `json:"cla_managers_count"`
+	CreatedAt         string `json:"created_at"`
+}
+
+// historySeriesID returns the hash key of the daily rollups of the entity
+func historySeriesID(scope, id string) string {
+	return fmt.Sprintf("%s#%s", scope, id)
+}
+
+func validHistoryScope(scope string) bool {
+	switch scope {
+	case HistoryScopeCLAGroup, HistoryScopeCompany, HistoryScopeFoundation, HistoryScopeTotal:
+		return true
+	}
+	return false
+}
+
+// historyRollups builds the rollups of the day from the calculated metrics - the foundation rollups count the
+// contributors, companies and CLA managers once even when they signed for several CLA Groups of the foundation
+func (m *Metrics) historyRollups(date string, claGroupMapping map[string]*claGroup) []*MetricsHistory {
+	newRollup := func(scope, id, name string) *MetricsHistory {
+		return &MetricsHistory{
+			SeriesID:  historySeriesID(scope, id),
+			Date:      date,
+			Scope:     scope,
+			ScopeID:   id,
+			Name:      name,
+			CreatedAt: m.CalculatedAt,
+		}
+	}
+
+	var rollups []*MetricsHistory
+
+	tm := m.TotalCountMetrics
+	total := newRollup(HistoryScopeTotal, IDHistoryTotal, "")
+	total.IclaCount = tm.IndividualContributorsCount
+	total.CclaCount = tm.CompaniesProjectContributionCount
+	total.EmployeeCount = tm.CorporateContributorsCount
+	total.RepositoriesCount = tm.GithubRepositoriesCount + tm.GerritRepositoriesCount
+	total.ClaManagersCount = tm.ClaManagersCount
+	rollups = append(rollups, total)
+
+	type foundationSets struct {
+		rollup                 *MetricsHistory
+		individualContributors map[string]interface{}
+		companies              map[string]interface{}
+		corporateContributors  map[string]interface{}
+		claManagers            map[string]interface{}
+	}
+	foundations := make(map[string]*foundationSets)
+
+	for claGroupID, pm := range m.ProjectMetrics.ProjectMetrics {
+		rollup := newRollup(HistoryScopeCLAGroup, claGroupID, pm.ProjectName)
+		rollup.IclaCount = pm.IndividualContributorsCount
+		rollup.CclaCount = pm.CompaniesCount
+		rollup.EmployeeCount = pm.CorporateContributorsCount
+		rollup.RepositoriesCount = pm.RepositoriesCount
+		rollup.ClaManagersCount = pm.ClaManagersCount
+		rollups = append(rollups, rollup)
+
+		cg, ok := claGroupMapping[claGroupID]
+		if !ok || cg.foundationSFID == "" {
+			continue
+		}
+		f, ok := foundations[cg.foundationSFID]
+		if !ok {
+			f = &foundationSets{
+				rollup:                 newRollup(HistoryScopeFoundation, cg.foundationSFID, ""),
+				individualContributors: make(map[string]interface{}),
+				companies:              make(map[string]interface{}),
+				corporateContributors:  make(map[string]interface{}),
+				claManagers:            make(map[string]interface{}),
+			}
+			foundations[cg.foundationSFID] = f
+		}
+		for key := range pm.individualContributors {
+			increaseCountIfNotPresent(f.individualContributors, &f.rollup.IclaCount, key)
+		}
+		for key := range pm.companies {
+			increaseCountIfNotPresent(f.companies, &f.rollup.CclaCount, key)
+		}
+		for key := range pm.corporateContributors {
+			increaseCountIfNotPresent(f.corporateContributors, &f.rollup.EmployeeCount, key)
+		}
+		for key := range pm.claManagers {
+			increaseCountIfNotPresent(f.claManagers, &f.rollup.ClaManagersCount, key)
+		}
+		f.rollup.RepositoriesCount += pm.RepositoriesCount
+	}
+	for _, f := range foundations {
+		rollups = append(rollups, f.rollup)
+	}
+
+	for companyID, cm := range m.CompanyMetrics.CompanyMetrics {
+		rollup := newRollup(HistoryScopeCompany, companyID, cm.CompanyName)
+		rollup.CclaCount = cm.ProjectCount
+		rollup.EmployeeCount = cm.CorporateContributorsCount
+		rollup.ClaManagersCount = cm.ClaManagersCount
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups
+}
+
+func (mh *MetricsHistory) toModel() *models.MetricsHistoryPoint {
+	return &models.MetricsHistoryPoint{
+		Date:              mh.Date,
+		IclaCount:         mh.IclaCount,
+		CclaCount:         mh.CclaCount,
+		EmployeeCount:     mh.EmployeeCount,
+		RepositoriesCount: mh.RepositoriesCount,
+		ClaManagersCount:  mh.ClaManagersCount,
+	}
+}
+
+// historyBucket returns the first day of the period of the granularity the date belongs to, the weeks start on Monday
+func historyBucket(date time.Time, granularity string) time.Time {
+	switch granularity {
+	case HistoryGranularityWeek:
+		offset := (int(date.Weekday()) + 6) % 7
+		return date.AddDate(0, 0, -offset)
+	case HistoryGranularityMonth:
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return date
+}
+
+// aggregateHistory returns one rollup per period of the granularity - the counts are totals at a point in time, so the
+// period keeps the rollup of its last day with a record, dated with the first day of the period
+func aggregateHistory(rollups []*MetricsHistory, granularity string) ([]*MetricsHistory, error) {
+	sorted := make([]*MetricsHistory, len(rollups))
+	copy(sorted, rollups)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date < sorted[j].Date
+	})
+
+	var out []*MetricsHistory
+	for _, rollup := range sorted {
+		date, err := time.Parse(HistoryDateFormat, rollup.Date)
+		if err != nil {
+			return nil, err
+		}
+		bucket := *rollup
+		bucket.Date = historyBucket(date, granularity).Format(HistoryDateFormat)
+		if len(out) > 0 && out[len(out)-1].Date == bucket.Date {
+			out[len(out)-1] = &bucket
+			continue
+		}
+		out = append(out, &bucket)
+	}
+	return out, nil
+}
@@ -0,0 +1,233 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistoryBucket(t *testing.T) {
+	testCases := []struct {
+		name        string
+		date        string
+		granularity string
+		bucket      string
+	}{
+		{"day", "2021-03-17", HistoryGranularityDay, "2021-03-17"},
+		{"week start", "2021-03-15", HistoryGranularityWeek, "2021-03-15"},
+		{"week middle", "2021-03-17", HistoryGranularityWeek, "2021-03-15"},
+		{"week end", "2021-03-21", HistoryGranularityWeek, "2021-03-15"},
+		{"week across the month end", "2021-04-02", HistoryGranularityWeek, "2021-03-29"},
+		{"week across the year end", "2021-01-03", HistoryGranularityWeek, "2020-12-28"},
+		{"month start", "2021-03-01", HistoryGranularityMonth, "2021-03-01"},
+		{"month end", "2021-03-31", HistoryGranularityMonth, "2021-03-01"},
+		{"leap day", "2020-02-29", HistoryGranularityMonth, "2020-02-01"},
+		{"year end", "2020-12-31", HistoryGranularityMonth, "2020-12-01"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			date, err := time.Parse(HistoryDateFormat, tc.date)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.bucket, historyBucket(date, tc.granularity).Format(HistoryDateFormat))
+			}
+		})
+	}
+}
+
+func TestAggregateHistory(t *testing.T) {
+	rollup := func(date string, iclaCount int64) *MetricsHistory {
+		return &MetricsHistory{Date: date, IclaCount: iclaCount}
+	}
+	type point struct {
+		date      string
+		iclaCount int64
+	}
+
+	testCases := []struct {
+		name        string
+		rollups     []*MetricsHistory
+		granularity string
+		points      []point
+	}{
+		{
+			name:        "no rollups",
+			granularity: HistoryGranularityWeek,
+			points:      []point{},
+		},
+		{
+			name:        "days are sorted",
+			rollups:     []*MetricsHistory{rollup("2021-03-02", 2), rollup("2021-03-01", 1)},
+			granularity: HistoryGranularityDay,
+			points:      []point{{"2021-03-01", 1}, {"2021-03-02", 2}},
+		},
+		{
+			name:        "empty days have no point",
+			rollups:     []*MetricsHistory{rollup("2021-03-01", 1), rollup("2021-03-04", 4)},
+			granularity: HistoryGranularityDay,
+			points:      []point{{"2021-03-01", 1}, {"2021-03-04", 4}},
+		},
+		{
+			name:        "week keeps its last day with a rollup",
+			rollups:     []*MetricsHistory{rollup("2021-03-15", 1), rollup("2021-03-19", 5), rollup("2021-03-21", 7), rollup("2021-03-22", 8)},
+			granularity: HistoryGranularityWeek,
+			points:      []point{{"2021-03-15", 7}, {"2021-03-22", 8}},
+		},
+		{
+			name:        "week without rollups has no point",
+			rollups:     []*MetricsHistory{rollup("2021-03-01", 1), rollup("2021-03-16", 16)},
+			granularity: HistoryGranularityWeek,
+			points:      []point{{"2021-03-01", 1}, {"2021-03-15", 16}},
+		},
+		{
+			name:        "month end",
+			rollups:     []*MetricsHistory{rollup("2021-01-31", 31), rollup("2021-02-01", 32), rollup("2021-02-28", 59)},
+			granularity: HistoryGranularityMonth,
+			points:      []point{{"2021-01-01", 31}, {"2021-02-01", 59}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregated, err := aggregateHistory(tc.rollups, tc.granularity)
+			if !assert.NoError(t, err) {
+				return
+			}
+			points := []point{}
+			for _, a := range aggregated {
+				points = append(points, point{a.Date, a.IclaCount})
+			}
+			assert.Equal(t, tc.points, points)
+		})
+	}
+
+	_, err := aggregateHistory([]*MetricsHistory{rollup("2021-3-1", 1)}, HistoryGranularityDay)
+	assert.Error(t, err)
+}
+
+// fakeHistoryRepository returns the rollups of the requested date range
+type fakeHistoryRepository struct {
+	Repository
+	rollups []*MetricsHistory
+	queried bool
+}
+
+func (r *fakeHistoryRepository) GetMetricsHistory(scope, id, fromDate, toDate string) ([]*MetricsHistory, error) {
+	r.queried = true
+	var out []*MetricsHistory
+	for _, rollup := range r.rollups {
+		if rollup.SeriesID == historySeriesID(scope, id) && rollup.Date >= fromDate && rollup.Date <= toDate {
+			out = append(out, rollup)
+		}
+	}
+	return out, nil
+}
+
+func TestGetMetricsHistoryValidation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		scope       string
+		from        string
+		to          string
+		granularity string
+		err         error
+	}{
+		{"single day", HistoryScopeCLAGroup, "2021-03-01", "2021-03-01", "", nil},
+		{"date range", HistoryScopeCompany, "2021-01-01", "2021-12-31", HistoryGranularityMonth, nil},
+		{"invalid scope", "project", "2021-03-01", "2021-03-31", "", ErrInvalidHistoryScope},
+		{"invalid granularity", HistoryScopeTotal, "2021-03-01", "2021-03-31", "year", ErrInvalidHistoryGranularity},
+		{"invalid from date", HistoryScopeTotal, "2021-3-1", "2021-03-31", "", ErrInvalidHistoryDateRange},
+		{"invalid to date", HistoryScopeTotal, "2021-03-01", "2021-02-30", "", ErrInvalidHistoryDateRange},
+		{"date time", HistoryScopeTotal, "2021-03-01T00:00:00Z", "2021-03-31", "", ErrInvalidHistoryDateRange},
+		{"from after to", HistoryScopeTotal, "2021-03-31", "2021-03-01", "", ErrInvalidHistoryDateRange},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := &fakeHistoryRepository{}
+			s := &service{metricsRepo: repo}
+			result, err := s.GetMetricsHistory(tc.scope, "id-1", tc.from, tc.to, tc.granularity)
+			if tc.err != nil {
+				assert.True(t, errors.Is(err, tc.err), "expected %v, got %v", tc.err, err)
+				assert.False(t, repo.queried)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.from, result.From)
+				assert.Equal(t, tc.to, result.To)
+				assert.Empty(t, result.List)
+			}
+		})
+	}
+}
+
+func TestGetMetricsHistory(t *testing.T) {
+	rollup := func(scope, id, date, name string, iclaCount int64) *MetricsHistory {
+		return &MetricsHistory{SeriesID: historySeriesID(scope, id), Date: date, Scope: scope, ScopeID: id, Name: name, IclaCount: iclaCount}
+	}
+	repo := &fakeHistoryRepository{rollups: []*MetricsHistory{
+		rollup(HistoryScopeTotal, IDHistoryTotal, "2021-03-05", "", 5),
+		rollup(HistoryScopeTotal, IDHistoryTotal, "2021-03-09", "", 9),
+		rollup(HistoryScopeTotal, IDHistoryTotal, "2021-03-10", "", 10),
+		rollup(HistoryScopeCLAGroup, "cla-group-1", "2021-03-09", "Acme", 1),
+	}}
+	s := &service{metricsRepo: repo}
+
+	// the id of the total scope is ignored
+	result, err := s.GetMetricsHistory(HistoryScopeTotal, "anything", "2021-03-01", "2021-03-09", HistoryGranularityWeek)
+	if assert.NoError(t, err) {
+		assert.Equal(t, IDHistoryTotal, result.ID)
+		assert.Equal(t, HistoryGranularityWeek, result.Granularity)
+		if assert.Len(t, result.List, 2) {
+			assert.Equal(t, "2021-03-01", result.List[0].Date)
+			assert.Equal(t, int64(5), result.List[0].IclaCount)
+			assert.Equal(t, "2021-03-08", result.List[1].Date)
+			assert.Equal(t, int64(9), result.List[1].IclaCount)
+		}
+	}
+
+	result, err = s.GetMetricsHistory(HistoryScopeCLAGroup, "cla-group-1", "2021-03-01", "2021-03-31", "")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Acme", result.Name)
+		assert.Equal(t, HistoryGranularityDay, result.Granularity)
+		assert.Len(t, result.List, 1)
+	}
+}
+
+func TestRepositoryGetMetricsHistory(t *testing.T) {
+	driver := storage.NewMemoryDriver(storage.TableSchemas("test")...)
+	r := &repo{dynamoDBClient: driver, historyTableName: "cla-test-metrics-history", stage: "test"}
+	for _, date := range []string{"2021-02-28", "2021-03-01", "2021-03-15", "2021-03-31", "2021-04-01"} {
+		item, err := dynamodbattribute.MarshalMap(&MetricsHistory{SeriesID: historySeriesID(HistoryScopeTotal, IDHistoryTotal), Date: date})
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = driver.PutItem(&dynamodb.PutItemInput{TableName: aws.String(r.historyTableName), Item: item})
+		if !assert.NoError(t, err) {
+			return
+		}
+	}
+
+	// both dates of the range are included
+	rollups, err := r.GetMetricsHistory(HistoryScopeTotal, IDHistoryTotal, "2021-03-01", "2021-03-31")
+	if assert.NoError(t, err) {
+		var dates []string
+		for _, rollup := range rollups {
+			dates = append(dates, rollup.Date)
+		}
+		assert.Equal(t, []string{"2021-03-01", "2021-03-15", "2021-03-31"}, dates)
+	}
+
+	rollups, err = r.GetMetricsHistory(HistoryScopeCLAGroup, "cla-group-1", "2021-03-01", "2021-03-31")
+	assert.NoError(t, err)
+	assert.Empty(t, rollups)
+}
@@ -13,10 +13,13 @@ type ItemSignature struct {
 	SignatureType          string   `json:"signature_type"`
 	SignatureReferenceType string   `json:"signature_reference_type"`
 	SignatureProjectID     string   `json:"signature_project_id"`
+	SignatureSigned        bool     `json:"signature_signed"`
+	SignatureApproved      bool     `json:"signature_approved"`
 }
 
 // ItemRepository represent item of repositories table
 type ItemRepository struct {
+	RepositoryID        string `json:"repository_id"`
 	RepositoryProjectID string `json:"repository_project_id"`
 	Enabled             bool   `json:"enabled"`
 }
@@ -29,6 +32,7 @@ type ItemCompany struct {
 
 // ItemGerritInstance represent item of gerrit instance table
 type ItemGerritInstance struct {
+	GerritID  string `json:"gerrit_id"`
 	ProjectID string `json:"project_id"`
 }
 
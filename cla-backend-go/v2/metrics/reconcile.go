@@ -0,0 +1,419 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// MetricType and ID of the report of the last reconciliation of the counters
+const (
+	MetricTypeReconciliation = "reconciliation"
+	IDReconciliation         = "reconciliation"
+)
+
+const (
+	// countersSeededAttribute is set on the total count metric once the counters have been seeded
+	countersSeededAttribute = "counters_seeded_at"
+	// seedWorkers is the number of records applied concurrently while seeding the counters
+	seedWorkers = 20
+	// maxReportedDiscrepancies limits the discrepancies kept in the reconciliation report, all of them are logged
+	maxReportedDiscrepancies = 100
+)
+
+// CounterDiscrepancy is a counter which differs from the value of the full calculation
+type CounterDiscrepancy struct {
+	MetricType string `json:"metric_type"`
+	ID         string `json:"id"`
+	Attribute  string `json:"attribute"`
+	Calculated int64  `json:"calculated"`
+	Counted    int64  `json:"counted"`
+}
+
+// ReconciliationReport is the result of the comparison of the counters with the full calculation
+type ReconciliationReport struct {
+	CheckedCount       int64                 `json:"checked_count"`
+	DiscrepanciesCount int64                 `json:"discrepancies_count"`
+	Discrepancies      []*CounterDiscrepancy `json:"discrepancies"`
+	CreatedAt          string                `json:"created_at"`
+}
+
+func numberAttribute(item map[string]*dynamodb.AttributeValue, name string) int64 {
+	av, ok := item[name]
+	if !ok || av.N == nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(*av.N, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// compareCounters returns the counted attributes of the metric item which differ between the full calculation and
+// the counters - a missing item or attribute counts as zero
+func compareCounters(metricType, id string, calculated, counted map[string]*dynamodb.AttributeValue) []*CounterDiscrepancy {
+	var discrepancies []*CounterDiscrepancy
+	for _, attribute := range countedAttributes[metricType] {
+		calculatedValue := numberAttribute(calculated, attribute)
+		countedValue := numberAttribute(counted, attribute)
+		if calculatedValue != countedValue {
+			discrepancies = append(discrepancies, &CounterDiscrepancy{
+				MetricType: metricType,
+				ID:         id,
+				Attribute:  attribute,
+				Calculated: calculatedValue,
+				Counted:    countedValue,
+			})
+		}
+	}
+	return discrepancies
+}
+
+// calculatedItems returns the metric items of the full calculation by metric type and ID
+func calculatedItems(metrics *Metrics) (map[string]map[string]map[string]*dynamodb.AttributeValue, error) {
+	items := map[string]map[string]map[string]*dynamodb.AttributeValue{
+		MetricTypeTotalCount: {},
+		MetricTypeProject:    {},
+		MetricTypeCompany:    {},
+	}
+
+	av, err := dynamodbattribute.MarshalMap(metrics.TotalCountMetrics)
+	if err != nil {
+		return nil, err
+	}
+	items[MetricTypeTotalCount][IDTotalCount] = av
+
+	for id, pm := range metrics.ProjectMetrics.ProjectMetrics {
+		av, err := dynamodbattribute.MarshalMap(pm)
+		if err != nil {
+			return nil, err
+		}
+		items[MetricTypeProject][id] = av
+	}
+	for id, cm := range metrics.CompanyMetrics.CompanyMetrics {
+		av, err := dynamodbattribute.MarshalMap(cm)
+		if err != nil {
+			return nil, err
+		}
+		items[MetricTypeCompany][id] = av
+	}
+	return items, nil
+}
+
+// getMetricItems returns the stored metric items of the metric type by ID
+func (repo *repo) getMetricItems(metricType string) (map[string]map[string]*dynamodb.AttributeValue, error) {
+	expr, err := expression.NewBuilder().WithKeyCondition(expression.Key("metric_type").Equal(expression.Value(metricType))).Build()
+	if err != nil {
+		return nil, err
+	}
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.metricTableName),
+	}
+
+	items := make(map[string]map[string]*dynamodb.AttributeValue)
+	for {
+		results, err := repo.dynamoDBClient.Query(queryInput)
+		if err != nil {
+			log.Warnf("error retrieving %s metrics, error: %v", metricType, err)
+			return nil, err
+		}
+		for _, item := range results.Items {
+			if av, ok := item["id"]; ok && av.S != nil {
+				items[*av.S] = item
+			}
+		}
+		if len(results.LastEvaluatedKey) != 0 {
+			queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+		} else {
+			break
+		}
+	}
+	return items, nil
+}
+
+// reconcileCounters compares the counters with the full calculation, logs the discrepancies and saves the report -
+// the counters are not changed, the stream events remain the source of the counts
+func (repo *repo) reconcileCounters(metrics *Metrics) (*ReconciliationReport, error) {
+	t := time.Now()
+	calculated, err := calculatedItems(metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconciliationReport{Discrepancies: []*CounterDiscrepancy{}}
+	for _, metricType := range []string{MetricTypeTotalCount, MetricTypeProject, MetricTypeCompany} {
+		counted, err := repo.getMetricItems(metricType)
+		if err != nil {
+			return nil, err
+		}
+
+		ids := utils.NewStringSet()
+		for id := range calculated[metricType] {
+			ids.Add(id)
+		}
+		for id := range counted {
+			ids.Add(id)
+		}
+		list := ids.List()
+		sort.Strings(list)
+
+		for _, id := range list {
+			report.CheckedCount++
+			for _, d := range compareCounters(metricType, id, calculated[metricType][id], counted[id]) {
+				log.WithFields(logrus.Fields{
+					"functionName": "reconcileCounters",
+					"metricType":   d.MetricType,
+					"id":           d.ID,
+					"attribute":    d.Attribute,
+					"calculated":   d.Calculated,
+					"counted":      d.Counted,
+				}).Warn("metrics counter discrepancy")
+				report.DiscrepanciesCount++
+				if len(report.Discrepancies) < maxReportedDiscrepancies {
+					report.Discrepancies = append(report.Discrepancies, d)
+				}
+			}
+		}
+	}
+
+	_, report.CreatedAt = utils.CurrentTime()
+	av, err := dynamodbattribute.MarshalMap(report)
+	if err != nil {
+		return nil, err
+	}
+	addIDTypeTime(av, IDReconciliation, MetricTypeReconciliation)
+	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(repo.metricTableName),
+	})
+	if err != nil {
+		log.Error("cannot put the reconciliation report in dynamodb", err)
+		return nil, err
+	}
+
+	log.Printf("reconciling %d metrics took :%s, %d discrepancies \n", report.CheckedCount, time.Since(t).String(), report.DiscrepanciesCount)
+	return report, nil
+}
+
+// countersSeeded returns true if the counters have been seeded from the tables
+func (repo *repo) countersSeeded() (bool, error) {
+	result, err := repo.dynamoDBClient.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(repo.metricTableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"metric_type": {S: aws.String(MetricTypeTotalCount)},
+			"id":          {S: aws.String(IDTotalCount)},
+		},
+	})
+	if err != nil {
+		return false, err
+	}
+	_, ok := result.Item[countersSeededAttribute]
+	return ok, nil
+}
+
+// seedCounters resets the counters and their members, then applies every record of the signatures, repositories and
+// CLA Groups tables as if it was inserted - the counters kept by the previous snapshots are discarded. The stream
+// events processed while seeding are applied on top, adding a member twice has no effect. An interrupted seeding
+// starts over on the next run.
+func (repo *repo) seedCounters(usersCache map[string]*ItemUser) error {
+	t := time.Now()
+	log.Println("seeding metrics counters")
+	err := repo.resetCounters()
+	if err != nil {
+		return err
+	}
+
+	var sigs []*ItemSignature
+	filter := expression.Name("signature_signed").Equal(expression.Value(true)).
+		And(expression.Name("signature_approved").Equal(expression.Value(true)))
+	err = repo.scanTable(fmt.Sprintf("cla-%s-signatures", repo.stage), expression.NamesList(
+		expression.Name("signature_id"),
+		expression.Name("signature_reference_id"),
+		expression.Name("signature_acl"),
+		expression.Name("signature_user_ccla_company_id"),
+		expression.Name("signature_type"),
+		expression.Name("signature_reference_type"),
+		expression.Name("signature_project_id"),
+		expression.Name("signature_signed"),
+		expression.Name("signature_approved"),
+	), &filter, &sigs)
+	if err != nil {
+		return err
+	}
+
+	var repos []*ItemRepository
+	err = repo.scanTable(fmt.Sprintf("cla-%s-repositories", repo.stage), expression.NamesList(
+		expression.Name("repository_id"),
+		expression.Name("repository_project_id"),
+		expression.Name("enabled"),
+	), nil, &repos)
+	if err != nil {
+		return err
+	}
+
+	var claGroups []*ItemProject
+	err = repo.scanTable(fmt.Sprintf("cla-%s-projects", repo.stage), expression.NamesList(
+		expression.Name("project_id"),
+		expression.Name("project_external_id"),
+		expression.Name("project_name"),
+		expression.Name("project_live"),
+	), nil, &claGroups)
+	if err != nil {
+		return err
+	}
+
+	var members []counterMember
+	for _, sig := range sigs {
+		var claManagers []string
+		for _, lfUsername := range sig.SignatureACL {
+			if _, ok := usersCache[lfUsername]; ok {
+				claManagers = append(claManagers, lfUsername)
+			}
+		}
+		members = append(members, signatureMembers(sig, claManagers)...)
+	}
+	for _, r := range repos {
+		members = append(members, repositoryMembers(r)...)
+	}
+	for _, claGroup := range claGroups {
+		members = append(members, claGroupMembers(claGroup)...)
+	}
+
+	err = repo.addMembers(members)
+	if err != nil {
+		return err
+	}
+
+	_, seededAt := utils.CurrentTime()
+	err = repo.setMetricAttributes(MetricTypeTotalCount, IDTotalCount, map[string]*dynamodb.AttributeValue{
+		countersSeededAttribute: {S: aws.String(seededAt)},
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("seeding %d metrics counter members took :%s \n", len(members), time.Since(t).String())
+	return nil
+}
+
+// addMembers adds the members concurrently and returns the first error
+func (repo *repo) addMembers(members []counterMember) error {
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+	work := make(chan counterMember)
+
+	for i := 0; i < seedWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for m := range work {
+				if err := repo.addMember(m); err != nil {
+					errOnce.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+	for _, m := range members {
+		work <- m
+	}
+	close(work)
+	wg.Wait()
+	return firstErr
+}
+
+// applyGerritInstances adds the gerrit instances to the repositories counters of the CLA Groups - the gerrit
+// instances table has no stream handler, a removed instance is reported by the reconciliation
+func (repo *repo) applyGerritInstances() error {
+	var gerritInstances []*ItemGerritInstance
+	err := repo.scanTable(fmt.Sprintf("cla-%s-gerrit-instances", repo.stage), expression.NamesList(
+		expression.Name("gerrit_id"),
+		expression.Name("project_id"),
+	), nil, &gerritInstances)
+	if err != nil {
+		return err
+	}
+	var members []counterMember
+	for _, gi := range gerritInstances {
+		members = append(members, gerritInstanceMembers(gi)...)
+	}
+	return repo.addMembers(members)
+}
+
+// resetCounters removes the counted attributes of the metric items and deletes the counter members
+func (repo *repo) resetCounters() error {
+	for metricType, attributes := range countedAttributes {
+		items, err := repo.getMetricItems(metricType)
+		if err != nil {
+			return err
+		}
+		names := make(map[string]*string, len(attributes))
+		updateExpression := "REMOVE"
+		for i, attribute := range attributes {
+			if i > 0 {
+				updateExpression += ","
+			}
+			updateExpression += fmt.Sprintf(" #a%d", i)
+			names[fmt.Sprintf("#a%d", i)] = aws.String(attribute)
+		}
+		for id := range items {
+			_, err = repo.dynamoDBClient.UpdateItem(&dynamodb.UpdateItemInput{
+				TableName: aws.String(repo.metricTableName),
+				Key: map[string]*dynamodb.AttributeValue{
+					"metric_type": {S: aws.String(metricType)},
+					"id":          {S: aws.String(id)},
+				},
+				UpdateExpression:         aws.String(updateExpression),
+				ExpressionAttributeNames: names,
+			})
+			if err != nil {
+				log.Warnf("error resetting the counters of the %s metric %s, error: %v", metricType, id, err)
+				return err
+			}
+		}
+	}
+
+	scanInput := &dynamodb.ScanInput{
+		TableName:            aws.String(repo.membersTableName),
+		ProjectionExpression: aws.String("counter_id, member_id"),
+	}
+	for {
+		results, err := repo.dynamoDBClient.Scan(scanInput)
+		if err != nil {
+			log.Warnf("error retrieving the metrics counter members, error: %v", err)
+			return err
+		}
+		for _, item := range results.Items {
+			_, err = repo.dynamoDBClient.DeleteItem(&dynamodb.DeleteItemInput{
+				TableName: aws.String(repo.membersTableName),
+				Key:       item,
+			})
+			if err != nil {
+				log.Warnf("error deleting the metrics counter member, error: %v", err)
+				return err
+			}
+		}
+		if len(results.LastEvaluatedKey) != 0 {
+			scanInput.ExclusiveStartKey = results.LastEvaluatedKey
+		} else {
+			break
+		}
+	}
+	return nil
+}
@@ -18,11 +18,11 @@ import (
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
 )
 
 // errors
@@ -44,6 +44,9 @@ const (
 // Repository provides methods for calculation,storage and retrieval of metrics
 type Repository interface {
 	CalculateAndSaveMetrics() error
+	ApplySignatureChange(oldSig, newSig *ItemSignature) error
+	ApplyRepositoryChange(oldRepo, newRepo *ItemRepository) error
+	ApplyCLAGroupChange(oldCLAGroup, newCLAGroup *ItemProject) error
 	GetClaManagerDistribution() (*ClaManagersDistribution, error)
 	GetTotalCountMetrics() (*TotalCountMetrics, error)
 	GetCompanyMetrics() ([]*CompanyMetric, error)
@@ -52,21 +55,26 @@ type Repository interface {
 	GetProjectMetric(projectID string) (*ProjectMetric, error)
 	GetProjectMetricBySalesForceID(salesforceID string) ([]*ProjectMetric, error)
 	ListCompanyProjectMetrics(companyID string) ([]*CompanyProjectMetric, error)
+	GetMetricsHistory(scope, id, fromDate, toDate string) ([]*MetricsHistory, error)
 }
 
 type repo struct {
 	metricTableName       string
-	dynamoDBClient        *dynamodb.DynamoDB
+	historyTableName      string
+	membersTableName      string
+	dynamoDBClient        storage.Driver
 	stage                 string
 	apiGatewayURL         string
 	projectsClaGroupsRepo projects_cla_groups.Repository
 }
 
 // NewRepository creates new metrics repository
-func NewRepository(awsSession *session.Session, stage string, apiGwURL string, pcgRepo projects_cla_groups.Repository) Repository {
+func NewRepository(dynamoDBClient storage.Driver, stage string, apiGwURL string, pcgRepo projects_cla_groups.Repository) Repository {
 	return &repo{
-		dynamoDBClient:        dynamodb.New(awsSession),
+		dynamoDBClient:        dynamoDBClient,
 		metricTableName:       fmt.Sprintf("cla-%s-metrics", stage),
+		historyTableName:      fmt.Sprintf("cla-%s-metrics-history", stage),
+		membersTableName:      fmt.Sprintf("cla-%s-metrics-members", stage),
 		stage:                 stage,
 		apiGatewayURL:         apiGwURL,
 		projectsClaGroupsRepo: pcgRepo,
@@ -721,20 +729,15 @@ func (repo *repo) scanTable(tableName string, projection expression.ProjectionBu
 	return nil
 }
 
-func (repo *repo) calculateMetrics() (*Metrics, error) {
+func (repo *repo) calculateMetrics(usersCache map[string]*ItemUser) (*Metrics, error) {
 	metrics := newMetrics()
 	t := time.Now()
-	// build users cache by lf-username
-	usersCache, err := repo.cacheUsersByLfUsername()
-	if err != nil {
-		return nil, err
-	}
 
 	log.Debug("Calculating CLA Group metrics...")
 	// calculate project count
 	// create structure for projectMetric
 	// cache project membership info
-	err = repo.processProjectsTable(metrics)
+	err := repo.processProjectsTable(metrics)
 	if err != nil {
 		return nil, err
 	}
@@ -878,6 +881,18 @@ func addIDTypeTime(item map[string]*dynamodb.AttributeValue, id string, metricTy
 	utils.AddStringAttribute(item, "created_at", ctime)
 }
 
+// saveMetricItem writes the attributes of the full calculation to the metric item - the counted attributes are kept
+// as they are maintained from the stream events
+func (repo *repo) saveMetricItem(item map[string]*dynamodb.AttributeValue, id string, metricType string) error {
+	addIDTypeTime(item, id, metricType)
+	delete(item, "id")
+	delete(item, "metric_type")
+	for _, attribute := range countedAttributes[metricType] {
+		delete(item, attribute)
+	}
+	return repo.setMetricAttributes(metricType, id, item)
+}
+
 func (repo *repo) saveTotalMetrics(tm *TotalCountMetrics) error {
 	log.Println("saving total count metrics")
 	tm.RepositoriesCount = tm.GithubRepositoriesCount + tm.GerritRepositoriesCount
@@ -885,11 +900,7 @@ func (repo *repo) saveTotalMetrics(tm *TotalCountMetrics) error {
 	if err != nil {
 		return err
 	}
-	addIDTypeTime(av, IDTotalCount, MetricTypeTotalCount)
-	_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
-		Item:      av,
-		TableName: aws.String(repo.metricTableName),
-	})
+	err = repo.saveMetricItem(av, IDTotalCount, MetricTypeTotalCount)
 	if err != nil {
 		log.Error("cannot put total_metrics in dynamodb", err)
 		return err
@@ -905,11 +916,7 @@ func (repo *repo) saveCompaniesMetrics(companyMetrics *CompanyMetrics) error {
 		if err != nil {
 			return err
 		}
-		addIDTypeTime(av, id, MetricTypeCompany)
-		_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
-			Item:      av,
-			TableName: aws.String(repo.metricTableName),
-		})
+		err = repo.saveMetricItem(av, id, MetricTypeCompany)
 		if err != nil {
 			log.Printf("cannot put company_metric in dynamodb, metric = %v, error = %s\n", cm, err.Error())
 			return err
@@ -945,11 +952,7 @@ func (repo *repo) saveProjectMetrics(projectMetrics *ProjectMetrics) error {
 		if err != nil {
 			return err
 		}
-		addIDTypeTime(av, id, MetricTypeProject)
-		_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
-			Item:      av,
-			TableName: aws.String(repo.metricTableName),
-		})
+		err = repo.saveMetricItem(av, id, MetricTypeProject)
 		if err != nil {
 			log.Printf("cannot put project_metric in dynamodb, metric = %v, error = %s\n", cm, err.Error())
 			return err
@@ -1094,16 +1097,76 @@ func (repo *repo) projectHelperMap(in *CompanyProjectMetrics, claGroupMapping ma
 	return filterProjectMap
 }
 
+// saveMetricsHistory writes the daily rollups of the metrics, the rollups are kept when the snapshot is cleared
+func (repo *repo) saveMetricsHistory(metrics *Metrics) error {
+	t := time.Now()
+	log.Println("saving metrics_history")
+	claGroupMapping, err := repo.getClaGroupProjectsMapping()
+	if err != nil {
+		return err
+	}
+
+	date := time.Now().UTC().Format(HistoryDateFormat)
+	for _, rollup := range metrics.historyRollups(date, claGroupMapping) {
+		av, err := dynamodbattribute.MarshalMap(rollup)
+		if err != nil {
+			return err
+		}
+		_, err = repo.dynamoDBClient.PutItem(&dynamodb.PutItemInput{
+			Item:      av,
+			TableName: aws.String(repo.historyTableName),
+		})
+		if err != nil {
+			log.Printf("cannot put metrics_history in dynamodb, rollup = %v, error = %s\n", rollup, err.Error())
+			return err
+		}
+	}
+	log.Printf("saving metrics_history took :%s \n", time.Since(t).String())
+	return nil
+}
+
+// CalculateAndSaveMetrics runs the full calculation of the metrics - the counters are seeded from the tables on the
+// first run, then maintained from the stream events and only reconciled with the full calculation, the other metrics
+// such as the names and the CLA manager distribution are saved from the full calculation
 func (repo *repo) CalculateAndSaveMetrics() error {
 	timeBeforeStartingMetricsCalculation := time.Now()
-	m, err := repo.calculateMetrics()
+	// build users cache by lf-username
+	usersCache, err := repo.cacheUsersByLfUsername()
+	if err != nil {
+		return err
+	}
+	m, err := repo.calculateMetrics(usersCache)
+	if err != nil {
+		return err
+	}
+	seeded, err := repo.countersSeeded()
+	if err != nil {
+		return err
+	}
+	if !seeded {
+		err = repo.seedCounters(usersCache)
+		if err != nil {
+			return err
+		}
+	}
+	err = repo.applyGerritInstances()
 	if err != nil {
 		return err
 	}
+	if seeded {
+		_, err = repo.reconcileCounters(m)
+		if err != nil {
+			return err
+		}
+	}
 	err = repo.saveMetrics(m)
 	if err != nil {
 		return err
 	}
+	err = repo.saveMetricsHistory(m)
+	if err != nil {
+		return err
+	}
 	err = repo.clearOldMetrics(timeBeforeStartingMetricsCalculation)
 	if err != nil {
 		return err
@@ -1126,6 +1189,8 @@ func (repo *repo) GetTotalCountMetrics() (*TotalCountMetrics, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the GitHub repositories are counted from the stream events, the gerrit instances by the full calculation
+	out.RepositoriesCount = out.GithubRepositoriesCount + out.GerritRepositoriesCount
 	return &out, nil
 }
 
@@ -1353,3 +1418,45 @@ func (repo *repo) ListCompanyProjectMetrics(companyID string) ([]*CompanyProject
 	}
 	return out, nil
 }
+
+// GetMetricsHistory returns the daily rollups of the entity between the dates, both included, sorted by date
+func (repo *repo) GetMetricsHistory(scope, id, fromDate, toDate string) ([]*MetricsHistory, error) {
+	condition := expression.Key("series_id").Equal(expression.Value(historySeriesID(scope, id))).
+		And(expression.Key("date").Between(expression.Value(fromDate), expression.Value(toDate)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.Warnf("error building expression for metrics history query, error: %v", err)
+		return nil, err
+	}
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(repo.historyTableName),
+	}
+
+	out := make([]*MetricsHistory, 0)
+	for {
+		results, errQuery := repo.dynamoDBClient.Query(queryInput)
+		if errQuery != nil {
+			log.Warnf("error retrieving metrics history, error: %v", errQuery)
+			return nil, errQuery
+		}
+
+		var rollups []*MetricsHistory
+		err := dynamodbattribute.UnmarshalListOfMaps(results.Items, &rollups)
+		if err != nil {
+			log.Warnf("error unmarshalling metrics history from database. error: %v", err)
+			return nil, err
+		}
+		out = append(out, rollups...)
+
+		if len(results.LastEvaluatedKey) != 0 {
+			queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+		} else {
+			break
+		}
+	}
+	return out, nil
+}
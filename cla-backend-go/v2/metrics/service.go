@@ -5,10 +5,12 @@ package metrics
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
@@ -36,6 +38,7 @@ type Service interface {
 	GetTopProjects() (*models.TopProjects, error)
 	ListProjectMetrics(paramPageSize *int64, paramNextKey *string) (*models.ListProjectMetric, error)
 	ListCompanyProjectMetrics(companyID string, projectSFID string) (*models.CompanyProjectMetrics, error)
+	GetMetricsHistory(scope, id, fromDate, toDate, granularity string) (*models.MetricsHistory, error)
 }
 
 type service struct {
@@ -325,3 +328,56 @@ func (s *service) ListCompanyProjectMetrics(companyID string, projectSFID string
 	})
 	return out, nil
 }
+
+// GetMetricsHistory returns the series of the rollups of the entity between the dates, both included, with one point
+// per day, week or month
+func (s *service) GetMetricsHistory(scope, id, fromDate, toDate, granularity string) (*models.MetricsHistory, error) {
+	if !validHistoryScope(scope) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHistoryScope, scope)
+	}
+	if scope == HistoryScopeTotal {
+		id = IDHistoryTotal
+	}
+	if granularity == "" {
+		granularity = HistoryGranularityDay
+	}
+	if granularity != HistoryGranularityDay && granularity != HistoryGranularityWeek && granularity != HistoryGranularityMonth {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidHistoryGranularity, granularity)
+	}
+	from, err := time.Parse(HistoryDateFormat, fromDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid from date %s", ErrInvalidHistoryDateRange, fromDate)
+	}
+	to, err := time.Parse(HistoryDateFormat, toDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid to date %s", ErrInvalidHistoryDateRange, toDate)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: the from date %s is after the to date %s", ErrInvalidHistoryDateRange, fromDate, toDate)
+	}
+
+	rollups, err := s.metricsRepo.GetMetricsHistory(scope, id, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	points, err := aggregateHistory(rollups, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &models.MetricsHistory{
+		Scope:       scope,
+		ID:          id,
+		Granularity: granularity,
+		From:        fromDate,
+		To:          toDate,
+		List:        make([]*models.MetricsHistoryPoint, 0, len(points)),
+	}
+	for _, point := range points {
+		if point.Name != "" {
+			out.Name = point.Name
+		}
+		out.List = append(out.List, point.toModel())
+	}
+	return out, nil
+}
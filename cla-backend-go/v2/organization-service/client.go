@@ -18,6 +18,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/communitybridge/easycla/cla-backend-go/token"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/organization-service/client"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/organization-service/client/organizations"
@@ -44,11 +45,7 @@ var (
 func InitClient(APIGwURL string, eventService events.Service) {
 	APIGwURL = strings.ReplaceAll(APIGwURL, "https://", "")
 	organizationServiceClient = &Client{
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     APIGwURL,
-			BasePath: "organization-service",
-			Schemes:  []string{"https"},
-		}),
+		cl: client.New(telemetry.NewClientTransport("organization-service", APIGwURL, "organization-service", []string{"https"}), strfmt.Default),
 	}
 	v1EventService = eventService
 }
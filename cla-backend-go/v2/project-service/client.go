@@ -9,6 +9,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 
 	"github.com/go-openapi/runtime"
@@ -40,11 +41,7 @@ var (
 func InitClient(APIGwURL string) {
 	APIGwURL = strings.ReplaceAll(APIGwURL, "https://", "")
 	projectServiceClient = &Client{
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     APIGwURL,
-			BasePath: "project-service/v1",
-			Schemes:  []string{"https"},
-		}),
+		cl: client.New(telemetry.NewClientTransport("project-service", APIGwURL, "project-service/v1", []string{"https"}), strfmt.Default),
 	}
 }
 
@@ -191,6 +191,81 @@ func Configure(api *operations.EasyclaAPI, service Service, eventService events.
 			return github_repositories.NewDeleteProjectGithubRepositoryNoContent()
 		})
 
+	api.GithubRepositoriesUpdateProjectGithubRepositoryEnforcementModeHandler = github_repositories.UpdateProjectGithubRepositoryEnforcementModeHandlerFunc(
+		func(params github_repositories.UpdateProjectGithubRepositoryEnforcementModeParams, authUser *auth.User) middleware.Responder {
+			reqID := utils.GetRequestID(params.XREQUESTID)
+			utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+			ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+			enforcementMode := utils.StringValue(params.GithubRepositoryEnforcementModeInput.EnforcementMode)
+			f := logrus.Fields{
+				"functionName":    "GithubRepositoriesUpdateProjectGithubRepositoryEnforcementModeHandler",
+				utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
+				"authUser":        authUser.UserName,
+				"authEmail":       authUser.Email,
+				"projectSFID":     params.ProjectSFID,
+				"repositoryID":    params.RepositoryID,
+				"enforcementMode": enforcementMode,
+			}
+
+			if !utils.IsUserAuthorizedForProjectTree(authUser, params.ProjectSFID, utils.ALLOW_ADMIN_SCOPE) {
+				msg := fmt.Sprintf("user %s does not have access to Update GitHub Repository Enforcement Mode with Project scope of %s",
+					authUser.UserName, params.ProjectSFID)
+				log.WithFields(f).Debug(msg)
+				return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeForbidden().WithPayload(
+					utils.ErrorResponseForbidden(reqID, msg))
+			}
+
+			ghRepo, err := service.GetRepository(ctx, params.RepositoryID)
+			if err != nil {
+				if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+					msg := fmt.Sprintf("repository not found for projectSFID: %s, repository: %s", params.ProjectSFID, params.RepositoryID)
+					log.WithFields(f).WithError(err).Warn(msg)
+					return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeNotFound().WithPayload(
+						utils.ErrorResponseNotFound(reqID, msg))
+				}
+
+				msg := fmt.Sprintf("problem looking up repository for projectSFID: %s, repository: %s", params.ProjectSFID, params.RepositoryID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeInternalServerError().WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+
+			result, err := service.UpdateEnforcementMode(ctx, params.ProjectSFID, params.RepositoryID, enforcementMode)
+			if err != nil {
+				msg := fmt.Sprintf("problem updating the enforcement mode for projectSFID: %s, repository: %s", params.ProjectSFID, params.RepositoryID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				if errors.Is(err, repositories.ErrGithubRepositoryNotFound) {
+					return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeNotFound().WithPayload(
+						utils.ErrorResponseNotFound(reqID, msg))
+				}
+				return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeBadRequest().WithPayload(
+					utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+			}
+
+			eventService.LogEvent(&events.LogEventArgs{
+				EventType:         events.RepositoryEnforcementModeUpdated,
+				ExternalProjectID: params.ProjectSFID,
+				ProjectID:         ghRepo.RepositoryProjectID,
+				LfUsername:        authUser.UserName,
+				EventData: &events.RepositoryEnforcementModeUpdatedEventData{
+					RepositoryName:     ghRepo.RepositoryName,
+					OldEnforcementMode: ghRepo.EnforcementMode,
+					NewEnforcementMode: result.EnforcementMode,
+				},
+			})
+
+			response := &models.GithubRepository{}
+			err = copier.Copy(response, result)
+			if err != nil {
+				msg := fmt.Sprintf("problem converting response for projectSFID: %s", params.ProjectSFID)
+				log.WithFields(f).WithError(err).Warn(msg)
+				return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeInternalServerError().WithPayload(
+					utils.ErrorResponseInternalServerErrorWithError(reqID, msg, err))
+			}
+
+			return github_repositories.NewUpdateProjectGithubRepositoryEnforcementModeOK().WithPayload(response)
+		})
+
 	api.GithubRepositoriesGetProjectGithubRepositoryBranchProtectionHandler = github_repositories.GetProjectGithubRepositoryBranchProtectionHandlerFunc(
 		func(params github_repositories.GetProjectGithubRepositoryBranchProtectionParams, authUser *auth.User) middleware.Responder {
 			reqID := utils.GetRequestID(params.XREQUESTID)
@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 
 	"github.com/sirupsen/logrus"
 
@@ -37,6 +36,7 @@ type Service interface {
 	ListProjectRepositories(ctx context.Context, projectSFID string) (*v1Models.ListGithubRepositories, error)
 	GetRepository(ctx context.Context, repositoryID string) (*v1Models.GithubRepository, error)
 	DisableCLAGroupRepositories(ctx context.Context, claGroupID string) error
+	UpdateEnforcementMode(ctx context.Context, projectSFID, repositoryID, enforcementMode string) (*v1Models.GithubRepository, error)
 	GetProtectedBranch(ctx context.Context, projectSFID, repositoryID string) (*v2Models.GithubRepositoryBranchProtection, error)
 	UpdateProtectedBranch(ctx context.Context, projectSFID, repositoryID string, input *v2Models.GithubRepositoryBranchProtectionInput) (*v2Models.GithubRepositoryBranchProtection, error)
 }
@@ -55,9 +55,12 @@ type service struct {
 }
 
 var (
-	requiredBranchProtectionChecks = []string{"EasyCLA"}
 	// ErrInvalidBranchProtectionName is returned when invalid protection option is supplied
 	ErrInvalidBranchProtectionName = errors.New("invalid protection option")
+	// ErrBranchProtectionNotSupported is returned for repositories which aren't hosted on GitHub
+	ErrBranchProtectionNotSupported = errors.New("branch protection is only supported for github repositories")
+	// ErrEnforcementModeNotSupported is returned for repositories which aren't hosted on GitHub
+	ErrEnforcementModeNotSupported = errors.New("the enforcement mode is only supported for github repositories")
 )
 
 // NewService creates a new githubOrganizations service
@@ -113,23 +116,25 @@ func (s *service) AddGithubRepository(ctx context.Context, projectSFID string, i
 	if len(org.List) == 0 {
 		return nil, errors.New("github app not installed on github organization")
 	}
-	repoGithubID, err := strconv.ParseInt(utils.StringValue(input.RepositoryGithubID), 10, 64)
+	organizationType := v1Repositories.OrganizationType(org.List[0])
+	provider, err := v1Repositories.GetProvider(organizationType)
 	if err != nil {
-		log.WithFields(f).WithError(err).Warn("unable to convert repository github ID to an integer - invalid value")
+		log.WithFields(f).WithError(err).Warn("unable to load the repository provider")
 		return nil, err
 	}
-	ghRepo, err := github.GetRepositoryByExternalID(ctx, org.List[0].OrganizationInstallationID, repoGithubID)
+	providerRepo, err := provider.GetRepository(ctx, org.List[0], utils.StringValue(input.RepositoryGithubID))
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("unable to get repository by external ID")
 		return nil, err
 	}
 	in := &v1Models.GithubRepositoryInput{
 		RepositoryExternalID:       input.RepositoryGithubID,
-		RepositoryName:             ghRepo.FullName,
+		RepositoryName:             aws.String(providerRepo.FullName),
 		RepositoryOrganizationName: input.GithubOrganizationName,
 		RepositoryProjectID:        input.ClaGroupID,
-		RepositoryType:             aws.String("github"),
-		RepositoryURL:              ghRepo.HTMLURL,
+		RepositoryType:             aws.String(organizationType),
+		RepositoryURL:              aws.String(providerRepo.HTMLURL),
+		EnforcementMode:            input.EnforcementMode,
 	}
 	return s.repo.AddGithubRepository(ctx, externalProjectID, projectSFID, in)
 }
@@ -257,6 +262,39 @@ func (s *service) GetRepository(ctx context.Context, repositoryID string) (*v1Mo
 	return s.repo.GetRepository(ctx, repositoryID)
 }
 
+// UpdateEnforcementMode updates the enforcement mode of the repository of the project - the status check of the new
+// mode is only required once the branch protection of the repository is updated
+func (s *service) UpdateEnforcementMode(ctx context.Context, projectSFID, repositoryID, enforcementMode string) (*v1Models.GithubRepository, error) {
+	f := logrus.Fields{
+		"functionName":    "repositories.UpdateEnforcementMode",
+		utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
+		"projectSFID":     projectSFID,
+		"repositoryID":    repositoryID,
+		"enforcementMode": enforcementMode,
+	}
+
+	if !v1Repositories.IsValidEnforcementMode(enforcementMode) {
+		return nil, v1Repositories.ErrInvalidEnforcementMode
+	}
+
+	githubRepository, err := s.getGithubRepo(ctx, projectSFID, repositoryID)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warnf("fetching repository %s, failed", repositoryID)
+		if errors.Is(err, ErrBranchProtectionNotSupported) {
+			return nil, ErrEnforcementModeNotSupported
+		}
+		return nil, err
+	}
+
+	err = s.repo.UpdateEnforcementMode(ctx, githubRepository.RepositoryID, v1Repositories.EnforcementMode(enforcementMode))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem updating the enforcement mode of the repository")
+		return nil, err
+	}
+
+	return s.repo.GetRepository(ctx, repositoryID)
+}
+
 func (s *service) GetProtectedBranch(ctx context.Context, projectSFID, repositoryID string) (*v2Models.GithubRepositoryBranchProtection, error) {
 	f := logrus.Fields{
 		"functionName":   "repositories.GetProtectedBranch",
@@ -304,7 +342,7 @@ func (s *service) GetProtectedBranch(ctx context.Context, projectSFID, repositor
 		result.EnforceAdmin = true
 	}
 
-	requiredChecks := requiredBranchProtectionChecks
+	requiredChecks := v1Repositories.StatusCheckNames(githubRepository.EnforcementMode)
 	requiredChecksResult := s.getRequiredProtectedBranchCheckStatus(branchProtection, requiredChecks)
 	result.StatusChecks = requiredChecksResult
 
@@ -349,9 +387,10 @@ func (s *service) UpdateProtectedBranch(ctx context.Context, projectSFID, reposi
 	var disabledChecks []string
 	if input.StatusChecks != nil {
 		for _, inputCheck := range input.StatusChecks {
-			// we want to make sure we only mutate checks related to lf
+			// we want to make sure we only mutate checks related to lf - the check of another enforcement mode
+			// can be disabled after the mode of the repository changed
 			var found bool
-			for _, rc := range requiredBranchProtectionChecks {
+			for _, rc := range v1Repositories.AllStatusCheckNames() {
 				if rc == *inputCheck.Name {
 					found = true
 					break
@@ -408,6 +447,11 @@ func (s *service) getGithubRepo(ctx context.Context, projectSFID, repositoryID s
 		return nil, errors.New(msg)
 	}
 
+	if githubRepository.RepositoryType != "" && githubRepository.RepositoryType != utils.GitHubType {
+		log.WithFields(f).Warnf("repository %s is a %s repository", repositoryID, githubRepository.RepositoryType)
+		return nil, ErrBranchProtectionNotSupported
+	}
+
 	return githubRepository, nil
 }
 
@@ -0,0 +1,197 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/resign_campaign"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	v1Project "github.com/communitybridge/easycla/cla-backend-go/project"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/sirupsen/logrus"
+)
+
+// Configure setup the re-sign campaign API handlers
+func Configure(api *operations.EasyclaAPI, service Service, projectService v1Project.Service) {
+	api.ResignCampaignGetTemplateImpactHandler = resign_campaign.GetTemplateImpactHandlerFunc(func(params resign_campaign.GetTemplateImpactParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "ResignCampaignGetTemplateImpactHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"claType":        params.ClaType,
+			"authUserName":   user.UserName,
+		}
+
+		claGroup, err := projectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA Group by ID")
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return resign_campaign.NewGetTemplateImpactNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return resign_campaign.NewGetTemplateImpactInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorized(user, claGroup) {
+			return resign_campaign.NewGetTemplateImpactForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Get Template Impact", claGroup))
+		}
+
+		impact, err := service.GetTemplateImpact(ctx, claGroup, params.ClaType)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the template impact")
+			if errors.Is(err, ErrNoDocument) {
+				return resign_campaign.NewGetTemplateImpactBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return resign_campaign.NewGetTemplateImpactInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return resign_campaign.NewGetTemplateImpactOK().WithXRequestID(reqID).WithPayload(impact)
+	})
+
+	api.ResignCampaignStartResignCampaignHandler = resign_campaign.StartResignCampaignHandlerFunc(func(params resign_campaign.StartResignCampaignParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		claType := swag.StringValue(params.Body.ClaType)
+		f := logrus.Fields{
+			"functionName":   "ResignCampaignStartResignCampaignHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"claType":        claType,
+			"authUserName":   user.UserName,
+		}
+
+		claGroup, err := projectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA Group by ID")
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return resign_campaign.NewStartResignCampaignNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return resign_campaign.NewStartResignCampaignInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorized(user, claGroup) {
+			return resign_campaign.NewStartResignCampaignForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Start Re-sign Campaign", claGroup))
+		}
+
+		campaign, err := service.StartCampaign(ctx, claGroup, claType, user.UserName)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem starting the re-sign campaign")
+			switch {
+			case errors.Is(err, ErrCampaignInProgress):
+				return resign_campaign.NewStartResignCampaignConflict().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			case errors.Is(err, ErrNoDocument), errors.Is(err, ErrNoOutdatedSignatures):
+				return resign_campaign.NewStartResignCampaignBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return resign_campaign.NewStartResignCampaignInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return resign_campaign.NewStartResignCampaignOK().WithXRequestID(reqID).WithPayload(campaign)
+	})
+
+	api.ResignCampaignListResignCampaignsHandler = resign_campaign.ListResignCampaignsHandlerFunc(func(params resign_campaign.ListResignCampaignsParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "ResignCampaignListResignCampaignsHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"authUserName":   user.UserName,
+		}
+
+		claGroup, err := projectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA Group by ID")
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return resign_campaign.NewListResignCampaignsNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return resign_campaign.NewListResignCampaignsInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorized(user, claGroup) {
+			return resign_campaign.NewListResignCampaignsForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "List Re-sign Campaigns", claGroup))
+		}
+
+		campaigns, err := service.GetCampaigns(ctx, claGroup, user.UserName)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the re-sign campaigns")
+			return resign_campaign.NewListResignCampaignsInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return resign_campaign.NewListResignCampaignsOK().WithXRequestID(reqID).WithPayload(campaigns)
+	})
+
+	api.ResignCampaignGetResignCampaignHandler = resign_campaign.GetResignCampaignHandlerFunc(func(params resign_campaign.GetResignCampaignParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "ResignCampaignGetResignCampaignHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"campaignID":     params.CampaignID,
+			"authUserName":   user.UserName,
+		}
+
+		claGroup, err := projectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading CLA Group by ID")
+			if errors.Is(err, v1Project.ErrProjectDoesNotExist) {
+				return resign_campaign.NewGetResignCampaignNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, "CLA Group not found", err))
+			}
+			return resign_campaign.NewGetResignCampaignInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if !isUserAuthorized(user, claGroup) {
+			return resign_campaign.NewGetResignCampaignForbidden().WithXRequestID(reqID).WithPayload(forbiddenResponse(reqID, user, "Get Re-sign Campaign", claGroup))
+		}
+
+		campaign, err := service.GetCampaign(ctx, claGroup, params.CampaignID, user.UserName)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the re-sign campaign")
+			if errors.Is(err, ErrCampaignNotFound) {
+				return resign_campaign.NewGetResignCampaignNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return resign_campaign.NewGetResignCampaignInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return resign_campaign.NewGetResignCampaignOK().WithXRequestID(reqID).WithPayload(campaign)
+	})
+}
+
+// isUserAuthorized returns true if the user is an admin or has access to the foundation of the CLA Group
+func isUserAuthorized(user *auth.User, claGroup *v1Models.ClaGroup) bool {
+	return utils.IsUserAdmin(user) || utils.IsUserAuthorizedForProjectTree(user, claGroup.FoundationSFID, utils.ALLOW_ADMIN_SCOPE)
+}
+
+func forbiddenResponse(reqID string, user *auth.User, action string, claGroup *v1Models.ClaGroup) *models.ErrorResponse {
+	return &models.ErrorResponse{
+		Code:       "403",
+		Message:    fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to %s for the CLA Group %s.", user.UserName, action, claGroup.ProjectName),
+		XRequestID: reqID,
+	}
+}
+
+type codedResponse interface {
+	Code() string
+}
+
+func errorResponse(reqID string, err error) *models.ErrorResponse {
+	code := ""
+	if e, ok := err.(codedResponse); ok {
+		code = e.Code()
+	}
+
+	e := models.ErrorResponse{
+		Code:       code,
+		Message:    err.Error(),
+		XRequestID: reqID,
+	}
+
+	return &e
+}
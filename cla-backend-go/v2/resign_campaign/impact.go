@@ -0,0 +1,208 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaign
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	v1Project "github.com/communitybridge/easycla/cla-backend-go/project"
+)
+
+// currentAndPreviousDocuments returns the current document of the CLA type and the document it replaced - the
+// documents are appended to the CLA Group each time a new template is applied, the previous document is nil
+// when the CLA Group only has a single document
+func currentAndPreviousDocuments(ctx context.Context, docs []v1Models.ClaGroupDocument) (v1Models.ClaGroupDocument, *v1Models.ClaGroupDocument, error) {
+	current, err := v1Project.GetCurrentDocument(ctx, docs)
+	if err != nil {
+		return v1Models.ClaGroupDocument{}, nil, err
+	}
+	if current == (v1Models.ClaGroupDocument{}) {
+		return current, nil, ErrNoDocument
+	}
+
+	var others []v1Models.ClaGroupDocument
+	for _, doc := range docs {
+		if doc != current {
+			others = append(others, doc)
+		}
+	}
+	if len(others) == 0 {
+		return current, nil, nil
+	}
+
+	previous, err := v1Project.GetCurrentDocument(ctx, others)
+	if err != nil {
+		return v1Models.ClaGroupDocument{}, nil, err
+	}
+	if previous == (v1Models.ClaGroupDocument{}) {
+		return current, nil, nil
+	}
+	return current, &previous, nil
+}
+
+// documentVersion returns the major.minor version of the document
+func documentVersion(doc v1Models.ClaGroupDocument) string {
+	return fmt.Sprintf("%s.%s", doc.DocumentMajorVersion, doc.DocumentMinorVersion)
+}
+
+// documentChanges lists the attributes which differ between the previous and the current document
+func documentChanges(previous, current v1Models.ClaGroupDocument) []*models.TemplateChange {
+	fields := []struct {
+		name              string
+		previous, current string
+	}{
+		{name: "document_name", previous: previous.DocumentName, current: current.DocumentName},
+		{name: "document_file_id", previous: previous.DocumentFileID, current: current.DocumentFileID},
+		{name: "document_major_version", previous: previous.DocumentMajorVersion, current: current.DocumentMajorVersion},
+		{name: "document_minor_version", previous: previous.DocumentMinorVersion, current: current.DocumentMinorVersion},
+		{name: "document_template_version", previous: templateVersionString(previous), current: templateVersionString(current)},
+		{name: "document_legal_entity_name", previous: previous.DocumentLegalEntityName, current: current.DocumentLegalEntityName},
+		{name: "document_s3_url", previous: previous.DocumentS3URL, current: current.DocumentS3URL},
+	}
+
+	var changes []*models.TemplateChange
+	for _, field := range fields {
+		if field.previous != field.current {
+			changes = append(changes, &models.TemplateChange{
+				Field:         field.name,
+				PreviousValue: field.previous,
+				CurrentValue:  field.current,
+			})
+		}
+	}
+	return changes
+}
+
+func templateVersionString(doc v1Models.ClaGroupDocument) string {
+	if doc.DocumentTemplateVersion == 0 {
+		return ""
+	}
+	return strconv.FormatInt(doc.DocumentTemplateVersion, 10)
+}
+
+// sameStoredTemplate returns true if both documents were generated from versions of the same stored template - the
+// documents keep the template ID as file ID
+func sameStoredTemplate(previous, current v1Models.ClaGroupDocument) bool {
+	return previous.DocumentFileID != "" && previous.DocumentFileID == current.DocumentFileID &&
+		previous.DocumentTemplateVersion > 0 && current.DocumentTemplateVersion > 0
+}
+
+// diffLines returns the lines removed from the previous text prefixed with "- " and the lines added to the current
+// text prefixed with "+ ", in the order of the current text, based on the longest common subsequence of the lines
+func diffLines(previous, current string) []string {
+	a := splitLines(previous)
+	b := splitLines(current)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, "- "+a[i])
+			i++
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, "+ "+b[j])
+	}
+	return diff
+}
+
+// splitLines splits the text in trimmed lines, the blank lines are skipped as they don't change the document
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// isOutdated returns true if the signature was signed on a major version older than the current one - the signatures
+// with a version which can't be parsed are reported as outdated so they get reviewed
+func isOutdated(sig *v1Models.Signature, currentMajorVersion int64) bool {
+	major, err := strconv.ParseInt(sig.SignatureMajorVersion, 10, 64)
+	if err != nil {
+		return true
+	}
+	return major < currentMajorVersion
+}
+
+func toAffectedSignature(sig *v1Models.Signature) *models.AffectedSignature {
+	return &models.AffectedSignature{
+		SignatureID:            sig.SignatureID.String(),
+		SignatureReferenceID:   sig.SignatureReferenceID.String(),
+		SignatureReferenceName: sig.SignatureReferenceName,
+		SignatureMajorVersion:  sig.SignatureMajorVersion,
+		SignatureMinorVersion:  sig.SignatureMinorVersion,
+		SignedOn:               sig.SignedOn,
+	}
+}
+
+// groupByCompany groups the outdated corporate signatures by company, sorted by company name
+func groupByCompany(sigs []*v1Models.Signature) []*models.AffectedCompany {
+	companies := map[string]*models.AffectedCompany{}
+	for _, sig := range sigs {
+		companyID := sig.SignatureReferenceID.String()
+		company, ok := companies[companyID]
+		if !ok {
+			companyName := sig.CompanyName
+			if companyName == "" {
+				companyName = sig.SignatureReferenceName
+			}
+			company = &models.AffectedCompany{
+				CompanyID:   companyID,
+				CompanyName: companyName,
+			}
+			companies[companyID] = company
+		}
+		company.Signatures = append(company.Signatures, toAffectedSignature(sig))
+	}
+
+	result := make([]*models.AffectedCompany, 0, len(companies))
+	for _, company := range companies {
+		result = append(result, company)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CompanyName != result[j].CompanyName {
+			return result[i].CompanyName < result[j].CompanyName
+		}
+		return result[i].CompanyID < result[j].CompanyID
+	})
+	return result
+}
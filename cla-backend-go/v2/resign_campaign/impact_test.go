@@ -0,0 +1,88 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaign
+
+import (
+	"context"
+	"testing"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentAndPreviousDocuments(t *testing.T) {
+	v1 := v1Models.ClaGroupDocument{DocumentName: "v1", DocumentMajorVersion: "1", DocumentMinorVersion: "0", DocumentCreationDate: "2020-01-01T00:00:00Z"}
+	v11 := v1Models.ClaGroupDocument{DocumentName: "v1.1", DocumentMajorVersion: "1", DocumentMinorVersion: "1", DocumentCreationDate: "2020-02-01T00:00:00Z"}
+	v2 := v1Models.ClaGroupDocument{DocumentName: "v2", DocumentMajorVersion: "2", DocumentMinorVersion: "0", DocumentCreationDate: "2020-03-01T00:00:00Z"}
+
+	current, previous, err := currentAndPreviousDocuments(context.Background(), []v1Models.ClaGroupDocument{v1, v2, v11})
+	assert.NoError(t, err)
+	assert.Equal(t, v2, current)
+	if assert.NotNil(t, previous) {
+		assert.Equal(t, v11, *previous)
+	}
+
+	current, previous, err = currentAndPreviousDocuments(context.Background(), []v1Models.ClaGroupDocument{v1})
+	assert.NoError(t, err)
+	assert.Equal(t, v1, current)
+	assert.Nil(t, previous)
+
+	_, _, err = currentAndPreviousDocuments(context.Background(), nil)
+	assert.Equal(t, ErrNoDocument, err)
+}
+
+func TestDocumentChanges(t *testing.T) {
+	previous := v1Models.ClaGroupDocument{DocumentName: "Apache Style", DocumentFileID: "t1", DocumentMajorVersion: "1", DocumentMinorVersion: "0", DocumentTemplateVersion: 1}
+	current := previous
+	current.DocumentMajorVersion = "2"
+	current.DocumentTemplateVersion = 2
+
+	changes := documentChanges(previous, current)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, "document_major_version", changes[0].Field)
+		assert.Equal(t, "1", changes[0].PreviousValue)
+		assert.Equal(t, "2", changes[0].CurrentValue)
+		assert.Equal(t, "document_template_version", changes[1].Field)
+	}
+	assert.True(t, sameStoredTemplate(previous, current))
+
+	current.DocumentFileID = "t2"
+	assert.False(t, sameStoredTemplate(previous, current))
+}
+
+func TestDiffLines(t *testing.T) {
+	previous := "<h1>CLA</h1>\n<p>You grant a license.</p>\n\n<p>Signature:</p>"
+	current := "<h1>CLA</h1>\n  <p>You grant a perpetual license.</p>\n<p>Signature:</p>\n<p>Date:</p>"
+
+	assert.Equal(t, []string{
+		"- <p>You grant a license.</p>",
+		"+ <p>You grant a perpetual license.</p>",
+		"+ <p>Date:</p>",
+	}, diffLines(previous, current))
+	assert.Empty(t, diffLines(current, current))
+}
+
+func TestOutdatedSignaturesGroupedByCompany(t *testing.T) {
+	sigs := []*v1Models.Signature{
+		{SignatureID: "s1", SignatureReferenceID: "c2", SignatureReferenceName: "Beta", SignatureMajorVersion: "1"},
+		{SignatureID: "s2", SignatureReferenceID: "c1", CompanyName: "Acme", SignatureMajorVersion: "1"},
+		{SignatureID: "s3", SignatureReferenceID: "c2", SignatureReferenceName: "Beta", SignatureMajorVersion: ""},
+		{SignatureID: "s4", SignatureReferenceID: "c3", SignatureReferenceName: "Gamma", SignatureMajorVersion: "2"},
+	}
+
+	var outdated []*v1Models.Signature
+	for _, sig := range sigs {
+		if isOutdated(sig, 2) {
+			outdated = append(outdated, sig)
+		}
+	}
+
+	companies := groupByCompany(outdated)
+	if assert.Len(t, companies, 2) {
+		assert.Equal(t, "Acme", companies[0].CompanyName)
+		assert.Len(t, companies[0].Signatures, 1)
+		assert.Equal(t, "Beta", companies[1].CompanyName)
+		assert.Len(t, companies[1].Signatures, 2)
+	}
+}
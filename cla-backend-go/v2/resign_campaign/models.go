@@ -0,0 +1,34 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaign
+
+// campaign status values
+const (
+	StatusInProgress = "in_progress"
+	StatusCompleted  = "completed"
+)
+
+// DBResignCampaign is the data model of a re-sign campaign
+type DBResignCampaign struct {
+	CampaignID         string              `dynamodbav:"campaign_id"`
+	ClaGroupID         string              `dynamodbav:"cla_group_id"`
+	ClaType            string              `dynamodbav:"cla_type"`
+	TargetMajorVersion int64               `dynamodbav:"target_major_version"`
+	Status             string              `dynamodbav:"status"`
+	CreatedBy          string              `dynamodbav:"created_by"`
+	DateCreated        string              `dynamodbav:"date_created"`
+	DateModified       string              `dynamodbav:"date_modified"`
+	Recipients         []DBResignRecipient `dynamodbav:"recipients"`
+}
+
+// DBResignRecipient is an outdated signature of a re-sign campaign and the emails which were notified for it
+type DBResignRecipient struct {
+	SignatureID            string   `dynamodbav:"signature_id"`
+	SignatureReferenceID   string   `dynamodbav:"signature_reference_id"`
+	SignatureReferenceName string   `dynamodbav:"signature_reference_name"`
+	Emails                 []string `dynamodbav:"emails"`
+	Notified               bool     `dynamodbav:"notified"`
+	Completed              bool     `dynamodbav:"completed"`
+	DateCompleted          string   `dynamodbav:"date_completed,omitempty"`
+}
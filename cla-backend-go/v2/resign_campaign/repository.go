@@ -0,0 +1,153 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/storage"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrCampaignNotFound = errors.New("re-sign campaign not found")
+)
+
+// Repository stores the re-sign campaigns
+type Repository interface {
+	SaveCampaign(ctx context.Context, campaign *DBResignCampaign) error
+	GetCampaign(ctx context.Context, campaignID string) (*DBResignCampaign, error)
+	GetCampaignsByCLAGroup(ctx context.Context, claGroupID string) ([]*DBResignCampaign, error)
+}
+
+// NewRepository creates a repository backed by the cla-<stage>-resign-campaigns table
+func NewRepository(dynamoDBClient storage.Driver, stage string) Repository {
+	return &repo{
+		stage:          stage,
+		dynamoDBClient: dynamoDBClient,
+		tableName:      fmt.Sprintf("cla-%s-resign-campaigns", stage),
+	}
+}
+
+type repo struct {
+	stage          string
+	dynamoDBClient storage.Driver
+	tableName      string
+}
+
+// SaveCampaign creates or replaces the re-sign campaign
+func (r *repo) SaveCampaign(ctx context.Context, campaign *DBResignCampaign) error {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.SaveCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaign.CampaignID,
+		"claGroupID":     campaign.ClaGroupID,
+	}
+
+	av, err := dynamodbattribute.MarshalMap(campaign)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem marshalling the re-sign campaign")
+		return err
+	}
+
+	_, err = r.dynamoDBClient.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(r.tableName),
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to save the re-sign campaign")
+		return err
+	}
+
+	return nil
+}
+
+// GetCampaign returns the re-sign campaign
+func (r *repo) GetCampaign(ctx context.Context, campaignID string) (*DBResignCampaign, error) {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.GetCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"campaignID":     campaignID,
+	}
+
+	result, err := r.dynamoDBClient.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"campaign_id": {S: aws.String(campaignID)},
+		},
+	})
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to load the re-sign campaign")
+		return nil, err
+	}
+	if len(result.Item) == 0 {
+		return nil, ErrCampaignNotFound
+	}
+
+	var campaign DBResignCampaign
+	err = dynamodbattribute.UnmarshalMap(result.Item, &campaign)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem unmarshalling the re-sign campaign")
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// GetCampaignsByCLAGroup returns the re-sign campaigns of the CLA Group
+func (r *repo) GetCampaignsByCLAGroup(ctx context.Context, claGroupID string) ([]*DBResignCampaign, error) {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.GetCampaignsByCLAGroup",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	condition := expression.Key("cla_group_id").Equal(expression.Value(claGroupID))
+	expr, err := expression.NewBuilder().WithKeyCondition(condition).Build()
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem building the query expression")
+		return nil, err
+	}
+
+	queryInput := &dynamodb.QueryInput{
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		KeyConditionExpression:    expr.KeyCondition(),
+		TableName:                 aws.String(r.tableName),
+		IndexName:                 aws.String("cla-group-id-index"),
+	}
+
+	var campaigns []*DBResignCampaign
+	for {
+		results, queryErr := r.dynamoDBClient.QueryWithContext(ctx, queryInput)
+		if queryErr != nil {
+			log.WithFields(f).WithError(queryErr).Warn("unable to query the re-sign campaigns")
+			return nil, queryErr
+		}
+
+		var items []*DBResignCampaign
+		err = dynamodbattribute.UnmarshalListOfMaps(results.Items, &items)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem unmarshalling the re-sign campaigns")
+			return nil, err
+		}
+		campaigns = append(campaigns, items...)
+
+		if len(results.LastEvaluatedKey) == 0 {
+			break
+		}
+		queryInput.ExclusiveStartKey = results.LastEvaluatedKey
+	}
+
+	return campaigns, nil
+}
@@ -0,0 +1,525 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package resign_campaign
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/communitybridge/easycla/cla-backend-go/events"
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/signatures"
+	v1Template "github.com/communitybridge/easycla/cla-backend-go/template"
+	"github.com/communitybridge/easycla/cla-backend-go/users"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+	"github.com/gofrs/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// errors
+var (
+	ErrNoDocument           = errors.New("the CLA Group has no document for the CLA type")
+	ErrNoOutdatedSignatures = errors.New("no signatures of an outdated major version")
+	ErrCampaignInProgress   = errors.New("a re-sign campaign is already in progress for the CLA type")
+)
+
+// signaturesPageSize is the page size used to load the signatures of the CLA Group
+const signaturesPageSize = 100
+
+// Service reports the impact of an agreement change and runs the re-sign campaigns
+type Service interface {
+	GetTemplateImpact(ctx context.Context, claGroup *v1Models.ClaGroup, claType string) (*models.TemplateImpact, error)
+	StartCampaign(ctx context.Context, claGroup *v1Models.ClaGroup, claType string, lfUsername string) (*models.ResignCampaign, error)
+	GetCampaigns(ctx context.Context, claGroup *v1Models.ClaGroup, lfUsername string) ([]*models.ResignCampaign, error)
+	GetCampaign(ctx context.Context, claGroup *v1Models.ClaGroup, campaignID string, lfUsername string) (*models.ResignCampaign, error)
+}
+
+type service struct {
+	repo             Repository
+	templateService  v1Template.Service
+	signatureService v1Signatures.SignatureService
+	usersService     users.Service
+	eventsService    events.Service
+}
+
+// NewService creates a new re-sign campaign service
+func NewService(repo Repository, templateService v1Template.Service, signatureService v1Signatures.SignatureService, usersService users.Service, eventsService events.Service) Service {
+	return &service{
+		repo:             repo,
+		templateService:  templateService,
+		signatureService: signatureService,
+		usersService:     usersService,
+		eventsService:    eventsService,
+	}
+}
+
+// GetTemplateImpact compares the current document of the CLA type with the document it replaced and lists the
+// signatures which were signed on an older major version - only a major version change requires a new signature
+func (s *service) GetTemplateImpact(ctx context.Context, claGroup *v1Models.ClaGroup, claType string) (*models.TemplateImpact, error) {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.GetTemplateImpact",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroup.ProjectID,
+		"claType":        claType,
+	}
+
+	current, previous, err := currentAndPreviousDocuments(ctx, claGroupDocuments(claGroup, claType))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the current document")
+		return nil, err
+	}
+
+	impact := &models.TemplateImpact{
+		ClaGroupID:     claGroup.ProjectID,
+		ClaType:        claType,
+		CurrentVersion: documentVersion(current),
+	}
+	if previous != nil {
+		impact.PreviousVersion = documentVersion(*previous)
+		impact.Changes = documentChanges(*previous, current)
+		if sameStoredTemplate(*previous, current) {
+			impact.TemplateDiff, err = s.templateDiff(ctx, claType, *previous, current)
+			if err != nil {
+				// the stored template versions are kept, the report is still useful without the diff
+				log.WithFields(f).WithError(err).Warn("unable to compare the template versions")
+			}
+		}
+	}
+
+	outdated, err := s.getOutdatedSignatures(ctx, claGroup.ProjectID, claType, current)
+	if err != nil {
+		return nil, err
+	}
+
+	impact.AffectedSignatureCount = int64(len(outdated))
+	impact.RequiresResign = len(outdated) > 0
+	if claType == utils.ClaTypeCCLA {
+		impact.AffectedCompanies = groupByCompany(outdated)
+	} else {
+		for _, sig := range outdated {
+			impact.AffectedIndividuals = append(impact.AffectedIndividuals, toAffectedSignature(sig))
+		}
+	}
+
+	log.WithFields(f).Debugf("%d signatures were signed on a major version older than %s", len(outdated), impact.CurrentVersion)
+	return impact, nil
+}
+
+// StartCampaign emails the signers of an outdated major version - the CLA Managers for the corporate signatures -
+// asking them to sign the current version of the document
+func (s *service) StartCampaign(ctx context.Context, claGroup *v1Models.ClaGroup, claType string, lfUsername string) (*models.ResignCampaign, error) {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.StartCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroup.ProjectID,
+		"claType":        claType,
+		"lfUsername":     lfUsername,
+	}
+
+	campaigns, err := s.repo.GetCampaignsByCLAGroup(ctx, claGroup.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, campaign := range campaigns {
+		if campaign.ClaType != claType || campaign.Status != StatusInProgress {
+			continue
+		}
+		// the recipients may have signed since the campaign was last loaded
+		if refreshErr := s.refreshCampaign(ctx, claGroup, campaign, lfUsername); refreshErr != nil {
+			return nil, refreshErr
+		}
+		if campaign.Status == StatusInProgress {
+			log.WithFields(f).Debugf("re-sign campaign %s is still in progress", campaign.CampaignID)
+			return nil, ErrCampaignInProgress
+		}
+	}
+
+	current, _, err := currentAndPreviousDocuments(ctx, claGroupDocuments(claGroup, claType))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("problem loading the current document")
+		return nil, err
+	}
+	targetMajorVersion, err := strconv.ParseInt(current.DocumentMajorVersion, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	outdated, err := s.getOutdatedSignatures(ctx, claGroup.ProjectID, claType, current)
+	if err != nil {
+		return nil, err
+	}
+	if len(outdated) == 0 {
+		return nil, ErrNoOutdatedSignatures
+	}
+
+	campaignID, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	_, now := utils.CurrentTime()
+	campaign := &DBResignCampaign{
+		CampaignID:         campaignID.String(),
+		ClaGroupID:         claGroup.ProjectID,
+		ClaType:            claType,
+		TargetMajorVersion: targetMajorVersion,
+		Status:             StatusInProgress,
+		CreatedBy:          lfUsername,
+		DateCreated:        now,
+		DateModified:       now,
+	}
+
+	for _, sig := range outdated {
+		recipient := DBResignRecipient{
+			SignatureID:            sig.SignatureID.String(),
+			SignatureReferenceID:   sig.SignatureReferenceID.String(),
+			SignatureReferenceName: sig.SignatureReferenceName,
+			Emails:                 s.recipientEmails(ctx, claType, sig),
+		}
+		if len(recipient.Emails) == 0 {
+			log.WithFields(f).Warnf("no email found for signature %s of %s, unable to notify", recipient.SignatureID, recipient.SignatureReferenceName)
+		} else {
+			subject, body := resignEmailContent(claGroup, claType, recipient.SignatureReferenceName, sig.SignatureMajorVersion, current)
+			if sendErr := utils.SendEmail(subject, body, recipient.Emails); sendErr != nil {
+				log.WithFields(f).WithError(sendErr).Warnf("unable to send the re-sign email for signature %s", recipient.SignatureID)
+			} else {
+				recipient.Notified = true
+			}
+		}
+		campaign.Recipients = append(campaign.Recipients, recipient)
+	}
+
+	err = s.repo.SaveCampaign(ctx, campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	s.eventsService.LogEvent(&events.LogEventArgs{
+		EventType:     events.ResignCampaignStarted,
+		ClaGroupModel: claGroup,
+		LfUsername:    lfUsername,
+		EventData: &events.ResignCampaignStartedEventData{
+			CampaignID:         campaign.CampaignID,
+			ClaType:            claType,
+			TargetMajorVersion: targetMajorVersion,
+			RecipientCount:     int64(len(campaign.Recipients)),
+		},
+	})
+
+	log.WithFields(f).Debugf("started re-sign campaign %s with %d recipients", campaign.CampaignID, len(campaign.Recipients))
+	return toCampaignModel(campaign), nil
+}
+
+// GetCampaigns returns the re-sign campaigns of the CLA Group, most recent first
+func (s *service) GetCampaigns(ctx context.Context, claGroup *v1Models.ClaGroup, lfUsername string) ([]*models.ResignCampaign, error) {
+	campaigns, err := s.repo.GetCampaignsByCLAGroup(ctx, claGroup.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(campaigns, func(i, j int) bool {
+		return campaigns[i].DateCreated > campaigns[j].DateCreated
+	})
+
+	result := make([]*models.ResignCampaign, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Status == StatusInProgress {
+			if refreshErr := s.refreshCampaign(ctx, claGroup, campaign, lfUsername); refreshErr != nil {
+				return nil, refreshErr
+			}
+		}
+		result = append(result, toCampaignModel(campaign))
+	}
+	return result, nil
+}
+
+// GetCampaign returns the re-sign campaign with the completion of the recipients refreshed
+func (s *service) GetCampaign(ctx context.Context, claGroup *v1Models.ClaGroup, campaignID string, lfUsername string) (*models.ResignCampaign, error) {
+	campaign, err := s.repo.GetCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.ClaGroupID != claGroup.ProjectID {
+		return nil, ErrCampaignNotFound
+	}
+
+	if campaign.Status == StatusInProgress {
+		if refreshErr := s.refreshCampaign(ctx, claGroup, campaign, lfUsername); refreshErr != nil {
+			return nil, refreshErr
+		}
+	}
+	return toCampaignModel(campaign), nil
+}
+
+// refreshCampaign marks the recipients which signed the target major version as completed - the campaign is
+// completed once all the recipients signed
+func (s *service) refreshCampaign(ctx context.Context, claGroup *v1Models.ClaGroup, campaign *DBResignCampaign, lfUsername string) error {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.refreshCampaign",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     campaign.ClaGroupID,
+		"campaignID":     campaign.CampaignID,
+	}
+
+	latestMajorVersions := map[string]int64{}
+	err := s.forEachSignature(ctx, campaign.ClaGroupID, campaign.ClaType, func(sig *v1Models.Signature) {
+		major, parseErr := strconv.ParseInt(sig.SignatureMajorVersion, 10, 64)
+		if parseErr != nil {
+			return
+		}
+		referenceID := sig.SignatureReferenceID.String()
+		if major > latestMajorVersions[referenceID] {
+			latestMajorVersions[referenceID] = major
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	_, now := utils.CurrentTime()
+	changed := false
+	completed := true
+	for i := range campaign.Recipients {
+		recipient := &campaign.Recipients[i]
+		if !recipient.Completed && latestMajorVersions[recipient.SignatureReferenceID] >= campaign.TargetMajorVersion {
+			recipient.Completed = true
+			recipient.DateCompleted = now
+			changed = true
+		}
+		completed = completed && recipient.Completed
+	}
+	if completed {
+		campaign.Status = StatusCompleted
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	campaign.DateModified = now
+	err = s.repo.SaveCampaign(ctx, campaign)
+	if err != nil {
+		return err
+	}
+
+	if completed {
+		log.WithFields(f).Debug("all the recipients signed the target major version, re-sign campaign completed")
+		s.eventsService.LogEvent(&events.LogEventArgs{
+			EventType:     events.ResignCampaignCompleted,
+			ClaGroupModel: claGroup,
+			LfUsername:    lfUsername,
+			EventData: &events.ResignCampaignCompletedEventData{
+				CampaignID:         campaign.CampaignID,
+				ClaType:            campaign.ClaType,
+				TargetMajorVersion: campaign.TargetMajorVersion,
+			},
+		})
+	}
+	return nil
+}
+
+// getOutdatedSignatures returns the signed and approved signatures of the CLA type signed on a major version older
+// than the one of the current document
+func (s *service) getOutdatedSignatures(ctx context.Context, claGroupID, claType string, current v1Models.ClaGroupDocument) ([]*v1Models.Signature, error) {
+	currentMajorVersion, err := strconv.ParseInt(current.DocumentMajorVersion, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var outdated []*v1Models.Signature
+	// a signer who already signed the current version is not affected by an older signature
+	signedCurrent := map[string]bool{}
+	err = s.forEachSignature(ctx, claGroupID, claType, func(sig *v1Models.Signature) {
+		if isOutdated(sig, currentMajorVersion) {
+			outdated = append(outdated, sig)
+		} else {
+			signedCurrent[sig.SignatureReferenceID.String()] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*v1Models.Signature
+	for _, sig := range outdated {
+		if !signedCurrent[sig.SignatureReferenceID.String()] {
+			result = append(result, sig)
+		}
+	}
+	return result, nil
+}
+
+// forEachSignature calls fn for each signed and approved signature of the CLA type
+func (s *service) forEachSignature(ctx context.Context, claGroupID, claType string, fn func(sig *v1Models.Signature)) error {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.forEachSignature",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+		"claType":        claType,
+	}
+
+	var nextKey *string
+	for {
+		page, err := s.signatureService.GetProjectSignatures(ctx, signatures.GetProjectSignaturesParams{
+			ClaType:   aws.String(claType),
+			NextKey:   nextKey,
+			PageSize:  aws.Int64(signaturesPageSize),
+			ProjectID: claGroupID,
+		})
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading the signatures")
+			return err
+		}
+
+		for _, sig := range page.Signatures {
+			fn(sig)
+		}
+
+		if page.LastKeyScanned == "" {
+			return nil
+		}
+		nextKey = aws.String(page.LastKeyScanned)
+	}
+}
+
+// templateDiff compares the HTML bodies of the stored template versions the documents were generated from
+func (s *service) templateDiff(ctx context.Context, claType string, previous, current v1Models.ClaGroupDocument) ([]string, error) {
+	previousTemplate, err := s.templateService.GetTemplateVersion(ctx, previous.DocumentFileID, previous.DocumentTemplateVersion)
+	if err != nil {
+		return nil, err
+	}
+	currentTemplate, err := s.templateService.GetTemplateVersion(ctx, current.DocumentFileID, current.DocumentTemplateVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if claType == utils.ClaTypeCCLA {
+		return diffLines(previousTemplate.CclaHTMLBody, currentTemplate.CclaHTMLBody), nil
+	}
+	return diffLines(previousTemplate.IclaHTMLBody, currentTemplate.IclaHTMLBody), nil
+}
+
+// recipientEmails returns the email of the individual signer or the emails of the CLA Managers of the company
+func (s *service) recipientEmails(ctx context.Context, claType string, sig *v1Models.Signature) []string {
+	f := logrus.Fields{
+		"functionName":   "resign_campaign.recipientEmails",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"signatureID":    sig.SignatureID,
+	}
+
+	var candidates []v1Models.User
+	if claType == utils.ClaTypeCCLA {
+		candidates = sig.SignatureACL
+	} else {
+		user, err := s.usersService.GetUser(sig.SignatureReferenceID.String())
+		if err != nil || user == nil {
+			log.WithFields(f).WithError(err).Warnf("unable to load the user %s", sig.SignatureReferenceID)
+			return nil
+		}
+		candidates = []v1Models.User{*user}
+	}
+
+	var emails []string
+	seen := map[string]bool{}
+	for _, user := range candidates {
+		email := getBestEmail(user)
+		if email != "" && !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+func getBestEmail(user v1Models.User) string {
+	if user.LfEmail != "" {
+		return user.LfEmail
+	}
+
+	for _, email := range user.Emails {
+		if email != "" {
+			return email
+		}
+	}
+
+	return ""
+}
+
+func claGroupDocuments(claGroup *v1Models.ClaGroup, claType string) []v1Models.ClaGroupDocument {
+	if claType == utils.ClaTypeCCLA {
+		return claGroup.ProjectCorporateDocuments
+	}
+	return claGroup.ProjectIndividualDocuments
+}
+
+// resignEmailContent prepares the email asking to sign the current version of the document
+func resignEmailContent(claGroup *v1Models.ClaGroup, claType, name, signedMajorVersion string, current v1Models.ClaGroupDocument) (string, string) {
+	isV2 := claGroup.Version == utils.V2
+	if claType == utils.ClaTypeCCLA {
+		subject := fmt.Sprintf("EasyCLA: The %s Corporate CLA was updated", claGroup.ProjectName)
+		body := fmt.Sprintf(`
+<p>Hello CLA Manager,</p>
+<p>This is a notification email from EasyCLA regarding the CLA Group %s.</p>
+<p>The Corporate CLA of %s was updated to version %s. %s signed version %s, which no longer covers the contributions
+   of your employees to the CLA Group.</p>
+<p>Please sign the new version of the Corporate CLA in the EasyCLA Corporate Console: %s</p>
+%s
+%s`,
+			claGroup.ProjectName, claGroup.ProjectName, documentVersion(current), name, signedMajorVersion,
+			utils.GetCorporateURL(isV2), utils.GetEmailHelpContent(isV2), utils.GetEmailSignOffContent())
+		return subject, body
+	}
+
+	subject := fmt.Sprintf("EasyCLA: The %s Individual CLA was updated", claGroup.ProjectName)
+	body := fmt.Sprintf(`
+<p>Hello %s,</p>
+<p>This is a notification email from EasyCLA regarding the CLA Group %s.</p>
+<p>The Individual CLA of %s was updated to version %s. You signed version %s, which no longer covers your
+   contributions to the CLA Group.</p>
+<p>You will be asked to sign the new version of the Individual CLA with your next contribution.</p>
+%s
+%s`,
+		name, claGroup.ProjectName, claGroup.ProjectName, documentVersion(current), signedMajorVersion,
+		utils.GetEmailHelpContent(isV2), utils.GetEmailSignOffContent())
+	return subject, body
+}
+
+func toCampaignModel(campaign *DBResignCampaign) *models.ResignCampaign {
+	result := &models.ResignCampaign{
+		CampaignID:         campaign.CampaignID,
+		ClaGroupID:         campaign.ClaGroupID,
+		ClaType:            campaign.ClaType,
+		TargetMajorVersion: campaign.TargetMajorVersion,
+		Status:             campaign.Status,
+		CreatedBy:          campaign.CreatedBy,
+		DateCreated:        campaign.DateCreated,
+		DateModified:       campaign.DateModified,
+		RecipientCount:     int64(len(campaign.Recipients)),
+		Recipients:         make([]*models.ResignCampaignRecipient, 0, len(campaign.Recipients)),
+	}
+	for _, recipient := range campaign.Recipients {
+		if recipient.Notified {
+			result.NotifiedCount++
+		}
+		if recipient.Completed {
+			result.CompletedCount++
+		}
+		result.Recipients = append(result.Recipients, &models.ResignCampaignRecipient{
+			SignatureID:            recipient.SignatureID,
+			SignatureReferenceID:   recipient.SignatureReferenceID,
+			SignatureReferenceName: recipient.SignatureReferenceName,
+			Emails:                 recipient.Emails,
+			Notified:               recipient.Notified,
+			Completed:              recipient.Completed,
+			DateCompleted:          recipient.DateCompleted,
+		})
+	}
+	return result
+}
@@ -82,6 +82,7 @@ type requestCorporateSignatureInput struct {
 	AuthorityName  string `json:"authority_name,omitempty"`
 	AuthorityEmail string `json:"authority_email,omitempty"`
 	ReturnURL      string `json:"return_url,omitempty"`
+	Locale         string `json:"locale,omitempty"`
 }
 
 type requestCorporateSignatureOutput struct {
@@ -216,6 +217,7 @@ func (s *service) RequestCorporateSignature(ctx context.Context, lfUsername stri
 		AuthorityName:  input.AuthorityName,
 		AuthorityEmail: input.AuthorityEmail.String(),
 		ReturnURL:      input.ReturnURL.String(),
+		Locale:         input.Locale,
 	})
 	if err != nil {
 		if input.AuthorityEmail.String() != "" {
@@ -248,6 +250,7 @@ func requestCorporateSignature(authToken string, apiURL string, input *requestCo
 		"AuthorityEmail": input.AuthorityEmail,
 		"ReturnURL":      input.ReturnURL,
 		"SendAsEmail":    input.SendAsEmail,
+		"Locale":         input.Locale,
 	}
 	requestBody, err := json.Marshal(input)
 	if err != nil {
@@ -0,0 +1,329 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/LF-Engineering/lfx-kit/auth"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/jinzhu/copier"
+	"github.com/sirupsen/logrus"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// approvalListCSVHeader is the header of the approval list CSV document
+var approvalListCSVHeader = []string{"Type", "Value"}
+
+// approvalListCSVTypes is the order the approval lists are written in
+var approvalListCSVTypes = []string{
+	signatures.ApprovalListTypeEmail,
+	signatures.ApprovalListTypeDomain,
+	signatures.ApprovalListTypeGithubUsername,
+	signatures.ApprovalListTypeGitlabUsername,
+	signatures.ApprovalListTypeGithubOrg,
+}
+
+// approvalListCSVFormulaPrefixes are the leading characters spreadsheet applications evaluate a cell as a formula for
+const approvalListCSVFormulaPrefixes = "=+-@"
+
+// errors
+var (
+	ErrApprovalListCSVHeader      = errors.New("bad request. the approval list CSV document must start with a Type,Value header")
+	ErrApprovalListCSVInvalidRows = errors.New("bad request. the approval list CSV document has invalid rows")
+)
+
+// GetApprovalListCsv returns the approval list of the company CCLA signature as a CSV document
+func (s service) GetApprovalListCsv(ctx context.Context, claGroupID, companyID string) ([]byte, error) {
+	sig, err := s.getApprovalListSignature(ctx, claGroupID, companyID)
+	if err != nil {
+		return nil, err
+	}
+	return approvalListCSV(sig)
+}
+
+// ImportApprovalListCsv compares the approval list CSV document with the approval list of the company CCLA signature
+// and, unless a dry run is requested, applies the changes through the approval list update of the signature service
+func (s service) ImportApprovalListCsv(ctx context.Context, authUser *auth.User, claGroupModel *v1Models.ClaGroup, companyModel *v1Models.Company, input *models.ApprovalListCsvImport) (*models.ApprovalListCsvImportResult, error) {
+	f := logrus.Fields{
+		"functionName":   "ImportApprovalListCsv",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupModel.ProjectID,
+		"companyID":      companyModel.CompanyID,
+		"dryRun":         input.DryRun,
+		"removeMissing":  input.RemoveMissing,
+	}
+
+	sig, err := s.getApprovalListSignature(ctx, claGroupModel.ProjectID, companyModel.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure current user is in the Signature ACL - checked here as well so dry runs are protected
+	if !utils.CurrentUserInACL(authUser, sig.SignatureACL) {
+		msg := fmt.Sprintf("CLA Manager %s / %s is not authorized to import the approval list for company ID: %s, CLA Group ID: %s",
+			authUser.UserName, authUser.Email, companyModel.CompanyID, claGroupModel.ProjectID)
+		log.WithFields(f).Warn(msg)
+		return nil, signatures.NewForbiddenError(msg)
+	}
+
+	entries, invalidRows, err := parseApprovalListCSV(aws.StringValue(input.Csv))
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to parse the approval list CSV document")
+		return nil, err
+	}
+
+	changes := approvalListCSVChanges(sig, entries, input.RemoveMissing)
+	result := &models.ApprovalListCsvImportResult{
+		DryRun:      true,
+		Changes:     &models.ApprovalList{},
+		InvalidRows: invalidRows,
+	}
+	if err = copier.Copy(result.Changes, changes); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to convert v1 to v2 approval list")
+		return nil, err
+	}
+
+	log.WithFields(f).Debugf("approval list CSV import - adds: %d, removes: %d, invalid rows: %d",
+		approvalListChangeCount(changes.AddEmailApprovalList, changes.AddDomainApprovalList, changes.AddGithubUsernameApprovalList, changes.AddGitlabUsernameApprovalList, changes.AddGithubOrgApprovalList),
+		approvalListChangeCount(changes.RemoveEmailApprovalList, changes.RemoveDomainApprovalList, changes.RemoveGithubUsernameApprovalList, changes.RemoveGitlabUsernameApprovalList, changes.RemoveGithubOrgApprovalList),
+		len(invalidRows))
+
+	if input.DryRun {
+		return result, nil
+	}
+	if len(invalidRows) > 0 {
+		return result, ErrApprovalListCSVInvalidRows
+	}
+	if !approvalListHasChanges(changes) {
+		log.WithFields(f).Debug("approval list CSV document matches the approval list - nothing to update")
+		return result, nil
+	}
+
+	// Same path as the approval list update so the events and the notification emails are the same
+	updatedSig, err := s.v1SignatureService.UpdateApprovalList(ctx, authUser, claGroupModel, companyModel, claGroupModel.ProjectID, changes)
+	if err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to update the approval list")
+		return nil, err
+	}
+
+	result.DryRun = false
+	result.Signature = &models.Signature{}
+	if err = copier.Copy(result.Signature, updatedSig); err != nil {
+		log.WithFields(f).WithError(err).Warn("unable to convert v1 to v2 signature")
+		return nil, err
+	}
+	return result, nil
+}
+
+// getApprovalListSignature loads the signed and approved CCLA signature of the company
+func (s service) getApprovalListSignature(ctx context.Context, claGroupID, companyID string) (*v1Models.Signature, error) {
+	signed, approved := true, true
+	sig, err := s.v1SignatureService.GetProjectCompanySignature(ctx, companyID, claGroupID, &signed, &approved, nil, aws.Int64(HugePageSize))
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, ErrSignatureNotFound
+	}
+	return sig, nil
+}
+
+// approvalListsOf returns the entries of each approval list of the signature
+func approvalListsOf(sig *v1Models.Signature) map[string][]string {
+	return map[string][]string{
+		signatures.ApprovalListTypeEmail:          sig.EmailApprovalList,
+		signatures.ApprovalListTypeDomain:         sig.DomainApprovalList,
+		signatures.ApprovalListTypeGithubUsername: sig.GithubUsernameApprovalList,
+		signatures.ApprovalListTypeGitlabUsername: sig.GitlabUsernameApprovalList,
+		signatures.ApprovalListTypeGithubOrg:      sig.GithubOrgApprovalList,
+	}
+}
+
+// approvalListCSV writes the approval lists of the signature as a CSV document
+func approvalListCSV(sig *v1Models.Signature) ([]byte, error) {
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.Write(approvalListCSVHeader); err != nil {
+		return nil, err
+	}
+
+	approvalLists := approvalListsOf(sig)
+	for _, listType := range approvalListCSVTypes {
+		for _, value := range approvalLists[listType] {
+			if err := w.Write([]string{listType, escapeApprovalListCSVValue(strings.TrimSpace(value))}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// parseApprovalListCSV reads the approval list CSV document, returns the valid entries by approval list type and the
+// rows which were rejected
+func parseApprovalListCSV(content string) (map[string][]string, []*models.ApprovalListCsvInvalidRow, error) {
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\ufeff")))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil || len(header) != len(approvalListCSVHeader) ||
+		!strings.EqualFold(strings.TrimSpace(header[0]), approvalListCSVHeader[0]) ||
+		!strings.EqualFold(strings.TrimSpace(header[1]), approvalListCSVHeader[1]) {
+		return nil, nil, ErrApprovalListCSVHeader
+	}
+
+	entries := map[string][]string{}
+	var invalidRows []*models.ApprovalListCsvInvalidRow
+	line := int64(1)
+	for {
+		record, readErr := r.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, nil, fmt.Errorf("bad request. unable to read the approval list CSV document: %v", readErr)
+		}
+		line++
+
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) != len(approvalListCSVHeader) {
+			invalidRows = append(invalidRows, &models.ApprovalListCsvInvalidRow{
+				Line:   line,
+				Value:  strings.Join(record, ","),
+				Reason: "expecting a type and a value",
+			})
+			continue
+		}
+
+		listType, value := approvalListCSVType(record[0]), unescapeApprovalListCSVValue(strings.TrimSpace(record[1]))
+		if msg, valid := validateApprovalListEntry(listType, value); !valid {
+			invalidRows = append(invalidRows, &models.ApprovalListCsvInvalidRow{
+				Line:   line,
+				Type:   strings.TrimSpace(record[0]),
+				Value:  value,
+				Reason: msg,
+			})
+			continue
+		}
+		if !approvalListContains(entries[listType], value) {
+			entries[listType] = append(entries[listType], value)
+		}
+	}
+
+	return entries, invalidRows, nil
+}
+
+// escapeApprovalListCSVValue prefixes the values a spreadsheet application would evaluate as a formula with a quote
+func escapeApprovalListCSVValue(value string) string {
+	if value != "" && strings.ContainsRune(approvalListCSVFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeApprovalListCSVValue removes the quote escapeApprovalListCSVValue adds to the exported values
+func unescapeApprovalListCSVValue(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(approvalListCSVFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// approvalListContains returns true if the approval list has the value, the approval list entries are matched
+// case insensitive
+func approvalListContains(list []string, value string) bool {
+	for _, entry := range list {
+		if strings.EqualFold(entry, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// approvalListCSVType returns the approval list type of the CSV type column, matched case insensitive
+func approvalListCSVType(value string) string {
+	value = strings.TrimSpace(value)
+	for _, listType := range approvalListCSVTypes {
+		if strings.EqualFold(value, listType) {
+			return listType
+		}
+	}
+	return value
+}
+
+// approvalListCSVChanges returns the approval list update adding the CSV entries missing from the signature and,
+// when requested, removing the signature entries missing from the CSV document
+func approvalListCSVChanges(sig *v1Models.Signature, entries map[string][]string, removeMissing bool) *v1Models.ApprovalList {
+	current := approvalListsOf(sig)
+	adds, removes := map[string][]string{}, map[string][]string{}
+	for _, listType := range approvalListCSVTypes {
+		var existing []string
+		for _, value := range current[listType] {
+			existing = append(existing, strings.TrimSpace(value))
+		}
+
+		for _, value := range entries[listType] {
+			if !approvalListContains(existing, value) {
+				adds[listType] = append(adds[listType], value)
+			}
+		}
+		if !removeMissing {
+			continue
+		}
+		for _, value := range existing {
+			if !approvalListContains(entries[listType], value) && !approvalListContains(removes[listType], value) {
+				removes[listType] = append(removes[listType], value)
+			}
+		}
+	}
+
+	return &v1Models.ApprovalList{
+		AddEmailApprovalList:             adds[signatures.ApprovalListTypeEmail],
+		AddDomainApprovalList:            adds[signatures.ApprovalListTypeDomain],
+		AddGithubUsernameApprovalList:    adds[signatures.ApprovalListTypeGithubUsername],
+		AddGitlabUsernameApprovalList:    adds[signatures.ApprovalListTypeGitlabUsername],
+		AddGithubOrgApprovalList:         adds[signatures.ApprovalListTypeGithubOrg],
+		RemoveEmailApprovalList:          removes[signatures.ApprovalListTypeEmail],
+		RemoveDomainApprovalList:         removes[signatures.ApprovalListTypeDomain],
+		RemoveGithubUsernameApprovalList: removes[signatures.ApprovalListTypeGithubUsername],
+		RemoveGitlabUsernameApprovalList: removes[signatures.ApprovalListTypeGitlabUsername],
+		RemoveGithubOrgApprovalList:      removes[signatures.ApprovalListTypeGithubOrg],
+	}
+}
+
+// approvalListHasChanges returns true if the approval list update adds or removes entries
+func approvalListHasChanges(changes *v1Models.ApprovalList) bool {
+	return approvalListChangeCount(
+		changes.AddEmailApprovalList, changes.AddDomainApprovalList, changes.AddGithubUsernameApprovalList, changes.AddGitlabUsernameApprovalList, changes.AddGithubOrgApprovalList,
+		changes.RemoveEmailApprovalList, changes.RemoveDomainApprovalList, changes.RemoveGithubUsernameApprovalList, changes.RemoveGitlabUsernameApprovalList, changes.RemoveGithubOrgApprovalList,
+	) > 0
+}
+
+// approvalListChangeCount returns the total number of entries of the lists
+func approvalListChangeCount(lists ...[]string) int {
+	var count int
+	for _, list := range lists {
+		count += len(list)
+	}
+	return count
+}
@@ -0,0 +1,133 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"testing"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalListCSVRoundTrip(t *testing.T) {
+	sig := &v1Models.Signature{
+		EmailApprovalList:          []string{"user@example.com"},
+		DomainApprovalList:         []string{"*.example.com", `regex:example\.(com|de)`},
+		GithubUsernameApprovalList: []string{"octocat"},
+		GithubOrgApprovalList:      []string{"example-org"},
+	}
+
+	content, err := approvalListCSV(sig)
+	assert.Nil(t, err)
+	assert.Equal(t, "Type,Value\nemail,user@example.com\ndomain,*.example.com\ndomain,regex:example\\.(com|de)\ngithubUsername,octocat\ngithubOrg,example-org\n", string(content))
+
+	entries, invalidRows, err := parseApprovalListCSV(string(content))
+	assert.Nil(t, err)
+	assert.Empty(t, invalidRows)
+	assert.Equal(t, map[string][]string{
+		"email":          {"user@example.com"},
+		"domain":         {"*.example.com", `regex:example\.(com|de)`},
+		"githubUsername": {"octocat"},
+		"githubOrg":      {"example-org"},
+	}, entries)
+
+	changes := approvalListCSVChanges(sig, entries, true)
+	assert.False(t, approvalListHasChanges(changes))
+}
+
+func TestParseApprovalListCSV(t *testing.T) {
+	content := "\ufefftype , VALUE\n" +
+		"Email, new@example.com\n" +
+		"email,new@example.com\n" +
+		"email,not-an-email\n" +
+		"\n" +
+		"domain,evil_example.com\n" +
+		"githubUsername,octocat,extra\n" +
+		"bitbucketUsername,octocat\n" +
+		"GITHUBORG,example-org\n"
+
+	entries, invalidRows, err := parseApprovalListCSV(content)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string][]string{
+		"email":     {"new@example.com"},
+		"githubOrg": {"example-org"},
+	}, entries)
+
+	if assert.Len(t, invalidRows, 4) {
+		assert.Equal(t, int64(4), invalidRows[0].Line)
+		assert.Equal(t, "not-an-email", invalidRows[0].Value)
+		assert.Equal(t, "domain", invalidRows[1].Type)
+		assert.Equal(t, "expecting a type and a value", invalidRows[2].Reason)
+		assert.Equal(t, "bitbucketUsername", invalidRows[3].Type)
+	}
+
+	_, _, err = parseApprovalListCSV("email,user@example.com\n")
+	assert.Equal(t, ErrApprovalListCSVHeader, err)
+}
+
+func TestApprovalListCSVChanges(t *testing.T) {
+	sig := &v1Models.Signature{
+		EmailApprovalList:     []string{"kept@example.com", " stale@example.com"},
+		GithubOrgApprovalList: []string{"example-org"},
+	}
+	entries := map[string][]string{
+		"email":  {"kept@example.com", "new@example.com"},
+		"domain": {"*.example.com"},
+	}
+
+	merged := approvalListCSVChanges(sig, entries, false)
+	assert.Equal(t, []string{"new@example.com"}, merged.AddEmailApprovalList)
+	assert.Equal(t, []string{"*.example.com"}, merged.AddDomainApprovalList)
+	assert.Nil(t, merged.RemoveEmailApprovalList)
+	assert.Nil(t, merged.RemoveGithubOrgApprovalList)
+
+	replaced := approvalListCSVChanges(sig, entries, true)
+	assert.Equal(t, []string{"new@example.com"}, replaced.AddEmailApprovalList)
+	assert.Equal(t, []string{"stale@example.com"}, replaced.RemoveEmailApprovalList)
+	assert.Equal(t, []string{"example-org"}, replaced.RemoveGithubOrgApprovalList)
+	assert.True(t, approvalListHasChanges(replaced))
+}
+
+func TestApprovalListCSVFormulaEscape(t *testing.T) {
+	testCases := []struct {
+		value   string
+		escaped string
+	}{
+		{"=HYPERLINK(\"http://evil.example.com\")", "'=HYPERLINK(\"http://evil.example.com\")"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1:A2)", "'@SUM(A1:A2)"},
+		{"user@example.com", "user@example.com"},
+		{"'quoted", "'quoted"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			assert.Equal(t, tc.escaped, escapeApprovalListCSVValue(tc.value))
+			assert.Equal(t, tc.value, unescapeApprovalListCSVValue(tc.escaped))
+		})
+	}
+
+	content, err := approvalListCSV(&v1Models.Signature{GithubOrgApprovalList: []string{"=cmd"}})
+	assert.Nil(t, err)
+	assert.Equal(t, "Type,Value\ngithubOrg,'=cmd\n", string(content))
+}
+
+func TestApprovalListCSVChangesIgnoreCase(t *testing.T) {
+	sig := &v1Models.Signature{
+		EmailApprovalList:          []string{"User@Example.com"},
+		GithubUsernameApprovalList: []string{"OctoCat", "stale"},
+	}
+	entries, invalidRows, err := parseApprovalListCSV("Type,Value\nemail,user@example.com\nemail,USER@example.com\ngithubUsername,octocat\n")
+	assert.Nil(t, err)
+	assert.Empty(t, invalidRows)
+	assert.Equal(t, []string{"user@example.com"}, entries["email"])
+
+	changes := approvalListCSVChanges(sig, entries, true)
+	assert.Nil(t, changes.AddEmailApprovalList)
+	assert.Nil(t, changes.AddGithubUsernameApprovalList)
+	assert.Nil(t, changes.RemoveEmailApprovalList)
+	assert.Equal(t, []string{"stale"}, changes.RemoveGithubUsernameApprovalList)
+}
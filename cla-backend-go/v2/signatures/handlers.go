@@ -178,6 +178,115 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 		return signatures.NewUpdateApprovalListOK().WithXRequestID(reqID).WithPayload(&v2Sig)
 	})
 
+	// Download the approval list as a CSV document
+	api.SignaturesDownloadApprovalListAsCSVHandler = signatures.DownloadApprovalListAsCSVHandlerFunc(func(params signatures.DownloadApprovalListAsCSVParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "SignaturesDownloadApprovalListAsCSVHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"projectSFID":    params.ProjectSFID,
+			"companySFID":    params.CompanySFID,
+		}
+
+		if !utils.IsUserAuthorizedForProjectOrganizationTree(authUser, params.ProjectSFID, params.CompanySFID, utils.DISALLOW_ADMIN_SCOPE) {
+			msg := fmt.Sprintf("user %s does not have access to download Project Company Approval List with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanySFID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewDownloadApprovalListAsCSVForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		log.WithFields(f).Debug("loading company by company SFID")
+		companyModel, compErr := companyService.GetCompanyByExternalID(ctx, params.CompanySFID)
+		if compErr != nil || companyModel == nil {
+			msg := fmt.Sprintf("unable to locate company by external company ID: %s", params.CompanySFID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewDownloadApprovalListAsCSVNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+		}
+
+		result, err := v2service.GetApprovalListCsv(ctx, params.ClaGroupID, companyModel.CompanyID)
+		if err != nil {
+			msg := fmt.Sprintf("problem getting approval list CSV for CLA Group: %s with company: %s", params.ClaGroupID, params.CompanySFID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			if err == ErrSignatureNotFound {
+				return signatures.NewDownloadApprovalListAsCSVNotFound().WithXRequestID(reqID).WithPayload(
+					utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			return signatures.NewDownloadApprovalListAsCSVBadRequest().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		log.WithFields(f).Debug("returning CSV response...")
+		return middleware.ResponderFunc(func(rw http.ResponseWriter, pr runtime.Producer) {
+			rw.Header().Set("Content-Type", "text/csv")
+			rw.Header().Set(utils.XREQUESTID, reqID)
+			rw.WriteHeader(http.StatusOK)
+			_, writeErr := rw.Write(result)
+			if writeErr != nil {
+				log.WithFields(f).WithError(writeErr).Warn("error writing csv file")
+			}
+		})
+	})
+
+	// Import the approval list from a CSV document
+	api.SignaturesImportApprovalListCSVHandler = signatures.ImportApprovalListCSVHandlerFunc(func(params signatures.ImportApprovalListCSVParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "SignaturesImportApprovalListCSVHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"claGroupID":     params.ClaGroupID,
+			"projectSFID":    params.ProjectSFID,
+			"companySFID":    params.CompanySFID,
+			"dryRun":         params.Body.DryRun,
+		}
+
+		// Must be in the Project|Organization Scope to see this - signature ACL is double-checked in the service level when the signature is loaded
+		if !utils.IsUserAuthorizedForProjectOrganizationTree(authUser, params.ProjectSFID, params.CompanySFID, utils.DISALLOW_ADMIN_SCOPE) {
+			msg := fmt.Sprintf("user %s does not have access to import Project Company Approval List with Project|Organization scope of %s | %s",
+				authUser.UserName, params.ProjectSFID, params.CompanySFID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewImportApprovalListCSVForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbidden(reqID, msg))
+		}
+
+		log.WithFields(f).Debug("loading company by company SFID")
+		companyModel, compErr := companyService.GetCompanyByExternalID(ctx, params.CompanySFID)
+		if compErr != nil || companyModel == nil {
+			msg := fmt.Sprintf("unable to locate company by external company ID: %s", params.CompanySFID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewImportApprovalListCSVNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+		}
+
+		claGroupModel, projErr := projectService.GetCLAGroupByID(ctx, params.ClaGroupID)
+		if projErr != nil || claGroupModel == nil {
+			msg := fmt.Sprintf("unable to locate project by CLA Group ID: %s", params.ClaGroupID)
+			log.WithFields(f).Warn(msg)
+			return signatures.NewImportApprovalListCSVNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFound(reqID, msg))
+		}
+
+		result, err := v2service.ImportApprovalListCsv(ctx, authUser, claGroupModel, companyModel, params.Body)
+		if err != nil {
+			msg := fmt.Sprintf("unable to import approval list CSV using CLA Group ID: %s", params.ClaGroupID)
+			log.WithFields(f).WithError(err).Warn(msg)
+			if forbiddenErr, ok := err.(*signatureService.ForbiddenError); ok {
+				return signatures.NewImportApprovalListCSVForbidden().WithXRequestID(reqID).WithPayload(utils.ErrorResponseForbiddenWithError(reqID, msg, forbiddenErr))
+			}
+			if err == ErrSignatureNotFound {
+				return signatures.NewImportApprovalListCSVNotFound().WithXRequestID(reqID).WithPayload(utils.ErrorResponseNotFoundWithError(reqID, msg, err))
+			}
+			if err == ErrApprovalListCSVInvalidRows {
+				msg = fmt.Sprintf("%s - %d invalid rows, use a dry run to list them", msg, len(result.InvalidRows))
+			}
+			return signatures.NewImportApprovalListCSVBadRequest().WithXRequestID(reqID).WithPayload(utils.ErrorResponseBadRequestWithError(reqID, msg, err))
+		}
+
+		log.WithFields(f).Debug("returning approval list CSV import result to caller...")
+		return signatures.NewImportApprovalListCSVOK().WithXRequestID(reqID).WithPayload(result)
+	})
+
 	// Retrieve GitHub Approval Entries
 	api.SignaturesGetGitHubOrgWhitelistHandler = signatures.GetGitHubOrgWhitelistHandlerFunc(func(params signatures.GetGitHubOrgWhitelistParams, authUser *auth.User) middleware.Responder {
 		reqID := utils.GetRequestID(params.XREQUESTID)
@@ -938,6 +1047,51 @@ func Configure(api *operations.EasyclaAPI, projectService project.Service, proje
 		return signatures.NewGetSignatureSignedDocumentOK().WithXRequestID(reqID).WithPayload(doc)
 	})
 
+	api.SignaturesVerifySignatureSignedDocumentHandler = signatures.VerifySignatureSignedDocumentHandlerFunc(func(params signatures.VerifySignatureSignedDocumentParams, authUser *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(authUser, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "SignaturesVerifySignatureSignedDocumentHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"signatureID":    params.SignatureID,
+		}
+
+		log.WithFields(f).Debug("loading signature by ID...")
+		signatureModel, err := v1SignatureService.GetSignature(ctx, params.SignatureID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading signature")
+			return signatures.NewVerifySignatureSignedDocumentBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		if signatureModel == nil {
+			log.WithFields(f).Warn("problem loading signature - signature not found")
+			return signatures.NewVerifySignatureSignedDocumentNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, errors.New("signature not found")))
+		}
+
+		haveAccess, err := isUserHaveAccessOfSignedSignaturePDF(ctx, authUser, signatureModel, companyService, projectClaGroupsRepo, projectRepo)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem determining signature access")
+			return signatures.NewVerifySignatureSignedDocumentBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		if !haveAccess {
+			return signatures.NewVerifySignatureSignedDocumentForbidden().WithXRequestID(reqID).WithPayload(
+				utils.ErrorResponseForbidden(reqID, fmt.Sprintf("user %s does not have access to the specified signature", authUser.UserName)))
+		}
+
+		result, err := v2service.VerifySignedDocument(ctx, signatureModel.SignatureID.String())
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem verifying signed document")
+			if strings.Contains(err.Error(), "bad request") {
+				return signatures.NewVerifySignatureSignedDocumentBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return signatures.NewVerifySignatureSignedDocumentInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		log.WithFields(f).Debugf("returning signed document verification status: %s to caller...", result.Status)
+		return signatures.NewVerifySignatureSignedDocumentOK().WithXRequestID(reqID).WithPayload(result)
+	})
+
 	api.SignaturesDownloadProjectSignatureICLAsHandler = signatures.DownloadProjectSignatureICLAsHandlerFunc(func(params signatures.DownloadProjectSignatureICLAsParams, authUser *auth.User) middleware.Responder {
 		reqID := utils.GetRequestID(params.XREQUESTID)
 		ctx := context.WithValue(context.Background(), utils.XREQUESTID, reqID) // nolint
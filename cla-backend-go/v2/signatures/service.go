@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/LF-Engineering/lfx-kit/auth"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -62,6 +63,10 @@ type Service interface {
 	GetSignedDocument(ctx context.Context, signatureID string) (*models.SignedDocument, error)
 	GetSignedIclaZipPdf(claGroupID string) (*models.URLObject, error)
 	GetSignedCclaZipPdf(claGroupID string) (*models.URLObject, error)
+	VerifySignedDocument(ctx context.Context, signatureID string) (*models.SignedDocumentVerification, error)
+	VerifyCLAGroupSignedDocuments(ctx context.Context, claGroupID string) ([]*models.SignedDocumentVerification, error)
+	GetApprovalListCsv(ctx context.Context, claGroupID, companyID string) ([]byte, error)
+	ImportApprovalListCsv(ctx context.Context, authUser *auth.User, claGroupModel *v1Models.ClaGroup, companyModel *v1Models.Company, input *models.ApprovalListCsvImport) (*models.ApprovalListCsvImportResult, error)
 }
 
 // NewService creates instance of v2 signature service
@@ -7,8 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/restapi/operations/signatures"
+	signatureService "github.com/communitybridge/easycla/cla-backend-go/signatures"
 	"github.com/communitybridge/easycla/cla-backend-go/utils"
 	"github.com/go-openapi/runtime/middleware"
 )
@@ -31,6 +33,7 @@ func hasApprovalListUpdates(params signatures.UpdateApprovalListParams) bool {
 	if len(params.Body.AddEmailApprovalList) > 0 || len(params.Body.RemoveEmailApprovalList) > 0 ||
 		len(params.Body.AddDomainApprovalList) > 0 || len(params.Body.RemoveDomainApprovalList) > 0 ||
 		len(params.Body.AddGithubUsernameApprovalList) > 0 || len(params.Body.RemoveGithubUsernameApprovalList) > 0 ||
+		len(params.Body.AddGitlabUsernameApprovalList) > 0 || len(params.Body.RemoveGitlabUsernameApprovalList) > 0 ||
 		len(params.Body.AddGithubOrgApprovalList) > 0 || len(params.Body.RemoveGithubOrgApprovalList) > 0 {
 		return true
 	}
@@ -56,16 +59,20 @@ func entriesAreValid(params signatures.UpdateApprovalListParams) (string, bool)
 		}
 	}
 
-	// Ensure the domains are valid
+	// Ensure the domains, wildcard domains and regex rules are valid
 	for _, domain := range params.Body.AddDomainApprovalList {
-		msg, valid := utils.ValidDomain(domain)
+		msg, valid := utils.ValidDomainApprovalListEntry(domain)
 		if !valid {
 			isValid = false
 			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list domain %s - %s", domain, msg))
 		}
 	}
 	for _, domain := range params.Body.RemoveDomainApprovalList {
-		msg, valid := utils.ValidDomain(domain)
+		// a regex rule stored before the syntax was restricted no longer validates, it can still be removed
+		if strings.HasPrefix(strings.TrimSpace(domain), utils.DomainApprovalListRegexPrefix) {
+			continue
+		}
+		msg, valid := utils.ValidDomainApprovalListEntry(domain)
 		if !valid {
 			isValid = false
 			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list domain %s - %s", domain, msg))
@@ -88,6 +95,22 @@ func entriesAreValid(params signatures.UpdateApprovalListParams) (string, bool)
 		}
 	}
 
+	// Ensure the gitlab usernames are valid
+	for _, gitlabUsername := range params.Body.AddGitlabUsernameApprovalList {
+		msg, valid := utils.ValidGitLabUsername(gitlabUsername)
+		if !valid {
+			isValid = false
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid add approval list GitLab Username %s - %s", gitlabUsername, msg))
+		}
+	}
+	for _, gitlabUsername := range params.Body.RemoveGitlabUsernameApprovalList {
+		msg, valid := utils.ValidGitLabUsername(gitlabUsername)
+		if !valid {
+			isValid = false
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid remove approval list GitLab Username %s - %s", gitlabUsername, msg))
+		}
+	}
+
 	// Ensure the github Organization values are valid
 	for _, githubOrg := range params.Body.AddGithubOrgApprovalList {
 		msg, valid := utils.ValidGitHubOrg(githubOrg)
@@ -104,5 +127,38 @@ func entriesAreValid(params signatures.UpdateApprovalListParams) (string, bool)
 		}
 	}
 
+	// Ensure the expiry of the added entries is a future RFC3339 date/time
+	if params.Body.ExpiresOn != "" {
+		expiresOn, err := time.Parse(time.RFC3339, params.Body.ExpiresOn)
+		if err != nil {
+			isValid = false
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid approval list expiry %s - expecting an RFC3339 date/time", params.Body.ExpiresOn))
+		} else if !expiresOn.After(time.Now()) {
+			isValid = false
+			listOfErrors = append(listOfErrors, fmt.Sprintf("invalid approval list expiry %s - expecting a future date/time", params.Body.ExpiresOn))
+		}
+	}
+
 	return strings.Join(listOfErrors, ", "), isValid
 }
+
+// validateApprovalListEntry returns true if the value is valid for the approval list type, returns false and a message otherwise
+func validateApprovalListEntry(listType, value string) (string, bool) {
+	switch listType {
+	case signatureService.ApprovalListTypeEmail:
+		if !utils.ValidEmail(value) {
+			return "invalid email", false
+		}
+		return "", true
+	case signatureService.ApprovalListTypeDomain:
+		return utils.ValidDomainApprovalListEntry(value)
+	case signatureService.ApprovalListTypeGithubUsername:
+		return utils.ValidGitHubUsername(value)
+	case signatureService.ApprovalListTypeGitlabUsername:
+		return utils.ValidGitLabUsername(value)
+	case signatureService.ApprovalListTypeGithubOrg:
+		return utils.ValidGitHubOrg(value)
+	default:
+		return fmt.Sprintf("unknown approval list type %s - expecting one of %s", listType, strings.Join(approvalListCSVTypes, ", ")), false
+	}
+}
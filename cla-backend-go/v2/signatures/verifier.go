@@ -0,0 +1,154 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/sirupsen/logrus"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/gen/v2/models"
+	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// signed document verification status values
+const (
+	SignedDocumentVerified        = "verified"
+	SignedDocumentMismatch        = "mismatch"
+	SignedDocumentMissing         = "missing_document"
+	SignedDocumentDigestNotStored = "no_digest"
+)
+
+// verifyPageSize is the number of signatures loaded per query when walking a CLA Group
+const verifyPageSize = int64(100)
+
+// errors
+var (
+	ErrSignatureNotFound    = errors.New("signature not found")
+	ErrNoSignedDocument     = errors.New("bad request. employee signature does not have signed document")
+	ErrUnknownSignatureType = errors.New("bad request. unknown signature type")
+)
+
+// VerifySignedDocument downloads the signed document of the signature and compares its digest with the digest
+// recorded on the signature when the document was stored
+func (s service) VerifySignedDocument(ctx context.Context, signatureID string) (*models.SignedDocumentVerification, error) {
+	sig, err := s.v1SignatureService.GetSignature(ctx, signatureID)
+	if err != nil {
+		return nil, err
+	}
+	if sig == nil {
+		return nil, ErrSignatureNotFound
+	}
+	return verifySignatureDocument(ctx, sig)
+}
+
+// VerifyCLAGroupSignedDocuments verifies the signed documents of all the ICLA and CCLA signatures of the CLA Group
+func (s service) VerifyCLAGroupSignedDocuments(ctx context.Context, claGroupID string) ([]*models.SignedDocumentVerification, error) {
+	f := logrus.Fields{
+		"functionName":   "VerifyCLAGroupSignedDocuments",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"claGroupID":     claGroupID,
+	}
+
+	var results []*models.SignedDocumentVerification
+	for _, claType := range []string{utils.ClaTypeICLA, utils.ClaTypeCCLA} {
+		var nextKey *string
+		for {
+			page, err := s.v1SignatureService.GetProjectSignatures(ctx, v1Signatures.GetProjectSignaturesParams{
+				ClaType:   aws.String(claType),
+				NextKey:   nextKey,
+				PageSize:  aws.Int64(verifyPageSize),
+				ProjectID: claGroupID,
+			})
+			if err != nil {
+				log.WithFields(f).WithError(err).Warnf("problem loading %s signatures", claType)
+				return nil, err
+			}
+
+			for _, sig := range page.Signatures {
+				result, verifyErr := verifySignatureDocument(ctx, sig)
+				if verifyErr == ErrNoSignedDocument {
+					continue
+				}
+				if verifyErr != nil {
+					log.WithFields(f).WithError(verifyErr).Warnf("problem verifying signed document for signature: %s", sig.SignatureID)
+					return nil, verifyErr
+				}
+				results = append(results, result)
+			}
+
+			if page.LastKeyScanned == "" {
+				break
+			}
+			nextKey = aws.String(page.LastKeyScanned)
+		}
+	}
+
+	log.WithFields(f).Debugf("verified %d signed documents", len(results))
+	return results, nil
+}
+
+// verifySignatureDocument compares the digest of the stored signed document with the digest recorded on the signature
+func verifySignatureDocument(ctx context.Context, sig *v1Models.Signature) (*models.SignedDocumentVerification, error) {
+	f := logrus.Fields{
+		"functionName":   "verifySignatureDocument",
+		utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+		"signatureID":    sig.SignatureID,
+		"claGroupID":     sig.ProjectID,
+	}
+
+	var claType string
+	switch sig.SignatureType {
+	case ClaSignatureType:
+		if sig.CompanyName != "" {
+			return nil, ErrNoSignedDocument
+		}
+		claType = utils.ClaTypeICLA
+	case CclaSignatureType:
+		claType = utils.ClaTypeCCLA
+	default:
+		return nil, ErrUnknownSignatureType
+	}
+
+	_, now := utils.CurrentTime()
+	result := &models.SignedDocumentVerification{
+		SignatureID:    sig.SignatureID.String(),
+		ClaGroupID:     sig.ProjectID,
+		ClaType:        claType,
+		DocumentPath:   utils.SignedCLAFilename(sig.ProjectID, claType, sig.SignatureReferenceID.String(), sig.SignatureID.String()),
+		ExpectedSHA256: sig.SignedDocumentDigest,
+		VerifiedOn:     now,
+	}
+
+	content, err := utils.DownloadFromS3(result.DocumentPath)
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == s3.ErrCodeNoSuchKey {
+			log.WithFields(f).Warnf("signed document is missing: %s", result.DocumentPath)
+			result.Status = SignedDocumentMissing
+			return result, nil
+		}
+		return nil, err
+	}
+
+	result.ActualSHA256 = utils.SignedDocumentDigest(content)
+	switch {
+	case result.ExpectedSHA256 == "":
+		result.Status = SignedDocumentDigestNotStored
+	case result.ExpectedSHA256 == result.ActualSHA256:
+		result.Status = SignedDocumentVerified
+	default:
+		log.WithFields(f).Warnf("signed document digest mismatch, expected: %s, actual: %s",
+			result.ExpectedSHA256, result.ActualSHA256)
+		result.Status = SignedDocumentMismatch
+	}
+
+	return result, nil
+}
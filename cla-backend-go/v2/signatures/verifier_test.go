@@ -0,0 +1,159 @@
+// Copyright The Linux Foundation and each contributor to CommunityBridge.
+// SPDX-License-Identifier: MIT
+
+package signatures
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/go-openapi/strfmt"
+	"github.com/stretchr/testify/assert"
+
+	v1Models "github.com/communitybridge/easycla/cla-backend-go/gen/models"
+	v1Signatures "github.com/communitybridge/easycla/cla-backend-go/gen/restapi/operations/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/signatures"
+	"github.com/communitybridge/easycla/cla-backend-go/utils"
+)
+
+// fakeS3Storage keeps the signed documents by file name
+type fakeS3Storage struct {
+	utils.S3Storage
+	documents map[string][]byte
+}
+
+func (s *fakeS3Storage) Download(filename string) ([]byte, error) {
+	content, ok := s.documents[filename]
+	if !ok {
+		return nil, awserr.New(s3.ErrCodeNoSuchKey, "The specified key does not exist.", nil)
+	}
+	return content, nil
+}
+
+// useS3Storage sets the default S3 storage to a fake holding the documents
+func useS3Storage(t *testing.T, documents map[string][]byte) {
+	previous := utils.GetS3Storage()
+	utils.SetS3StorageClient(&fakeS3Storage{documents: documents})
+	t.Cleanup(func() { utils.SetS3StorageClient(previous) })
+}
+
+const (
+	testClaGroupID   = "cla-group-1"
+	testSignatureID  = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	testReferenceID  = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	testOtherContent = "tampered document"
+)
+
+var testDocument = []byte("signed document")
+
+func testSignature(signatureType, companyName, digest string) *v1Models.Signature {
+	return &v1Models.Signature{
+		SignatureID:          strfmt.UUID4(testSignatureID),
+		SignatureReferenceID: strfmt.UUID4(testReferenceID),
+		SignatureType:        signatureType,
+		CompanyName:          companyName,
+		ProjectID:            testClaGroupID,
+		SignedDocumentDigest: digest,
+	}
+}
+
+func TestVerifySignatureDocument(t *testing.T) {
+	iclaPath := utils.SignedCLAFilename(testClaGroupID, utils.ClaTypeICLA, testReferenceID, testSignatureID)
+	cclaPath := utils.SignedCLAFilename(testClaGroupID, utils.ClaTypeCCLA, testReferenceID, testSignatureID)
+	digest := utils.SignedDocumentDigest(testDocument)
+
+	testCases := []struct {
+		name      string
+		signature *v1Models.Signature
+		documents map[string][]byte
+		status    string
+		path      string
+		actual    string
+	}{
+		{"digest match", testSignature(ClaSignatureType, "", digest), map[string][]byte{iclaPath: testDocument}, SignedDocumentVerified, iclaPath, digest},
+		{"ccla digest match", testSignature(CclaSignatureType, "Acme", digest), map[string][]byte{cclaPath: testDocument}, SignedDocumentVerified, cclaPath, digest},
+		{"digest mismatch", testSignature(ClaSignatureType, "", digest), map[string][]byte{iclaPath: []byte(testOtherContent)}, SignedDocumentMismatch, iclaPath, utils.SignedDocumentDigest([]byte(testOtherContent))},
+		{"missing object", testSignature(ClaSignatureType, "", digest), map[string][]byte{}, SignedDocumentMissing, iclaPath, ""},
+		{"missing recorded digest", testSignature(ClaSignatureType, "", ""), map[string][]byte{iclaPath: testDocument}, SignedDocumentDigestNotStored, iclaPath, digest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			useS3Storage(t, tc.documents)
+			result, err := verifySignatureDocument(context.Background(), tc.signature)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tc.status, result.Status)
+				assert.Equal(t, tc.path, result.DocumentPath)
+				assert.Equal(t, tc.signature.SignedDocumentDigest, result.ExpectedSHA256)
+				assert.Equal(t, tc.actual, result.ActualSHA256)
+			}
+		})
+	}
+
+	useS3Storage(t, map[string][]byte{})
+	_, err := verifySignatureDocument(context.Background(), testSignature(ClaSignatureType, "Acme", digest))
+	assert.Equal(t, ErrNoSignedDocument, err)
+	_, err = verifySignatureDocument(context.Background(), testSignature("unknown", "", digest))
+	assert.Equal(t, ErrUnknownSignatureType, err)
+}
+
+// fakeSignatureService returns the signatures of a single CLA Group, one signature per page
+type fakeSignatureService struct {
+	signatures.SignatureService
+	signatures []*v1Models.Signature
+}
+
+func (s *fakeSignatureService) GetProjectSignatures(ctx context.Context, params v1Signatures.GetProjectSignaturesParams) (*v1Models.Signatures, error) {
+	var matching []*v1Models.Signature
+	for _, sig := range s.signatures {
+		if (*params.ClaType == utils.ClaTypeCCLA) == (sig.SignatureType == CclaSignatureType) {
+			matching = append(matching, sig)
+		}
+	}
+	index := 0
+	if params.NextKey != nil {
+		for i, sig := range matching {
+			if sig.SignatureID.String() == *params.NextKey {
+				index = i + 1
+			}
+		}
+	}
+	page := &v1Models.Signatures{}
+	if index < len(matching) {
+		page.Signatures = matching[index : index+1]
+		if index+1 < len(matching) {
+			page.LastKeyScanned = matching[index].SignatureID.String()
+		}
+	}
+	return page, nil
+}
+
+func TestVerifyCLAGroupSignedDocuments(t *testing.T) {
+	signature := func(signatureID, signatureType, companyName string) *v1Models.Signature {
+		sig := testSignature(signatureType, companyName, utils.SignedDocumentDigest(testDocument))
+		sig.SignatureID = strfmt.UUID4(signatureID)
+		return sig
+	}
+	icla := signature("6ba7b812-9dad-11d1-80b4-00c04fd430c8", ClaSignatureType, "")
+	employee := signature("6ba7b813-9dad-11d1-80b4-00c04fd430c8", ClaSignatureType, "Acme")
+	missing := signature("6ba7b814-9dad-11d1-80b4-00c04fd430c8", ClaSignatureType, "")
+	ccla := signature("6ba7b815-9dad-11d1-80b4-00c04fd430c8", CclaSignatureType, "Acme")
+	useS3Storage(t, map[string][]byte{
+		utils.SignedCLAFilename(testClaGroupID, utils.ClaTypeICLA, testReferenceID, icla.SignatureID.String()): testDocument,
+		utils.SignedCLAFilename(testClaGroupID, utils.ClaTypeCCLA, testReferenceID, ccla.SignatureID.String()): testDocument,
+	})
+
+	s := service{v1SignatureService: &fakeSignatureService{signatures: []*v1Models.Signature{icla, employee, missing, ccla}}}
+	results, err := s.VerifyCLAGroupSignedDocuments(context.Background(), testClaGroupID)
+	if assert.NoError(t, err) && assert.Len(t, results, 3) {
+		// the employee acknowledgements have no signed document
+		assert.Equal(t, icla.SignatureID.String(), results[0].SignatureID)
+		assert.Equal(t, SignedDocumentVerified, results[0].Status)
+		assert.Equal(t, missing.SignatureID.String(), results[1].SignatureID)
+		assert.Equal(t, SignedDocumentMissing, results[1].Status)
+		assert.Equal(t, ccla.SignatureID.String(), results[2].SignatureID)
+		assert.Equal(t, SignedDocumentVerified, results[2].Status)
+	}
+}
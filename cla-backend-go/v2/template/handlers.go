@@ -5,6 +5,8 @@ package template
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/sirupsen/logrus"
@@ -50,6 +52,266 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 		return template.NewGetTemplatesOK().WithPayload(response)
 	})
 
+	api.TemplateGetTemplateHandler = template.GetTemplateHandlerFunc(func(params template.GetTemplateParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "TemplateGetTemplateHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"templateID":     params.TemplateID,
+		}
+
+		templateModel, err := service.GetTemplate(ctx, params.TemplateID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading template")
+			if errors.Is(err, v1Template.ErrTemplateNotFound) {
+				return template.NewGetTemplateNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewGetTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		response, err := v2Template(templateModel)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template")
+			return template.NewGetTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return template.NewGetTemplateOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
+	api.TemplateGetTemplateVersionsHandler = template.GetTemplateVersionsHandlerFunc(func(params template.GetTemplateVersionsParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "TemplateGetTemplateVersionsHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"templateID":     params.TemplateID,
+		}
+
+		templates, err := service.GetTemplateVersions(ctx, params.TemplateID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading template versions")
+			if errors.Is(err, v1Template.ErrTemplateNotFound) {
+				return template.NewGetTemplateVersionsNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewGetTemplateVersionsInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		var response []models.Template
+		err = copier.Copy(&response, templates)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template versions")
+			return template.NewGetTemplateVersionsInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return template.NewGetTemplateVersionsOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
+	api.TemplateGetTemplateVersionHandler = template.GetTemplateVersionHandlerFunc(func(params template.GetTemplateVersionParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":    "TemplateGetTemplateVersionHandler",
+			utils.XREQUESTID:  ctx.Value(utils.XREQUESTID),
+			"templateID":      params.TemplateID,
+			"templateVersion": params.TemplateVersion,
+		}
+
+		templateModel, err := service.GetTemplateVersion(ctx, params.TemplateID, params.TemplateVersion)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading template version")
+			if errors.Is(err, v1Template.ErrTemplateNotFound) {
+				return template.NewGetTemplateVersionNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewGetTemplateVersionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		response, err := v2Template(templateModel)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template")
+			return template.NewGetTemplateVersionInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return template.NewGetTemplateVersionOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
+	api.TemplateGetTemplateLocalesHandler = template.GetTemplateLocalesHandlerFunc(func(params template.GetTemplateLocalesParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "TemplateGetTemplateLocalesHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"templateID":     params.TemplateID,
+		}
+
+		locales, err := service.GetTemplateLocales(ctx, params.TemplateID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem loading template locales")
+			if errors.Is(err, v1Template.ErrTemplateNotFound) {
+				return template.NewGetTemplateLocalesNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewGetTemplateLocalesInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		return template.NewGetTemplateLocalesOK().WithXRequestID(reqID).WithPayload(&models.TemplateLocales{
+			TemplateID:    params.TemplateID,
+			DefaultLocale: v1Template.DefaultLocale,
+			Locales:       locales,
+		})
+	})
+
+	api.TemplateCreateTemplateHandler = template.CreateTemplateHandlerFunc(func(params template.CreateTemplateParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "TemplateCreateTemplateHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"authUserName":   user.UserName,
+		}
+
+		if !utils.IsUserAdmin(user) {
+			return template.NewCreateTemplateForbidden().WithXRequestID(reqID).WithPayload(&models.ErrorResponse{
+				Code:       "403",
+				Message:    fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to Create Template - only Admins allowed to manage the templates.", user.UserName),
+				XRequestID: reqID,
+			})
+		}
+
+		input, err := v1TemplateInput(&params.Body)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template input")
+			return template.NewCreateTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		templateModel, err := service.CreateTemplate(ctx, input, user.UserName)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem creating template")
+			if errors.Is(err, v1Template.ErrInvalidTemplate) {
+				return template.NewCreateTemplateBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewCreateTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		eventsService.LogEvent(&events.LogEventArgs{
+			EventType:  events.CLATemplateVersionCreated,
+			LfUsername: user.UserName,
+			EventData: &events.CLATemplateVersionCreatedEventData{
+				TemplateID:      templateModel.ID,
+				TemplateName:    templateModel.Name,
+				TemplateVersion: templateModel.TemplateVersion,
+			},
+		})
+
+		response, err := v2Template(templateModel)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template")
+			return template.NewCreateTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return template.NewCreateTemplateOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
+	api.TemplateUpdateTemplateHandler = template.UpdateTemplateHandlerFunc(func(params template.UpdateTemplateParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "TemplateUpdateTemplateHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"templateID":     params.TemplateID,
+			"authUserName":   user.UserName,
+		}
+
+		if !utils.IsUserAdmin(user) {
+			return template.NewUpdateTemplateForbidden().WithXRequestID(reqID).WithPayload(&models.ErrorResponse{
+				Code:       "403",
+				Message:    fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to Update Template - only Admins allowed to manage the templates.", user.UserName),
+				XRequestID: reqID,
+			})
+		}
+
+		input, err := v1TemplateInput(&params.Body)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template input")
+			return template.NewUpdateTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		templateModel, err := service.UpdateTemplate(ctx, params.TemplateID, input, user.UserName)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem updating template")
+			switch {
+			case errors.Is(err, v1Template.ErrTemplateNotFound):
+				return template.NewUpdateTemplateNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			case errors.Is(err, v1Template.ErrInvalidTemplate), errors.Is(err, v1Template.ErrBuiltInTemplate):
+				return template.NewUpdateTemplateBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			case errors.Is(err, v1Template.ErrTemplateVersionExists):
+				return template.NewUpdateTemplateConflict().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewUpdateTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		eventsService.LogEvent(&events.LogEventArgs{
+			EventType:  events.CLATemplateVersionCreated,
+			LfUsername: user.UserName,
+			EventData: &events.CLATemplateVersionCreatedEventData{
+				TemplateID:      templateModel.ID,
+				TemplateName:    templateModel.Name,
+				TemplateVersion: templateModel.TemplateVersion,
+			},
+		})
+
+		response, err := v2Template(templateModel)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem converting template")
+			return template.NewUpdateTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+		return template.NewUpdateTemplateOK().WithXRequestID(reqID).WithPayload(response)
+	})
+
+	api.TemplateDeleteTemplateHandler = template.DeleteTemplateHandlerFunc(func(params template.DeleteTemplateParams, user *auth.User) middleware.Responder {
+		reqID := utils.GetRequestID(params.XREQUESTID)
+		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
+		utils.SetAuthUserProperties(user, params.XUSERNAME, params.XEMAIL)
+		f := logrus.Fields{
+			"functionName":   "TemplateDeleteTemplateHandler",
+			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"templateID":     params.TemplateID,
+			"authUserName":   user.UserName,
+		}
+
+		if !utils.IsUserAdmin(user) {
+			return template.NewDeleteTemplateForbidden().WithXRequestID(reqID).WithPayload(&models.ErrorResponse{
+				Code:       "403",
+				Message:    fmt.Sprintf("EasyCLA - 403 Forbidden - user %s does not have access to Delete Template - only Admins allowed to manage the templates.", user.UserName),
+				XRequestID: reqID,
+			})
+		}
+
+		templateModel, err := service.DeleteTemplate(ctx, params.TemplateID)
+		if err != nil {
+			log.WithFields(f).WithError(err).Warn("problem deleting template")
+			switch {
+			case errors.Is(err, v1Template.ErrTemplateNotFound):
+				return template.NewDeleteTemplateNotFound().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			case errors.Is(err, v1Template.ErrBuiltInTemplate):
+				return template.NewDeleteTemplateBadRequest().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+			}
+			return template.NewDeleteTemplateInternalServerError().WithXRequestID(reqID).WithPayload(errorResponse(reqID, err))
+		}
+
+		eventsService.LogEvent(&events.LogEventArgs{
+			EventType:  events.CLATemplateDeleted,
+			LfUsername: user.UserName,
+			EventData: &events.CLATemplateDeletedEventData{
+				TemplateID:   templateModel.ID,
+				TemplateName: templateModel.Name,
+			},
+		})
+
+		return template.NewDeleteTemplateNoContent().WithXRequestID(reqID)
+	})
+
 	api.TemplateCreateCLAGroupTemplateHandler = template.CreateCLAGroupTemplateHandlerFunc(func(params template.CreateCLAGroupTemplateParams, user *auth.User) middleware.Responder {
 		reqID := utils.GetRequestID(params.XREQUESTID)
 		ctx := context.WithValue(params.HTTPRequest.Context(), utils.XREQUESTID, reqID) // nolint
@@ -97,6 +359,7 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 			"functionName":   "TemplateTemplatePreviewHandler",
 			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
 			"templateFor":    params.TemplateFor,
+			"locale":         utils.StringValue(params.Locale),
 		}
 
 		var param v1Models.CreateClaGroupTemplate
@@ -105,7 +368,7 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 			log.WithFields(f).WithError(err).Warn("problem converting templates")
 			return writeResponse(http.StatusInternalServerError, runtime.JSONMime, runtime.JSONProducer(), reqID, errorResponse(reqID, err))
 		}
-		pdf, err := service.CreateTemplatePreview(ctx, &param, params.TemplateFor)
+		pdf, err := service.CreateTemplatePreview(ctx, &param, params.TemplateFor, utils.StringValue(params.Locale))
 		if err != nil {
 			log.WithFields(f).WithError(err).Warnf("Error generating PDFs from provided templates, error: %v", err)
 			return writeResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), reqID, errorResponse(reqID, err))
@@ -125,8 +388,9 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 		f := logrus.Fields{
 			"functionName":   "TemplateGetCLATemplatePreviewHandler",
 			utils.XREQUESTID: ctx.Value(utils.XREQUESTID),
+			"locale":         utils.StringValue(params.Locale),
 		}
-		pdf, err := service.GetCLATemplatePreview(params.HTTPRequest.Context(), params.ClaGroupID, params.ClaType, *params.Watermark)
+		pdf, err := service.GetCLATemplatePreview(params.HTTPRequest.Context(), params.ClaGroupID, params.ClaType, utils.StringValue(params.Locale), *params.Watermark)
 		if err != nil {
 			log.WithFields(f).WithError(err).Warnf("Error getting PDFs for provided cla group ID : %s, error: %v", params.ClaGroupID, err)
 			return writeResponse(http.StatusBadRequest, runtime.JSONMime, runtime.JSONProducer(), reqID, errorResponse(reqID, err))
@@ -142,6 +406,27 @@ func Configure(api *operations.EasyclaAPI, service v1Template.Service, eventsSer
 	})
 }
 
+// v1TemplateInput converts the template input to the v1 template model the service works with
+func v1TemplateInput(input *models.TemplateInput) (*v1Models.Template, error) {
+	template := &v1Models.Template{}
+	err := copier.Copy(template, input)
+	if err != nil {
+		return nil, err
+	}
+	template.Name = utils.StringValue(input.Name)
+	return template, nil
+}
+
+// v2Template converts the v1 template model to the v2 response model
+func v2Template(template v1Models.Template) (*models.Template, error) {
+	response := &models.Template{}
+	err := copier.Copy(response, &template)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
 type codedResponse interface {
 	Code() string
 }
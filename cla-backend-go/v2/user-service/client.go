@@ -16,6 +16,7 @@ import (
 	"github.com/sirupsen/logrus"
 
 	log "github.com/communitybridge/easycla/cla-backend-go/logging"
+	"github.com/communitybridge/easycla/cla-backend-go/telemetry"
 	"github.com/communitybridge/easycla/cla-backend-go/v2/user-service/client/staff"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -35,9 +36,10 @@ var (
 
 // Client is client for user_service
 type Client struct {
-	cl       *client.UserService
-	apiKey   string
-	apiGwURL string
+	cl         *client.UserService
+	apiKey     string
+	apiGwURL   string
+	searchHTTP *http.Client
 }
 
 var (
@@ -48,13 +50,10 @@ var (
 func InitClient(APIGwURL string, apiKey string) {
 	APIGwURL = strings.ReplaceAll(APIGwURL, "https://", "")
 	userServiceClient = &Client{
-		apiKey:   apiKey,
-		apiGwURL: APIGwURL,
-		cl: client.NewHTTPClientWithConfig(strfmt.Default, &client.TransportConfig{
-			Host:     APIGwURL,
-			BasePath: "user-service/v1",
-			Schemes:  []string{"https"},
-		}),
+		apiKey:     apiKey,
+		apiGwURL:   APIGwURL,
+		cl:         client.New(telemetry.NewClientTransport("user-service", APIGwURL, "user-service/v1", []string{"https"}), strfmt.Default),
+		searchHTTP: telemetry.NewHTTPClient("user-service", "searchUsers"),
 	}
 }
 
@@ -141,7 +140,7 @@ func (usc *Client) SearchUsers(firstName string, lastName string, email string)
 	request.Header.Set("Authorization", "Bearer "+tok)
 	request.Header.Set("Content-Type", "application/json")
 
-	response, err := http.DefaultClient.Do(request)
+	response, err := usc.searchHTTP.Do(request)
 	if err != nil {
 		log.WithFields(f).WithError(err).Warn("problem searching user")
 		return nil, err
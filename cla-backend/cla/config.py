@@ -93,7 +93,8 @@ GITHUB_OAUTH_CALLBACK_URL = os.path.join(API_BASE_URL, 'v2/github/installation')
 #: GitHub OAuth2 Token URL.
 GITHUB_OAUTH_TOKEN_URL = 'https://github.com/login/oauth/access_token'
 #: How users get notified of CLA status in GitHub ('status', 'comment', or 'status+comment').
-GITHUB_PR_NOTIFICATION = 'status+comment'
+#: The EasyCLA status of the pull requests is published as a check run by the v4 API, so we only comment here.
+GITHUB_PR_NOTIFICATION = 'comment'
 
 # GitHub Application Service.
 GITHUB_APP_WEBHOOK_SECRET = os.getenv("GITHUB_APP_WEBHOOK_SECRET", "")
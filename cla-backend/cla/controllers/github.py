@@ -274,7 +274,8 @@ def activity(event_type, body):
     elif event_type == 'installation_repositories' or event_type == 'integration_installation_repositories':
         handle_installation_repositories_event(action, body)
 
-    # GitHub Pull Request Event
+    # GitHub Pull Request Event - the v4 golang api publishes the EasyCLA check run, we only comment on the pull request
+    # Note: the 'merge_group' events are only handled by the v4 golang api, see routes.github_app_activity
     elif event_type == 'pull_request':
         handle_pull_request_event(action, body)
 
@@ -327,17 +328,8 @@ def handle_installation_event(action: str, body: dict):
             )
             return {'status': 'Already Enrolled Organization Updated. CLA System is operational'}
 
-    elif action == 'deleted':
-        cla.log.debug(f'{func_name} - processing github installation activity for action: {action}')
-        org_name = get_org_name_from_installation_event(body)
-        if org_name is None:
-            cla.log.warning('Unable to determine organization name from the github installation event '
-                            f'with action: {action}'
-                            f'event body: {json.dumps(body)}')
-            return {'status', f'GitHub installation {action} event malformed.'}
-        repositories = Repository().get_repositories_by_organization(org_name)
-        notify_project_managers(repositories)
-        return
+    # Note: the 'deleted', 'suspend' and 'unsuspend' actions are handled by the v4 golang api, see
+    # routes.github_app_activity
     else:
         cla.log.debug(f'{func_name} - ignoring github installation activity for action: {action}')
 
@@ -19,7 +19,7 @@ from cla.utils import get_signing_service, get_signature_instance, get_email_ser
 
 CLA_MANAGER_ROLE = 'cla-manager'
 
-def request_individual_signature(project_id, user_id, return_url_type, return_url=None, request=None):
+def request_individual_signature(project_id, user_id, return_url_type, return_url=None, request=None, locale=None):
     """
     Handle POST request to send ICLA signature request to user.
     :param project_id: The project to sign for.
@@ -32,6 +32,8 @@ def request_individual_signature(project_id, user_id, return_url_type, return_ur
     :type return_url: string
     :param request: The Falcon Request object.
     :type request: object
+    :param locale: The locale of the courtesy translation shown next to the legally binding English text.
+    :type locale: string
     """
     signing_service = get_signing_service()
     if return_url_type == "Gerrit":
@@ -41,11 +43,12 @@ def request_individual_signature(project_id, user_id, return_url_type, return_ur
         github = get_repository_service("github")
         primary_user_email = github.get_primary_user_email(request)
         return signing_service.request_individual_signature(str(project_id), str(user_id), return_url,
-                                                            preferred_email=primary_user_email)
+                                                            preferred_email=primary_user_email, locale=locale)
 
 
 def request_corporate_signature(auth_user, project_id, company_id, send_as_email=False,
-                                authority_name=None, authority_email=None, return_url_type=None, return_url=None):
+                                authority_name=None, authority_email=None, return_url_type=None, return_url=None,
+                                locale=None):
     """
     Creates CCLA signature object that represents a company signing a CCLA.
 
@@ -68,10 +71,12 @@ def request_corporate_signature(auth_user, project_id, company_id, send_as_email
     :type return_url: str
     :param return_url: The URL to return the user to after signing is complete.
     :type return_url: string
+    :param locale: The locale of the courtesy translation shown next to the legally binding English text.
+    :type locale: string
     """
     return get_signing_service().request_corporate_signature(auth_user, str(project_id), str(company_id), send_as_email,
                                                              authority_name, authority_email,
-                                                             return_url_type, return_url)
+                                                             return_url_type, return_url, locale)
 
 
 def request_employee_signature(project_id, company_id, user_id, return_url_type, return_url=None):
@@ -9,6 +9,7 @@ https://developers.docusign.com/esign-rest-api/guides/post-go-live
 
 """
 
+import hashlib
 import io
 import os
 import urllib.request
@@ -125,7 +126,7 @@ class DocuSign(signing_service_interface.SigningService):
         self.s3storage.initialize(None)
 
     def request_individual_signature(self, project_id, user_id, return_url=None, callback_url=None,
-                                     preferred_email=None):
+                                     preferred_email=None, locale=None):
         request_info = 'project: {project_id}, user: {user_id} with return_url: {return_url}'.format(
             project_id=project_id, user_id=user_id, return_url=return_url)
         cla.log.debug('Individual Signature - creating new signature for: {}'.format(request_info))
@@ -186,6 +187,7 @@ class DocuSign(signing_service_interface.SigningService):
                           format(latest_signature.get_signature_id()))
 
             # Re-generate and set the signing url - this will update the signature record
+            latest_signature.set_signature_locale(locale)
             self.populate_sign_url(latest_signature, callback_url, default_values=default_cla_values,
                                    preferred_email=preferred_email)
 
@@ -247,6 +249,7 @@ class DocuSign(signing_service_interface.SigningService):
         # Set signature ACL
         cla.log.debug('Individual Signature - setting ACL using user GH id: {}'.format(user.get_user_github_id()))
         signature.set_signature_acl('github:{}'.format(user.get_user_github_id()))
+        signature.set_signature_locale(locale)
 
         # Populate sign url
         self.populate_sign_url(signature, callback_url, default_values=default_cla_values,
@@ -703,7 +706,8 @@ class DocuSign(signing_service_interface.SigningService):
 
     def handle_signing_new_corporate_signature(self, signature, project, company, user,
                                                signatory_name=None, signatory_email=None,
-                                               send_as_email=False, return_url_type=None, return_url=None):
+                                               send_as_email=False, return_url_type=None, return_url=None,
+                                               locale=None):
         cla.log.debug('Handle signing of new corporate signature - '
                       f'project: {project}, '
                       f'company: {company}, '
@@ -753,6 +757,7 @@ class DocuSign(signing_service_interface.SigningService):
 
         # Set signature ACL
         signature.set_signature_acl(user.get_lf_username())
+        signature.set_signature_locale(locale)
 
         self.populate_sign_url(signature, callback_url,
                                signatory_name, signatory_email,
@@ -773,7 +778,7 @@ class DocuSign(signing_service_interface.SigningService):
 
     def request_corporate_signature(self, auth_user, project_id, company_id, send_as_email=False,
                                     signatory_name=None, signatory_email=None, return_url_type=None,
-                                    return_url=None):
+                                    return_url=None, locale=None):
 
         cla.log.debug('Request corporate signature - '
                       f'project id: {project_id}, '
@@ -953,14 +958,16 @@ class DocuSign(signing_service_interface.SigningService):
             return self.handle_signing_new_corporate_signature(
                 signature=None, project=project, company=company, user=cla_manager_user,
                 signatory_name=signatory_name, signatory_email=signatory_email,
-                send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url)
+                send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url,
+                locale=locale)
 
         cla.log.debug(f'Previous unsigned CCLA signatures on file for project: {project_id}, company: {company_id}')
         # TODO: should I delete all but one?
         return self.handle_signing_new_corporate_signature(
             signature=signatures[0], project=project, company=company, user=cla_manager_user,
             signatory_name=signatory_name, signatory_email=signatory_email,
-            send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url)
+            send_as_email=send_as_email, return_url_type=return_url_type, return_url=return_url,
+            locale=locale)
 
     def populate_sign_url(self, signature, callback_url=None,
                           authority_or_signatory_name=None,
@@ -1149,9 +1156,21 @@ class DocuSign(signing_service_interface.SigningService):
                                        supportedLanguage='en',
                                        )
 
+        # The localized document contains the legally binding English text followed by the courtesy translation
+        document_s3_url = document.get_document_s3_url()
+        signature_locale = signature.get_signature_locale()
+        if signature_locale:
+            localized_s3_url = document.get_document_localized_s3_url(signature_locale)
+            if localized_s3_url is not None:
+                document_s3_url = localized_s3_url
+            else:
+                cla.log.warning(f'populate_sign_url - {sig_type} - document has no {signature_locale} translation - '
+                                'using the English document')
+                signature.set_signature_locale(None)
+
         content_type = document.get_document_content_type()
-        if document.get_document_s3_url() is not None:
-            pdf = self.get_document_resource(document.get_document_s3_url())
+        if document_s3_url is not None:
+            pdf = self.get_document_resource(document_s3_url)
         elif content_type.startswith('url+'):
             pdf_url = document.get_document_content()
             pdf = self.get_document_resource(pdf_url)
@@ -1267,7 +1286,7 @@ class DocuSign(signing_service_interface.SigningService):
 
             # Store document on S3
             project_id = signature.get_signature_project_id()
-            self.send_to_s3(document_data, project_id, signature_id, 'icla', user_id)
+            self.send_to_s3(document_data, project_id, signature, 'icla', user_id)
 
             # Log the event
             try:
@@ -1375,7 +1394,7 @@ class DocuSign(signing_service_interface.SigningService):
 
             # Store document on S3
             project_id = signature.get_signature_project_id()
-            self.send_to_s3(document_data, project_id, signature_id, 'icla', user_id)
+            self.send_to_s3(document_data, project_id, signature, 'icla', user_id)
             cla.log.debug('signed_individual_callback_gerrit - uploaded ICLA document to s3')
 
     def signed_corporate_callback(self, content, project_id, company_id):
@@ -1551,7 +1570,7 @@ class DocuSign(signing_service_interface.SigningService):
 
             # Store document on S3
             cla.log.debug(f'signed_corporate_callback - uploading CCLA document to s3, params: {param_str}...')
-            self.send_to_s3(document_data, project_id, signature_id, 'ccla', company_id)
+            self.send_to_s3(document_data, project_id, signature, 'ccla', company_id)
             cla.log.debug(f'signed_corporate_callback - uploaded CCLA document to s3, params: {param_str}')
             cla.log.debug(f'signed_corporate_callback - DONE!, params: {param_str}')
 
@@ -1630,14 +1649,20 @@ class DocuSign(signing_service_interface.SigningService):
         cla.log.info(f'Sending signed CLA document to {recipient} with subject: {subject}')
         cla.utils.get_email_service().send(subject, body, recipient)
 
-    def send_to_s3(self, document_data, project_id, signature_id, cla_type, identifier):
+    def send_to_s3(self, document_data, project_id, signature, cla_type, identifier):
         # cla_type could be: icla or ccla (String)
         # identifier could be: user_id or company_id
+        signature_id = signature.get_signature_id()
         filename = str.join('/',
                             ('contract-group', str(project_id), cla_type, str(identifier), str(signature_id) + '.pdf'))
         cla.log.debug(f'send_to_s3 - uploading document with filename: {filename}')
         self.s3storage.store(filename, document_data)
 
+        # Record the digest of the stored document so the copy in S3 can be verified later
+        digest = hashlib.sha256(document_data).hexdigest()
+        cla.log.debug(f'send_to_s3 - recording signed document sha256: {digest} for signature: {signature_id}')
+        signature.save_signed_document_sha256(digest)
+
     def get_document_resource(self, url):  # pylint: disable=no-self-use
         """
         Mockable method to fetch the PDF for signing.
@@ -8,7 +8,6 @@ Easily access CLA models backed by DynamoDB using pynamodb.
 import base64
 import datetime
 import os
-import re
 import time
 import uuid
 from typing import Optional, List
@@ -773,6 +772,18 @@ class DocumentTab(model_interfaces.DocumentTab):
         self.model.document_tab_anchor_y_offset = document_tab_anchor_y_offset
 
 
+class ApprovalListEntryDetailModel(MapAttribute):
+    """
+    Represents the expiry and the adding user of an approval list entry.
+    """
+
+    list_type = UnicodeAttribute()
+    value = UnicodeAttribute()
+    expires_on = UnicodeAttribute(null=True)
+    added_by = UnicodeAttribute(null=True)
+    added_on = UnicodeAttribute(null=True)
+
+
 class DocumentModel(MapAttribute):
     """
     Represents a document in the project model.
@@ -791,6 +802,10 @@ class DocumentModel(MapAttribute):
     document_legal_entity_name = UnicodeAttribute(null=True)
     document_s3_url = UnicodeAttribute(null=True)
     document_tabs = ListAttribute(of=DocumentTabModel, default=[])
+    # the version of the stored template the document was generated from, not set for the built-in templates
+    document_template_version = NumberAttribute(null=True)
+    # the S3 URLs of the localized documents by locale - the English text followed by a courtesy translation
+    document_localized_s3_urls = MapAttribute(null=True)
 
 
 class Document(model_interfaces.Document):
@@ -889,6 +904,18 @@ class Document(model_interfaces.Document):
     def get_document_s3_url(self):
         return self.model.document_s3_url
 
+    def get_document_localized_s3_url(self, locale):
+        """
+        Returns the S3 URL of the localized document for the locale, None if the document has no translation
+        for the locale - the English document is used instead.
+        """
+        if not locale or self.model.document_localized_s3_urls is None:
+            return None
+        for document_locale, s3_url in self.model.document_localized_s3_urls.as_dict().items():
+            if document_locale.lower() == locale.lower():
+                return s3_url
+        return None
+
     def get_document_tabs(self):
         tabs = []
         for tab in self.model.document_tabs:
@@ -1009,6 +1036,9 @@ class ProjectModel(BaseModel):
     foundation_sfid_project_name_index = ProjectFoundationIDIndex()
 
     project_acl = UnicodeSetAttribute(default=set())
+    # The contributor identities exempted from signing - managed by the Go backend, declared so that saving the
+    # project keeps them
+    exemptions = ListAttribute(null=True)
     # Default is v1 for all of our models - override for this model so that we can redirect to new UI when ready
     # version = UnicodeAttribute(default="v2")  # Schema version is v2 for Project Models
 
@@ -1776,20 +1806,11 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
         :rtype: bool
         """
         fn = 'dynamo_models.preprocess_pattern'
-        for pattern in patterns:
-            if pattern.startswith("*."):
-                pattern = pattern.replace("*.", ".*")
-            elif pattern.startswith("*"):
-                pattern = pattern.replace("*", ".*")
-            elif pattern.startswith("."):
-                pattern = pattern.replace(".", ".*")
-
-            preprocessed_pattern = "^.*@" + pattern + "$"
-            pat = re.compile(preprocessed_pattern)
-            for email in emails:
-                if pat.match(email) is not None:
-                    self.log_debug(f'{fn} - found user email in email approval pattern')
-                    return True
+        for email in emails:
+            pattern = cla.utils.match_domain_approval_list(email, patterns)
+            if pattern is not None:
+                self.log_debug(f'{fn} - found user email in email approval pattern: {pattern}')
+                return True
         return False
 
     # Accepts a Signature object
@@ -1812,7 +1833,7 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
             emails = [email.strip() for email in emails]
 
         # First, we check email whitelist
-        whitelist = ccla_signature.get_email_whitelist()
+        whitelist = ccla_signature.get_active_approval_list('email')
         cla.log.debug(f'{fn} - testing user emails: {emails} with '
                       f'CCLA approval emails: {whitelist}')
 
@@ -1826,11 +1847,9 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
             cla.log.debug(f'{fn} - no email whitelist match for user: {self}')
 
         # Secondly, let's check domain whitelist
-        # If a naked domain (e.g. google.com) is provided, we prefix it with '^.*@',
-        # so that sub-domains are not allowed.
-        # If a '*', '*.' or '.' prefix is provided, we replace the prefix with '.*\.',
-        # which will allow subdomains.
-        patterns = ccla_signature.get_domain_whitelist()
+        # A naked domain (e.g. google.com) does not allow sub-domains, a '*', '*.' or '.' prefix
+        # allows sub-domains and a 'regex:' prefix is matched against the whole email domain.
+        patterns = ccla_signature.get_active_approval_list('domain')
         cla.log.debug(f'{fn} - testing user email domains: {emails} with '
                       f'domain approval values: {patterns}')
 
@@ -1875,7 +1894,7 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
         if github_username is not None:
             # remove leading and trailing whitespace from github username
             github_username = github_username.strip()
-            github_whitelist = ccla_signature.get_github_whitelist()
+            github_whitelist = ccla_signature.get_active_approval_list('githubUsername')
             cla.log.debug(f'{fn} - testing user github username: {github_username} with '
                           f'CCLA github approval list: {github_whitelist}')
 
@@ -1891,7 +1910,7 @@ class User(model_interfaces.User):  # pylint: disable=too-many-public-methods
         # Check github org approval list
         if github_username is not None:
             # Load the github org approval list for this CCLA signature record
-            github_org_approval_list = ccla_signature.get_github_org_whitelist()
+            github_org_approval_list = ccla_signature.get_active_approval_list('githubOrg')
             if github_org_approval_list is not None:
                 # Fetch the list of orgs associated with this user
                 cla.log.debug(f'{fn} - determining if github user {github_username} is associated '
@@ -2229,6 +2248,8 @@ class SignatureModel(BaseModel):  # pylint: disable=too-many-instance-attributes
     email_whitelist = ListAttribute(null=True)
     github_whitelist = ListAttribute(null=True)
     github_org_whitelist = ListAttribute(null=True)
+    # expiry and adding user of the approval list entries, maintained by the go backend
+    approval_list_entry_details = ListAttribute(of=ApprovalListEntryDetailModel, null=True)
 
     # Additional attributes for ICLAs
     user_email = UnicodeAttribute(null=True)
@@ -2238,6 +2259,10 @@ class SignatureModel(BaseModel):  # pylint: disable=too-many-instance-attributes
     user_docusign_name = UnicodeAttribute(null=True)
     user_docusign_date_signed = UnicodeAttribute(null=True)
     user_docusign_raw_xml = UnicodeAttribute(null=True)
+    # Hex encoded SHA-256 digest of the signed document stored in S3
+    signed_document_sha256 = UnicodeAttribute(null=True)
+    # Locale of the courtesy translation shown to the signer next to the legally binding English text
+    signature_locale = UnicodeAttribute(null=True)
 
 
 class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-methods
@@ -2485,6 +2510,47 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def get_github_org_whitelist(self):
         return self.model.github_org_whitelist
 
+    def get_approval_list_entry_details(self):
+        return self.model.approval_list_entry_details
+
+    def get_active_approval_list(self, list_type):
+        """
+        Returns the approval list without the entries that have expired - expired entries are removed
+        by a scheduled job, so they may still be present for a short while after their expiry.
+
+        :param list_type: one of email, domain, githubUsername or githubOrg
+        :type list_type: string
+        :return: the unexpired approval list values or None if the approval list is not set
+        :rtype: [string]
+        """
+        approval_lists = {
+            'email': self.get_email_whitelist,
+            'domain': self.get_domain_whitelist,
+            'githubUsername': self.get_github_whitelist,
+            'githubOrg': self.get_github_org_whitelist,
+        }
+        if list_type not in approval_lists:
+            return None
+        approval_list = approval_lists[list_type]()
+        entry_details = self.get_approval_list_entry_details()
+        if approval_list is None or not entry_details:
+            return approval_list
+
+        now = datetime.datetime.utcnow()
+        expired = set()
+        for detail in entry_details:
+            if detail.list_type != list_type or not detail.expires_on:
+                continue
+            try:
+                expires_on = datetime.datetime.strptime(detail.expires_on, '%Y-%m-%dT%H:%M:%SZ')
+            except ValueError:
+                cla.log.warning(f'unable to parse approval list entry expiry: {detail.expires_on} '
+                                f'for signature: {self.get_signature_id()}')
+                continue
+            if expires_on <= now:
+                expired.add(detail.value.strip())
+        return [value for value in approval_list if value.strip() not in expired]
+
     def get_note(self):
         return self.model.note
 
@@ -2533,6 +2599,12 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def get_user_docusign_raw_xml(self):
         return self.model.user_docusign_raw_xml
 
+    def get_signed_document_sha256(self):
+        return self.model.signed_document_sha256
+
+    def get_signature_locale(self):
+        return self.model.signature_locale
+
     def set_signature_id(self, signature_id):
         self.model.signature_id = str(signature_id)
 
@@ -2666,6 +2738,22 @@ class Signature(model_interfaces.Signature):  # pylint: disable=too-many-public-
     def set_user_docusign_raw_xml(self, user_docusign_raw_xml):
         self.model.user_docusign_raw_xml = user_docusign_raw_xml
 
+    def set_signed_document_sha256(self, signed_document_sha256):
+        self.model.signed_document_sha256 = signed_document_sha256
+
+    def save_signed_document_sha256(self, signed_document_sha256):
+        """
+        Records the digest of the stored signed document with an update expression which only sets the digest
+        attribute, so the concurrent updates of the other signature attributes are not overwritten.
+
+        :param signed_document_sha256: The hex encoded SHA-256 digest of the signed document.
+        :type signed_document_sha256: string
+        """
+        self.model.update(actions=[SignatureModel.signed_document_sha256.set(signed_document_sha256)])
+
+    def set_signature_locale(self, signature_locale):
+        self.model.signature_locale = signature_locale
+
     def get_signatures_by_reference(
             self,  # pylint: disable=too-many-arguments
             reference_id,
@@ -943,8 +943,9 @@ def update_pull_request(installation_id, github_repository_id, pull_request, rep
 
     # Here we update the PR status by adding/updating the PR body - this is the way the EasyCLA app
     # knows if it is pass/fail.
-    # Create check run for users that haven't yet signed and/or affiliated
-    if missing:
+    # Create check run for users that haven't yet signed and/or affiliated - only when we publish the status,
+    # otherwise the EasyCLA check run of the v4 API reports it
+    if missing and (both or notification == 'status'):
         text = ""
         for authors in missing:
             # Check for valid github id
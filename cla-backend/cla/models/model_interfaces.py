@@ -937,6 +937,9 @@ class Signature(object):  # pylint: disable=too-many-public-methods
     def get_github_org_whitelist(self):
         raise NotImplementedError()
 
+    def get_active_approval_list(self, list_type):
+        raise NotImplementedError()
+
     def get_note(self):
         raise NotImplementedError()
 
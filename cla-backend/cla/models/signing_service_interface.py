@@ -23,7 +23,7 @@ class SigningService(object):
         raise NotImplementedError()
 
     def request_individual_signature(self, project_id, user_id, return_url_type, return_url, callback_url=None,
-                                     preferred_email=None):
+                                     preferred_email=None, locale=None):
         """
         Method that will request a new signature from the user.
 
@@ -43,6 +43,8 @@ class SigningService(object):
         :type callback_url: string
         :param preferred_email: preferred email to use when creating signature
         :type preferred_email: string
+        :param locale: the locale of the courtesy translation shown next to the legally binding English text
+        :type locale: string
         :return: All data necessary to notify the user of the signing URL.
             Should return a dict of:
 
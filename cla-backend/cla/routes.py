@@ -7,7 +7,7 @@ The entry point for the CLA service. Lays out all routes and controller function
 
 import hug
 import requests
-from falcon import HTTP_401, HTTP_400, HTTP_OK, HTTP_500, Response
+from falcon import HTTP_401, HTTP_400, HTTP_OK, HTTP_500, Response, get_http_status
 from hug.middleware import LogMiddleware
 
 import cla
@@ -1190,6 +1190,7 @@ def delete_project_document(
 )
 def request_individual_signature(
         request, project_id: hug.types.uuid, user_id: hug.types.uuid, return_url_type=None, return_url=None,
+        locale=None,
 ):
     """
     POST: /request-individual-signature
@@ -1197,7 +1198,8 @@ def request_individual_signature(
     DATA: {'project_id': 'some-project-id',
            'user_id': 'some-user-id',
            'return_url_type': Gerrit/Github. Optional depending on presence of return_url
-           'return_url': <optional>}
+           'return_url': <optional>,
+           'locale': <optional - the courtesy translation shown next to the English text>}
 
     Creates a new signature given project and user IDs. The user will be redirected to the
     return_url once signature is complete.
@@ -1213,7 +1215,7 @@ def request_individual_signature(
     signing service provider.
     """
     return cla.controllers.signing.request_individual_signature(project_id, user_id, return_url_type, return_url,
-                                                                request=request)
+                                                                request=request, locale=locale)
 
 
 @hug.post(
@@ -1231,6 +1233,7 @@ def request_corporate_signature(
         authority_email=None,
         return_url_type=None,
         return_url=None,
+        locale=None,
 ):
     """
     POST: /request-corporate-signature
@@ -1240,7 +1243,8 @@ def request_corporate_signature(
            'send_as_email': 'boolean',
            'authority_name': 'string',
            'authority_email': 'string',
-           'return_url': <optional>}
+           'return_url': <optional>,
+           'locale': <optional - the courtesy translation shown next to the English text>}
 
     Creates a new signature given project and company IDs. The manager will be redirected to the
     return_url once signature is complete.
@@ -1262,6 +1266,7 @@ def request_corporate_signature(
     # staff_verify(user) or company_manager_verify(user, company_id)
     return cla.controllers.signing.request_corporate_signature(
         auth_user, project_id, company_id, send_as_email, authority_name, authority_email, return_url_type, return_url,
+        locale,
     )
 
 
@@ -1570,14 +1575,20 @@ def github_app_activity(body, request, response):
     if event_type == "installation_repositories" or \
             event_type == "integration_installation_repositories" or \
             event_type == "repository" or \
+            event_type == "organization" or \
+            (event_type == "installation" and action in ("deleted", "suspend", "unsuspend")) or \
+            event_type == "pull_request" or \
+            event_type == "merge_group" or \
             (event_type == "push" and action and action == "created"):
         try:
             cla.log.debug(f"redirecting event to {event_type} v4 golang api")
             v4_easycla_github_activity(cla.config.PLATFORM_GATEWAY_URL, request)
-            response.status = HTTP_OK
-            return {"status": "OK"}
         except requests.exceptions.HTTPError as ex:
-            cla.log.error(f"v4 golang api failed with : {ex.response.status_code} : {ex.response.json()}")
+            cla.log.error(f"v4 golang api failed with : {ex.response.status_code} : {ex.response.text}")
+            if ex.response.status_code >= 500:
+                # fail the delivery so GitHub can redeliver it, the v4 golang api accepts the redelivery of a failed delivery
+                response.status = get_http_status(ex.response.status_code)
+                return {"status": "v4_easycla_github_activity failed {}".format(ex.response.status_code)}
             response.status = HTTP_OK
             return {"status": "OK"}
         except Exception as ex:
@@ -1585,6 +1596,11 @@ def github_app_activity(body, request, response):
             response.status = HTTP_500
             return {"status": "v4_easycla_github_activity failed {}".format(str(ex))}
 
+        # the v4 golang api publishes the EasyCLA check run of the pull requests, we still comment on them below
+        if event_type != "pull_request":
+            response.status = HTTP_OK
+            return {"status": "OK"}
+
     # if not any of the events above we handle it via python
     valid_request = cla.controllers.github.webhook_secret_validation(request.headers.get('X-HUB-SIGNATURE'),
                                                                      request.bounded_stream.read())
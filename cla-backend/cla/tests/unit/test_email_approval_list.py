@@ -5,7 +5,7 @@ from unittest.mock import patch, MagicMock
 
 import pytest
 
-from cla.models.dynamo_models import Signature, User, UserModel
+from cla.models.dynamo_models import Signature, User, UserModel, ApprovalListEntryDetailModel
 
 
 @pytest.fixture()
@@ -65,6 +65,28 @@ def test_pattern_with_dot_prefix(create_user):
     assert create_user.preprocess_pattern(domain_emails, patterns) == True
 
 
+def test_wildcard_pattern_with_lookalike_domain(create_user):
+    """Test that wildcard patterns do not match domains merely ending with the same characters"""
+    emails = ["harold@evil-bar.com"]
+    patterns = ["*bar.com", "*.bar.com", ".bar.com"]
+    assert create_user.preprocess_pattern(emails, patterns) == False
+    assert create_user.preprocess_pattern(["harold@bar.com.evil.org"], patterns) == False
+
+
+def test_regex_pattern(create_user):
+    """Test given user email against regex rules"""
+    patterns = [r"regex:bar\.(com|de)"]
+    assert create_user.preprocess_pattern(["harold@bar.de"], patterns) == True
+    assert create_user.preprocess_pattern(["harold@BAR.com"], patterns) == True
+    assert create_user.preprocess_pattern(["harold@evil-bar.com"], patterns) == False
+    assert create_user.preprocess_pattern(["harold@bar.com"], ["regex:bar(com"]) == False
+
+
+def test_regex_pattern_unsupported(create_user):
+    """Test regex rules matching any domain or using syntax outside of the shared subset are ignored"""
+    for pattern in ["regex:.*", r"regex:.+\..+", r"regex:[a-z]+\.com", r"regex:(a+)+\.bar\.com", r"regex:\w+\.bar\.com"]:
+        assert create_user.preprocess_pattern(["harold@gmail.com", "harold@eu.bar.com"], [pattern]) == False
+
 def test_email_approval_list_fail(create_user):
     """Test email that fails domain and email approval list checks """
     signature = Signature()
@@ -80,3 +102,18 @@ def test_gerrit_project_approval_listing(create_user):
     signature.get_email_whitelist = MagicMock(return_value={"phillip.leigh@amdocs.com"})
     create_user.get_all_user_emails = MagicMock(return_value=["phillip.leigh@amdocs.com"])
     assert create_user.is_approved(signature) == True
+
+
+def test_expired_email_approval_list_entry(create_user):
+    """Test that an expired approval list entry no longer approves the user"""
+    signature = Signature()
+    signature.get_email_whitelist = MagicMock(return_value=["intern@amdocs.com", "employee@amdocs.com"])
+    signature.get_approval_list_entry_details = MagicMock(return_value=[
+        ApprovalListEntryDetailModel(list_type="email", value="intern@amdocs.com", expires_on="2021-01-01T00:00:00Z"),
+        ApprovalListEntryDetailModel(list_type="email", value="employee@amdocs.com", expires_on="2999-01-01T00:00:00Z"),
+    ])
+    assert signature.get_active_approval_list("email") == ["employee@amdocs.com"]
+    create_user.get_all_user_emails = MagicMock(return_value=["intern@amdocs.com"])
+    assert create_user.is_approved(signature) == False
+    create_user.get_all_user_emails = MagicMock(return_value=["employee@amdocs.com"])
+    assert create_user.is_approved(signature) == True
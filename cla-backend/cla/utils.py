@@ -8,6 +8,8 @@ Utility functions for the CLA project.
 import inspect
 import json
 import os
+import re
+import string
 import urllib.parse
 from datetime import datetime
 from typing import List, Optional
@@ -1360,6 +1362,148 @@ def update_github_username(github_user: dict, user: User):
             user.set_user_github_username(github_user['login'])
 
 
+DOMAIN_APPROVAL_LIST_REGEX_MAX_LENGTH = 255
+DOMAIN_APPROVAL_LIST_REGEX_MAX_REPEATS = 3
+# unrelated domains a regex rule must not match - a rule matching them would approve nearly every email address
+DOMAIN_APPROVAL_LIST_REGEX_PROBES = ('gmail.com', 'outlook.com', 'yahoo.co.jp', 'qq.com', 'mail.ru', 'gmx.de',
+                                     'proton.me', 'icloud.com', 'a.b.c.d.e.f.xyz', 'x-1.io')
+DOMAIN_CHARS = frozenset(string.ascii_letters + string.digits + '-')
+
+
+def _valid_domain_char_class(char_class: str) -> bool:
+    """
+    Returns True if the character class content only holds domain characters and letter or digit
+    ranges, a '-' which isn't part of a range has to be the last character.
+    """
+    if not char_class:
+        return False
+    i = 0
+    while i < len(char_class):
+        c = char_class[i]
+        if c == '-':
+            if i != len(char_class) - 1:
+                return False
+            i += 1
+            continue
+        if c not in DOMAIN_CHARS:
+            return False
+        if i + 2 < len(char_class) and char_class[i + 1] == '-':
+            start, end = c, char_class[i + 2]
+            same_kind = any(start in kind and end in kind
+                            for kind in (string.ascii_lowercase, string.ascii_uppercase, string.digits))
+            if not same_kind or start > end:
+                return False
+            i += 2
+        i += 1
+    return True
+
+
+def valid_domain_approval_list_regex(expr: str) -> bool:
+    """
+    Checks the regex rule only uses the syntax the go backend and re.fullmatch agree on - this mirrors
+    checkDomainApprovalListRegex in the go backend: the domain characters, escaped dots, character
+    classes of domain characters and ranges, groups of alternatives which don't nest and the ?, + and
+    * quantifiers on a character or a character class. A quantifier never applies to a group, which
+    rules out the nested repetitions re backtracks on, and the rule must not match unrelated domains.
+
+    :param expr: the regular expression, without the 'regex:' prefix
+    :return: True if the rule is valid
+    """
+    if not expr or len(expr) > DOMAIN_APPROVAL_LIST_REGEX_MAX_LENGTH:
+        return False
+    depth, repeats, quantifiable = 0, 0, False
+    i = 0
+    while i < len(expr):
+        c = expr[i]
+        if c in DOMAIN_CHARS:
+            quantifiable = True
+        elif c == '\\':
+            if i + 1 == len(expr) or expr[i + 1] != '.':
+                return False
+            i += 1
+            quantifiable = True
+        elif c == '[':
+            end = expr.find(']', i)
+            if end < 0 or not _valid_domain_char_class(expr[i + 1:end]):
+                return False
+            i = end
+            quantifiable = True
+        elif c == '(':
+            if depth > 0:
+                return False
+            depth += 1
+            quantifiable = False
+        elif c == ')':
+            if depth == 0:
+                return False
+            depth -= 1
+            quantifiable = False
+        elif c == '|':
+            quantifiable = False
+        elif c in '?+*':
+            if not quantifiable:
+                return False
+            if c != '?':
+                repeats += 1
+            quantifiable = False
+        else:
+            return False
+        i += 1
+    if depth != 0 or repeats > DOMAIN_APPROVAL_LIST_REGEX_MAX_REPEATS:
+        return False
+    return not any(re.fullmatch(expr, probe, re.IGNORECASE) for probe in DOMAIN_APPROVAL_LIST_REGEX_PROBES)
+
+
+def match_domain_approval_list(email: str, patterns: List[str]) -> Optional[str]:
+    """
+    Matches the domain of the email against the domain approval list entries - this mirrors
+    DomainApprovalListMatch in the go backend, so both backends agree on who is covered.
+
+    A naked domain (e.g. example.com) only matches the domain itself. A '*.', '*' or '.' prefix
+    (e.g. *.example.com) matches the domain and all of its sub-domains, but never a domain that
+    merely ends with the same characters (e.g. evil-example.com). An entry prefixed with 'regex:'
+    is a regular expression matched, case insensitive, against the whole domain - rules using syntax
+    outside of valid_domain_approval_list_regex are ignored.
+
+    :param email: the email address to check
+    :param patterns: the domain approval list entries
+    :return: the first matching entry or None if none of the entries match
+    """
+    email = email.strip().lower()
+    if '@' not in email:
+        return None
+    email_domain = email.rsplit('@', 1)[1]
+    if not email_domain:
+        return None
+
+    for pattern in patterns:
+        entry = pattern.strip()
+        if entry.startswith('regex:'):
+            expr = entry[len('regex:'):]
+            try:
+                if not valid_domain_approval_list_regex(expr):
+                    cla.log.warning(f'ignoring unsupported domain approval list rule: {entry}')
+                    continue
+                if re.fullmatch(expr, email_domain, re.IGNORECASE) is not None:
+                    return pattern
+            except re.error as e:
+                cla.log.warning(f'unable to compile domain approval list rule: {entry} - error: {e}')
+            continue
+
+        entry = entry.lower()
+        wildcard = False
+        for prefix in ('*.', '*', '.'):
+            if entry.startswith(prefix):
+                entry = entry[len(prefix):]
+                wildcard = True
+                break
+        if not entry:
+            continue
+        if email_domain == entry or (wildcard and email_domain.endswith('.' + entry)):
+            return pattern
+    return None
+
+
 def is_approved(ccla_signature: Signature, email=None, github_username=None, github_id=None):
     """
     Given either email, github username or github id a check is made against ccla signature to
@@ -1375,7 +1519,7 @@ def is_approved(ccla_signature: Signature, email=None, github_username=None, git
 
     if email:
         # Checking email whitelist
-        whitelist = ccla_signature.get_email_whitelist()
+        whitelist = ccla_signature.get_active_approval_list('email')
         cla.log.debug(f'{fn} - testing email: {email} with CCLA approval list emails: {whitelist}')
         if whitelist is not None:
             if email.lower() in (s.lower() for s in whitelist):
@@ -1383,7 +1527,7 @@ def is_approved(ccla_signature: Signature, email=None, github_username=None, git
                 return True
 
         # Checking domain whitelist
-        patterns = ccla_signature.get_domain_whitelist()
+        patterns = ccla_signature.get_active_approval_list('domain')
         cla.log.debug(f"{fn} - testing user email domain: {email} with "
                       f"domain approval list values in database: {patterns}")
         if patterns is not None:
@@ -1401,7 +1545,7 @@ def is_approved(ccla_signature: Signature, email=None, github_username=None, git
     if github_username is not None:
         # remove leading and trailing whitespace from github username
         github_username = github_username.strip()
-        github_approval_list = ccla_signature.get_github_whitelist()
+        github_approval_list = ccla_signature.get_active_approval_list('githubUsername')
         cla.log.debug(f"{fn} - testing user github username: {github_username} with "
                       f"CCLA github approval list: {github_approval_list}")
 
@@ -1418,7 +1562,7 @@ def is_approved(ccla_signature: Signature, email=None, github_username=None, git
         github_orgs = cla.utils.lookup_github_organizations(github_username)
         if "error" not in github_orgs:
             # Fetch the list of orgs this user is part of
-            github_org_approval_list = ccla_signature.get_github_org_whitelist()
+            github_org_approval_list = ccla_signature.get_active_approval_list('githubOrg')
             cla.log.debug(f'{fn} - testing user github orgs: {github_orgs} with '
                           f'CCLA github org approval list values: {github_org_approval_list}')
 